@@ -0,0 +1,145 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/dheerajgopi/todo-api/apikey"
+	"github.com/dheerajgopi/todo-api/common"
+	todoErr "github.com/dheerajgopi/todo-api/common/error"
+	"github.com/dheerajgopi/todo-api/common/middlewares"
+	"github.com/dheerajgopi/todo-api/models"
+	"github.com/gorilla/mux"
+)
+
+// APIKeyHandler represents HTTP handler for API keys
+type APIKeyHandler struct {
+	APIKeyService apikey.Service
+	App           *common.App
+}
+
+// New creates new HTTP handler for API keys.
+// Keys are managed with access tokens only, so that a leaked key can not be used to create more keys.
+func New(router *mux.Router, service apikey.Service, app *common.App) {
+	handler := &APIKeyHandler{
+		APIKeyService: service,
+		App:           app,
+	}
+
+	jwtMiddleware := middlewares.JwtValidator(app)
+
+	router.HandleFunc("/users/me/api-keys", app.CreateHandler(jwtMiddleware(handler.Create))).Methods("POST")
+	router.HandleFunc("/users/me/api-keys", app.CreateHandler(jwtMiddleware(handler.List))).Methods("GET")
+	router.HandleFunc("/users/me/api-keys/{id:[0-9]+}", app.CreateHandler(jwtMiddleware(handler.Revoke))).Methods("DELETE")
+}
+
+// Create will generate a new API key for the user, which is shown only in this response
+func (handler *APIKeyHandler) Create(res http.ResponseWriter, req *http.Request, reqCtx *common.RequestContext) (int, interface{}, *todoErr.APIError) {
+	defer req.Body.Close()
+
+	decoder := json.NewDecoder(req.Body)
+	var createKeyReqBody CreateAPIKeyRequest
+	err := decoder.Decode(&createKeyReqBody)
+
+	if err != nil {
+		apiError := todoErr.NewAPIError("", &todoErr.APIErrorBody{
+			Message: "Invalid request body",
+		})
+
+		return http.StatusBadRequest, nil, apiError
+	}
+
+	validationErrors := createKeyReqBody.ValidateAndBuild()
+
+	if len(validationErrors) > 0 {
+		apiError := todoErr.NewAPIError("", validationErrors...)
+
+		return http.StatusBadRequest, nil, apiError
+	}
+
+	newKey := &models.APIKey{
+		User: &models.User{
+			ID: reqCtx.UserID,
+		},
+		Name:      createKeyReqBody.Name,
+		Scopes:    createKeyReqBody.Scopes,
+		ExpiresAt: createKeyReqBody.ExpiresAt,
+	}
+
+	key, err := handler.APIKeyService.Create(context.TODO(), newKey)
+
+	if err != nil {
+		return apiKeyServiceError(err)
+	}
+
+	responseData := &CreateAPIKeyResponse{
+		APIKey: newAPIKeyData(newKey),
+		Key:    key,
+	}
+
+	return http.StatusCreated, responseData, nil
+}
+
+// List will return the API keys of the user, without the keys themselves
+func (handler *APIKeyHandler) List(res http.ResponseWriter, req *http.Request, reqCtx *common.RequestContext) (int, interface{}, *todoErr.APIError) {
+	keys, err := handler.APIKeyService.List(context.TODO(), reqCtx.UserID)
+
+	if err != nil {
+		return apiKeyServiceError(err)
+	}
+
+	keyList := make([]*APIKeyData, 0)
+
+	for _, key := range keys {
+		keyList = append(keyList, newAPIKeyData(key))
+	}
+
+	responseData := &ListAPIKeyResponse{
+		APIKeys: keyList,
+	}
+
+	return http.StatusOK, responseData, nil
+}
+
+// Revoke will remove an API key of the user, so that it stops working
+func (handler *APIKeyHandler) Revoke(res http.ResponseWriter, req *http.Request, reqCtx *common.RequestContext) (int, interface{}, *todoErr.APIError) {
+	keyID, err := strconv.ParseInt(mux.Vars(req)["id"], 10, 64)
+
+	if err != nil {
+		apiError := todoErr.NewAPIError(err.Error(), &todoErr.APIErrorBody{
+			Message: "Invalid value",
+			Target:  "id",
+		})
+
+		return http.StatusBadRequest, nil, apiError
+	}
+
+	if err = handler.APIKeyService.Revoke(context.TODO(), keyID, reqCtx.UserID); err != nil {
+		return apiKeyServiceError(err)
+	}
+
+	return http.StatusOK, nil, nil
+}
+
+// apiKeyServiceError maps errors returned by the API key service to the API response
+func apiKeyServiceError(err error) (int, interface{}, *todoErr.APIError) {
+	switch err.(type) {
+	case *todoErr.ResourceNotFoundError:
+		resourceNotFoundErr, _ := err.(*todoErr.ResourceNotFoundError)
+
+		apiError := todoErr.NewAPIError(resourceNotFoundErr.Error(), &todoErr.APIErrorBody{
+			Message: "Not found",
+			Target:  resourceNotFoundErr.Resource,
+		})
+
+		return http.StatusNotFound, nil, apiError
+	default:
+		apiError := todoErr.NewAPIError(err.Error(), &todoErr.APIErrorBody{
+			Message: "Internal server error",
+		})
+
+		return http.StatusInternalServerError, nil, apiError
+	}
+}
@@ -0,0 +1,145 @@
+package http_test
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dheerajgopi/todo-api/apikey"
+	_apiKeyHandler "github.com/dheerajgopi/todo-api/apikey/delivery/http"
+	mock "github.com/dheerajgopi/todo-api/apikey/mock"
+	"github.com/dheerajgopi/todo-api/common"
+	_errors "github.com/dheerajgopi/todo-api/common/error"
+	"github.com/dheerajgopi/todo-api/config"
+	"github.com/dheerajgopi/todo-api/models"
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateWithInvalidData(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+	req := httptest.NewRequest("POST", "/users/me/api-keys", strings.NewReader(`{"name":" ","scopes":["tasks:read","tasks:admin"],"expiresAt":"2019-01-01T00:00:00Z"}`))
+
+	status, data, err := handler.Create(httptest.NewRecorder(), req, reqCtx)
+
+	assert.Equal(400, status)
+	assert.Nil(data)
+	assert.Equal(3, len(err.Body))
+	assert.Equal("name", err.Body[0].Target)
+	assert.Equal("scopes", err.Body[1].Target)
+	assert.Equal("expiresAt", err.Body[2].Target)
+}
+
+func TestCreate(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+	req := httptest.NewRequest("POST", "/users/me/api-keys", strings.NewReader(`{"name":" ci ","scopes":["tasks:write","tasks:read","tasks:write"]}`))
+
+	mockService.
+		EXPECT().
+		Create(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx interface{}, key *models.APIKey) (string, error) {
+			key.ID = 2
+			key.Prefix = "todo_abcdefg"
+			return "todo_abcdefgsecret", nil
+		}).
+		Times(1)
+
+	status, data, err := handler.Create(httptest.NewRecorder(), req, reqCtx)
+
+	responseData := data.(*_apiKeyHandler.CreateAPIKeyResponse)
+
+	assert.Equal(201, status)
+	assert.Nil(err)
+	assert.Equal("todo_abcdefgsecret", responseData.Key)
+	assert.Equal(int64(2), responseData.APIKey.ID)
+	assert.Equal("ci", responseData.APIKey.Name)
+	assert.Equal("todo_abcdefg", responseData.APIKey.Prefix)
+	assert.Equal([]string{"tasks:read", "tasks:write"}, responseData.APIKey.Scopes)
+	assert.Nil(responseData.APIKey.ExpiresAt)
+}
+
+func TestList(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+	req := httptest.NewRequest("GET", "/users/me/api-keys", nil)
+
+	lastUsedAt := time.Now()
+
+	mockService.
+		EXPECT().
+		List(gomock.Any(), reqCtx.UserID).
+		Return([]*models.APIKey{{ID: 2, Name: "ci", Prefix: "todo_abcdefg", KeyHash: "hash", LastUsedAt: &lastUsedAt}}, nil).
+		Times(1)
+
+	status, data, err := handler.List(httptest.NewRecorder(), req, reqCtx)
+
+	responseData := data.(*_apiKeyHandler.ListAPIKeyResponse)
+
+	assert.Equal(200, status)
+	assert.Nil(err)
+	assert.Equal(1, len(responseData.APIKeys))
+	assert.Equal("todo_abcdefg", responseData.APIKeys[0].Prefix)
+	assert.Equal(&lastUsedAt, responseData.APIKeys[0].LastUsedAt)
+}
+
+func TestRevokeWithMissingKey(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+	req := httptest.NewRequest("DELETE", "/users/me/api-keys/2", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "2"})
+
+	mockService.
+		EXPECT().
+		Revoke(gomock.Any(), int64(2), reqCtx.UserID).
+		Return(&_errors.ResourceNotFoundError{Resource: "apiKey"}).
+		Times(1)
+
+	status, data, err := handler.Revoke(httptest.NewRecorder(), req, reqCtx)
+
+	assert.Equal(404, status)
+	assert.Nil(data)
+	assert.Equal("apiKey", err.Body[0].Target)
+}
+
+func setupHandler(mockService apikey.Service) *_apiKeyHandler.APIKeyHandler {
+	app := &common.App{
+		Logger: logrus.New(),
+		Config: &config.Config{},
+	}
+
+	handler := &_apiKeyHandler.APIKeyHandler{
+		APIKeyService: mockService,
+		App:           app,
+	}
+
+	return handler
+}
+
+func setupRequestContext(app *common.App) *common.RequestContext {
+	reqCtx := &common.RequestContext{
+		RequestID: "dummyRequestID",
+		UserID:    1,
+		LogEntry: app.Logger.WithFields(
+			logrus.Fields{},
+		),
+	}
+
+	return reqCtx
+}
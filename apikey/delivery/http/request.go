@@ -0,0 +1,71 @@
+package http
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/dheerajgopi/todo-api/auth"
+	todoErr "github.com/dheerajgopi/todo-api/common/error"
+)
+
+// CreateAPIKeyRequest represents request body for POST /users/me/api-keys API.
+// Key does not expire if expiresAt is not given.
+type CreateAPIKeyRequest struct {
+	Name      string     `json:"name"`
+	Scopes    []string   `json:"scopes"`
+	ExpiresAt *time.Time `json:"expiresAt"`
+}
+
+// ValidateAndBuild validates the request body for POST /users/me/api-keys API.
+// Scopes are deduplicated and ordered like auth.Scopes.
+func (body *CreateAPIKeyRequest) ValidateAndBuild() []*todoErr.APIErrorBody {
+	validationErrors := make([]*todoErr.APIErrorBody, 0)
+
+	body.Name = strings.TrimSpace(body.Name)
+
+	if body.Name == "" {
+		validationErrors = append(validationErrors, &todoErr.APIErrorBody{
+			Message: "Non-empty value is required",
+			Target:  "name",
+		})
+	} else if utf8.RuneCountInString(body.Name) > 64 {
+		validationErrors = append(validationErrors, &todoErr.APIErrorBody{
+			Message: "Length should be 64 or less",
+			Target:  "name",
+		})
+	}
+
+	requested := make(map[string]bool)
+
+	for _, scope := range body.Scopes {
+		requested[scope] = true
+	}
+
+	scopes := make([]string, 0, len(requested))
+
+	for _, scope := range auth.Scopes {
+		if requested[scope] {
+			scopes = append(scopes, scope)
+			delete(requested, scope)
+		}
+	}
+
+	if len(scopes) == 0 || len(requested) > 0 {
+		validationErrors = append(validationErrors, &todoErr.APIErrorBody{
+			Message: "Values should be among " + strings.Join(auth.Scopes, ", "),
+			Target:  "scopes",
+		})
+	}
+
+	body.Scopes = scopes
+
+	if body.ExpiresAt != nil && !body.ExpiresAt.After(time.Now()) {
+		validationErrors = append(validationErrors, &todoErr.APIErrorBody{
+			Message: "Value should be in the future",
+			Target:  "expiresAt",
+		})
+	}
+
+	return validationErrors
+}
@@ -0,0 +1,43 @@
+package http
+
+import (
+	"time"
+
+	"github.com/dheerajgopi/todo-api/models"
+)
+
+// APIKeyData represents json structure for API key. Only the first characters of the key are shown.
+type APIKeyData struct {
+	ID         int64      `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expiresAt"`
+	LastUsedAt *time.Time `json:"lastUsedAt"`
+	CreatedAt  time.Time  `json:"createdAt"`
+}
+
+// CreateAPIKeyResponse represents response for POST /users/me/api-keys API.
+// Key is shown only once.
+type CreateAPIKeyResponse struct {
+	APIKey *APIKeyData `json:"apiKey"`
+	Key    string      `json:"key"`
+}
+
+// ListAPIKeyResponse represents response for GET /users/me/api-keys API
+type ListAPIKeyResponse struct {
+	APIKeys []*APIKeyData `json:"apiKeys"`
+}
+
+// newAPIKeyData builds the json structure of an API key
+func newAPIKeyData(key *models.APIKey) *APIKeyData {
+	return &APIKeyData{
+		ID:         key.ID,
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		Scopes:     key.Scopes,
+		ExpiresAt:  key.ExpiresAt,
+		LastUsedAt: key.LastUsedAt,
+		CreatedAt:  key.CreatedAt,
+	}
+}
@@ -0,0 +1,109 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/dheerajgopi/todo-api/apikey (interfaces: Repository)
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	models "github.com/dheerajgopi/todo-api/models"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+	time "time"
+)
+
+// Repository is a mock of Repository interface
+type Repository struct {
+	ctrl     *gomock.Controller
+	recorder *RepositoryMockRecorder
+}
+
+// RepositoryMockRecorder is the mock recorder for Repository
+type RepositoryMockRecorder struct {
+	mock *Repository
+}
+
+// NewRepository creates a new mock instance
+func NewRepository(ctrl *gomock.Controller) *Repository {
+	mock := &Repository{ctrl: ctrl}
+	mock.recorder = &RepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *Repository) EXPECT() *RepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method
+func (m *Repository) Create(arg0 context.Context, arg1 *models.APIKey) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create
+func (mr *RepositoryMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*Repository)(nil).Create), arg0, arg1)
+}
+
+// Delete mocks base method
+func (m *Repository) Delete(arg0 context.Context, arg1, arg2 int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", arg0, arg1, arg2)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Delete indicates an expected call of Delete
+func (mr *RepositoryMockRecorder) Delete(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*Repository)(nil).Delete), arg0, arg1, arg2)
+}
+
+// GetByKeyHash mocks base method
+func (m *Repository) GetByKeyHash(arg0 context.Context, arg1 string) (*models.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByKeyHash", arg0, arg1)
+	ret0, _ := ret[0].(*models.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByKeyHash indicates an expected call of GetByKeyHash
+func (mr *RepositoryMockRecorder) GetByKeyHash(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByKeyHash", reflect.TypeOf((*Repository)(nil).GetByKeyHash), arg0, arg1)
+}
+
+// GetByUserID mocks base method
+func (m *Repository) GetByUserID(arg0 context.Context, arg1 int64) ([]*models.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByUserID", arg0, arg1)
+	ret0, _ := ret[0].([]*models.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByUserID indicates an expected call of GetByUserID
+func (mr *RepositoryMockRecorder) GetByUserID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUserID", reflect.TypeOf((*Repository)(nil).GetByUserID), arg0, arg1)
+}
+
+// Touch mocks base method
+func (m *Repository) Touch(arg0 context.Context, arg1 int64, arg2 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Touch", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Touch indicates an expected call of Touch
+func (mr *RepositoryMockRecorder) Touch(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Touch", reflect.TypeOf((*Repository)(nil).Touch), arg0, arg1, arg2)
+}
@@ -0,0 +1,94 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/dheerajgopi/todo-api/apikey (interfaces: Service)
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	models "github.com/dheerajgopi/todo-api/models"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+)
+
+// Service is a mock of Service interface
+type Service struct {
+	ctrl     *gomock.Controller
+	recorder *ServiceMockRecorder
+}
+
+// ServiceMockRecorder is the mock recorder for Service
+type ServiceMockRecorder struct {
+	mock *Service
+}
+
+// NewService creates a new mock instance
+func NewService(ctrl *gomock.Controller) *Service {
+	mock := &Service{ctrl: ctrl}
+	mock.recorder = &ServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *Service) EXPECT() *ServiceMockRecorder {
+	return m.recorder
+}
+
+// Authenticate mocks base method
+func (m *Service) Authenticate(arg0 context.Context, arg1 string) (*models.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Authenticate", arg0, arg1)
+	ret0, _ := ret[0].(*models.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Authenticate indicates an expected call of Authenticate
+func (mr *ServiceMockRecorder) Authenticate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authenticate", reflect.TypeOf((*Service)(nil).Authenticate), arg0, arg1)
+}
+
+// Create mocks base method
+func (m *Service) Create(arg0 context.Context, arg1 *models.APIKey) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create
+func (mr *ServiceMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*Service)(nil).Create), arg0, arg1)
+}
+
+// List mocks base method
+func (m *Service) List(arg0 context.Context, arg1 int64) ([]*models.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", arg0, arg1)
+	ret0, _ := ret[0].([]*models.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List
+func (mr *ServiceMockRecorder) List(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*Service)(nil).List), arg0, arg1)
+}
+
+// Revoke mocks base method
+func (m *Service) Revoke(arg0 context.Context, arg1, arg2 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revoke", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Revoke indicates an expected call of Revoke
+func (mr *ServiceMockRecorder) Revoke(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revoke", reflect.TypeOf((*Service)(nil).Revoke), arg0, arg1, arg2)
+}
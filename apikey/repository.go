@@ -0,0 +1,17 @@
+package apikey
+
+import (
+	"context"
+	"time"
+
+	"github.com/dheerajgopi/todo-api/models"
+)
+
+// Repository represents API key's repository contract
+type Repository interface {
+	Create(ctx context.Context, key *models.APIKey) error
+	GetByUserID(ctx context.Context, userID int64) ([]*models.APIKey, error)
+	GetByKeyHash(ctx context.Context, keyHash string) (*models.APIKey, error)
+	Delete(ctx context.Context, id int64, userID int64) (bool, error)
+	Touch(ctx context.Context, id int64, usedAt time.Time) error
+}
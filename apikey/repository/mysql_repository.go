@@ -0,0 +1,187 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/dheerajgopi/todo-api/apikey"
+	"github.com/dheerajgopi/todo-api/models"
+)
+
+type mySQLAPIKeyRepo struct {
+	DB *sql.DB
+}
+
+// New will return new object which implements apikey.Repository
+func New(db *sql.DB) apikey.Repository {
+	return &mySQLAPIKeyRepo{
+		DB: db,
+	}
+}
+
+// touchInterval is the minimum time between the updates of the last used time of a key, so that each request does not write
+const touchInterval = time.Minute
+
+const selectKeyQuery = `SELECT k.id, k.user_id, u.is_active, k.name, k.prefix, k.key_hash, k.scopes, k.expires_at, k.last_used_at, k.created_at
+	FROM api_key k JOIN user u ON u.id=k.user_id `
+
+// rowScanner is implemented by both sql.Row and sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanKey reads an API key, along with the id and active flag of its user, from a row of selectKeyQuery
+func scanKey(row rowScanner) (*models.APIKey, error) {
+	key := &models.APIKey{
+		User: &models.User{},
+	}
+
+	scopes := ""
+
+	err := row.Scan(
+		&key.ID,
+		&key.User.ID,
+		&key.User.IsActive,
+		&key.Name,
+		&key.Prefix,
+		&key.KeyHash,
+		&scopes,
+		&key.ExpiresAt,
+		&key.LastUsedAt,
+		&key.CreatedAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	key.Scopes = strings.Split(scopes, ",")
+
+	return key, nil
+}
+
+// GetByUserID will return the API keys of an user, oldest first
+func (repo *mySQLAPIKeyRepo) GetByUserID(ctx context.Context, userID int64) ([]*models.APIKey, error) {
+	stmt, err := repo.DB.PrepareContext(ctx, selectKeyQuery+`WHERE k.user_id=? AND u.deleted_at IS NULL ORDER BY k.id`)
+
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := stmt.QueryContext(ctx, userID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	keys := make([]*models.APIKey, 0)
+
+	for rows.Next() {
+		key, err := scanKey(rows)
+
+		if err != nil {
+			return nil, err
+		}
+
+		keys = append(keys, key)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// GetByKeyHash will return the API key with the given key hash, unless its user is deleted or deactivated
+func (repo *mySQLAPIKeyRepo) GetByKeyHash(ctx context.Context, keyHash string) (*models.APIKey, error) {
+	stmt, err := repo.DB.PrepareContext(ctx, selectKeyQuery+`WHERE k.key_hash=? AND u.deleted_at IS NULL AND u.deactivated_at IS NULL`)
+
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := scanKey(stmt.QueryRowContext(ctx, keyHash))
+
+	switch err {
+	case nil:
+	case sql.ErrNoRows:
+		return nil, nil
+	default:
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// Create will store new API key
+func (repo *mySQLAPIKeyRepo) Create(ctx context.Context, key *models.APIKey) error {
+	query := `INSERT INTO api_key (user_id, name, prefix, key_hash, scopes, expires_at, created_at) VALUES (?, ?, ?, ?, ?, ?, ?)`
+
+	tx, err := repo.DB.BeginTx(ctx, nil)
+
+	if err != nil {
+		return err
+	}
+
+	res, err := tx.Exec(
+		query,
+		key.User.ID,
+		key.Name,
+		key.Prefix,
+		key.KeyHash,
+		strings.Join(key.Scopes, ","),
+		key.ExpiresAt,
+		key.CreatedAt,
+	)
+
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	lastID, err := res.LastInsertId()
+
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+
+	key.ID = lastID
+
+	return nil
+}
+
+// Delete will remove an API key of the user, and tells whether it existed
+func (repo *mySQLAPIKeyRepo) Delete(ctx context.Context, id int64, userID int64) (bool, error) {
+	result, err := repo.DB.ExecContext(ctx, `DELETE FROM api_key WHERE id=? AND user_id=?`, id, userID)
+
+	if err != nil {
+		return false, err
+	}
+
+	deleted, err := result.RowsAffected()
+
+	if err != nil {
+		return false, err
+	}
+
+	return deleted > 0, nil
+}
+
+// Touch will record the last use of an API key. It is skipped if the key was used within the touch interval.
+func (repo *mySQLAPIKeyRepo) Touch(ctx context.Context, id int64, usedAt time.Time) error {
+	query := `UPDATE api_key SET last_used_at=? WHERE id=? AND (last_used_at IS NULL OR last_used_at<?)`
+
+	_, err := repo.DB.ExecContext(ctx, query, usedAt, id, usedAt.Add(-touchInterval))
+
+	return err
+}
@@ -0,0 +1,139 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/dheerajgopi/todo-api/apikey/repository"
+	"github.com/dheerajgopi/todo-api/models"
+	"github.com/stretchr/testify/assert"
+)
+
+var keyColumns = []string{"id", "user_id", "is_active", "name", "prefix", "key_hash", "scopes", "expires_at", "last_used_at", "created_at"}
+
+func TestGetByUserID(t *testing.T) {
+	assert := assert.New(t)
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	now := time.Now()
+	rows := sqlmock.
+		NewRows(keyColumns).
+		AddRow(2, 1, true, "ci", "todo_abcdefg", "hash", "tasks:read,tasks:write", nil, now, now)
+
+	prep := mock.ExpectPrepare("SELECT (.+) FROM api_key k JOIN user u ON u.id=k.user_id WHERE k.user_id=\\? AND u.deleted_at IS NULL ORDER BY k.id")
+	prep.ExpectQuery().WithArgs(int64(1)).WillReturnRows(rows)
+
+	repo := repository.New(db)
+
+	keys, err := repo.GetByUserID(context.TODO(), 1)
+	assert.NoError(err)
+	assert.Equal(1, len(keys))
+	assert.Equal([]string{"tasks:read", "tasks:write"}, keys[0].Scopes)
+	assert.Nil(keys[0].ExpiresAt)
+	assert.Equal(now, *keys[0].LastUsedAt)
+}
+
+func TestGetByKeyHashWithNoRows(t *testing.T) {
+	assert := assert.New(t)
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	prep := mock.ExpectPrepare("SELECT (.+) FROM api_key k JOIN user u ON u.id=k.user_id WHERE k.key_hash=\\? AND u.deleted_at IS NULL AND u.deactivated_at IS NULL")
+	prep.ExpectQuery().WithArgs("hash").WillReturnError(sql.ErrNoRows)
+
+	repo := repository.New(db)
+
+	key, err := repo.GetByKeyHash(context.TODO(), "hash")
+	assert.NoError(err)
+	assert.Nil(key)
+}
+
+func TestCreate(t *testing.T) {
+	assert := assert.New(t)
+	now := time.Now()
+	key := &models.APIKey{
+		User:      &models.User{ID: 1},
+		Name:      "ci",
+		Prefix:    "todo_abcdefg",
+		KeyHash:   "hash",
+		Scopes:    []string{"tasks:read", "tasks:write"},
+		CreatedAt: now,
+	}
+
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	query := "INSERT INTO api_key \\(user_id, name, prefix, key_hash, scopes, expires_at, created_at\\) VALUES \\(\\?, \\?, \\?, \\?, \\?, \\?, \\?\\)"
+
+	mock.ExpectBegin()
+	mock.ExpectExec(query).
+		WithArgs(int64(1), "ci", "todo_abcdefg", "hash", "tasks:read,tasks:write", nil, now).
+		WillReturnResult(sqlmock.NewResult(2, 1))
+	mock.ExpectCommit()
+
+	repo := repository.New(db)
+
+	err = repo.Create(context.TODO(), key)
+	assert.NoError(err)
+	assert.Equal(int64(2), key.ID)
+}
+
+func TestDelete(t *testing.T) {
+	assert := assert.New(t)
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	mock.ExpectExec("DELETE FROM api_key WHERE id=\\? AND user_id=\\?").
+		WithArgs(int64(2), int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	repo := repository.New(db)
+
+	deleted, err := repo.Delete(context.TODO(), 2, 1)
+	assert.NoError(err)
+	assert.False(deleted)
+}
+
+func TestTouch(t *testing.T) {
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	now := time.Now()
+
+	mock.ExpectExec("UPDATE api_key SET last_used_at=\\? WHERE id=\\? AND \\(last_used_at IS NULL OR last_used_at<\\?\\)").
+		WithArgs(now, int64(2), now.Add(-time.Minute)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	repo := repository.New(db)
+
+	assert.NoError(t, repo.Touch(context.TODO(), 2, now))
+}
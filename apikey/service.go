@@ -0,0 +1,19 @@
+package apikey
+
+import (
+	"context"
+
+	"github.com/dheerajgopi/todo-api/models"
+)
+
+// KeyPrefix starts every API key, which tells API keys apart from access tokens in the Authorization header
+const KeyPrefix = "todo_"
+
+// Service represents API key's service contract.
+// API keys are long lived credentials of scripts, which are sent instead of access tokens and are limited to their scopes.
+type Service interface {
+	Create(ctx context.Context, key *models.APIKey) (string, error)
+	List(ctx context.Context, userID int64) ([]*models.APIKey, error)
+	Revoke(ctx context.Context, id int64, userID int64) error
+	Authenticate(ctx context.Context, key string) (*models.APIKey, error)
+}
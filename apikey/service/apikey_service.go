@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/dheerajgopi/todo-api/apikey"
+	todoErr "github.com/dheerajgopi/todo-api/common/error"
+	"github.com/dheerajgopi/todo-api/models"
+)
+
+// keySize is the number of random bytes in an API key
+const keySize = 32
+
+// prefixLength is the number of leading characters of a key which are stored as they are, to tell the keys apart
+const prefixLength = 12
+
+type apiKeyService struct {
+	apiKeyRepo apikey.Repository
+}
+
+// New returns a new object implementing apikey.Service interface
+func New(apiKeyRepo apikey.Repository) apikey.Service {
+	return &apiKeyService{
+		apiKeyRepo: apiKeyRepo,
+	}
+}
+
+// Create generates a new API key with the name, scopes and expiry of the given key, and returns the key.
+// Key is shown only once, since only its hash is kept.
+func (service *apiKeyService) Create(ctx context.Context, key *models.APIKey) (string, error) {
+	keyBytes := make([]byte, keySize)
+
+	if _, err := rand.Read(keyBytes); err != nil {
+		return "", err
+	}
+
+	plainKey := apikey.KeyPrefix + base64.RawURLEncoding.EncodeToString(keyBytes)
+
+	key.Prefix = plainKey[:prefixLength]
+	key.KeyHash = hashKey(plainKey)
+	key.CreatedAt = time.Now()
+
+	if err := service.apiKeyRepo.Create(ctx, key); err != nil {
+		return "", err
+	}
+
+	return plainKey, nil
+}
+
+// List returns the API keys of an user
+func (service *apiKeyService) List(ctx context.Context, userID int64) ([]*models.APIKey, error) {
+	return service.apiKeyRepo.GetByUserID(ctx, userID)
+}
+
+// Revoke removes an API key of an user, so that it stops working
+func (service *apiKeyService) Revoke(ctx context.Context, id int64, userID int64) error {
+	deleted, err := service.apiKeyRepo.Delete(ctx, id, userID)
+
+	if err != nil {
+		return err
+	}
+
+	if !deleted {
+		return &todoErr.ResourceNotFoundError{
+			Resource: "apiKey",
+		}
+	}
+
+	return nil
+}
+
+// Authenticate returns the API key with the given value, and records its use.
+// UnauthorizedError is returned if the key is unknown, revoked or expired.
+func (service *apiKeyService) Authenticate(ctx context.Context, key string) (*models.APIKey, error) {
+	if !strings.HasPrefix(key, apikey.KeyPrefix) {
+		return nil, &todoErr.UnauthorizedError{}
+	}
+
+	existingKey, err := service.apiKeyRepo.GetByKeyHash(ctx, hashKey(key))
+
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+
+	if existingKey == nil || (existingKey.ExpiresAt != nil && !existingKey.ExpiresAt.After(now)) {
+		return nil, &todoErr.UnauthorizedError{}
+	}
+
+	if err = service.apiKeyRepo.Touch(ctx, existingKey.ID, now); err != nil {
+		return nil, err
+	}
+
+	return existingKey, nil
+}
+
+// hashKey returns the hex encoded SHA-256 hash of a key
+func hashKey(key string) string {
+	hash := sha256.Sum256([]byte(key))
+
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,121 @@
+package service_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+	"time"
+
+	repoMock "github.com/dheerajgopi/todo-api/apikey/mock"
+	"github.com/dheerajgopi/todo-api/apikey/service"
+	todoErr "github.com/dheerajgopi/todo-api/common/error"
+	"github.com/dheerajgopi/todo-api/models"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func hash(value string) string {
+	sum := sha256.Sum256([]byte(value))
+
+	return hex.EncodeToString(sum[:])
+}
+
+func TestCreate(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	apiKeyRepoMock := repoMock.NewRepository(mockCtrl)
+	apiKeyService := service.New(apiKeyRepoMock)
+
+	newKey := &models.APIKey{
+		User:   &models.User{ID: 1},
+		Name:   "ci",
+		Scopes: []string{"tasks:read"},
+	}
+
+	apiKeyRepoMock.EXPECT().Create(ctx, newKey).Return(nil)
+
+	key, err := apiKeyService.Create(ctx, newKey)
+
+	assert.NoError(err)
+	assert.True(strings.HasPrefix(key, "todo_"))
+	assert.Equal(48, len(key))
+	assert.Equal(key[:12], newKey.Prefix)
+	assert.Equal(hash(key), newKey.KeyHash)
+	assert.False(newKey.CreatedAt.IsZero())
+}
+
+func TestRevokeWithMissingKey(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	apiKeyRepoMock := repoMock.NewRepository(mockCtrl)
+	apiKeyService := service.New(apiKeyRepoMock)
+
+	apiKeyRepoMock.EXPECT().Delete(ctx, int64(2), int64(1)).Return(true, nil)
+	apiKeyRepoMock.EXPECT().Delete(ctx, int64(3), int64(1)).Return(false, nil)
+
+	assert.NoError(apiKeyService.Revoke(ctx, 2, 1))
+	assert.Equal(&todoErr.ResourceNotFoundError{Resource: "apiKey"}, apiKeyService.Revoke(ctx, 3, 1))
+}
+
+func TestAuthenticate(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	apiKeyRepoMock := repoMock.NewRepository(mockCtrl)
+	apiKeyService := service.New(apiKeyRepoMock)
+
+	expiresAt := time.Now().Add(time.Hour)
+	existingKey := &models.APIKey{
+		ID:        2,
+		User:      &models.User{ID: 1},
+		Scopes:    []string{"tasks:read"},
+		ExpiresAt: &expiresAt,
+	}
+
+	gomock.InOrder(
+		apiKeyRepoMock.EXPECT().GetByKeyHash(ctx, hash("todo_key")).Return(existingKey, nil),
+		apiKeyRepoMock.EXPECT().Touch(ctx, int64(2), gomock.Any()).Return(nil),
+	)
+
+	key, err := apiKeyService.Authenticate(ctx, "todo_key")
+
+	assert.NoError(err)
+	assert.Equal(existingKey, key)
+}
+
+func TestAuthenticateWithInvalidKey(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	apiKeyRepoMock := repoMock.NewRepository(mockCtrl)
+	apiKeyService := service.New(apiKeyRepoMock)
+
+	expiredAt := time.Now().Add(-time.Minute)
+	expiredKey := &models.APIKey{
+		ID:        2,
+		User:      &models.User{ID: 1},
+		ExpiresAt: &expiredAt,
+	}
+
+	apiKeyRepoMock.EXPECT().GetByKeyHash(ctx, hash("todo_unknown")).Return(nil, nil)
+	apiKeyRepoMock.EXPECT().GetByKeyHash(ctx, hash("todo_expired")).Return(expiredKey, nil)
+
+	for _, key := range []string{"eyJhbGciOi", "todo_unknown", "todo_expired"} {
+		authenticatedKey, err := apiKeyService.Authenticate(ctx, key)
+
+		assert.Nil(authenticatedKey)
+		assert.Equal(&todoErr.UnauthorizedError{}, err)
+	}
+}
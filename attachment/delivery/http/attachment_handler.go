@@ -0,0 +1,259 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/dheerajgopi/todo-api/attachment"
+	"github.com/dheerajgopi/todo-api/auth"
+	"github.com/dheerajgopi/todo-api/common"
+	todoErr "github.com/dheerajgopi/todo-api/common/error"
+	"github.com/dheerajgopi/todo-api/common/middlewares"
+	"github.com/dheerajgopi/todo-api/models"
+	"github.com/gorilla/mux"
+)
+
+// multipartOverhead is the room left in the request body for multipart boundaries and headers
+const multipartOverhead = 1 << 20
+
+// maxMemory is the maximum size of an uploaded file kept in memory while parsing, rest is kept in temporary files
+const maxMemory = 1 << 20
+
+// AttachmentHandler represents HTTP handler for files attached to tasks
+type AttachmentHandler struct {
+	AttachmentService attachment.Service
+	App               *common.App
+}
+
+// New creates new HTTP handler for attachment
+func New(router *mux.Router, service attachment.Service, app *common.App) {
+	handler := &AttachmentHandler{
+		AttachmentService: service,
+		App:               app,
+	}
+
+	// API keys are accepted besides access tokens, as far as they grant the scope
+	readMiddleware := middlewares.ScopedValidator(app, auth.ScopeTasksRead)
+	writeMiddleware := middlewares.ScopedValidator(app, auth.ScopeTasksWrite)
+
+	router.HandleFunc("/tasks/{id:[0-9]+}/attachments", app.CreateHandler(writeMiddleware(handler.Upload))).Methods("POST")
+	router.HandleFunc("/tasks/{id:[0-9]+}/attachments", app.CreateHandler(readMiddleware(handler.List))).Methods("GET")
+	router.HandleFunc("/tasks/{id:[0-9]+}/attachments/{attachmentId:[0-9]+}", app.CreateHandler(readMiddleware(handler.Download))).Methods("GET")
+	router.HandleFunc("/tasks/{id:[0-9]+}/attachments/{attachmentId:[0-9]+}", app.CreateHandler(writeMiddleware(handler.Delete))).Methods("DELETE")
+}
+
+// Upload will attach a file to a task. File is sent as the "file" part of a multipart form.
+func (handler *AttachmentHandler) Upload(res http.ResponseWriter, req *http.Request, reqCtx *common.RequestContext) (int, interface{}, *todoErr.APIError) {
+	defer req.Body.Close()
+
+	taskID, apiError := parsePathID(req, "id")
+
+	if apiError != nil {
+		return http.StatusBadRequest, nil, apiError
+	}
+
+	setting := handler.App.Config.Attachment
+	maxBodySize := setting.MaxSizeInBytes + multipartOverhead
+
+	if req.ContentLength > maxBodySize {
+		apiError := todoErr.NewAPIError("", &todoErr.APIErrorBody{
+			Message: fmt.Sprintf("Size should be %d bytes or less", setting.MaxSizeInBytes),
+			Target:  "file",
+		})
+
+		return http.StatusBadRequest, nil, apiError
+	}
+
+	req.Body = http.MaxBytesReader(res, req.Body, maxBodySize)
+
+	if err := req.ParseMultipartForm(maxMemory); err != nil {
+		apiError := todoErr.NewAPIError(err.Error(), &todoErr.APIErrorBody{
+			Message: "Invalid request body",
+		})
+
+		return http.StatusBadRequest, nil, apiError
+	}
+
+	defer req.MultipartForm.RemoveAll()
+
+	file, header, err := req.FormFile("file")
+
+	if err != nil {
+		apiError := todoErr.NewAPIError(err.Error(), &todoErr.APIErrorBody{
+			Message: "Non-empty file is required",
+			Target:  "file",
+		})
+
+		return http.StatusBadRequest, nil, apiError
+	}
+
+	defer file.Close()
+
+	contentType, validationErrors := validateFile(file, header, setting)
+
+	if len(validationErrors) > 0 {
+		apiError := todoErr.NewAPIError("", validationErrors...)
+
+		return http.StatusBadRequest, nil, apiError
+	}
+
+	newAttachment := &models.Attachment{
+		Task: &models.Task{
+			ID: taskID,
+		},
+		FileName:    sanitizeFileName(header.Filename),
+		ContentType: contentType,
+		Size:        header.Size,
+		CreatedBy: &models.User{
+			ID: reqCtx.UserID,
+		},
+		CreatedAt: time.Now(),
+	}
+
+	if err = handler.AttachmentService.Upload(context.TODO(), newAttachment, file); err != nil {
+		return attachmentServiceError(err)
+	}
+
+	responseData := &UploadAttachmentResponse{
+		Attachment: newAttachmentData(newAttachment),
+	}
+
+	return http.StatusCreated, responseData, nil
+}
+
+// List will return the attachments of a task
+func (handler *AttachmentHandler) List(res http.ResponseWriter, req *http.Request, reqCtx *common.RequestContext) (int, interface{}, *todoErr.APIError) {
+	taskID, apiError := parsePathID(req, "id")
+
+	if apiError != nil {
+		return http.StatusBadRequest, nil, apiError
+	}
+
+	attachments, err := handler.AttachmentService.List(context.TODO(), taskID, reqCtx.UserID)
+
+	if err != nil {
+		return attachmentServiceError(err)
+	}
+
+	attachmentList := make([]*AttachmentData, 0)
+
+	for _, attachment := range attachments {
+		attachmentList = append(attachmentList, newAttachmentData(attachment))
+	}
+
+	responseData := &ListAttachmentResponse{
+		Attachments: attachmentList,
+	}
+
+	return http.StatusOK, responseData, nil
+}
+
+// Download will send the content of an attachment as a file
+func (handler *AttachmentHandler) Download(res http.ResponseWriter, req *http.Request, reqCtx *common.RequestContext) (int, interface{}, *todoErr.APIError) {
+	taskID, apiError := parsePathID(req, "id")
+
+	if apiError != nil {
+		return http.StatusBadRequest, nil, apiError
+	}
+
+	attachmentID, apiError := parsePathID(req, "attachmentId")
+
+	if apiError != nil {
+		return http.StatusBadRequest, nil, apiError
+	}
+
+	existingAttachment, content, err := handler.AttachmentService.Download(context.TODO(), taskID, attachmentID, reqCtx.UserID)
+
+	if err != nil {
+		return attachmentServiceError(err)
+	}
+
+	responseData := &common.RawResponse{
+		ContentType: existingAttachment.ContentType,
+		Headers: map[string]string{
+			"Content-Disposition":    fmt.Sprintf(`attachment; filename="%s"`, existingAttachment.FileName),
+			"Content-Length":         strconv.FormatInt(existingAttachment.Size, 10),
+			"X-Content-Type-Options": "nosniff",
+		},
+		Body: content,
+	}
+
+	return http.StatusOK, responseData, nil
+}
+
+// Delete will remove an attachment from a task
+func (handler *AttachmentHandler) Delete(res http.ResponseWriter, req *http.Request, reqCtx *common.RequestContext) (int, interface{}, *todoErr.APIError) {
+	taskID, apiError := parsePathID(req, "id")
+
+	if apiError != nil {
+		return http.StatusBadRequest, nil, apiError
+	}
+
+	attachmentID, apiError := parsePathID(req, "attachmentId")
+
+	if apiError != nil {
+		return http.StatusBadRequest, nil, apiError
+	}
+
+	if err := handler.AttachmentService.Delete(context.TODO(), taskID, attachmentID, reqCtx.UserID); err != nil {
+		return attachmentServiceError(err)
+	}
+
+	return http.StatusOK, nil, nil
+}
+
+// parsePathID reads an id from the request path
+func parsePathID(req *http.Request, name string) (int64, *todoErr.APIError) {
+	id, err := strconv.ParseInt(mux.Vars(req)[name], 10, 64)
+
+	if err != nil {
+		return 0, todoErr.NewAPIError(err.Error(), &todoErr.APIErrorBody{
+			Message: "Invalid value",
+			Target:  name,
+		})
+	}
+
+	return id, nil
+}
+
+// attachmentServiceError maps errors returned by the attachment service to the API response
+func attachmentServiceError(err error) (int, interface{}, *todoErr.APIError) {
+	switch err.(type) {
+	case *todoErr.ResourceNotFoundError:
+		resourceNotFoundErr, _ := err.(*todoErr.ResourceNotFoundError)
+
+		apiError := todoErr.NewAPIError(resourceNotFoundErr.Error(), &todoErr.APIErrorBody{
+			Message: "Not found",
+			Target:  resourceNotFoundErr.Resource,
+		})
+
+		return http.StatusNotFound, nil, apiError
+	case *todoErr.PermissionDeniedError:
+		permissionDeniedErr, _ := err.(*todoErr.PermissionDeniedError)
+
+		apiError := todoErr.NewAPIError(permissionDeniedErr.Error(), &todoErr.APIErrorBody{
+			Message: "Permission denied",
+			Target:  permissionDeniedErr.Resource,
+		})
+
+		return http.StatusForbidden, nil, apiError
+	case *todoErr.InvalidValueError:
+		invalidValueErr, _ := err.(*todoErr.InvalidValueError)
+
+		apiError := todoErr.NewAPIError(invalidValueErr.Error(), &todoErr.APIErrorBody{
+			Message: invalidValueErr.Reason,
+			Target:  invalidValueErr.Field,
+		})
+
+		return http.StatusBadRequest, nil, apiError
+	default:
+		apiError := todoErr.NewAPIError(err.Error(), &todoErr.APIErrorBody{
+			Message: "Internal server error",
+		})
+
+		return http.StatusInternalServerError, nil, apiError
+	}
+}
@@ -0,0 +1,231 @@
+package http_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dheerajgopi/todo-api/attachment"
+	_attachmentHandler "github.com/dheerajgopi/todo-api/attachment/delivery/http"
+	mock "github.com/dheerajgopi/todo-api/attachment/mock"
+	"github.com/dheerajgopi/todo-api/common"
+	_errors "github.com/dheerajgopi/todo-api/common/error"
+	"github.com/dheerajgopi/todo-api/config"
+	"github.com/dheerajgopi/todo-api/models"
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpload(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+	reqCtx.UserID = 1
+	req := newUploadRequest("../notes \"v2\".txt", "hello world")
+
+	mockService.
+		EXPECT().
+		Upload(gomock.Any(), gomock.Any(), gomock.Any()).
+		Do(func(_ interface{}, newAttachment *models.Attachment, _ interface{}) {
+			newAttachment.ID = 7
+		}).
+		Return(nil).
+		Times(1)
+
+	status, data, err := handler.Upload(httptest.NewRecorder(), req, reqCtx)
+
+	responseData := data.(*_attachmentHandler.UploadAttachmentResponse)
+
+	assert.Equal(201, status)
+	assert.Nil(err)
+	assert.Equal(int64(7), responseData.Attachment.ID)
+	assert.Equal(int64(5), responseData.Attachment.TaskID)
+	assert.Equal("notes _v2_.txt", responseData.Attachment.FileName)
+	assert.Equal("text/plain", responseData.Attachment.ContentType)
+	assert.Equal(int64(11), responseData.Attachment.Size)
+	assert.Equal(int64(1), responseData.Attachment.CreatedBy)
+}
+
+func TestUploadWithDisallowedContentType(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+	req := newUploadRequest("page.txt", "<html><body>hello</body></html>")
+
+	status, data, err := handler.Upload(httptest.NewRecorder(), req, reqCtx)
+
+	assert.Equal(400, status)
+	assert.Nil(data)
+	assert.Equal("file", err.Body[0].Target)
+	assert.Equal("Content type text/html is not allowed", err.Body[0].Message)
+}
+
+func TestUploadWithLargeFile(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+	req := newUploadRequest("notes.txt", strings.Repeat("a", 101))
+
+	status, data, err := handler.Upload(httptest.NewRecorder(), req, reqCtx)
+
+	assert.Equal(400, status)
+	assert.Nil(data)
+	assert.Equal("Size should be 100 bytes or less", err.Body[0].Message)
+}
+
+func TestUploadWithoutFile(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	writer.WriteField("name", "notes.txt")
+	writer.Close()
+
+	req := httptest.NewRequest("POST", "/tasks/5/attachments", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	req = mux.SetURLVars(req, map[string]string{"id": "5"})
+
+	status, data, err := handler.Upload(httptest.NewRecorder(), req, reqCtx)
+
+	assert.Equal(400, status)
+	assert.Nil(data)
+	assert.Equal("file", err.Body[0].Target)
+}
+
+func TestUploadExceedingQuota(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+	req := newUploadRequest("notes.txt", "hello")
+
+	mockService.
+		EXPECT().
+		Upload(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(&_errors.InvalidValueError{Resource: "attachment", Field: "file", Reason: "Attachment quota of 100 bytes exceeded"}).
+		Times(1)
+
+	status, data, err := handler.Upload(httptest.NewRecorder(), req, reqCtx)
+
+	assert.Equal(400, status)
+	assert.Nil(data)
+	assert.Equal("Attachment quota of 100 bytes exceeded", err.Body[0].Message)
+}
+
+func TestDownload(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+	reqCtx.UserID = 1
+	req := httptest.NewRequest("GET", "/tasks/5/attachments/7", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "5", "attachmentId": "7"})
+
+	existingAttachment := &models.Attachment{
+		ID:          7,
+		Task:        &models.Task{ID: 5},
+		FileName:    "notes.txt",
+		ContentType: "text/plain",
+		Size:        5,
+	}
+
+	mockService.
+		EXPECT().
+		Download(gomock.Any(), int64(5), int64(7), int64(1)).
+		Return(existingAttachment, ioutil.NopCloser(strings.NewReader("hello")), nil).
+		Times(1)
+
+	status, data, err := handler.Download(httptest.NewRecorder(), req, reqCtx)
+
+	responseData := data.(*common.RawResponse)
+
+	assert.Equal(200, status)
+	assert.Nil(err)
+	assert.Equal("text/plain", responseData.ContentType)
+	assert.Equal(`attachment; filename="notes.txt"`, responseData.Headers["Content-Disposition"])
+	assert.Equal("5", responseData.Headers["Content-Length"])
+}
+
+func TestDownloadFromInaccessibleTask(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+	req := httptest.NewRequest("GET", "/tasks/5/attachments/7", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "5", "attachmentId": "7"})
+
+	mockService.
+		EXPECT().
+		Download(gomock.Any(), int64(5), int64(7), gomock.Any()).
+		Return(nil, nil, &_errors.ResourceNotFoundError{Resource: "task"}).
+		Times(1)
+
+	status, data, err := handler.Download(httptest.NewRecorder(), req, reqCtx)
+
+	assert.Equal(404, status)
+	assert.Nil(data)
+	assert.Equal("task", err.Body[0].Target)
+}
+
+func newUploadRequest(fileName string, content string) *http.Request {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, _ := writer.CreateFormFile("file", fileName)
+	part.Write([]byte(content))
+	writer.Close()
+
+	req := httptest.NewRequest("POST", "/tasks/5/attachments", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	return mux.SetURLVars(req, map[string]string{"id": "5"})
+}
+
+func setupHandler(mockService attachment.Service) *_attachmentHandler.AttachmentHandler {
+	app := &common.App{
+		Logger: logrus.New(),
+		Config: &config.Config{
+			Attachment: &config.AttachmentSetting{
+				MaxSizeInBytes: 100,
+				QuotaInBytes:   1000,
+				ContentTypes:   []string{"text/plain", "image/png"},
+			},
+		},
+	}
+
+	handler := &_attachmentHandler.AttachmentHandler{
+		AttachmentService: mockService,
+		App:               app,
+	}
+
+	return handler
+}
+
+func setupRequestContext(app *common.App) *common.RequestContext {
+	reqCtx := &common.RequestContext{
+		RequestID: "dummyRequestID",
+		LogEntry: app.Logger.WithFields(
+			logrus.Fields{},
+		),
+	}
+
+	return reqCtx
+}
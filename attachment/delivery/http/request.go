@@ -0,0 +1,103 @@
+package http
+
+import (
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	todoErr "github.com/dheerajgopi/todo-api/common/error"
+	"github.com/dheerajgopi/todo-api/config"
+)
+
+// maxFileNameLength is the maximum number of characters kept from the name of an uploaded file
+const maxFileNameLength = 255
+
+// sniffLength is the number of bytes used for detecting the content type of an uploaded file
+const sniffLength = 512
+
+// validateFile checks the size and content type of the file part in POST /tasks/{id}/attachments API.
+// Content type is detected from the content itself, since the one sent by the client can not be trusted.
+// File is rewound to its beginning afterwards.
+func validateFile(file multipart.File, header *multipart.FileHeader, setting *config.AttachmentSetting) (string, []*todoErr.APIErrorBody) {
+	validationErrors := make([]*todoErr.APIErrorBody, 0)
+
+	if header.Size == 0 {
+		validationErrors = append(validationErrors, &todoErr.APIErrorBody{
+			Message: "Non-empty file is required",
+			Target:  "file",
+		})
+
+		return "", validationErrors
+	}
+
+	if header.Size > setting.MaxSizeInBytes {
+		validationErrors = append(validationErrors, &todoErr.APIErrorBody{
+			Message: fmt.Sprintf("Size should be %d bytes or less", setting.MaxSizeInBytes),
+			Target:  "file",
+		})
+
+		return "", validationErrors
+	}
+
+	buffer := make([]byte, sniffLength)
+	n, err := file.Read(buffer)
+
+	if err == nil || n > 0 {
+		_, err = file.Seek(0, io.SeekStart)
+	}
+
+	if err != nil {
+		validationErrors = append(validationErrors, &todoErr.APIErrorBody{
+			Message: "Unreadable file",
+			Target:  "file",
+		})
+
+		return "", validationErrors
+	}
+
+	contentType := strings.TrimSpace(strings.Split(http.DetectContentType(buffer[:n]), ";")[0])
+
+	for _, allowed := range setting.ContentTypes {
+		if allowed == contentType {
+			return contentType, validationErrors
+		}
+	}
+
+	validationErrors = append(validationErrors, &todoErr.APIErrorBody{
+		Message: fmt.Sprintf("Content type %s is not allowed", contentType),
+		Target:  "file",
+	})
+
+	return "", validationErrors
+}
+
+// sanitizeFileName strips the directories and control characters from the name of an uploaded file,
+// along with quotes and backslashes so that it can be sent back in Content-Disposition header
+func sanitizeFileName(name string) string {
+	name = filepath.Base(strings.Replace(name, "\\", "/", -1))
+
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) || r == '"' || r == '/' {
+			return '_'
+		}
+
+		return r
+	}, name)
+
+	name = strings.TrimSpace(name)
+
+	if utf8.RuneCountInString(name) > maxFileNameLength {
+		name = string([]rune(name)[:maxFileNameLength])
+	}
+
+	if name == "" || name == "." {
+		return "attachment"
+	}
+
+	return name
+}
@@ -0,0 +1,41 @@
+package http
+
+import (
+	"time"
+
+	"github.com/dheerajgopi/todo-api/models"
+)
+
+// AttachmentData represents json structure for attachment
+type AttachmentData struct {
+	ID          int64     `json:"id"`
+	TaskID      int64     `json:"taskId"`
+	FileName    string    `json:"fileName"`
+	ContentType string    `json:"contentType"`
+	Size        int64     `json:"size"`
+	CreatedBy   int64     `json:"createdBy"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+// UploadAttachmentResponse represents response for POST /tasks/{id}/attachments API
+type UploadAttachmentResponse struct {
+	Attachment *AttachmentData `json:"attachment"`
+}
+
+// ListAttachmentResponse represents response for GET /tasks/{id}/attachments API
+type ListAttachmentResponse struct {
+	Attachments []*AttachmentData `json:"attachments"`
+}
+
+// newAttachmentData builds the json structure of an attachment
+func newAttachmentData(attachment *models.Attachment) *AttachmentData {
+	return &AttachmentData{
+		ID:          attachment.ID,
+		TaskID:      attachment.Task.ID,
+		FileName:    attachment.FileName,
+		ContentType: attachment.ContentType,
+		Size:        attachment.Size,
+		CreatedBy:   attachment.CreatedBy.ID,
+		CreatedAt:   attachment.CreatedAt,
+	}
+}
@@ -0,0 +1,139 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/dheerajgopi/todo-api/attachment (interfaces: Repository)
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	models "github.com/dheerajgopi/todo-api/models"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+)
+
+// Repository is a mock of Repository interface
+type Repository struct {
+	ctrl     *gomock.Controller
+	recorder *RepositoryMockRecorder
+}
+
+// RepositoryMockRecorder is the mock recorder for Repository
+type RepositoryMockRecorder struct {
+	mock *Repository
+}
+
+// NewRepository creates a new mock instance
+func NewRepository(ctrl *gomock.Controller) *Repository {
+	mock := &Repository{ctrl: ctrl}
+	mock.recorder = &RepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *Repository) EXPECT() *RepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method
+func (m *Repository) Create(arg0 context.Context, arg1 *models.Attachment, arg2 int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0, arg1, arg2)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create
+func (mr *RepositoryMockRecorder) Create(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*Repository)(nil).Create), arg0, arg1, arg2)
+}
+
+// Delete mocks base method
+func (m *Repository) Delete(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete
+func (mr *RepositoryMockRecorder) Delete(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*Repository)(nil).Delete), arg0, arg1)
+}
+
+// GetAllByTaskID mocks base method
+func (m *Repository) GetAllByTaskID(arg0 context.Context, arg1 int64) ([]*models.Attachment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllByTaskID", arg0, arg1)
+	ret0, _ := ret[0].([]*models.Attachment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllByTaskID indicates an expected call of GetAllByTaskID
+func (mr *RepositoryMockRecorder) GetAllByTaskID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllByTaskID", reflect.TypeOf((*Repository)(nil).GetAllByTaskID), arg0, arg1)
+}
+
+// GetAllByTaskIDs mocks base method
+func (m *Repository) GetAllByTaskIDs(arg0 context.Context, arg1 []int64) ([]*models.Attachment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllByTaskIDs", arg0, arg1)
+	ret0, _ := ret[0].([]*models.Attachment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllByTaskIDs indicates an expected call of GetAllByTaskIDs
+func (mr *RepositoryMockRecorder) GetAllByTaskIDs(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllByTaskIDs", reflect.TypeOf((*Repository)(nil).GetAllByTaskIDs), arg0, arg1)
+}
+
+// GetAllByUserID mocks base method
+func (m *Repository) GetAllByUserID(arg0 context.Context, arg1 int64) ([]*models.Attachment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllByUserID", arg0, arg1)
+	ret0, _ := ret[0].([]*models.Attachment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllByUserID indicates an expected call of GetAllByUserID
+func (mr *RepositoryMockRecorder) GetAllByUserID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllByUserID", reflect.TypeOf((*Repository)(nil).GetAllByUserID), arg0, arg1)
+}
+
+// GetByID mocks base method
+func (m *Repository) GetByID(arg0 context.Context, arg1 int64) (*models.Attachment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", arg0, arg1)
+	ret0, _ := ret[0].(*models.Attachment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID
+func (mr *RepositoryMockRecorder) GetByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*Repository)(nil).GetByID), arg0, arg1)
+}
+
+// GetTotalSizeByUserID mocks base method
+func (m *Repository) GetTotalSizeByUserID(arg0 context.Context, arg1 int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTotalSizeByUserID", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTotalSizeByUserID indicates an expected call of GetTotalSizeByUserID
+func (mr *RepositoryMockRecorder) GetTotalSizeByUserID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTotalSizeByUserID", reflect.TypeOf((*Repository)(nil).GetTotalSizeByUserID), arg0, arg1)
+}
@@ -0,0 +1,95 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/dheerajgopi/todo-api/attachment (interfaces: Service)
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	models "github.com/dheerajgopi/todo-api/models"
+	gomock "github.com/golang/mock/gomock"
+	io "io"
+	reflect "reflect"
+)
+
+// Service is a mock of Service interface
+type Service struct {
+	ctrl     *gomock.Controller
+	recorder *ServiceMockRecorder
+}
+
+// ServiceMockRecorder is the mock recorder for Service
+type ServiceMockRecorder struct {
+	mock *Service
+}
+
+// NewService creates a new mock instance
+func NewService(ctrl *gomock.Controller) *Service {
+	mock := &Service{ctrl: ctrl}
+	mock.recorder = &ServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *Service) EXPECT() *ServiceMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method
+func (m *Service) Delete(arg0 context.Context, arg1, arg2, arg3 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete
+func (mr *ServiceMockRecorder) Delete(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*Service)(nil).Delete), arg0, arg1, arg2, arg3)
+}
+
+// Download mocks base method
+func (m *Service) Download(arg0 context.Context, arg1, arg2, arg3 int64) (*models.Attachment, io.ReadCloser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Download", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*models.Attachment)
+	ret1, _ := ret[1].(io.ReadCloser)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Download indicates an expected call of Download
+func (mr *ServiceMockRecorder) Download(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Download", reflect.TypeOf((*Service)(nil).Download), arg0, arg1, arg2, arg3)
+}
+
+// List mocks base method
+func (m *Service) List(arg0 context.Context, arg1, arg2 int64) ([]*models.Attachment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*models.Attachment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List
+func (mr *ServiceMockRecorder) List(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*Service)(nil).List), arg0, arg1, arg2)
+}
+
+// Upload mocks base method
+func (m *Service) Upload(arg0 context.Context, arg1 *models.Attachment, arg2 io.Reader) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Upload", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Upload indicates an expected call of Upload
+func (mr *ServiceMockRecorder) Upload(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upload", reflect.TypeOf((*Service)(nil).Upload), arg0, arg1, arg2)
+}
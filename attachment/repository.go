@@ -0,0 +1,18 @@
+package attachment
+
+import (
+	"context"
+
+	"github.com/dheerajgopi/todo-api/models"
+)
+
+// Repository represents attachment's repository contract
+type Repository interface {
+	GetByID(ctx context.Context, id int64) (*models.Attachment, error)
+	GetAllByTaskID(ctx context.Context, taskID int64) ([]*models.Attachment, error)
+	GetAllByTaskIDs(ctx context.Context, taskIDs []int64) ([]*models.Attachment, error)
+	GetAllByUserID(ctx context.Context, userID int64) ([]*models.Attachment, error)
+	GetTotalSizeByUserID(ctx context.Context, userID int64) (int64, error)
+	Create(ctx context.Context, attachment *models.Attachment, quotaInBytes int64) (bool, error)
+	Delete(ctx context.Context, id int64) error
+}
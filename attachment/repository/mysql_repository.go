@@ -0,0 +1,243 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/dheerajgopi/todo-api/attachment"
+	"github.com/dheerajgopi/todo-api/models"
+)
+
+type mySQLAttachmentRepo struct {
+	DB *sql.DB
+}
+
+// New will return new object which implements attachment.Repository
+func New(db *sql.DB) attachment.Repository {
+	return &mySQLAttachmentRepo{
+		DB: db,
+	}
+}
+
+const selectAttachmentQuery = `SELECT id, task_id, file_name, content_type, size, storage_key, created_by, created_at FROM attachment `
+
+// rowScanner is implemented by both sql.Row and sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanAttachment reads an attachment from a row of selectAttachmentQuery
+func scanAttachment(row rowScanner) (*models.Attachment, error) {
+	attachment := &models.Attachment{
+		Task:      &models.Task{},
+		CreatedBy: &models.User{},
+	}
+
+	err := row.Scan(
+		&attachment.ID,
+		&attachment.Task.ID,
+		&attachment.FileName,
+		&attachment.ContentType,
+		&attachment.Size,
+		&attachment.StorageKey,
+		&attachment.CreatedBy.ID,
+		&attachment.CreatedAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return attachment, nil
+}
+
+// GetByID will return attachment with the given id
+func (repo *mySQLAttachmentRepo) GetByID(ctx context.Context, id int64) (*models.Attachment, error) {
+	stmt, err := repo.DB.PrepareContext(ctx, selectAttachmentQuery+`WHERE id=?`)
+
+	if err != nil {
+		return nil, err
+	}
+
+	attachment, err := scanAttachment(stmt.QueryRowContext(ctx, id))
+
+	switch err {
+	case nil:
+	case sql.ErrNoRows:
+		return nil, nil
+	default:
+		return nil, err
+	}
+
+	return attachment, nil
+}
+
+// GetAllByTaskID returns the attachments of a task, oldest first
+func (repo *mySQLAttachmentRepo) GetAllByTaskID(ctx context.Context, taskID int64) ([]*models.Attachment, error) {
+	return repo.getAll(ctx, selectAttachmentQuery+`WHERE task_id=? ORDER BY created_at, id`, taskID)
+}
+
+// GetAllByTaskIDs returns the attachments of the given tasks and of their subtasks
+func (repo *mySQLAttachmentRepo) GetAllByTaskIDs(ctx context.Context, taskIDs []int64) ([]*models.Attachment, error) {
+	if len(taskIDs) == 0 {
+		return make([]*models.Attachment, 0), nil
+	}
+
+	args := make([]interface{}, 0, len(taskIDs))
+
+	for _, id := range taskIDs {
+		args = append(args, id)
+	}
+
+	inTaskIDs := `(` + strings.TrimSuffix(strings.Repeat("?, ", len(taskIDs)), ", ") + `)`
+	query := selectAttachmentQuery + `WHERE task_id IN (SELECT id FROM task WHERE id IN ` + inTaskIDs +
+		` OR parent_id IN ` + inTaskIDs + `) ORDER BY id`
+
+	return repo.getAll(ctx, query, append(args, args...)...)
+}
+
+// GetAllByUserID returns the attachments uploaded by an user, along with the attachments of the user's tasks and their subtasks
+func (repo *mySQLAttachmentRepo) GetAllByUserID(ctx context.Context, userID int64) ([]*models.Attachment, error) {
+	query := selectAttachmentQuery + `WHERE created_by=? OR task_id IN (SELECT id FROM task
+		WHERE created_by=? OR parent_id IN (SELECT id FROM task WHERE created_by=?)) ORDER BY id`
+
+	return repo.getAll(ctx, query, userID, userID, userID)
+}
+
+func (repo *mySQLAttachmentRepo) getAll(ctx context.Context, query string, args ...interface{}) ([]*models.Attachment, error) {
+	stmt, err := repo.DB.PrepareContext(ctx, query)
+
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := stmt.QueryContext(ctx, args...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	attachments := make([]*models.Attachment, 0)
+
+	for rows.Next() {
+		attachment, err := scanAttachment(rows)
+
+		if err != nil {
+			return nil, err
+		}
+
+		attachments = append(attachments, attachment)
+	}
+
+	err = rows.Err()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return attachments, nil
+}
+
+// GetTotalSizeByUserID returns the total size in bytes of the attachments uploaded by an user
+func (repo *mySQLAttachmentRepo) GetTotalSizeByUserID(ctx context.Context, userID int64) (int64, error) {
+	stmt, err := repo.DB.PrepareContext(ctx, `SELECT COALESCE(SUM(size), 0) FROM attachment WHERE created_by=?`)
+
+	if err != nil {
+		return 0, err
+	}
+
+	var totalSize int64
+
+	if err = stmt.QueryRowContext(ctx, userID).Scan(&totalSize); err != nil {
+		return 0, err
+	}
+
+	return totalSize, nil
+}
+
+// Create will store new attachment entry, unless the total size of the attachments uploaded by its user would exceed the quota.
+// User row is locked while the total size is read, so that parallel uploads of the user can not exceed the quota together.
+// False is returned if the attachment is not stored because of the quota.
+func (repo *mySQLAttachmentRepo) Create(ctx context.Context, attachment *models.Attachment, quotaInBytes int64) (bool, error) {
+	tx, err := repo.DB.BeginTx(ctx, nil)
+
+	if err != nil {
+		return false, err
+	}
+
+	var lockedID, usedSize int64
+
+	if err = tx.QueryRow(`SELECT id FROM user WHERE id=? FOR UPDATE`, attachment.CreatedBy.ID).Scan(&lockedID); err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	err = tx.QueryRow(`SELECT COALESCE(SUM(size), 0) FROM attachment WHERE created_by=?`, attachment.CreatedBy.ID).Scan(&usedSize)
+
+	if err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	if usedSize+attachment.Size > quotaInBytes {
+		tx.Rollback()
+		return false, nil
+	}
+
+	query := `INSERT INTO attachment (task_id, file_name, content_type, size, storage_key, created_by, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`
+
+	res, err := tx.Exec(
+		query,
+		attachment.Task.ID,
+		attachment.FileName,
+		attachment.ContentType,
+		attachment.Size,
+		attachment.StorageKey,
+		attachment.CreatedBy.ID,
+		attachment.CreatedAt,
+	)
+
+	if err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	lastID, err := res.LastInsertId()
+
+	if err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return false, err
+	}
+
+	attachment.ID = lastID
+
+	return true, nil
+}
+
+// Delete will remove an attachment entry
+func (repo *mySQLAttachmentRepo) Delete(ctx context.Context, id int64) error {
+	query := `DELETE FROM attachment WHERE id=?`
+
+	tx, err := repo.DB.BeginTx(ctx, nil)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(query, id)
+
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,269 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/dheerajgopi/todo-api/attachment/repository"
+	"github.com/dheerajgopi/todo-api/models"
+	"github.com/stretchr/testify/assert"
+)
+
+var attachmentColumns = []string{"id", "task_id", "file_name", "content_type", "size", "storage_key", "created_by", "created_at"}
+
+const selectAttachmentQuery = "SELECT id, task_id, file_name, content_type, size, storage_key, created_by, created_at FROM attachment "
+
+func TestGetByID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	rows := sqlmock.
+		NewRows(attachmentColumns).
+		AddRow(1, 5, "screen.png", "image/png", 1024, "tasks/5/abc", 2, time.Now())
+
+	prep := mock.ExpectPrepare(selectAttachmentQuery + "WHERE id=\\?")
+	prep.ExpectQuery().WithArgs(int64(1)).WillReturnRows(rows)
+
+	repo := repository.New(db)
+
+	attachment, err := repo.GetByID(context.TODO(), 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), attachment.Task.ID)
+	assert.Equal(t, int64(2), attachment.CreatedBy.ID)
+	assert.Equal(t, "screen.png", attachment.FileName)
+	assert.Equal(t, int64(1024), attachment.Size)
+	assert.Equal(t, "tasks/5/abc", attachment.StorageKey)
+}
+
+func TestGetByIDWithNoRows(t *testing.T) {
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	prep := mock.ExpectPrepare(selectAttachmentQuery + "WHERE id=\\?")
+	prep.ExpectQuery().WithArgs(int64(1)).WillReturnRows(sqlmock.NewRows(attachmentColumns))
+
+	repo := repository.New(db)
+
+	attachment, err := repo.GetByID(context.TODO(), 1)
+
+	assert.NoError(t, err)
+	assert.Nil(t, attachment)
+}
+
+func TestGetAllByTaskID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	rows := sqlmock.
+		NewRows(attachmentColumns).
+		AddRow(1, 5, "screen.png", "image/png", 1024, "tasks/5/abc", 1, time.Now()).
+		AddRow(2, 5, "spec.pdf", "application/pdf", 2048, "tasks/5/def", 2, time.Now())
+
+	prep := mock.ExpectPrepare(selectAttachmentQuery + "WHERE task_id=\\? ORDER BY created_at, id")
+	prep.ExpectQuery().WithArgs(int64(5)).WillReturnRows(rows)
+
+	repo := repository.New(db)
+
+	attachments, err := repo.GetAllByTaskID(context.TODO(), 5)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(attachments))
+	assert.Equal(t, "spec.pdf", attachments[1].FileName)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetAllByTaskIDs(t *testing.T) {
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	rows := sqlmock.
+		NewRows(attachmentColumns).
+		AddRow(1, 5, "screen.png", "image/png", 1024, "tasks/5/abc", 2, time.Now()).
+		AddRow(2, 8, "notes.txt", "text/plain", 12, "tasks/8/def", 2, time.Now())
+
+	query := selectAttachmentQuery + "WHERE task_id IN \\(SELECT id FROM task WHERE id IN \\(\\?, \\?\\) " +
+		"OR parent_id IN \\(\\?, \\?\\)\\) ORDER BY id"
+
+	prep := mock.ExpectPrepare(query)
+	prep.ExpectQuery().WithArgs(int64(5), int64(6), int64(5), int64(6)).WillReturnRows(rows)
+
+	repo := repository.New(db)
+
+	attachments, err := repo.GetAllByTaskIDs(context.TODO(), []int64{5, 6})
+
+	assert.NoError(t, err)
+	assert.Len(t, attachments, 2)
+	assert.Equal(t, "tasks/8/def", attachments[1].StorageKey)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetAllByUserID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	rows := sqlmock.
+		NewRows(attachmentColumns).
+		AddRow(1, 5, "screen.png", "image/png", 1024, "tasks/5/abc", 2, time.Now())
+
+	query := selectAttachmentQuery + "WHERE created_by=\\? OR task_id IN \\(SELECT id FROM task\\s+" +
+		"WHERE created_by=\\? OR parent_id IN \\(SELECT id FROM task WHERE created_by=\\?\\)\\) ORDER BY id"
+
+	prep := mock.ExpectPrepare(query)
+	prep.ExpectQuery().WithArgs(int64(2), int64(2), int64(2)).WillReturnRows(rows)
+
+	repo := repository.New(db)
+
+	attachments, err := repo.GetAllByUserID(context.TODO(), 2)
+
+	assert.NoError(t, err)
+	assert.Len(t, attachments, 1)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetTotalSizeByUserID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{"size"}).AddRow(3072)
+
+	prep := mock.ExpectPrepare("SELECT COALESCE\\(SUM\\(size\\), 0\\) FROM attachment WHERE created_by=\\?")
+	prep.ExpectQuery().WithArgs(int64(2)).WillReturnRows(rows)
+
+	repo := repository.New(db)
+
+	totalSize, err := repo.GetTotalSizeByUserID(context.TODO(), 2)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3072), totalSize)
+}
+
+func TestCreate(t *testing.T) {
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	attachment := &models.Attachment{
+		Task:        &models.Task{ID: 5},
+		FileName:    "screen.png",
+		ContentType: "image/png",
+		Size:        1024,
+		StorageKey:  "tasks/5/abc",
+		CreatedBy:   &models.User{ID: 2},
+		CreatedAt:   time.Now(),
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT id FROM user WHERE id=\\? FOR UPDATE").
+		WithArgs(int64(2)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+	mock.ExpectQuery("SELECT COALESCE\\(SUM\\(size\\), 0\\) FROM attachment WHERE created_by=\\?").
+		WithArgs(int64(2)).
+		WillReturnRows(sqlmock.NewRows([]string{"size"}).AddRow(2048))
+	mock.ExpectExec("INSERT INTO attachment \\(task_id, file_name, content_type, size, storage_key, created_by, created_at\\)\\s+VALUES \\(\\?, \\?, \\?, \\?, \\?, \\?, \\?\\)").
+		WithArgs(int64(5), "screen.png", "image/png", int64(1024), "tasks/5/abc", int64(2), attachment.CreatedAt).
+		WillReturnResult(sqlmock.NewResult(7, 1))
+	mock.ExpectCommit()
+
+	repo := repository.New(db)
+
+	created, err := repo.Create(context.TODO(), attachment, 4096)
+
+	assert.NoError(t, err)
+	assert.True(t, created)
+	assert.Equal(t, int64(7), attachment.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateExceedingQuota(t *testing.T) {
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	attachment := &models.Attachment{
+		Task:      &models.Task{ID: 5},
+		Size:      1024,
+		CreatedBy: &models.User{ID: 2},
+		CreatedAt: time.Now(),
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT id FROM user WHERE id=\\? FOR UPDATE").
+		WithArgs(int64(2)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+	mock.ExpectQuery("SELECT COALESCE\\(SUM\\(size\\), 0\\) FROM attachment WHERE created_by=\\?").
+		WithArgs(int64(2)).
+		WillReturnRows(sqlmock.NewRows([]string{"size"}).AddRow(3584))
+	mock.ExpectRollback()
+
+	repo := repository.New(db)
+
+	created, err := repo.Create(context.TODO(), attachment, 4096)
+
+	assert.NoError(t, err)
+	assert.False(t, created)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDelete(t *testing.T) {
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM attachment WHERE id=\\?").
+		WithArgs(int64(7)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	repo := repository.New(db)
+
+	err = repo.Delete(context.TODO(), 7)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
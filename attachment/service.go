@@ -0,0 +1,16 @@
+package attachment
+
+import (
+	"context"
+	"io"
+
+	"github.com/dheerajgopi/todo-api/models"
+)
+
+// Service represents attachment service contract
+type Service interface {
+	Upload(ctx context.Context, newAttachment *models.Attachment, content io.Reader) error
+	List(ctx context.Context, taskID int64, userID int64) ([]*models.Attachment, error)
+	Download(ctx context.Context, taskID int64, id int64, userID int64) (*models.Attachment, io.ReadCloser, error)
+	Delete(ctx context.Context, taskID int64, id int64, userID int64) error
+}
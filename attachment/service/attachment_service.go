@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/dheerajgopi/todo-api/attachment"
+	"github.com/dheerajgopi/todo-api/collaborator"
+	todoErr "github.com/dheerajgopi/todo-api/common/error"
+	"github.com/dheerajgopi/todo-api/models"
+	"github.com/dheerajgopi/todo-api/storage"
+	"github.com/dheerajgopi/todo-api/task"
+	"github.com/google/uuid"
+)
+
+type attachmentService struct {
+	attachmentRepo attachment.Repository
+	store          storage.Storage
+	taskService    task.Service
+	quotaInBytes   int64
+}
+
+// New returns a new object implementing attachment.Service interface.
+// Access to the attachments of a task is checked through the task service,
+// and the total size of the attachments uploaded by an user is limited by the quota.
+func New(repo attachment.Repository, store storage.Storage, taskService task.Service, quotaInBytes int64) attachment.Service {
+	return &attachmentService{
+		attachmentRepo: repo,
+		store:          store,
+		taskService:    taskService,
+		quotaInBytes:   quotaInBytes,
+	}
+}
+
+// Upload stores the content of a new attachment and adds it to a task editable by the user.
+// Content is stored under a generated key, and removed again if the attachment could not be saved.
+// Quota is checked before storing the content, and again while saving the attachment, since parallel uploads may pass the first check.
+func (service *attachmentService) Upload(ctx context.Context, newAttachment *models.Attachment, content io.Reader) error {
+	userID := newAttachment.CreatedBy.ID
+
+	if _, err := service.taskService.Authorize(ctx, newAttachment.Task.ID, userID, collaborator.RoleEditor, "attach files to"); err != nil {
+		return err
+	}
+
+	usedSize, err := service.attachmentRepo.GetTotalSizeByUserID(ctx, userID)
+
+	if err != nil {
+		return err
+	}
+
+	if usedSize+newAttachment.Size > service.quotaInBytes {
+		return service.quotaError()
+	}
+
+	newAttachment.StorageKey = fmt.Sprintf("tasks/%d/%s", newAttachment.Task.ID, uuid.New().String())
+
+	if err = service.store.Put(ctx, newAttachment.StorageKey, content, newAttachment.Size, newAttachment.ContentType); err != nil {
+		return err
+	}
+
+	created, err := service.attachmentRepo.Create(ctx, newAttachment, service.quotaInBytes)
+
+	if err != nil || !created {
+		service.store.Delete(ctx, newAttachment.StorageKey)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if !created {
+		return service.quotaError()
+	}
+
+	return nil
+}
+
+// List returns the attachments of a task accessible to the user, oldest first
+func (service *attachmentService) List(ctx context.Context, taskID int64, userID int64) ([]*models.Attachment, error) {
+	if _, err := service.taskService.GetByID(ctx, taskID, userID); err != nil {
+		return nil, err
+	}
+
+	return service.attachmentRepo.GetAllByTaskID(ctx, taskID)
+}
+
+// Download returns an attachment of a task accessible to the user, along with its content.
+// Content should be closed by the caller.
+func (service *attachmentService) Download(ctx context.Context, taskID int64, id int64, userID int64) (*models.Attachment, io.ReadCloser, error) {
+	if _, err := service.taskService.GetByID(ctx, taskID, userID); err != nil {
+		return nil, nil, err
+	}
+
+	existingAttachment, err := service.get(ctx, taskID, id)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	content, err := service.store.Get(ctx, existingAttachment.StorageKey)
+
+	if err == storage.ErrNotFound {
+		return nil, nil, &todoErr.ResourceNotFoundError{
+			Resource: "attachment",
+		}
+	}
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return existingAttachment, content, nil
+}
+
+// Delete removes an attachment from a task editable by the user, along with its content
+func (service *attachmentService) Delete(ctx context.Context, taskID int64, id int64, userID int64) error {
+	if _, err := service.taskService.Authorize(ctx, taskID, userID, collaborator.RoleEditor, "remove attachments from"); err != nil {
+		return err
+	}
+
+	existingAttachment, err := service.get(ctx, taskID, id)
+
+	if err != nil {
+		return err
+	}
+
+	if err = service.attachmentRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	return service.store.Delete(ctx, existingAttachment.StorageKey)
+}
+
+// get loads an attachment, and returns ResourceNotFoundError if it is missing or attached to another task
+func (service *attachmentService) get(ctx context.Context, taskID int64, id int64) (*models.Attachment, error) {
+	existingAttachment, err := service.attachmentRepo.GetByID(ctx, id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if existingAttachment == nil || existingAttachment.Task.ID != taskID {
+		return nil, &todoErr.ResourceNotFoundError{
+			Resource: "attachment",
+		}
+	}
+
+	return existingAttachment, nil
+}
+
+// quotaError is returned when an upload would exceed the attachment quota of the user
+func (service *attachmentService) quotaError() error {
+	return &todoErr.InvalidValueError{
+		Resource: "attachment",
+		Field:    "file",
+		Reason:   fmt.Sprintf("Attachment quota of %d bytes exceeded", service.quotaInBytes),
+	}
+}
@@ -0,0 +1,286 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	attachmentMock "github.com/dheerajgopi/todo-api/attachment/mock"
+	"github.com/dheerajgopi/todo-api/attachment/service"
+	"github.com/dheerajgopi/todo-api/collaborator"
+	todoErr "github.com/dheerajgopi/todo-api/common/error"
+	"github.com/dheerajgopi/todo-api/models"
+	"github.com/dheerajgopi/todo-api/storage"
+	storageMock "github.com/dheerajgopi/todo-api/storage/mock"
+	taskMock "github.com/dheerajgopi/todo-api/task/mock"
+)
+
+func TestUpload(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	mockRepo := attachmentMock.NewRepository(mockCtrl)
+	mockStorage := storageMock.NewStorage(mockCtrl)
+	mockTaskService := taskMock.NewService(mockCtrl)
+	attachmentService := service.New(mockRepo, mockStorage, mockTaskService, 100)
+	content := strings.NewReader("hello")
+
+	newAttachment := &models.Attachment{
+		Task:        &models.Task{ID: 5},
+		FileName:    "notes.txt",
+		ContentType: "text/plain",
+		Size:        5,
+		CreatedBy:   &models.User{ID: 1},
+	}
+
+	gomock.InOrder(
+		mockTaskService.EXPECT().Authorize(ctx, int64(5), int64(1), collaborator.RoleEditor, gomock.Any()).Return(&models.Task{ID: 5}, nil),
+		mockRepo.EXPECT().GetTotalSizeByUserID(ctx, int64(1)).Return(int64(95), nil),
+		mockStorage.EXPECT().Put(ctx, gomock.Any(), content, int64(5), "text/plain").Return(nil),
+		mockRepo.EXPECT().Create(ctx, newAttachment, int64(100)).Return(true, nil),
+	)
+
+	err := attachmentService.Upload(ctx, newAttachment, content)
+
+	assert.NoError(err)
+	assert.True(strings.HasPrefix(newAttachment.StorageKey, "tasks/5/"))
+}
+
+func TestUploadExceedingQuota(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	mockRepo := attachmentMock.NewRepository(mockCtrl)
+	mockTaskService := taskMock.NewService(mockCtrl)
+	attachmentService := service.New(mockRepo, storageMock.NewStorage(mockCtrl), mockTaskService, 100)
+
+	newAttachment := &models.Attachment{
+		Task:      &models.Task{ID: 5},
+		Size:      6,
+		CreatedBy: &models.User{ID: 1},
+	}
+
+	gomock.InOrder(
+		mockTaskService.EXPECT().Authorize(ctx, int64(5), int64(1), collaborator.RoleEditor, gomock.Any()).Return(&models.Task{ID: 5}, nil),
+		mockRepo.EXPECT().GetTotalSizeByUserID(ctx, int64(1)).Return(int64(95), nil),
+	)
+
+	err := attachmentService.Upload(ctx, newAttachment, strings.NewReader("hello!"))
+
+	assert.Error(err)
+	assert.IsType(&todoErr.InvalidValueError{}, err)
+}
+
+func TestUploadExceedingQuotaWithParallelUpload(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	mockRepo := attachmentMock.NewRepository(mockCtrl)
+	mockStorage := storageMock.NewStorage(mockCtrl)
+	mockTaskService := taskMock.NewService(mockCtrl)
+	attachmentService := service.New(mockRepo, mockStorage, mockTaskService, 100)
+
+	newAttachment := &models.Attachment{
+		Task:        &models.Task{ID: 5},
+		ContentType: "text/plain",
+		Size:        5,
+		CreatedBy:   &models.User{ID: 1},
+	}
+
+	// another upload used up the quota after the first check, so the attachment is not saved
+	gomock.InOrder(
+		mockTaskService.EXPECT().Authorize(ctx, int64(5), int64(1), collaborator.RoleEditor, gomock.Any()).Return(&models.Task{ID: 5}, nil),
+		mockRepo.EXPECT().GetTotalSizeByUserID(ctx, int64(1)).Return(int64(95), nil),
+		mockStorage.EXPECT().Put(ctx, gomock.Any(), gomock.Any(), int64(5), "text/plain").Return(nil),
+		mockRepo.EXPECT().Create(ctx, newAttachment, int64(100)).Return(false, nil),
+		mockStorage.EXPECT().Delete(ctx, gomock.Any()).Return(nil),
+	)
+
+	err := attachmentService.Upload(ctx, newAttachment, strings.NewReader("hello"))
+
+	assert.IsType(&todoErr.InvalidValueError{}, err)
+}
+
+func TestUploadByViewer(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	mockTaskService := taskMock.NewService(mockCtrl)
+	attachmentService := service.New(attachmentMock.NewRepository(mockCtrl), storageMock.NewStorage(mockCtrl), mockTaskService, 100)
+
+	newAttachment := &models.Attachment{
+		Task:      &models.Task{ID: 5},
+		Size:      5,
+		CreatedBy: &models.User{ID: 1},
+	}
+
+	mockTaskService.
+		EXPECT().
+		Authorize(ctx, int64(5), int64(1), collaborator.RoleEditor, gomock.Any()).
+		Return(nil, &todoErr.PermissionDeniedError{Resource: "task", Action: "attach files to"}).
+		Times(1)
+
+	err := attachmentService.Upload(ctx, newAttachment, strings.NewReader("hello"))
+
+	assert.IsType(&todoErr.PermissionDeniedError{}, err)
+}
+
+func TestUploadRemovesContentWhenSaveFails(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	mockRepo := attachmentMock.NewRepository(mockCtrl)
+	mockStorage := storageMock.NewStorage(mockCtrl)
+	mockTaskService := taskMock.NewService(mockCtrl)
+	attachmentService := service.New(mockRepo, mockStorage, mockTaskService, 100)
+	storageKey := ""
+
+	newAttachment := &models.Attachment{
+		Task:        &models.Task{ID: 5},
+		ContentType: "text/plain",
+		Size:        5,
+		CreatedBy:   &models.User{ID: 1},
+	}
+
+	gomock.InOrder(
+		mockTaskService.EXPECT().Authorize(ctx, int64(5), int64(1), collaborator.RoleEditor, gomock.Any()).Return(&models.Task{ID: 5}, nil),
+		mockRepo.EXPECT().GetTotalSizeByUserID(ctx, int64(1)).Return(int64(0), nil),
+		mockStorage.EXPECT().Put(ctx, gomock.Any(), gomock.Any(), int64(5), "text/plain").
+			Do(func(_ interface{}, key string, _ interface{}, _ int64, _ string) {
+				storageKey = key
+			}).
+			Return(nil),
+		mockRepo.EXPECT().Create(ctx, newAttachment, int64(100)).Return(false, errors.New("db down")),
+		mockStorage.EXPECT().Delete(ctx, gomock.Any()).
+			Do(func(_ interface{}, key string) {
+				assert.Equal(storageKey, key)
+			}).
+			Return(nil),
+	)
+
+	err := attachmentService.Upload(ctx, newAttachment, strings.NewReader("hello"))
+
+	assert.EqualError(err, "db down")
+}
+
+func TestDownload(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	mockRepo := attachmentMock.NewRepository(mockCtrl)
+	mockStorage := storageMock.NewStorage(mockCtrl)
+	mockTaskService := taskMock.NewService(mockCtrl)
+	attachmentService := service.New(mockRepo, mockStorage, mockTaskService, 100)
+
+	existingAttachment := &models.Attachment{
+		ID:         7,
+		Task:       &models.Task{ID: 5},
+		StorageKey: "tasks/5/abc",
+	}
+
+	gomock.InOrder(
+		mockTaskService.EXPECT().GetByID(ctx, int64(5), int64(1)).Return(&models.Task{ID: 5}, nil),
+		mockRepo.EXPECT().GetByID(ctx, int64(7)).Return(existingAttachment, nil),
+		mockStorage.EXPECT().Get(ctx, "tasks/5/abc").Return(ioutil.NopCloser(strings.NewReader("hello")), nil),
+	)
+
+	attachment, content, err := attachmentService.Download(ctx, 5, 7, 1)
+
+	assert.NoError(err)
+	assert.Equal(existingAttachment, attachment)
+
+	body, _ := ioutil.ReadAll(content)
+
+	assert.Equal("hello", string(body))
+}
+
+func TestDownloadFromOtherTask(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	mockRepo := attachmentMock.NewRepository(mockCtrl)
+	mockTaskService := taskMock.NewService(mockCtrl)
+	attachmentService := service.New(mockRepo, storageMock.NewStorage(mockCtrl), mockTaskService, 100)
+
+	gomock.InOrder(
+		mockTaskService.EXPECT().GetByID(ctx, int64(5), int64(1)).Return(&models.Task{ID: 5}, nil),
+		mockRepo.EXPECT().GetByID(ctx, int64(7)).Return(&models.Attachment{ID: 7, Task: &models.Task{ID: 6}}, nil),
+	)
+
+	_, _, err := attachmentService.Download(ctx, 5, 7, 1)
+
+	assert.Equal(&todoErr.ResourceNotFoundError{Resource: "attachment"}, err)
+}
+
+func TestDownloadWithMissingContent(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	mockRepo := attachmentMock.NewRepository(mockCtrl)
+	mockStorage := storageMock.NewStorage(mockCtrl)
+	mockTaskService := taskMock.NewService(mockCtrl)
+	attachmentService := service.New(mockRepo, mockStorage, mockTaskService, 100)
+
+	gomock.InOrder(
+		mockTaskService.EXPECT().GetByID(ctx, int64(5), int64(1)).Return(&models.Task{ID: 5}, nil),
+		mockRepo.EXPECT().GetByID(ctx, int64(7)).Return(&models.Attachment{ID: 7, Task: &models.Task{ID: 5}, StorageKey: "tasks/5/abc"}, nil),
+		mockStorage.EXPECT().Get(ctx, "tasks/5/abc").Return(nil, storage.ErrNotFound),
+	)
+
+	_, _, err := attachmentService.Download(ctx, 5, 7, 1)
+
+	assert.Equal(&todoErr.ResourceNotFoundError{Resource: "attachment"}, err)
+}
+
+func TestDelete(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	mockRepo := attachmentMock.NewRepository(mockCtrl)
+	mockStorage := storageMock.NewStorage(mockCtrl)
+	mockTaskService := taskMock.NewService(mockCtrl)
+	attachmentService := service.New(mockRepo, mockStorage, mockTaskService, 100)
+
+	gomock.InOrder(
+		mockTaskService.EXPECT().Authorize(ctx, int64(5), int64(1), collaborator.RoleEditor, gomock.Any()).Return(&models.Task{ID: 5}, nil),
+		mockRepo.EXPECT().GetByID(ctx, int64(7)).Return(&models.Attachment{ID: 7, Task: &models.Task{ID: 5}, StorageKey: "tasks/5/abc"}, nil),
+		mockRepo.EXPECT().Delete(ctx, int64(7)).Return(nil),
+		mockStorage.EXPECT().Delete(ctx, "tasks/5/abc").Return(nil),
+	)
+
+	err := attachmentService.Delete(ctx, 5, 7, 1)
+
+	assert.NoError(err)
+}
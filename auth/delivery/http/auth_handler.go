@@ -0,0 +1,149 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/dheerajgopi/todo-api/auth"
+	"github.com/dheerajgopi/todo-api/common"
+	todoErr "github.com/dheerajgopi/todo-api/common/error"
+	"github.com/dheerajgopi/todo-api/common/middlewares"
+	"github.com/gorilla/mux"
+)
+
+// jwksMaxAge is the number of seconds for which the JWKS can be cached.
+// New keys should be published this long before they sign any token.
+const jwksMaxAge = 300
+
+// AuthHandler represents HTTP handler for auth tokens
+type AuthHandler struct {
+	AuthService auth.Service
+	App         *common.App
+}
+
+// New creates new HTTP handler for auth tokens.
+// Refresh is authenticated by the refresh token in its body, and the JWKS is public,
+// so they do not go through the JWT validator.
+func New(router *mux.Router, service auth.Service, app *common.App) {
+	handler := &AuthHandler{
+		AuthService: service,
+		App:         app,
+	}
+
+	jwtMiddleware := middlewares.UnverifiedJwtValidator(app)
+
+	router.HandleFunc("/token/refresh", app.CreateHandler(handler.Refresh)).Methods("POST")
+	router.HandleFunc("/logout", app.CreateHandler(jwtMiddleware(handler.Logout))).Methods("POST")
+	router.HandleFunc("/.well-known/jwks.json", app.CreateHandler(handler.JWKS)).Methods("GET")
+}
+
+// Refresh will exchange a refresh token for a new access token and refresh token
+func (handler *AuthHandler) Refresh(res http.ResponseWriter, req *http.Request, reqCtx *common.RequestContext) (int, interface{}, *todoErr.APIError) {
+	timeoutInSec := time.Duration(handler.App.Config.Application.RequestTimeout) * time.Second
+	timeoutContext, cancel := context.WithTimeout(context.TODO(), timeoutInSec)
+	defer cancel()
+	defer req.Body.Close()
+
+	decoder := json.NewDecoder(req.Body)
+	var refreshReqBody RefreshTokenRequest
+	err := decoder.Decode(&refreshReqBody)
+
+	if err != nil {
+		reqCtx.AddLogMessage("Invalid request body")
+		apiError := todoErr.NewAPIError("", &todoErr.APIErrorBody{
+			Message: "Invalid request body",
+		})
+
+		return http.StatusBadRequest, nil, apiError
+	}
+
+	validationErrors := refreshReqBody.Validate()
+
+	if len(validationErrors) > 0 {
+		reqCtx.AddLogMessage("validation error")
+		apiError := todoErr.NewAPIError("", validationErrors...)
+
+		return http.StatusBadRequest, nil, apiError
+	}
+
+	tokens, err := handler.AuthService.Refresh(timeoutContext, refreshReqBody.RefreshToken)
+
+	if err != nil {
+		return authServiceError(err)
+	}
+
+	responseData := &TokenResponse{
+		Token:            tokens.AccessToken,
+		ExpiresAt:        tokens.AccessExpiresAt,
+		RefreshToken:     tokens.RefreshToken,
+		RefreshExpiresAt: tokens.RefreshExpiresAt,
+	}
+
+	return http.StatusOK, responseData, nil
+}
+
+// Logout will revoke the access token in the request, along with the refresh tokens of its session
+func (handler *AuthHandler) Logout(res http.ResponseWriter, req *http.Request, reqCtx *common.RequestContext) (int, interface{}, *todoErr.APIError) {
+	timeoutInSec := time.Duration(handler.App.Config.Application.RequestTimeout) * time.Second
+	timeoutContext, cancel := context.WithTimeout(context.TODO(), timeoutInSec)
+	defer cancel()
+
+	err := handler.AuthService.Logout(timeoutContext, reqCtx.TokenID, reqCtx.SessionID, reqCtx.TokenExpiresAt)
+
+	if err != nil {
+		return authServiceError(err)
+	}
+
+	return http.StatusOK, nil, nil
+}
+
+// JWKS will publish the public keys which validate access tokens, so that other services can validate them.
+// Keys are served as a plain JSON Web Key Set, as JWT libraries expect, and can be cached for a while.
+func (handler *AuthHandler) JWKS(res http.ResponseWriter, req *http.Request, reqCtx *common.RequestContext) (int, interface{}, *todoErr.APIError) {
+	content, err := json.Marshal(handler.App.Keys.JWKS())
+
+	if err != nil {
+		apiError := todoErr.NewAPIError(err.Error(), &todoErr.APIErrorBody{
+			Message: "Internal server error",
+		})
+
+		return http.StatusInternalServerError, nil, apiError
+	}
+
+	responseData := &common.RawResponse{
+		ContentType: "application/json",
+		Headers: map[string]string{
+			"Cache-Control":  "public, max-age=" + strconv.Itoa(jwksMaxAge),
+			"Content-Length": strconv.Itoa(len(content)),
+		},
+		Body: ioutil.NopCloser(bytes.NewReader(content)),
+	}
+
+	return http.StatusOK, responseData, nil
+}
+
+// authServiceError maps errors returned by the auth service to the API response
+func authServiceError(err error) (int, interface{}, *todoErr.APIError) {
+	switch err.(type) {
+	case *todoErr.UnauthorizedError:
+		unauthorizedErr, _ := err.(*todoErr.UnauthorizedError)
+
+		apiError := todoErr.NewAPIError(unauthorizedErr.Error(), &todoErr.APIErrorBody{
+			Message: "Access denied",
+			Target:  "refreshToken",
+		})
+
+		return http.StatusForbidden, nil, apiError
+	default:
+		apiError := todoErr.NewAPIError(err.Error(), &todoErr.APIErrorBody{
+			Message: "Internal server error",
+		})
+
+		return http.StatusInternalServerError, nil, apiError
+	}
+}
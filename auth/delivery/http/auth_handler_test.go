@@ -0,0 +1,193 @@
+package http_test
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dheerajgopi/todo-api/auth"
+	_authHandler "github.com/dheerajgopi/todo-api/auth/delivery/http"
+	mock "github.com/dheerajgopi/todo-api/auth/mock"
+	"github.com/dheerajgopi/todo-api/auth/signing"
+	"github.com/dheerajgopi/todo-api/common"
+	_errors "github.com/dheerajgopi/todo-api/common/error"
+	"github.com/dheerajgopi/todo-api/config"
+	"github.com/golang/mock/gomock"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/ed25519"
+)
+
+func TestRefreshWithInvalidBody(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+
+	req := httptest.NewRequest("POST", "/token/refresh", strings.NewReader(`{"refreshToken": " "}`))
+
+	status, data, err := handler.Refresh(httptest.NewRecorder(), req, reqCtx)
+
+	assert.Equal(400, status)
+	assert.Nil(data)
+	assert.Equal("Non-empty value is required", err.Body[0].Message)
+	assert.Equal("refreshToken", err.Body[0].Target)
+
+	req = httptest.NewRequest("POST", "/token/refresh", nil)
+
+	status, _, err = handler.Refresh(httptest.NewRecorder(), req, reqCtx)
+
+	assert.Equal(400, status)
+	assert.Equal("Invalid request body", err.Body[0].Message)
+}
+
+func TestRefresh(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+
+	tokens := &auth.TokenPair{
+		AccessToken:      "token",
+		AccessExpiresAt:  time.Now().Add(time.Hour),
+		RefreshToken:     "next",
+		RefreshExpiresAt: time.Now().Add(24 * time.Hour),
+	}
+
+	mockService.EXPECT().Refresh(gomock.Any(), "refresh").Return(tokens, nil)
+
+	req := httptest.NewRequest("POST", "/token/refresh", strings.NewReader(`{"refreshToken": "refresh"}`))
+
+	status, data, err := handler.Refresh(httptest.NewRecorder(), req, reqCtx)
+
+	tokenResponse := data.(*_authHandler.TokenResponse)
+
+	assert.Equal(200, status)
+	assert.Nil(err)
+	assert.Equal("token", tokenResponse.Token)
+	assert.Equal(tokens.AccessExpiresAt, tokenResponse.ExpiresAt)
+	assert.Equal("next", tokenResponse.RefreshToken)
+	assert.Equal(tokens.RefreshExpiresAt, tokenResponse.RefreshExpiresAt)
+}
+
+func TestRefreshWithRevokedToken(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+
+	mockService.EXPECT().Refresh(gomock.Any(), "refresh").Return(nil, &_errors.UnauthorizedError{})
+
+	req := httptest.NewRequest("POST", "/token/refresh", strings.NewReader(`{"refreshToken": "refresh"}`))
+
+	status, data, err := handler.Refresh(httptest.NewRecorder(), req, reqCtx)
+
+	assert.Equal(403, status)
+	assert.Nil(data)
+	assert.Equal("Access denied", err.Body[0].Message)
+	assert.Equal("refreshToken", err.Body[0].Target)
+}
+
+func TestLogout(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+	reqCtx.UserID = 1
+	reqCtx.TokenID = "token"
+	reqCtx.SessionID = "session"
+	reqCtx.TokenExpiresAt = time.Now().Add(time.Hour)
+
+	mockService.EXPECT().Logout(gomock.Any(), "token", "session", reqCtx.TokenExpiresAt).Return(nil)
+
+	req := httptest.NewRequest("POST", "/logout", nil)
+
+	status, data, err := handler.Logout(httptest.NewRecorder(), req, reqCtx)
+
+	assert.Equal(200, status)
+	assert.Nil(data)
+	assert.Nil(err)
+}
+
+func TestLogoutWithServerError(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+
+	mockService.EXPECT().Logout(gomock.Any(), "", "", time.Time{}).Return(errors.New("server error"))
+
+	req := httptest.NewRequest("POST", "/logout", nil)
+
+	status, _, err := handler.Logout(httptest.NewRecorder(), req, reqCtx)
+
+	assert.Equal(500, status)
+	assert.Equal("Internal server error", err.Body[0].Message)
+}
+
+func TestJWKS(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+
+	_, privateKey, _ := ed25519.GenerateKey(rand.Reader)
+	key, _ := signing.NewKey("k1", privateKey)
+	handler.App.Keys, _ = signing.New([]*signing.Key{key}, "")
+
+	req := httptest.NewRequest("GET", "/.well-known/jwks.json", nil)
+
+	status, data, err := handler.JWKS(httptest.NewRecorder(), req, reqCtx)
+
+	rawResponse := data.(*common.RawResponse)
+	content, _ := ioutil.ReadAll(rawResponse.Body)
+
+	var jwks signing.JWKSet
+	json.Unmarshal(content, &jwks)
+
+	assert.Equal(200, status)
+	assert.Nil(err)
+	assert.Equal("application/json", rawResponse.ContentType)
+	assert.Equal("public, max-age=300", rawResponse.Headers["Cache-Control"])
+	assert.Equal(1, len(jwks.Keys))
+	assert.Equal("k1", jwks.Keys[0].KeyID)
+	assert.Equal("EdDSA", jwks.Keys[0].Algorithm)
+}
+
+func setupHandler(mockService auth.Service) *_authHandler.AuthHandler {
+	app := &common.App{
+		Logger: logrus.New(),
+		Config: &config.Config{},
+	}
+
+	app.Config.Application = &config.ApplicationSetting{
+		RequestTimeout: 5,
+	}
+
+	return &_authHandler.AuthHandler{
+		AuthService: mockService,
+		App:         app,
+	}
+}
+
+func setupRequestContext(app *common.App) *common.RequestContext {
+	reqCtx := &common.RequestContext{
+		RequestID: "dummyRequestID",
+		LogEntry: app.Logger.WithFields(
+			logrus.Fields{},
+		),
+	}
+
+	return reqCtx
+}
@@ -0,0 +1,28 @@
+package http
+
+import (
+	"strings"
+
+	todoErr "github.com/dheerajgopi/todo-api/common/error"
+)
+
+// RefreshTokenRequest represents request body for POST /token/refresh API
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refreshToken"`
+}
+
+// Validate validates the request body for POST /token/refresh API
+func (body *RefreshTokenRequest) Validate() []*todoErr.APIErrorBody {
+	validationErrors := make([]*todoErr.APIErrorBody, 0)
+
+	body.RefreshToken = strings.TrimSpace(body.RefreshToken)
+
+	if body.RefreshToken == "" {
+		validationErrors = append(validationErrors, &todoErr.APIErrorBody{
+			Message: "Non-empty value is required",
+			Target:  "refreshToken",
+		})
+	}
+
+	return validationErrors
+}
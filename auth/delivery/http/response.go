@@ -0,0 +1,11 @@
+package http
+
+import "time"
+
+// TokenResponse represents response for POST /token/refresh API
+type TokenResponse struct {
+	Token            string    `json:"token"`
+	ExpiresAt        time.Time `json:"expiresAt"`
+	RefreshToken     string    `json:"refreshToken"`
+	RefreshExpiresAt time.Time `json:"refreshExpiresAt"`
+}
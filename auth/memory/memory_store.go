@@ -0,0 +1,66 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/dheerajgopi/todo-api/auth"
+)
+
+type memoryStore struct {
+	mutex     sync.RWMutex
+	revoked   map[string]time.Time
+	nextSweep time.Time
+}
+
+// sweepInterval is the minimum time between the removals of expired entries
+const sweepInterval = time.Minute
+
+// New returns an in-process store which implements auth.RevocationStore.
+// Revocations are lost on restart and are not shared between instances, so it fits single instance deployments only.
+func New() auth.RevocationStore {
+	return &memoryStore{
+		revoked: make(map[string]time.Time),
+	}
+}
+
+// Revoke adds the id to the store until the given expiry. Expired entries are removed along the way.
+func (store *memoryStore) Revoke(ctx context.Context, id string, expiresAt time.Time) error {
+	now := time.Now()
+
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	if now.After(store.nextSweep) {
+		for revokedID, revokedUntil := range store.revoked {
+			if !revokedUntil.After(now) {
+				delete(store.revoked, revokedID)
+			}
+		}
+
+		store.nextSweep = now.Add(sweepInterval)
+	}
+
+	if existing, ok := store.revoked[id]; !ok || expiresAt.After(existing) {
+		store.revoked[id] = expiresAt
+	}
+
+	return nil
+}
+
+// IsRevoked tells whether any of the ids is revoked and not yet expired
+func (store *memoryStore) IsRevoked(ctx context.Context, ids ...string) (bool, error) {
+	now := time.Now()
+
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+
+	for _, id := range ids {
+		if revokedUntil, ok := store.revoked[id]; ok && revokedUntil.After(now) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
@@ -0,0 +1,48 @@
+package memory_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dheerajgopi/todo-api/auth/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRevoke(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	store := memory.New()
+
+	assert.NoError(store.Revoke(ctx, "token", time.Now().Add(time.Hour)))
+	assert.NoError(store.Revoke(ctx, "expired", time.Now().Add(-time.Second)))
+
+	revoked, err := store.IsRevoked(ctx, "session", "token")
+
+	assert.NoError(err)
+	assert.True(revoked)
+
+	revoked, err = store.IsRevoked(ctx, "session", "expired")
+
+	assert.NoError(err)
+	assert.False(revoked)
+
+	revoked, err = store.IsRevoked(ctx)
+
+	assert.NoError(err)
+	assert.False(revoked)
+}
+
+func TestRevokeKeepsLaterExpiry(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	store := memory.New()
+
+	assert.NoError(store.Revoke(ctx, "session", time.Now().Add(time.Hour)))
+	assert.NoError(store.Revoke(ctx, "session", time.Now().Add(-time.Second)))
+
+	revoked, err := store.IsRevoked(ctx, "session")
+
+	assert.NoError(err)
+	assert.True(revoked)
+}
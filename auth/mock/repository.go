@@ -0,0 +1,94 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/dheerajgopi/todo-api/auth (interfaces: Repository)
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	models "github.com/dheerajgopi/todo-api/models"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+	time "time"
+)
+
+// Repository is a mock of Repository interface
+type Repository struct {
+	ctrl     *gomock.Controller
+	recorder *RepositoryMockRecorder
+}
+
+// RepositoryMockRecorder is the mock recorder for Repository
+type RepositoryMockRecorder struct {
+	mock *Repository
+}
+
+// NewRepository creates a new mock instance
+func NewRepository(ctrl *gomock.Controller) *Repository {
+	mock := &Repository{ctrl: ctrl}
+	mock.recorder = &RepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *Repository) EXPECT() *RepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method
+func (m *Repository) Create(arg0 context.Context, arg1 *models.RefreshToken) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create
+func (mr *RepositoryMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*Repository)(nil).Create), arg0, arg1)
+}
+
+// GetByTokenHash mocks base method
+func (m *Repository) GetByTokenHash(arg0 context.Context, arg1 string) (*models.RefreshToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByTokenHash", arg0, arg1)
+	ret0, _ := ret[0].(*models.RefreshToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByTokenHash indicates an expected call of GetByTokenHash
+func (mr *RepositoryMockRecorder) GetByTokenHash(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByTokenHash", reflect.TypeOf((*Repository)(nil).GetByTokenHash), arg0, arg1)
+}
+
+// RevokeFamily mocks base method
+func (m *Repository) RevokeFamily(arg0 context.Context, arg1 string, arg2 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeFamily", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeFamily indicates an expected call of RevokeFamily
+func (mr *RepositoryMockRecorder) RevokeFamily(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeFamily", reflect.TypeOf((*Repository)(nil).RevokeFamily), arg0, arg1, arg2)
+}
+
+// Rotate mocks base method
+func (m *Repository) Rotate(arg0 context.Context, arg1 int64, arg2 time.Time, arg3 *models.RefreshToken) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Rotate", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Rotate indicates an expected call of Rotate
+func (mr *RepositoryMockRecorder) Rotate(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rotate", reflect.TypeOf((*Repository)(nil).Rotate), arg0, arg1, arg2, arg3)
+}
@@ -0,0 +1,69 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/dheerajgopi/todo-api/auth (interfaces: RevocationStore)
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+	time "time"
+)
+
+// RevocationStore is a mock of RevocationStore interface
+type RevocationStore struct {
+	ctrl     *gomock.Controller
+	recorder *RevocationStoreMockRecorder
+}
+
+// RevocationStoreMockRecorder is the mock recorder for RevocationStore
+type RevocationStoreMockRecorder struct {
+	mock *RevocationStore
+}
+
+// NewRevocationStore creates a new mock instance
+func NewRevocationStore(ctrl *gomock.Controller) *RevocationStore {
+	mock := &RevocationStore{ctrl: ctrl}
+	mock.recorder = &RevocationStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *RevocationStore) EXPECT() *RevocationStoreMockRecorder {
+	return m.recorder
+}
+
+// IsRevoked mocks base method
+func (m *RevocationStore) IsRevoked(arg0 context.Context, arg1 ...string) (bool, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "IsRevoked", varargs...)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsRevoked indicates an expected call of IsRevoked
+func (mr *RevocationStoreMockRecorder) IsRevoked(arg0 interface{}, arg1 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsRevoked", reflect.TypeOf((*RevocationStore)(nil).IsRevoked), varargs...)
+}
+
+// Revoke mocks base method
+func (m *RevocationStore) Revoke(arg0 context.Context, arg1 string, arg2 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revoke", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Revoke indicates an expected call of Revoke
+func (mr *RevocationStoreMockRecorder) Revoke(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revoke", reflect.TypeOf((*RevocationStore)(nil).Revoke), arg0, arg1, arg2)
+}
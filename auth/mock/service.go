@@ -0,0 +1,139 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/dheerajgopi/todo-api/auth (interfaces: Service)
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	auth "github.com/dheerajgopi/todo-api/auth"
+	models "github.com/dheerajgopi/todo-api/models"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+	time "time"
+)
+
+// Service is a mock of Service interface
+type Service struct {
+	ctrl     *gomock.Controller
+	recorder *ServiceMockRecorder
+}
+
+// ServiceMockRecorder is the mock recorder for Service
+type ServiceMockRecorder struct {
+	mock *Service
+}
+
+// NewService creates a new mock instance
+func NewService(ctrl *gomock.Controller) *Service {
+	mock := &Service{ctrl: ctrl}
+	mock.recorder = &ServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *Service) EXPECT() *ServiceMockRecorder {
+	return m.recorder
+}
+
+// Issue mocks base method
+func (m *Service) Issue(arg0 context.Context, arg1 *models.User) (*auth.TokenPair, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Issue", arg0, arg1)
+	ret0, _ := ret[0].(*auth.TokenPair)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Issue indicates an expected call of Issue
+func (mr *ServiceMockRecorder) Issue(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Issue", reflect.TypeOf((*Service)(nil).Issue), arg0, arg1)
+}
+
+// IssueChallenge mocks base method
+func (m *Service) IssueChallenge(arg0 context.Context, arg1 *models.User) (*auth.Challenge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IssueChallenge", arg0, arg1)
+	ret0, _ := ret[0].(*auth.Challenge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IssueChallenge indicates an expected call of IssueChallenge
+func (mr *ServiceMockRecorder) IssueChallenge(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IssueChallenge", reflect.TypeOf((*Service)(nil).IssueChallenge), arg0, arg1)
+}
+
+// Logout mocks base method
+func (m *Service) Logout(arg0 context.Context, arg1, arg2 string, arg3 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Logout", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Logout indicates an expected call of Logout
+func (mr *ServiceMockRecorder) Logout(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Logout", reflect.TypeOf((*Service)(nil).Logout), arg0, arg1, arg2, arg3)
+}
+
+// ParseChallenge mocks base method
+func (m *Service) ParseChallenge(arg0 context.Context, arg1 string) (*auth.Challenge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ParseChallenge", arg0, arg1)
+	ret0, _ := ret[0].(*auth.Challenge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ParseChallenge indicates an expected call of ParseChallenge
+func (mr *ServiceMockRecorder) ParseChallenge(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ParseChallenge", reflect.TypeOf((*Service)(nil).ParseChallenge), arg0, arg1)
+}
+
+// RedeemChallenge mocks base method
+func (m *Service) RedeemChallenge(arg0 context.Context, arg1 *auth.Challenge) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RedeemChallenge", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RedeemChallenge indicates an expected call of RedeemChallenge
+func (mr *ServiceMockRecorder) RedeemChallenge(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RedeemChallenge", reflect.TypeOf((*Service)(nil).RedeemChallenge), arg0, arg1)
+}
+
+// Refresh mocks base method
+func (m *Service) Refresh(arg0 context.Context, arg1 string) (*auth.TokenPair, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Refresh", arg0, arg1)
+	ret0, _ := ret[0].(*auth.TokenPair)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Refresh indicates an expected call of Refresh
+func (mr *ServiceMockRecorder) Refresh(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Refresh", reflect.TypeOf((*Service)(nil).Refresh), arg0, arg1)
+}
+
+// RevokeUser mocks base method
+func (m *Service) RevokeUser(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeUser", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeUser indicates an expected call of RevokeUser
+func (mr *ServiceMockRecorder) RevokeUser(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeUser", reflect.TypeOf((*Service)(nil).RevokeUser), arg0, arg1)
+}
@@ -0,0 +1,80 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/dheerajgopi/todo-api/auth"
+)
+
+type mySQLStore struct {
+	DB *sql.DB
+}
+
+// New will return new object which implements auth.RevocationStore using revoked_token table
+func New(db *sql.DB) auth.RevocationStore {
+	return &mySQLStore{
+		DB: db,
+	}
+}
+
+// Revoke will store the id until the given expiry. Expired entries are removed along the way.
+func (store *mySQLStore) Revoke(ctx context.Context, id string, expiresAt time.Time) error {
+	query := `INSERT INTO revoked_token (id, expires_at) VALUES (?, ?)
+		ON DUPLICATE KEY UPDATE expires_at=GREATEST(expires_at, VALUES(expires_at))`
+
+	tx, err := store.DB.BeginTx(ctx, nil)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`DELETE FROM revoked_token WHERE expires_at<?`, time.Now())
+
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	_, err = tx.Exec(query, id, expiresAt)
+
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// IsRevoked tells whether any of the ids is revoked and not yet expired
+func (store *mySQLStore) IsRevoked(ctx context.Context, ids ...string) (bool, error) {
+	if len(ids) == 0 {
+		return false, nil
+	}
+
+	query := `SELECT COUNT(*) FROM revoked_token WHERE id IN (?` + strings.Repeat(", ?", len(ids)-1) + `) AND expires_at>?`
+
+	stmt, err := store.DB.PrepareContext(ctx, query)
+
+	if err != nil {
+		return false, err
+	}
+
+	args := make([]interface{}, 0, len(ids)+1)
+
+	for _, id := range ids {
+		args = append(args, id)
+	}
+
+	args = append(args, time.Now())
+
+	var count int
+
+	if err = stmt.QueryRowContext(ctx, args...).Scan(&count); err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
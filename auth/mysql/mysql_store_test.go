@@ -0,0 +1,79 @@
+package mysql_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/dheerajgopi/todo-api/auth/mysql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRevoke(t *testing.T) {
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	expiresAt := time.Now().Add(time.Hour)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM revoked_token WHERE expires_at<\\?").
+		WithArgs(sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec("INSERT INTO revoked_token \\(id, expires_at\\) VALUES \\(\\?, \\?\\)\\s+"+
+		"ON DUPLICATE KEY UPDATE expires_at=GREATEST\\(expires_at, VALUES\\(expires_at\\)\\)").
+		WithArgs("token", expiresAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	store := mysql.New(db)
+
+	assert.NoError(t, store.Revoke(context.TODO(), "token", expiresAt))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIsRevoked(t *testing.T) {
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{"count"}).AddRow(1)
+
+	prep := mock.ExpectPrepare("SELECT COUNT\\(\\*\\) FROM revoked_token WHERE id IN \\(\\?, \\?\\) AND expires_at>\\?")
+	prep.ExpectQuery().WithArgs("token", "session", sqlmock.AnyArg()).WillReturnRows(rows)
+
+	store := mysql.New(db)
+
+	revoked, err := store.IsRevoked(context.TODO(), "token", "session")
+
+	assert.NoError(t, err)
+	assert.True(t, revoked)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIsRevokedWithoutIDs(t *testing.T) {
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	store := mysql.New(db)
+
+	revoked, err := store.IsRevoked(context.TODO())
+
+	assert.NoError(t, err)
+	assert.False(t, revoked)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
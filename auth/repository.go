@@ -0,0 +1,22 @@
+package auth
+
+import (
+	"context"
+	"time"
+
+	"github.com/dheerajgopi/todo-api/models"
+)
+
+// Repository represents refresh token's repository contract
+type Repository interface {
+	Create(ctx context.Context, token *models.RefreshToken) error
+	GetByTokenHash(ctx context.Context, tokenHash string) (*models.RefreshToken, error)
+	Rotate(ctx context.Context, id int64, usedAt time.Time, next *models.RefreshToken) (bool, error)
+	RevokeFamily(ctx context.Context, familyID string, revokedAt time.Time) error
+}
+
+// RevocationStore keeps the ids of revoked access tokens and sessions until the access tokens expire
+type RevocationStore interface {
+	Revoke(ctx context.Context, id string, expiresAt time.Time) error
+	IsRevoked(ctx context.Context, ids ...string) (bool, error)
+}
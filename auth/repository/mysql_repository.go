@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/dheerajgopi/todo-api/auth"
+	"github.com/dheerajgopi/todo-api/models"
+)
+
+type mySQLAuthRepo struct {
+	DB *sql.DB
+}
+
+// New will return new object which implements auth.Repository
+func New(db *sql.DB) auth.Repository {
+	return &mySQLAuthRepo{
+		DB: db,
+	}
+}
+
+// Create will store new refresh token. Expired refresh tokens of the user are removed along the way.
+func (repo *mySQLAuthRepo) Create(ctx context.Context, token *models.RefreshToken) error {
+	tx, err := repo.DB.BeginTx(ctx, nil)
+
+	if err != nil {
+		return err
+	}
+
+	if err = insertToken(tx, token); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// insertToken removes the expired refresh tokens of the user and stores the new one
+func insertToken(tx *sql.Tx, token *models.RefreshToken) error {
+	_, err := tx.Exec(`DELETE FROM refresh_token WHERE user_id=? AND expires_at<?`, token.User.ID, token.CreatedAt)
+
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO refresh_token (user_id, token_hash, family_id, expires_at, created_at) VALUES (?, ?, ?, ?, ?)`
+
+	res, err := tx.Exec(query, token.User.ID, token.TokenHash, token.FamilyID, token.ExpiresAt, token.CreatedAt)
+
+	if err != nil {
+		return err
+	}
+
+	lastID, err := res.LastInsertId()
+
+	if err != nil {
+		return err
+	}
+
+	token.ID = lastID
+
+	return nil
+}
+
+// GetByTokenHash will return the refresh token with the given hash, along with its user, unless the user is deleted or deactivated
+func (repo *mySQLAuthRepo) GetByTokenHash(ctx context.Context, tokenHash string) (*models.RefreshToken, error) {
+	query := `SELECT r.id, r.user_id, u.name, u.email, u.is_active, r.token_hash, r.family_id, r.expires_at, r.used_at, r.revoked_at, r.created_at
+		FROM refresh_token r JOIN user u ON u.id=r.user_id WHERE r.token_hash=?
+		AND u.deleted_at IS NULL AND u.deactivated_at IS NULL`
+
+	stmt, err := repo.DB.PrepareContext(ctx, query)
+
+	if err != nil {
+		return nil, err
+	}
+
+	token := &models.RefreshToken{
+		User: &models.User{},
+	}
+
+	err = stmt.QueryRowContext(ctx, tokenHash).Scan(
+		&token.ID,
+		&token.User.ID,
+		&token.User.Name,
+		&token.User.Email,
+		&token.User.IsActive,
+		&token.TokenHash,
+		&token.FamilyID,
+		&token.ExpiresAt,
+		&token.UsedAt,
+		&token.RevokedAt,
+		&token.CreatedAt,
+	)
+
+	switch err {
+	case nil:
+	case sql.ErrNoRows:
+		return nil, nil
+	default:
+		return nil, err
+	}
+
+	return token, nil
+}
+
+// Rotate will mark the refresh token as used and store the next token of its family.
+// False is returned, without storing the next token, if the token was already used or revoked.
+func (repo *mySQLAuthRepo) Rotate(ctx context.Context, id int64, usedAt time.Time, next *models.RefreshToken) (bool, error) {
+	tx, err := repo.DB.BeginTx(ctx, nil)
+
+	if err != nil {
+		return false, err
+	}
+
+	result, err := tx.Exec(`UPDATE refresh_token SET used_at=? WHERE id=? AND used_at IS NULL AND revoked_at IS NULL`, usedAt, id)
+
+	if err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	if rotated, err := result.RowsAffected(); err != nil || rotated == 0 {
+		tx.Rollback()
+		return false, err
+	}
+
+	if err = insertToken(tx, next); err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// RevokeFamily will revoke all the refresh tokens of a family
+func (repo *mySQLAuthRepo) RevokeFamily(ctx context.Context, familyID string, revokedAt time.Time) error {
+	tx, err := repo.DB.BeginTx(ctx, nil)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`UPDATE refresh_token SET revoked_at=? WHERE family_id=? AND revoked_at IS NULL`, revokedAt, familyID)
+
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,191 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/dheerajgopi/todo-api/auth/repository"
+	"github.com/dheerajgopi/todo-api/models"
+	"github.com/stretchr/testify/assert"
+)
+
+var tokenColumns = []string{
+	"id", "user_id", "name", "email", "is_active", "token_hash", "family_id", "expires_at", "used_at", "revoked_at", "created_at",
+}
+
+const insertTokenQuery = "INSERT INTO refresh_token \\(user_id, token_hash, family_id, expires_at, created_at\\) " +
+	"VALUES \\(\\?, \\?, \\?, \\?, \\?\\)"
+
+func newToken(now time.Time) *models.RefreshToken {
+	return &models.RefreshToken{
+		User:      &models.User{ID: 1},
+		TokenHash: "abc",
+		FamilyID:  "family",
+		ExpiresAt: now.Add(time.Hour),
+		CreatedAt: now,
+	}
+}
+
+func TestCreate(t *testing.T) {
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	now := time.Now()
+	token := newToken(now)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM refresh_token WHERE user_id=\\? AND expires_at<\\?").
+		WithArgs(int64(1), now).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(insertTokenQuery).
+		WithArgs(int64(1), "abc", "family", token.ExpiresAt, now).
+		WillReturnResult(sqlmock.NewResult(5, 1))
+	mock.ExpectCommit()
+
+	repo := repository.New(db)
+
+	assert.NoError(t, repo.Create(context.TODO(), token))
+	assert.Equal(t, int64(5), token.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetByTokenHash(t *testing.T) {
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	now := time.Now()
+	rows := sqlmock.NewRows(tokenColumns).AddRow(5, 1, "user", "user@mail.com", true, "abc", "family", now, now, nil, now)
+
+	prep := mock.ExpectPrepare("SELECT r.id, r.user_id, u.name, u.email, u.is_active, r.token_hash, r.family_id, r.expires_at, r.used_at, " +
+		"r.revoked_at, r.created_at\\s+FROM refresh_token r JOIN user u ON u.id=r.user_id " +
+		"WHERE r.token_hash=\\?\\s+AND u.deleted_at IS NULL AND u.deactivated_at IS NULL")
+	prep.ExpectQuery().WithArgs("abc").WillReturnRows(rows)
+
+	repo := repository.New(db)
+
+	token, err := repo.GetByTokenHash(context.TODO(), "abc")
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), token.ID)
+	assert.Equal(t, int64(1), token.User.ID)
+	assert.Equal(t, "user@mail.com", token.User.Email)
+	assert.True(t, token.User.IsActive)
+	assert.Equal(t, "family", token.FamilyID)
+	assert.NotNil(t, token.UsedAt)
+	assert.Nil(t, token.RevokedAt)
+}
+
+func TestGetByTokenHashWithNoRows(t *testing.T) {
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	prep := mock.ExpectPrepare("SELECT r.id, r.user_id")
+	prep.ExpectQuery().WithArgs("abc").WillReturnRows(sqlmock.NewRows(tokenColumns))
+
+	repo := repository.New(db)
+
+	token, err := repo.GetByTokenHash(context.TODO(), "abc")
+
+	assert.NoError(t, err)
+	assert.Nil(t, token)
+}
+
+func TestRotate(t *testing.T) {
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	now := time.Now()
+	next := newToken(now)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE refresh_token SET used_at=\\? WHERE id=\\? AND used_at IS NULL AND revoked_at IS NULL").
+		WithArgs(now, int64(4)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM refresh_token WHERE user_id=\\? AND expires_at<\\?").
+		WithArgs(int64(1), now).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(insertTokenQuery).
+		WithArgs(int64(1), "abc", "family", next.ExpiresAt, now).
+		WillReturnResult(sqlmock.NewResult(5, 1))
+	mock.ExpectCommit()
+
+	repo := repository.New(db)
+
+	rotated, err := repo.Rotate(context.TODO(), 4, now, next)
+
+	assert.NoError(t, err)
+	assert.True(t, rotated)
+	assert.Equal(t, int64(5), next.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRotateUsedToken(t *testing.T) {
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	now := time.Now()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE refresh_token SET used_at=\\?").
+		WithArgs(now, int64(4)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	repo := repository.New(db)
+
+	rotated, err := repo.Rotate(context.TODO(), 4, now, newToken(now))
+
+	assert.NoError(t, err)
+	assert.False(t, rotated)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRevokeFamily(t *testing.T) {
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	now := time.Now()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE refresh_token SET revoked_at=\\? WHERE family_id=\\? AND revoked_at IS NULL").
+		WithArgs(now, "family").
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectCommit()
+
+	repo := repository.New(db)
+
+	assert.NoError(t, repo.RevokeFamily(context.TODO(), "family", now))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
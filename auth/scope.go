@@ -0,0 +1,10 @@
+package auth
+
+// Scopes which can be granted to API keys. Access tokens grant every scope.
+const (
+	ScopeTasksRead  = "tasks:read"
+	ScopeTasksWrite = "tasks:write"
+)
+
+// Scopes lists every scope, in the order they are shown
+var Scopes = []string{ScopeTasksRead, ScopeTasksWrite}
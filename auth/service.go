@@ -0,0 +1,20 @@
+package auth
+
+import (
+	"context"
+	"time"
+
+	"github.com/dheerajgopi/todo-api/models"
+)
+
+// Service represents auth token's service contract.
+// Each MFA challenge can be redeemed once.
+type Service interface {
+	Issue(ctx context.Context, user *models.User) (*TokenPair, error)
+	Refresh(ctx context.Context, refreshToken string) (*TokenPair, error)
+	Logout(ctx context.Context, tokenID string, sessionID string, expiresAt time.Time) error
+	IssueChallenge(ctx context.Context, user *models.User) (*Challenge, error)
+	ParseChallenge(ctx context.Context, token string) (*Challenge, error)
+	RedeemChallenge(ctx context.Context, challenge *Challenge) error
+	RevokeUser(ctx context.Context, userID int64) error
+}
@@ -0,0 +1,245 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/dheerajgopi/todo-api/auth"
+	"github.com/dheerajgopi/todo-api/auth/signing"
+	todoErr "github.com/dheerajgopi/todo-api/common/error"
+	"github.com/dheerajgopi/todo-api/models"
+	"github.com/google/uuid"
+)
+
+// refreshTokenSize is the number of random bytes in a refresh token
+const refreshTokenSize = 32
+
+type authService struct {
+	authRepo        auth.Repository
+	revocations     auth.RevocationStore
+	keys            *signing.KeySet
+	accessExpiry    time.Duration
+	refreshExpiry   time.Duration
+	challengeExpiry time.Duration
+}
+
+// New returns a new object implementing auth.Service interface.
+// Access tokens and MFA challenges are signed with the signing key of the key set,
+// and all kinds of tokens expire after the given durations.
+func New(authRepo auth.Repository, revocations auth.RevocationStore, keys *signing.KeySet, accessExpiry time.Duration, refreshExpiry time.Duration, challengeExpiry time.Duration) auth.Service {
+	return &authService{
+		authRepo:        authRepo,
+		revocations:     revocations,
+		keys:            keys,
+		accessExpiry:    accessExpiry,
+		refreshExpiry:   refreshExpiry,
+		challengeExpiry: challengeExpiry,
+	}
+}
+
+// Issue starts a new session for an user, who is already authenticated
+func (service *authService) Issue(ctx context.Context, user *models.User) (*auth.TokenPair, error) {
+	return service.issue(ctx, user, uuid.New().String(), nil)
+}
+
+// Refresh exchanges a refresh token for a new pair of tokens of the same session.
+// Each refresh token works once. If an used token is presented again, it is taken as stolen,
+// and the whole session is revoked, so that neither the thief nor the user can continue with it.
+func (service *authService) Refresh(ctx context.Context, refreshToken string) (*auth.TokenPair, error) {
+	existingToken, err := service.authRepo.GetByTokenHash(ctx, hashToken(refreshToken))
+
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+
+	if existingToken == nil || existingToken.RevokedAt != nil || !existingToken.ExpiresAt.After(now) {
+		return nil, &todoErr.UnauthorizedError{}
+	}
+
+	if existingToken.UsedAt != nil {
+		if err = service.revokeSession(ctx, existingToken.FamilyID, now); err != nil {
+			return nil, err
+		}
+
+		return nil, &todoErr.UnauthorizedError{}
+	}
+
+	return service.issue(ctx, existingToken.User, existingToken.FamilyID, existingToken)
+}
+
+// Logout revokes the access token and the session it belongs to
+func (service *authService) Logout(ctx context.Context, tokenID string, sessionID string, expiresAt time.Time) error {
+	if err := service.revocations.Revoke(ctx, tokenID, expiresAt); err != nil {
+		return err
+	}
+
+	return service.revokeSession(ctx, sessionID, time.Now())
+}
+
+// IssueChallenge signs a challenge for an user who passed the password step of a login
+func (service *authService) IssueChallenge(ctx context.Context, user *models.User) (*auth.Challenge, error) {
+	now := time.Now()
+	challenge := &auth.Challenge{
+		ID:        uuid.New().String(),
+		UserID:    user.ID,
+		ExpiresAt: now.Add(service.challengeExpiry),
+	}
+
+	signedToken, err := service.keys.Sign(jwt.MapClaims{
+		"iss":             "todo-api",
+		"sub":             "user",
+		"jti":             challenge.ID,
+		auth.ClaimUserID:  user.ID,
+		auth.ClaimPurpose: auth.PurposeMfaChallenge,
+		"iat":             now.Unix(),
+		"exp":             challenge.ExpiresAt.Unix(),
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	challenge.Token = signedToken
+
+	return challenge, nil
+}
+
+// ParseChallenge validates a challenge token. UnauthorizedError is returned if the token is invalid,
+// expired, redeemed, or is not a challenge.
+func (service *authService) ParseChallenge(ctx context.Context, token string) (*auth.Challenge, error) {
+	parsedToken, err := service.keys.Parse(token)
+
+	if err != nil || !parsedToken.Valid {
+		return nil, &todoErr.UnauthorizedError{}
+	}
+
+	claims, _ := parsedToken.Claims.(jwt.MapClaims)
+	userID, hasUser := claims[auth.ClaimUserID].(float64)
+	tokenID, _ := claims["jti"].(string)
+	purpose, _ := claims[auth.ClaimPurpose].(string)
+	expiresAt, hasExpiry := claims["exp"].(float64)
+
+	if !hasUser || !hasExpiry || tokenID == "" || purpose != auth.PurposeMfaChallenge {
+		return nil, &todoErr.UnauthorizedError{}
+	}
+
+	redeemed, err := service.revocations.IsRevoked(ctx, tokenID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if redeemed {
+		return nil, &todoErr.UnauthorizedError{}
+	}
+
+	return &auth.Challenge{
+		ID:        tokenID,
+		UserID:    int64(userID),
+		Token:     token,
+		ExpiresAt: time.Unix(int64(expiresAt), 0),
+	}, nil
+}
+
+// RedeemChallenge revokes a challenge, so that it can not be exchanged again
+func (service *authService) RedeemChallenge(ctx context.Context, challenge *auth.Challenge) error {
+	return service.revocations.Revoke(ctx, challenge.ID, challenge.ExpiresAt)
+}
+
+// RevokeUser revokes all the access tokens issued to an user so far, like when the user is deleted or deactivated.
+// Refresh tokens of the user are revoked along with the deletion or deactivation.
+func (service *authService) RevokeUser(ctx context.Context, userID int64) error {
+	return service.revocations.Revoke(ctx, auth.UserRevocationID(userID), time.Now().Add(service.accessExpiry))
+}
+
+// revokeSession revokes the refresh tokens of a session, along with the access tokens issued to it.
+// Access tokens issued to the session expire within the access token expiry, so the session id is kept in
+// the revocation store only that long.
+func (service *authService) revokeSession(ctx context.Context, sessionID string, now time.Time) error {
+	if err := service.authRepo.RevokeFamily(ctx, sessionID, now); err != nil {
+		return err
+	}
+
+	return service.revocations.Revoke(ctx, sessionID, now.Add(service.accessExpiry))
+}
+
+// issue signs a new access token and stores a new refresh token for the session.
+// If the session is refreshed, the previous refresh token is rotated out in the same step.
+func (service *authService) issue(ctx context.Context, user *models.User, sessionID string, previous *models.RefreshToken) (*auth.TokenPair, error) {
+	now := time.Now()
+	accessExpiresAt := now.Add(service.accessExpiry)
+
+	signedToken, err := service.keys.Sign(jwt.MapClaims{
+		"iss":               "todo-api",
+		"sub":               "user",
+		"jti":               uuid.New().String(),
+		auth.ClaimUserID:    user.ID,
+		auth.ClaimSessionID: sessionID,
+		auth.ClaimVerified:  user.IsActive,
+		"name":              user.Name,
+		"email":             user.Email,
+		"iat":               now.Unix(),
+		"exp":               accessExpiresAt.Unix(),
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	tokenBytes := make([]byte, refreshTokenSize)
+
+	if _, err = rand.Read(tokenBytes); err != nil {
+		return nil, err
+	}
+
+	refreshToken := base64.RawURLEncoding.EncodeToString(tokenBytes)
+
+	newToken := &models.RefreshToken{
+		User: &models.User{
+			ID: user.ID,
+		},
+		TokenHash: hashToken(refreshToken),
+		FamilyID:  sessionID,
+		ExpiresAt: now.Add(service.refreshExpiry),
+		CreatedAt: now,
+	}
+
+	if previous == nil {
+		err = service.authRepo.Create(ctx, newToken)
+	} else {
+		var rotated bool
+
+		rotated, err = service.authRepo.Rotate(ctx, previous.ID, now, newToken)
+
+		if err == nil && !rotated {
+			// the token was used or revoked by a concurrent request since it was read
+			if err = service.revokeSession(ctx, sessionID, now); err == nil {
+				err = &todoErr.UnauthorizedError{}
+			}
+		}
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &auth.TokenPair{
+		AccessToken:      signedToken,
+		AccessExpiresAt:  accessExpiresAt,
+		RefreshToken:     refreshToken,
+		RefreshExpiresAt: newToken.ExpiresAt,
+	}, nil
+}
+
+// hashToken returns the hex encoded SHA-256 hash of a refresh token
+func hashToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
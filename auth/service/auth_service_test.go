@@ -0,0 +1,339 @@
+package service_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	authMock "github.com/dheerajgopi/todo-api/auth/mock"
+	"github.com/dheerajgopi/todo-api/auth/service"
+	"github.com/dheerajgopi/todo-api/auth/signing"
+	todoErr "github.com/dheerajgopi/todo-api/common/error"
+	"github.com/dheerajgopi/todo-api/models"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+const secret = "secret"
+
+func hash(value string) string {
+	sum := sha256.Sum256([]byte(value))
+
+	return hex.EncodeToString(sum[:])
+}
+
+func parseClaims(t *testing.T, token string) jwt.MapClaims {
+	parsedToken, err := jwt.Parse(token, func(token *jwt.Token) (interface{}, error) {
+		return []byte(secret), nil
+	})
+
+	if err != nil {
+		t.Fatalf("Unexpected error while parsing token: %s", err)
+	}
+
+	claims, _ := parsedToken.Claims.(jwt.MapClaims)
+
+	return claims
+}
+
+func TestIssue(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockRepo := authMock.NewRepository(mockCtrl)
+	mockStore := authMock.NewRevocationStore(mockCtrl)
+	authService := service.New(mockRepo, mockStore, signing.NewSecret(secret), 15*time.Minute, 24*time.Hour, 5*time.Minute)
+
+	user := &models.User{
+		ID:       1,
+		Name:     "user",
+		Email:    "user@mail.com",
+		IsActive: true,
+	}
+
+	var storedToken *models.RefreshToken
+
+	mockRepo.
+		EXPECT().
+		Create(ctx, gomock.Any()).
+		DoAndReturn(func(ctx context.Context, token *models.RefreshToken) error {
+			storedToken = token
+			return nil
+		})
+
+	tokens, err := authService.Issue(ctx, user)
+
+	assert.NoError(err)
+
+	claims := parseClaims(t, tokens.AccessToken)
+
+	assert.NoError(claims.Valid())
+	assert.Equal(float64(1), claims["userId"])
+	assert.Equal(true, claims["verified"])
+	assert.Equal(float64(tokens.AccessExpiresAt.Unix()), claims["exp"])
+	assert.NotEmpty(claims["jti"])
+	assert.WithinDuration(time.Now().Add(15*time.Minute), tokens.AccessExpiresAt, time.Minute)
+
+	assert.Equal(43, len(tokens.RefreshToken))
+	assert.Equal(hash(tokens.RefreshToken), storedToken.TokenHash)
+	assert.Equal(claims["sid"], storedToken.FamilyID)
+	assert.Equal(int64(1), storedToken.User.ID)
+	assert.Equal(tokens.RefreshExpiresAt, storedToken.ExpiresAt)
+	assert.WithinDuration(time.Now().Add(24*time.Hour), storedToken.ExpiresAt, time.Minute)
+}
+
+func TestRefresh(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockRepo := authMock.NewRepository(mockCtrl)
+	mockStore := authMock.NewRevocationStore(mockCtrl)
+	authService := service.New(mockRepo, mockStore, signing.NewSecret(secret), 15*time.Minute, 24*time.Hour, 5*time.Minute)
+
+	existingToken := &models.RefreshToken{
+		ID:        4,
+		User:      &models.User{ID: 1, Name: "user", Email: "user@mail.com"},
+		TokenHash: hash("refresh"),
+		FamilyID:  "family",
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+
+	var nextToken *models.RefreshToken
+
+	gomock.InOrder(
+		mockRepo.EXPECT().GetByTokenHash(ctx, hash("refresh")).Return(existingToken, nil),
+		mockRepo.
+			EXPECT().
+			Rotate(ctx, int64(4), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, id int64, usedAt time.Time, next *models.RefreshToken) (bool, error) {
+				nextToken = next
+				return true, nil
+			}),
+	)
+
+	tokens, err := authService.Refresh(ctx, "refresh")
+
+	assert.NoError(err)
+	assert.Equal("family", nextToken.FamilyID)
+	assert.Equal(hash(tokens.RefreshToken), nextToken.TokenHash)
+	assert.Equal("family", parseClaims(t, tokens.AccessToken)["sid"])
+}
+
+func TestRefreshWithUnknownToken(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockRepo := authMock.NewRepository(mockCtrl)
+	mockStore := authMock.NewRevocationStore(mockCtrl)
+	authService := service.New(mockRepo, mockStore, signing.NewSecret(secret), 15*time.Minute, 24*time.Hour, 5*time.Minute)
+
+	expiredToken := &models.RefreshToken{
+		ID:        4,
+		User:      &models.User{ID: 1},
+		FamilyID:  "family",
+		ExpiresAt: time.Now().Add(-time.Second),
+	}
+
+	mockRepo.EXPECT().GetByTokenHash(ctx, hash("unknown")).Return(nil, nil)
+	mockRepo.EXPECT().GetByTokenHash(ctx, hash("expired")).Return(expiredToken, nil)
+
+	tokens, err := authService.Refresh(ctx, "unknown")
+
+	assert.Nil(tokens)
+	assert.Equal(&todoErr.UnauthorizedError{}, err)
+
+	tokens, err = authService.Refresh(ctx, "expired")
+
+	assert.Nil(tokens)
+	assert.Equal(&todoErr.UnauthorizedError{}, err)
+}
+
+func TestRefreshWithReusedToken(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockRepo := authMock.NewRepository(mockCtrl)
+	mockStore := authMock.NewRevocationStore(mockCtrl)
+	authService := service.New(mockRepo, mockStore, signing.NewSecret(secret), 15*time.Minute, 24*time.Hour, 5*time.Minute)
+
+	usedAt := time.Now().Add(-time.Minute)
+	usedToken := &models.RefreshToken{
+		ID:        4,
+		User:      &models.User{ID: 1},
+		FamilyID:  "family",
+		ExpiresAt: time.Now().Add(time.Hour),
+		UsedAt:    &usedAt,
+	}
+
+	gomock.InOrder(
+		mockRepo.EXPECT().GetByTokenHash(ctx, hash("refresh")).Return(usedToken, nil),
+		mockRepo.EXPECT().RevokeFamily(ctx, "family", gomock.Any()).Return(nil),
+		mockStore.
+			EXPECT().
+			Revoke(ctx, "family", gomock.Any()).
+			DoAndReturn(func(ctx context.Context, id string, expiresAt time.Time) error {
+				assert.WithinDuration(time.Now().Add(15*time.Minute), expiresAt, time.Minute)
+				return nil
+			}),
+	)
+
+	tokens, err := authService.Refresh(ctx, "refresh")
+
+	assert.Nil(tokens)
+	assert.Equal(&todoErr.UnauthorizedError{}, err)
+}
+
+func TestRefreshWithConcurrentlyUsedToken(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockRepo := authMock.NewRepository(mockCtrl)
+	mockStore := authMock.NewRevocationStore(mockCtrl)
+	authService := service.New(mockRepo, mockStore, signing.NewSecret(secret), 15*time.Minute, 24*time.Hour, 5*time.Minute)
+
+	existingToken := &models.RefreshToken{
+		ID:        4,
+		User:      &models.User{ID: 1},
+		FamilyID:  "family",
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+
+	gomock.InOrder(
+		mockRepo.EXPECT().GetByTokenHash(ctx, hash("refresh")).Return(existingToken, nil),
+		mockRepo.EXPECT().Rotate(ctx, int64(4), gomock.Any(), gomock.Any()).Return(false, nil),
+		mockRepo.EXPECT().RevokeFamily(ctx, "family", gomock.Any()).Return(nil),
+		mockStore.EXPECT().Revoke(ctx, "family", gomock.Any()).Return(nil),
+	)
+
+	tokens, err := authService.Refresh(ctx, "refresh")
+
+	assert.Nil(tokens)
+	assert.Equal(&todoErr.UnauthorizedError{}, err)
+}
+
+func TestLogout(t *testing.T) {
+	ctx := context.TODO()
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockRepo := authMock.NewRepository(mockCtrl)
+	mockStore := authMock.NewRevocationStore(mockCtrl)
+	authService := service.New(mockRepo, mockStore, signing.NewSecret(secret), 15*time.Minute, 24*time.Hour, 5*time.Minute)
+
+	expiresAt := time.Now().Add(10 * time.Minute)
+
+	gomock.InOrder(
+		mockStore.EXPECT().Revoke(ctx, "token", expiresAt).Return(nil),
+		mockRepo.EXPECT().RevokeFamily(ctx, "family", gomock.Any()).Return(nil),
+		mockStore.EXPECT().Revoke(ctx, "family", gomock.Any()).Return(nil),
+	)
+
+	assert.NoError(t, authService.Logout(ctx, "token", "family", expiresAt))
+}
+
+func TestRevokeUser(t *testing.T) {
+	ctx := context.TODO()
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockRepo := authMock.NewRepository(mockCtrl)
+	mockStore := authMock.NewRevocationStore(mockCtrl)
+	authService := service.New(mockRepo, mockStore, signing.NewSecret(secret), 15*time.Minute, 24*time.Hour, 5*time.Minute)
+
+	// the user id is revoked for as long as the access tokens issued so far are valid
+	mockStore.EXPECT().Revoke(ctx, "user:1", gomock.Any()).
+		DoAndReturn(func(ctx context.Context, id string, expiresAt time.Time) error {
+			assert.WithinDuration(t, time.Now().Add(15*time.Minute), expiresAt, time.Second)
+			return nil
+		})
+
+	assert.NoError(t, authService.RevokeUser(ctx, 1))
+}
+
+func TestIssueAndParseChallenge(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockRepo := authMock.NewRepository(mockCtrl)
+	mockStore := authMock.NewRevocationStore(mockCtrl)
+	authService := service.New(mockRepo, mockStore, signing.NewSecret(secret), 15*time.Minute, 24*time.Hour, 5*time.Minute)
+
+	challenge, err := authService.IssueChallenge(ctx, &models.User{ID: 1})
+
+	assert.NoError(err)
+	assert.Empty(parseClaims(t, challenge.Token)["sid"])
+	assert.WithinDuration(time.Now().Add(5*time.Minute), challenge.ExpiresAt, time.Minute)
+
+	mockStore.EXPECT().IsRevoked(ctx, challenge.ID).Return(false, nil)
+
+	parsedChallenge, err := authService.ParseChallenge(ctx, challenge.Token)
+
+	assert.NoError(err)
+	assert.Equal(challenge.ID, parsedChallenge.ID)
+	assert.Equal(int64(1), parsedChallenge.UserID)
+	assert.Equal(challenge.ExpiresAt.Unix(), parsedChallenge.ExpiresAt.Unix())
+}
+
+func TestParseChallengeWithAccessToken(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockRepo := authMock.NewRepository(mockCtrl)
+	mockStore := authMock.NewRevocationStore(mockCtrl)
+	authService := service.New(mockRepo, mockStore, signing.NewSecret(secret), 15*time.Minute, 24*time.Hour, 5*time.Minute)
+
+	mockRepo.EXPECT().Create(ctx, gomock.Any()).Return(nil)
+
+	tokens, err := authService.Issue(ctx, &models.User{ID: 1})
+
+	assert.NoError(err)
+
+	challenge, err := authService.ParseChallenge(ctx, tokens.AccessToken)
+
+	assert.Nil(challenge)
+	assert.Equal(&todoErr.UnauthorizedError{}, err)
+}
+
+func TestParseRedeemedChallenge(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockRepo := authMock.NewRepository(mockCtrl)
+	mockStore := authMock.NewRevocationStore(mockCtrl)
+	authService := service.New(mockRepo, mockStore, signing.NewSecret(secret), 15*time.Minute, 24*time.Hour, 5*time.Minute)
+
+	challenge, err := authService.IssueChallenge(ctx, &models.User{ID: 1})
+
+	assert.NoError(err)
+
+	gomock.InOrder(
+		mockStore.EXPECT().Revoke(ctx, challenge.ID, challenge.ExpiresAt).Return(nil),
+		mockStore.EXPECT().IsRevoked(ctx, challenge.ID).Return(true, nil),
+	)
+
+	assert.NoError(authService.RedeemChallenge(ctx, challenge))
+
+	parsedChallenge, err := authService.ParseChallenge(ctx, challenge.Token)
+
+	assert.Nil(parsedChallenge)
+	assert.Equal(&todoErr.UnauthorizedError{}, err)
+}
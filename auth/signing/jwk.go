@@ -0,0 +1,159 @@
+package signing
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"golang.org/x/crypto/ed25519"
+)
+
+// JWK is the public part of a key, as RFC 7517 describes
+type JWK struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	Curve     string `json:"crv,omitempty"`
+	N         string `json:"n,omitempty"`
+	E         string `json:"e,omitempty"`
+	X         string `json:"x,omitempty"`
+	Y         string `json:"y,omitempty"`
+}
+
+// JWKSet is the set of public keys which validate tokens
+type JWKSet struct {
+	Keys []*JWK `json:"keys"`
+}
+
+// JWKS returns the public keys of the key set. Shared secrets are left out.
+func (keySet *KeySet) JWKS() *JWKSet {
+	jwks := &JWKSet{
+		Keys: make([]*JWK, 0, len(keySet.keys)),
+	}
+
+	for _, key := range keySet.keys {
+		jwk := &JWK{
+			KeyID:     key.ID,
+			Use:       "sig",
+			Algorithm: key.Method.Alg(),
+		}
+
+		switch verifyKey := key.verifyKey.(type) {
+		case *rsa.PublicKey:
+			jwk.KeyType = "RSA"
+			jwk.N = encode(verifyKey.N.Bytes())
+			jwk.E = encode(big.NewInt(int64(verifyKey.E)).Bytes())
+		case *ecdsa.PublicKey:
+			size := (verifyKey.Curve.Params().BitSize + 7) / 8
+			jwk.KeyType = "EC"
+			jwk.Curve = verifyKey.Curve.Params().Name
+			jwk.X = encode(padLeft(verifyKey.X.Bytes(), size))
+			jwk.Y = encode(padLeft(verifyKey.Y.Bytes(), size))
+		case ed25519.PublicKey:
+			jwk.KeyType = "OKP"
+			jwk.Curve = "Ed25519"
+			jwk.X = encode(verifyKey)
+		default:
+			continue
+		}
+
+		jwks.Keys = append(jwks.Keys, jwk)
+	}
+
+	return jwks
+}
+
+// PublicKey returns the RSA or EC public key which the JWK describes, in the form jwt-go verifies with
+func (jwk *JWK) PublicKey() (interface{}, error) {
+	switch jwk.KeyType {
+	case "RSA":
+		n, err := decode(jwk.N)
+
+		if err != nil {
+			return nil, err
+		}
+
+		e, err := decode(jwk.E)
+
+		if err != nil {
+			return nil, err
+		}
+
+		exponent := new(big.Int).SetBytes(e)
+
+		if len(n) == 0 || !exponent.IsInt64() || exponent.Int64() < 3 || exponent.Int64() > 1<<31-1 {
+			return nil, errors.New("invalid RSA key")
+		}
+
+		return &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(exponent.Int64()),
+		}, nil
+	case "EC":
+		var curve elliptic.Curve
+
+		switch jwk.Curve {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", jwk.Curve)
+		}
+
+		x, err := decode(jwk.X)
+
+		if err != nil {
+			return nil, err
+		}
+
+		y, err := decode(jwk.Y)
+
+		if err != nil {
+			return nil, err
+		}
+
+		publicKey := &ecdsa.PublicKey{
+			Curve: curve,
+			X:     new(big.Int).SetBytes(x),
+			Y:     new(big.Int).SetBytes(y),
+		}
+
+		if !curve.IsOnCurve(publicKey.X, publicKey.Y) {
+			return nil, errors.New("invalid EC key")
+		}
+
+		return publicKey, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", jwk.KeyType)
+	}
+}
+
+// encode encodes bytes in unpadded base64url
+func encode(value []byte) string {
+	return base64.RawURLEncoding.EncodeToString(value)
+}
+
+// decode decodes unpadded base64url
+func decode(value string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(value)
+}
+
+// padLeft pads the big-endian bytes with zeros up to the given size, since EC coordinates have a fixed size
+func padLeft(value []byte, size int) []byte {
+	if len(value) >= size {
+		return value
+	}
+
+	padded := make([]byte, size)
+	copy(padded[size-len(value):], value)
+
+	return padded
+}
@@ -0,0 +1,206 @@
+package signing
+
+import (
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/dheerajgopi/todo-api/config"
+	"golang.org/x/crypto/ed25519"
+)
+
+// minRSAKeyBits is the minimum size of RSA keys
+const minRSAKeyBits = 2048
+
+// Key is a key which validates tokens, and signs them too if its private part is known.
+// Algorithm of the key follows from its type: RS256 for RSA, ES256/ES384/ES512 for ECDSA and EdDSA for Ed25519.
+type Key struct {
+	ID         string
+	Method     jwt.SigningMethod
+	signingKey interface{}
+	verifyKey  interface{}
+}
+
+// KeySet signs tokens with one of its keys, and validates tokens signed by any of its keys.
+// Signing key is named in the kid header of the tokens. Keys can be rotated by adding a new key
+// and signing with it, while keeping the previous key until the tokens signed by it expire.
+type KeySet struct {
+	signing *Key
+	keys    []*Key
+	byID    map[string]*Key
+}
+
+// NewSecret returns a key set which signs and validates tokens with a shared HS256 secret.
+// Tokens do not have a kid header, and the secret is not published in the JWKS.
+func NewSecret(secret string) *KeySet {
+	key := &Key{
+		Method:     jwt.SigningMethodHS256,
+		signingKey: []byte(secret),
+		verifyKey:  []byte(secret),
+	}
+
+	return &KeySet{
+		signing: key,
+		keys:    []*Key{key},
+		byID:    map[string]*Key{"": key},
+	}
+}
+
+// NewKey returns a key with the given id, for a RSA, ECDSA or Ed25519 private or public key
+func NewKey(id string, key interface{}) (*Key, error) {
+	newKey := &Key{
+		ID: id,
+	}
+
+	switch key := key.(type) {
+	case *rsa.PrivateKey:
+		newKey.signingKey = key
+		newKey.verifyKey = &key.PublicKey
+	case *rsa.PublicKey:
+		newKey.verifyKey = key
+	case *ecdsa.PrivateKey:
+		newKey.signingKey = key
+		newKey.verifyKey = &key.PublicKey
+	case *ecdsa.PublicKey:
+		newKey.verifyKey = key
+	case ed25519.PrivateKey:
+		newKey.signingKey = key
+		newKey.verifyKey = key.Public()
+	case ed25519.PublicKey:
+		newKey.verifyKey = key
+	default:
+		return nil, fmt.Errorf("key %s: unsupported key type %T", id, key)
+	}
+
+	switch verifyKey := newKey.verifyKey.(type) {
+	case *rsa.PublicKey:
+		if verifyKey.N.BitLen() < minRSAKeyBits {
+			return nil, fmt.Errorf("key %s: RSA key should have %d bits or more", id, minRSAKeyBits)
+		}
+
+		newKey.Method = jwt.SigningMethodRS256
+	case *ecdsa.PublicKey:
+		switch verifyKey.Curve.Params().Name {
+		case "P-256":
+			newKey.Method = jwt.SigningMethodES256
+		case "P-384":
+			newKey.Method = jwt.SigningMethodES384
+		case "P-521":
+			newKey.Method = jwt.SigningMethodES512
+		default:
+			return nil, fmt.Errorf("key %s: unsupported curve %s", id, verifyKey.Curve.Params().Name)
+		}
+	case ed25519.PublicKey:
+		newKey.Method = SigningMethodEdDSA
+	}
+
+	return newKey, nil
+}
+
+// New returns a key set of the given keys, which signs with the key having the signing key id.
+// First key signs if the signing key id is empty.
+func New(keys []*Key, signingKeyID string) (*KeySet, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("no keys given")
+	}
+
+	if signingKeyID == "" {
+		signingKeyID = keys[0].ID
+	}
+
+	keySet := &KeySet{
+		keys: keys,
+		byID: make(map[string]*Key),
+	}
+
+	for _, key := range keys {
+		if key.ID == "" {
+			return nil, errors.New("key id is required")
+		}
+
+		if _, ok := keySet.byID[key.ID]; ok {
+			return nil, fmt.Errorf("key %s: duplicate key id", key.ID)
+		}
+
+		keySet.byID[key.ID] = key
+	}
+
+	keySet.signing = keySet.byID[signingKeyID]
+
+	if keySet.signing == nil {
+		return nil, fmt.Errorf("signing key %s not found", signingKeyID)
+	}
+
+	if keySet.signing.signingKey == nil {
+		return nil, fmt.Errorf("signing key %s has no private key", signingKeyID)
+	}
+
+	return keySet, nil
+}
+
+// Load returns the key set of the JWT configuration. Keys are read from PEM files.
+// Shared secret is used if no keys are configured.
+func Load(setting *config.JwtSetting) (*KeySet, error) {
+	if len(setting.Keys) == 0 {
+		return NewSecret(setting.Secret), nil
+	}
+
+	keys := make([]*Key, 0, len(setting.Keys))
+
+	for _, keySetting := range setting.Keys {
+		content, err := ioutil.ReadFile(keySetting.File)
+
+		if err != nil {
+			return nil, err
+		}
+
+		parsedKey, err := parsePEM(content)
+
+		if err != nil {
+			return nil, fmt.Errorf("key %s: %v", keySetting.ID, err)
+		}
+
+		key, err := NewKey(keySetting.ID, parsedKey)
+
+		if err != nil {
+			return nil, err
+		}
+
+		keys = append(keys, key)
+	}
+
+	return New(keys, setting.SigningKeyID)
+}
+
+// Sign signs the claims with the signing key, naming the key in the kid header
+func (keySet *KeySet) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(keySet.signing.Method, claims)
+
+	if keySet.signing.ID != "" {
+		token.Header["kid"] = keySet.signing.ID
+	}
+
+	return token.SignedString(keySet.signing.signingKey)
+}
+
+// Parse parses and validates a token. Token should name one of the keys in its kid header,
+// and should be signed with the algorithm of that key, so that a public key is never taken as a HMAC secret.
+func (keySet *KeySet) Parse(tokenString string) (*jwt.Token, error) {
+	return jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		keyID, _ := token.Header["kid"].(string)
+		key, ok := keySet.byID[keyID]
+
+		if !ok {
+			return nil, fmt.Errorf("unknown key %q", keyID)
+		}
+
+		if token.Method.Alg() != key.Method.Alg() {
+			return nil, fmt.Errorf("unexpected signing algorithm %s", token.Method.Alg())
+		}
+
+		return key.verifyKey, nil
+	})
+}
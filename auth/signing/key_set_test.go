@@ -0,0 +1,286 @@
+package signing_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/dheerajgopi/todo-api/auth/signing"
+	"github.com/dheerajgopi/todo-api/config"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/ed25519"
+)
+
+var (
+	rsaKey, _        = rsa.GenerateKey(rand.Reader, 2048)
+	ecKey, _         = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	_, ed25519Key, _ = ed25519.GenerateKey(rand.Reader)
+)
+
+func newKey(t *testing.T, id string, key interface{}) *signing.Key {
+	newKey, err := signing.NewKey(id, key)
+
+	if err != nil {
+		t.Fatalf("Unexpected error while creating key: %s", err)
+	}
+
+	return newKey
+}
+
+func TestSignAndParse(t *testing.T) {
+	assert := assert.New(t)
+
+	keys := map[string]interface{}{
+		"RS256": rsaKey,
+		"ES256": ecKey,
+		"EdDSA": ed25519Key,
+	}
+
+	for alg, key := range keys {
+		keySet, err := signing.New([]*signing.Key{newKey(t, "k1", key)}, "")
+
+		assert.NoError(err)
+
+		token, err := keySet.Sign(jwt.MapClaims{"userId": 1})
+
+		assert.NoError(err)
+
+		parsedToken, err := keySet.Parse(token)
+
+		assert.NoError(err, alg)
+		assert.True(parsedToken.Valid)
+		assert.Equal(alg, parsedToken.Method.Alg())
+		assert.Equal("k1", parsedToken.Header["kid"])
+		assert.Equal(float64(1), parsedToken.Claims.(jwt.MapClaims)["userId"])
+	}
+}
+
+func TestParseWithRotatedKeys(t *testing.T) {
+	assert := assert.New(t)
+
+	oldKeySet, _ := signing.New([]*signing.Key{newKey(t, "old", rsaKey)}, "")
+	oldToken, _ := oldKeySet.Sign(jwt.MapClaims{"userId": 1})
+
+	keySet, err := signing.New([]*signing.Key{newKey(t, "new", ed25519Key), newKey(t, "old", &rsaKey.PublicKey)}, "new")
+
+	assert.NoError(err)
+
+	_, err = keySet.Parse(oldToken)
+
+	assert.NoError(err)
+
+	newToken, _ := keySet.Sign(jwt.MapClaims{"userId": 1})
+	parsedToken, err := keySet.Parse(newToken)
+
+	assert.NoError(err)
+	assert.Equal("new", parsedToken.Header["kid"])
+
+	_, err = oldKeySet.Parse(newToken)
+
+	assert.Error(err)
+}
+
+func TestParseRejectsOtherAlgorithms(t *testing.T) {
+	assert := assert.New(t)
+
+	keySet, _ := signing.New([]*signing.Key{newKey(t, "k1", rsaKey)}, "")
+	publicKey, _ := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+	publicPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicKey})
+
+	hmacToken := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"userId": 1})
+	hmacToken.Header["kid"] = "k1"
+	forgedToken, _ := hmacToken.SignedString(publicPEM)
+
+	_, err := keySet.Parse(forgedToken)
+
+	assert.Error(err)
+
+	unknownKeySet, _ := signing.New([]*signing.Key{newKey(t, "k2", rsaKey)}, "")
+	unknownToken, _ := unknownKeySet.Sign(jwt.MapClaims{"userId": 1})
+
+	_, err = keySet.Parse(unknownToken)
+
+	assert.Error(err)
+
+	secretToken, _ := signing.NewSecret("secret").Sign(jwt.MapClaims{"userId": 1})
+
+	_, err = keySet.Parse(secretToken)
+
+	assert.Error(err)
+
+	rsaToken, _ := keySet.Sign(jwt.MapClaims{"userId": 1})
+
+	_, err = signing.NewSecret("secret").Parse(rsaToken)
+
+	assert.Error(err)
+
+	_, err = signing.NewSecret("secret").Parse(secretToken)
+
+	assert.NoError(err)
+}
+
+func TestNewWithInvalidKeys(t *testing.T) {
+	assert := assert.New(t)
+
+	_, err := signing.New([]*signing.Key{newKey(t, "k1", &rsaKey.PublicKey)}, "")
+
+	assert.EqualError(err, "signing key k1 has no private key")
+
+	_, err = signing.New([]*signing.Key{newKey(t, "k1", rsaKey), newKey(t, "k1", ecKey)}, "")
+
+	assert.EqualError(err, "key k1: duplicate key id")
+
+	_, err = signing.New([]*signing.Key{newKey(t, "k1", rsaKey)}, "k2")
+
+	assert.EqualError(err, "signing key k2 not found")
+
+	smallKey, _ := rsa.GenerateKey(rand.Reader, 1024)
+
+	_, err = signing.NewKey("k1", smallKey)
+
+	assert.EqualError(err, "key k1: RSA key should have 2048 bits or more")
+
+	_, err = signing.NewKey("k1", []byte("secret"))
+
+	assert.EqualError(err, "key k1: unsupported key type []uint8")
+}
+
+func TestJWKS(t *testing.T) {
+	assert := assert.New(t)
+
+	keySet, _ := signing.New([]*signing.Key{
+		newKey(t, "rsa", rsaKey),
+		newKey(t, "ec", &ecKey.PublicKey),
+		newKey(t, "ed", ed25519Key),
+	}, "")
+
+	jwks := keySet.JWKS()
+
+	assert.Equal(3, len(jwks.Keys))
+
+	assert.Equal("RSA", jwks.Keys[0].KeyType)
+	assert.Equal("rsa", jwks.Keys[0].KeyID)
+	assert.Equal("RS256", jwks.Keys[0].Algorithm)
+	assert.Equal("sig", jwks.Keys[0].Use)
+	assert.Equal("AQAB", jwks.Keys[0].E)
+	assert.Equal(base64.RawURLEncoding.EncodeToString(rsaKey.N.Bytes()), jwks.Keys[0].N)
+
+	assert.Equal("EC", jwks.Keys[1].KeyType)
+	assert.Equal("P-256", jwks.Keys[1].Curve)
+	assert.Equal("ES256", jwks.Keys[1].Algorithm)
+	assert.Equal(43, len(jwks.Keys[1].X))
+	assert.Equal(43, len(jwks.Keys[1].Y))
+
+	assert.Equal("OKP", jwks.Keys[2].KeyType)
+	assert.Equal("Ed25519", jwks.Keys[2].Curve)
+	assert.Equal("EdDSA", jwks.Keys[2].Algorithm)
+	assert.Equal(base64.RawURLEncoding.EncodeToString(ed25519Key.Public().(ed25519.PublicKey)), jwks.Keys[2].X)
+
+	assert.Equal(0, len(signing.NewSecret("secret").JWKS().Keys))
+}
+
+func TestJWKPublicKey(t *testing.T) {
+	assert := assert.New(t)
+
+	keySet, _ := signing.New([]*signing.Key{
+		newKey(t, "rsa", rsaKey),
+		newKey(t, "ec", &ecKey.PublicKey),
+	}, "")
+
+	jwks := keySet.JWKS()
+
+	rsaPublicKey, err := jwks.Keys[0].PublicKey()
+
+	assert.NoError(err)
+	assert.Equal(&rsaKey.PublicKey, rsaPublicKey)
+
+	ecPublicKey, err := jwks.Keys[1].PublicKey()
+
+	assert.NoError(err)
+	assert.Equal(ecKey.PublicKey.X, ecPublicKey.(*ecdsa.PublicKey).X)
+	assert.Equal(ecKey.PublicKey.Y, ecPublicKey.(*ecdsa.PublicKey).Y)
+
+	_, err = (&signing.JWK{KeyType: "EC", Curve: "P-256", X: jwks.Keys[1].Y, Y: jwks.Keys[1].X}).PublicKey()
+
+	assert.EqualError(err, "invalid EC key")
+
+	_, err = (&signing.JWK{KeyType: "oct"}).PublicKey()
+
+	assert.EqualError(err, `unsupported key type "oct"`)
+}
+
+func TestLoad(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "keys")
+
+	if err != nil {
+		t.Fatalf("Unexpected error while creating temp dir: %s", err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	seed, _ := asn1.Marshal(ed25519Key.Seed())
+	ed25519PKCS8, _ := asn1.Marshal(struct {
+		Version    int
+		Algorithm  pkix.AlgorithmIdentifier
+		PrivateKey []byte
+	}{
+		Algorithm:  pkix.AlgorithmIdentifier{Algorithm: asn1.ObjectIdentifier{1, 3, 101, 112}},
+		PrivateKey: seed,
+	})
+	ecPrivateKey, _ := x509.MarshalECPrivateKey(ecKey)
+
+	files := map[string]*pem.Block{
+		"ed.pem":  {Type: "PRIVATE KEY", Bytes: ed25519PKCS8},
+		"rsa.pem": {Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rsaKey)},
+		"ec.pem":  {Type: "EC PRIVATE KEY", Bytes: ecPrivateKey},
+	}
+
+	for name, block := range files {
+		if err = ioutil.WriteFile(filepath.Join(dir, name), pem.EncodeToMemory(block), 0600); err != nil {
+			t.Fatalf("Unexpected error while writing key: %s", err)
+		}
+	}
+
+	keySet, err := signing.Load(&config.JwtSetting{
+		Keys: []*config.JwtKeySetting{
+			{ID: "ed", File: filepath.Join(dir, "ed.pem")},
+			{ID: "rsa", File: filepath.Join(dir, "rsa.pem")},
+			{ID: "ec", File: filepath.Join(dir, "ec.pem")},
+		},
+		SigningKeyID: "ed",
+	})
+
+	assert.NoError(err)
+
+	token, _ := keySet.Sign(jwt.MapClaims{"userId": 1})
+	parsedToken, err := keySet.Parse(token)
+
+	assert.NoError(err)
+	assert.Equal("EdDSA", parsedToken.Method.Alg())
+
+	assert.Equal(base64.RawURLEncoding.EncodeToString(ed25519Key.Public().(ed25519.PublicKey)), keySet.JWKS().Keys[0].X)
+
+	_, err = signing.Load(&config.JwtSetting{
+		Keys: []*config.JwtKeySetting{{ID: "missing", File: filepath.Join(dir, "missing.pem")}},
+	})
+
+	assert.Error(err)
+
+	keySet, err = signing.Load(&config.JwtSetting{Secret: "secret"})
+
+	assert.NoError(err)
+	assert.Equal(0, len(keySet.JWKS().Keys))
+}
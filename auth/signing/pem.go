@@ -0,0 +1,74 @@
+package signing
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
+	"errors"
+
+	"golang.org/x/crypto/ed25519"
+)
+
+// oidEd25519 identifies Ed25519 keys in PKCS #8 and PKIX structures, as RFC 8410 describes
+var oidEd25519 = asn1.ObjectIdentifier{1, 3, 101, 112}
+
+// pkcs8 is the PKCS #8 structure of a private key
+type pkcs8 struct {
+	Version    int
+	Algorithm  pkix.AlgorithmIdentifier
+	PrivateKey []byte
+}
+
+// publicKeyInfo is the PKIX structure of a public key
+type publicKeyInfo struct {
+	Algorithm pkix.AlgorithmIdentifier
+	PublicKey asn1.BitString
+}
+
+// parsePEM parses a PEM encoded private or public key.
+// RSA and ECDSA keys are parsed by crypto/x509. Ed25519 keys are parsed here, since crypto/x509 does not know them.
+func parsePEM(content []byte) (interface{}, error) {
+	block, _ := pem.Decode(content)
+
+	if block == nil {
+		return nil, errors.New("no PEM data found")
+	}
+
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		return x509.ParsePKCS1PublicKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		return x509.ParseECPrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		var privateKey pkcs8
+
+		if _, err := asn1.Unmarshal(block.Bytes, &privateKey); err == nil && privateKey.Algorithm.Algorithm.Equal(oidEd25519) {
+			var seed []byte
+
+			if _, err := asn1.Unmarshal(privateKey.PrivateKey, &seed); err != nil || len(seed) != ed25519.SeedSize {
+				return nil, errors.New("invalid Ed25519 private key")
+			}
+
+			return ed25519.NewKeyFromSeed(seed), nil
+		}
+
+		return x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		var publicKey publicKeyInfo
+
+		if _, err := asn1.Unmarshal(block.Bytes, &publicKey); err == nil && publicKey.Algorithm.Algorithm.Equal(oidEd25519) {
+			if len(publicKey.PublicKey.Bytes) != ed25519.PublicKeySize {
+				return nil, errors.New("invalid Ed25519 public key")
+			}
+
+			return ed25519.PublicKey(publicKey.PublicKey.Bytes), nil
+		}
+
+		return x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return nil, errors.New("unsupported PEM block type " + block.Type)
+	}
+}
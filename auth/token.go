@@ -0,0 +1,43 @@
+package auth
+
+import (
+	"strconv"
+	"time"
+)
+
+// Claims of the access tokens, besides the registered claims.
+// Verified claim tells whether the user had verified the email when the token was issued.
+// Purpose claim is set on the tokens which are not access tokens, like MFA challenges.
+const (
+	ClaimUserID    = "userId"
+	ClaimSessionID = "sid"
+	ClaimVerified  = "verified"
+	ClaimPurpose   = "purpose"
+)
+
+// PurposeMfaChallenge is the purpose of MFA challenge tokens
+const PurposeMfaChallenge = "mfaChallenge"
+
+// UserRevocationID returns the id under which all the access tokens of an user are revoked at once, like when the user is deleted or deactivated
+func UserRevocationID(userID int64) string {
+	return "user:" + strconv.FormatInt(userID, 10)
+}
+
+// TokenPair is a short lived access token (JWT), along with the refresh token which renews it.
+// A session is the family of refresh tokens rotated from one login, and its id is in the sid claim of the access tokens.
+type TokenPair struct {
+	AccessToken      string
+	AccessExpiresAt  time.Time
+	RefreshToken     string
+	RefreshExpiresAt time.Time
+}
+
+// Challenge is a short lived token (JWT) which proves that an user passed the password step of a login,
+// and which is exchanged for a token pair along with a TOTP or recovery code.
+// Challenges have no session, so they are not accepted as access tokens.
+type Challenge struct {
+	ID        string
+	UserID    int64
+	Token     string
+	ExpiresAt time.Time
+}
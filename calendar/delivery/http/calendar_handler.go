@@ -0,0 +1,164 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/dheerajgopi/todo-api/calendar"
+	"github.com/dheerajgopi/todo-api/common"
+	todoErr "github.com/dheerajgopi/todo-api/common/error"
+	"github.com/dheerajgopi/todo-api/common/middlewares"
+	"github.com/gorilla/mux"
+)
+
+// icsContentType is the content type of calendar feeds
+const icsContentType = "text/calendar; charset=utf-8"
+
+// CalendarHandler represents HTTP handler for calendar feeds
+type CalendarHandler struct {
+	CalendarService calendar.Service
+	App             *common.App
+}
+
+// New creates new HTTP handler for calendar feeds.
+// Feed is read by calendar apps with the secret token in its path, so it does not go through the JWT validator.
+func New(router *mux.Router, service calendar.Service, app *common.App) {
+	handler := &CalendarHandler{
+		CalendarService: service,
+		App:             app,
+	}
+
+	jwtMiddleware := middlewares.JwtValidator(app)
+
+	router.HandleFunc("/calendar/feed", app.CreateHandler(jwtMiddleware(handler.Create))).Methods("POST")
+	router.HandleFunc("/calendar/feed", app.CreateHandler(jwtMiddleware(handler.Revoke))).Methods("DELETE")
+	router.HandleFunc("/calendar/feed/{token:[A-Za-z0-9_-]+}.ics", app.CreateHandler(handler.Feed)).Methods("GET")
+}
+
+// Create will generate a new secret feed url for the user, revoking the previous one
+func (handler *CalendarHandler) Create(res http.ResponseWriter, req *http.Request, reqCtx *common.RequestContext) (int, interface{}, *todoErr.APIError) {
+	token, err := handler.CalendarService.Create(context.TODO(), reqCtx.UserID)
+
+	if err != nil {
+		return calendarServiceError(err)
+	}
+
+	responseData := &CreateFeedResponse{
+		Token: token,
+		Path:  "/calendar/feed/" + token + ".ics",
+	}
+
+	return http.StatusCreated, responseData, nil
+}
+
+// Revoke will remove the feed url of the user
+func (handler *CalendarHandler) Revoke(res http.ResponseWriter, req *http.Request, reqCtx *common.RequestContext) (int, interface{}, *todoErr.APIError) {
+	if err := handler.CalendarService.Revoke(context.TODO(), reqCtx.UserID); err != nil {
+		return calendarServiceError(err)
+	}
+
+	return http.StatusOK, nil, nil
+}
+
+// Feed will render the tasks of the feed owner having due dates as an iCalendar file.
+// Tasks are rendered as events, or as to-dos if kind=todo is given.
+// Calendar is not rendered again if the ETag sent in If-None-Match still matches the tasks.
+func (handler *CalendarHandler) Feed(res http.ResponseWriter, req *http.Request, reqCtx *common.RequestContext) (int, interface{}, *todoErr.APIError) {
+	kind := req.URL.Query().Get("kind")
+
+	switch kind {
+	case "":
+		kind = kindEvent
+	case kindEvent, kindTodo:
+	default:
+		apiError := todoErr.NewAPIError("", &todoErr.APIErrorBody{
+			Message: "Value should be one of event, todo",
+			Target:  "kind",
+		})
+
+		return http.StatusBadRequest, nil, apiError
+	}
+
+	feed, err := handler.CalendarService.Authenticate(context.TODO(), mux.Vars(req)["token"])
+
+	if err != nil {
+		return calendarServiceError(err)
+	}
+
+	version, err := handler.CalendarService.Version(context.TODO(), feed.User.ID)
+
+	if err != nil {
+		return calendarServiceError(err)
+	}
+
+	etag := `"` + version + "-" + kind + `"`
+	headers := map[string]string{
+		"ETag":          etag,
+		"Cache-Control": "private, no-cache",
+	}
+
+	if etagMatches(req.Header.Get("If-None-Match"), etag) {
+		responseData := &common.RawResponse{
+			ContentType: icsContentType,
+			Headers:     headers,
+			Body:        ioutil.NopCloser(bytes.NewReader(nil)),
+		}
+
+		return http.StatusNotModified, responseData, nil
+	}
+
+	tasks, err := handler.CalendarService.ListTasks(context.TODO(), feed.User.ID)
+
+	if err != nil {
+		return calendarServiceError(err)
+	}
+
+	content := renderCalendar(tasks, kind)
+	headers["Content-Length"] = strconv.Itoa(len(content))
+
+	responseData := &common.RawResponse{
+		ContentType: icsContentType,
+		Headers:     headers,
+		Body:        ioutil.NopCloser(bytes.NewReader(content)),
+	}
+
+	return http.StatusOK, responseData, nil
+}
+
+// etagMatches checks whether an If-None-Match header lists the given ETag, comparing weakly as RFC 7232 asks for
+func etagMatches(header string, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+
+	return false
+}
+
+// calendarServiceError maps errors returned by the calendar service to the API response
+func calendarServiceError(err error) (int, interface{}, *todoErr.APIError) {
+	switch err.(type) {
+	case *todoErr.ResourceNotFoundError:
+		resourceNotFoundErr, _ := err.(*todoErr.ResourceNotFoundError)
+
+		apiError := todoErr.NewAPIError(resourceNotFoundErr.Error(), &todoErr.APIErrorBody{
+			Message: "Not found",
+			Target:  resourceNotFoundErr.Resource,
+		})
+
+		return http.StatusNotFound, nil, apiError
+	default:
+		apiError := todoErr.NewAPIError(err.Error(), &todoErr.APIErrorBody{
+			Message: "Internal server error",
+		})
+
+		return http.StatusInternalServerError, nil, apiError
+	}
+}
@@ -0,0 +1,292 @@
+package http_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dheerajgopi/todo-api/calendar"
+	_calendarHandler "github.com/dheerajgopi/todo-api/calendar/delivery/http"
+	mock "github.com/dheerajgopi/todo-api/calendar/mock"
+	"github.com/dheerajgopi/todo-api/common"
+	_errors "github.com/dheerajgopi/todo-api/common/error"
+	"github.com/dheerajgopi/todo-api/config"
+	"github.com/dheerajgopi/todo-api/models"
+	"github.com/dheerajgopi/todo-api/task"
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreate(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+	reqCtx.UserID = 1
+	req := httptest.NewRequest("POST", "/calendar/feed", nil)
+
+	mockService.
+		EXPECT().
+		Create(gomock.Any(), int64(1)).
+		Return("secret", nil).
+		Times(1)
+
+	status, data, err := handler.Create(httptest.NewRecorder(), req, reqCtx)
+
+	responseData := data.(*_calendarHandler.CreateFeedResponse)
+
+	assert.Equal(201, status)
+	assert.Nil(err)
+	assert.Equal("secret", responseData.Token)
+	assert.Equal("/calendar/feed/secret.ics", responseData.Path)
+}
+
+func TestRevokeWithoutFeed(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+	reqCtx.UserID = 1
+	req := httptest.NewRequest("DELETE", "/calendar/feed", nil)
+
+	mockService.
+		EXPECT().
+		Revoke(gomock.Any(), int64(1)).
+		Return(&_errors.ResourceNotFoundError{Resource: "calendarFeed"}).
+		Times(1)
+
+	status, data, err := handler.Revoke(httptest.NewRecorder(), req, reqCtx)
+
+	assert.Equal(404, status)
+	assert.Nil(data)
+	assert.Equal("calendarFeed", err.Body[0].Target)
+}
+
+func TestFeed(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+	req := feedRequest("/calendar/feed/secret.ics")
+
+	updatedAt := time.Date(2019, 10, 20, 9, 0, 0, 0, time.UTC)
+	allDay := time.Date(2019, 10, 30, 18, 30, 0, 0, time.UTC)
+	timed := time.Date(2019, 10, 31, 15, 0, 0, 0, time.UTC)
+	remindAt := time.Date(2019, 10, 31, 14, 45, 0, 0, time.UTC)
+
+	mockService.EXPECT().Authenticate(gomock.Any(), "secret").Return(&models.CalendarFeed{User: &models.User{ID: 1}}, nil)
+	mockService.EXPECT().Version(gomock.Any(), int64(1)).Return("abc", nil)
+	mockService.
+		EXPECT().
+		ListTasks(gomock.Any(), int64(1)).
+		Return([]*models.Task{
+			{ID: 1, Title: "Pay rent; electricity, water", DueAt: &allDay, DueTimezone: "Asia/Kolkata", CreatedAt: updatedAt, UpdatedAt: updatedAt},
+			{ID: 2, Title: "Call mom", Description: "Ask about\nthe trip", DueAt: &timed, DueTimezone: "UTC", RemindAt: &remindAt,
+				CreatedAt: updatedAt, UpdatedAt: updatedAt},
+		}, nil)
+
+	status, data, err := handler.Feed(httptest.NewRecorder(), req, reqCtx)
+
+	assert.Equal(200, status)
+	assert.Nil(err)
+
+	responseData := data.(*common.RawResponse)
+	body, _ := ioutil.ReadAll(responseData.Body)
+
+	assert.Equal("text/calendar; charset=utf-8", responseData.ContentType)
+	assert.Equal(`"abc-event"`, responseData.Headers["ETag"])
+	assert.Equal(strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//todo-api//Tasks//EN",
+		"CALSCALE:GREGORIAN",
+		"METHOD:PUBLISH",
+		"X-WR-CALNAME:Tasks",
+		"REFRESH-INTERVAL;VALUE=DURATION:PT1H",
+		"X-PUBLISHED-TTL:PT1H",
+		"BEGIN:VEVENT",
+		"UID:task-1@todo-api",
+		"DTSTAMP:20191020T090000Z",
+		"CREATED:20191020T090000Z",
+		"LAST-MODIFIED:20191020T090000Z",
+		`SUMMARY:Pay rent\; electricity\, water`,
+		"DTSTART;VALUE=DATE:20191031",
+		"TRANSP:TRANSPARENT",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:task-2@todo-api",
+		"DTSTAMP:20191020T090000Z",
+		"CREATED:20191020T090000Z",
+		"LAST-MODIFIED:20191020T090000Z",
+		"SUMMARY:Call mom",
+		`DESCRIPTION:Ask about\nthe trip`,
+		"DTSTART:20191031T150000Z",
+		"TRANSP:TRANSPARENT",
+		"BEGIN:VALARM",
+		"ACTION:DISPLAY",
+		"DESCRIPTION:Call mom",
+		"TRIGGER;VALUE=DATE-TIME:20191031T144500Z",
+		"END:VALARM",
+		"END:VEVENT",
+		"END:VCALENDAR",
+		"",
+	}, "\r\n"), string(body))
+}
+
+func TestFeedAsTodos(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+	req := feedRequest("/calendar/feed/secret.ics?kind=todo")
+
+	updatedAt := time.Date(2019, 10, 20, 9, 0, 0, 0, time.UTC)
+	dueAt := time.Date(2019, 10, 31, 15, 0, 0, 0, time.UTC)
+	longTitle := strings.Repeat("Plan the trip to Kochi ", 4) + "ünd more"
+
+	mockService.EXPECT().Authenticate(gomock.Any(), "secret").Return(&models.CalendarFeed{User: &models.User{ID: 1}}, nil)
+	mockService.EXPECT().Version(gomock.Any(), int64(1)).Return("abc", nil)
+	mockService.
+		EXPECT().
+		ListTasks(gomock.Any(), int64(1)).
+		Return([]*models.Task{
+			{ID: 1, Title: longTitle, DueAt: &dueAt, Priority: task.PriorityHigh, IsComplete: true, CreatedAt: updatedAt, UpdatedAt: updatedAt},
+		}, nil)
+
+	status, data, _ := handler.Feed(httptest.NewRecorder(), req, reqCtx)
+
+	responseData := data.(*common.RawResponse)
+	body, _ := ioutil.ReadAll(responseData.Body)
+	content := string(body)
+
+	assert.Equal(200, status)
+	assert.Equal(`"abc-todo"`, responseData.Headers["ETag"])
+	assert.Contains(content, "BEGIN:VTODO\r\n")
+	assert.Contains(content, "DUE:20191031T150000Z\r\n")
+	assert.Contains(content, "PRIORITY:3\r\n")
+	assert.Contains(content, "STATUS:COMPLETED\r\nCOMPLETED:20191020T090000Z\r\n")
+
+	for _, line := range strings.Split(content, "\r\n") {
+		assert.True(len(line) <= 75, line)
+	}
+
+	unfolded := strings.Replace(content, "\r\n ", "", -1)
+
+	assert.Contains(unfolded, "SUMMARY:"+longTitle+"\r\n")
+}
+
+func TestFeedNotModified(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+	req := feedRequest("/calendar/feed/secret.ics")
+	req.Header.Set("If-None-Match", `"old-event", W/"abc-event"`)
+
+	mockService.EXPECT().Authenticate(gomock.Any(), "secret").Return(&models.CalendarFeed{User: &models.User{ID: 1}}, nil)
+	mockService.EXPECT().Version(gomock.Any(), int64(1)).Return("abc", nil)
+	mockService.EXPECT().ListTasks(gomock.Any(), gomock.Any()).Times(0)
+
+	status, data, err := handler.Feed(httptest.NewRecorder(), req, reqCtx)
+
+	responseData := data.(*common.RawResponse)
+	body, _ := ioutil.ReadAll(responseData.Body)
+
+	assert.Equal(304, status)
+	assert.Nil(err)
+	assert.Equal(`"abc-event"`, responseData.Headers["ETag"])
+	assert.Equal(0, len(body))
+}
+
+func TestFeedWithUnknownToken(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+	req := feedRequest("/calendar/feed/secret.ics")
+
+	mockService.EXPECT().Authenticate(gomock.Any(), "secret").Return(nil, &_errors.ResourceNotFoundError{Resource: "calendarFeed"})
+
+	status, data, err := handler.Feed(httptest.NewRecorder(), req, reqCtx)
+
+	assert.Equal(404, status)
+	assert.Nil(data)
+	assert.Equal("Not found", err.Body[0].Message)
+}
+
+func TestFeedWithInvalidKind(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+	req := feedRequest("/calendar/feed/secret.ics?kind=journal")
+
+	status, data, err := handler.Feed(httptest.NewRecorder(), req, reqCtx)
+
+	assert.Equal(400, status)
+	assert.Nil(data)
+	assert.Equal("kind", err.Body[0].Target)
+}
+
+func TestFeedWithServerError(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+	req := feedRequest("/calendar/feed/secret.ics")
+
+	mockService.EXPECT().Authenticate(gomock.Any(), "secret").Return(&models.CalendarFeed{User: &models.User{ID: 1}}, nil)
+	mockService.EXPECT().Version(gomock.Any(), int64(1)).Return("", errors.New("db down"))
+
+	status, data, err := handler.Feed(httptest.NewRecorder(), req, reqCtx)
+
+	assert.Equal(500, status)
+	assert.Nil(data)
+	assert.Equal("Internal server error", err.Body[0].Message)
+}
+
+// feedRequest builds a request for the feed, with the token in the path variables
+func feedRequest(target string) *http.Request {
+	req := httptest.NewRequest("GET", target, nil)
+
+	return mux.SetURLVars(req, map[string]string{"token": "secret"})
+}
+
+func setupHandler(mockService calendar.Service) *_calendarHandler.CalendarHandler {
+	app := &common.App{
+		Logger: logrus.New(),
+		Config: &config.Config{},
+	}
+
+	handler := &_calendarHandler.CalendarHandler{
+		CalendarService: mockService,
+		App:             app,
+	}
+
+	return handler
+}
+
+func setupRequestContext(app *common.App) *common.RequestContext {
+	reqCtx := &common.RequestContext{
+		RequestID: "dummyRequestID",
+		LogEntry: app.Logger.WithFields(
+			logrus.Fields{},
+		),
+	}
+
+	return reqCtx
+}
@@ -0,0 +1,176 @@
+package http
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/dheerajgopi/todo-api/models"
+	"github.com/dheerajgopi/todo-api/task"
+)
+
+// Kinds of RFC 5545 components which tasks are rendered as
+const (
+	kindEvent = "event"
+	kindTodo  = "todo"
+)
+
+// Layouts of RFC 5545 date and UTC date-time values
+const (
+	icsDateLayout     = "20060102"
+	icsDateTimeLayout = "20060102T150405Z"
+)
+
+// icsLineLimit is the maximum length of a content line in octets, excluding the line break
+const icsLineLimit = 75
+
+// icsPriorities holds the RFC 5545 priority of each task priority, where 1 is the highest and 0 is undefined
+var icsPriorities = map[int]int{
+	task.PriorityUrgent: 1,
+	task.PriorityHigh:   3,
+	task.PriorityMedium: 5,
+	task.PriorityLow:    7,
+}
+
+// icsWriter builds an RFC 5545 calendar, one content line at a time
+type icsWriter struct {
+	buffer bytes.Buffer
+}
+
+// renderCalendar builds a calendar with a component of the given kind for each task.
+// Events suit calendar apps which do not show to-dos, like Google Calendar.
+func renderCalendar(tasks []*models.Task, kind string) []byte {
+	writer := &icsWriter{}
+
+	writer.line("BEGIN", "VCALENDAR")
+	writer.line("VERSION", "2.0")
+	writer.line("PRODID", "-//todo-api//Tasks//EN")
+	writer.line("CALSCALE", "GREGORIAN")
+	writer.line("METHOD", "PUBLISH")
+	writer.line("X-WR-CALNAME", escapeText("Tasks"))
+	writer.line("REFRESH-INTERVAL;VALUE=DURATION", "PT1H")
+	writer.line("X-PUBLISHED-TTL", "PT1H")
+
+	for _, taskModel := range tasks {
+		if kind == kindTodo {
+			writer.todo(taskModel)
+		} else {
+			writer.event(taskModel)
+		}
+	}
+
+	writer.line("END", "VCALENDAR")
+
+	return writer.buffer.Bytes()
+}
+
+// event writes a task as a VEVENT at its due date, lasting all day if the task is due at midnight
+func (writer *icsWriter) event(taskModel *models.Task) {
+	writer.line("BEGIN", "VEVENT")
+	writer.common(taskModel)
+	writer.due("DTSTART", taskModel)
+	writer.line("TRANSP", "TRANSPARENT")
+	writer.alarm(taskModel)
+	writer.line("END", "VEVENT")
+}
+
+// todo writes a task as a VTODO with its due date, priority and completion
+func (writer *icsWriter) todo(taskModel *models.Task) {
+	writer.line("BEGIN", "VTODO")
+	writer.common(taskModel)
+	writer.due("DUE", taskModel)
+
+	if priority, ok := icsPriorities[taskModel.Priority]; ok {
+		writer.line("PRIORITY", strconv.Itoa(priority))
+	}
+
+	if taskModel.IsComplete {
+		writer.line("STATUS", "COMPLETED")
+		writer.line("COMPLETED", formatDateTime(taskModel.UpdatedAt))
+	} else {
+		writer.line("STATUS", "NEEDS-ACTION")
+	}
+
+	writer.alarm(taskModel)
+	writer.line("END", "VTODO")
+}
+
+// common writes the properties shared by events and to-dos
+func (writer *icsWriter) common(taskModel *models.Task) {
+	writer.line("UID", "task-"+strconv.FormatInt(taskModel.ID, 10)+"@todo-api")
+	writer.line("DTSTAMP", formatDateTime(taskModel.UpdatedAt))
+	writer.line("CREATED", formatDateTime(taskModel.CreatedAt))
+	writer.line("LAST-MODIFIED", formatDateTime(taskModel.UpdatedAt))
+	writer.line("SUMMARY", escapeText(taskModel.Title))
+
+	if taskModel.Description != "" {
+		writer.line("DESCRIPTION", escapeText(taskModel.Description))
+	}
+}
+
+// due writes the due date of a task, as a date if the task is due at midnight in its timezone
+func (writer *icsWriter) due(name string, taskModel *models.Task) {
+	dueAt := *taskModel.DueAt
+
+	if location, err := time.LoadLocation(taskModel.DueTimezone); err == nil {
+		dueAt = dueAt.In(location)
+	}
+
+	if dueAt.Hour() == 0 && dueAt.Minute() == 0 && dueAt.Second() == 0 {
+		writer.line(name+";VALUE=DATE", dueAt.Format(icsDateLayout))
+		return
+	}
+
+	writer.line(name, formatDateTime(dueAt))
+}
+
+// alarm writes the reminder of a task, if it has one
+func (writer *icsWriter) alarm(taskModel *models.Task) {
+	if taskModel.RemindAt == nil {
+		return
+	}
+
+	writer.line("BEGIN", "VALARM")
+	writer.line("ACTION", "DISPLAY")
+	writer.line("DESCRIPTION", escapeText(taskModel.Title))
+	writer.line("TRIGGER;VALUE=DATE-TIME", formatDateTime(*taskModel.RemindAt))
+	writer.line("END", "VALARM")
+}
+
+// line writes a content line ending with CRLF. Lines longer than 75 octets are folded,
+// without splitting multi-byte characters.
+func (writer *icsWriter) line(name string, value string) {
+	content := name + ":" + value
+	limit := icsLineLimit
+
+	for len(content) > limit {
+		cut := limit
+
+		for cut > 0 && !utf8.RuneStart(content[cut]) {
+			cut--
+		}
+
+		writer.buffer.WriteString(content[:cut])
+		writer.buffer.WriteString("\r\n ")
+		content = content[cut:]
+		limit = icsLineLimit - 1
+	}
+
+	writer.buffer.WriteString(content)
+	writer.buffer.WriteString("\r\n")
+}
+
+// formatDateTime formats a time as an UTC date-time
+func formatDateTime(value time.Time) string {
+	return value.UTC().Format(icsDateTimeLayout)
+}
+
+// icsTextEscaper escapes the special characters of RFC 5545 text values
+var icsTextEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", `\n`)
+
+// escapeText escapes a text value
+func escapeText(value string) string {
+	return icsTextEscaper.Replace(value)
+}
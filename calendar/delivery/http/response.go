@@ -0,0 +1,8 @@
+package http
+
+// CreateFeedResponse represents response for POST /calendar/feed API.
+// Token is shown only once, and the feed can be read from the path without any other authentication.
+type CreateFeedResponse struct {
+	Token string `json:"token"`
+	Path  string `json:"path"`
+}
@@ -0,0 +1,123 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/dheerajgopi/todo-api/calendar (interfaces: Repository)
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	models "github.com/dheerajgopi/todo-api/models"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+)
+
+// Repository is a mock of Repository interface
+type Repository struct {
+	ctrl     *gomock.Controller
+	recorder *RepositoryMockRecorder
+}
+
+// RepositoryMockRecorder is the mock recorder for Repository
+type RepositoryMockRecorder struct {
+	mock *Repository
+}
+
+// NewRepository creates a new mock instance
+func NewRepository(ctrl *gomock.Controller) *Repository {
+	mock := &Repository{ctrl: ctrl}
+	mock.recorder = &RepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *Repository) EXPECT() *RepositoryMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method
+func (m *Repository) Delete(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete
+func (mr *RepositoryMockRecorder) Delete(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*Repository)(nil).Delete), arg0, arg1)
+}
+
+// GetByTokenHash mocks base method
+func (m *Repository) GetByTokenHash(arg0 context.Context, arg1 string) (*models.CalendarFeed, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByTokenHash", arg0, arg1)
+	ret0, _ := ret[0].(*models.CalendarFeed)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByTokenHash indicates an expected call of GetByTokenHash
+func (mr *RepositoryMockRecorder) GetByTokenHash(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByTokenHash", reflect.TypeOf((*Repository)(nil).GetByTokenHash), arg0, arg1)
+}
+
+// GetByUserID mocks base method
+func (m *Repository) GetByUserID(arg0 context.Context, arg1 int64) (*models.CalendarFeed, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByUserID", arg0, arg1)
+	ret0, _ := ret[0].(*models.CalendarFeed)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByUserID indicates an expected call of GetByUserID
+func (mr *RepositoryMockRecorder) GetByUserID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUserID", reflect.TypeOf((*Repository)(nil).GetByUserID), arg0, arg1)
+}
+
+// GetTasks mocks base method
+func (m *Repository) GetTasks(arg0 context.Context, arg1 int64) ([]*models.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTasks", arg0, arg1)
+	ret0, _ := ret[0].([]*models.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTasks indicates an expected call of GetTasks
+func (mr *RepositoryMockRecorder) GetTasks(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTasks", reflect.TypeOf((*Repository)(nil).GetTasks), arg0, arg1)
+}
+
+// GetVersion mocks base method
+func (m *Repository) GetVersion(arg0 context.Context, arg1 int64) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVersion", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVersion indicates an expected call of GetVersion
+func (mr *RepositoryMockRecorder) GetVersion(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVersion", reflect.TypeOf((*Repository)(nil).GetVersion), arg0, arg1)
+}
+
+// Save mocks base method
+func (m *Repository) Save(arg0 context.Context, arg1 *models.CalendarFeed) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save
+func (mr *RepositoryMockRecorder) Save(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*Repository)(nil).Save), arg0, arg1)
+}
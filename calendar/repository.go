@@ -0,0 +1,17 @@
+package calendar
+
+import (
+	"context"
+
+	"github.com/dheerajgopi/todo-api/models"
+)
+
+// Repository represents calendar feed's repository contract
+type Repository interface {
+	GetByUserID(ctx context.Context, userID int64) (*models.CalendarFeed, error)
+	GetByTokenHash(ctx context.Context, tokenHash string) (*models.CalendarFeed, error)
+	Save(ctx context.Context, feed *models.CalendarFeed) error
+	Delete(ctx context.Context, userID int64) error
+	GetTasks(ctx context.Context, userID int64) ([]*models.Task, error)
+	GetVersion(ctx context.Context, userID int64) (string, error)
+}
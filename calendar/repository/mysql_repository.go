@@ -0,0 +1,190 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/dheerajgopi/todo-api/calendar"
+	"github.com/dheerajgopi/todo-api/models"
+)
+
+type mySQLCalendarRepo struct {
+	DB *sql.DB
+}
+
+// New will return new object which implements calendar.Repository
+func New(db *sql.DB) calendar.Repository {
+	return &mySQLCalendarRepo{
+		DB: db,
+	}
+}
+
+const selectFeedQuery = `SELECT f.user_id, f.token_hash, f.created_at FROM calendar_feed f JOIN user u ON u.id=f.user_id `
+
+// GetByUserID will return the calendar feed of an user
+func (repo *mySQLCalendarRepo) GetByUserID(ctx context.Context, userID int64) (*models.CalendarFeed, error) {
+	return repo.getOne(ctx, selectFeedQuery+`WHERE f.user_id=? AND u.deleted_at IS NULL`, userID)
+}
+
+// GetByTokenHash will return the calendar feed with the given token hash, unless its user is deleted or deactivated
+func (repo *mySQLCalendarRepo) GetByTokenHash(ctx context.Context, tokenHash string) (*models.CalendarFeed, error) {
+	return repo.getOne(ctx, selectFeedQuery+`WHERE f.token_hash=? AND u.deleted_at IS NULL AND u.deactivated_at IS NULL`, tokenHash)
+}
+
+func (repo *mySQLCalendarRepo) getOne(ctx context.Context, query string, args ...interface{}) (*models.CalendarFeed, error) {
+	stmt, err := repo.DB.PrepareContext(ctx, query)
+
+	if err != nil {
+		return nil, err
+	}
+
+	feed := &models.CalendarFeed{
+		User: &models.User{},
+	}
+
+	err = stmt.QueryRowContext(ctx, args...).Scan(&feed.User.ID, &feed.TokenHash, &feed.CreatedAt)
+
+	switch err {
+	case nil:
+	case sql.ErrNoRows:
+		return nil, nil
+	default:
+		return nil, err
+	}
+
+	return feed, nil
+}
+
+// Save will store the calendar feed of an user, replacing the token of an existing feed
+func (repo *mySQLCalendarRepo) Save(ctx context.Context, feed *models.CalendarFeed) error {
+	query := `INSERT INTO calendar_feed (user_id, token_hash, created_at) VALUES (?, ?, ?)
+		ON DUPLICATE KEY UPDATE token_hash=VALUES(token_hash), created_at=VALUES(created_at)`
+
+	tx, err := repo.DB.BeginTx(ctx, nil)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(query, feed.User.ID, feed.TokenHash, feed.CreatedAt)
+
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Delete will remove the calendar feed of an user
+func (repo *mySQLCalendarRepo) Delete(ctx context.Context, userID int64) error {
+	tx, err := repo.DB.BeginTx(ctx, nil)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`DELETE FROM calendar_feed WHERE user_id=?`, userID)
+
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetTasks returns the tasks of an user which have a due date, soonest first
+func (repo *mySQLCalendarRepo) GetTasks(ctx context.Context, userID int64) ([]*models.Task, error) {
+	query := `SELECT id, title, description, is_complete, priority, due_at, due_timezone, remind_at, created_at, updated_at
+		FROM task WHERE created_by=? AND due_at IS NOT NULL AND deleted_at IS NULL ORDER BY due_at, id`
+
+	stmt, err := repo.DB.PrepareContext(ctx, query)
+
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := stmt.QueryContext(ctx, userID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	tasks := make([]*models.Task, 0)
+
+	for rows.Next() {
+		task := &models.Task{
+			CreatedBy: &models.User{
+				ID: userID,
+			},
+		}
+		dueTimezone := sql.NullString{}
+
+		err = rows.Scan(
+			&task.ID,
+			&task.Title,
+			&task.Description,
+			&task.IsComplete,
+			&task.Priority,
+			&task.DueAt,
+			&dueTimezone,
+			&task.RemindAt,
+			&task.CreatedAt,
+			&task.UpdatedAt,
+		)
+
+		if err != nil {
+			return nil, err
+		}
+
+		task.DueTimezone = dueTimezone.String
+		tasks = append(tasks, task)
+	}
+
+	err = rows.Err()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return tasks, nil
+}
+
+// GetVersion returns a value which changes whenever a task of an user with a due date is created, updated, deleted,
+// restored or purged. Sum of the task revisions moves on every update, even within the second of the last update.
+// Only the columns of an index are read, so that polling the version is cheaper than reading the tasks.
+func (repo *mySQLCalendarRepo) GetVersion(ctx context.Context, userID int64) (string, error) {
+	query := `SELECT COUNT(*), COALESCE(SUM(revision), 0), MAX(updated_at), COUNT(deleted_at), MAX(deleted_at)
+		FROM task WHERE created_by=? AND due_at IS NOT NULL`
+
+	stmt, err := repo.DB.PrepareContext(ctx, query)
+
+	if err != nil {
+		return "", err
+	}
+
+	var count, revisions, deletedCount int64
+	var lastUpdatedAt, lastDeletedAt *time.Time
+
+	err = stmt.QueryRowContext(ctx, userID).Scan(&count, &revisions, &lastUpdatedAt, &deletedCount, &lastDeletedAt)
+
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%d:%d:%d:%d:%d", count, revisions, unixTime(lastUpdatedAt), deletedCount, unixTime(lastDeletedAt)), nil
+}
+
+// unixTime returns the unix time in nanoseconds, or zero for NULL
+func unixTime(value *time.Time) int64 {
+	if value == nil {
+		return 0
+	}
+
+	return value.UnixNano()
+}
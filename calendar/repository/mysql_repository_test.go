@@ -0,0 +1,165 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/dheerajgopi/todo-api/calendar/repository"
+	"github.com/dheerajgopi/todo-api/models"
+	"github.com/stretchr/testify/assert"
+)
+
+var feedColumns = []string{"user_id", "token_hash", "created_at"}
+
+const selectFeedQuery = "SELECT f.user_id, f.token_hash, f.created_at FROM calendar_feed f JOIN user u ON u.id=f.user_id "
+
+func TestGetByTokenHash(t *testing.T) {
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	rows := sqlmock.NewRows(feedColumns).AddRow(1, "abc", time.Now())
+
+	prep := mock.ExpectPrepare(selectFeedQuery + "WHERE f.token_hash=\\? AND u.deleted_at IS NULL AND u.deactivated_at IS NULL")
+	prep.ExpectQuery().WithArgs("abc").WillReturnRows(rows)
+
+	repo := repository.New(db)
+
+	feed, err := repo.GetByTokenHash(context.TODO(), "abc")
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), feed.User.ID)
+	assert.Equal(t, "abc", feed.TokenHash)
+}
+
+func TestGetByUserIDWithNoRows(t *testing.T) {
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	prep := mock.ExpectPrepare(selectFeedQuery + "WHERE f.user_id=\\? AND u.deleted_at IS NULL")
+	prep.ExpectQuery().WithArgs(int64(1)).WillReturnRows(sqlmock.NewRows(feedColumns))
+
+	repo := repository.New(db)
+
+	feed, err := repo.GetByUserID(context.TODO(), 1)
+
+	assert.NoError(t, err)
+	assert.Nil(t, feed)
+}
+
+func TestSave(t *testing.T) {
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	now := time.Now()
+	feed := &models.CalendarFeed{
+		User:      &models.User{ID: 1},
+		TokenHash: "abc",
+		CreatedAt: now,
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO calendar_feed \\(user_id, token_hash, created_at\\) VALUES \\(\\?, \\?, \\?\\)\\s+"+
+		"ON DUPLICATE KEY UPDATE token_hash=VALUES\\(token_hash\\), created_at=VALUES\\(created_at\\)").
+		WithArgs(int64(1), "abc", now).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	repo := repository.New(db)
+
+	assert.NoError(t, repo.Save(context.TODO(), feed))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDelete(t *testing.T) {
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM calendar_feed WHERE user_id=\\?").WithArgs(int64(1)).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	repo := repository.New(db)
+
+	assert.NoError(t, repo.Delete(context.TODO(), 1))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetTasks(t *testing.T) {
+	assert := assert.New(t)
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	dueAt := time.Now()
+	rows := sqlmock.
+		NewRows([]string{"id", "title", "description", "is_complete", "priority", "due_at", "due_timezone", "remind_at", "created_at", "updated_at"}).
+		AddRow(1, "title", "", false, 2, dueAt, "Asia/Kolkata", nil, time.Now(), time.Now()).
+		AddRow(2, "title", "", true, 0, dueAt, nil, dueAt, time.Now(), time.Now())
+
+	prep := mock.ExpectPrepare("SELECT id, title, description, is_complete, priority, due_at, due_timezone, remind_at, created_at, updated_at\\s+" +
+		"FROM task WHERE created_by=\\? AND due_at IS NOT NULL AND deleted_at IS NULL ORDER BY due_at, id")
+	prep.ExpectQuery().WithArgs(int64(1)).WillReturnRows(rows)
+
+	repo := repository.New(db)
+
+	tasks, err := repo.GetTasks(context.TODO(), 1)
+
+	assert.NoError(err)
+	assert.Equal(2, len(tasks))
+	assert.Equal("Asia/Kolkata", tasks[0].DueTimezone)
+	assert.Nil(tasks[0].RemindAt)
+	assert.Equal("", tasks[1].DueTimezone)
+	assert.NotNil(tasks[1].RemindAt)
+}
+
+func TestGetVersion(t *testing.T) {
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	updatedAt := time.Unix(1571562000, 0)
+	query := "SELECT COUNT\\(\\*\\), COALESCE\\(SUM\\(revision\\), 0\\), MAX\\(updated_at\\), COUNT\\(deleted_at\\), MAX\\(deleted_at\\)\\s+" +
+		"FROM task WHERE created_by=\\? AND due_at IS NOT NULL"
+
+	prep := mock.ExpectPrepare(query)
+	prep.ExpectQuery().WithArgs(int64(1)).WillReturnRows(
+		sqlmock.NewRows([]string{"count", "revisions", "updated_at", "deleted_count", "deleted_at"}).AddRow(2, 7, updatedAt, 0, nil),
+	)
+
+	repo := repository.New(db)
+
+	version, err := repo.GetVersion(context.TODO(), 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "2:7:1571562000000000000:0:0", version)
+}
@@ -0,0 +1,17 @@
+package calendar
+
+import (
+	"context"
+
+	"github.com/dheerajgopi/todo-api/models"
+)
+
+// Service represents calendar feed's service contract.
+// Each user can have one feed, which is read with a secret token instead of the JWT.
+type Service interface {
+	Create(ctx context.Context, userID int64) (string, error)
+	Revoke(ctx context.Context, userID int64) error
+	Authenticate(ctx context.Context, token string) (*models.CalendarFeed, error)
+	Version(ctx context.Context, userID int64) (string, error)
+	ListTasks(ctx context.Context, userID int64) ([]*models.Task, error)
+}
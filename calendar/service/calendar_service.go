@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"time"
+
+	"github.com/dheerajgopi/todo-api/calendar"
+	todoErr "github.com/dheerajgopi/todo-api/common/error"
+	"github.com/dheerajgopi/todo-api/models"
+)
+
+// tokenSize is the number of random bytes in a feed token
+const tokenSize = 32
+
+type calendarService struct {
+	calendarRepo calendar.Repository
+}
+
+// New returns a new object implementing calendar.Service interface
+func New(calendarRepo calendar.Repository) calendar.Service {
+	return &calendarService{
+		calendarRepo: calendarRepo,
+	}
+}
+
+// Create generates a new secret token for the calendar feed of an user, and returns the token.
+// Existing token of the user is revoked, since only its hash is kept and it can not be shown again.
+func (service *calendarService) Create(ctx context.Context, userID int64) (string, error) {
+	tokenBytes := make([]byte, tokenSize)
+
+	if _, err := rand.Read(tokenBytes); err != nil {
+		return "", err
+	}
+
+	token := base64.RawURLEncoding.EncodeToString(tokenBytes)
+
+	feed := &models.CalendarFeed{
+		User: &models.User{
+			ID: userID,
+		},
+		TokenHash: hashToken(token),
+		CreatedAt: time.Now(),
+	}
+
+	if err := service.calendarRepo.Save(ctx, feed); err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+// Revoke removes the calendar feed of an user, so that its token stops working
+func (service *calendarService) Revoke(ctx context.Context, userID int64) error {
+	existingFeed, err := service.calendarRepo.GetByUserID(ctx, userID)
+
+	if err != nil {
+		return err
+	}
+
+	if existingFeed == nil {
+		return &todoErr.ResourceNotFoundError{
+			Resource: "calendarFeed",
+		}
+	}
+
+	return service.calendarRepo.Delete(ctx, userID)
+}
+
+// Authenticate returns the calendar feed with the given token.
+// ResourceNotFoundError is returned if the token is unknown or revoked.
+func (service *calendarService) Authenticate(ctx context.Context, token string) (*models.CalendarFeed, error) {
+	existingFeed, err := service.calendarRepo.GetByTokenHash(ctx, hashToken(token))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if existingFeed == nil {
+		return nil, &todoErr.ResourceNotFoundError{
+			Resource: "calendarFeed",
+		}
+	}
+
+	return existingFeed, nil
+}
+
+// Version returns a short hash which changes whenever the tasks in the calendar feed of an user change
+func (service *calendarService) Version(ctx context.Context, userID int64) (string, error) {
+	version, err := service.calendarRepo.GetVersion(ctx, userID)
+
+	if err != nil {
+		return "", err
+	}
+
+	return hashToken(version)[:16], nil
+}
+
+// ListTasks returns the tasks of an user which have a due date, soonest first
+func (service *calendarService) ListTasks(ctx context.Context, userID int64) ([]*models.Task, error) {
+	return service.calendarRepo.GetTasks(ctx, userID)
+}
+
+// hashToken returns the hex encoded SHA-256 hash of a value
+func hashToken(value string) string {
+	hash := sha256.Sum256([]byte(value))
+
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,154 @@
+package service_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	calendarMock "github.com/dheerajgopi/todo-api/calendar/mock"
+	"github.com/dheerajgopi/todo-api/calendar/service"
+	todoErr "github.com/dheerajgopi/todo-api/common/error"
+	"github.com/dheerajgopi/todo-api/models"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func hash(value string) string {
+	sum := sha256.Sum256([]byte(value))
+
+	return hex.EncodeToString(sum[:])
+}
+
+func TestCreate(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	mockRepo := calendarMock.NewRepository(mockCtrl)
+	calendarService := service.New(mockRepo)
+
+	var savedFeed *models.CalendarFeed
+
+	mockRepo.
+		EXPECT().
+		Save(ctx, gomock.Any()).
+		DoAndReturn(func(ctx context.Context, feed *models.CalendarFeed) error {
+			savedFeed = feed
+			return nil
+		}).
+		Times(2)
+
+	token, err := calendarService.Create(ctx, 1)
+
+	assert.NoError(err)
+	assert.Equal(43, len(token))
+	assert.Equal(int64(1), savedFeed.User.ID)
+	assert.Equal(hash(token), savedFeed.TokenHash)
+
+	newToken, err := calendarService.Create(ctx, 1)
+
+	assert.NoError(err)
+	assert.NotEqual(token, newToken)
+}
+
+func TestCreateWithError(t *testing.T) {
+	ctx := context.TODO()
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	mockRepo := calendarMock.NewRepository(mockCtrl)
+	calendarService := service.New(mockRepo)
+
+	mockRepo.EXPECT().Save(ctx, gomock.Any()).Return(errors.New("db down"))
+
+	token, err := calendarService.Create(ctx, 1)
+
+	assert.EqualError(t, err, "db down")
+	assert.Equal(t, "", token)
+}
+
+func TestRevoke(t *testing.T) {
+	ctx := context.TODO()
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	mockRepo := calendarMock.NewRepository(mockCtrl)
+	calendarService := service.New(mockRepo)
+
+	gomock.InOrder(
+		mockRepo.EXPECT().GetByUserID(ctx, int64(1)).Return(&models.CalendarFeed{User: &models.User{ID: 1}}, nil),
+		mockRepo.EXPECT().Delete(ctx, int64(1)).Return(nil),
+	)
+
+	assert.NoError(t, calendarService.Revoke(ctx, 1))
+}
+
+func TestRevokeWithoutFeed(t *testing.T) {
+	ctx := context.TODO()
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	mockRepo := calendarMock.NewRepository(mockCtrl)
+	calendarService := service.New(mockRepo)
+
+	mockRepo.EXPECT().GetByUserID(ctx, int64(1)).Return(nil, nil)
+
+	err := calendarService.Revoke(ctx, 1)
+
+	assert.IsType(t, &todoErr.ResourceNotFoundError{}, err)
+}
+
+func TestAuthenticate(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	mockRepo := calendarMock.NewRepository(mockCtrl)
+	calendarService := service.New(mockRepo)
+
+	mockRepo.EXPECT().GetByTokenHash(ctx, hash("secret")).Return(&models.CalendarFeed{User: &models.User{ID: 1}}, nil)
+	mockRepo.EXPECT().GetByTokenHash(ctx, hash("revoked")).Return(nil, nil)
+
+	feed, err := calendarService.Authenticate(ctx, "secret")
+
+	assert.NoError(err)
+	assert.Equal(int64(1), feed.User.ID)
+
+	feed, err = calendarService.Authenticate(ctx, "revoked")
+
+	assert.Nil(feed)
+	assert.IsType(&todoErr.ResourceNotFoundError{}, err)
+}
+
+func TestVersion(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	mockRepo := calendarMock.NewRepository(mockCtrl)
+	calendarService := service.New(mockRepo)
+
+	mockRepo.EXPECT().GetVersion(ctx, int64(1)).Return("2:1571562000000000000:0:0", nil)
+	mockRepo.EXPECT().GetVersion(ctx, int64(1)).Return("1:1571562000000000000:1:1571565600000000000", nil)
+
+	version, err := calendarService.Version(ctx, 1)
+
+	assert.NoError(err)
+	assert.Equal(16, len(version))
+
+	newVersion, err := calendarService.Version(ctx, 1)
+
+	assert.NoError(err)
+	assert.NotEqual(version, newVersion)
+}
@@ -0,0 +1,192 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/dheerajgopi/todo-api/collaborator"
+	"github.com/dheerajgopi/todo-api/common"
+	todoErr "github.com/dheerajgopi/todo-api/common/error"
+	"github.com/dheerajgopi/todo-api/common/middlewares"
+	"github.com/gorilla/mux"
+)
+
+// CollaboratorHandler represents HTTP handler for collaborators of tasks and projects
+type CollaboratorHandler struct {
+	CollaboratorService collaborator.Service
+	App                 *common.App
+}
+
+// New creates new HTTP handler for collaborators
+func New(router *mux.Router, service collaborator.Service, app *common.App) {
+	handler := &CollaboratorHandler{
+		CollaboratorService: service,
+		App:                 app,
+	}
+
+	jwtMiddleware := middlewares.JwtValidator(app)
+
+	paths := map[string]string{
+		collaborator.ResourceTask:    "/tasks/{id:[0-9]+}/collaborators",
+		collaborator.ResourceProject: "/projects/{id:[0-9]+}/collaborators",
+	}
+
+	for resource, path := range paths {
+		router.HandleFunc(path, app.CreateHandler(jwtMiddleware(handler.Share(resource)))).Methods("POST")
+		router.HandleFunc(path, app.CreateHandler(jwtMiddleware(handler.List(resource)))).Methods("GET")
+		router.HandleFunc(path+"/{userId:[0-9]+}", app.CreateHandler(jwtMiddleware(handler.Unshare(resource)))).Methods("DELETE")
+	}
+}
+
+// Share returns a handler which will share a task or project with another user
+func (handler *CollaboratorHandler) Share(resource string) common.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request, reqCtx *common.RequestContext) (int, interface{}, *todoErr.APIError) {
+		defer req.Body.Close()
+
+		resourceID, apiError := parsePathID(req, "id")
+
+		if apiError != nil {
+			return http.StatusBadRequest, nil, apiError
+		}
+
+		decoder := json.NewDecoder(req.Body)
+		var shareReqBody ShareRequest
+		err := decoder.Decode(&shareReqBody)
+
+		if err != nil {
+			apiError := todoErr.NewAPIError("", &todoErr.APIErrorBody{
+				Message: "Invalid request body",
+			})
+
+			return http.StatusBadRequest, nil, apiError
+		}
+
+		validationErrors := shareReqBody.ValidateAndBuild()
+
+		if len(validationErrors) > 0 {
+			apiError := todoErr.NewAPIError("", validationErrors...)
+
+			return http.StatusBadRequest, nil, apiError
+		}
+
+		newCollaborator, err := handler.CollaboratorService.Share(context.TODO(), resource, resourceID, shareReqBody.Email,
+			shareReqBody.Role, reqCtx.UserID)
+
+		if err != nil {
+			return collaboratorServiceError(err)
+		}
+
+		responseData := &ShareResponse{
+			Collaborator: newCollaboratorData(newCollaborator),
+		}
+
+		return http.StatusCreated, responseData, nil
+	}
+}
+
+// List returns a handler which will return the collaborators of a task or project
+func (handler *CollaboratorHandler) List(resource string) common.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request, reqCtx *common.RequestContext) (int, interface{}, *todoErr.APIError) {
+		resourceID, apiError := parsePathID(req, "id")
+
+		if apiError != nil {
+			return http.StatusBadRequest, nil, apiError
+		}
+
+		collaborators, err := handler.CollaboratorService.List(context.TODO(), resource, resourceID, reqCtx.UserID)
+
+		if err != nil {
+			return collaboratorServiceError(err)
+		}
+
+		collaboratorList := make([]*CollaboratorData, 0)
+
+		for _, collaborator := range collaborators {
+			collaboratorList = append(collaboratorList, newCollaboratorData(collaborator))
+		}
+
+		responseData := &ListCollaboratorResponse{
+			Collaborators: collaboratorList,
+		}
+
+		return http.StatusOK, responseData, nil
+	}
+}
+
+// Unshare returns a handler which will remove a collaborator from a task or project
+func (handler *CollaboratorHandler) Unshare(resource string) common.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request, reqCtx *common.RequestContext) (int, interface{}, *todoErr.APIError) {
+		resourceID, apiError := parsePathID(req, "id")
+
+		if apiError != nil {
+			return http.StatusBadRequest, nil, apiError
+		}
+
+		collaboratorID, apiError := parsePathID(req, "userId")
+
+		if apiError != nil {
+			return http.StatusBadRequest, nil, apiError
+		}
+
+		if err := handler.CollaboratorService.Unshare(context.TODO(), resource, resourceID, collaboratorID, reqCtx.UserID); err != nil {
+			return collaboratorServiceError(err)
+		}
+
+		return http.StatusOK, nil, nil
+	}
+}
+
+// parsePathID reads an id from the request path
+func parsePathID(req *http.Request, name string) (int64, *todoErr.APIError) {
+	id, err := strconv.ParseInt(mux.Vars(req)[name], 10, 64)
+
+	if err != nil {
+		return 0, todoErr.NewAPIError(err.Error(), &todoErr.APIErrorBody{
+			Message: "Invalid value",
+			Target:  name,
+		})
+	}
+
+	return id, nil
+}
+
+// collaboratorServiceError maps errors returned by the collaborator service to the API response
+func collaboratorServiceError(err error) (int, interface{}, *todoErr.APIError) {
+	switch err.(type) {
+	case *todoErr.ResourceNotFoundError:
+		resourceNotFoundErr, _ := err.(*todoErr.ResourceNotFoundError)
+
+		apiError := todoErr.NewAPIError(resourceNotFoundErr.Error(), &todoErr.APIErrorBody{
+			Message: "Not found",
+			Target:  resourceNotFoundErr.Resource,
+		})
+
+		return http.StatusNotFound, nil, apiError
+	case *todoErr.PermissionDeniedError:
+		permissionDeniedErr, _ := err.(*todoErr.PermissionDeniedError)
+
+		apiError := todoErr.NewAPIError(permissionDeniedErr.Error(), &todoErr.APIErrorBody{
+			Message: "Permission denied",
+			Target:  permissionDeniedErr.Resource,
+		})
+
+		return http.StatusForbidden, nil, apiError
+	case *todoErr.InvalidValueError:
+		invalidValueErr, _ := err.(*todoErr.InvalidValueError)
+
+		apiError := todoErr.NewAPIError(invalidValueErr.Error(), &todoErr.APIErrorBody{
+			Message: invalidValueErr.Reason,
+			Target:  invalidValueErr.Field,
+		})
+
+		return http.StatusBadRequest, nil, apiError
+	default:
+		apiError := todoErr.NewAPIError(err.Error(), &todoErr.APIErrorBody{
+			Message: "Internal server error",
+		})
+
+		return http.StatusInternalServerError, nil, apiError
+	}
+}
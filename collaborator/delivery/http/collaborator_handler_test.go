@@ -0,0 +1,130 @@
+package http_test
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dheerajgopi/todo-api/collaborator"
+	_collaboratorHandler "github.com/dheerajgopi/todo-api/collaborator/delivery/http"
+	mock "github.com/dheerajgopi/todo-api/collaborator/mock"
+	"github.com/dheerajgopi/todo-api/common"
+	_errors "github.com/dheerajgopi/todo-api/common/error"
+	"github.com/dheerajgopi/todo-api/config"
+	"github.com/dheerajgopi/todo-api/models"
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShareWithInvalidData(t *testing.T) {
+	reqBody := &_collaboratorHandler.ShareRequest{
+		Email: " ",
+		Role:  "owner",
+	}
+
+	payload, _ := json.Marshal(reqBody)
+
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+	req := httptest.NewRequest("POST", "/tasks/1/collaborators", strings.NewReader(string(payload)))
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+
+	status, data, err := handler.Share(collaborator.ResourceTask)(httptest.NewRecorder(), req, reqCtx)
+
+	assert.Equal(400, status)
+	assert.Nil(data)
+	assert.Equal(2, len(err.Body))
+	assert.Equal("email", err.Body[0].Target)
+	assert.Equal("role", err.Body[1].Target)
+}
+
+func TestShare(t *testing.T) {
+	reqBody := &_collaboratorHandler.ShareRequest{
+		Email: " jane@example.com ",
+	}
+
+	payload, _ := json.Marshal(reqBody)
+
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+	reqCtx.UserID = 1
+	req := httptest.NewRequest("POST", "/projects/5/collaborators", strings.NewReader(string(payload)))
+	req = mux.SetURLVars(req, map[string]string{"id": "5"})
+
+	mockService.
+		EXPECT().
+		Share(gomock.Any(), collaborator.ResourceProject, int64(5), "jane@example.com", collaborator.RoleViewer, int64(1)).
+		Return(&models.Collaborator{
+			User:      &models.User{ID: 2, Name: "jane", Email: "jane@example.com"},
+			Role:      collaborator.RoleViewer,
+			CreatedAt: time.Now(),
+		}, nil).
+		Times(1)
+
+	status, data, err := handler.Share(collaborator.ResourceProject)(httptest.NewRecorder(), req, reqCtx)
+
+	responseData := data.(*_collaboratorHandler.ShareResponse)
+
+	assert.Equal(201, status)
+	assert.Nil(err)
+	assert.Equal(int64(2), responseData.Collaborator.UserID)
+	assert.Equal("viewer", responseData.Collaborator.Role)
+}
+
+func TestUnshareWithPermissionDenied(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+	reqCtx.UserID = 3
+	req := httptest.NewRequest("DELETE", "/tasks/1/collaborators/4", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "1", "userId": "4"})
+
+	mockService.
+		EXPECT().
+		Unshare(gomock.Any(), collaborator.ResourceTask, int64(1), int64(4), int64(3)).
+		Return(&_errors.PermissionDeniedError{Resource: "task", Action: "unshare"}).
+		Times(1)
+
+	status, data, err := handler.Unshare(collaborator.ResourceTask)(httptest.NewRecorder(), req, reqCtx)
+
+	assert.Equal(403, status)
+	assert.Nil(data)
+	assert.Equal("task", err.Body[0].Target)
+}
+
+func setupHandler(mockService collaborator.Service) *_collaboratorHandler.CollaboratorHandler {
+	app := &common.App{
+		Logger: logrus.New(),
+		Config: &config.Config{},
+	}
+
+	handler := &_collaboratorHandler.CollaboratorHandler{
+		CollaboratorService: mockService,
+		App:                 app,
+	}
+
+	return handler
+}
+
+func setupRequestContext(app *common.App) *common.RequestContext {
+	reqCtx := &common.RequestContext{
+		RequestID: "dummyRequestID",
+		LogEntry: app.Logger.WithFields(
+			logrus.Fields{},
+		),
+	}
+
+	return reqCtx
+}
@@ -0,0 +1,42 @@
+package http
+
+import (
+	"strings"
+
+	"github.com/dheerajgopi/todo-api/collaborator"
+	todoErr "github.com/dheerajgopi/todo-api/common/error"
+)
+
+// ShareRequest represents request body for POST /tasks/{id}/collaborators and POST /projects/{id}/collaborators APIs.
+// Role of the collaborator is viewer by default.
+type ShareRequest struct {
+	Email string `json:"email"`
+	Role  string `json:"role"`
+}
+
+// ValidateAndBuild validates the request body for the share APIs
+func (body *ShareRequest) ValidateAndBuild() []*todoErr.APIErrorBody {
+	validationErrors := make([]*todoErr.APIErrorBody, 0)
+
+	body.Email = strings.TrimSpace(body.Email)
+
+	if body.Email == "" {
+		validationErrors = append(validationErrors, &todoErr.APIErrorBody{
+			Message: "Non-empty value is required",
+			Target:  "email",
+		})
+	}
+
+	if body.Role == "" {
+		body.Role = collaborator.RoleViewer
+	}
+
+	if !collaborator.IsValidRole(body.Role) {
+		validationErrors = append(validationErrors, &todoErr.APIErrorBody{
+			Message: "Value should be one of viewer, editor",
+			Target:  "role",
+		})
+	}
+
+	return validationErrors
+}
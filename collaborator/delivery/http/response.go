@@ -0,0 +1,37 @@
+package http
+
+import (
+	"time"
+
+	"github.com/dheerajgopi/todo-api/models"
+)
+
+// CollaboratorData represents json structure for collaborator
+type CollaboratorData struct {
+	UserID    int64     `json:"userId"`
+	Name      string    `json:"name"`
+	Email     string    `json:"email"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// ShareResponse represents response for POST /tasks/{id}/collaborators and POST /projects/{id}/collaborators APIs
+type ShareResponse struct {
+	Collaborator *CollaboratorData `json:"collaborator"`
+}
+
+// ListCollaboratorResponse represents response for GET /tasks/{id}/collaborators and GET /projects/{id}/collaborators APIs
+type ListCollaboratorResponse struct {
+	Collaborators []*CollaboratorData `json:"collaborators"`
+}
+
+// newCollaboratorData builds the json structure of a collaborator
+func newCollaboratorData(collaborator *models.Collaborator) *CollaboratorData {
+	return &CollaboratorData{
+		UserID:    collaborator.User.ID,
+		Name:      collaborator.User.Name,
+		Email:     collaborator.User.Email,
+		Role:      collaborator.Role,
+		CreatedAt: collaborator.CreatedAt,
+	}
+}
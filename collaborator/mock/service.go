@@ -0,0 +1,79 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/dheerajgopi/todo-api/collaborator (interfaces: Service)
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	models "github.com/dheerajgopi/todo-api/models"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+)
+
+// Service is a mock of Service interface
+type Service struct {
+	ctrl     *gomock.Controller
+	recorder *ServiceMockRecorder
+}
+
+// ServiceMockRecorder is the mock recorder for Service
+type ServiceMockRecorder struct {
+	mock *Service
+}
+
+// NewService creates a new mock instance
+func NewService(ctrl *gomock.Controller) *Service {
+	mock := &Service{ctrl: ctrl}
+	mock.recorder = &ServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *Service) EXPECT() *ServiceMockRecorder {
+	return m.recorder
+}
+
+// List mocks base method
+func (m *Service) List(arg0 context.Context, arg1 string, arg2, arg3 int64) ([]*models.Collaborator, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]*models.Collaborator)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List
+func (mr *ServiceMockRecorder) List(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*Service)(nil).List), arg0, arg1, arg2, arg3)
+}
+
+// Share mocks base method
+func (m *Service) Share(arg0 context.Context, arg1 string, arg2 int64, arg3, arg4 string, arg5 int64) (*models.Collaborator, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Share", arg0, arg1, arg2, arg3, arg4, arg5)
+	ret0, _ := ret[0].(*models.Collaborator)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Share indicates an expected call of Share
+func (mr *ServiceMockRecorder) Share(arg0, arg1, arg2, arg3, arg4, arg5 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Share", reflect.TypeOf((*Service)(nil).Share), arg0, arg1, arg2, arg3, arg4, arg5)
+}
+
+// Unshare mocks base method
+func (m *Service) Unshare(arg0 context.Context, arg1 string, arg2, arg3, arg4 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unshare", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Unshare indicates an expected call of Unshare
+func (mr *ServiceMockRecorder) Unshare(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unshare", reflect.TypeOf((*Service)(nil).Unshare), arg0, arg1, arg2, arg3, arg4)
+}
@@ -0,0 +1,16 @@
+package collaborator
+
+import (
+	"context"
+
+	"github.com/dheerajgopi/todo-api/models"
+)
+
+// Repository represents collaborator's repository contract.
+// Resource is either ResourceTask or ResourceProject.
+type Repository interface {
+	GetAll(ctx context.Context, resource string, resourceID int64) ([]*models.Collaborator, error)
+	GetRole(ctx context.Context, resource string, resourceID int64, userID int64) (string, error)
+	Save(ctx context.Context, resource string, resourceID int64, collaborator *models.Collaborator) error
+	Delete(ctx context.Context, resource string, resourceID int64, userID int64) error
+}
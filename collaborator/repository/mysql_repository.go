@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/dheerajgopi/todo-api/collaborator"
+	"github.com/dheerajgopi/todo-api/models"
+)
+
+type mySQLCollaboratorRepo struct {
+	DB *sql.DB
+}
+
+// New will return new object which implements collaborator.Repository
+func New(db *sql.DB) collaborator.Repository {
+	return &mySQLCollaboratorRepo{
+		DB: db,
+	}
+}
+
+// tableOf returns the collaborator table of a resource, along with its column referring to the resource
+func tableOf(resource string) (string, string) {
+	if resource == collaborator.ResourceProject {
+		return "project_collaborator", "project_id"
+	}
+
+	return "task_collaborator", "task_id"
+}
+
+// GetAll returns the collaborators of a resource in the order they were added
+func (repo *mySQLCollaboratorRepo) GetAll(ctx context.Context, resource string, resourceID int64) ([]*models.Collaborator, error) {
+	table, column := tableOf(resource)
+	query := `SELECT u.id, u.name, u.email, c.role, c.created_at FROM ` + table + ` c JOIN user u ON u.id=c.user_id
+		WHERE c.` + column + `=? ORDER BY c.created_at, u.id`
+
+	stmt, err := repo.DB.PrepareContext(ctx, query)
+
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := stmt.QueryContext(ctx, resourceID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	collaborators := make([]*models.Collaborator, 0)
+
+	for rows.Next() {
+		collaborator := &models.Collaborator{
+			User: &models.User{},
+		}
+
+		err = rows.Scan(
+			&collaborator.User.ID,
+			&collaborator.User.Name,
+			&collaborator.User.Email,
+			&collaborator.Role,
+			&collaborator.CreatedAt,
+		)
+
+		if err != nil {
+			return nil, err
+		}
+
+		collaborators = append(collaborators, collaborator)
+	}
+
+	err = rows.Err()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return collaborators, nil
+}
+
+// GetRole returns the role of an user on a resource, or an empty role if the resource is not shared with the user
+func (repo *mySQLCollaboratorRepo) GetRole(ctx context.Context, resource string, resourceID int64, userID int64) (string, error) {
+	table, column := tableOf(resource)
+	query := `SELECT role FROM ` + table + ` WHERE ` + column + `=? AND user_id=?`
+
+	stmt, err := repo.DB.PrepareContext(ctx, query)
+
+	if err != nil {
+		return "", err
+	}
+
+	role := ""
+	err = stmt.QueryRowContext(ctx, resourceID, userID).Scan(&role)
+
+	switch err {
+	case nil:
+	case sql.ErrNoRows:
+		return "", nil
+	default:
+		return "", err
+	}
+
+	return role, nil
+}
+
+// Save will share a resource with an user, or change the role of an existing collaborator
+func (repo *mySQLCollaboratorRepo) Save(ctx context.Context, resource string, resourceID int64, collaborator *models.Collaborator) error {
+	table, column := tableOf(resource)
+	query := `INSERT INTO ` + table + ` (` + column + `, user_id, role, created_at) VALUES (?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE role=VALUES(role)`
+
+	tx, err := repo.DB.BeginTx(ctx, nil)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(query, resourceID, collaborator.User.ID, collaborator.Role, collaborator.CreatedAt)
+
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Delete will stop sharing a resource with an user
+func (repo *mySQLCollaboratorRepo) Delete(ctx context.Context, resource string, resourceID int64, userID int64) error {
+	table, column := tableOf(resource)
+	query := `DELETE FROM ` + table + ` WHERE ` + column + `=? AND user_id=?`
+
+	tx, err := repo.DB.BeginTx(ctx, nil)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(query, resourceID, userID)
+
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
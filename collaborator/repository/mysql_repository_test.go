@@ -0,0 +1,121 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/dheerajgopi/todo-api/collaborator"
+	"github.com/dheerajgopi/todo-api/collaborator/repository"
+	"github.com/dheerajgopi/todo-api/models"
+	"github.com/stretchr/testify/assert"
+)
+
+var collaboratorColumns = []string{"id", "name", "email", "role", "created_at"}
+
+func TestGetAll(t *testing.T) {
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	rows := sqlmock.
+		NewRows(collaboratorColumns).
+		AddRow(2, "jane", "jane@example.com", "viewer", time.Now()).
+		AddRow(3, "john", "john@example.com", "editor", time.Now())
+
+	query := "SELECT u.id, u.name, u.email, c.role, c.created_at FROM project_collaborator c JOIN user u ON u.id=c.user_id\\s+" +
+		"WHERE c.project_id=\\? ORDER BY c.created_at, u.id"
+
+	prep := mock.ExpectPrepare(query)
+	prep.ExpectQuery().WithArgs(int64(5)).WillReturnRows(rows)
+
+	repo := repository.New(db)
+
+	collaborators, err := repo.GetAll(context.TODO(), collaborator.ResourceProject, 5)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(collaborators))
+	assert.Equal(t, "jane@example.com", collaborators[0].User.Email)
+	assert.Equal(t, "editor", collaborators[1].Role)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetRoleWithNoRows(t *testing.T) {
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	prep := mock.ExpectPrepare("SELECT role FROM task_collaborator WHERE task_id=\\? AND user_id=\\?")
+	prep.ExpectQuery().WithArgs(int64(1), int64(2)).WillReturnRows(sqlmock.NewRows([]string{"role"}))
+
+	repo := repository.New(db)
+
+	role, err := repo.GetRole(context.TODO(), collaborator.ResourceTask, 1, 2)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "", role)
+}
+
+func TestSave(t *testing.T) {
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	newCollaborator := &models.Collaborator{
+		User:      &models.User{ID: 2},
+		Role:      collaborator.RoleEditor,
+		CreatedAt: time.Now(),
+	}
+
+	query := "INSERT INTO task_collaborator \\(task_id, user_id, role, created_at\\) VALUES \\(\\?, \\?, \\?, \\?\\)\\s+" +
+		"ON DUPLICATE KEY UPDATE role=VALUES\\(role\\)"
+
+	mock.ExpectBegin()
+	mock.ExpectExec(query).
+		WithArgs(int64(1), int64(2), "editor", newCollaborator.CreatedAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	repo := repository.New(db)
+
+	err = repo.Save(context.TODO(), collaborator.ResourceTask, 1, newCollaborator)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDelete(t *testing.T) {
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM project_collaborator WHERE project_id=\\? AND user_id=\\?").
+		WithArgs(int64(5), int64(2)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	repo := repository.New(db)
+
+	err = repo.Delete(context.TODO(), collaborator.ResourceProject, 5, 2)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,63 @@
+package collaborator
+
+import todoErr "github.com/dheerajgopi/todo-api/common/error"
+
+// Resources which can be shared with collaborators
+const (
+	ResourceTask    = "task"
+	ResourceProject = "project"
+)
+
+// Roles of an user on a shared resource, from lowest to highest. Owner is implicit and never stored.
+const (
+	RoleViewer = "viewer"
+	RoleEditor = "editor"
+	RoleOwner  = "owner"
+)
+
+// roleRanks orders the roles, an empty role means no access
+var roleRanks = map[string]int{
+	"":         0,
+	RoleViewer: 1,
+	RoleEditor: 2,
+	RoleOwner:  3,
+}
+
+// IsValidRole checks whether a role can be given to a collaborator
+func IsValidRole(role string) bool {
+	return role == RoleViewer || role == RoleEditor
+}
+
+// HasRole checks whether a role allows everything the required role allows
+func HasRole(role string, required string) bool {
+	return roleRanks[role] >= roleRanks[required]
+}
+
+// HigherRole returns the higher of two roles
+func HigherRole(role string, other string) string {
+	if HasRole(role, other) {
+		return role
+	}
+
+	return other
+}
+
+// CheckRole verifies that the role of an user on a resource allows an action.
+// ResourceNotFoundError is returned if the user has no access, so that the resource is not revealed to strangers,
+// and PermissionDeniedError is returned if the role is lower than the required role.
+func CheckRole(resource string, role string, required string, action string) error {
+	if role == "" {
+		return &todoErr.ResourceNotFoundError{
+			Resource: resource,
+		}
+	}
+
+	if !HasRole(role, required) {
+		return &todoErr.PermissionDeniedError{
+			Resource: resource,
+			Action:   action,
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,14 @@
+package collaborator
+
+import (
+	"context"
+
+	"github.com/dheerajgopi/todo-api/models"
+)
+
+// Service represents collaborator service contract
+type Service interface {
+	Share(ctx context.Context, resource string, resourceID int64, email string, role string, userID int64) (*models.Collaborator, error)
+	List(ctx context.Context, resource string, resourceID int64, userID int64) ([]*models.Collaborator, error)
+	Unshare(ctx context.Context, resource string, resourceID int64, collaboratorID int64, userID int64) error
+}
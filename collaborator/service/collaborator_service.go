@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/dheerajgopi/todo-api/collaborator"
+	todoErr "github.com/dheerajgopi/todo-api/common/error"
+	"github.com/dheerajgopi/todo-api/models"
+	"github.com/dheerajgopi/todo-api/project"
+	"github.com/dheerajgopi/todo-api/task"
+	"github.com/dheerajgopi/todo-api/user"
+)
+
+type collaboratorService struct {
+	collaboratorRepo collaborator.Repository
+	userRepo         user.Repository
+	taskRepo         task.Repository
+	projectRepo      project.Repository
+}
+
+// New returns a new object implementing collaborator.Service interface
+func New(repo collaborator.Repository, userRepo user.Repository, taskRepo task.Repository, projectRepo project.Repository) collaborator.Service {
+	return &collaboratorService{
+		collaboratorRepo: repo,
+		userRepo:         userRepo,
+		taskRepo:         taskRepo,
+		projectRepo:      projectRepo,
+	}
+}
+
+// Share shares a resource owned by the user with another user, who is looked up by email.
+// Role of the collaborator is changed if the resource is already shared with the other user.
+// ResourceNotFoundError is returned if the resource is not accessible to the user, or if there is no active user with the email.
+// PermissionDeniedError is returned if the user is a collaborator and not the owner of the resource.
+func (service *collaboratorService) Share(ctx context.Context, resource string, resourceID int64, email string, role string, userID int64) (*models.Collaborator, error) {
+	existingRole, err := service.roleOf(ctx, resource, resourceID, userID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err = collaborator.CheckRole(resource, existingRole, collaborator.RoleOwner, "share"); err != nil {
+		return nil, err
+	}
+
+	if !collaborator.IsValidRole(role) {
+		return nil, &todoErr.InvalidValueError{
+			Resource: "collaborator",
+			Field:    "role",
+			Reason:   "Value should be one of viewer, editor",
+		}
+	}
+
+	sharedWith, err := service.userRepo.GetByEmail(ctx, email)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// deleted and deactivated users are kept until they are purged, but can not be shared with
+	if sharedWith == nil || sharedWith.DeletedAt != nil || sharedWith.DeactivatedAt != nil {
+		return nil, &todoErr.ResourceNotFoundError{
+			Resource: "user",
+		}
+	}
+
+	if sharedWith.ID == userID {
+		return nil, &todoErr.InvalidValueError{
+			Resource: "collaborator",
+			Field:    "email",
+			Reason:   "Owner can not be added as a collaborator",
+		}
+	}
+
+	newCollaborator := &models.Collaborator{
+		User: &models.User{
+			ID:    sharedWith.ID,
+			Name:  sharedWith.Name,
+			Email: sharedWith.Email,
+		},
+		Role:      role,
+		CreatedAt: time.Now(),
+	}
+
+	if err = service.collaboratorRepo.Save(ctx, resource, resourceID, newCollaborator); err != nil {
+		return nil, err
+	}
+
+	return newCollaborator, nil
+}
+
+// List returns the collaborators of a resource, which are visible to its owner and its collaborators.
+// ResourceNotFoundError is returned if the resource is missing, or not shared with the user.
+func (service *collaboratorService) List(ctx context.Context, resource string, resourceID int64, userID int64) ([]*models.Collaborator, error) {
+	role, err := service.roleOf(ctx, resource, resourceID, userID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err = collaborator.CheckRole(resource, role, collaborator.RoleViewer, "view"); err != nil {
+		return nil, err
+	}
+
+	return service.collaboratorRepo.GetAll(ctx, resource, resourceID)
+}
+
+// Unshare stops sharing a resource with a collaborator.
+// Owner can remove any collaborator, while collaborators can only remove themselves.
+func (service *collaboratorService) Unshare(ctx context.Context, resource string, resourceID int64, collaboratorID int64, userID int64) error {
+	role, err := service.roleOf(ctx, resource, resourceID, userID)
+
+	if err != nil {
+		return err
+	}
+
+	required := collaborator.RoleOwner
+
+	if collaboratorID == userID {
+		required = collaborator.RoleViewer
+	}
+
+	if err = collaborator.CheckRole(resource, role, required, "unshare"); err != nil {
+		return err
+	}
+
+	collaboratorRole, err := service.collaboratorRepo.GetRole(ctx, resource, resourceID, collaboratorID)
+
+	if err != nil {
+		return err
+	}
+
+	if collaboratorRole == "" {
+		return &todoErr.ResourceNotFoundError{
+			Resource: "collaborator",
+		}
+	}
+
+	return service.collaboratorRepo.Delete(ctx, resource, resourceID, collaboratorID)
+}
+
+// roleOf returns the role of an user on a resource, or an empty role if the resource is missing or not shared with the user
+func (service *collaboratorService) roleOf(ctx context.Context, resource string, resourceID int64, userID int64) (string, error) {
+	var owner *models.User
+
+	if resource == collaborator.ResourceProject {
+		existingProject, err := service.projectRepo.GetByID(ctx, resourceID)
+
+		if err != nil || existingProject == nil {
+			return "", err
+		}
+
+		owner = existingProject.CreatedBy
+	} else {
+		existingTask, err := service.taskRepo.GetByID(ctx, resourceID)
+
+		if err != nil || existingTask == nil {
+			return "", err
+		}
+
+		owner = existingTask.CreatedBy
+	}
+
+	if owner.ID == userID {
+		return collaborator.RoleOwner, nil
+	}
+
+	return service.collaboratorRepo.GetRole(ctx, resource, resourceID, userID)
+}
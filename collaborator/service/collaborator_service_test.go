@@ -0,0 +1,199 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dheerajgopi/todo-api/collaborator"
+	collaboratorMock "github.com/dheerajgopi/todo-api/collaborator/mock"
+	"github.com/dheerajgopi/todo-api/collaborator/service"
+	todoErr "github.com/dheerajgopi/todo-api/common/error"
+	"github.com/dheerajgopi/todo-api/models"
+	projectMock "github.com/dheerajgopi/todo-api/project/mock"
+	taskMock "github.com/dheerajgopi/todo-api/task/mock"
+	userMock "github.com/dheerajgopi/todo-api/user/mock"
+)
+
+func TestShare(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	mockRepo := collaboratorMock.NewRepository(mockCtrl)
+	mockUserRepo := userMock.NewRepository(mockCtrl)
+	mockTaskRepo := taskMock.NewRepository(mockCtrl)
+	collaboratorService := service.New(mockRepo, mockUserRepo, mockTaskRepo, projectMock.NewRepository(mockCtrl))
+
+	var saved *models.Collaborator
+
+	mockTaskRepo.EXPECT().GetByID(ctx, int64(1)).Return(&models.Task{ID: 1, CreatedBy: &models.User{ID: 1}}, nil).Times(1)
+	mockUserRepo.
+		EXPECT().
+		GetByEmail(ctx, "jane@example.com").
+		Return(&models.User{ID: 2, Name: "jane", Email: "jane@example.com", Passwd: "secret"}, nil).
+		Times(1)
+	mockRepo.
+		EXPECT().
+		Save(ctx, collaborator.ResourceTask, int64(1), gomock.Any()).
+		Do(func(_ context.Context, _ string, _ int64, c *models.Collaborator) {
+			saved = c
+		}).
+		Return(nil).
+		Times(1)
+
+	result, err := collaboratorService.Share(ctx, collaborator.ResourceTask, 1, "jane@example.com", collaborator.RoleEditor, 1)
+
+	assert.NoError(err)
+	assert.Equal(saved, result)
+	assert.Equal(&models.User{ID: 2, Name: "jane", Email: "jane@example.com"}, result.User)
+	assert.Equal(collaborator.RoleEditor, result.Role)
+}
+
+func TestShareByEditor(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	mockRepo := collaboratorMock.NewRepository(mockCtrl)
+	mockProjectRepo := projectMock.NewRepository(mockCtrl)
+	collaboratorService := service.New(mockRepo, userMock.NewRepository(mockCtrl), taskMock.NewRepository(mockCtrl), mockProjectRepo)
+
+	mockProjectRepo.EXPECT().GetByID(ctx, int64(5)).Return(&models.Project{ID: 5, CreatedBy: &models.User{ID: 2}}, nil).Times(1)
+	mockRepo.EXPECT().GetRole(ctx, collaborator.ResourceProject, int64(5), int64(1)).Return(collaborator.RoleEditor, nil).Times(1)
+
+	result, err := collaboratorService.Share(ctx, collaborator.ResourceProject, 5, "john@example.com", collaborator.RoleViewer, 1)
+
+	assert.Nil(result)
+	assert.Equal(&todoErr.PermissionDeniedError{Resource: "project", Action: "share"}, err)
+}
+
+func TestShareWithUnknownEmail(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	mockUserRepo := userMock.NewRepository(mockCtrl)
+	mockTaskRepo := taskMock.NewRepository(mockCtrl)
+	collaboratorService := service.New(collaboratorMock.NewRepository(mockCtrl), mockUserRepo, mockTaskRepo, projectMock.NewRepository(mockCtrl))
+
+	mockTaskRepo.EXPECT().GetByID(ctx, int64(1)).Return(&models.Task{ID: 1, CreatedBy: &models.User{ID: 1}}, nil).Times(1)
+	mockUserRepo.EXPECT().GetByEmail(ctx, "nobody@example.com").Return(nil, nil).Times(1)
+
+	result, err := collaboratorService.Share(ctx, collaborator.ResourceTask, 1, "nobody@example.com", collaborator.RoleViewer, 1)
+
+	assert.Nil(result)
+	assert.Equal(&todoErr.ResourceNotFoundError{Resource: "user"}, err)
+}
+
+func TestShareWithDeletedOrDeactivatedUser(t *testing.T) {
+	now := time.Now()
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	mockUserRepo := userMock.NewRepository(mockCtrl)
+	mockTaskRepo := taskMock.NewRepository(mockCtrl)
+	collaboratorService := service.New(collaboratorMock.NewRepository(mockCtrl), mockUserRepo, mockTaskRepo, projectMock.NewRepository(mockCtrl))
+
+	mockTaskRepo.EXPECT().GetByID(ctx, int64(1)).Return(&models.Task{ID: 1, CreatedBy: &models.User{ID: 1}}, nil).Times(2)
+	mockUserRepo.EXPECT().GetByEmail(ctx, "deleted@example.com").Return(&models.User{ID: 2, DeletedAt: &now}, nil).Times(1)
+	mockUserRepo.EXPECT().GetByEmail(ctx, "deactivated@example.com").Return(&models.User{ID: 3, DeactivatedAt: &now}, nil).Times(1)
+
+	for _, email := range []string{"deleted@example.com", "deactivated@example.com"} {
+		result, err := collaboratorService.Share(ctx, collaborator.ResourceTask, 1, email, collaborator.RoleViewer, 1)
+
+		assert.Nil(result)
+		assert.Equal(&todoErr.ResourceNotFoundError{Resource: "user"}, err)
+	}
+}
+
+func TestShareWithOwner(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	mockUserRepo := userMock.NewRepository(mockCtrl)
+	mockTaskRepo := taskMock.NewRepository(mockCtrl)
+	collaboratorService := service.New(collaboratorMock.NewRepository(mockCtrl), mockUserRepo, mockTaskRepo, projectMock.NewRepository(mockCtrl))
+
+	mockTaskRepo.EXPECT().GetByID(ctx, int64(1)).Return(&models.Task{ID: 1, CreatedBy: &models.User{ID: 1}}, nil).Times(1)
+	mockUserRepo.EXPECT().GetByEmail(ctx, "me@example.com").Return(&models.User{ID: 1}, nil).Times(1)
+
+	_, err := collaboratorService.Share(ctx, collaborator.ResourceTask, 1, "me@example.com", collaborator.RoleViewer, 1)
+
+	assert.IsType(&todoErr.InvalidValueError{}, err)
+	assert.Equal("email", err.(*todoErr.InvalidValueError).Field)
+}
+
+func TestListForStranger(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	mockRepo := collaboratorMock.NewRepository(mockCtrl)
+	mockTaskRepo := taskMock.NewRepository(mockCtrl)
+	collaboratorService := service.New(mockRepo, userMock.NewRepository(mockCtrl), mockTaskRepo, projectMock.NewRepository(mockCtrl))
+
+	mockTaskRepo.EXPECT().GetByID(ctx, int64(1)).Return(&models.Task{ID: 1, CreatedBy: &models.User{ID: 2}}, nil).Times(1)
+	mockRepo.EXPECT().GetRole(ctx, collaborator.ResourceTask, int64(1), int64(3)).Return("", nil).Times(1)
+
+	collaborators, err := collaboratorService.List(ctx, collaborator.ResourceTask, 1, 3)
+
+	assert.Nil(collaborators)
+	assert.Equal(&todoErr.ResourceNotFoundError{Resource: "task"}, err)
+}
+
+func TestUnshareByCollaboratorItself(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	mockRepo := collaboratorMock.NewRepository(mockCtrl)
+	mockTaskRepo := taskMock.NewRepository(mockCtrl)
+	collaboratorService := service.New(mockRepo, userMock.NewRepository(mockCtrl), mockTaskRepo, projectMock.NewRepository(mockCtrl))
+
+	mockTaskRepo.EXPECT().GetByID(ctx, int64(1)).Return(&models.Task{ID: 1, CreatedBy: &models.User{ID: 2}}, nil).Times(1)
+	mockRepo.EXPECT().GetRole(ctx, collaborator.ResourceTask, int64(1), int64(3)).Return(collaborator.RoleViewer, nil).Times(2)
+	mockRepo.EXPECT().Delete(ctx, collaborator.ResourceTask, int64(1), int64(3)).Return(nil).Times(1)
+
+	err := collaboratorService.Unshare(ctx, collaborator.ResourceTask, 1, 3, 3)
+
+	assert.NoError(err)
+}
+
+func TestUnshareOtherCollaboratorByEditor(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	mockRepo := collaboratorMock.NewRepository(mockCtrl)
+	mockTaskRepo := taskMock.NewRepository(mockCtrl)
+	collaboratorService := service.New(mockRepo, userMock.NewRepository(mockCtrl), mockTaskRepo, projectMock.NewRepository(mockCtrl))
+
+	mockTaskRepo.EXPECT().GetByID(ctx, int64(1)).Return(&models.Task{ID: 1, CreatedBy: &models.User{ID: 2}}, nil).Times(1)
+	mockRepo.EXPECT().GetRole(ctx, collaborator.ResourceTask, int64(1), int64(3)).Return(collaborator.RoleEditor, nil).Times(1)
+
+	err := collaboratorService.Unshare(ctx, collaborator.ResourceTask, 1, 4, 3)
+
+	assert.Equal(&todoErr.PermissionDeniedError{Resource: "task", Action: "unshare"}, err)
+}
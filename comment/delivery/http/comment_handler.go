@@ -0,0 +1,242 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/dheerajgopi/todo-api/auth"
+	"github.com/dheerajgopi/todo-api/comment"
+	"github.com/dheerajgopi/todo-api/common"
+	todoErr "github.com/dheerajgopi/todo-api/common/error"
+	"github.com/dheerajgopi/todo-api/common/middlewares"
+	"github.com/dheerajgopi/todo-api/models"
+	"github.com/gorilla/mux"
+)
+
+// CommentHandler represents HTTP handler for comments on tasks
+type CommentHandler struct {
+	CommentService comment.Service
+	App            *common.App
+}
+
+// New creates new HTTP handler for comment
+func New(router *mux.Router, service comment.Service, app *common.App) {
+	handler := &CommentHandler{
+		CommentService: service,
+		App:            app,
+	}
+
+	// API keys are accepted besides access tokens, as far as they grant the scope
+	readMiddleware := middlewares.ScopedValidator(app, auth.ScopeTasksRead)
+	writeMiddleware := middlewares.ScopedValidator(app, auth.ScopeTasksWrite)
+
+	router.HandleFunc("/tasks/{id:[0-9]+}/comments", app.CreateHandler(writeMiddleware(handler.Create))).Methods("POST")
+	router.HandleFunc("/tasks/{id:[0-9]+}/comments", app.CreateHandler(readMiddleware(handler.List))).Methods("GET")
+	router.HandleFunc("/tasks/{id:[0-9]+}/comments/{commentId:[0-9]+}", app.CreateHandler(writeMiddleware(handler.Update))).Methods("PUT")
+	router.HandleFunc("/tasks/{id:[0-9]+}/comments/{commentId:[0-9]+}", app.CreateHandler(writeMiddleware(handler.Delete))).Methods("DELETE")
+}
+
+// Create will add a comment to a task
+func (handler *CommentHandler) Create(res http.ResponseWriter, req *http.Request, reqCtx *common.RequestContext) (int, interface{}, *todoErr.APIError) {
+	defer req.Body.Close()
+
+	taskID, apiError := parsePathID(req, "id")
+
+	if apiError != nil {
+		return http.StatusBadRequest, nil, apiError
+	}
+
+	decoder := json.NewDecoder(req.Body)
+	var createCommentReqBody CreateCommentRequest
+	err := decoder.Decode(&createCommentReqBody)
+
+	if err != nil {
+		apiError := todoErr.NewAPIError("", &todoErr.APIErrorBody{
+			Message: "Invalid request body",
+		})
+
+		return http.StatusBadRequest, nil, apiError
+	}
+
+	validationErrors := createCommentReqBody.ValidateAndBuild()
+
+	if len(validationErrors) > 0 {
+		apiError := todoErr.NewAPIError("", validationErrors...)
+
+		return http.StatusBadRequest, nil, apiError
+	}
+
+	newComment := &models.Comment{
+		Task: &models.Task{
+			ID: taskID,
+		},
+		Body: createCommentReqBody.Body,
+		CreatedBy: &models.User{
+			ID: reqCtx.UserID,
+		},
+		CreatedAt: time.Now(),
+	}
+
+	if err = handler.CommentService.Create(context.TODO(), newComment); err != nil {
+		return commentServiceError(err)
+	}
+
+	responseData := &CreateCommentResponse{
+		Comment: newCommentData(newComment),
+	}
+
+	return http.StatusCreated, responseData, nil
+}
+
+// List will return the comments of a task
+func (handler *CommentHandler) List(res http.ResponseWriter, req *http.Request, reqCtx *common.RequestContext) (int, interface{}, *todoErr.APIError) {
+	taskID, apiError := parsePathID(req, "id")
+
+	if apiError != nil {
+		return http.StatusBadRequest, nil, apiError
+	}
+
+	comments, err := handler.CommentService.List(context.TODO(), taskID, reqCtx.UserID)
+
+	if err != nil {
+		return commentServiceError(err)
+	}
+
+	commentList := make([]*CommentData, 0)
+
+	for _, comment := range comments {
+		commentList = append(commentList, newCommentData(comment))
+	}
+
+	responseData := &ListCommentResponse{
+		Comments: commentList,
+	}
+
+	return http.StatusOK, responseData, nil
+}
+
+// Update will edit a comment written by the user
+func (handler *CommentHandler) Update(res http.ResponseWriter, req *http.Request, reqCtx *common.RequestContext) (int, interface{}, *todoErr.APIError) {
+	defer req.Body.Close()
+
+	taskID, apiError := parsePathID(req, "id")
+
+	if apiError != nil {
+		return http.StatusBadRequest, nil, apiError
+	}
+
+	commentID, apiError := parsePathID(req, "commentId")
+
+	if apiError != nil {
+		return http.StatusBadRequest, nil, apiError
+	}
+
+	decoder := json.NewDecoder(req.Body)
+	var updateCommentReqBody UpdateCommentRequest
+	err := decoder.Decode(&updateCommentReqBody)
+
+	if err != nil {
+		apiError := todoErr.NewAPIError("", &todoErr.APIErrorBody{
+			Message: "Invalid request body",
+		})
+
+		return http.StatusBadRequest, nil, apiError
+	}
+
+	validationErrors := updateCommentReqBody.ValidateAndBuild()
+
+	if len(validationErrors) > 0 {
+		apiError := todoErr.NewAPIError("", validationErrors...)
+
+		return http.StatusBadRequest, nil, apiError
+	}
+
+	now := time.Now()
+
+	updatedComment := &models.Comment{
+		ID: commentID,
+		Task: &models.Task{
+			ID: taskID,
+		},
+		Body:     updateCommentReqBody.Body,
+		EditedAt: &now,
+	}
+
+	if err = handler.CommentService.Update(context.TODO(), updatedComment, reqCtx.UserID); err != nil {
+		return commentServiceError(err)
+	}
+
+	responseData := &UpdateCommentResponse{
+		Comment: newCommentData(updatedComment),
+	}
+
+	return http.StatusOK, responseData, nil
+}
+
+// Delete will remove a comment written by the user
+func (handler *CommentHandler) Delete(res http.ResponseWriter, req *http.Request, reqCtx *common.RequestContext) (int, interface{}, *todoErr.APIError) {
+	taskID, apiError := parsePathID(req, "id")
+
+	if apiError != nil {
+		return http.StatusBadRequest, nil, apiError
+	}
+
+	commentID, apiError := parsePathID(req, "commentId")
+
+	if apiError != nil {
+		return http.StatusBadRequest, nil, apiError
+	}
+
+	if err := handler.CommentService.Delete(context.TODO(), taskID, commentID, reqCtx.UserID); err != nil {
+		return commentServiceError(err)
+	}
+
+	return http.StatusOK, nil, nil
+}
+
+// parsePathID reads an id from the request path
+func parsePathID(req *http.Request, name string) (int64, *todoErr.APIError) {
+	id, err := strconv.ParseInt(mux.Vars(req)[name], 10, 64)
+
+	if err != nil {
+		return 0, todoErr.NewAPIError(err.Error(), &todoErr.APIErrorBody{
+			Message: "Invalid value",
+			Target:  name,
+		})
+	}
+
+	return id, nil
+}
+
+// commentServiceError maps errors returned by the comment service to the API response
+func commentServiceError(err error) (int, interface{}, *todoErr.APIError) {
+	switch err.(type) {
+	case *todoErr.ResourceNotFoundError:
+		resourceNotFoundErr, _ := err.(*todoErr.ResourceNotFoundError)
+
+		apiError := todoErr.NewAPIError(resourceNotFoundErr.Error(), &todoErr.APIErrorBody{
+			Message: "Not found",
+			Target:  resourceNotFoundErr.Resource,
+		})
+
+		return http.StatusNotFound, nil, apiError
+	case *todoErr.PermissionDeniedError:
+		permissionDeniedErr, _ := err.(*todoErr.PermissionDeniedError)
+
+		apiError := todoErr.NewAPIError(permissionDeniedErr.Error(), &todoErr.APIErrorBody{
+			Message: "Permission denied",
+			Target:  permissionDeniedErr.Resource,
+		})
+
+		return http.StatusForbidden, nil, apiError
+	default:
+		apiError := todoErr.NewAPIError(err.Error(), &todoErr.APIErrorBody{
+			Message: "Internal server error",
+		})
+
+		return http.StatusInternalServerError, nil, apiError
+	}
+}
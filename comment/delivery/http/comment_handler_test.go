@@ -0,0 +1,134 @@
+package http_test
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dheerajgopi/todo-api/comment"
+	_commentHandler "github.com/dheerajgopi/todo-api/comment/delivery/http"
+	mock "github.com/dheerajgopi/todo-api/comment/mock"
+	"github.com/dheerajgopi/todo-api/common"
+	_errors "github.com/dheerajgopi/todo-api/common/error"
+	"github.com/dheerajgopi/todo-api/config"
+	"github.com/dheerajgopi/todo-api/models"
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateWithInvalidData(t *testing.T) {
+	reqBody := &_commentHandler.CreateCommentRequest{
+		Body: "  ",
+	}
+
+	payload, _ := json.Marshal(reqBody)
+
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+	req := httptest.NewRequest("POST", "/tasks/5/comments", strings.NewReader(string(payload)))
+	req = mux.SetURLVars(req, map[string]string{"id": "5"})
+
+	status, data, err := handler.Create(httptest.NewRecorder(), req, reqCtx)
+
+	assert.Equal(400, status)
+	assert.Nil(data)
+	assert.Equal(1, len(err.Body))
+	assert.Equal("body", err.Body[0].Target)
+}
+
+func TestCreate(t *testing.T) {
+	reqBody := &_commentHandler.CreateCommentRequest{
+		Body: " looks good ",
+	}
+
+	payload, _ := json.Marshal(reqBody)
+
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+	reqCtx.UserID = 1
+	req := httptest.NewRequest("POST", "/tasks/5/comments", strings.NewReader(string(payload)))
+	req = mux.SetURLVars(req, map[string]string{"id": "5"})
+
+	mockService.
+		EXPECT().
+		Create(gomock.Any(), gomock.Any()).
+		Do(func(_ interface{}, newComment *models.Comment) {
+			newComment.ID = 7
+		}).
+		Return(nil).
+		Times(1)
+
+	status, data, err := handler.Create(httptest.NewRecorder(), req, reqCtx)
+
+	responseData := data.(*_commentHandler.CreateCommentResponse)
+
+	assert.Equal(201, status)
+	assert.Nil(err)
+	assert.Equal(int64(7), responseData.Comment.ID)
+	assert.Equal(int64(5), responseData.Comment.TaskID)
+	assert.Equal(int64(1), responseData.Comment.CreatedBy)
+	assert.Equal("looks good", responseData.Comment.Body)
+	assert.Nil(responseData.Comment.EditedAt)
+}
+
+func TestUpdateCommentOfOtherUser(t *testing.T) {
+	reqBody := &_commentHandler.UpdateCommentRequest{
+		Body: "changed",
+	}
+
+	payload, _ := json.Marshal(reqBody)
+
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+	req := httptest.NewRequest("PUT", "/tasks/5/comments/7", strings.NewReader(string(payload)))
+	req = mux.SetURLVars(req, map[string]string{"id": "5", "commentId": "7"})
+
+	mockService.
+		EXPECT().
+		Update(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(&_errors.PermissionDeniedError{Resource: "comment", Action: "edit"}).
+		Times(1)
+
+	status, data, err := handler.Update(httptest.NewRecorder(), req, reqCtx)
+
+	assert.Equal(403, status)
+	assert.Nil(data)
+	assert.Equal("comment", err.Body[0].Target)
+}
+
+func setupHandler(mockService comment.Service) *_commentHandler.CommentHandler {
+	app := &common.App{
+		Logger: logrus.New(),
+		Config: &config.Config{},
+	}
+
+	handler := &_commentHandler.CommentHandler{
+		CommentService: mockService,
+		App:            app,
+	}
+
+	return handler
+}
+
+func setupRequestContext(app *common.App) *common.RequestContext {
+	reqCtx := &common.RequestContext{
+		RequestID: "dummyRequestID",
+		LogEntry: app.Logger.WithFields(
+			logrus.Fields{},
+		),
+	}
+
+	return reqCtx
+}
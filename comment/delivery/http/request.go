@@ -0,0 +1,70 @@
+package http
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	todoErr "github.com/dheerajgopi/todo-api/common/error"
+)
+
+// maxBodyLength is the maximum number of characters in a comment
+const maxBodyLength = 10000
+
+// CommentData represents json structure for comment
+type CommentData struct {
+	ID        int64      `json:"id"`
+	TaskID    int64      `json:"taskId"`
+	Body      string     `json:"body"`
+	CreatedBy int64      `json:"createdBy"`
+	CreatedAt time.Time  `json:"createdAt"`
+	EditedAt  *time.Time `json:"editedAt"`
+}
+
+// CreateCommentRequest represents request body for POST /tasks/{id}/comments API
+type CreateCommentRequest struct {
+	Body string `json:"body"`
+}
+
+// ValidateAndBuild validates the request body for POST /tasks/{id}/comments API
+func (body *CreateCommentRequest) ValidateAndBuild() []*todoErr.APIErrorBody {
+	commentBody, validationErrors := validateBody(body.Body)
+
+	body.Body = commentBody
+
+	return validationErrors
+}
+
+// UpdateCommentRequest represents request body for PUT /tasks/{id}/comments/{commentId} API
+type UpdateCommentRequest struct {
+	Body string `json:"body"`
+}
+
+// ValidateAndBuild validates the request body for PUT /tasks/{id}/comments/{commentId} API
+func (body *UpdateCommentRequest) ValidateAndBuild() []*todoErr.APIErrorBody {
+	commentBody, validationErrors := validateBody(body.Body)
+
+	body.Body = commentBody
+
+	return validationErrors
+}
+
+// validateBody checks the body of a comment and returns its trimmed value
+func validateBody(body string) (string, []*todoErr.APIErrorBody) {
+	trimmedBody := strings.TrimSpace(body)
+	validationErrors := make([]*todoErr.APIErrorBody, 0)
+
+	if trimmedBody == "" {
+		validationErrors = append(validationErrors, &todoErr.APIErrorBody{
+			Message: "Non-empty value is required",
+			Target:  "body",
+		})
+	} else if utf8.RuneCountInString(trimmedBody) > maxBodyLength {
+		validationErrors = append(validationErrors, &todoErr.APIErrorBody{
+			Message: "Length should be 10000 or less",
+			Target:  "body",
+		})
+	}
+
+	return trimmedBody, validationErrors
+}
@@ -0,0 +1,30 @@
+package http
+
+import "github.com/dheerajgopi/todo-api/models"
+
+// CreateCommentResponse represents response for POST /tasks/{id}/comments API
+type CreateCommentResponse struct {
+	Comment *CommentData `json:"comment"`
+}
+
+// ListCommentResponse represents response for GET /tasks/{id}/comments API
+type ListCommentResponse struct {
+	Comments []*CommentData `json:"comments"`
+}
+
+// UpdateCommentResponse represents response for PUT /tasks/{id}/comments/{commentId} API
+type UpdateCommentResponse struct {
+	Comment *CommentData `json:"comment"`
+}
+
+// newCommentData builds the json structure of a comment
+func newCommentData(comment *models.Comment) *CommentData {
+	return &CommentData{
+		ID:        comment.ID,
+		TaskID:    comment.Task.ID,
+		Body:      comment.Body,
+		CreatedBy: comment.CreatedBy.ID,
+		CreatedAt: comment.CreatedAt,
+		EditedAt:  comment.EditedAt,
+	}
+}
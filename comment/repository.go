@@ -0,0 +1,16 @@
+package comment
+
+import (
+	"context"
+
+	"github.com/dheerajgopi/todo-api/models"
+)
+
+// Repository represents comment's repository contract
+type Repository interface {
+	GetByID(ctx context.Context, id int64) (*models.Comment, error)
+	GetAllByTaskID(ctx context.Context, taskID int64) ([]*models.Comment, error)
+	Create(ctx context.Context, comment *models.Comment) error
+	Update(ctx context.Context, comment *models.Comment) error
+	Delete(ctx context.Context, id int64) error
+}
@@ -0,0 +1,183 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/dheerajgopi/todo-api/comment"
+	"github.com/dheerajgopi/todo-api/models"
+)
+
+type mySQLCommentRepo struct {
+	DB *sql.DB
+}
+
+// New will return new object which implements comment.Repository
+func New(db *sql.DB) comment.Repository {
+	return &mySQLCommentRepo{
+		DB: db,
+	}
+}
+
+const selectCommentQuery = `SELECT id, task_id, body, created_by, created_at, edited_at FROM comment `
+
+// rowScanner is implemented by both sql.Row and sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanComment reads a comment from a row of selectCommentQuery
+func scanComment(row rowScanner) (*models.Comment, error) {
+	comment := &models.Comment{
+		Task:      &models.Task{},
+		CreatedBy: &models.User{},
+	}
+
+	err := row.Scan(
+		&comment.ID,
+		&comment.Task.ID,
+		&comment.Body,
+		&comment.CreatedBy.ID,
+		&comment.CreatedAt,
+		&comment.EditedAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return comment, nil
+}
+
+// GetByID will return comment with the given id
+func (repo *mySQLCommentRepo) GetByID(ctx context.Context, id int64) (*models.Comment, error) {
+	stmt, err := repo.DB.PrepareContext(ctx, selectCommentQuery+`WHERE id=?`)
+
+	if err != nil {
+		return nil, err
+	}
+
+	comment, err := scanComment(stmt.QueryRowContext(ctx, id))
+
+	switch err {
+	case nil:
+	case sql.ErrNoRows:
+		return nil, nil
+	default:
+		return nil, err
+	}
+
+	return comment, nil
+}
+
+// GetAllByTaskID returns the comments of a task, oldest first
+func (repo *mySQLCommentRepo) GetAllByTaskID(ctx context.Context, taskID int64) ([]*models.Comment, error) {
+	stmt, err := repo.DB.PrepareContext(ctx, selectCommentQuery+`WHERE task_id=? ORDER BY created_at, id`)
+
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := stmt.QueryContext(ctx, taskID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	comments := make([]*models.Comment, 0)
+
+	for rows.Next() {
+		comment, err := scanComment(rows)
+
+		if err != nil {
+			return nil, err
+		}
+
+		comments = append(comments, comment)
+	}
+
+	err = rows.Err()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return comments, nil
+}
+
+// Create will store new comment entry
+func (repo *mySQLCommentRepo) Create(ctx context.Context, comment *models.Comment) error {
+	query := `INSERT INTO comment (task_id, body, created_by, created_at) VALUES (?, ?, ?, ?)`
+
+	tx, err := repo.DB.BeginTx(ctx, nil)
+
+	if err != nil {
+		return err
+	}
+
+	res, err := tx.Exec(query, comment.Task.ID, comment.Body, comment.CreatedBy.ID, comment.CreatedAt)
+
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	lastID, err := res.LastInsertId()
+
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	err = tx.Commit()
+
+	if err != nil {
+		return err
+	}
+
+	comment.ID = lastID
+
+	return nil
+}
+
+// Update will overwrite the body of an existing comment entry, along with its edit time
+func (repo *mySQLCommentRepo) Update(ctx context.Context, comment *models.Comment) error {
+	query := `UPDATE comment SET body=?, edited_at=? WHERE id=?`
+
+	tx, err := repo.DB.BeginTx(ctx, nil)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(query, comment.Body, comment.EditedAt, comment.ID)
+
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Delete will remove a comment entry
+func (repo *mySQLCommentRepo) Delete(ctx context.Context, id int64) error {
+	query := `DELETE FROM comment WHERE id=?`
+
+	tx, err := repo.DB.BeginTx(ctx, nil)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(query, id)
+
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
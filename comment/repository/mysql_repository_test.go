@@ -0,0 +1,153 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/dheerajgopi/todo-api/comment/repository"
+	"github.com/dheerajgopi/todo-api/models"
+	"github.com/stretchr/testify/assert"
+)
+
+var commentColumns = []string{"id", "task_id", "body", "created_by", "created_at", "edited_at"}
+
+const selectCommentQuery = "SELECT id, task_id, body, created_by, created_at, edited_at FROM comment "
+
+func TestGetByID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	editedAt := time.Now()
+	rows := sqlmock.
+		NewRows(commentColumns).
+		AddRow(1, 5, "looks good", 2, time.Now(), editedAt)
+
+	prep := mock.ExpectPrepare(selectCommentQuery + "WHERE id=\\?")
+	prep.ExpectQuery().WithArgs(int64(1)).WillReturnRows(rows)
+
+	repo := repository.New(db)
+
+	comment, err := repo.GetByID(context.TODO(), 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), comment.Task.ID)
+	assert.Equal(t, int64(2), comment.CreatedBy.ID)
+	assert.Equal(t, "looks good", comment.Body)
+	assert.Equal(t, editedAt, *comment.EditedAt)
+}
+
+func TestGetByIDWithNoRows(t *testing.T) {
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	prep := mock.ExpectPrepare(selectCommentQuery + "WHERE id=\\?")
+	prep.ExpectQuery().WithArgs(int64(1)).WillReturnRows(sqlmock.NewRows(commentColumns))
+
+	repo := repository.New(db)
+
+	comment, err := repo.GetByID(context.TODO(), 1)
+
+	assert.NoError(t, err)
+	assert.Nil(t, comment)
+}
+
+func TestGetAllByTaskID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	rows := sqlmock.
+		NewRows(commentColumns).
+		AddRow(1, 5, "first", 1, time.Now(), nil).
+		AddRow(2, 5, "second", 2, time.Now(), nil)
+
+	prep := mock.ExpectPrepare(selectCommentQuery + "WHERE task_id=\\? ORDER BY created_at, id")
+	prep.ExpectQuery().WithArgs(int64(5)).WillReturnRows(rows)
+
+	repo := repository.New(db)
+
+	comments, err := repo.GetAllByTaskID(context.TODO(), 5)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(comments))
+	assert.Equal(t, "first", comments[0].Body)
+	assert.Nil(t, comments[0].EditedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreate(t *testing.T) {
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	comment := &models.Comment{
+		Task:      &models.Task{ID: 5},
+		Body:      "looks good",
+		CreatedBy: &models.User{ID: 2},
+		CreatedAt: time.Now(),
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO comment \\(task_id, body, created_by, created_at\\) VALUES \\(\\?, \\?, \\?, \\?\\)").
+		WithArgs(int64(5), "looks good", int64(2), comment.CreatedAt).
+		WillReturnResult(sqlmock.NewResult(7, 1))
+	mock.ExpectCommit()
+
+	repo := repository.New(db)
+
+	err = repo.Create(context.TODO(), comment)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), comment.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdate(t *testing.T) {
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	editedAt := time.Now()
+	comment := &models.Comment{
+		ID:       7,
+		Body:     "looks great",
+		EditedAt: &editedAt,
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE comment SET body=\\?, edited_at=\\? WHERE id=\\?").
+		WithArgs("looks great", &editedAt, int64(7)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	repo := repository.New(db)
+
+	err = repo.Update(context.TODO(), comment)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
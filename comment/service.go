@@ -0,0 +1,15 @@
+package comment
+
+import (
+	"context"
+
+	"github.com/dheerajgopi/todo-api/models"
+)
+
+// Service represents comment service contract
+type Service interface {
+	Create(ctx context.Context, newComment *models.Comment) error
+	List(ctx context.Context, taskID int64, userID int64) ([]*models.Comment, error)
+	Update(ctx context.Context, comment *models.Comment, userID int64) error
+	Delete(ctx context.Context, taskID int64, id int64, userID int64) error
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+
+	"github.com/dheerajgopi/todo-api/comment"
+	todoErr "github.com/dheerajgopi/todo-api/common/error"
+	"github.com/dheerajgopi/todo-api/models"
+	"github.com/dheerajgopi/todo-api/task"
+)
+
+type commentService struct {
+	commentRepo comment.Repository
+	taskService task.Service
+}
+
+// New returns a new object implementing comment.Service interface.
+// Access to the comments of a task is checked through the task service.
+func New(repo comment.Repository, taskService task.Service) comment.Service {
+	return &commentService{
+		commentRepo: repo,
+		taskService: taskService,
+	}
+}
+
+// Create adds a comment to a task accessible to the user. Viewers of a shared task can comment on it too.
+func (service *commentService) Create(ctx context.Context, newComment *models.Comment) error {
+	if _, err := service.taskService.GetByID(ctx, newComment.Task.ID, newComment.CreatedBy.ID); err != nil {
+		return err
+	}
+
+	return service.commentRepo.Create(ctx, newComment)
+}
+
+// List returns the comments of a task accessible to the user, oldest first
+func (service *commentService) List(ctx context.Context, taskID int64, userID int64) ([]*models.Comment, error) {
+	if _, err := service.taskService.GetByID(ctx, taskID, userID); err != nil {
+		return nil, err
+	}
+
+	return service.commentRepo.GetAllByTaskID(ctx, taskID)
+}
+
+// Update overwrites the body of a comment written by the user.
+// Task, author and creation time are always retained from the stored comment.
+func (service *commentService) Update(ctx context.Context, comment *models.Comment, userID int64) error {
+	existingComment, err := service.authorize(ctx, comment.Task.ID, comment.ID, userID, "edit")
+
+	if err != nil {
+		return err
+	}
+
+	comment.Task = existingComment.Task
+	comment.CreatedBy = existingComment.CreatedBy
+	comment.CreatedAt = existingComment.CreatedAt
+
+	return service.commentRepo.Update(ctx, comment)
+}
+
+// Delete removes a comment written by the user
+func (service *commentService) Delete(ctx context.Context, taskID int64, id int64, userID int64) error {
+	if _, err := service.authorize(ctx, taskID, id, userID, "delete"); err != nil {
+		return err
+	}
+
+	return service.commentRepo.Delete(ctx, id)
+}
+
+// authorize loads a comment on a task accessible to the user, and verifies that the user is its author.
+// ResourceNotFoundError is returned if the task is not accessible or if the comment is not found on the task,
+// and PermissionDeniedError is returned if the comment is written by someone else.
+func (service *commentService) authorize(ctx context.Context, taskID int64, id int64, userID int64, action string) (*models.Comment, error) {
+	if _, err := service.taskService.GetByID(ctx, taskID, userID); err != nil {
+		return nil, err
+	}
+
+	existingComment, err := service.commentRepo.GetByID(ctx, id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if existingComment == nil || existingComment.Task.ID != taskID {
+		return nil, &todoErr.ResourceNotFoundError{
+			Resource: "comment",
+		}
+	}
+
+	if existingComment.CreatedBy.ID != userID {
+		return nil, &todoErr.PermissionDeniedError{
+			Resource: "comment",
+			Action:   action,
+		}
+	}
+
+	return existingComment, nil
+}
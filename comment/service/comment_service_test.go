@@ -0,0 +1,151 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	commentMock "github.com/dheerajgopi/todo-api/comment/mock"
+	"github.com/dheerajgopi/todo-api/comment/service"
+	todoErr "github.com/dheerajgopi/todo-api/common/error"
+	"github.com/dheerajgopi/todo-api/models"
+	taskMock "github.com/dheerajgopi/todo-api/task/mock"
+)
+
+func TestCreate(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	mockRepo := commentMock.NewRepository(mockCtrl)
+	mockTaskService := taskMock.NewService(mockCtrl)
+	commentService := service.New(mockRepo, mockTaskService)
+
+	newComment := &models.Comment{
+		Task:      &models.Task{ID: 5},
+		Body:      "looks good",
+		CreatedBy: &models.User{ID: 1},
+	}
+
+	gomock.InOrder(
+		mockTaskService.EXPECT().GetByID(ctx, int64(5), int64(1)).Return(&models.Task{ID: 5}, nil),
+		mockRepo.EXPECT().Create(ctx, newComment).Return(nil),
+	)
+
+	err := commentService.Create(ctx, newComment)
+
+	assert.NoError(err)
+}
+
+func TestCreateOnInaccessibleTask(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	mockTaskService := taskMock.NewService(mockCtrl)
+	commentService := service.New(commentMock.NewRepository(mockCtrl), mockTaskService)
+
+	mockTaskService.
+		EXPECT().
+		GetByID(ctx, int64(5), int64(1)).
+		Return(nil, &todoErr.ResourceNotFoundError{Resource: "task"}).
+		Times(1)
+
+	err := commentService.Create(ctx, &models.Comment{Task: &models.Task{ID: 5}, CreatedBy: &models.User{ID: 1}})
+
+	assert.Equal(&todoErr.ResourceNotFoundError{Resource: "task"}, err)
+}
+
+func TestUpdate(t *testing.T) {
+	createdAt := time.Now().Add(-time.Hour)
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	mockRepo := commentMock.NewRepository(mockCtrl)
+	mockTaskService := taskMock.NewService(mockCtrl)
+	commentService := service.New(mockRepo, mockTaskService)
+
+	existingComment := &models.Comment{
+		ID:        7,
+		Task:      &models.Task{ID: 5},
+		Body:      "looks good",
+		CreatedBy: &models.User{ID: 1},
+		CreatedAt: createdAt,
+	}
+
+	editedAt := time.Now()
+	updatedComment := &models.Comment{
+		ID:       7,
+		Task:     &models.Task{ID: 5},
+		Body:     "looks great",
+		EditedAt: &editedAt,
+	}
+
+	gomock.InOrder(
+		mockTaskService.EXPECT().GetByID(ctx, int64(5), int64(1)).Return(&models.Task{ID: 5}, nil),
+		mockRepo.EXPECT().GetByID(ctx, int64(7)).Return(existingComment, nil),
+		mockRepo.EXPECT().Update(ctx, updatedComment).Return(nil),
+	)
+
+	err := commentService.Update(ctx, updatedComment, 1)
+
+	assert.NoError(err)
+	assert.Equal(int64(1), updatedComment.CreatedBy.ID)
+	assert.Equal(createdAt, updatedComment.CreatedAt)
+}
+
+func TestUpdateCommentOfOtherUser(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	mockRepo := commentMock.NewRepository(mockCtrl)
+	mockTaskService := taskMock.NewService(mockCtrl)
+	commentService := service.New(mockRepo, mockTaskService)
+
+	mockTaskService.EXPECT().GetByID(ctx, int64(5), int64(1)).Return(&models.Task{ID: 5}, nil).Times(1)
+	mockRepo.
+		EXPECT().
+		GetByID(ctx, int64(7)).
+		Return(&models.Comment{ID: 7, Task: &models.Task{ID: 5}, CreatedBy: &models.User{ID: 2}}, nil).
+		Times(1)
+
+	err := commentService.Update(ctx, &models.Comment{ID: 7, Task: &models.Task{ID: 5}, Body: "changed"}, 1)
+
+	assert.Equal(&todoErr.PermissionDeniedError{Resource: "comment", Action: "edit"}, err)
+}
+
+func TestDeleteCommentOfOtherTask(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	mockRepo := commentMock.NewRepository(mockCtrl)
+	mockTaskService := taskMock.NewService(mockCtrl)
+	commentService := service.New(mockRepo, mockTaskService)
+
+	mockTaskService.EXPECT().GetByID(ctx, int64(5), int64(1)).Return(&models.Task{ID: 5}, nil).Times(1)
+	mockRepo.
+		EXPECT().
+		GetByID(ctx, int64(7)).
+		Return(&models.Comment{ID: 7, Task: &models.Task{ID: 6}, CreatedBy: &models.User{ID: 1}}, nil).
+		Times(1)
+
+	err := commentService.Delete(ctx, 5, 7, 1)
+
+	assert.Equal(&todoErr.ResourceNotFoundError{Resource: "comment"}, err)
+}
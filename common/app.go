@@ -3,18 +3,26 @@ package common
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 
+	"github.com/dheerajgopi/todo-api/apikey"
+	"github.com/dheerajgopi/todo-api/auth"
+	"github.com/dheerajgopi/todo-api/auth/signing"
 	todoErr "github.com/dheerajgopi/todo-api/common/error"
 	"github.com/dheerajgopi/todo-api/config"
 	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 )
 
-// App stores app config
+// App stores app config, along with the keys of access tokens, the store of revoked access tokens
+// and the API keys which are accepted instead of access tokens
 type App struct {
-	Logger *logrus.Logger
-	Config *config.Config
+	Logger      *logrus.Logger
+	Config      *config.Config
+	Keys        *signing.KeySet
+	Revocations auth.RevocationStore
+	APIKeys     apikey.Service
 }
 
 // CreateHandler creates a new HandlerFunc with a new RequestContext per request
@@ -45,6 +53,10 @@ func (app *App) CreateHandler(fn HandlerFunc) func(res http.ResponseWriter, req
 			reqCtx.Response.Status = 201
 			reqCtx.Response.Data = data
 			reqCtx.LogInfo()
+		case http.StatusNotModified:
+			reqCtx.Response.Status = 304
+			reqCtx.Response.Data = data
+			reqCtx.LogInfo()
 		case http.StatusBadRequest:
 			reqCtx.Response.Status = 400
 			reqCtx.Response.Errors = apiError.Body
@@ -64,6 +76,10 @@ func (app *App) CreateHandler(fn HandlerFunc) func(res http.ResponseWriter, req
 			reqCtx.Response.Errors = apiError.Body
 			reqCtx.LogEntry = reqCtx.LogEntry.WithError(apiError)
 			reqCtx.LogWarn()
+		case http.StatusTooManyRequests:
+			reqCtx.Response.Status = 429
+			reqCtx.Response.Errors = apiError.Body
+			reqCtx.LogWarn()
 		default:
 			reqCtx.Response.Status = 500
 			reqCtx.Response.Errors = apiError.Body
@@ -71,6 +87,11 @@ func (app *App) CreateHandler(fn HandlerFunc) func(res http.ResponseWriter, req
 			reqCtx.LogError()
 		}
 
+		if rawResponse, ok := reqCtx.Response.Data.(*RawResponse); ok {
+			writeRawResponse(res, reqCtx, rawResponse)
+			return
+		}
+
 		response, _ := json.Marshal(reqCtx.Response)
 
 		res.Header().Set("Content-Type", "application/json")
@@ -80,6 +101,23 @@ func (app *App) CreateHandler(fn HandlerFunc) func(res http.ResponseWriter, req
 	}
 }
 
+// writeRawResponse copies the body of a raw response to the client, along with its headers
+func writeRawResponse(res http.ResponseWriter, reqCtx *RequestContext, rawResponse *RawResponse) {
+	defer rawResponse.Body.Close()
+
+	for name, value := range rawResponse.Headers {
+		res.Header().Set(name, value)
+	}
+
+	res.Header().Set("Content-Type", rawResponse.ContentType)
+	res.Header().Set("X-Request-ID", reqCtx.RequestID)
+	res.WriteHeader(reqCtx.Response.Status)
+
+	if _, err := io.Copy(res, rawResponse.Body); err != nil {
+		reqCtx.LogEntry.WithError(err).Warn("Error writing response body")
+	}
+}
+
 // NewRequestContext creates new struct to store request scoped data
 func (app *App) newRequestContext() *RequestContext {
 	requestID, _ := uuid.NewUUID()
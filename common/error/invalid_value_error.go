@@ -0,0 +1,14 @@
+package error
+
+import "fmt"
+
+// InvalidValueError indicates a value which is not allowed for a field of a resource in its current state
+type InvalidValueError struct {
+	Resource string
+	Field    string
+	Reason   string
+}
+
+func (ive *InvalidValueError) Error() string {
+	return fmt.Sprintf("Invalid value for %s field in %s resource: %s", ive.Field, ive.Resource, ive.Reason)
+}
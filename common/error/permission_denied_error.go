@@ -0,0 +1,13 @@
+package error
+
+import "fmt"
+
+// PermissionDeniedError indicates an action which is not allowed on a resource the user has access to
+type PermissionDeniedError struct {
+	Resource string
+	Action   string
+}
+
+func (pde *PermissionDeniedError) Error() string {
+	return fmt.Sprintf("permission denied to %s %s", pde.Action, pde.Resource)
+}
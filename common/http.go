@@ -1,6 +1,10 @@
 package common
 
-import todoErr "github.com/dheerajgopi/todo-api/common/error"
+import (
+	"io"
+
+	todoErr "github.com/dheerajgopi/todo-api/common/error"
+)
 
 // APIResponse represents json structure of API response
 type APIResponse struct {
@@ -8,3 +12,11 @@ type APIResponse struct {
 	Errors []*todoErr.APIErrorBody `json:"errors"`
 	Data   interface{}             `json:"data"`
 }
+
+// RawResponse is returned as data by handlers which respond with a file instead of the json structure.
+// Body is copied to the response as is, and closed afterwards.
+type RawResponse struct {
+	ContentType string
+	Headers     map[string]string
+	Body        io.ReadCloser
+}
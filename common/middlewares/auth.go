@@ -2,45 +2,180 @@ package middlewares
 
 import (
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/dgrijalva/jwt-go"
+	"github.com/dheerajgopi/todo-api/apikey"
+	"github.com/dheerajgopi/todo-api/auth"
 	"github.com/dheerajgopi/todo-api/common"
 	todoErr "github.com/dheerajgopi/todo-api/common/error"
+	"github.com/dheerajgopi/todo-api/config"
 )
 
-// JwtValidator middleware validates the token in the Authorization header.
-// It responds with 403 error in case of invalid or missing token.
-func JwtValidator(secret string) MiddlewareFunc {
+// JwtValidator middleware validates the token in the Authorization header against the key set of the app.
+// It responds with 403 error in case of invalid, missing or revoked token, or if the user of the token is deleted or deactivated.
+// Tokens without id, session or expiry are rejected, since they can not be revoked.
+// Users who have not verified their email are restricted as the account settings describe.
+func JwtValidator(app *common.App) MiddlewareFunc {
+	return jwtValidator(app, true)
+}
+
+// UnverifiedJwtValidator middleware validates the token like JwtValidator, but lets the users who have
+// not verified their email through. It suits the actions which such users should always be able to take,
+// like logging out.
+func UnverifiedJwtValidator(app *common.App) MiddlewareFunc {
+	return jwtValidator(app, false)
+}
+
+func jwtValidator(app *common.App, restrictUnverified bool) MiddlewareFunc {
 	return func(f common.HandlerFunc) common.HandlerFunc {
 		return func(res http.ResponseWriter, req *http.Request, reqCtx *common.RequestContext) (int, interface{}, *todoErr.APIError) {
 			authHeader := req.Header["Authorization"]
 
 			if authHeader == nil {
-				err := todoErr.UnauthorizedError{}
+				return accessDenied()
+			}
+
+			token, err := app.Keys.Parse(authHeader[0])
+
+			if err != nil || !token.Valid {
+				return accessDenied()
+			}
+
+			claims, _ := token.Claims.(jwt.MapClaims)
+			userID, hasUser := claims[auth.ClaimUserID].(float64)
+			tokenID, _ := claims["jti"].(string)
+			sessionID, _ := claims[auth.ClaimSessionID].(string)
+			expiresAt, hasExpiry := claims["exp"].(float64)
+
+			if !hasUser || !hasExpiry || tokenID == "" || sessionID == "" {
+				return accessDenied()
+			}
+
+			if verified, ok := claims[auth.ClaimVerified].(bool); ok && !verified && restrictUnverified {
+				if !unverifiedAllowed(app.Config.Account.UnverifiedAccess, req.Method) {
+					apiError := todoErr.NewAPIError("email not verified", &todoErr.APIErrorBody{
+						Message: "Email not verified",
+						Target:  "email",
+					})
+
+					return http.StatusForbidden, nil, apiError
+				}
+			}
+
+			revoked, err := app.Revocations.IsRevoked(req.Context(), tokenID, sessionID, auth.UserRevocationID(int64(userID)))
+
+			if err != nil {
 				apiError := todoErr.NewAPIError(err.Error(), &todoErr.APIErrorBody{
-					Message: "Access denied",
+					Message: "Internal server error",
 				})
 
-				return 403, nil, apiError
+				return http.StatusInternalServerError, nil, apiError
 			}
 
-			token, err := jwt.Parse(authHeader[0], func(token *jwt.Token) (interface{}, error) {
-				return []byte(secret), nil
-			})
+			if revoked {
+				return accessDenied()
+			}
+
+			reqCtx.UserID = int64(userID)
+			reqCtx.TokenID = tokenID
+			reqCtx.SessionID = sessionID
+			reqCtx.TokenExpiresAt = time.Unix(int64(expiresAt), 0)
+			reqCtx.Scopes = auth.Scopes
+
+			return f(res, req, reqCtx)
+		}
+	}
+}
+
+// ScopedValidator middleware accepts either an access token, which it validates like JwtValidator,
+// or an API key which grants the scope. It suits the endpoints which scripts call, like /tasks.
+// Users who have not verified their email are restricted the same way with either credential.
+func ScopedValidator(app *common.App, scope string) MiddlewareFunc {
+	tokenValidator := JwtValidator(app)
+
+	return func(f common.HandlerFunc) common.HandlerFunc {
+		withToken := tokenValidator(f)
 
-			if !token.Valid || err != nil {
-				err := todoErr.UnauthorizedError{}
+		return func(res http.ResponseWriter, req *http.Request, reqCtx *common.RequestContext) (int, interface{}, *todoErr.APIError) {
+			authHeader := req.Header["Authorization"]
+
+			if authHeader == nil || !strings.HasPrefix(authHeader[0], apikey.KeyPrefix) {
+				return withToken(res, req, reqCtx)
+			}
+
+			key, err := app.APIKeys.Authenticate(req.Context(), authHeader[0])
+
+			switch err.(type) {
+			case nil:
+				break
+			case *todoErr.UnauthorizedError:
+				return accessDenied()
+			default:
 				apiError := todoErr.NewAPIError(err.Error(), &todoErr.APIErrorBody{
-					Message: "Access denied",
+					Message: "Internal server error",
 				})
 
-				return 403, nil, apiError
+				return http.StatusInternalServerError, nil, apiError
 			}
 
-			claims, _ := token.Claims.(jwt.MapClaims)
-			reqCtx.UserID = int64(claims["userId"].(float64))
+			if !hasScope(key.Scopes, scope) {
+				apiError := todoErr.NewAPIError("missing scope "+scope, &todoErr.APIErrorBody{
+					Message: "Insufficient scope",
+					Target:  scope,
+				})
+
+				return http.StatusForbidden, nil, apiError
+			}
+
+			if !key.User.IsActive && !unverifiedAllowed(app.Config.Account.UnverifiedAccess, req.Method) {
+				apiError := todoErr.NewAPIError("email not verified", &todoErr.APIErrorBody{
+					Message: "Email not verified",
+					Target:  "email",
+				})
+
+				return http.StatusForbidden, nil, apiError
+			}
+
+			reqCtx.UserID = key.User.ID
+			reqCtx.APIKeyID = key.ID
+			reqCtx.Scopes = key.Scopes
 
 			return f(res, req, reqCtx)
 		}
 	}
 }
+
+// hasScope tells whether the granted scopes include the scope
+func hasScope(granted []string, scope string) bool {
+	for _, grantedScope := range granted {
+		if grantedScope == scope {
+			return true
+		}
+	}
+
+	return false
+}
+
+// unverifiedAllowed tells whether an user who has not verified the email can make a request with the method
+func unverifiedAllowed(access string, method string) bool {
+	switch access {
+	case config.UnverifiedFullAccess:
+		return true
+	case config.UnverifiedReadOnly:
+		return method == http.MethodGet || method == http.MethodHead
+	default:
+		return false
+	}
+}
+
+// accessDenied responds with 403 error
+func accessDenied() (int, interface{}, *todoErr.APIError) {
+	err := todoErr.UnauthorizedError{}
+	apiError := todoErr.NewAPIError(err.Error(), &todoErr.APIErrorBody{
+		Message: "Access denied",
+	})
+
+	return http.StatusForbidden, nil, apiError
+}
@@ -0,0 +1,179 @@
+package middlewares_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	apiKeyMock "github.com/dheerajgopi/todo-api/apikey/mock"
+	"github.com/dheerajgopi/todo-api/auth"
+	authMock "github.com/dheerajgopi/todo-api/auth/mock"
+	"github.com/dheerajgopi/todo-api/auth/signing"
+	"github.com/dheerajgopi/todo-api/common"
+	_errors "github.com/dheerajgopi/todo-api/common/error"
+	"github.com/dheerajgopi/todo-api/common/middlewares"
+	"github.com/dheerajgopi/todo-api/config"
+	"github.com/dheerajgopi/todo-api/models"
+	"github.com/golang/mock/gomock"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScopedValidatorWithAccessToken(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	app, _ := setupApp(mockCtrl)
+	revocations := authMock.NewRevocationStore(mockCtrl)
+	app.Revocations = revocations
+
+	token, _ := app.Keys.Sign(jwt.MapClaims{
+		"jti":               "token",
+		"exp":               time.Now().Add(time.Hour).Unix(),
+		auth.ClaimUserID:    1,
+		auth.ClaimSessionID: "session",
+		auth.ClaimVerified:  true,
+	})
+
+	revocations.EXPECT().IsRevoked(gomock.Any(), "token", "session", "user:1").Return(false, nil)
+
+	req := httptest.NewRequest("POST", "/tasks", nil)
+	req.Header.Set("Authorization", token)
+	reqCtx := setupRequestContext(app)
+
+	status, _, _ := middlewares.ScopedValidator(app, auth.ScopeTasksWrite)(echo)(httptest.NewRecorder(), req, reqCtx)
+
+	assert.Equal(200, status)
+	assert.Equal(int64(1), reqCtx.UserID)
+	assert.Equal("session", reqCtx.SessionID)
+	assert.Equal(auth.Scopes, reqCtx.Scopes)
+}
+
+func TestScopedValidatorWithAPIKey(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	app, apiKeys := setupApp(mockCtrl)
+
+	apiKeys.
+		EXPECT().
+		Authenticate(gomock.Any(), "todo_key").
+		Return(&models.APIKey{ID: 2, User: &models.User{ID: 1, IsActive: true}, Scopes: []string{auth.ScopeTasksRead}}, nil).
+		Times(2)
+
+	req := httptest.NewRequest("GET", "/tasks", nil)
+	req.Header.Set("Authorization", "todo_key")
+	reqCtx := setupRequestContext(app)
+
+	status, _, _ := middlewares.ScopedValidator(app, auth.ScopeTasksRead)(echo)(httptest.NewRecorder(), req, reqCtx)
+
+	assert.Equal(200, status)
+	assert.Equal(int64(1), reqCtx.UserID)
+	assert.Equal(int64(2), reqCtx.APIKeyID)
+	assert.Equal([]string{auth.ScopeTasksRead}, reqCtx.Scopes)
+
+	req = httptest.NewRequest("POST", "/tasks", nil)
+	req.Header.Set("Authorization", "todo_key")
+
+	status, _, err := middlewares.ScopedValidator(app, auth.ScopeTasksWrite)(echo)(httptest.NewRecorder(), req, setupRequestContext(app))
+
+	assert.Equal(403, status)
+	assert.Equal("Insufficient scope", err.Body[0].Message)
+	assert.Equal(auth.ScopeTasksWrite, err.Body[0].Target)
+}
+
+func TestScopedValidatorWithInvalidAPIKey(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	app, apiKeys := setupApp(mockCtrl)
+
+	apiKeys.EXPECT().Authenticate(gomock.Any(), "todo_revoked").Return(nil, &_errors.UnauthorizedError{})
+
+	req := httptest.NewRequest("GET", "/tasks", nil)
+	req.Header.Set("Authorization", "todo_revoked")
+
+	status, _, err := middlewares.ScopedValidator(app, auth.ScopeTasksRead)(echo)(httptest.NewRecorder(), req, setupRequestContext(app))
+
+	assert.Equal(403, status)
+	assert.Equal("Access denied", err.Body[0].Message)
+}
+
+func TestJwtValidatorWithAPIKey(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	app, _ := setupApp(mockCtrl)
+
+	req := httptest.NewRequest("GET", "/users/me/api-keys", nil)
+	req.Header.Set("Authorization", "todo_key")
+
+	status, _, _ := middlewares.JwtValidator(app)(echo)(httptest.NewRecorder(), req, setupRequestContext(app))
+
+	assert.Equal(t, 403, status)
+}
+
+func TestJwtValidatorWithDeactivatedUser(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	app, _ := setupApp(mockCtrl)
+	revocations := authMock.NewRevocationStore(mockCtrl)
+	app.Revocations = revocations
+
+	token, _ := app.Keys.Sign(jwt.MapClaims{
+		"jti":               "token",
+		"exp":               time.Now().Add(time.Hour).Unix(),
+		auth.ClaimUserID:    1,
+		auth.ClaimSessionID: "session",
+		auth.ClaimVerified:  true,
+	})
+
+	// deactivation revokes every token of the user at once
+	revocations.EXPECT().IsRevoked(gomock.Any(), "token", "session", "user:1").Return(true, nil)
+
+	req := httptest.NewRequest("GET", "/users/me", nil)
+	req.Header.Set("Authorization", token)
+
+	status, _, _ := middlewares.JwtValidator(app)(echo)(httptest.NewRecorder(), req, setupRequestContext(app))
+
+	assert.Equal(t, 403, status)
+}
+
+// echo is a handler which responds with 200
+func echo(res http.ResponseWriter, req *http.Request, reqCtx *common.RequestContext) (int, interface{}, *_errors.APIError) {
+	return http.StatusOK, nil, nil
+}
+
+func setupApp(mockCtrl *gomock.Controller) (*common.App, *apiKeyMock.Service) {
+	apiKeys := apiKeyMock.NewService(mockCtrl)
+
+	app := &common.App{
+		Logger: logrus.New(),
+		Config: &config.Config{
+			Account: &config.AccountSetting{
+				UnverifiedAccess: config.UnverifiedReadOnly,
+			},
+		},
+		Keys:    signing.NewSecret("secret"),
+		APIKeys: apiKeys,
+	}
+
+	return app, apiKeys
+}
+
+func setupRequestContext(app *common.App) *common.RequestContext {
+	reqCtx := &common.RequestContext{
+		RequestID: "dummyRequestID",
+		LogEntry: app.Logger.WithFields(
+			logrus.Fields{},
+		),
+	}
+
+	return reqCtx
+}
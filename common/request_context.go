@@ -2,16 +2,24 @@ package common
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/sirupsen/logrus"
 )
 
-// RequestContext stores request scoped data
+// RequestContext stores request scoped data.
+// Id, session and expiry of the access token are set along with the user, once the token is validated.
+// If an API key is sent instead, its id is set along with the user. Scopes are the scopes granted to the credential.
 type RequestContext struct {
-	RequestID string
-	Response  *APIResponse
-	LogEntry  *logrus.Entry
-	UserID    int64
+	RequestID      string
+	Response       *APIResponse
+	LogEntry       *logrus.Entry
+	UserID         int64
+	TokenID        string
+	SessionID      string
+	TokenExpiresAt time.Time
+	APIKeyID       int64
+	Scopes         []string
 }
 
 // AddLogFields will add the specified fields to the LogEntry
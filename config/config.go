@@ -13,12 +13,19 @@ type Config struct {
 	Application *ApplicationSetting `json:"application"`
 	Database    *DatabaseSetting    `json:"database"`
 	Auth        *AuthSetting        `json:"auth"`
+	Attachment  *AttachmentSetting  `json:"attachment"`
+	Search      *SearchSetting      `json:"search"`
+	Account     *AccountSetting     `json:"account"`
+	Mail        *MailSetting        `json:"mail"`
 }
 
-// ApplicationSetting holds all general application configurations
+// ApplicationSetting holds all general application configurations.
+// Deleted tasks and users are purged once they are in trash for longer than the retention period.
 type ApplicationSetting struct {
-	Port           int `json:"port"`
-	RequestTimeout int `json:"requestTimeout"`
+	Port                     int `json:"port"`
+	RequestTimeout           int `json:"requestTimeout"`
+	TrashRetentionInDays     int `json:"trashRetentionInDays"`
+	TrashPurgeIntervalInMins int `json:"trashPurgeIntervalInMins"`
 }
 
 // DatabaseSetting holds all database configurations
@@ -31,13 +38,184 @@ type DatabaseSetting struct {
 
 // AuthSetting holds all auth related configurations
 type AuthSetting struct {
-	Jwt *JwtSetting `json:"jwt"`
+	Jwt        *JwtSetting        `json:"jwt"`
+	Revocation *RevocationSetting `json:"revocation"`
+	Login      *LoginSetting      `json:"login"`
+	Mfa        *MfaSetting        `json:"mfa"`
+	Oidc       *OidcSetting       `json:"oidc"`
 }
 
-// JwtSetting holds all JWT related configurations
+// JwtSetting holds all JWT related configurations.
+// Access tokens expire after ExpiryInSeconds, and the refresh tokens which renew them after RefreshExpiryInSeconds.
+// Tokens are signed with the key having SigningKeyID (first key by default), or with the HS256 secret if there are no keys.
 type JwtSetting struct {
-	Secret          string `json:"secret"`
-	ExpiryInSeconds int    `json:"expiryInSeconds"`
+	Secret                 string           `json:"secret"`
+	ExpiryInSeconds        int              `json:"expiryInSeconds"`
+	RefreshExpiryInSeconds int              `json:"refreshExpiryInSeconds"`
+	Keys                   []*JwtKeySetting `json:"keys"`
+	SigningKeyID           string           `json:"signingKeyId"`
+}
+
+// JwtKeySetting holds the id of a JWT key, which is sent in the kid header, and its PEM file.
+// File may hold a public key only, for keys which are kept to validate tokens after rotation.
+type JwtKeySetting struct {
+	ID   string `json:"id"`
+	File string `json:"file"`
+}
+
+// Supported types of revocation store
+const (
+	MySQLRevocation  = "mysql"
+	MemoryRevocation = "memory"
+)
+
+// RevocationSetting holds the configurations of revoked access tokens.
+// Memory store is not shared between instances, and fits single instance deployments only.
+type RevocationSetting struct {
+	Type string `json:"type"`
+}
+
+// Supported types of login attempt store
+const (
+	MySQLLoginAttempts  = "mysql"
+	MemoryLoginAttempts = "memory"
+)
+
+// LoginSetting holds the brute-force protection of login, which tracks failed attempts per account and per IP address.
+// Each failure past the free attempts doubles the delay before the next attempt, starting from BaseDelayInSeconds,
+// and the account or IP address is locked out for LockoutInMinutes once it reaches the lockout attempts.
+// Failures are forgotten after WindowInMinutes without failures.
+// Client IP address is taken from ClientIPHeader (like X-Real-IP) if set, which only a trusted proxy should set.
+type LoginSetting struct {
+	Store                  string `json:"store"`
+	AccountFreeAttempts    int    `json:"accountFreeAttempts"`
+	AccountLockoutAttempts int    `json:"accountLockoutAttempts"`
+	IPFreeAttempts         int    `json:"ipFreeAttempts"`
+	IPLockoutAttempts      int    `json:"ipLockoutAttempts"`
+	BaseDelayInSeconds     int    `json:"baseDelayInSeconds"`
+	LockoutInMinutes       int    `json:"lockoutInMinutes"`
+	WindowInMinutes        int    `json:"windowInMinutes"`
+	ClientIPHeader         string `json:"clientIpHeader"`
+}
+
+// MfaSetting holds configurations of TOTP two-factor authentication.
+// EncryptionKey is the base64 encoded 32 byte AES key which encrypts the TOTP secrets at rest. It is never marshalled.
+// Challenge tokens, which stand between the password and the code steps of a login, expire after ChallengeExpiryInSeconds.
+type MfaSetting struct {
+	Issuer                   string `json:"issuer"`
+	EncryptionKey            string `json:"-"`
+	ChallengeExpiryInSeconds int    `json:"challengeExpiryInSeconds"`
+	RecoveryCodeCount        int    `json:"recoveryCodeCount"`
+}
+
+// OidcSetting holds configurations of the login with an external OpenID Connect provider, which is off unless Issuer is set.
+// Endpoints and keys of the provider are discovered from Issuer. Users are linked by verified email,
+// and users of unknown emails are created only if AutoProvision is set.
+// Login states, which keep the PKCE verifier between the steps of a login, expire after StateExpiryInSeconds.
+// ClientSecret is never marshalled.
+type OidcSetting struct {
+	Issuer               string   `json:"issuer"`
+	ClientID             string   `json:"clientId"`
+	ClientSecret         string   `json:"-"`
+	RedirectURL          string   `json:"redirectUrl"`
+	Scopes               []string `json:"scopes"`
+	AutoProvision        bool     `json:"autoProvision"`
+	StateExpiryInSeconds int      `json:"stateExpiryInSeconds"`
+}
+
+// AttachmentSetting holds all configurations for task attachments.
+// Quota is the total size of the attachments which an user can upload.
+type AttachmentSetting struct {
+	MaxSizeInBytes int64           `json:"maxSizeInBytes"`
+	QuotaInBytes   int64           `json:"quotaInBytes"`
+	ContentTypes   []string        `json:"contentTypes"`
+	Storage        *StorageSetting `json:"storage"`
+}
+
+// Supported types of blob storage
+const (
+	LocalStorage = "local"
+	S3Storage    = "s3"
+)
+
+// StorageSetting holds blob storage configurations. Only the section of the chosen storage type is used.
+type StorageSetting struct {
+	Type  string               `json:"type"`
+	Local *LocalStorageSetting `json:"local"`
+	S3    *S3StorageSetting    `json:"s3"`
+}
+
+// LocalStorageSetting holds configurations for storing blobs in the local filesystem
+type LocalStorageSetting struct {
+	Directory string `json:"directory"`
+}
+
+// S3StorageSetting holds configurations for storing blobs in S3, or in any S3 compatible storage.
+// Path style addressing puts the bucket in the path instead of the host name, which most S3 compatible storages need.
+// Access key and secret key are never marshalled.
+type S3StorageSetting struct {
+	Endpoint  string `json:"endpoint"`
+	Region    string `json:"region"`
+	Bucket    string `json:"bucket"`
+	AccessKey string `json:"-"`
+	SecretKey string `json:"-"`
+	PathStyle bool   `json:"pathStyle"`
+}
+
+// Supported types of search index
+const (
+	MySQLSearch  = "mysql"
+	MemorySearch = "memory"
+)
+
+// SearchSetting holds search configurations. Memory index is rebuilt at startup, and fits small deployments only.
+type SearchSetting struct {
+	Type string `json:"type"`
+}
+
+// Access levels of the users who have not verified their email yet
+const (
+	UnverifiedFullAccess = "full"
+	UnverifiedReadOnly   = "readOnly"
+	UnverifiedNoAccess   = "none"
+)
+
+// AccountSetting holds configurations of email verification, password reset and email change.
+// Unverified users can use the whole API (full), only read (readOnly), or can not log in (none).
+// Links in the mails are built by replacing {token} in the urls.
+type AccountSetting struct {
+	UnverifiedAccess             string `json:"unverifiedAccess"`
+	VerificationExpiryInHours    int    `json:"verificationExpiryInHours"`
+	PasswordResetExpiryInMinutes int    `json:"passwordResetExpiryInMinutes"`
+	EmailChangeExpiryInHours     int    `json:"emailChangeExpiryInHours"`
+	VerificationURL              string `json:"verificationUrl"`
+	PasswordResetURL             string `json:"passwordResetUrl"`
+	EmailChangeURL               string `json:"emailChangeUrl"`
+}
+
+// Supported types of mailer
+const (
+	SMTPMailer   = "smtp"
+	MemoryMailer = "memory"
+)
+
+// MailSetting holds mail delivery configurations.
+// Memory mailer keeps the mails instead of delivering them, and fits tests and local development only.
+type MailSetting struct {
+	Type string       `json:"type"`
+	From string       `json:"from"`
+	SMTP *SMTPSetting `json:"smtp"`
+}
+
+// SMTPSetting holds configurations of the SMTP server which delivers mails.
+// ImplicitTLS is for servers which expect TLS from the start (usually on port 465), instead of STARTTLS.
+// Password is never marshalled.
+type SMTPSetting struct {
+	Host        string `json:"host"`
+	Port        int    `json:"port"`
+	Username    string `json:"username"`
+	Password    string `json:"-"`
+	ImplicitTLS bool   `json:"implicitTls"`
 }
 
 // Load will fetch configuration from environment specific file and populate the configuration struct.
@@ -72,6 +250,22 @@ func (config *Config) Load() error {
 		return err
 	}
 
+	if err := config.configureAttachment(viperRegistry); err != nil {
+		return err
+	}
+
+	if err := config.configureSearch(viperRegistry); err != nil {
+		return err
+	}
+
+	if err := config.configureAccount(viperRegistry); err != nil {
+		return err
+	}
+
+	if err := config.configureMail(viperRegistry); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -125,7 +319,8 @@ func (config *Config) configureDB(viperRegistry *viper.Viper) error {
 }
 
 // configureApplication will load general application configurations.
-// Port and request timeout values are optional (default values are applied).
+// Port, request timeout and trash purge values are optional (default values are applied).
+// Trash purge values should be positive.
 func (config *Config) configureApplication(viperRegistry *viper.Viper) error {
 	appConfig := &ApplicationSetting{}
 	appSettings := viperRegistry.Sub("application")
@@ -146,14 +341,30 @@ func (config *Config) configureApplication(viperRegistry *viper.Viper) error {
 		appConfig.RequestTimeout = 10
 	}
 
+	if !appSettings.IsSet("trashRetentionInDays") {
+		appConfig.TrashRetentionInDays = 30
+	}
+
+	if !appSettings.IsSet("trashPurgeIntervalInMins") {
+		appConfig.TrashPurgeIntervalInMins = 60
+	}
+
+	if appConfig.TrashRetentionInDays <= 0 || appConfig.TrashPurgeIntervalInMins <= 0 {
+		return errors.New("trash retention and purge interval should be positive")
+	}
+
 	config.Application = appConfig
 
 	return nil
 }
 
 // configureAuth loads auth specific configurations.
-// JWT secret is taken from OS environment variable, if missing.
-// JWT expiry time is optional (defaults applied).
+// JWT secret is taken from OS environment variable, if missing. Secret is not needed if JWT keys are configured.
+// JWT and refresh token expiry times are optional (defaults applied).
+// Revocation section is optional, and MySQL is used by default.
+// Login section is optional (defaults applied), and failed attempts are kept in MySQL by default.
+// MFA section is optional (defaults applied), but its encryption key is taken from OS environment variable, if missing.
+// OIDC section is optional. Client secret is taken from OS environment variable, if missing, and is not needed by public clients.
 func (config *Config) configureAuth(viperRegistry *viper.Viper) error {
 	authConfig := &AuthSetting{}
 	authSettings := viperRegistry.Sub("auth")
@@ -174,18 +385,312 @@ func (config *Config) configureAuth(viperRegistry *viper.Viper) error {
 		return err
 	}
 
-	if !jwtSettings.IsSet("secret") {
+	if len(jwtConfig.Keys) == 0 && !jwtSettings.IsSet("secret") {
 		if !viperRegistry.IsSet("AUTH_JWT_SECRET") {
 			return errors.New("jwt secret not set")
 		}
 	}
 
+	for _, key := range jwtConfig.Keys {
+		if key.ID == "" || key.File == "" {
+			return errors.New("jwt keys should have id and file")
+		}
+	}
+
 	if !jwtSettings.IsSet("expiryInSeconds") {
 		jwtConfig.ExpiryInSeconds = 3600
 	}
 
+	if !jwtSettings.IsSet("refreshExpiryInSeconds") {
+		jwtConfig.RefreshExpiryInSeconds = 30 * 24 * 3600
+	}
+
+	revocationConfig := &RevocationSetting{
+		Type: MySQLRevocation,
+	}
+
+	if revocationSettings := authSettings.Sub("revocation"); revocationSettings != nil {
+		if err := revocationSettings.Unmarshal(revocationConfig); err != nil {
+			return err
+		}
+	}
+
+	if revocationConfig.Type != MySQLRevocation && revocationConfig.Type != MemoryRevocation {
+		return errors.New("revocation type should be one of mysql, memory")
+	}
+
+	loginConfig := &LoginSetting{
+		Store:                  MySQLLoginAttempts,
+		AccountFreeAttempts:    3,
+		AccountLockoutAttempts: 10,
+		IPFreeAttempts:         20,
+		IPLockoutAttempts:      100,
+		BaseDelayInSeconds:     1,
+		LockoutInMinutes:       15,
+		WindowInMinutes:        15,
+	}
+
+	if loginSettings := authSettings.Sub("login"); loginSettings != nil {
+		if err := loginSettings.Unmarshal(loginConfig); err != nil {
+			return err
+		}
+	}
+
+	if loginConfig.Store != MySQLLoginAttempts && loginConfig.Store != MemoryLoginAttempts {
+		return errors.New("login store should be one of mysql, memory")
+	}
+
+	if loginConfig.AccountLockoutAttempts <= loginConfig.AccountFreeAttempts ||
+		loginConfig.IPLockoutAttempts <= loginConfig.IPFreeAttempts {
+		return errors.New("login lockout attempts should be more than free attempts")
+	}
+
+	if loginConfig.BaseDelayInSeconds <= 0 || loginConfig.LockoutInMinutes <= 0 || loginConfig.WindowInMinutes <= 0 {
+		return errors.New("login delay, lockout and window should be positive")
+	}
+
+	mfaConfig := &MfaSetting{
+		Issuer:                   "todo-api",
+		ChallengeExpiryInSeconds: 300,
+		RecoveryCodeCount:        10,
+	}
+
+	if mfaSettings := authSettings.Sub("mfa"); mfaSettings != nil {
+		if err := mfaSettings.Unmarshal(mfaConfig); err != nil {
+			return err
+		}
+	}
+
+	if mfaConfig.EncryptionKey == "" {
+		if !viperRegistry.IsSet("MFA_ENCRYPTION_KEY") {
+			return errors.New("mfa encryption key not set")
+		}
+
+		mfaConfig.EncryptionKey = viperRegistry.GetString("MFA_ENCRYPTION_KEY")
+	}
+
+	if mfaConfig.ChallengeExpiryInSeconds <= 0 || mfaConfig.RecoveryCodeCount <= 0 {
+		return errors.New("mfa challenge expiry and recovery code count should be positive")
+	}
+
+	oidcConfig := &OidcSetting{
+		Scopes:               []string{"openid", "email", "profile"},
+		StateExpiryInSeconds: 600,
+	}
+
+	if oidcSettings := authSettings.Sub("oidc"); oidcSettings != nil {
+		if err := oidcSettings.Unmarshal(oidcConfig); err != nil {
+			return err
+		}
+	}
+
+	if oidcConfig.Issuer != "" {
+		if oidcConfig.ClientID == "" || oidcConfig.RedirectURL == "" {
+			return errors.New("oidc client id and redirect url not set")
+		}
+
+		if oidcConfig.ClientSecret == "" {
+			oidcConfig.ClientSecret = viperRegistry.GetString("OIDC_CLIENT_SECRET")
+		}
+
+		if !containsString(oidcConfig.Scopes, "openid") {
+			return errors.New("oidc scopes should include openid")
+		}
+
+		if oidcConfig.StateExpiryInSeconds <= 0 {
+			return errors.New("oidc state expiry should be positive")
+		}
+	}
+
 	authConfig.Jwt = jwtConfig
+	authConfig.Revocation = revocationConfig
+	authConfig.Login = loginConfig
+	authConfig.Mfa = mfaConfig
+	authConfig.Oidc = oidcConfig
 	config.Auth = authConfig
 
 	return nil
 }
+
+// configureAttachment loads attachment specific configurations.
+// Whole section is optional, and attachments are stored in the local filesystem by default.
+// S3 access key and secret key are taken from OS environment variables, if missing.
+func (config *Config) configureAttachment(viperRegistry *viper.Viper) error {
+	attachmentConfig := &AttachmentSetting{
+		MaxSizeInBytes: 10 << 20,
+		QuotaInBytes:   100 << 20,
+		ContentTypes:   []string{"image/png", "image/jpeg", "image/gif", "application/pdf", "text/plain"},
+		Storage: &StorageSetting{
+			Type: LocalStorage,
+			Local: &LocalStorageSetting{
+				Directory: "attachments",
+			},
+		},
+	}
+
+	config.Attachment = attachmentConfig
+	attachmentSettings := viperRegistry.Sub("attachment")
+
+	if attachmentSettings == nil {
+		return nil
+	}
+
+	if err := attachmentSettings.Unmarshal(attachmentConfig); err != nil {
+		return err
+	}
+
+	switch attachmentConfig.Storage.Type {
+	case LocalStorage:
+		if attachmentConfig.Storage.Local == nil || attachmentConfig.Storage.Local.Directory == "" {
+			return errors.New("attachment storage directory not set")
+		}
+	case S3Storage:
+		return configureS3(attachmentConfig.Storage, viperRegistry)
+	default:
+		return errors.New("attachment storage type should be one of local, s3")
+	}
+
+	return nil
+}
+
+// configureS3 validates S3 storage configurations, and fills the keys from OS environment variables
+func configureS3(storageConfig *StorageSetting, viperRegistry *viper.Viper) error {
+	s3Config := storageConfig.S3
+
+	if s3Config == nil || s3Config.Endpoint == "" || s3Config.Bucket == "" {
+		return errors.New("attachment storage endpoint or bucket not set")
+	}
+
+	if s3Config.Region == "" {
+		s3Config.Region = "us-east-1"
+	}
+
+	if s3Config.AccessKey == "" {
+		if !viperRegistry.IsSet("S3_ACCESS_KEY") {
+			return errors.New("s3 access key not set")
+		}
+
+		s3Config.AccessKey = viperRegistry.GetString("S3_ACCESS_KEY")
+	}
+
+	if s3Config.SecretKey == "" {
+		if !viperRegistry.IsSet("S3_SECRET_KEY") {
+			return errors.New("s3 secret key not set")
+		}
+
+		s3Config.SecretKey = viperRegistry.GetString("S3_SECRET_KEY")
+	}
+
+	return nil
+}
+
+// configureSearch loads search specific configurations. Whole section is optional, and MySQL is used by default.
+func (config *Config) configureSearch(viperRegistry *viper.Viper) error {
+	searchConfig := &SearchSetting{
+		Type: MySQLSearch,
+	}
+
+	config.Search = searchConfig
+	searchSettings := viperRegistry.Sub("search")
+
+	if searchSettings == nil {
+		return nil
+	}
+
+	if err := searchSettings.Unmarshal(searchConfig); err != nil {
+		return err
+	}
+
+	if searchConfig.Type != MySQLSearch && searchConfig.Type != MemorySearch {
+		return errors.New("search type should be one of mysql, memory")
+	}
+
+	return nil
+}
+
+// configureAccount loads account specific configurations. Whole section is optional (defaults applied).
+func (config *Config) configureAccount(viperRegistry *viper.Viper) error {
+	accountConfig := &AccountSetting{
+		UnverifiedAccess:             UnverifiedFullAccess,
+		VerificationExpiryInHours:    48,
+		PasswordResetExpiryInMinutes: 60,
+		EmailChangeExpiryInHours:     24,
+	}
+
+	config.Account = accountConfig
+	accountSettings := viperRegistry.Sub("account")
+
+	if accountSettings == nil {
+		return nil
+	}
+
+	if err := accountSettings.Unmarshal(accountConfig); err != nil {
+		return err
+	}
+
+	switch accountConfig.UnverifiedAccess {
+	case UnverifiedFullAccess, UnverifiedReadOnly, UnverifiedNoAccess:
+	default:
+		return errors.New("unverified access should be one of full, readOnly, none")
+	}
+
+	if accountConfig.VerificationExpiryInHours <= 0 || accountConfig.PasswordResetExpiryInMinutes <= 0 ||
+		accountConfig.EmailChangeExpiryInHours <= 0 {
+		return errors.New("verification, password reset and email change expiry should be positive")
+	}
+
+	return nil
+}
+
+// configureMail loads mail specific configurations. Whole section is optional, and memory mailer is used by default.
+// SMTP password is taken from OS environment variable, if missing.
+func (config *Config) configureMail(viperRegistry *viper.Viper) error {
+	mailConfig := &MailSetting{
+		Type: MemoryMailer,
+		From: "todo-api@localhost",
+	}
+
+	config.Mail = mailConfig
+	mailSettings := viperRegistry.Sub("mail")
+
+	if mailSettings == nil {
+		return nil
+	}
+
+	if err := mailSettings.Unmarshal(mailConfig); err != nil {
+		return err
+	}
+
+	switch mailConfig.Type {
+	case MemoryMailer:
+	case SMTPMailer:
+		smtpConfig := mailConfig.SMTP
+
+		if smtpConfig == nil || smtpConfig.Host == "" || smtpConfig.Port == 0 {
+			return errors.New("smtp host or port not set")
+		}
+
+		if smtpConfig.Username != "" && smtpConfig.Password == "" {
+			if !viperRegistry.IsSet("SMTP_PASSWORD") {
+				return errors.New("smtp password not set")
+			}
+
+			smtpConfig.Password = viperRegistry.GetString("SMTP_PASSWORD")
+		}
+	default:
+		return errors.New("mail type should be one of smtp, memory")
+	}
+
+	return nil
+}
+
+// containsString tells whether the values include the value
+func containsString(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,54 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func newRegistry(t *testing.T, content string) *viper.Viper {
+	viperRegistry := viper.New()
+	viperRegistry.SetConfigType("json")
+
+	if err := viperRegistry.ReadConfig(strings.NewReader(content)); err != nil {
+		t.Fatalf("Unexpected error while reading config: %s", err)
+	}
+
+	return viperRegistry
+}
+
+func TestConfigureApplication(t *testing.T) {
+	assert := assert.New(t)
+	config := &Config{}
+
+	err := config.configureApplication(newRegistry(t, `{"application": {"port": 9090}}`))
+
+	assert.NoError(err)
+	assert.Equal(9090, config.Application.Port)
+	assert.Equal(30, config.Application.TrashRetentionInDays)
+	assert.Equal(60, config.Application.TrashPurgeIntervalInMins)
+}
+
+func TestConfigureApplicationWithInvalidTrashRetention(t *testing.T) {
+	for _, retention := range []string{"0", "-1"} {
+		config := &Config{}
+
+		err := config.configureApplication(newRegistry(t, `{"application": {"trashRetentionInDays": `+retention+`}}`))
+
+		assert.EqualError(t, err, "trash retention and purge interval should be positive")
+		assert.Nil(t, config.Application)
+	}
+}
+
+func TestConfigureApplicationWithInvalidTrashPurgeInterval(t *testing.T) {
+	for _, interval := range []string{"0", "-5"} {
+		config := &Config{}
+
+		err := config.configureApplication(newRegistry(t, `{"application": {"trashPurgeIntervalInMins": `+interval+`}}`))
+
+		assert.EqualError(t, err, "trash retention and purge interval should be positive")
+		assert.Nil(t, config.Application)
+	}
+}
@@ -0,0 +1,86 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/dheerajgopi/todo-api/auth"
+	"github.com/dheerajgopi/todo-api/common"
+	todoErr "github.com/dheerajgopi/todo-api/common/error"
+	"github.com/dheerajgopi/todo-api/common/middlewares"
+	"github.com/dheerajgopi/todo-api/history"
+	"github.com/gorilla/mux"
+)
+
+// HistoryHandler represents HTTP handler for the history of tasks
+type HistoryHandler struct {
+	HistoryService history.Service
+	App            *common.App
+}
+
+// New creates new HTTP handler for task history
+func New(router *mux.Router, service history.Service, app *common.App) {
+	handler := &HistoryHandler{
+		HistoryService: service,
+		App:            app,
+	}
+
+	// API keys are accepted besides access tokens, as far as they grant the scope
+	readMiddleware := middlewares.ScopedValidator(app, auth.ScopeTasksRead)
+
+	router.HandleFunc("/tasks/{id:[0-9]+}/history", app.CreateHandler(readMiddleware(handler.List))).Methods("GET")
+}
+
+// List will return the events of a task, oldest first
+func (handler *HistoryHandler) List(res http.ResponseWriter, req *http.Request, reqCtx *common.RequestContext) (int, interface{}, *todoErr.APIError) {
+	taskID, err := strconv.ParseInt(mux.Vars(req)["id"], 10, 64)
+
+	if err != nil {
+		apiError := todoErr.NewAPIError(err.Error(), &todoErr.APIErrorBody{
+			Message: "Invalid value",
+			Target:  "id",
+		})
+
+		return http.StatusBadRequest, nil, apiError
+	}
+
+	events, err := handler.HistoryService.List(context.TODO(), taskID, reqCtx.UserID)
+
+	if err != nil {
+		return historyServiceError(err)
+	}
+
+	eventList := make([]*EventData, 0)
+
+	for _, event := range events {
+		eventList = append(eventList, newEventData(event))
+	}
+
+	responseData := &ListEventResponse{
+		Events: eventList,
+	}
+
+	return http.StatusOK, responseData, nil
+}
+
+// historyServiceError maps errors returned by the history service to the API response
+func historyServiceError(err error) (int, interface{}, *todoErr.APIError) {
+	switch err.(type) {
+	case *todoErr.ResourceNotFoundError:
+		resourceNotFoundErr, _ := err.(*todoErr.ResourceNotFoundError)
+
+		apiError := todoErr.NewAPIError(resourceNotFoundErr.Error(), &todoErr.APIErrorBody{
+			Message: "Not found",
+			Target:  resourceNotFoundErr.Resource,
+		})
+
+		return http.StatusNotFound, nil, apiError
+	default:
+		apiError := todoErr.NewAPIError(err.Error(), &todoErr.APIErrorBody{
+			Message: "Internal server error",
+		})
+
+		return http.StatusInternalServerError, nil, apiError
+	}
+}
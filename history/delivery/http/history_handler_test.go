@@ -0,0 +1,102 @@
+package http_test
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dheerajgopi/todo-api/common"
+	_errors "github.com/dheerajgopi/todo-api/common/error"
+	"github.com/dheerajgopi/todo-api/config"
+	"github.com/dheerajgopi/todo-api/history"
+	_historyHandler "github.com/dheerajgopi/todo-api/history/delivery/http"
+	mock "github.com/dheerajgopi/todo-api/history/mock"
+	"github.com/dheerajgopi/todo-api/models"
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestList(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+	reqCtx.UserID = 1
+	req := httptest.NewRequest("GET", "/tasks/5/history", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "5"})
+	oldTitle := "draft"
+	newTitle := "report"
+
+	mockService.
+		EXPECT().
+		List(gomock.Any(), int64(5), int64(1)).
+		Return([]*models.TaskEvent{
+			{ID: 1, Task: &models.Task{ID: 5}, Type: history.EventCreated, NewValue: &oldTitle, Actor: &models.User{ID: 1}, RequestID: "request-1", CreatedAt: time.Now()},
+			{ID: 2, Task: &models.Task{ID: 5}, Type: history.EventTitleChanged, OldValue: &oldTitle, NewValue: &newTitle, CreatedAt: time.Now()},
+		}, nil).
+		Times(1)
+
+	status, data, err := handler.List(httptest.NewRecorder(), req, reqCtx)
+
+	responseData := data.(*_historyHandler.ListEventResponse)
+
+	assert.Equal(200, status)
+	assert.Nil(err)
+	assert.Equal(2, len(responseData.Events))
+	assert.Equal("created", responseData.Events[0].Type)
+	assert.Nil(responseData.Events[0].OldValue)
+	assert.Equal(int64(1), *responseData.Events[0].ActorID)
+	assert.Equal("request-1", responseData.Events[0].RequestID)
+	assert.Equal("report", *responseData.Events[1].NewValue)
+	assert.Nil(responseData.Events[1].ActorID)
+}
+
+func TestListOfInaccessibleTask(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+	req := httptest.NewRequest("GET", "/tasks/5/history", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "5"})
+
+	mockService.
+		EXPECT().
+		List(gomock.Any(), int64(5), gomock.Any()).
+		Return(nil, &_errors.ResourceNotFoundError{Resource: "task"}).
+		Times(1)
+
+	status, data, err := handler.List(httptest.NewRecorder(), req, reqCtx)
+
+	assert.Equal(404, status)
+	assert.Nil(data)
+	assert.Equal("task", err.Body[0].Target)
+}
+
+func setupHandler(mockService history.Service) *_historyHandler.HistoryHandler {
+	app := &common.App{
+		Logger: logrus.New(),
+		Config: &config.Config{},
+	}
+
+	handler := &_historyHandler.HistoryHandler{
+		HistoryService: mockService,
+		App:            app,
+	}
+
+	return handler
+}
+
+func setupRequestContext(app *common.App) *common.RequestContext {
+	reqCtx := &common.RequestContext{
+		RequestID: "dummyRequestID",
+		LogEntry: app.Logger.WithFields(
+			logrus.Fields{},
+		),
+	}
+
+	return reqCtx
+}
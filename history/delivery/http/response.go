@@ -0,0 +1,44 @@
+package http
+
+import (
+	"time"
+
+	"github.com/dheerajgopi/todo-api/models"
+)
+
+// EventData represents json structure for task event.
+// Actor is null for the changes not made by an user.
+type EventData struct {
+	ID        int64     `json:"id"`
+	TaskID    int64     `json:"taskId"`
+	Type      string    `json:"type"`
+	OldValue  *string   `json:"oldValue"`
+	NewValue  *string   `json:"newValue"`
+	ActorID   *int64    `json:"actorId"`
+	RequestID string    `json:"requestId"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// ListEventResponse represents response for GET /tasks/{id}/history API
+type ListEventResponse struct {
+	Events []*EventData `json:"events"`
+}
+
+// newEventData builds the json structure of a task event
+func newEventData(event *models.TaskEvent) *EventData {
+	eventData := &EventData{
+		ID:        event.ID,
+		TaskID:    event.Task.ID,
+		Type:      event.Type,
+		OldValue:  event.OldValue,
+		NewValue:  event.NewValue,
+		RequestID: event.RequestID,
+		CreatedAt: event.CreatedAt,
+	}
+
+	if event.Actor != nil {
+		eventData.ActorID = &event.Actor.ID
+	}
+
+	return eventData
+}
@@ -0,0 +1,33 @@
+package history
+
+import "context"
+
+// Types of task events
+const (
+	EventCreated      = "created"
+	EventTitleChanged = "title_changed"
+	EventCompleted    = "completed"
+	EventReopened     = "reopened"
+	EventDeleted      = "deleted"
+	EventRestored     = "restored"
+)
+
+// Actor is the user making a change, along with the request in which the change is made
+type Actor struct {
+	UserID    int64
+	RequestID string
+}
+
+type actorKey struct{}
+
+// WithActor returns a copy of the context carrying the actor, which is recorded in the events of the changes made with it
+func WithActor(ctx context.Context, actor *Actor) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFrom returns the actor carried by the context, or nil if there is none
+func ActorFrom(ctx context.Context) *Actor {
+	actor, _ := ctx.Value(actorKey{}).(*Actor)
+
+	return actor
+}
@@ -0,0 +1,16 @@
+package history_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dheerajgopi/todo-api/history"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActorFrom(t *testing.T) {
+	actor := &history.Actor{UserID: 1, RequestID: "request-1"}
+
+	assert.Equal(t, actor, history.ActorFrom(history.WithActor(context.TODO(), actor)))
+	assert.Nil(t, history.ActorFrom(context.TODO()))
+}
@@ -0,0 +1,13 @@
+package history
+
+import (
+	"context"
+
+	"github.com/dheerajgopi/todo-api/models"
+)
+
+// Repository represents task history's repository contract.
+// Events are written by the task repository, in the same transaction as the change.
+type Repository interface {
+	GetAllByTaskID(ctx context.Context, taskID int64) ([]*models.TaskEvent, error)
+}
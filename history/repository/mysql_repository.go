@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/dheerajgopi/todo-api/history"
+	"github.com/dheerajgopi/todo-api/models"
+)
+
+type mySQLHistoryRepo struct {
+	DB *sql.DB
+}
+
+// New will return new object which implements history.Repository
+func New(db *sql.DB) history.Repository {
+	return &mySQLHistoryRepo{
+		DB: db,
+	}
+}
+
+// GetAllByTaskID returns the events of a task, oldest first
+func (repo *mySQLHistoryRepo) GetAllByTaskID(ctx context.Context, taskID int64) ([]*models.TaskEvent, error) {
+	query := `SELECT id, task_id, type, old_value, new_value, actor_id, request_id, created_at FROM task_event
+		WHERE task_id=? ORDER BY id`
+
+	stmt, err := repo.DB.PrepareContext(ctx, query)
+
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := stmt.QueryContext(ctx, taskID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	events := make([]*models.TaskEvent, 0)
+
+	for rows.Next() {
+		event := &models.TaskEvent{
+			Task: &models.Task{},
+		}
+
+		actorID := sql.NullInt64{}
+		requestID := sql.NullString{}
+
+		err = rows.Scan(
+			&event.ID,
+			&event.Task.ID,
+			&event.Type,
+			&event.OldValue,
+			&event.NewValue,
+			&actorID,
+			&requestID,
+			&event.CreatedAt,
+		)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if actorID.Valid {
+			event.Actor = &models.User{
+				ID: actorID.Int64,
+			}
+		}
+
+		event.RequestID = requestID.String
+		events = append(events, event)
+	}
+
+	err = rows.Err()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
@@ -0,0 +1,45 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/dheerajgopi/todo-api/history/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetAllByTaskID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	rows := sqlmock.
+		NewRows([]string{"id", "task_id", "type", "old_value", "new_value", "actor_id", "request_id", "created_at"}).
+		AddRow(1, 5, "created", nil, "draft", 2, "request-1", time.Now()).
+		AddRow(2, 5, "completed", "false", "true", nil, nil, time.Now())
+
+	prep := mock.ExpectPrepare("SELECT id, task_id, type, old_value, new_value, actor_id, request_id, created_at FROM task_event\\s+WHERE task_id=\\? ORDER BY id")
+	prep.ExpectQuery().WithArgs(int64(5)).WillReturnRows(rows)
+
+	repo := repository.New(db)
+
+	events, err := repo.GetAllByTaskID(context.TODO(), 5)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(events))
+	assert.Nil(t, events[0].OldValue)
+	assert.Equal(t, "draft", *events[0].NewValue)
+	assert.Equal(t, int64(2), events[0].Actor.ID)
+	assert.Equal(t, "request-1", events[0].RequestID)
+	assert.Equal(t, "completed", events[1].Type)
+	assert.Equal(t, "true", *events[1].NewValue)
+	assert.Nil(t, events[1].Actor)
+	assert.Equal(t, "", events[1].RequestID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,12 @@
+package history
+
+import (
+	"context"
+
+	"github.com/dheerajgopi/todo-api/models"
+)
+
+// Service represents task history service contract
+type Service interface {
+	List(ctx context.Context, taskID int64, userID int64) ([]*models.TaskEvent, error)
+}
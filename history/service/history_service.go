@@ -0,0 +1,32 @@
+package service
+
+import (
+	"context"
+
+	"github.com/dheerajgopi/todo-api/history"
+	"github.com/dheerajgopi/todo-api/models"
+	"github.com/dheerajgopi/todo-api/task"
+)
+
+type historyService struct {
+	historyRepo history.Repository
+	taskService task.Service
+}
+
+// New returns a new object implementing history.Service interface.
+// Access to the history of a task is checked through the task service.
+func New(repo history.Repository, taskService task.Service) history.Service {
+	return &historyService{
+		historyRepo: repo,
+		taskService: taskService,
+	}
+}
+
+// List returns the events of a task accessible to the user, oldest first
+func (service *historyService) List(ctx context.Context, taskID int64, userID int64) ([]*models.TaskEvent, error) {
+	if _, err := service.taskService.GetByID(ctx, taskID, userID); err != nil {
+		return nil, err
+	}
+
+	return service.historyRepo.GetAllByTaskID(ctx, taskID)
+}
@@ -0,0 +1,59 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	todoErr "github.com/dheerajgopi/todo-api/common/error"
+	historyMock "github.com/dheerajgopi/todo-api/history/mock"
+	"github.com/dheerajgopi/todo-api/history/service"
+	"github.com/dheerajgopi/todo-api/models"
+	taskMock "github.com/dheerajgopi/todo-api/task/mock"
+)
+
+func TestList(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	mockRepo := historyMock.NewRepository(mockCtrl)
+	mockTaskService := taskMock.NewService(mockCtrl)
+	historyService := service.New(mockRepo, mockTaskService)
+	events := []*models.TaskEvent{{ID: 1, Type: "created"}}
+
+	gomock.InOrder(
+		mockTaskService.EXPECT().GetByID(ctx, int64(5), int64(1)).Return(&models.Task{ID: 5}, nil),
+		mockRepo.EXPECT().GetAllByTaskID(ctx, int64(5)).Return(events, nil),
+	)
+
+	result, err := historyService.List(ctx, 5, 1)
+
+	assert.NoError(err)
+	assert.Equal(events, result)
+}
+
+func TestListOfInaccessibleTask(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	mockTaskService := taskMock.NewService(mockCtrl)
+	historyService := service.New(historyMock.NewRepository(mockCtrl), mockTaskService)
+
+	mockTaskService.
+		EXPECT().
+		GetByID(ctx, int64(5), int64(1)).
+		Return(nil, &todoErr.ResourceNotFoundError{Resource: "task"}).
+		Times(1)
+
+	_, err := historyService.List(ctx, 5, 1)
+
+	assert.IsType(&todoErr.ResourceNotFoundError{}, err)
+}
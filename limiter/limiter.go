@@ -0,0 +1,122 @@
+package limiter
+
+import (
+	"context"
+	"time"
+)
+
+// Store keeps the recent failed attempts of keys, like accounts and IP addresses, and how long the keys are blocked
+type Store interface {
+	// BlockedUntil returns the latest time until which any of the keys is blocked, or zero time if none is blocked
+	BlockedUntil(ctx context.Context, keys ...string) (time.Time, error)
+	// Fail records a failed attempt of the key and returns the number of its failures.
+	// Earlier failures are forgotten if the last of them was before resetBefore.
+	Fail(ctx context.Context, key string, failedAt time.Time, resetBefore time.Time) (int, error)
+	// Block blocks the key until the given time, unless it is already blocked for longer
+	Block(ctx context.Context, key string, until time.Time) error
+	// Reset forgets the failures of the key
+	Reset(ctx context.Context, key string) error
+}
+
+// Rule tells how many failed attempts a key is allowed before each further attempt is delayed,
+// and after how many failed attempts the key is locked out
+type Rule struct {
+	FreeAttempts    int
+	LockoutAttempts int
+}
+
+// Block describes a key which got blocked after a failed attempt
+type Block struct {
+	Key      string
+	Failures int
+	Until    time.Time
+	Lockout  bool
+}
+
+// Limiter slows down repeated failed attempts, like password guesses.
+// Each failure past the free attempts of a rule doubles the delay before the next attempt, starting from the base delay,
+// and the key is locked out once it reaches the lockout attempts. Failures are forgotten after a window without failures.
+type Limiter struct {
+	store     Store
+	baseDelay time.Duration
+	lockout   time.Duration
+	window    time.Duration
+}
+
+// New returns a limiter which keeps the failed attempts in the store
+func New(store Store, baseDelay time.Duration, lockout time.Duration, window time.Duration) *Limiter {
+	return &Limiter{
+		store:     store,
+		baseDelay: baseDelay,
+		lockout:   lockout,
+		window:    window,
+	}
+}
+
+// Check returns the time after which the keys can be attempted again, or zero time if they can be attempted now
+func (limiter *Limiter) Check(ctx context.Context, keys ...string) (time.Time, error) {
+	blockedUntil, err := limiter.store.BlockedUntil(ctx, keys...)
+
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if !blockedUntil.After(time.Now()) {
+		return time.Time{}, nil
+	}
+
+	return blockedUntil, nil
+}
+
+// Fail records a failed attempt of the key, and blocks the key as the rule says.
+// Block is returned only if the key got blocked.
+func (limiter *Limiter) Fail(ctx context.Context, key string, rule Rule) (*Block, error) {
+	now := time.Now()
+	failures, err := limiter.store.Fail(ctx, key, now, now.Add(-limiter.window))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if failures <= rule.FreeAttempts {
+		return nil, nil
+	}
+
+	block := &Block{
+		Key:      key,
+		Failures: failures,
+		Lockout:  failures >= rule.LockoutAttempts,
+	}
+
+	if block.Lockout {
+		block.Until = now.Add(limiter.lockout)
+	} else {
+		block.Until = now.Add(limiter.delay(failures - rule.FreeAttempts))
+	}
+
+	if err = limiter.store.Block(ctx, key, block.Until); err != nil {
+		return nil, err
+	}
+
+	return block, nil
+}
+
+// Reset forgets the failed attempts of the key, after a successful attempt
+func (limiter *Limiter) Reset(ctx context.Context, key string) error {
+	return limiter.store.Reset(ctx, key)
+}
+
+// delay returns the delay after the given number of failures past the free attempts, which is at most the lockout
+func (limiter *Limiter) delay(excessFailures int) time.Duration {
+	delay := limiter.baseDelay
+
+	for i := 1; i < excessFailures && delay < limiter.lockout; i++ {
+		delay *= 2
+	}
+
+	if delay > limiter.lockout {
+		return limiter.lockout
+	}
+
+	return delay
+}
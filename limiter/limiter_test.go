@@ -0,0 +1,86 @@
+package limiter_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dheerajgopi/todo-api/limiter"
+	"github.com/dheerajgopi/todo-api/limiter/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFail(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	rule := limiter.Rule{FreeAttempts: 2, LockoutAttempts: 6}
+	attempts := limiter.New(memory.New(), time.Second, 10*time.Second, time.Hour)
+
+	for i := 0; i < 2; i++ {
+		block, err := attempts.Fail(ctx, "account:a", rule)
+
+		assert.NoError(err)
+		assert.Nil(block)
+	}
+
+	retryAt, err := attempts.Check(ctx, "account:a", "ip:1")
+
+	assert.NoError(err)
+	assert.True(retryAt.IsZero())
+
+	// delay doubles with each failure past the free attempts, until the lockout
+	for i, delay := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 10 * time.Second} {
+		block, err := attempts.Fail(ctx, "account:a", rule)
+
+		assert.NoError(err)
+		assert.Equal("account:a", block.Key)
+		assert.Equal(i+3, block.Failures)
+		assert.Equal(i == 3, block.Lockout)
+		assert.WithinDuration(time.Now().Add(delay), block.Until, 100*time.Millisecond)
+	}
+
+	retryAt, err = attempts.Check(ctx, "account:a", "ip:1")
+
+	assert.NoError(err)
+	assert.WithinDuration(time.Now().Add(10*time.Second), retryAt, 100*time.Millisecond)
+
+	retryAt, err = attempts.Check(ctx, "account:b", "ip:1")
+
+	assert.NoError(err)
+	assert.True(retryAt.IsZero())
+}
+
+func TestFailAfterWindow(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	rule := limiter.Rule{FreeAttempts: 1, LockoutAttempts: 3}
+	attempts := limiter.New(memory.New(), time.Second, time.Minute, 10*time.Millisecond)
+
+	block, err := attempts.Fail(ctx, "ip:1", rule)
+
+	assert.NoError(err)
+	assert.Nil(block)
+
+	time.Sleep(20 * time.Millisecond)
+
+	// earlier failure is forgotten, since it is out of the window
+	block, err = attempts.Fail(ctx, "ip:1", rule)
+
+	assert.NoError(err)
+	assert.Nil(block)
+}
+
+func TestReset(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	rule := limiter.Rule{FreeAttempts: 1, LockoutAttempts: 2}
+	attempts := limiter.New(memory.New(), time.Second, time.Minute, time.Hour)
+
+	attempts.Fail(ctx, "account:a", rule)
+	assert.NoError(attempts.Reset(ctx, "account:a"))
+
+	block, err := attempts.Fail(ctx, "account:a", rule)
+
+	assert.NoError(err)
+	assert.Nil(block)
+}
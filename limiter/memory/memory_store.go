@@ -0,0 +1,110 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/dheerajgopi/todo-api/limiter"
+)
+
+// attempts holds the failures of a key
+type attempts struct {
+	failures     int
+	lastFailedAt time.Time
+	blockedUntil time.Time
+}
+
+type memoryStore struct {
+	mutex     sync.Mutex
+	attempts  map[string]*attempts
+	nextSweep time.Time
+}
+
+// sweepInterval is the minimum time between the removals of stale entries
+const sweepInterval = time.Minute
+
+// New returns an in-process store which implements limiter.Store.
+// Failures are lost on restart and are not shared between instances, so it fits single instance deployments only.
+func New() limiter.Store {
+	return &memoryStore{
+		attempts: make(map[string]*attempts),
+	}
+}
+
+// BlockedUntil returns the latest time until which any of the keys is blocked, or zero time if none is blocked
+func (store *memoryStore) BlockedUntil(ctx context.Context, keys ...string) (time.Time, error) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	var blockedUntil time.Time
+
+	for _, key := range keys {
+		if entry, ok := store.attempts[key]; ok && entry.blockedUntil.After(blockedUntil) {
+			blockedUntil = entry.blockedUntil
+		}
+	}
+
+	return blockedUntil, nil
+}
+
+// Fail records a failed attempt of the key and returns the number of its failures. Stale entries are removed along the way.
+func (store *memoryStore) Fail(ctx context.Context, key string, failedAt time.Time, resetBefore time.Time) (int, error) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	if failedAt.After(store.nextSweep) {
+		for staleKey, entry := range store.attempts {
+			if entry.lastFailedAt.Before(resetBefore) && !entry.blockedUntil.After(failedAt) {
+				delete(store.attempts, staleKey)
+			}
+		}
+
+		store.nextSweep = failedAt.Add(sweepInterval)
+	}
+
+	entry, ok := store.attempts[key]
+
+	if !ok {
+		entry = &attempts{}
+		store.attempts[key] = entry
+	}
+
+	if entry.lastFailedAt.Before(resetBefore) {
+		entry.failures = 0
+	}
+
+	entry.failures++
+	entry.lastFailedAt = failedAt
+
+	return entry.failures, nil
+}
+
+// Block blocks the key until the given time, unless it is already blocked for longer
+func (store *memoryStore) Block(ctx context.Context, key string, until time.Time) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	entry, ok := store.attempts[key]
+
+	if !ok {
+		entry = &attempts{}
+		store.attempts[key] = entry
+	}
+
+	if until.After(entry.blockedUntil) {
+		entry.blockedUntil = until
+	}
+
+	return nil
+}
+
+// Reset forgets the failures of the key
+func (store *memoryStore) Reset(ctx context.Context, key string) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	delete(store.attempts, key)
+
+	return nil
+}
@@ -0,0 +1,55 @@
+package memory_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dheerajgopi/todo-api/limiter/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFail(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	store := memory.New()
+	now := time.Now()
+
+	failures, err := store.Fail(ctx, "key", now.Add(-time.Hour), now.Add(-2*time.Hour))
+
+	assert.NoError(err)
+	assert.Equal(1, failures)
+
+	failures, err = store.Fail(ctx, "key", now.Add(-time.Minute), now.Add(-2*time.Hour))
+
+	assert.NoError(err)
+	assert.Equal(2, failures)
+
+	failures, err = store.Fail(ctx, "key", now, now.Add(-30*time.Second))
+
+	assert.NoError(err)
+	assert.Equal(1, failures)
+}
+
+func TestBlock(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	store := memory.New()
+	later := time.Now().Add(time.Hour)
+
+	assert.NoError(store.Block(ctx, "account", later))
+	assert.NoError(store.Block(ctx, "account", time.Now().Add(time.Minute)))
+	assert.NoError(store.Block(ctx, "ip", time.Now().Add(time.Minute)))
+
+	blockedUntil, err := store.BlockedUntil(ctx, "account", "ip")
+
+	assert.NoError(err)
+	assert.Equal(later, blockedUntil)
+
+	assert.NoError(store.Reset(ctx, "account"))
+
+	blockedUntil, err = store.BlockedUntil(ctx, "account")
+
+	assert.NoError(err)
+	assert.True(blockedUntil.IsZero())
+}
@@ -0,0 +1,120 @@
+package mysql
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/dheerajgopi/todo-api/limiter"
+)
+
+type mySQLStore struct {
+	DB *sql.DB
+}
+
+// New will return new object which implements limiter.Store using login_attempt table.
+// Keys are stored as SHA-256 hashes, so that emails and IP addresses are not kept, and long keys fit the index.
+func New(db *sql.DB) limiter.Store {
+	return &mySQLStore{
+		DB: db,
+	}
+}
+
+// BlockedUntil returns the latest time until which any of the keys is blocked, or zero time if none is blocked
+func (store *mySQLStore) BlockedUntil(ctx context.Context, keys ...string) (time.Time, error) {
+	if len(keys) == 0 {
+		return time.Time{}, nil
+	}
+
+	query := `SELECT MAX(blocked_until) FROM login_attempt WHERE key_hash IN (?` + strings.Repeat(", ?", len(keys)-1) + `)`
+
+	stmt, err := store.DB.PrepareContext(ctx, query)
+
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	args := make([]interface{}, 0, len(keys))
+
+	for _, key := range keys {
+		args = append(args, hash(key))
+	}
+
+	var blockedUntil *time.Time
+
+	if err = stmt.QueryRowContext(ctx, args...).Scan(&blockedUntil); err != nil {
+		return time.Time{}, err
+	}
+
+	if blockedUntil == nil {
+		return time.Time{}, nil
+	}
+
+	return *blockedUntil, nil
+}
+
+// Fail records a failed attempt of the key and returns the number of its failures. Stale entries are removed along the way.
+func (store *mySQLStore) Fail(ctx context.Context, key string, failedAt time.Time, resetBefore time.Time) (int, error) {
+	// failures is assigned before last_failed_at, since MySQL evaluates the assignments in order
+	query := `INSERT INTO login_attempt (key_hash, failures, last_failed_at) VALUES (?, 1, ?)
+		ON DUPLICATE KEY UPDATE failures=IF(last_failed_at<?, 1, failures+1), last_failed_at=VALUES(last_failed_at)`
+
+	tx, err := store.DB.BeginTx(ctx, nil)
+
+	if err != nil {
+		return 0, err
+	}
+
+	_, err = tx.Exec(`DELETE FROM login_attempt WHERE last_failed_at<? AND (blocked_until IS NULL OR blocked_until<?)`, resetBefore, failedAt)
+
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	_, err = tx.Exec(query, hash(key), failedAt, resetBefore)
+
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	var failures int
+
+	if err = tx.QueryRow(`SELECT failures FROM login_attempt WHERE key_hash=?`, hash(key)).Scan(&failures); err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	return failures, nil
+}
+
+// Block blocks the key until the given time, unless it is already blocked for longer
+func (store *mySQLStore) Block(ctx context.Context, key string, until time.Time) error {
+	query := `UPDATE login_attempt SET blocked_until=? WHERE key_hash=? AND (blocked_until IS NULL OR blocked_until<?)`
+
+	_, err := store.DB.ExecContext(ctx, query, until, hash(key), until)
+
+	return err
+}
+
+// Reset forgets the failures of the key
+func (store *mySQLStore) Reset(ctx context.Context, key string) error {
+	_, err := store.DB.ExecContext(ctx, `DELETE FROM login_attempt WHERE key_hash=?`, hash(key))
+
+	return err
+}
+
+// hash returns the hex encoded SHA-256 hash of the key
+func hash(key string) string {
+	sum := sha256.Sum256([]byte(key))
+
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,124 @@
+package mysql_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/dheerajgopi/todo-api/limiter/mysql"
+	"github.com/stretchr/testify/assert"
+)
+
+func hash(key string) string {
+	sum := sha256.Sum256([]byte(key))
+
+	return hex.EncodeToString(sum[:])
+}
+
+func TestBlockedUntil(t *testing.T) {
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	blockedUntil := time.Now().Add(time.Minute)
+	rows := sqlmock.NewRows([]string{"blocked_until"}).AddRow(blockedUntil)
+
+	prep := mock.ExpectPrepare("SELECT MAX\\(blocked_until\\) FROM login_attempt WHERE key_hash IN \\(\\?, \\?\\)")
+	prep.ExpectQuery().WithArgs(hash("account:a"), hash("ip:1")).WillReturnRows(rows)
+
+	store := mysql.New(db)
+
+	result, err := store.BlockedUntil(context.TODO(), "account:a", "ip:1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, blockedUntil, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestBlockedUntilWithNoBlock(t *testing.T) {
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{"blocked_until"}).AddRow(nil)
+
+	prep := mock.ExpectPrepare("SELECT MAX\\(blocked_until\\) FROM login_attempt WHERE key_hash IN \\(\\?\\)")
+	prep.ExpectQuery().WithArgs(hash("account:a")).WillReturnRows(rows)
+
+	store := mysql.New(db)
+
+	result, err := store.BlockedUntil(context.TODO(), "account:a")
+
+	assert.NoError(t, err)
+	assert.True(t, result.IsZero())
+}
+
+func TestFail(t *testing.T) {
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	failedAt := time.Now()
+	resetBefore := failedAt.Add(-time.Hour)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM login_attempt WHERE last_failed_at<\\? AND \\(blocked_until IS NULL OR blocked_until<\\?\\)").
+		WithArgs(resetBefore, failedAt).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec("INSERT INTO login_attempt \\(key_hash, failures, last_failed_at\\) VALUES \\(\\?, 1, \\?\\)\\s+"+
+		"ON DUPLICATE KEY UPDATE failures=IF\\(last_failed_at<\\?, 1, failures\\+1\\), last_failed_at=VALUES\\(last_failed_at\\)").
+		WithArgs(hash("account:a"), failedAt, resetBefore).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectQuery("SELECT failures FROM login_attempt WHERE key_hash=\\?").
+		WithArgs(hash("account:a")).
+		WillReturnRows(sqlmock.NewRows([]string{"failures"}).AddRow(4))
+	mock.ExpectCommit()
+
+	store := mysql.New(db)
+
+	failures, err := store.Fail(context.TODO(), "account:a", failedAt, resetBefore)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 4, failures)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestBlockAndReset(t *testing.T) {
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	until := time.Now().Add(time.Minute)
+
+	mock.ExpectExec("UPDATE login_attempt SET blocked_until=\\? WHERE key_hash=\\? AND \\(blocked_until IS NULL OR blocked_until<\\?\\)").
+		WithArgs(until, hash("ip:1"), until).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM login_attempt WHERE key_hash=\\?").
+		WithArgs(hash("account:a")).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	store := mysql.New(db)
+
+	assert.NoError(t, store.Block(context.TODO(), "ip:1", until))
+	assert.NoError(t, store.Reset(context.TODO(), "account:a"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
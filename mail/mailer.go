@@ -0,0 +1,15 @@
+package mail
+
+import "context"
+
+// Message is a plain text mail to a single recipient
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer represents mail delivery contract
+type Mailer interface {
+	Send(ctx context.Context, message *Message) error
+}
@@ -0,0 +1,43 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/dheerajgopi/todo-api/mail"
+)
+
+// Outbox implements mail.Mailer by keeping the messages in memory instead of delivering them.
+// It is meant for tests and local development.
+type Outbox struct {
+	mutex    sync.Mutex
+	messages []*mail.Message
+}
+
+// New returns an empty outbox
+func New() *Outbox {
+	return &Outbox{}
+}
+
+// Send keeps a copy of the message
+func (outbox *Outbox) Send(ctx context.Context, message *mail.Message) error {
+	copied := *message
+
+	outbox.mutex.Lock()
+	defer outbox.mutex.Unlock()
+
+	outbox.messages = append(outbox.messages, &copied)
+
+	return nil
+}
+
+// Messages returns the messages sent so far, oldest first
+func (outbox *Outbox) Messages() []*mail.Message {
+	outbox.mutex.Lock()
+	defer outbox.mutex.Unlock()
+
+	messages := make([]*mail.Message, len(outbox.messages))
+	copy(messages, outbox.messages)
+
+	return messages
+}
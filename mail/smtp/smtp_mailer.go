@@ -0,0 +1,135 @@
+package smtp
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dheerajgopi/todo-api/config"
+	"github.com/dheerajgopi/todo-api/mail"
+)
+
+type smtpMailer struct {
+	from    string
+	setting *config.SMTPSetting
+}
+
+// New will return new object which implements mail.Mailer, delivering the mails through a SMTP server.
+// STARTTLS is used whenever the server offers it, unless the connection is already TLS.
+func New(from string, setting *config.SMTPSetting) mail.Mailer {
+	return &smtpMailer{
+		from:    from,
+		setting: setting,
+	}
+}
+
+// Send delivers a message, giving up when the context is done
+func (mailer *smtpMailer) Send(ctx context.Context, message *mail.Message) error {
+	if strings.ContainsAny(message.To+message.Subject, "\r\n") {
+		return errors.New("mail headers can not contain line breaks")
+	}
+
+	content, err := mailer.compose(message)
+
+	if err != nil {
+		return err
+	}
+
+	host := mailer.setting.Host
+	dialer := &net.Dialer{}
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(host, strconv.Itoa(mailer.setting.Port)))
+
+	if err != nil {
+		return err
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	if mailer.setting.ImplicitTLS {
+		conn = tls.Client(conn, &tls.Config{ServerName: host})
+	}
+
+	client, err := smtp.NewClient(conn, host)
+
+	if err != nil {
+		conn.Close()
+		return err
+	}
+
+	defer client.Close()
+
+	if !mailer.setting.ImplicitTLS {
+		if ok, _ := client.Extension("STARTTLS"); ok {
+			if err = client.StartTLS(&tls.Config{ServerName: host}); err != nil {
+				return err
+			}
+		}
+	}
+
+	if mailer.setting.Username != "" {
+		if err = client.Auth(smtp.PlainAuth("", mailer.setting.Username, mailer.setting.Password, host)); err != nil {
+			return err
+		}
+	}
+
+	if err = client.Mail(mailer.from); err != nil {
+		return err
+	}
+
+	if err = client.Rcpt(message.To); err != nil {
+		return err
+	}
+
+	writer, err := client.Data()
+
+	if err != nil {
+		return err
+	}
+
+	if _, err = writer.Write(content); err != nil {
+		return err
+	}
+
+	if err = writer.Close(); err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
+
+// compose builds a plain text mail with quoted-printable body, as RFC 5322 and RFC 2045 describe
+func (mailer *smtpMailer) compose(message *mail.Message) ([]byte, error) {
+	var content bytes.Buffer
+
+	content.WriteString("From: " + mailer.from + "\r\n")
+	content.WriteString("To: " + message.To + "\r\n")
+	content.WriteString("Subject: " + mime.QEncoding.Encode("utf-8", message.Subject) + "\r\n")
+	content.WriteString("Date: " + time.Now().Format(time.RFC1123Z) + "\r\n")
+	content.WriteString("MIME-Version: 1.0\r\n")
+	content.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	content.WriteString("Content-Transfer-Encoding: quoted-printable\r\n")
+	content.WriteString("\r\n")
+
+	body := quotedprintable.NewWriter(&content)
+	text := strings.Replace(strings.Replace(message.Body, "\r\n", "\n", -1), "\n", "\r\n", -1)
+
+	if _, err := body.Write([]byte(text)); err != nil {
+		return nil, err
+	}
+
+	if err := body.Close(); err != nil {
+		return nil, err
+	}
+
+	return content.Bytes(), nil
+}
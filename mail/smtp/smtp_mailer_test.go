@@ -0,0 +1,129 @@
+package smtp_test
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dheerajgopi/todo-api/config"
+	"github.com/dheerajgopi/todo-api/mail"
+	"github.com/dheerajgopi/todo-api/mail/smtp"
+	"github.com/stretchr/testify/assert"
+)
+
+// serveOnce accepts a single SMTP session on the listener and sends the received commands and data on the channel
+func serveOnce(listener net.Listener, received chan<- string) {
+	conn, err := listener.Accept()
+
+	if err != nil {
+		close(received)
+		return
+	}
+
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+	reply := func(line string) {
+		conn.Write([]byte(line + "\r\n"))
+	}
+
+	var session strings.Builder
+
+	reply("220 localhost ESMTP")
+
+	for {
+		line, err := reader.ReadString('\n')
+
+		if err != nil {
+			break
+		}
+
+		command := strings.ToUpper(strings.TrimSpace(line))
+		session.WriteString(line)
+
+		switch {
+		case strings.HasPrefix(command, "EHLO"):
+			reply("250 localhost")
+		case strings.HasPrefix(command, "DATA"):
+			reply("354 go ahead")
+
+			for {
+				dataLine, err := reader.ReadString('\n')
+
+				if err != nil || dataLine == ".\r\n" {
+					break
+				}
+
+				session.WriteString(dataLine)
+			}
+
+			reply("250 queued")
+		case strings.HasPrefix(command, "QUIT"):
+			reply("221 bye")
+			received <- session.String()
+			return
+		default:
+			reply("250 ok")
+		}
+	}
+
+	received <- session.String()
+}
+
+func TestSend(t *testing.T) {
+	assert := assert.New(t)
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+
+	if err != nil {
+		t.Fatalf("Unexpected error while listening: %s", err)
+	}
+
+	defer listener.Close()
+
+	received := make(chan string, 1)
+	go serveOnce(listener, received)
+
+	address := listener.Addr().(*net.TCPAddr)
+	mailer := smtp.New("todo-api@localhost", &config.SMTPSetting{
+		Host: "127.0.0.1",
+		Port: address.Port,
+	})
+
+	ctx, cancel := context.WithTimeout(context.TODO(), 5*time.Second)
+	defer cancel()
+
+	err = mailer.Send(ctx, &mail.Message{
+		To:      "user@email.com",
+		Subject: "Vérify your email",
+		Body:    "Hello,\nOpen https://todo.app/verify?token=abc to verify.",
+	})
+
+	assert.NoError(err)
+
+	session := <-received
+
+	assert.Contains(session, "MAIL FROM:<todo-api@localhost>")
+	assert.Contains(session, "RCPT TO:<user@email.com>")
+	assert.Contains(session, "To: user@email.com\r\n")
+	assert.Contains(session, "Subject: =?utf-8?q?V=C3=A9rify_your_email?=\r\n")
+	assert.Contains(session, "Content-Transfer-Encoding: quoted-printable\r\n")
+	assert.Contains(session, "Open https://todo.app/verify?token=3Dabc to verify.")
+}
+
+func TestSendWithLineBreakInHeader(t *testing.T) {
+	mailer := smtp.New("todo-api@localhost", &config.SMTPSetting{
+		Host: "127.0.0.1",
+		Port: 1,
+	})
+
+	err := mailer.Send(context.TODO(), &mail.Message{
+		To:      "user@email.com\r\nBcc: other@email.com",
+		Subject: "Verify your email",
+		Body:    "Hello",
+	})
+
+	assert.EqualError(t, err, "mail headers can not contain line breaks")
+}
@@ -1,24 +1,82 @@
 package main
 
 import (
+	"context"
 	"database/sql"
-	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
 	"strconv"
+	"time"
 
 	"gopkg.in/natefinch/lumberjack.v2"
 
 	"github.com/gorilla/mux"
 	"github.com/sirupsen/logrus"
 
+	_apiKeyHttpDelivery "github.com/dheerajgopi/todo-api/apikey/delivery/http"
+	_apiKeyRepo "github.com/dheerajgopi/todo-api/apikey/repository"
+	_apiKeyService "github.com/dheerajgopi/todo-api/apikey/service"
+	_attachmentHttpDelivery "github.com/dheerajgopi/todo-api/attachment/delivery/http"
+	_attachmentRepo "github.com/dheerajgopi/todo-api/attachment/repository"
+	_attachmentService "github.com/dheerajgopi/todo-api/attachment/service"
+	"github.com/dheerajgopi/todo-api/auth"
+	_authHttpDelivery "github.com/dheerajgopi/todo-api/auth/delivery/http"
+	_memoryRevocation "github.com/dheerajgopi/todo-api/auth/memory"
+	_mySQLRevocation "github.com/dheerajgopi/todo-api/auth/mysql"
+	_authRepo "github.com/dheerajgopi/todo-api/auth/repository"
+	_authService "github.com/dheerajgopi/todo-api/auth/service"
+	"github.com/dheerajgopi/todo-api/auth/signing"
+	_calendarHttpDelivery "github.com/dheerajgopi/todo-api/calendar/delivery/http"
+	_calendarRepo "github.com/dheerajgopi/todo-api/calendar/repository"
+	_calendarService "github.com/dheerajgopi/todo-api/calendar/service"
+	_collaboratorHttpDelivery "github.com/dheerajgopi/todo-api/collaborator/delivery/http"
+	_collaboratorRepo "github.com/dheerajgopi/todo-api/collaborator/repository"
+	_collaboratorService "github.com/dheerajgopi/todo-api/collaborator/service"
+	_commentHttpDelivery "github.com/dheerajgopi/todo-api/comment/delivery/http"
+	_commentRepo "github.com/dheerajgopi/todo-api/comment/repository"
+	_commentService "github.com/dheerajgopi/todo-api/comment/service"
 	common "github.com/dheerajgopi/todo-api/common"
 	"github.com/dheerajgopi/todo-api/config"
+	_historyHttpDelivery "github.com/dheerajgopi/todo-api/history/delivery/http"
+	_historyRepo "github.com/dheerajgopi/todo-api/history/repository"
+	_historyService "github.com/dheerajgopi/todo-api/history/service"
+	"github.com/dheerajgopi/todo-api/limiter"
+	_memoryLoginAttempts "github.com/dheerajgopi/todo-api/limiter/memory"
+	_mySQLLoginAttempts "github.com/dheerajgopi/todo-api/limiter/mysql"
+	"github.com/dheerajgopi/todo-api/mail"
+	_memoryMailer "github.com/dheerajgopi/todo-api/mail/memory"
+	_smtpMailer "github.com/dheerajgopi/todo-api/mail/smtp"
+	_mfaHttpDelivery "github.com/dheerajgopi/todo-api/mfa/delivery/http"
+	_mfaRepo "github.com/dheerajgopi/todo-api/mfa/repository"
+	"github.com/dheerajgopi/todo-api/mfa/secret"
+	_mfaService "github.com/dheerajgopi/todo-api/mfa/service"
+	_oidcHttpDelivery "github.com/dheerajgopi/todo-api/oidc/delivery/http"
+	_oidcProvider "github.com/dheerajgopi/todo-api/oidc/provider"
+	_oidcRepo "github.com/dheerajgopi/todo-api/oidc/repository"
+	_oidcService "github.com/dheerajgopi/todo-api/oidc/service"
+	_projectHttpDelivery "github.com/dheerajgopi/todo-api/project/delivery/http"
+	_projectRepo "github.com/dheerajgopi/todo-api/project/repository"
+	_projectService "github.com/dheerajgopi/todo-api/project/service"
+	"github.com/dheerajgopi/todo-api/search"
+	_searchHttpDelivery "github.com/dheerajgopi/todo-api/search/delivery/http"
+	_searchIndexing "github.com/dheerajgopi/todo-api/search/indexing"
+	_memorySearch "github.com/dheerajgopi/todo-api/search/memory"
+	_mySQLSearch "github.com/dheerajgopi/todo-api/search/mysql"
+	_searchService "github.com/dheerajgopi/todo-api/search/service"
+	"github.com/dheerajgopi/todo-api/storage"
+	_localStorage "github.com/dheerajgopi/todo-api/storage/local"
+	_s3Storage "github.com/dheerajgopi/todo-api/storage/s3"
+	_tagHttpDelivery "github.com/dheerajgopi/todo-api/tag/delivery/http"
+	_tagRepo "github.com/dheerajgopi/todo-api/tag/repository"
+	_tagService "github.com/dheerajgopi/todo-api/tag/service"
 	_taskHttpDelivery "github.com/dheerajgopi/todo-api/task/delivery/http"
 	_taskRepo "github.com/dheerajgopi/todo-api/task/repository"
 	_taskService "github.com/dheerajgopi/todo-api/task/service"
+	_trashHttpDelivery "github.com/dheerajgopi/todo-api/trash/delivery/http"
+	_trashPurger "github.com/dheerajgopi/todo-api/trash/purger"
+	_trashService "github.com/dheerajgopi/todo-api/trash/service"
 	_userHttpDelivery "github.com/dheerajgopi/todo-api/user/delivery/http"
 	_userRepo "github.com/dheerajgopi/todo-api/user/repository"
 	_userService "github.com/dheerajgopi/todo-api/user/service"
@@ -67,27 +125,175 @@ func main() {
 
 	defer dbConn.Close()
 
-	app := &common.App{
-		Config: cfg,
-		Logger: logger,
+	// keys of access tokens
+	keys, err := signing.Load(cfg.Auth.Jwt)
+
+	if err != nil {
+		logger.Errorf("Error loading JWT keys: %v", err)
+		os.Exit(1)
+	}
+
+	// revoked access tokens
+	var revocations auth.RevocationStore
+
+	if cfg.Auth.Revocation.Type == config.MemoryRevocation {
+		revocations = _memoryRevocation.New()
+	} else {
+		revocations = _mySQLRevocation.New(dbConn)
 	}
 
-	cfgJSON, _ := json.Marshal(app.Config)
+	// API keys, which are accepted instead of access tokens
+	apiKeyRepo := _apiKeyRepo.New(dbConn)
+	apiKeyService := _apiKeyService.New(apiKeyRepo)
 
-	fmt.Println(string(cfgJSON))
+	app := &common.App{
+		Config:      cfg,
+		Logger:      logger,
+		Keys:        keys,
+		Revocations: revocations,
+		APIKeys:     apiKeyService,
+	}
 
 	router := mux.NewRouter()
 
+	// auth service
+	authRepo := _authRepo.New(dbConn)
+	accessExpiry := time.Duration(cfg.Auth.Jwt.ExpiryInSeconds) * time.Second
+	refreshExpiry := time.Duration(cfg.Auth.Jwt.RefreshExpiryInSeconds) * time.Second
+	challengeExpiry := time.Duration(cfg.Auth.Mfa.ChallengeExpiryInSeconds) * time.Second
+	authService := _authService.New(authRepo, revocations, keys, accessExpiry, refreshExpiry, challengeExpiry)
+	_authHttpDelivery.New(router, authService, app)
+	_apiKeyHttpDelivery.New(router, apiKeyService, app)
+
+	// mailer
+	var mailer mail.Mailer
+
+	if cfg.Mail.Type == config.SMTPMailer {
+		mailer = _smtpMailer.New(cfg.Mail.From, cfg.Mail.SMTP)
+	} else {
+		mailer = _memoryMailer.New()
+	}
+
+	// failed login attempts
+	var loginAttempts limiter.Store
+
+	if cfg.Auth.Login.Store == config.MemoryLoginAttempts {
+		loginAttempts = _memoryLoginAttempts.New()
+	} else {
+		loginAttempts = _mySQLLoginAttempts.New(dbConn)
+	}
+
+	loginLimiter := limiter.New(
+		loginAttempts,
+		time.Duration(cfg.Auth.Login.BaseDelayInSeconds)*time.Second,
+		time.Duration(cfg.Auth.Login.LockoutInMinutes)*time.Minute,
+		time.Duration(cfg.Auth.Login.WindowInMinutes)*time.Minute,
+	)
+
+	// two-factor authentication service
+	secretBox, err := secret.NewBox(cfg.Auth.Mfa.EncryptionKey)
+
+	if err != nil {
+		logger.Errorf("Error loading MFA encryption key: %v", err)
+		os.Exit(1)
+	}
+
+	mfaRepo := _mfaRepo.New(dbConn)
+	mfaService := _mfaService.New(mfaRepo, secretBox, cfg.Auth.Mfa)
+	_mfaHttpDelivery.New(router, mfaService, loginLimiter, app)
+
 	// user service
 	userRepo := _userRepo.New(dbConn)
-	userService := _userService.New(userRepo)
-	_userHttpDelivery.New(router, userService, app)
+	userService := _userService.New(userRepo, mailer, cfg.Account)
+	_userHttpDelivery.New(router, userService, authService, mfaService, loginLimiter, app)
 
-	// task service
+	// OpenID Connect login, if a provider is configured
+	if cfg.Auth.Oidc.Issuer != "" {
+		oidcRepo := _oidcRepo.New(dbConn)
+		oidcService := _oidcService.New(_oidcProvider.New(cfg.Auth.Oidc), oidcRepo, userRepo, cfg.Auth.Oidc)
+		_oidcHttpDelivery.New(router, oidcService, authService, app)
+	}
+
+	// tag service
+	tagRepo := _tagRepo.New(dbConn)
+	tagService := _tagService.New(tagRepo)
+	_tagHttpDelivery.New(router, tagService, app)
+
+	// project service
+	collaboratorRepo := _collaboratorRepo.New(dbConn)
+	projectRepo := _projectRepo.New(dbConn)
+	projectService := _projectService.New(projectRepo, collaboratorRepo, userRepo)
+	_projectHttpDelivery.New(router, projectService, app)
+
+	// search index
+	var searchIndex search.Index
 	taskRepo := _taskRepo.New(dbConn)
-	taskService := _taskService.New(taskRepo)
+	commentRepo := _commentRepo.New(dbConn)
+
+	if cfg.Search.Type == config.MemorySearch {
+		indexer := _memorySearch.New()
+
+		if err = _searchIndexing.Load(context.Background(), dbConn, indexer); err != nil {
+			logger.Errorf("Error loading search index: %v", err)
+			os.Exit(1)
+		}
+
+		taskRepo = _searchIndexing.NewTaskRepository(taskRepo, indexer)
+		commentRepo = _searchIndexing.NewCommentRepository(commentRepo, indexer)
+		searchIndex = indexer
+	} else {
+		searchIndex = _mySQLSearch.New(dbConn)
+	}
+
+	// task service
+	taskService := _taskService.New(taskRepo, tagRepo, projectRepo, collaboratorRepo, userRepo)
 	_taskHttpDelivery.New(router, taskService, app)
 
+	// history service
+	historyRepo := _historyRepo.New(dbConn)
+	historyService := _historyService.New(historyRepo, taskService)
+	_historyHttpDelivery.New(router, historyService, app)
+
+	// comment service
+	commentService := _commentService.New(commentRepo, taskService)
+	_commentHttpDelivery.New(router, commentService, app)
+
+	// search service
+	searchService := _searchService.New(searchIndex, taskService)
+	_searchHttpDelivery.New(router, searchService, app)
+
+	// attachment service
+	var attachmentStorage storage.Storage
+
+	if cfg.Attachment.Storage.Type == config.S3Storage {
+		attachmentStorage = _s3Storage.New(cfg.Attachment.Storage.S3)
+	} else {
+		attachmentStorage = _localStorage.New(cfg.Attachment.Storage.Local.Directory)
+	}
+
+	attachmentRepo := _attachmentRepo.New(dbConn)
+	attachmentService := _attachmentService.New(attachmentRepo, attachmentStorage, taskService, cfg.Attachment.QuotaInBytes)
+	_attachmentHttpDelivery.New(router, attachmentService, app)
+
+	// collaborator service
+	collaboratorService := _collaboratorService.New(collaboratorRepo, userRepo, taskRepo, projectRepo)
+	_collaboratorHttpDelivery.New(router, collaboratorService, app)
+
+	// calendar service
+	calendarRepo := _calendarRepo.New(dbConn)
+	calendarService := _calendarService.New(calendarRepo)
+	_calendarHttpDelivery.New(router, calendarService, app)
+
+	// trash service
+	trashService := _trashService.New(taskRepo, userRepo, attachmentRepo, attachmentStorage)
+	_trashHttpDelivery.New(router, trashService, app)
+
+	trashRetention := time.Duration(cfg.Application.TrashRetentionInDays) * 24 * time.Hour
+	trashPurgeInterval := time.Duration(cfg.Application.TrashPurgeIntervalInMins) * time.Minute
+	trashPurger := _trashPurger.New(trashService, trashRetention, trashPurgeInterval, logger)
+	trashPurger.Start()
+	defer trashPurger.Stop()
+
 	port := strconv.Itoa(cfg.Application.Port)
 	logger.Info(fmt.Sprintf("Starting server at port %s", port))
 	logger.Fatal(http.ListenAndServe(":"+port, router))
@@ -0,0 +1,230 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/dheerajgopi/todo-api/common"
+	todoErr "github.com/dheerajgopi/todo-api/common/error"
+	"github.com/dheerajgopi/todo-api/common/middlewares"
+	"github.com/dheerajgopi/todo-api/limiter"
+	"github.com/dheerajgopi/todo-api/mfa"
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+)
+
+// MfaHandler represents HTTP handler for two-factor authentication.
+// Wrong codes are tracked per user by the login limiter, along with the wrong codes of POST /login/mfa.
+type MfaHandler struct {
+	MfaService   mfa.Service
+	LoginLimiter *limiter.Limiter
+	App          *common.App
+}
+
+// New creates new HTTP handler for two-factor authentication
+func New(router *mux.Router, service mfa.Service, loginLimiter *limiter.Limiter, app *common.App) {
+	handler := &MfaHandler{
+		MfaService:   service,
+		LoginLimiter: loginLimiter,
+		App:          app,
+	}
+
+	jwtMiddleware := middlewares.JwtValidator(app)
+
+	router.HandleFunc("/users/me/mfa/totp", app.CreateHandler(jwtMiddleware(handler.Enroll))).Methods("POST")
+	router.HandleFunc("/users/me/mfa/totp/confirm", app.CreateHandler(jwtMiddleware(handler.Confirm))).Methods("POST")
+	router.HandleFunc("/users/me/mfa/totp/disable", app.CreateHandler(jwtMiddleware(handler.Disable))).Methods("POST")
+}
+
+// Enroll will generate a new TOTP secret for the logged in user, which is enabled once a code of it is confirmed
+func (handler *MfaHandler) Enroll(res http.ResponseWriter, req *http.Request, reqCtx *common.RequestContext) (int, interface{}, *todoErr.APIError) {
+	timeoutInSec := time.Duration(handler.App.Config.Application.RequestTimeout) * time.Second
+	timeoutContext, cancel := context.WithTimeout(context.TODO(), timeoutInSec)
+	defer cancel()
+
+	enrollment, err := handler.MfaService.Enroll(timeoutContext, reqCtx.UserID)
+
+	if err != nil {
+		return mfaServiceError(err)
+	}
+
+	responseData := &EnrollResponse{
+		Secret:          enrollment.Secret,
+		ProvisioningURI: enrollment.ProvisioningURI,
+	}
+
+	return http.StatusOK, responseData, nil
+}
+
+// Confirm will enable the pending TOTP secret of the logged in user with a code of it, and return the recovery codes
+func (handler *MfaHandler) Confirm(res http.ResponseWriter, req *http.Request, reqCtx *common.RequestContext) (int, interface{}, *todoErr.APIError) {
+	timeoutInSec := time.Duration(handler.App.Config.Application.RequestTimeout) * time.Second
+	timeoutContext, cancel := context.WithTimeout(context.TODO(), timeoutInSec)
+	defer cancel()
+	defer req.Body.Close()
+
+	var codeReqBody CodeRequest
+
+	if status, apiError := decodeAndValidate(req, reqCtx, &codeReqBody); apiError != nil {
+		return status, nil, apiError
+	}
+
+	recoveryCodes, err := handler.MfaService.Confirm(timeoutContext, reqCtx.UserID, codeReqBody.Code)
+
+	if err != nil {
+		return mfaServiceError(err)
+	}
+
+	responseData := &ConfirmResponse{
+		RecoveryCodes: recoveryCodes,
+	}
+
+	return http.StatusOK, responseData, nil
+}
+
+// Disable will turn off two-factor authentication of the logged in user, given a TOTP or recovery code.
+// Repeated wrong codes delay further attempts, and eventually lock them out, so that a stolen access token
+// can not be used to guess a code.
+func (handler *MfaHandler) Disable(res http.ResponseWriter, req *http.Request, reqCtx *common.RequestContext) (int, interface{}, *todoErr.APIError) {
+	timeoutInSec := time.Duration(handler.App.Config.Application.RequestTimeout) * time.Second
+	timeoutContext, cancel := context.WithTimeout(context.TODO(), timeoutInSec)
+	defer cancel()
+	defer req.Body.Close()
+
+	var codeReqBody CodeRequest
+
+	if status, apiError := decodeAndValidate(req, reqCtx, &codeReqBody); apiError != nil {
+		return status, nil, apiError
+	}
+
+	loginSetting := handler.App.Config.Auth.Login
+	mfaKey := "mfa:" + strconv.FormatInt(reqCtx.UserID, 10)
+
+	retryAt, err := handler.LoginLimiter.Check(timeoutContext, mfaKey)
+
+	if err != nil {
+		apiError := todoErr.NewAPIError(err.Error(), &todoErr.APIErrorBody{
+			Message: "Internal server error",
+		})
+
+		return http.StatusInternalServerError, nil, apiError
+	}
+
+	if !retryAt.IsZero() {
+		return throttled(res, reqCtx, retryAt)
+	}
+
+	switch err = handler.MfaService.Disable(timeoutContext, reqCtx.UserID, codeReqBody.Code); err.(type) {
+	case nil:
+		break
+	case *todoErr.InvalidValueError:
+		mfaRule := limiter.Rule{
+			FreeAttempts:    loginSetting.AccountFreeAttempts,
+			LockoutAttempts: loginSetting.AccountLockoutAttempts,
+		}
+
+		block, failErr := handler.LoginLimiter.Fail(timeoutContext, mfaKey, mfaRule)
+
+		if failErr != nil {
+			apiError := todoErr.NewAPIError(failErr.Error(), &todoErr.APIErrorBody{
+				Message: "Internal server error",
+			})
+
+			return http.StatusInternalServerError, nil, apiError
+		}
+
+		if block != nil && block.Lockout {
+			reqCtx.AddLogFields(logrus.Fields{
+				"lockoutKey":      block.Key,
+				"lockoutFailures": block.Failures,
+				"lockoutUntil":    block.Until,
+			})
+			reqCtx.AddLogMessage("login locked out")
+		}
+
+		return mfaServiceError(err)
+	default:
+		return mfaServiceError(err)
+	}
+
+	if err = handler.LoginLimiter.Reset(timeoutContext, mfaKey); err != nil {
+		apiError := todoErr.NewAPIError(err.Error(), &todoErr.APIErrorBody{
+			Message: "Internal server error",
+		})
+
+		return http.StatusInternalServerError, nil, apiError
+	}
+
+	return http.StatusOK, nil, nil
+}
+
+// decodeAndValidate decodes the JSON request body and validates it
+func decodeAndValidate(req *http.Request, reqCtx *common.RequestContext, body *CodeRequest) (int, *todoErr.APIError) {
+	if err := json.NewDecoder(req.Body).Decode(body); err != nil {
+		reqCtx.AddLogMessage("Invalid request body")
+		apiError := todoErr.NewAPIError("", &todoErr.APIErrorBody{
+			Message: "Invalid request body",
+		})
+
+		return http.StatusBadRequest, apiError
+	}
+
+	if validationErrors := body.Validate(); len(validationErrors) > 0 {
+		reqCtx.AddLogMessage("validation error")
+
+		return http.StatusBadRequest, todoErr.NewAPIError("", validationErrors...)
+	}
+
+	return http.StatusOK, nil
+}
+
+// mfaServiceError maps errors returned by the mfa service to the API response
+func mfaServiceError(err error) (int, interface{}, *todoErr.APIError) {
+	switch err.(type) {
+	case *todoErr.ResourceNotFoundError:
+		apiError := todoErr.NewAPIError(err.Error(), &todoErr.APIErrorBody{
+			Message: "Not found",
+			Target:  "user",
+		})
+
+		return http.StatusNotFound, nil, apiError
+	case *todoErr.DataConflictError:
+		apiError := todoErr.NewAPIError(err.Error(), &todoErr.APIErrorBody{
+			Message: "TOTP already enabled",
+			Target:  "totp",
+		})
+
+		return http.StatusConflict, nil, apiError
+	case *todoErr.InvalidValueError:
+		invalidValueErr, _ := err.(*todoErr.InvalidValueError)
+
+		apiError := todoErr.NewAPIError(invalidValueErr.Error(), &todoErr.APIErrorBody{
+			Message: invalidValueErr.Reason,
+			Target:  invalidValueErr.Field,
+		})
+
+		return http.StatusBadRequest, nil, apiError
+	default:
+		apiError := todoErr.NewAPIError(err.Error(), &todoErr.APIErrorBody{
+			Message: "Internal server error",
+		})
+
+		return http.StatusInternalServerError, nil, apiError
+	}
+}
+
+// throttled responds with 429 error, telling the client when to retry
+func throttled(res http.ResponseWriter, reqCtx *common.RequestContext, retryAt time.Time) (int, interface{}, *todoErr.APIError) {
+	retryAfter := int(math.Ceil(time.Until(retryAt).Seconds()))
+	res.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+	reqCtx.AddLogMessage("mfa attempt throttled")
+	apiError := todoErr.NewAPIError("", &todoErr.APIErrorBody{
+		Message: "Too many failed attempts, retry after " + strconv.Itoa(retryAfter) + " seconds",
+	})
+
+	return http.StatusTooManyRequests, nil, apiError
+}
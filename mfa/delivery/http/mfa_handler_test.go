@@ -0,0 +1,228 @@
+package http_test
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dheerajgopi/todo-api/common"
+	_errors "github.com/dheerajgopi/todo-api/common/error"
+	"github.com/dheerajgopi/todo-api/config"
+	"github.com/dheerajgopi/todo-api/limiter"
+	"github.com/dheerajgopi/todo-api/limiter/memory"
+	"github.com/dheerajgopi/todo-api/mfa"
+	_mfaHandler "github.com/dheerajgopi/todo-api/mfa/delivery/http"
+	mock "github.com/dheerajgopi/todo-api/mfa/mock"
+	"github.com/golang/mock/gomock"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnroll(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+	req := httptest.NewRequest("POST", "/users/me/mfa/totp", nil)
+
+	mockService.
+		EXPECT().
+		Enroll(gomock.Any(), reqCtx.UserID).
+		Return(&mfa.Enrollment{Secret: "SECRET", ProvisioningURI: "otpauth://totp/todo-api:test?secret=SECRET"}, nil).
+		Times(1)
+
+	status, data, err := handler.Enroll(httptest.NewRecorder(), req, reqCtx)
+
+	responseData := data.(*_mfaHandler.EnrollResponse)
+
+	assert.Equal(200, status)
+	assert.Nil(err)
+	assert.Equal("SECRET", responseData.Secret)
+	assert.Equal("otpauth://totp/todo-api:test?secret=SECRET", responseData.ProvisioningURI)
+}
+
+func TestEnrollWhenEnabled(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+	req := httptest.NewRequest("POST", "/users/me/mfa/totp", nil)
+
+	mockService.
+		EXPECT().
+		Enroll(gomock.Any(), reqCtx.UserID).
+		Return(nil, &_errors.DataConflictError{Resource: "user", Field: "totp"}).
+		Times(1)
+
+	status, data, err := handler.Enroll(httptest.NewRecorder(), req, reqCtx)
+
+	assert.Equal(409, status)
+	assert.Nil(data)
+	assert.Equal("TOTP already enabled", err.Body[0].Message)
+	assert.Equal("totp", err.Body[0].Target)
+}
+
+func TestConfirmWithEmptyCode(t *testing.T) {
+	payload, _ := json.Marshal(&_mfaHandler.CodeRequest{Code: " "})
+
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+	req := httptest.NewRequest("POST", "/users/me/mfa/totp/confirm", strings.NewReader(string(payload)))
+
+	status, data, err := handler.Confirm(httptest.NewRecorder(), req, reqCtx)
+
+	assert.Equal(400, status)
+	assert.Nil(data)
+	assert.Equal("code", err.Body[0].Target)
+}
+
+func TestConfirm(t *testing.T) {
+	payload, _ := json.Marshal(&_mfaHandler.CodeRequest{Code: "123456"})
+
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+	req := httptest.NewRequest("POST", "/users/me/mfa/totp/confirm", strings.NewReader(string(payload)))
+
+	mockService.
+		EXPECT().
+		Confirm(gomock.Any(), reqCtx.UserID, "123456").
+		Return([]string{"abcde-23456", "fghij-34567"}, nil).
+		Times(1)
+
+	status, data, err := handler.Confirm(httptest.NewRecorder(), req, reqCtx)
+
+	responseData := data.(*_mfaHandler.ConfirmResponse)
+
+	assert.Equal(200, status)
+	assert.Nil(err)
+	assert.Equal([]string{"abcde-23456", "fghij-34567"}, responseData.RecoveryCodes)
+}
+
+func TestConfirmWithInvalidCode(t *testing.T) {
+	payload, _ := json.Marshal(&_mfaHandler.CodeRequest{Code: "000000"})
+
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+	req := httptest.NewRequest("POST", "/users/me/mfa/totp/confirm", strings.NewReader(string(payload)))
+
+	mockService.
+		EXPECT().
+		Confirm(gomock.Any(), reqCtx.UserID, "000000").
+		Return(nil, &_errors.InvalidValueError{Resource: "totp", Field: "code", Reason: "Invalid code"}).
+		Times(1)
+
+	status, data, err := handler.Confirm(httptest.NewRecorder(), req, reqCtx)
+
+	assert.Equal(400, status)
+	assert.Nil(data)
+	assert.Equal("Invalid code", err.Body[0].Message)
+	assert.Equal("code", err.Body[0].Target)
+}
+
+func TestDisable(t *testing.T) {
+	payload, _ := json.Marshal(&_mfaHandler.CodeRequest{Code: "abcde-23456"})
+
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+	req := httptest.NewRequest("POST", "/users/me/mfa/totp/disable", strings.NewReader(string(payload)))
+
+	mockService.
+		EXPECT().
+		Disable(gomock.Any(), reqCtx.UserID, "abcde-23456").
+		Return(nil).
+		Times(1)
+
+	status, data, err := handler.Disable(httptest.NewRecorder(), req, reqCtx)
+
+	assert.Equal(200, status)
+	assert.Nil(data)
+	assert.Nil(err)
+}
+
+func TestDisableWithLockout(t *testing.T) {
+	payload, _ := json.Marshal(&_mfaHandler.CodeRequest{Code: "000000"})
+
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+
+	// delays are too short to block, so that only the lockout stops the guesses
+	handler.LoginLimiter = limiter.New(memory.New(), time.Nanosecond, time.Hour, time.Hour)
+
+	mockService.
+		EXPECT().
+		Disable(gomock.Any(), int64(1), "000000").
+		Return(&_errors.InvalidValueError{Resource: "totp", Field: "code", Reason: "Invalid code"}).
+		Times(4)
+
+	for i := 0; i < 4; i++ {
+		req := httptest.NewRequest("POST", "/users/me/mfa/totp/disable", strings.NewReader(string(payload)))
+		status, _, _ := handler.Disable(httptest.NewRecorder(), req, setupRequestContext(handler.App))
+
+		assert.Equal(400, status)
+	}
+
+	res := httptest.NewRecorder()
+	req := httptest.NewRequest("POST", "/users/me/mfa/totp/disable", strings.NewReader(string(payload)))
+	status, data, err := handler.Disable(res, req, setupRequestContext(handler.App))
+
+	assert.Equal(429, status)
+	assert.Nil(data)
+	assert.Equal("Too many failed attempts, retry after 3600 seconds", err.Body[0].Message)
+	assert.Equal("3600", res.Header().Get("Retry-After"))
+}
+
+func setupHandler(mockService mfa.Service) *_mfaHandler.MfaHandler {
+	app := &common.App{
+		Logger: logrus.New(),
+		Config: &config.Config{},
+	}
+
+	app.Config.Application = &config.ApplicationSetting{
+		RequestTimeout: 5,
+	}
+
+	app.Config.Auth = &config.AuthSetting{
+		Login: &config.LoginSetting{
+			AccountFreeAttempts:    2,
+			AccountLockoutAttempts: 4,
+		},
+	}
+
+	handler := &_mfaHandler.MfaHandler{
+		MfaService:   mockService,
+		LoginLimiter: limiter.New(memory.New(), time.Minute, time.Hour, time.Hour),
+		App:          app,
+	}
+
+	return handler
+}
+
+func setupRequestContext(app *common.App) *common.RequestContext {
+	reqCtx := &common.RequestContext{
+		RequestID: "dummyRequestID",
+		UserID:    1,
+		LogEntry: app.Logger.WithFields(
+			logrus.Fields{},
+		),
+	}
+
+	return reqCtx
+}
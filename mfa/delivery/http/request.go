@@ -0,0 +1,28 @@
+package http
+
+import (
+	"strings"
+
+	todoErr "github.com/dheerajgopi/todo-api/common/error"
+)
+
+// CodeRequest represents request body for POST /users/me/mfa/totp/confirm and POST /users/me/mfa/totp/disable APIs
+type CodeRequest struct {
+	Code string `json:"code"`
+}
+
+// Validate validates the request body for POST /users/me/mfa/totp/confirm and POST /users/me/mfa/totp/disable APIs
+func (body *CodeRequest) Validate() []*todoErr.APIErrorBody {
+	validationErrors := make([]*todoErr.APIErrorBody, 0)
+
+	body.Code = strings.TrimSpace(body.Code)
+
+	if body.Code == "" {
+		validationErrors = append(validationErrors, &todoErr.APIErrorBody{
+			Message: "Non-empty value is required",
+			Target:  "code",
+		})
+	}
+
+	return validationErrors
+}
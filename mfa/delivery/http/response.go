@@ -0,0 +1,13 @@
+package http
+
+// EnrollResponse represents response for POST /users/me/mfa/totp API
+type EnrollResponse struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioningUri"`
+}
+
+// ConfirmResponse represents response for POST /users/me/mfa/totp/confirm API.
+// Recovery codes are shown only once.
+type ConfirmResponse struct {
+	RecoveryCodes []string `json:"recoveryCodes"`
+}
@@ -0,0 +1,7 @@
+package mfa
+
+// Enrollment holds a new TOTP secret (base32), and its otpauth URI which authenticator apps read from a QR code
+type Enrollment struct {
+	Secret          string
+	ProvisioningURI string
+}
@@ -0,0 +1,18 @@
+package mfa
+
+import (
+	"context"
+	"time"
+
+	"github.com/dheerajgopi/todo-api/models"
+)
+
+// Repository represents the contract of two-factor authentication's repository
+type Repository interface {
+	GetTOTP(ctx context.Context, userID int64) (*models.UserTOTP, error)
+	SetPendingSecret(ctx context.Context, userID int64, secret string, updatedAt time.Time) (bool, error)
+	Enable(ctx context.Context, userID int64, step int64, codes []*models.RecoveryCode, enabledAt time.Time) (bool, error)
+	UseStep(ctx context.Context, userID int64, step int64) (bool, error)
+	UseRecoveryCode(ctx context.Context, userID int64, codeHash string, usedAt time.Time) (bool, error)
+	Disable(ctx context.Context, userID int64, disabledAt time.Time) error
+}
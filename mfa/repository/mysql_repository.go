@@ -0,0 +1,195 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/dheerajgopi/todo-api/mfa"
+	"github.com/dheerajgopi/todo-api/models"
+)
+
+type mySQLMfaRepo struct {
+	DB *sql.DB
+}
+
+// New will return new object which implements mfa.Repository
+func New(db *sql.DB) mfa.Repository {
+	return &mySQLMfaRepo{
+		DB: db,
+	}
+}
+
+// GetTOTP will return the TOTP state of the user, along with the user, unless the user is deleted
+func (repo *mySQLMfaRepo) GetTOTP(ctx context.Context, userID int64) (*models.UserTOTP, error) {
+	query := `SELECT id, name, email, is_active, totp_secret, totp_enabled, totp_last_step FROM user WHERE id=? AND deleted_at IS NULL`
+
+	stmt, err := repo.DB.PrepareContext(ctx, query)
+
+	if err != nil {
+		return nil, err
+	}
+
+	totp := &models.UserTOTP{
+		User: &models.User{},
+	}
+
+	secret := sql.NullString{}
+
+	err = stmt.QueryRowContext(ctx, userID).Scan(
+		&totp.User.ID,
+		&totp.User.Name,
+		&totp.User.Email,
+		&totp.User.IsActive,
+		&secret,
+		&totp.Enabled,
+		&totp.LastStep,
+	)
+
+	switch err {
+	case nil:
+	case sql.ErrNoRows:
+		return nil, nil
+	default:
+		return nil, err
+	}
+
+	totp.Secret = secret.String
+	totp.User.TotpEnabled = totp.Enabled
+
+	return totp, nil
+}
+
+// SetPendingSecret will store a new encrypted secret, which is not enabled until a code is confirmed.
+// False is returned, without storing the secret, if TOTP is already enabled.
+func (repo *mySQLMfaRepo) SetPendingSecret(ctx context.Context, userID int64, secret string, updatedAt time.Time) (bool, error) {
+	query := `UPDATE user SET totp_secret=?, totp_last_step=0, updated_at=? WHERE id=? AND totp_enabled=0 AND deleted_at IS NULL`
+
+	result, err := repo.DB.ExecContext(ctx, query, secret, updatedAt, userID)
+
+	if err != nil {
+		return false, err
+	}
+
+	updated, err := result.RowsAffected()
+
+	if err != nil {
+		return false, err
+	}
+
+	return updated > 0, nil
+}
+
+// Enable will enable the pending secret with the step of the confirmed code, replacing the recovery codes of the user.
+// False is returned, without changing anything, if TOTP is already enabled or there is no pending secret.
+func (repo *mySQLMfaRepo) Enable(ctx context.Context, userID int64, step int64, codes []*models.RecoveryCode, enabledAt time.Time) (bool, error) {
+	tx, err := repo.DB.BeginTx(ctx, nil)
+
+	if err != nil {
+		return false, err
+	}
+
+	query := `UPDATE user SET totp_enabled=1, totp_last_step=?, updated_at=?
+		WHERE id=? AND totp_enabled=0 AND totp_secret IS NOT NULL AND deleted_at IS NULL`
+
+	result, err := tx.Exec(query, step, enabledAt, userID)
+
+	if err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	if enabled, err := result.RowsAffected(); err != nil || enabled == 0 {
+		tx.Rollback()
+		return false, err
+	}
+
+	if _, err = tx.Exec(`DELETE FROM recovery_code WHERE user_id=?`, userID); err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	if len(codes) > 0 {
+		values := make([]string, 0, len(codes))
+		args := make([]interface{}, 0, 3*len(codes))
+
+		for _, code := range codes {
+			values = append(values, "(?, ?, ?)")
+			args = append(args, userID, code.CodeHash, code.CreatedAt)
+		}
+
+		query = `INSERT INTO recovery_code (user_id, code_hash, created_at) VALUES ` + strings.Join(values, ", ")
+
+		if _, err = tx.Exec(query, args...); err != nil {
+			tx.Rollback()
+			return false, err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// UseStep will record the step of an accepted code.
+// False is returned if the step is not later than the last accepted one, which means that the code was replayed.
+func (repo *mySQLMfaRepo) UseStep(ctx context.Context, userID int64, step int64) (bool, error) {
+	result, err := repo.DB.ExecContext(ctx, `UPDATE user SET totp_last_step=? WHERE id=? AND totp_last_step<?`, step, userID, step)
+
+	if err != nil {
+		return false, err
+	}
+
+	used, err := result.RowsAffected()
+
+	if err != nil {
+		return false, err
+	}
+
+	return used > 0, nil
+}
+
+// UseRecoveryCode will mark an unused recovery code of the user as used, and tells whether it was unused
+func (repo *mySQLMfaRepo) UseRecoveryCode(ctx context.Context, userID int64, codeHash string, usedAt time.Time) (bool, error) {
+	query := `UPDATE recovery_code SET used_at=? WHERE user_id=? AND code_hash=? AND used_at IS NULL`
+
+	result, err := repo.DB.ExecContext(ctx, query, usedAt, userID, codeHash)
+
+	if err != nil {
+		return false, err
+	}
+
+	used, err := result.RowsAffected()
+
+	if err != nil {
+		return false, err
+	}
+
+	return used > 0, nil
+}
+
+// Disable will remove the secret and the recovery codes of the user
+func (repo *mySQLMfaRepo) Disable(ctx context.Context, userID int64, disabledAt time.Time) error {
+	tx, err := repo.DB.BeginTx(ctx, nil)
+
+	if err != nil {
+		return err
+	}
+
+	query := `UPDATE user SET totp_secret=NULL, totp_enabled=0, totp_last_step=0, updated_at=? WHERE id=?`
+
+	if _, err = tx.Exec(query, disabledAt, userID); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if _, err = tx.Exec(`DELETE FROM recovery_code WHERE user_id=?`, userID); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,221 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/dheerajgopi/todo-api/mfa/repository"
+	"github.com/dheerajgopi/todo-api/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetTOTP(t *testing.T) {
+	assert := assert.New(t)
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	rows := sqlmock.
+		NewRows([]string{"id", "name", "email", "is_active", "totp_secret", "totp_enabled", "totp_last_step"}).
+		AddRow(1, "test user", "test@email.com", true, "sealed", true, 42)
+
+	query := "SELECT id, name, email, is_active, totp_secret, totp_enabled, totp_last_step FROM user WHERE id=\\? AND deleted_at IS NULL"
+
+	prep := mock.ExpectPrepare(query)
+	prep.ExpectQuery().WithArgs(int64(1)).WillReturnRows(rows)
+
+	repo := repository.New(db)
+
+	totp, err := repo.GetTOTP(context.TODO(), 1)
+	assert.NoError(err)
+	assert.Equal("sealed", totp.Secret)
+	assert.True(totp.Enabled)
+	assert.True(totp.User.TotpEnabled)
+	assert.Equal(int64(42), totp.LastStep)
+}
+
+func TestGetTOTPWithNoRows(t *testing.T) {
+	assert := assert.New(t)
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	query := "SELECT id, name, email, is_active, totp_secret, totp_enabled, totp_last_step FROM user WHERE id=\\? AND deleted_at IS NULL"
+
+	prep := mock.ExpectPrepare(query)
+	prep.ExpectQuery().WithArgs(int64(1)).WillReturnError(sql.ErrNoRows)
+
+	repo := repository.New(db)
+
+	totp, err := repo.GetTOTP(context.TODO(), 1)
+	assert.NoError(err)
+	assert.Nil(totp)
+}
+
+func TestSetPendingSecret(t *testing.T) {
+	assert := assert.New(t)
+	now := time.Now()
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	query := "UPDATE user SET totp_secret=\\?, totp_last_step=0, updated_at=\\? WHERE id=\\? AND totp_enabled=0 AND deleted_at IS NULL"
+
+	mock.ExpectExec(query).WithArgs("sealed", now, int64(1)).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	repo := repository.New(db)
+
+	updated, err := repo.SetPendingSecret(context.TODO(), 1, "sealed", now)
+	assert.NoError(err)
+	assert.True(updated)
+}
+
+func TestEnable(t *testing.T) {
+	assert := assert.New(t)
+	now := time.Now()
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	codes := []*models.RecoveryCode{
+		{CodeHash: "hash1", CreatedAt: now},
+		{CodeHash: "hash2", CreatedAt: now},
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE user SET totp_enabled=1, totp_last_step=\\?, updated_at=\\?").
+		WithArgs(int64(42), now, int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM recovery_code WHERE user_id=\\?").
+		WithArgs(int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("INSERT INTO recovery_code \\(user_id, code_hash, created_at\\) VALUES \\(\\?, \\?, \\?\\), \\(\\?, \\?, \\?\\)").
+		WithArgs(int64(1), "hash1", now, int64(1), "hash2", now).
+		WillReturnResult(sqlmock.NewResult(1, 2))
+	mock.ExpectCommit()
+
+	repo := repository.New(db)
+
+	enabled, err := repo.Enable(context.TODO(), 1, 42, codes, now)
+	assert.NoError(err)
+	assert.True(enabled)
+	assert.NoError(mock.ExpectationsWereMet())
+}
+
+func TestEnableWhenAlreadyEnabled(t *testing.T) {
+	assert := assert.New(t)
+	now := time.Now()
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE user SET totp_enabled=1, totp_last_step=\\?, updated_at=\\?").
+		WithArgs(int64(42), now, int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	repo := repository.New(db)
+
+	enabled, err := repo.Enable(context.TODO(), 1, 42, nil, now)
+	assert.NoError(err)
+	assert.False(enabled)
+	assert.NoError(mock.ExpectationsWereMet())
+}
+
+func TestUseStep(t *testing.T) {
+	assert := assert.New(t)
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	query := "UPDATE user SET totp_last_step=\\? WHERE id=\\? AND totp_last_step<\\?"
+
+	mock.ExpectExec(query).WithArgs(int64(42), int64(1), int64(42)).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(query).WithArgs(int64(42), int64(1), int64(42)).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	repo := repository.New(db)
+
+	used, err := repo.UseStep(context.TODO(), 1, 42)
+	assert.NoError(err)
+	assert.True(used)
+
+	used, err = repo.UseStep(context.TODO(), 1, 42)
+	assert.NoError(err)
+	assert.False(used)
+}
+
+func TestUseRecoveryCode(t *testing.T) {
+	assert := assert.New(t)
+	now := time.Now()
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	query := "UPDATE recovery_code SET used_at=\\? WHERE user_id=\\? AND code_hash=\\? AND used_at IS NULL"
+
+	mock.ExpectExec(query).WithArgs(now, int64(1), "hash").WillReturnResult(sqlmock.NewResult(0, 1))
+
+	repo := repository.New(db)
+
+	used, err := repo.UseRecoveryCode(context.TODO(), 1, "hash", now)
+	assert.NoError(err)
+	assert.True(used)
+}
+
+func TestDisable(t *testing.T) {
+	assert := assert.New(t)
+	now := time.Now()
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE user SET totp_secret=NULL, totp_enabled=0, totp_last_step=0, updated_at=\\? WHERE id=\\?").
+		WithArgs(now, int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM recovery_code WHERE user_id=\\?").
+		WithArgs(int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectCommit()
+
+	repo := repository.New(db)
+
+	assert.NoError(repo.Disable(context.TODO(), 1, now))
+	assert.NoError(mock.ExpectationsWereMet())
+}
@@ -0,0 +1,83 @@
+package secret
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+)
+
+// keySize is the size of AES-256 keys
+const keySize = 32
+
+// Box encrypts secrets which are stored in the database, with AES-256-GCM.
+// Sealed secrets are base64 encoded, with the random nonce in front of the ciphertext.
+type Box struct {
+	aead cipher.AEAD
+}
+
+// NewBox returns a box which encrypts with the base64 encoded 32 byte key
+func NewBox(encodedKey string) (*Box, error) {
+	key, err := base64.StdEncoding.DecodeString(encodedKey)
+
+	if err != nil {
+		return nil, errors.New("encryption key is not valid base64")
+	}
+
+	if len(key) != keySize {
+		return nil, errors.New("encryption key should be 32 bytes")
+	}
+
+	block, err := aes.NewCipher(key)
+
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := cipher.NewGCM(block)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &Box{
+		aead: aead,
+	}, nil
+}
+
+// Seal encrypts a secret
+func (box *Box) Seal(plaintext string) (string, error) {
+	nonce := make([]byte, box.aead.NonceSize())
+
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	sealed := box.aead.Seal(nonce, nonce, []byte(plaintext), nil)
+
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Open decrypts a sealed secret. It fails if the secret was sealed with another key or was tampered with.
+func (box *Box) Open(sealed string) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(sealed)
+
+	if err != nil {
+		return "", err
+	}
+
+	nonceSize := box.aead.NonceSize()
+
+	if len(data) < nonceSize {
+		return "", errors.New("sealed secret is too short")
+	}
+
+	plaintext, err := box.aead.Open(nil, data[:nonceSize], data[nonceSize:], nil)
+
+	if err != nil {
+		return "", err
+	}
+
+	return string(plaintext), nil
+}
@@ -0,0 +1,59 @@
+package secret_test
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/dheerajgopi/todo-api/mfa/secret"
+	"github.com/stretchr/testify/assert"
+)
+
+var key = base64.StdEncoding.EncodeToString([]byte(strings.Repeat("k", 32)))
+
+func TestSealAndOpen(t *testing.T) {
+	assert := assert.New(t)
+	box, err := secret.NewBox(key)
+
+	assert.NoError(err)
+
+	sealed, err := box.Seal("secret")
+
+	assert.NoError(err)
+	assert.NotContains(sealed, "secret")
+
+	other, _ := box.Seal("secret")
+
+	assert.NotEqual(sealed, other)
+
+	opened, err := box.Open(sealed)
+
+	assert.NoError(err)
+	assert.Equal("secret", opened)
+}
+
+func TestOpenWithOtherKey(t *testing.T) {
+	assert := assert.New(t)
+	box, _ := secret.NewBox(key)
+	otherBox, _ := secret.NewBox(base64.StdEncoding.EncodeToString([]byte(strings.Repeat("o", 32))))
+
+	sealed, _ := box.Seal("secret")
+
+	_, err := otherBox.Open(sealed)
+
+	assert.Error(err)
+
+	_, err = box.Open("c2hvcnQ=")
+
+	assert.EqualError(err, "sealed secret is too short")
+}
+
+func TestNewBoxWithInvalidKey(t *testing.T) {
+	_, err := secret.NewBox(base64.StdEncoding.EncodeToString([]byte("short")))
+
+	assert.EqualError(t, err, "encryption key should be 32 bytes")
+
+	_, err = secret.NewBox("not base64!")
+
+	assert.EqualError(t, err, "encryption key is not valid base64")
+}
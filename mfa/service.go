@@ -0,0 +1,17 @@
+package mfa
+
+import (
+	"context"
+
+	"github.com/dheerajgopi/todo-api/models"
+)
+
+// Service represents the contract of two-factor authentication's service.
+// Enrollment stays pending until a code of the new secret is confirmed, which also returns the recovery codes.
+// Each code, TOTP or recovery, works once.
+type Service interface {
+	Enroll(ctx context.Context, userID int64) (*Enrollment, error)
+	Confirm(ctx context.Context, userID int64, code string) ([]string, error)
+	Verify(ctx context.Context, userID int64, code string) (*models.User, error)
+	Disable(ctx context.Context, userID int64, code string) error
+}
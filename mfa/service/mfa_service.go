@@ -0,0 +1,300 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	todoErr "github.com/dheerajgopi/todo-api/common/error"
+	"github.com/dheerajgopi/todo-api/config"
+	"github.com/dheerajgopi/todo-api/mfa"
+	"github.com/dheerajgopi/todo-api/mfa/secret"
+	"github.com/dheerajgopi/todo-api/mfa/totp"
+	"github.com/dheerajgopi/todo-api/models"
+)
+
+// recoveryCodeSize is the number of random bytes in a recovery code, which makes 10 base32 characters
+const recoveryCodeSize = 6
+
+// recoveryCodeEncoding is the encoding of recovery codes, in lower case since they are typed by hand
+var recoveryCodeEncoding = base32.NewEncoding("abcdefghijklmnopqrstuvwxyz234567").WithPadding(base32.NoPadding)
+
+type mfaService struct {
+	mfaRepo mfa.Repository
+	box     *secret.Box
+	setting *config.MfaSetting
+}
+
+// New returns a new object implementing mfa.Service interface.
+// TOTP secrets are encrypted with the box before they are stored.
+func New(repo mfa.Repository, box *secret.Box, setting *config.MfaSetting) mfa.Service {
+	return &mfaService{
+		mfaRepo: repo,
+		box:     box,
+		setting: setting,
+	}
+}
+
+// Enroll generates a new pending secret for the user, replacing any earlier pending secret
+func (service *mfaService) Enroll(ctx context.Context, userID int64) (*mfa.Enrollment, error) {
+	userTOTP, err := service.getTOTP(ctx, userID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if userTOTP.Enabled {
+		return nil, &todoErr.DataConflictError{
+			Resource: "user",
+			Field:    "totp",
+		}
+	}
+
+	plainSecret, err := totp.GenerateSecret()
+
+	if err != nil {
+		return nil, err
+	}
+
+	sealedSecret, err := service.box.Seal(plainSecret)
+
+	if err != nil {
+		return nil, err
+	}
+
+	stored, err := service.mfaRepo.SetPendingSecret(ctx, userID, sealedSecret, time.Now())
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !stored {
+		// enabled by a concurrent request since it was read
+		return nil, &todoErr.DataConflictError{
+			Resource: "user",
+			Field:    "totp",
+		}
+	}
+
+	return &mfa.Enrollment{
+		Secret:          plainSecret,
+		ProvisioningURI: totp.ProvisioningURI(service.setting.Issuer, userTOTP.User.Email, plainSecret),
+	}, nil
+}
+
+// Confirm enables the pending secret if the code belongs to it, and returns new recovery codes.
+// Recovery codes are returned only this once, since only their hashes are stored.
+func (service *mfaService) Confirm(ctx context.Context, userID int64, code string) ([]string, error) {
+	userTOTP, err := service.getTOTP(ctx, userID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if userTOTP.Enabled {
+		return nil, &todoErr.DataConflictError{
+			Resource: "user",
+			Field:    "totp",
+		}
+	}
+
+	if userTOTP.Secret == "" {
+		return nil, &todoErr.InvalidValueError{
+			Resource: "totp",
+			Field:    "code",
+			Reason:   "TOTP enrollment not started",
+		}
+	}
+
+	plainSecret, err := service.box.Open(userTOTP.Secret)
+
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	step, valid, err := totp.Validate(plainSecret, code, now)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !valid {
+		return nil, invalidCode()
+	}
+
+	codes := make([]string, 0, service.setting.RecoveryCodeCount)
+	recoveryCodes := make([]*models.RecoveryCode, 0, service.setting.RecoveryCodeCount)
+
+	for i := 0; i < service.setting.RecoveryCodeCount; i++ {
+		codeBytes := make([]byte, recoveryCodeSize)
+
+		if _, err = rand.Read(codeBytes); err != nil {
+			return nil, err
+		}
+
+		recoveryCode := recoveryCodeEncoding.EncodeToString(codeBytes)
+
+		codes = append(codes, recoveryCode[:5]+"-"+recoveryCode[5:])
+		recoveryCodes = append(recoveryCodes, &models.RecoveryCode{
+			User: &models.User{
+				ID: userID,
+			},
+			CodeHash:  hashCode(recoveryCode),
+			CreatedAt: now,
+		})
+	}
+
+	enabled, err := service.mfaRepo.Enable(ctx, userID, step, recoveryCodes, now)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !enabled {
+		return nil, &todoErr.DataConflictError{
+			Resource: "user",
+			Field:    "totp",
+		}
+	}
+
+	return codes, nil
+}
+
+// Verify checks a TOTP code or a recovery code of the user, uses it up, and returns the user
+func (service *mfaService) Verify(ctx context.Context, userID int64, code string) (*models.User, error) {
+	userTOTP, err := service.mfaRepo.GetTOTP(ctx, userID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if userTOTP == nil || !userTOTP.Enabled {
+		return nil, invalidCode()
+	}
+
+	if err = service.useCode(ctx, userTOTP, code); err != nil {
+		return nil, err
+	}
+
+	return userTOTP.User, nil
+}
+
+// Disable removes the secret and the recovery codes of the user.
+// A code is needed once TOTP is enabled, so that a stolen access token can not turn it off.
+func (service *mfaService) Disable(ctx context.Context, userID int64, code string) error {
+	userTOTP, err := service.getTOTP(ctx, userID)
+
+	if err != nil {
+		return err
+	}
+
+	if userTOTP.Enabled {
+		if err = service.useCode(ctx, userTOTP, code); err != nil {
+			return err
+		}
+	}
+
+	return service.mfaRepo.Disable(ctx, userID, time.Now())
+}
+
+// getTOTP returns the TOTP state of the user, or an error if the user does not exist
+func (service *mfaService) getTOTP(ctx context.Context, userID int64) (*models.UserTOTP, error) {
+	userTOTP, err := service.mfaRepo.GetTOTP(ctx, userID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if userTOTP == nil {
+		return nil, &todoErr.ResourceNotFoundError{
+			Resource: "user",
+		}
+	}
+
+	return userTOTP, nil
+}
+
+// useCode checks a six digit TOTP code, or else a recovery code, and marks it used so that it works only once
+func (service *mfaService) useCode(ctx context.Context, userTOTP *models.UserTOTP, code string) error {
+	normalized := strings.ToLower(strings.Replace(strings.Replace(code, "-", "", -1), " ", "", -1))
+	now := time.Now()
+	userID := userTOTP.User.ID
+
+	if !isTOTPCode(normalized) {
+		used, err := service.mfaRepo.UseRecoveryCode(ctx, userID, hashCode(normalized), now)
+
+		if err != nil {
+			return err
+		}
+
+		if !used {
+			return invalidCode()
+		}
+
+		return nil
+	}
+
+	plainSecret, err := service.box.Open(userTOTP.Secret)
+
+	if err != nil {
+		return err
+	}
+
+	step, valid, err := totp.Validate(plainSecret, normalized, now)
+
+	if err != nil {
+		return err
+	}
+
+	if !valid || step <= userTOTP.LastStep {
+		return invalidCode()
+	}
+
+	used, err := service.mfaRepo.UseStep(ctx, userID, step)
+
+	if err != nil {
+		return err
+	}
+
+	if !used {
+		// the code was used by a concurrent request since the state was read
+		return invalidCode()
+	}
+
+	return nil
+}
+
+// isTOTPCode tells whether the code has the digits of a TOTP code
+func isTOTPCode(code string) bool {
+	if len(code) != totp.Digits {
+		return false
+	}
+
+	for _, char := range code {
+		if char < '0' || char > '9' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// invalidCode returns the error of a wrong, expired or used code
+func invalidCode() error {
+	return &todoErr.InvalidValueError{
+		Resource: "totp",
+		Field:    "code",
+		Reason:   "Invalid code",
+	}
+}
+
+// hashCode returns the hex encoded SHA-256 hash of a normalized recovery code
+func hashCode(code string) string {
+	hash := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,284 @@
+package service_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+	"testing"
+	"time"
+
+	todoErr "github.com/dheerajgopi/todo-api/common/error"
+	"github.com/dheerajgopi/todo-api/config"
+	repoMock "github.com/dheerajgopi/todo-api/mfa/mock"
+	"github.com/dheerajgopi/todo-api/mfa/secret"
+	"github.com/dheerajgopi/todo-api/mfa/service"
+	"github.com/dheerajgopi/todo-api/mfa/totp"
+	"github.com/dheerajgopi/todo-api/models"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+// plainSecret is the TOTP secret of the tests
+const plainSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func newBox() *secret.Box {
+	box, _ := secret.NewBox(base64.StdEncoding.EncodeToString([]byte(strings.Repeat("k", 32))))
+
+	return box
+}
+
+func newMfaSetting() *config.MfaSetting {
+	return &config.MfaSetting{
+		Issuer:            "todo-api",
+		RecoveryCodeCount: 3,
+	}
+}
+
+func newUserTOTP(enabled bool, lastStep int64) *models.UserTOTP {
+	sealedSecret, _ := newBox().Seal(plainSecret)
+
+	return &models.UserTOTP{
+		User: &models.User{
+			ID:    1,
+			Email: "testName@email.com",
+		},
+		Secret:   sealedSecret,
+		Enabled:  enabled,
+		LastStep: lastStep,
+	}
+}
+
+func currentCode() (string, int64) {
+	step := totp.Step(time.Now())
+	code, _ := totp.Code(plainSecret, step)
+
+	return code, step
+}
+
+func hash(value string) string {
+	sum := sha256.Sum256([]byte(value))
+
+	return hex.EncodeToString(sum[:])
+}
+
+func TestEnroll(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mfaRepoMock := repoMock.NewRepository(mockCtrl)
+	box := newBox()
+	mfaService := service.New(mfaRepoMock, box, newMfaSetting())
+
+	var storedSecret string
+
+	gomock.InOrder(
+		mfaRepoMock.
+			EXPECT().
+			GetTOTP(ctx, int64(1)).
+			Return(&models.UserTOTP{User: &models.User{ID: 1, Email: "testName@email.com"}}, nil),
+		mfaRepoMock.
+			EXPECT().
+			SetPendingSecret(ctx, int64(1), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, userID int64, secret string, updatedAt time.Time) (bool, error) {
+				storedSecret = secret
+				return true, nil
+			}),
+	)
+
+	enrollment, err := mfaService.Enroll(ctx, 1)
+
+	assert.NoError(err)
+	assert.NotEqual(enrollment.Secret, storedSecret)
+	assert.Contains(enrollment.ProvisioningURI, "otpauth://totp/todo-api:testName@email.com?")
+	assert.Contains(enrollment.ProvisioningURI, "secret="+enrollment.Secret)
+
+	openedSecret, err := box.Open(storedSecret)
+
+	assert.NoError(err)
+	assert.Equal(enrollment.Secret, openedSecret)
+}
+
+func TestEnrollWhenEnabled(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mfaRepoMock := repoMock.NewRepository(mockCtrl)
+	mfaService := service.New(mfaRepoMock, newBox(), newMfaSetting())
+
+	mfaRepoMock.EXPECT().GetTOTP(ctx, int64(1)).Return(newUserTOTP(true, 0), nil)
+	mfaRepoMock.EXPECT().GetTOTP(ctx, int64(2)).Return(nil, nil)
+
+	enrollment, err := mfaService.Enroll(ctx, 1)
+
+	assert.Nil(enrollment)
+	assert.Equal(&todoErr.DataConflictError{Resource: "user", Field: "totp"}, err)
+
+	enrollment, err = mfaService.Enroll(ctx, 2)
+
+	assert.Nil(enrollment)
+	assert.Equal(&todoErr.ResourceNotFoundError{Resource: "user"}, err)
+}
+
+func TestConfirm(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mfaRepoMock := repoMock.NewRepository(mockCtrl)
+	mfaService := service.New(mfaRepoMock, newBox(), newMfaSetting())
+	code, step := currentCode()
+
+	var storedCodes []*models.RecoveryCode
+
+	gomock.InOrder(
+		mfaRepoMock.EXPECT().GetTOTP(ctx, int64(1)).Return(newUserTOTP(false, 0), nil),
+		mfaRepoMock.
+			EXPECT().
+			Enable(ctx, int64(1), step, gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, userID int64, step int64, codes []*models.RecoveryCode, enabledAt time.Time) (bool, error) {
+				storedCodes = codes
+				return true, nil
+			}),
+	)
+
+	recoveryCodes, err := mfaService.Confirm(ctx, 1, code)
+
+	assert.NoError(err)
+	assert.Equal(3, len(recoveryCodes))
+	assert.Equal(3, len(storedCodes))
+
+	for i, recoveryCode := range recoveryCodes {
+		assert.Equal(11, len(recoveryCode))
+		assert.Equal(hash(strings.Replace(recoveryCode, "-", "", 1)), storedCodes[i].CodeHash)
+	}
+}
+
+func TestConfirmWithInvalidCode(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mfaRepoMock := repoMock.NewRepository(mockCtrl)
+	mfaService := service.New(mfaRepoMock, newBox(), newMfaSetting())
+
+	mfaRepoMock.EXPECT().GetTOTP(ctx, int64(1)).Return(newUserTOTP(false, 0), nil)
+	mfaRepoMock.EXPECT().GetTOTP(ctx, int64(2)).Return(&models.UserTOTP{User: &models.User{ID: 2}}, nil)
+
+	recoveryCodes, err := mfaService.Confirm(ctx, 1, "abcdef")
+
+	assert.Nil(recoveryCodes)
+	assert.Equal(&todoErr.InvalidValueError{Resource: "totp", Field: "code", Reason: "Invalid code"}, err)
+
+	recoveryCodes, err = mfaService.Confirm(ctx, 2, "123456")
+
+	assert.Nil(recoveryCodes)
+	assert.Equal(&todoErr.InvalidValueError{Resource: "totp", Field: "code", Reason: "TOTP enrollment not started"}, err)
+}
+
+func TestVerifyWithTOTPCode(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mfaRepoMock := repoMock.NewRepository(mockCtrl)
+	mfaService := service.New(mfaRepoMock, newBox(), newMfaSetting())
+	code, step := currentCode()
+	userTOTP := newUserTOTP(true, step-5)
+
+	gomock.InOrder(
+		mfaRepoMock.EXPECT().GetTOTP(ctx, int64(1)).Return(userTOTP, nil),
+		mfaRepoMock.EXPECT().UseStep(ctx, int64(1), step).Return(true, nil),
+	)
+
+	verifiedUser, err := mfaService.Verify(ctx, 1, code)
+
+	assert.NoError(err)
+	assert.Equal(userTOTP.User, verifiedUser)
+}
+
+func TestVerifyWithReplayedCode(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mfaRepoMock := repoMock.NewRepository(mockCtrl)
+	mfaService := service.New(mfaRepoMock, newBox(), newMfaSetting())
+	code, step := currentCode()
+
+	mfaRepoMock.EXPECT().GetTOTP(ctx, int64(1)).Return(newUserTOTP(true, step), nil)
+
+	verifiedUser, err := mfaService.Verify(ctx, 1, code)
+
+	assert.Nil(verifiedUser)
+	assert.Equal(&todoErr.InvalidValueError{Resource: "totp", Field: "code", Reason: "Invalid code"}, err)
+}
+
+func TestVerifyWithRecoveryCode(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mfaRepoMock := repoMock.NewRepository(mockCtrl)
+	mfaService := service.New(mfaRepoMock, newBox(), newMfaSetting())
+
+	mfaRepoMock.EXPECT().GetTOTP(ctx, int64(1)).Return(newUserTOTP(true, 0), nil).Times(2)
+	mfaRepoMock.EXPECT().UseRecoveryCode(ctx, int64(1), hash("abcde23456"), gomock.Any()).Return(true, nil)
+	mfaRepoMock.EXPECT().UseRecoveryCode(ctx, int64(1), hash("abcde23456"), gomock.Any()).Return(false, nil)
+
+	verifiedUser, err := mfaService.Verify(ctx, 1, "ABCDE-23456")
+
+	assert.NoError(err)
+	assert.NotNil(verifiedUser)
+
+	verifiedUser, err = mfaService.Verify(ctx, 1, "abcde-23456")
+
+	assert.Nil(verifiedUser)
+	assert.Equal(&todoErr.InvalidValueError{Resource: "totp", Field: "code", Reason: "Invalid code"}, err)
+}
+
+func TestDisable(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mfaRepoMock := repoMock.NewRepository(mockCtrl)
+	mfaService := service.New(mfaRepoMock, newBox(), newMfaSetting())
+	code, step := currentCode()
+
+	gomock.InOrder(
+		mfaRepoMock.EXPECT().GetTOTP(ctx, int64(1)).Return(newUserTOTP(true, 0), nil),
+		mfaRepoMock.EXPECT().UseStep(ctx, int64(1), step).Return(true, nil),
+		mfaRepoMock.EXPECT().Disable(ctx, int64(1), gomock.Any()).Return(nil),
+	)
+
+	assert.NoError(mfaService.Disable(ctx, 1, code))
+}
+
+func TestDisableWithInvalidCode(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mfaRepoMock := repoMock.NewRepository(mockCtrl)
+	mfaService := service.New(mfaRepoMock, newBox(), newMfaSetting())
+
+	mfaRepoMock.EXPECT().GetTOTP(ctx, int64(1)).Return(newUserTOTP(true, 0), nil)
+	mfaRepoMock.EXPECT().UseRecoveryCode(ctx, int64(1), hash("wrong"), gomock.Any()).Return(false, nil)
+
+	err := mfaService.Disable(ctx, 1, "wrong")
+
+	assert.Equal(&todoErr.InvalidValueError{Resource: "totp", Field: "code", Reason: "Invalid code"}, err)
+}
@@ -0,0 +1,111 @@
+package totp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Parameters of the codes, which are the defaults of RFC 6238 and the only ones most authenticator apps support
+const (
+	Digits = 6
+	Period = 30
+)
+
+// modulo is 10 to the power of Digits, which cuts the truncated HMAC to a code
+const modulo = 1000000
+
+// secretSize is the number of random bytes in a secret, which is the size of a SHA-1 HMAC key as RFC 4226 recommends
+const secretSize = 20
+
+// skew is the number of time steps before and after the current one, whose codes are also accepted,
+// so that small clock drifts and typing delays do not fail the code
+const skew = 1
+
+// encoding is the base32 encoding of secrets, without padding since authenticator apps do not expect it
+var encoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GenerateSecret returns a new random secret, base32 encoded
+func GenerateSecret() (string, error) {
+	secret := make([]byte, secretSize)
+
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+
+	return encoding.EncodeToString(secret), nil
+}
+
+// ProvisioningURI returns the otpauth URI of a secret, which authenticator apps read from a QR code
+func ProvisioningURI(issuer string, account string, secret string) string {
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", issuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", strconv.Itoa(Digits))
+	query.Set("period", strconv.Itoa(Period))
+
+	label := url.PathEscape(issuer) + ":" + url.PathEscape(account)
+
+	return "otpauth://totp/" + label + "?" + query.Encode()
+}
+
+// Step returns the time step of a time
+func Step(at time.Time) int64 {
+	return at.Unix() / Period
+}
+
+// Code returns the code of a secret at a time step
+func Code(secret string, step int64) (string, error) {
+	key, err := encoding.DecodeString(strings.ToUpper(secret))
+
+	if err != nil {
+		return "", err
+	}
+
+	counter := make([]byte, 8)
+	binary.BigEndian.PutUint64(counter, uint64(step))
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(counter)
+	sum := mac.Sum(nil)
+
+	// dynamic truncation of RFC 4226
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	code := strconv.FormatUint(uint64(value%modulo), 10)
+
+	return strings.Repeat("0", Digits-len(code)) + code, nil
+}
+
+// Validate checks a code against the secret around the time, and returns the time step which the code belongs to.
+// Callers should reject steps which were already used, so that a code can not be replayed.
+func Validate(secret string, code string, at time.Time) (int64, bool, error) {
+	if len(code) != Digits {
+		return 0, false, nil
+	}
+
+	current := Step(at)
+
+	for step := current - skew; step <= current+skew; step++ {
+		expected, err := Code(secret, step)
+
+		if err != nil {
+			return 0, false, err
+		}
+
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			return step, true, nil
+		}
+	}
+
+	return 0, false, nil
+}
@@ -0,0 +1,85 @@
+package totp_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dheerajgopi/todo-api/mfa/totp"
+	"github.com/stretchr/testify/assert"
+)
+
+// rfcSecret is the base32 encoded SHA-1 secret of the RFC 6238 test vectors, "12345678901234567890"
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestCode(t *testing.T) {
+	assert := assert.New(t)
+
+	// last six digits of the SHA-1 test vectors of RFC 6238
+	vectors := map[int64]string{
+		59:          "287082",
+		1111111109:  "081804",
+		1111111111:  "050471",
+		1234567890:  "005924",
+		2000000000:  "279037",
+		20000000000: "353130",
+	}
+
+	for unix, expected := range vectors {
+		code, err := totp.Code(rfcSecret, totp.Step(time.Unix(unix, 0)))
+
+		assert.NoError(err)
+		assert.Equal(expected, code, unix)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	assert := assert.New(t)
+	at := time.Unix(1111111111, 0)
+
+	step, valid, err := totp.Validate(rfcSecret, "050471", at)
+
+	assert.NoError(err)
+	assert.True(valid)
+	assert.Equal(totp.Step(at), step)
+
+	// code of the previous step is accepted for clock drift
+	step, valid, err = totp.Validate(rfcSecret, "081804", at)
+
+	assert.NoError(err)
+	assert.True(valid)
+	assert.Equal(totp.Step(at)-1, step)
+
+	_, valid, err = totp.Validate(rfcSecret, "050471", at.Add(2*time.Minute))
+
+	assert.NoError(err)
+	assert.False(valid)
+
+	_, valid, err = totp.Validate(rfcSecret, "50471", at)
+
+	assert.NoError(err)
+	assert.False(valid)
+}
+
+func TestGenerateSecret(t *testing.T) {
+	assert := assert.New(t)
+
+	secret, err := totp.GenerateSecret()
+
+	assert.NoError(err)
+	assert.Equal(32, len(secret))
+
+	code, err := totp.Code(secret, totp.Step(time.Now()))
+
+	assert.NoError(err)
+	assert.Equal(totp.Digits, len(code))
+}
+
+func TestProvisioningURI(t *testing.T) {
+	uri := totp.ProvisioningURI("todo api", "user@email.com", rfcSecret)
+
+	assert.Equal(
+		t,
+		"otpauth://totp/todo%20api:user@email.com?algorithm=SHA1&digits=6&issuer=todo+api&period=30&secret="+rfcSecret,
+		uri,
+	)
+}
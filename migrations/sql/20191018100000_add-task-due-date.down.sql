@@ -0,0 +1,6 @@
+-- drop due date and reminder from task table
+ALTER TABLE task
+  DROP KEY idx_created_by_due_at,
+  DROP COLUMN remind_at,
+  DROP COLUMN due_timezone,
+  DROP COLUMN due_at;
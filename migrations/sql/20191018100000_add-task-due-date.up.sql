@@ -0,0 +1,6 @@
+-- add due date and reminder to task table
+ALTER TABLE task
+  ADD COLUMN due_at datetime DEFAULT NULL AFTER is_complete,
+  ADD COLUMN due_timezone varchar(64) DEFAULT NULL AFTER due_at,
+  ADD COLUMN remind_at datetime DEFAULT NULL AFTER due_timezone,
+  ADD KEY idx_created_by_due_at (created_by, due_at);
@@ -0,0 +1,5 @@
+-- drop priority and manual sort position from task table
+ALTER TABLE task
+  DROP KEY idx_created_by_position,
+  DROP COLUMN position,
+  DROP COLUMN priority;
@@ -0,0 +1,8 @@
+-- add priority and manual sort position to task table
+ALTER TABLE task
+  ADD COLUMN priority tinyint(1) NOT NULL DEFAULT 0 AFTER is_complete,
+  ADD COLUMN position bigint(20) NOT NULL DEFAULT 0 AFTER priority,
+  ADD KEY idx_created_by_position (created_by, position);
+
+-- spread existing tasks in creation order
+UPDATE task SET position = id * 65536;
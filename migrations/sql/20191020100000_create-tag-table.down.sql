@@ -0,0 +1,3 @@
+-- drop task_tag and tag tables
+DROP TABLE task_tag;
+DROP TABLE tag;
@@ -0,0 +1,22 @@
+-- create tag table
+CREATE TABLE tag (
+  id bigint(20) NOT NULL AUTO_INCREMENT,
+  name varchar(64) NOT NULL,
+  color varchar(7) DEFAULT NULL,
+  created_by bigint(20) NOT NULL,
+  created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (id),
+  UNIQUE KEY unique_created_by_name (created_by, name),
+  CONSTRAINT tag_ibfk_1 FOREIGN KEY (created_by) REFERENCES user (id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- create task_tag table
+CREATE TABLE task_tag (
+  task_id bigint(20) NOT NULL,
+  tag_id bigint(20) NOT NULL,
+  PRIMARY KEY (task_id, tag_id),
+  KEY idx_tag_id (tag_id),
+  CONSTRAINT task_tag_ibfk_1 FOREIGN KEY (task_id) REFERENCES task (id) ON DELETE CASCADE,
+  CONSTRAINT task_tag_ibfk_2 FOREIGN KEY (tag_id) REFERENCES tag (id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
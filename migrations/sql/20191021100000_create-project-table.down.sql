@@ -0,0 +1,7 @@
+-- drop project from task table and drop project table
+ALTER TABLE task
+  DROP FOREIGN KEY task_ibfk_2,
+  DROP KEY idx_project_id,
+  DROP COLUMN project_id;
+
+DROP TABLE project;
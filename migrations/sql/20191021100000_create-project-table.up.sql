@@ -0,0 +1,18 @@
+-- create project table
+CREATE TABLE project (
+  id bigint(20) NOT NULL AUTO_INCREMENT,
+  name varchar(255) NOT NULL,
+  is_archived tinyint(1) NOT NULL DEFAULT 0,
+  created_by bigint(20) NOT NULL,
+  created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (id),
+  KEY idx_created_by (created_by),
+  CONSTRAINT project_ibfk_1 FOREIGN KEY (created_by) REFERENCES user (id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- add project to task table, tasks without project belong to the inbox
+ALTER TABLE task
+  ADD COLUMN project_id bigint(20) DEFAULT NULL AFTER created_by,
+  ADD KEY idx_project_id (project_id),
+  ADD CONSTRAINT task_ibfk_2 FOREIGN KEY (project_id) REFERENCES project (id) ON DELETE SET NULL;
@@ -0,0 +1,6 @@
+-- drop parent task and auto completion from task table
+ALTER TABLE task
+  DROP FOREIGN KEY task_ibfk_3,
+  DROP KEY idx_parent_id,
+  DROP COLUMN auto_complete,
+  DROP COLUMN parent_id;
@@ -0,0 +1,6 @@
+-- add parent task and auto completion to task table
+ALTER TABLE task
+  ADD COLUMN parent_id bigint(20) DEFAULT NULL AFTER project_id,
+  ADD COLUMN auto_complete tinyint(1) NOT NULL DEFAULT 0 AFTER is_complete,
+  ADD KEY idx_parent_id (parent_id),
+  ADD CONSTRAINT task_ibfk_3 FOREIGN KEY (parent_id) REFERENCES task (id) ON DELETE CASCADE;
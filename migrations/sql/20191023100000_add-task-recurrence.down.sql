@@ -0,0 +1,4 @@
+-- drop recurrence rule and series start from task table
+ALTER TABLE task
+  DROP COLUMN recurrence_start,
+  DROP COLUMN recurrence;
@@ -0,0 +1,4 @@
+-- add recurrence rule and series start to task table
+ALTER TABLE task
+  ADD COLUMN recurrence varchar(255) DEFAULT NULL AFTER remind_at,
+  ADD COLUMN recurrence_start datetime DEFAULT NULL AFTER recurrence;
@@ -0,0 +1,4 @@
+-- drop collaborator tables
+DROP TABLE project_collaborator;
+
+DROP TABLE task_collaborator;
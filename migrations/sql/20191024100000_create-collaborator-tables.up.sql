@@ -0,0 +1,23 @@
+-- create task_collaborator table for tasks shared with other users
+CREATE TABLE task_collaborator (
+  task_id bigint(20) NOT NULL,
+  user_id bigint(20) NOT NULL,
+  role varchar(16) NOT NULL,
+  created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (task_id, user_id),
+  KEY idx_user_id (user_id),
+  CONSTRAINT task_collaborator_ibfk_1 FOREIGN KEY (task_id) REFERENCES task (id) ON DELETE CASCADE,
+  CONSTRAINT task_collaborator_ibfk_2 FOREIGN KEY (user_id) REFERENCES user (id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- create project_collaborator table for projects shared with other users
+CREATE TABLE project_collaborator (
+  project_id bigint(20) NOT NULL,
+  user_id bigint(20) NOT NULL,
+  role varchar(16) NOT NULL,
+  created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (project_id, user_id),
+  KEY idx_user_id (user_id),
+  CONSTRAINT project_collaborator_ibfk_1 FOREIGN KEY (project_id) REFERENCES project (id) ON DELETE CASCADE,
+  CONSTRAINT project_collaborator_ibfk_2 FOREIGN KEY (user_id) REFERENCES user (id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
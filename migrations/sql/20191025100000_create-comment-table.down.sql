@@ -0,0 +1,2 @@
+-- drop comment table
+DROP TABLE comment;
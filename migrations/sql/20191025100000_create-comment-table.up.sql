@@ -0,0 +1,13 @@
+-- create comment table for discussions on tasks
+CREATE TABLE comment (
+  id bigint(20) NOT NULL AUTO_INCREMENT,
+  task_id bigint(20) NOT NULL,
+  body text NOT NULL,
+  created_by bigint(20) NOT NULL,
+  created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  edited_at timestamp NULL DEFAULT NULL,
+  PRIMARY KEY (id),
+  KEY idx_task_id_created_at (task_id, created_at),
+  CONSTRAINT comment_ibfk_1 FOREIGN KEY (task_id) REFERENCES task (id) ON DELETE CASCADE,
+  CONSTRAINT comment_ibfk_2 FOREIGN KEY (created_by) REFERENCES user (id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
@@ -0,0 +1,2 @@
+-- drop attachment table
+DROP TABLE attachment;
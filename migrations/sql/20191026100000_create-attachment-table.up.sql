@@ -0,0 +1,17 @@
+-- create attachment table for files attached to tasks
+CREATE TABLE attachment (
+  id bigint(20) NOT NULL AUTO_INCREMENT,
+  task_id bigint(20) NOT NULL,
+  file_name varchar(255) NOT NULL,
+  content_type varchar(255) NOT NULL,
+  size bigint(20) NOT NULL,
+  storage_key varchar(255) NOT NULL,
+  created_by bigint(20) NOT NULL,
+  created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (id),
+  UNIQUE KEY storage_key (storage_key),
+  KEY idx_task_id_created_at (task_id, created_at),
+  KEY idx_created_by (created_by),
+  CONSTRAINT attachment_ibfk_1 FOREIGN KEY (task_id) REFERENCES task (id) ON DELETE CASCADE,
+  CONSTRAINT attachment_ibfk_2 FOREIGN KEY (created_by) REFERENCES user (id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
@@ -0,0 +1,3 @@
+-- drop full-text indexes of tasks and comments
+ALTER TABLE comment DROP KEY ft_body;
+ALTER TABLE task DROP KEY ft_title_description;
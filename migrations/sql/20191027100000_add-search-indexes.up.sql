@@ -0,0 +1,3 @@
+-- add full-text indexes for searching tasks and comments
+ALTER TABLE task ADD FULLTEXT KEY ft_title_description (title, description);
+ALTER TABLE comment ADD FULLTEXT KEY ft_body (body);
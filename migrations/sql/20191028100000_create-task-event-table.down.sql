@@ -0,0 +1,2 @@
+-- drop task_event table
+DROP TABLE task_event;
@@ -0,0 +1,15 @@
+-- create task_event table for the history of task changes
+-- task_id is not a foreign key, so that the events outlive the deleted tasks
+CREATE TABLE task_event (
+  id bigint(20) NOT NULL AUTO_INCREMENT,
+  task_id bigint(20) NOT NULL,
+  type varchar(32) NOT NULL,
+  old_value text NULL,
+  new_value text NULL,
+  actor_id bigint(20) DEFAULT NULL,
+  request_id varchar(64) DEFAULT NULL,
+  created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (id),
+  KEY idx_task_id (task_id, id),
+  CONSTRAINT task_event_ibfk_1 FOREIGN KEY (actor_id) REFERENCES user (id) ON DELETE SET NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
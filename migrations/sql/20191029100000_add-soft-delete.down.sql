@@ -0,0 +1,8 @@
+-- drop soft delete from task and user tables
+ALTER TABLE user
+  DROP KEY idx_deleted_at,
+  DROP COLUMN deleted_at;
+
+ALTER TABLE task
+  DROP KEY idx_deleted_at,
+  DROP COLUMN deleted_at;
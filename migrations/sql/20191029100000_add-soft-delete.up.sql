@@ -0,0 +1,8 @@
+-- add soft delete to task and user tables
+ALTER TABLE task
+  ADD COLUMN deleted_at datetime DEFAULT NULL AFTER updated_at,
+  ADD KEY idx_deleted_at (deleted_at);
+
+ALTER TABLE user
+  ADD COLUMN deleted_at datetime DEFAULT NULL AFTER updated_at,
+  ADD KEY idx_deleted_at (deleted_at);
@@ -0,0 +1,5 @@
+-- drop calendar_feed table and the calendar feed index of task table
+ALTER TABLE task
+  DROP KEY idx_created_by_due_at_updated_at;
+
+DROP TABLE calendar_feed;
@@ -0,0 +1,13 @@
+-- create calendar_feed table for the secret calendar feed url of each user
+CREATE TABLE calendar_feed (
+  user_id bigint(20) NOT NULL,
+  token_hash char(64) NOT NULL,
+  created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (user_id),
+  UNIQUE KEY unique_token_hash (token_hash),
+  CONSTRAINT calendar_feed_ibfk_1 FOREIGN KEY (user_id) REFERENCES user (id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- covering index for the version of calendar feeds, which is polled by calendar clients
+ALTER TABLE task
+  ADD KEY idx_created_by_due_at_updated_at (created_by, due_at, updated_at, deleted_at);
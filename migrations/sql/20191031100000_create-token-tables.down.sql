@@ -0,0 +1,4 @@
+-- drop refresh_token and revoked_token tables
+DROP TABLE revoked_token;
+
+DROP TABLE refresh_token;
@@ -0,0 +1,23 @@
+-- create refresh_token table for the rotating refresh tokens, and revoked_token table for revoked access tokens
+CREATE TABLE refresh_token (
+  id bigint(20) NOT NULL AUTO_INCREMENT,
+  user_id bigint(20) NOT NULL,
+  token_hash char(64) NOT NULL,
+  family_id char(36) NOT NULL,
+  expires_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  used_at timestamp NULL DEFAULT NULL,
+  revoked_at timestamp NULL DEFAULT NULL,
+  created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (id),
+  UNIQUE KEY unique_token_hash (token_hash),
+  KEY idx_family_id (family_id),
+  KEY idx_user_id_expires_at (user_id, expires_at),
+  CONSTRAINT refresh_token_ibfk_1 FOREIGN KEY (user_id) REFERENCES user (id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+CREATE TABLE revoked_token (
+  id char(36) NOT NULL,
+  expires_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (id),
+  KEY idx_expires_at (expires_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
@@ -0,0 +1,2 @@
+-- drop user_token table
+DROP TABLE user_token;
@@ -0,0 +1,14 @@
+-- create user_token table for the single use email verification and password reset tokens
+CREATE TABLE user_token (
+  id bigint(20) NOT NULL AUTO_INCREMENT,
+  user_id bigint(20) NOT NULL,
+  purpose varchar(20) NOT NULL,
+  token_hash char(64) NOT NULL,
+  expires_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  used_at timestamp NULL DEFAULT NULL,
+  created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (id),
+  UNIQUE KEY unique_token_hash (token_hash),
+  KEY idx_user_id_purpose (user_id, purpose),
+  CONSTRAINT user_token_ibfk_1 FOREIGN KEY (user_id) REFERENCES user (id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
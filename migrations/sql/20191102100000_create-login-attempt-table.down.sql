@@ -0,0 +1,2 @@
+-- drop login_attempt table
+DROP TABLE login_attempt;
@@ -0,0 +1,9 @@
+-- create login_attempt table for the failed login attempts of accounts and IP addresses
+CREATE TABLE login_attempt (
+  key_hash char(64) NOT NULL,
+  failures int(11) NOT NULL DEFAULT 0,
+  last_failed_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  blocked_until timestamp NULL DEFAULT NULL,
+  PRIMARY KEY (key_hash),
+  KEY idx_last_failed_at (last_failed_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
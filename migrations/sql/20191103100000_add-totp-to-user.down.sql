@@ -0,0 +1,7 @@
+-- drop recovery_code table and TOTP columns of user table
+DROP TABLE recovery_code;
+
+ALTER TABLE user
+  DROP COLUMN totp_last_step,
+  DROP COLUMN totp_enabled,
+  DROP COLUMN totp_secret;
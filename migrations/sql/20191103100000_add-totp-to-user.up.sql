@@ -0,0 +1,16 @@
+-- add TOTP columns to user table, and create recovery_code table for the single-use recovery codes of two-factor authentication
+ALTER TABLE user
+  ADD COLUMN totp_secret varchar(255) NULL DEFAULT NULL,
+  ADD COLUMN totp_enabled tinyint(1) NOT NULL DEFAULT 0,
+  ADD COLUMN totp_last_step bigint(20) NOT NULL DEFAULT 0;
+
+CREATE TABLE recovery_code (
+  id bigint(20) NOT NULL AUTO_INCREMENT,
+  user_id bigint(20) NOT NULL,
+  code_hash char(64) NOT NULL,
+  used_at timestamp NULL DEFAULT NULL,
+  created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (id),
+  UNIQUE KEY unique_user_id_code_hash (user_id, code_hash),
+  CONSTRAINT recovery_code_ibfk_1 FOREIGN KEY (user_id) REFERENCES user (id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
@@ -0,0 +1,2 @@
+-- drop api_key table
+DROP TABLE api_key;
@@ -0,0 +1,16 @@
+-- create api_key table for the personal API keys of users, which scripts use instead of access tokens
+CREATE TABLE api_key (
+  id bigint(20) NOT NULL AUTO_INCREMENT,
+  user_id bigint(20) NOT NULL,
+  name varchar(64) NOT NULL,
+  prefix char(12) NOT NULL,
+  key_hash char(64) NOT NULL,
+  scopes varchar(255) NOT NULL,
+  expires_at timestamp NULL DEFAULT NULL,
+  last_used_at timestamp NULL DEFAULT NULL,
+  created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (id),
+  UNIQUE KEY unique_key_hash (key_hash),
+  KEY idx_user_id (user_id),
+  CONSTRAINT api_key_ibfk_1 FOREIGN KEY (user_id) REFERENCES user (id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
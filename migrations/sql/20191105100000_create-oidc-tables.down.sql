@@ -0,0 +1,4 @@
+-- drop oidc_state and user_identity tables
+DROP TABLE user_identity;
+
+DROP TABLE oidc_state;
@@ -0,0 +1,22 @@
+-- create oidc_state table for the OpenID Connect logins in progress, and user_identity table for the linked provider subjects
+CREATE TABLE oidc_state (
+  state_hash char(64) NOT NULL,
+  verifier varchar(64) NOT NULL,
+  nonce varchar(64) NOT NULL,
+  expires_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (state_hash),
+  KEY idx_expires_at (expires_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+CREATE TABLE user_identity (
+  id bigint(20) NOT NULL AUTO_INCREMENT,
+  user_id bigint(20) NOT NULL,
+  issuer varchar(255) CHARACTER SET ascii NOT NULL,
+  subject varchar(255) CHARACTER SET ascii NOT NULL,
+  created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (id),
+  UNIQUE KEY unique_issuer_subject (issuer, subject),
+  KEY idx_user_id (user_id),
+  CONSTRAINT user_identity_ibfk_1 FOREIGN KEY (user_id) REFERENCES user (id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
@@ -0,0 +1,6 @@
+-- drop email column of user_token table and deactivated_at column of user table
+ALTER TABLE user_token
+  DROP COLUMN email;
+
+ALTER TABLE user
+  DROP COLUMN deactivated_at;
@@ -0,0 +1,6 @@
+-- add deactivated_at column to user table, and the new email of email change tokens to user_token table
+ALTER TABLE user
+  ADD COLUMN deactivated_at timestamp NULL DEFAULT NULL;
+
+ALTER TABLE user_token
+  ADD COLUMN email varchar(255) NULL DEFAULT NULL;
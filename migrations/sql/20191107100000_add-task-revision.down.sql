@@ -0,0 +1,7 @@
+-- drop revision column of task table, and restore the calendar feed index without it
+ALTER TABLE task
+  DROP KEY idx_created_by_due_at_updated_at,
+  ADD KEY idx_created_by_due_at_updated_at (created_by, due_at, updated_at, deleted_at);
+
+ALTER TABLE task
+  DROP COLUMN revision;
@@ -0,0 +1,8 @@
+-- add revision column to task table, which is incremented on every update so that calendar feed versions change within a second
+ALTER TABLE task
+  ADD COLUMN revision bigint(20) NOT NULL DEFAULT 0;
+
+-- keep the calendar feed version covered by the index
+ALTER TABLE task
+  DROP KEY idx_created_by_due_at_updated_at,
+  ADD KEY idx_created_by_due_at_updated_at (created_by, due_at, updated_at, deleted_at, revision);
@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// APIKey represents api_key table.
+// Only the SHA-256 hash of the key is stored, along with its first characters which tell the keys of an user apart.
+// Keys without expiry are valid until revoked.
+type APIKey struct {
+	ID         int64
+	User       *User
+	Name       string
+	Prefix     string
+	KeyHash    string
+	Scopes     []string
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+	CreatedAt  time.Time
+}
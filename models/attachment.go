@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// Attachment represents attachment table.
+// Content of the attachment is kept in the blob storage under StorageKey.
+type Attachment struct {
+	ID          int64
+	Task        *Task
+	FileName    string
+	ContentType string
+	Size        int64
+	StorageKey  string
+	CreatedBy   *User
+	CreatedAt   time.Time
+}
@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+// CalendarFeed represents calendar_feed table.
+// Only the SHA-256 hash of the secret token in the feed url is stored.
+type CalendarFeed struct {
+	User      *User
+	TokenHash string
+	CreatedAt time.Time
+}
@@ -0,0 +1,10 @@
+package models
+
+import "time"
+
+// Collaborator represents an user with whom a task or a project is shared, along with the role of the user
+type Collaborator struct {
+	User      *User
+	Role      string
+	CreatedAt time.Time
+}
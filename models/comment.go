@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// Comment represents comment table.
+// EditedAt is nil until the comment is edited for the first time.
+type Comment struct {
+	ID        int64
+	Task      *Task
+	Body      string
+	CreatedBy *User
+	CreatedAt time.Time
+	EditedAt  *time.Time
+}
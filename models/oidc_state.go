@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// OidcState represents oidc_state table, which keeps a started OpenID Connect login until the provider redirects back.
+// Only the SHA-256 hash of the state is stored. Verifier is the PKCE code verifier, and nonce is expected in the ID token.
+type OidcState struct {
+	StateHash string
+	Verifier  string
+	Nonce     string
+	ExpiresAt time.Time
+	CreatedAt time.Time
+}
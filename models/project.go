@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// Project represents project table.
+// Task counts are derived from the tasks of the project, and are not stored.
+// SharedBy is set to the owner when the project is read by a collaborator.
+type Project struct {
+	ID             int64
+	Name           string
+	IsArchived     bool
+	CreatedBy      *User
+	SharedBy       *User
+	OpenTasks      int64
+	CompletedTasks int64
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
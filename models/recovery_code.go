@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// RecoveryCode represents recovery_code table, which holds the single-use codes which replace a lost TOTP device.
+// Only the SHA-256 hash of the code is stored.
+type RecoveryCode struct {
+	ID        int64
+	User      *User
+	CodeHash  string
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
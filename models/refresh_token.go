@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// RefreshToken represents refresh_token table.
+// Only the SHA-256 hash of the token is stored. Tokens rotated from the same login share a family.
+type RefreshToken struct {
+	ID        int64
+	User      *User
+	TokenHash string
+	FamilyID  string
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	RevokedAt *time.Time
+	CreatedAt time.Time
+}
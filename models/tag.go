@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// Tag represents tag table
+type Tag struct {
+	ID        int64
+	Name      string
+	Color     string
+	CreatedBy *User
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
@@ -6,11 +6,33 @@ import (
 
 // Task represents task table
 type Task struct {
-	ID          int64     `json:"id"`
-	Title       string    `json:"title"`
-	Description string    `json:"description" validate:"required"`
-	CreatedBy   *User     `json:"user" validate:"required"`
-	IsComplete  bool      `json:"isComplete"`
-	CreatedAt   time.Time `json:"createdAt"`
-	UpdatedAt   time.Time `json:"updatedAt"`
+	ID              int64      `json:"id"`
+	Title           string     `json:"title"`
+	Description     string     `json:"description" validate:"required"`
+	CreatedBy       *User      `json:"user" validate:"required"`
+	SharedBy        *User      `json:"sharedBy"`
+	Project         *Project   `json:"project"`
+	Parent          *Task      `json:"parent"`
+	IsComplete      bool       `json:"isComplete"`
+	AutoComplete    bool       `json:"autoComplete"`
+	Priority        int        `json:"priority"`
+	Position        int64      `json:"position"`
+	DueAt           *time.Time `json:"dueAt"`
+	DueTimezone     string     `json:"dueTimezone"`
+	RemindAt        *time.Time `json:"remindAt"`
+	Recurrence      string     `json:"recurrence"`
+	RecurrenceStart *time.Time `json:"recurrenceStart"`
+	Tags            []*Tag     `json:"tags"`
+	Subtasks        []*Task    `json:"subtasks"`
+	Progress        *Progress  `json:"progress"`
+	CommentCount    int64      `json:"commentCount"`
+	CreatedAt       time.Time  `json:"createdAt"`
+	UpdatedAt       time.Time  `json:"updatedAt"`
+	DeletedAt       *time.Time `json:"deletedAt"`
+}
+
+// Progress represents the completion of the subtasks of a task
+type Progress struct {
+	Completed int64 `json:"completed"`
+	Total     int64 `json:"total"`
 }
@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// TaskEvent represents task_event table. Events are appended on every change of a task, and never updated.
+// Actor is nil if the change was not made by an user, and values are nil if they do not apply to the event.
+type TaskEvent struct {
+	ID        int64
+	Task      *Task
+	Type      string
+	OldValue  *string
+	NewValue  *string
+	Actor     *User
+	RequestID string
+	CreatedAt time.Time
+}
@@ -2,13 +2,18 @@ package models
 
 import "time"
 
-// User represents user table
+// User represents user table.
+// TotpEnabled tells whether the user logs in with a TOTP code besides the password.
+// Deactivated users keep their data, but can not log in or use their tokens and API keys.
 type User struct {
-	ID        int64
-	Name      string
-	Email     string
-	Passwd    string
-	IsActive  bool
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	ID            int64
+	Name          string
+	Email         string
+	Passwd        string
+	IsActive      bool
+	TotpEnabled   bool
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	DeletedAt     *time.Time
+	DeactivatedAt *time.Time
 }
@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+// UserIdentity represents user_identity table, which links an user to the subject of an OpenID Connect provider
+type UserIdentity struct {
+	User      *User
+	Issuer    string
+	Subject   string
+	CreatedAt time.Time
+}
@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// UserToken represents user_token table, which holds the email verification, password reset and email change tokens.
+// Only the SHA-256 hash of the token is stored. Email is the new email of an email change token.
+type UserToken struct {
+	ID        int64
+	User      *User
+	Purpose   string
+	TokenHash string
+	Email     string
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
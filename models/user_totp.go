@@ -0,0 +1,11 @@
+package models
+
+// UserTOTP represents the TOTP columns of user table.
+// Secret is encrypted, and empty until the user enrolls. It is pending until enabled by a confirmed code.
+// LastStep is the time step of the last accepted code, so that a code can not be used twice.
+type UserTOTP struct {
+	User     *User
+	Secret   string
+	Enabled  bool
+	LastStep int64
+}
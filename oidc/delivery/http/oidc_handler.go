@@ -0,0 +1,168 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/dheerajgopi/todo-api/auth"
+	"github.com/dheerajgopi/todo-api/common"
+	todoErr "github.com/dheerajgopi/todo-api/common/error"
+	"github.com/dheerajgopi/todo-api/oidc"
+	"github.com/gorilla/mux"
+)
+
+// OidcHandler represents HTTP handler for OpenID Connect login.
+// Users who logged in at the provider get the same tokens as after a password login.
+type OidcHandler struct {
+	OidcService oidc.Service
+	AuthService auth.Service
+	App         *common.App
+}
+
+// New creates new HTTP handler for OpenID Connect login
+func New(router *mux.Router, service oidc.Service, authService auth.Service, app *common.App) {
+	handler := &OidcHandler{
+		OidcService: service,
+		AuthService: authService,
+		App:         app,
+	}
+
+	router.HandleFunc("/login/oidc", app.CreateHandler(handler.Begin)).Methods("GET")
+	router.HandleFunc("/login/oidc", app.CreateHandler(handler.Complete)).Methods("POST")
+}
+
+// Begin will start a login, and return the authorization URL of the provider to send the user to
+func (handler *OidcHandler) Begin(res http.ResponseWriter, req *http.Request, reqCtx *common.RequestContext) (int, interface{}, *todoErr.APIError) {
+	timeoutInSec := time.Duration(handler.App.Config.Application.RequestTimeout) * time.Second
+	timeoutContext, cancel := context.WithTimeout(context.TODO(), timeoutInSec)
+	defer cancel()
+
+	authorization, err := handler.OidcService.Begin(timeoutContext)
+
+	if err != nil {
+		return oidcServiceError(err)
+	}
+
+	beginResponse := BeginLoginResponse{
+		AuthorizationURL: authorization.URL,
+		State:            authorization.State,
+		ExpiresAt:        authorization.ExpiresAt,
+	}
+
+	return http.StatusOK, beginResponse, nil
+}
+
+// Complete will exchange the code which the provider redirected back with for an access token and a refresh token.
+// MFA challenge is returned instead if the user has enabled two-factor authentication.
+func (handler *OidcHandler) Complete(res http.ResponseWriter, req *http.Request, reqCtx *common.RequestContext) (int, interface{}, *todoErr.APIError) {
+	timeoutInSec := time.Duration(handler.App.Config.Application.RequestTimeout) * time.Second
+	timeoutContext, cancel := context.WithTimeout(context.TODO(), timeoutInSec)
+	defer cancel()
+	defer req.Body.Close()
+
+	var completeReqBody CompleteLoginRequest
+
+	if err := json.NewDecoder(req.Body).Decode(&completeReqBody); err != nil {
+		reqCtx.AddLogMessage("Invalid request body")
+		apiError := todoErr.NewAPIError("", &todoErr.APIErrorBody{
+			Message: "Invalid request body",
+		})
+
+		return http.StatusBadRequest, nil, apiError
+	}
+
+	if validationErrors := completeReqBody.Validate(); len(validationErrors) > 0 {
+		reqCtx.AddLogMessage("validation error")
+
+		return http.StatusBadRequest, nil, todoErr.NewAPIError("", validationErrors...)
+	}
+
+	authenticatedUser, err := handler.OidcService.Complete(timeoutContext, completeReqBody.State, completeReqBody.Code)
+
+	if err != nil {
+		return oidcServiceError(err)
+	}
+
+	if authenticatedUser.TotpEnabled {
+		challenge, err := handler.AuthService.IssueChallenge(timeoutContext, authenticatedUser)
+
+		if err != nil {
+			apiError := todoErr.NewAPIError(err.Error(), &todoErr.APIErrorBody{
+				Message: "Internal server error",
+			})
+
+			return http.StatusInternalServerError, nil, apiError
+		}
+
+		challengeResponse := MfaChallengeResponse{
+			MfaRequired:        true,
+			ChallengeToken:     challenge.Token,
+			ChallengeExpiresAt: challenge.ExpiresAt,
+		}
+
+		return http.StatusOK, challengeResponse, nil
+	}
+
+	tokens, err := handler.AuthService.Issue(timeoutContext, authenticatedUser)
+
+	if err != nil {
+		apiError := todoErr.NewAPIError(err.Error(), &todoErr.APIErrorBody{
+			Message: "Internal server error",
+		})
+
+		return http.StatusInternalServerError, nil, apiError
+	}
+
+	loginResponse := LoginResponse{
+		Token:            tokens.AccessToken,
+		ExpiresAt:        tokens.AccessExpiresAt,
+		RefreshToken:     tokens.RefreshToken,
+		RefreshExpiresAt: tokens.RefreshExpiresAt,
+	}
+
+	return http.StatusOK, loginResponse, nil
+}
+
+// oidcServiceError maps errors returned by the OpenID Connect login service to the API response
+func oidcServiceError(err error) (int, interface{}, *todoErr.APIError) {
+	switch err.(type) {
+	case *todoErr.InvalidValueError:
+		invalidValueErr, _ := err.(*todoErr.InvalidValueError)
+
+		apiError := todoErr.NewAPIError(invalidValueErr.Error(), &todoErr.APIErrorBody{
+			Message: invalidValueErr.Reason,
+			Target:  invalidValueErr.Field,
+		})
+
+		return http.StatusBadRequest, nil, apiError
+	case *todoErr.UnauthorizedError:
+		apiError := todoErr.NewAPIError(err.Error(), &todoErr.APIErrorBody{
+			Message: "Access denied",
+			Target:  "code",
+		})
+
+		return http.StatusForbidden, nil, apiError
+	case *todoErr.PermissionDeniedError:
+		apiError := todoErr.NewAPIError(err.Error(), &todoErr.APIErrorBody{
+			Message: "Email not verified",
+			Target:  "email",
+		})
+
+		return http.StatusForbidden, nil, apiError
+	case *todoErr.ResourceNotFoundError:
+		apiError := todoErr.NewAPIError(err.Error(), &todoErr.APIErrorBody{
+			Message: "Not found",
+			Target:  "user",
+		})
+
+		return http.StatusNotFound, nil, apiError
+	default:
+		apiError := todoErr.NewAPIError(err.Error(), &todoErr.APIErrorBody{
+			Message: "Internal server error",
+		})
+
+		return http.StatusInternalServerError, nil, apiError
+	}
+}
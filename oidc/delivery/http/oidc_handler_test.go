@@ -0,0 +1,192 @@
+package http_test
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dheerajgopi/todo-api/auth"
+	authMock "github.com/dheerajgopi/todo-api/auth/mock"
+	"github.com/dheerajgopi/todo-api/common"
+	_errors "github.com/dheerajgopi/todo-api/common/error"
+	"github.com/dheerajgopi/todo-api/config"
+	"github.com/dheerajgopi/todo-api/models"
+	"github.com/dheerajgopi/todo-api/oidc"
+	_oidcHandler "github.com/dheerajgopi/todo-api/oidc/delivery/http"
+	mock "github.com/dheerajgopi/todo-api/oidc/mock"
+	"github.com/golang/mock/gomock"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBegin(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService, authMock.NewService(mockCtrl))
+	reqCtx := setupRequestContext(handler.App)
+	req := httptest.NewRequest("GET", "/login/oidc", nil)
+
+	expiresAt := time.Now().Add(10 * time.Minute)
+
+	mockService.
+		EXPECT().
+		Begin(gomock.Any()).
+		Return(&oidc.Authorization{URL: "https://provider/authorize?state=state", State: "state", ExpiresAt: expiresAt}, nil).
+		Times(1)
+
+	status, data, err := handler.Begin(httptest.NewRecorder(), req, reqCtx)
+
+	responseData := data.(_oidcHandler.BeginLoginResponse)
+
+	assert.Equal(200, status)
+	assert.Nil(err)
+	assert.Equal("https://provider/authorize?state=state", responseData.AuthorizationURL)
+	assert.Equal("state", responseData.State)
+	assert.Equal(expiresAt, responseData.ExpiresAt)
+}
+
+func TestCompleteWithInvalidData(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService, authMock.NewService(mockCtrl))
+	reqCtx := setupRequestContext(handler.App)
+	req := httptest.NewRequest("POST", "/login/oidc", strings.NewReader(`{"state":" "}`))
+
+	status, data, err := handler.Complete(httptest.NewRecorder(), req, reqCtx)
+
+	assert.Equal(400, status)
+	assert.Nil(data)
+	assert.Equal(2, len(err.Body))
+	assert.Equal("state", err.Body[0].Target)
+	assert.Equal("code", err.Body[1].Target)
+}
+
+func TestCompleteWithServiceErrors(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService, authMock.NewService(mockCtrl))
+
+	serviceErrors := map[error]int{
+		&_errors.InvalidValueError{Resource: "oidc", Field: "state", Reason: "unknown or expired state"}: 400,
+		&_errors.UnauthorizedError{}:                                     403,
+		&_errors.PermissionDeniedError{Resource: "user", Action: "link"}: 403,
+		&_errors.ResourceNotFoundError{Resource: "user"}:                 404,
+	}
+
+	for serviceErr, expectedStatus := range serviceErrors {
+		reqCtx := setupRequestContext(handler.App)
+		req := httptest.NewRequest("POST", "/login/oidc", strings.NewReader(`{"state":"state","code":"code"}`))
+
+		mockService.
+			EXPECT().
+			Complete(gomock.Any(), "state", "code").
+			Return(nil, serviceErr).
+			Times(1)
+
+		status, data, err := handler.Complete(httptest.NewRecorder(), req, reqCtx)
+
+		assert.Equal(expectedStatus, status, serviceErr.Error())
+		assert.Nil(data)
+		assert.NotNil(err)
+	}
+}
+
+func TestComplete(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	mockAuthService := authMock.NewService(mockCtrl)
+	handler := setupHandler(mockService, mockAuthService)
+	reqCtx := setupRequestContext(handler.App)
+	req := httptest.NewRequest("POST", "/login/oidc", strings.NewReader(`{"state":"state","code":"code"}`))
+
+	authenticatedUser := &models.User{ID: 1, IsActive: true}
+	expiresAt := time.Now().Add(time.Hour)
+
+	mockService.
+		EXPECT().
+		Complete(gomock.Any(), "state", "code").
+		Return(authenticatedUser, nil).
+		Times(1)
+
+	mockAuthService.
+		EXPECT().
+		Issue(gomock.Any(), authenticatedUser).
+		Return(&auth.TokenPair{AccessToken: "access", AccessExpiresAt: expiresAt, RefreshToken: "refresh"}, nil).
+		Times(1)
+
+	status, data, err := handler.Complete(httptest.NewRecorder(), req, reqCtx)
+
+	responseData := data.(_oidcHandler.LoginResponse)
+
+	assert.Equal(200, status)
+	assert.Nil(err)
+	assert.Equal("access", responseData.Token)
+	assert.Equal(expiresAt, responseData.ExpiresAt)
+	assert.Equal("refresh", responseData.RefreshToken)
+}
+
+func TestCompleteWithTotpEnabled(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	mockAuthService := authMock.NewService(mockCtrl)
+	handler := setupHandler(mockService, mockAuthService)
+	reqCtx := setupRequestContext(handler.App)
+	req := httptest.NewRequest("POST", "/login/oidc", strings.NewReader(`{"state":"state","code":"code"}`))
+
+	authenticatedUser := &models.User{ID: 1, IsActive: true, TotpEnabled: true}
+
+	mockService.
+		EXPECT().
+		Complete(gomock.Any(), "state", "code").
+		Return(authenticatedUser, nil).
+		Times(1)
+
+	mockAuthService.
+		EXPECT().
+		IssueChallenge(gomock.Any(), authenticatedUser).
+		Return(&auth.Challenge{Token: "challenge"}, nil).
+		Times(1)
+
+	status, data, err := handler.Complete(httptest.NewRecorder(), req, reqCtx)
+
+	responseData := data.(_oidcHandler.MfaChallengeResponse)
+
+	assert.Equal(200, status)
+	assert.Nil(err)
+	assert.True(responseData.MfaRequired)
+	assert.Equal("challenge", responseData.ChallengeToken)
+}
+
+func setupHandler(mockService oidc.Service, mockAuthService auth.Service) *_oidcHandler.OidcHandler {
+	app := &common.App{
+		Logger: logrus.New(),
+		Config: &config.Config{
+			Application: &config.ApplicationSetting{RequestTimeout: 5},
+		},
+	}
+
+	handler := &_oidcHandler.OidcHandler{
+		OidcService: mockService,
+		AuthService: mockAuthService,
+		App:         app,
+	}
+
+	return handler
+}
+
+func setupRequestContext(app *common.App) *common.RequestContext {
+	reqCtx := &common.RequestContext{
+		RequestID: "dummyRequestID",
+		LogEntry: app.Logger.WithFields(
+			logrus.Fields{},
+		),
+	}
+
+	return reqCtx
+}
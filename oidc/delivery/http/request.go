@@ -0,0 +1,37 @@
+package http
+
+import (
+	"strings"
+
+	todoErr "github.com/dheerajgopi/todo-api/common/error"
+)
+
+// CompleteLoginRequest represents request body for POST /login/oidc API, with the state and code which the provider redirected back with
+type CompleteLoginRequest struct {
+	State string `json:"state"`
+	Code  string `json:"code"`
+}
+
+// Validate validates the request body for POST /login/oidc API
+func (body *CompleteLoginRequest) Validate() []*todoErr.APIErrorBody {
+	validationErrors := make([]*todoErr.APIErrorBody, 0)
+
+	body.State = strings.TrimSpace(body.State)
+	body.Code = strings.TrimSpace(body.Code)
+
+	if body.State == "" {
+		validationErrors = append(validationErrors, &todoErr.APIErrorBody{
+			Message: "Non-empty value is required",
+			Target:  "state",
+		})
+	}
+
+	if body.Code == "" {
+		validationErrors = append(validationErrors, &todoErr.APIErrorBody{
+			Message: "Non-empty value is required",
+			Target:  "code",
+		})
+	}
+
+	return validationErrors
+}
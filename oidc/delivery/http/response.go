@@ -0,0 +1,27 @@
+package http
+
+import "time"
+
+// BeginLoginResponse represents response for GET /login/oidc API.
+// Client sends the user to the authorization URL, and keeps the state to complete the login with.
+type BeginLoginResponse struct {
+	AuthorizationURL string    `json:"authorizationUrl"`
+	State            string    `json:"state"`
+	ExpiresAt        time.Time `json:"expiresAt"`
+}
+
+// LoginResponse represents response for POST /login/oidc API
+type LoginResponse struct {
+	Token            string    `json:"token"`
+	ExpiresAt        time.Time `json:"expiresAt"`
+	RefreshToken     string    `json:"refreshToken"`
+	RefreshExpiresAt time.Time `json:"refreshExpiresAt"`
+}
+
+// MfaChallengeResponse represents response for POST /login/oidc API, when the user has enabled two-factor authentication.
+// Challenge is completed at POST /login/mfa, like after a password login.
+type MfaChallengeResponse struct {
+	MfaRequired        bool      `json:"mfaRequired"`
+	ChallengeToken     string    `json:"challengeToken"`
+	ChallengeExpiresAt time.Time `json:"challengeExpiresAt"`
+}
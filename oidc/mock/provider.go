@@ -0,0 +1,79 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/dheerajgopi/todo-api/oidc (interfaces: Provider)
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	oidc "github.com/dheerajgopi/todo-api/oidc"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+)
+
+// Provider is a mock of Provider interface
+type Provider struct {
+	ctrl     *gomock.Controller
+	recorder *ProviderMockRecorder
+}
+
+// ProviderMockRecorder is the mock recorder for Provider
+type ProviderMockRecorder struct {
+	mock *Provider
+}
+
+// NewProvider creates a new mock instance
+func NewProvider(ctrl *gomock.Controller) *Provider {
+	mock := &Provider{ctrl: ctrl}
+	mock.recorder = &ProviderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *Provider) EXPECT() *ProviderMockRecorder {
+	return m.recorder
+}
+
+// AuthCodeURL mocks base method
+func (m *Provider) AuthCodeURL(arg0 context.Context, arg1, arg2, arg3 string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AuthCodeURL", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AuthCodeURL indicates an expected call of AuthCodeURL
+func (mr *ProviderMockRecorder) AuthCodeURL(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuthCodeURL", reflect.TypeOf((*Provider)(nil).AuthCodeURL), arg0, arg1, arg2, arg3)
+}
+
+// Exchange mocks base method
+func (m *Provider) Exchange(arg0 context.Context, arg1, arg2 string) (*oidc.IDToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Exchange", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*oidc.IDToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Exchange indicates an expected call of Exchange
+func (mr *ProviderMockRecorder) Exchange(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Exchange", reflect.TypeOf((*Provider)(nil).Exchange), arg0, arg1, arg2)
+}
+
+// Issuer mocks base method
+func (m *Provider) Issuer() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Issuer")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Issuer indicates an expected call of Issuer
+func (mr *ProviderMockRecorder) Issuer() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Issuer", reflect.TypeOf((*Provider)(nil).Issuer))
+}
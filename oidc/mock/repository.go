@@ -0,0 +1,107 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/dheerajgopi/todo-api/oidc (interfaces: Repository)
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	models "github.com/dheerajgopi/todo-api/models"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+)
+
+// Repository is a mock of Repository interface
+type Repository struct {
+	ctrl     *gomock.Controller
+	recorder *RepositoryMockRecorder
+}
+
+// RepositoryMockRecorder is the mock recorder for Repository
+type RepositoryMockRecorder struct {
+	mock *Repository
+}
+
+// NewRepository creates a new mock instance
+func NewRepository(ctrl *gomock.Controller) *Repository {
+	mock := &Repository{ctrl: ctrl}
+	mock.recorder = &RepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *Repository) EXPECT() *RepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetUserByIdentity mocks base method
+func (m *Repository) GetUserByIdentity(arg0 context.Context, arg1, arg2 string) (*models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserByIdentity", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserByIdentity indicates an expected call of GetUserByIdentity
+func (mr *RepositoryMockRecorder) GetUserByIdentity(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByIdentity", reflect.TypeOf((*Repository)(nil).GetUserByIdentity), arg0, arg1, arg2)
+}
+
+// Link mocks base method
+func (m *Repository) Link(arg0 context.Context, arg1 *models.UserIdentity) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Link", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Link indicates an expected call of Link
+func (mr *RepositoryMockRecorder) Link(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Link", reflect.TypeOf((*Repository)(nil).Link), arg0, arg1)
+}
+
+// Provision mocks base method
+func (m *Repository) Provision(arg0 context.Context, arg1 *models.UserIdentity) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Provision", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Provision indicates an expected call of Provision
+func (mr *RepositoryMockRecorder) Provision(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Provision", reflect.TypeOf((*Repository)(nil).Provision), arg0, arg1)
+}
+
+// SaveState mocks base method
+func (m *Repository) SaveState(arg0 context.Context, arg1 *models.OidcState) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveState", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveState indicates an expected call of SaveState
+func (mr *RepositoryMockRecorder) SaveState(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveState", reflect.TypeOf((*Repository)(nil).SaveState), arg0, arg1)
+}
+
+// TakeState mocks base method
+func (m *Repository) TakeState(arg0 context.Context, arg1 string) (*models.OidcState, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TakeState", arg0, arg1)
+	ret0, _ := ret[0].(*models.OidcState)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TakeState indicates an expected call of TakeState
+func (mr *RepositoryMockRecorder) TakeState(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TakeState", reflect.TypeOf((*Repository)(nil).TakeState), arg0, arg1)
+}
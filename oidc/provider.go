@@ -0,0 +1,23 @@
+package oidc
+
+import "context"
+
+// IDToken holds the claims of a verified ID token which identify the user
+type IDToken struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+	Nonce         string
+}
+
+// Provider is an OpenID Connect provider, which authenticates users with the authorization code flow and PKCE
+type Provider interface {
+	// Issuer returns the issuer identifier of the provider
+	Issuer() string
+	// AuthCodeURL returns the authorization endpoint URL to which the user is sent, with the state, nonce and S256 code challenge
+	AuthCodeURL(ctx context.Context, state string, nonce string, challenge string) (string, error)
+	// Exchange redeems the authorization code with its PKCE verifier, and returns the verified ID token.
+	// UnauthorizedError is returned if the provider rejects the code or the ID token is not valid.
+	Exchange(ctx context.Context, code string, verifier string) (*IDToken, error)
+}
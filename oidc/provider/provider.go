@@ -0,0 +1,401 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/dheerajgopi/todo-api/auth/signing"
+	todoErr "github.com/dheerajgopi/todo-api/common/error"
+	"github.com/dheerajgopi/todo-api/config"
+	"github.com/dheerajgopi/todo-api/oidc"
+)
+
+// keysRefreshInterval is the minimum time between the fetches of the provider keys, when a token names an unknown key
+const keysRefreshInterval = time.Minute
+
+// clockSkew is the allowed difference between the clocks of the provider and this server
+const clockSkew = time.Minute
+
+// maxResponseSize is the maximum size of a provider response, in bytes
+const maxResponseSize = 1 << 20
+
+// discovery is the part of the provider metadata (OpenID Connect Discovery 1.0) which the login needs
+type discovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JwksURI               string `json:"jwks_uri"`
+}
+
+// tokenResponse is the response of the token endpoint
+type tokenResponse struct {
+	IDToken string `json:"id_token"`
+}
+
+type oidcProvider struct {
+	setting       *config.OidcSetting
+	client        *http.Client
+	mutex         sync.Mutex
+	discovery     *discovery
+	keys          map[string]interface{}
+	keysFetchedAt time.Time
+}
+
+// New will return new object which implements oidc.Provider.
+// Provider metadata is discovered on first use, and the provider keys are fetched again when a token names an unknown key.
+func New(setting *config.OidcSetting) oidc.Provider {
+	return &oidcProvider{
+		setting: setting,
+		client: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+// Issuer returns the issuer identifier of the provider
+func (provider *oidcProvider) Issuer() string {
+	return provider.setting.Issuer
+}
+
+// AuthCodeURL returns the authorization endpoint URL to which the user is sent, with the state, nonce and S256 code challenge
+func (provider *oidcProvider) AuthCodeURL(ctx context.Context, state string, nonce string, challenge string) (string, error) {
+	metadata, err := provider.getDiscovery(ctx)
+
+	if err != nil {
+		return "", err
+	}
+
+	authURL, err := url.Parse(metadata.AuthorizationEndpoint)
+
+	if err != nil {
+		return "", err
+	}
+
+	query := authURL.Query()
+	query.Set("response_type", "code")
+	query.Set("client_id", provider.setting.ClientID)
+	query.Set("redirect_uri", provider.setting.RedirectURL)
+	query.Set("scope", strings.Join(provider.setting.Scopes, " "))
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	query.Set("code_challenge", challenge)
+	query.Set("code_challenge_method", "S256")
+	authURL.RawQuery = query.Encode()
+
+	return authURL.String(), nil
+}
+
+// Exchange redeems the authorization code with its PKCE verifier at the token endpoint, and returns the verified ID token.
+// Client secret, if any, is sent with HTTP basic authentication.
+func (provider *oidcProvider) Exchange(ctx context.Context, code string, verifier string) (*oidc.IDToken, error) {
+	metadata, err := provider.getDiscovery(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", provider.setting.RedirectURL)
+	form.Set("client_id", provider.setting.ClientID)
+	form.Set("code_verifier", verifier)
+
+	req, err := http.NewRequest(http.MethodPost, metadata.TokenEndpoint, strings.NewReader(form.Encode()))
+
+	if err != nil {
+		return nil, err
+	}
+
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	if provider.setting.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(provider.setting.ClientID), url.QueryEscape(provider.setting.ClientSecret))
+	}
+
+	res, err := provider.client.Do(req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer res.Body.Close()
+
+	switch res.StatusCode {
+	case http.StatusOK:
+	case http.StatusBadRequest, http.StatusUnauthorized:
+		// invalid_grant and the like, which the user can not recover from but by logging in again
+		return nil, &todoErr.UnauthorizedError{}
+	default:
+		return nil, responseError(res)
+	}
+
+	tokens := &tokenResponse{}
+
+	if err = json.NewDecoder(io.LimitReader(res.Body, maxResponseSize)).Decode(tokens); err != nil {
+		return nil, err
+	}
+
+	if tokens.IDToken == "" {
+		return nil, errors.New("token response has no id_token")
+	}
+
+	return provider.verify(ctx, metadata, tokens.IDToken)
+}
+
+// verify validates the signature and the claims of an ID token, as OpenID Connect Core 1.0 section 3.1.3.7 asks
+func (provider *oidcProvider) verify(ctx context.Context, metadata *discovery, rawToken string) (*oidc.IDToken, error) {
+	var keyErr error
+
+	parser := &jwt.Parser{
+		SkipClaimsValidation: true,
+	}
+
+	token, err := parser.Parse(rawToken, func(token *jwt.Token) (interface{}, error) {
+		switch token.Method.(type) {
+		case *jwt.SigningMethodRSA, *jwt.SigningMethodRSAPSS, *jwt.SigningMethodECDSA:
+		default:
+			return nil, fmt.Errorf("unexpected signing algorithm %s", token.Method.Alg())
+		}
+
+		keyID, _ := token.Header["kid"].(string)
+		key, err := provider.getKey(ctx, metadata, keyID)
+		keyErr = err
+
+		return key, err
+	})
+
+	if keyErr != nil && !isUnknownKey(keyErr) {
+		return nil, keyErr
+	}
+
+	if err != nil || !token.Valid {
+		return nil, &todoErr.UnauthorizedError{}
+	}
+
+	claims, _ := token.Claims.(jwt.MapClaims)
+	now := time.Now()
+
+	issuer, _ := claims["iss"].(string)
+	subject, _ := claims["sub"].(string)
+	expiresAt, hasExpiry := claims["exp"].(float64)
+	issuedAt, hasIssuedAt := claims["iat"].(float64)
+	authorizedParty, _ := claims["azp"].(string)
+	audiences := audience(claims["aud"])
+
+	switch {
+	case issuer != metadata.Issuer || subject == "":
+		return nil, &todoErr.UnauthorizedError{}
+	case !containsString(audiences, provider.setting.ClientID):
+		return nil, &todoErr.UnauthorizedError{}
+	case len(audiences) > 1 && authorizedParty != provider.setting.ClientID:
+		return nil, &todoErr.UnauthorizedError{}
+	case !hasExpiry || now.Add(-clockSkew).After(time.Unix(int64(expiresAt), 0)):
+		return nil, &todoErr.UnauthorizedError{}
+	case hasIssuedAt && now.Add(clockSkew).Before(time.Unix(int64(issuedAt), 0)):
+		return nil, &todoErr.UnauthorizedError{}
+	}
+
+	idToken := &oidc.IDToken{
+		Subject: subject,
+	}
+
+	idToken.Email, _ = claims["email"].(string)
+	idToken.Name, _ = claims["name"].(string)
+	idToken.Nonce, _ = claims["nonce"].(string)
+
+	// some providers send email_verified as a string
+	switch emailVerified := claims["email_verified"].(type) {
+	case bool:
+		idToken.EmailVerified = emailVerified
+	case string:
+		idToken.EmailVerified = emailVerified == "true"
+	}
+
+	return idToken, nil
+}
+
+// getDiscovery returns the provider metadata, fetching it on first use.
+// Issuer in the metadata should be the configured issuer, so that a provider can not claim to be another.
+func (provider *oidcProvider) getDiscovery(ctx context.Context) (*discovery, error) {
+	provider.mutex.Lock()
+	metadata := provider.discovery
+	provider.mutex.Unlock()
+
+	if metadata != nil {
+		return metadata, nil
+	}
+
+	metadata = &discovery{}
+	discoveryURL := strings.TrimSuffix(provider.setting.Issuer, "/") + "/.well-known/openid-configuration"
+
+	if err := provider.getJSON(ctx, discoveryURL, metadata); err != nil {
+		return nil, err
+	}
+
+	if metadata.Issuer != provider.setting.Issuer {
+		return nil, fmt.Errorf("provider issuer %q does not match %q", metadata.Issuer, provider.setting.Issuer)
+	}
+
+	if metadata.AuthorizationEndpoint == "" || metadata.TokenEndpoint == "" || metadata.JwksURI == "" {
+		return nil, errors.New("provider metadata is missing endpoints")
+	}
+
+	provider.mutex.Lock()
+	provider.discovery = metadata
+	provider.mutex.Unlock()
+
+	return metadata, nil
+}
+
+// unknownKeyError is returned when a token names a key which the provider does not publish
+type unknownKeyError struct {
+	keyID string
+}
+
+func (err *unknownKeyError) Error() string {
+	return fmt.Sprintf("unknown key %q", err.keyID)
+}
+
+// isUnknownKey tells whether the error is about an unknown key, which means that the token is not valid
+func isUnknownKey(err error) bool {
+	_, ok := err.(*unknownKeyError)
+
+	return ok
+}
+
+// getKey returns the provider key with the id, fetching the keys again if the id is unknown and the keys are not fresh.
+// Tokens without key id are accepted only if the provider publishes a single key.
+func (provider *oidcProvider) getKey(ctx context.Context, metadata *discovery, keyID string) (interface{}, error) {
+	provider.mutex.Lock()
+	key, found := lookupKey(provider.keys, keyID)
+	fresh := time.Since(provider.keysFetchedAt) < keysRefreshInterval
+	provider.mutex.Unlock()
+
+	if found {
+		return key, nil
+	}
+
+	if fresh {
+		return nil, &unknownKeyError{keyID: keyID}
+	}
+
+	jwks := &signing.JWKSet{}
+
+	if err := provider.getJSON(ctx, metadata.JwksURI, jwks); err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]interface{})
+
+	for _, jwk := range jwks.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+
+		// keys of unsupported types are skipped, since the provider may publish them besides the signing keys
+		if publicKey, err := jwk.PublicKey(); err == nil {
+			keys[jwk.KeyID] = publicKey
+		}
+	}
+
+	provider.mutex.Lock()
+	provider.keys = keys
+	provider.keysFetchedAt = time.Now()
+	provider.mutex.Unlock()
+
+	if key, found = lookupKey(keys, keyID); !found {
+		return nil, &unknownKeyError{keyID: keyID}
+	}
+
+	return key, nil
+}
+
+// lookupKey finds the key with the id, or the only key if the id is empty
+func lookupKey(keys map[string]interface{}, keyID string) (interface{}, bool) {
+	if keyID == "" && len(keys) == 1 {
+		for _, key := range keys {
+			return key, true
+		}
+	}
+
+	key, found := keys[keyID]
+
+	return key, found
+}
+
+// getJSON fetches a JSON document from the provider
+func (provider *oidcProvider) getJSON(ctx context.Context, documentURL string, document interface{}) error {
+	req, err := http.NewRequest(http.MethodGet, documentURL, nil)
+
+	if err != nil {
+		return err
+	}
+
+	req = req.WithContext(ctx)
+	req.Header.Set("Accept", "application/json")
+
+	res, err := provider.client.Do(req)
+
+	if err != nil {
+		return err
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return responseError(res)
+	}
+
+	return json.NewDecoder(io.LimitReader(res.Body, maxResponseSize)).Decode(document)
+}
+
+// responseError builds an error from an unexpected response, including the start of its body
+func responseError(res *http.Response) error {
+	body, _ := ioutil.ReadAll(io.LimitReader(res.Body, 512))
+
+	return fmt.Errorf("provider responded %d to %s: %s", res.StatusCode, res.Request.URL.Path, strings.TrimSpace(string(body)))
+}
+
+// audience reads the aud claim, which is either a string or an array of strings
+func audience(claim interface{}) []string {
+	switch aud := claim.(type) {
+	case string:
+		return []string{aud}
+	case []interface{}:
+		audiences := make([]string, 0, len(aud))
+
+		for _, value := range aud {
+			if audience, ok := value.(string); ok {
+				audiences = append(audiences, audience)
+			}
+		}
+
+		return audiences
+	default:
+		return nil
+	}
+}
+
+// containsString tells whether the values include the value
+func containsString(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,286 @@
+package provider_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/dheerajgopi/todo-api/auth/signing"
+	todoErr "github.com/dheerajgopi/todo-api/common/error"
+	"github.com/dheerajgopi/todo-api/config"
+	"github.com/dheerajgopi/todo-api/oidc/provider"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	providerKey, _ = rsa.GenerateKey(rand.Reader, 2048)
+	otherKey, _    = rsa.GenerateKey(rand.Reader, 2048)
+)
+
+// authorization is an authorization request which the fake provider granted a code for
+type authorization struct {
+	challenge string
+	nonce     string
+}
+
+// fakeProvider is an in-process OpenID Connect provider, which grants a code to every authorization request
+type fakeProvider struct {
+	server       *httptest.Server
+	issuer       string
+	mutex        sync.Mutex
+	codes        map[string]*authorization
+	claims       jwt.MapClaims
+	signingKey   *rsa.PrivateKey
+	signingKeyID string
+	jwksFetches  int
+}
+
+func newFakeProvider() *fakeProvider {
+	fake := &fakeProvider{
+		codes:        make(map[string]*authorization),
+		claims:       jwt.MapClaims{},
+		signingKey:   providerKey,
+		signingKeyID: "key-1",
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", fake.discovery)
+	mux.HandleFunc("/jwks", fake.jwks)
+	mux.HandleFunc("/token", fake.token)
+
+	fake.server = httptest.NewServer(mux)
+	fake.issuer = fake.server.URL
+
+	return fake
+}
+
+func (fake *fakeProvider) discovery(res http.ResponseWriter, req *http.Request) {
+	json.NewEncoder(res).Encode(map[string]string{
+		"issuer":                 fake.issuer,
+		"authorization_endpoint": fake.server.URL + "/authorize?prompt=login",
+		"token_endpoint":         fake.server.URL + "/token",
+		"jwks_uri":               fake.server.URL + "/jwks",
+	})
+}
+
+func (fake *fakeProvider) jwks(res http.ResponseWriter, req *http.Request) {
+	fake.mutex.Lock()
+	fake.jwksFetches++
+	fake.mutex.Unlock()
+
+	key, _ := signing.NewKey("key-1", providerKey)
+	keySet, _ := signing.New([]*signing.Key{key}, "")
+
+	json.NewEncoder(res).Encode(keySet.JWKS())
+}
+
+// authorize grants a code for the authorization URL, as if the user logged in at the provider
+func (fake *fakeProvider) authorize(authURL string) string {
+	parsedURL, _ := url.Parse(authURL)
+	query := parsedURL.Query()
+	code := "code-" + query.Get("state")
+
+	fake.mutex.Lock()
+	fake.codes[code] = &authorization{
+		challenge: query.Get("code_challenge"),
+		nonce:     query.Get("nonce"),
+	}
+	fake.mutex.Unlock()
+
+	return code
+}
+
+func (fake *fakeProvider) token(res http.ResponseWriter, req *http.Request) {
+	// client credentials are form encoded before basic authentication, as RFC 6749 section 2.3.1 asks
+	clientID, clientSecret, _ := req.BasicAuth()
+	clientID, _ = url.QueryUnescape(clientID)
+	clientSecret, _ = url.QueryUnescape(clientSecret)
+	req.ParseForm()
+
+	fake.mutex.Lock()
+	grant, ok := fake.codes[req.PostForm.Get("code")]
+	delete(fake.codes, req.PostForm.Get("code"))
+	fake.mutex.Unlock()
+
+	verifierHash := sha256.Sum256([]byte(req.PostForm.Get("code_verifier")))
+
+	if !ok || clientID != "todo-api" || clientSecret != "client secret" ||
+		req.PostForm.Get("grant_type") != "authorization_code" ||
+		req.PostForm.Get("redirect_uri") != "https://todo.app/login/oidc/callback" ||
+		base64.RawURLEncoding.EncodeToString(verifierHash[:]) != grant.challenge {
+		res.WriteHeader(http.StatusBadRequest)
+		res.Write([]byte(`{"error":"invalid_grant"}`))
+		return
+	}
+
+	claims := jwt.MapClaims{
+		"iss":            fake.issuer,
+		"sub":            "subject-1",
+		"aud":            "todo-api",
+		"exp":            time.Now().Add(time.Hour).Unix(),
+		"iat":            time.Now().Unix(),
+		"nonce":          grant.nonce,
+		"email":          "user@company.com",
+		"email_verified": true,
+		"name":           "Company User",
+	}
+
+	for name, value := range fake.claims {
+		claims[name] = value
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = fake.signingKeyID
+	idToken, _ := token.SignedString(fake.signingKey)
+
+	json.NewEncoder(res).Encode(map[string]string{
+		"access_token": "access",
+		"token_type":   "Bearer",
+		"id_token":     idToken,
+	})
+}
+
+func newSetting(issuer string) *config.OidcSetting {
+	return &config.OidcSetting{
+		Issuer:       issuer,
+		ClientID:     "todo-api",
+		ClientSecret: "client secret",
+		RedirectURL:  "https://todo.app/login/oidc/callback",
+		Scopes:       []string{"openid", "email"},
+	}
+}
+
+func challenge(verifier string) string {
+	hash := sha256.Sum256([]byte(verifier))
+
+	return base64.RawURLEncoding.EncodeToString(hash[:])
+}
+
+func TestAuthCodeURL(t *testing.T) {
+	assert := assert.New(t)
+	fake := newFakeProvider()
+	defer fake.server.Close()
+
+	oidcProvider := provider.New(newSetting(fake.issuer))
+
+	authURL, err := oidcProvider.AuthCodeURL(context.TODO(), "state", "nonce", "challenge")
+
+	assert.NoError(err)
+
+	parsedURL, _ := url.Parse(authURL)
+	query := parsedURL.Query()
+
+	assert.Equal("/authorize", parsedURL.Path)
+	assert.Equal("login", query.Get("prompt"))
+	assert.Equal("code", query.Get("response_type"))
+	assert.Equal("todo-api", query.Get("client_id"))
+	assert.Equal("https://todo.app/login/oidc/callback", query.Get("redirect_uri"))
+	assert.Equal("openid email", query.Get("scope"))
+	assert.Equal("state", query.Get("state"))
+	assert.Equal("nonce", query.Get("nonce"))
+	assert.Equal("challenge", query.Get("code_challenge"))
+	assert.Equal("S256", query.Get("code_challenge_method"))
+}
+
+func TestAuthCodeURLWithMismatchingIssuer(t *testing.T) {
+	fake := newFakeProvider()
+	defer fake.server.Close()
+
+	fake.issuer = "https://other.provider"
+	oidcProvider := provider.New(newSetting(fake.server.URL))
+
+	_, err := oidcProvider.AuthCodeURL(context.TODO(), "state", "nonce", "challenge")
+
+	assert.EqualError(t, err, `provider issuer "https://other.provider" does not match "`+fake.server.URL+`"`)
+}
+
+func TestExchange(t *testing.T) {
+	assert := assert.New(t)
+	fake := newFakeProvider()
+	defer fake.server.Close()
+
+	oidcProvider := provider.New(newSetting(fake.issuer))
+
+	authURL, _ := oidcProvider.AuthCodeURL(context.TODO(), "state", "nonce", challenge("verifier"))
+	idToken, err := oidcProvider.Exchange(context.TODO(), fake.authorize(authURL), "verifier")
+
+	assert.NoError(err)
+	assert.Equal("subject-1", idToken.Subject)
+	assert.Equal("user@company.com", idToken.Email)
+	assert.True(idToken.EmailVerified)
+	assert.Equal("Company User", idToken.Name)
+	assert.Equal("nonce", idToken.Nonce)
+}
+
+func TestExchangeWithWrongVerifier(t *testing.T) {
+	assert := assert.New(t)
+	fake := newFakeProvider()
+	defer fake.server.Close()
+
+	oidcProvider := provider.New(newSetting(fake.issuer))
+
+	authURL, _ := oidcProvider.AuthCodeURL(context.TODO(), "state", "nonce", challenge("verifier"))
+	idToken, err := oidcProvider.Exchange(context.TODO(), fake.authorize(authURL), "other verifier")
+
+	assert.Nil(idToken)
+	assert.Equal(&todoErr.UnauthorizedError{}, err)
+}
+
+func TestExchangeWithInvalidIDToken(t *testing.T) {
+	invalidClaims := map[string]jwt.MapClaims{
+		"other issuer":     {"iss": "https://other.provider"},
+		"other audience":   {"aud": "other-client"},
+		"other party":      {"aud": []string{"todo-api", "other-client"}, "azp": "other-client"},
+		"expired":          {"exp": time.Now().Add(-2 * time.Minute).Unix()},
+		"issued in future": {"iat": time.Now().Add(2 * time.Minute).Unix()},
+		"no subject":       {"sub": ""},
+	}
+
+	for name, claims := range invalidClaims {
+		fake := newFakeProvider()
+		fake.claims = claims
+		oidcProvider := provider.New(newSetting(fake.issuer))
+
+		authURL, _ := oidcProvider.AuthCodeURL(context.TODO(), "state", "nonce", challenge("verifier"))
+		idToken, err := oidcProvider.Exchange(context.TODO(), fake.authorize(authURL), "verifier")
+
+		assert.Nil(t, idToken, name)
+		assert.Equal(t, &todoErr.UnauthorizedError{}, err, name)
+
+		fake.server.Close()
+	}
+}
+
+func TestExchangeWithUnknownSigningKey(t *testing.T) {
+	assert := assert.New(t)
+	fake := newFakeProvider()
+	defer fake.server.Close()
+
+	oidcProvider := provider.New(newSetting(fake.issuer))
+
+	// a key which the provider does not publish, under a published key id and under an unknown key id
+	fake.signingKey = otherKey
+
+	for _, keyID := range []string{"key-1", "key-2", "key-2"} {
+		fake.signingKeyID = keyID
+
+		authURL, _ := oidcProvider.AuthCodeURL(context.TODO(), "state", "nonce", challenge("verifier"))
+		idToken, err := oidcProvider.Exchange(context.TODO(), fake.authorize(authURL), "verifier")
+
+		assert.Nil(idToken)
+		assert.Equal(&todoErr.UnauthorizedError{}, err)
+	}
+
+	// keys are not fetched again for unknown key ids within the refresh interval
+	assert.Equal(1, fake.jwksFetches)
+}
@@ -0,0 +1,16 @@
+package oidc
+
+import (
+	"context"
+
+	"github.com/dheerajgopi/todo-api/models"
+)
+
+// Repository represents OpenID Connect login's repository contract
+type Repository interface {
+	SaveState(ctx context.Context, state *models.OidcState) error
+	TakeState(ctx context.Context, stateHash string) (*models.OidcState, error)
+	GetUserByIdentity(ctx context.Context, issuer string, subject string) (*models.User, error)
+	Link(ctx context.Context, identity *models.UserIdentity) error
+	Provision(ctx context.Context, identity *models.UserIdentity) error
+}
@@ -0,0 +1,175 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/dheerajgopi/todo-api/models"
+	"github.com/dheerajgopi/todo-api/oidc"
+)
+
+type mySQLOidcRepo struct {
+	DB *sql.DB
+}
+
+// New will return new object which implements oidc.Repository
+func New(db *sql.DB) oidc.Repository {
+	return &mySQLOidcRepo{
+		DB: db,
+	}
+}
+
+// SaveState will store a started login. States which expired before it was started are removed along the way.
+func (repo *mySQLOidcRepo) SaveState(ctx context.Context, state *models.OidcState) error {
+	tx, err := repo.DB.BeginTx(ctx, nil)
+
+	if err != nil {
+		return err
+	}
+
+	if _, err = tx.Exec(`DELETE FROM oidc_state WHERE expires_at<?`, state.CreatedAt); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	query := `INSERT INTO oidc_state (state_hash, verifier, nonce, expires_at, created_at) VALUES (?, ?, ?, ?, ?)`
+
+	_, err = tx.Exec(query, state.StateHash, state.Verifier, state.Nonce, state.ExpiresAt, state.CreatedAt)
+
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// TakeState will return a started login and remove it, so that each state is completed once.
+// Nil is returned if there is no login with the state hash.
+func (repo *mySQLOidcRepo) TakeState(ctx context.Context, stateHash string) (*models.OidcState, error) {
+	tx, err := repo.DB.BeginTx(ctx, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	query := `SELECT state_hash, verifier, nonce, expires_at, created_at FROM oidc_state WHERE state_hash=? FOR UPDATE`
+	state := &models.OidcState{}
+
+	err = tx.QueryRow(query, stateHash).Scan(
+		&state.StateHash,
+		&state.Verifier,
+		&state.Nonce,
+		&state.ExpiresAt,
+		&state.CreatedAt,
+	)
+
+	switch err {
+	case nil:
+	case sql.ErrNoRows:
+		tx.Rollback()
+		return nil, nil
+	default:
+		tx.Rollback()
+		return nil, err
+	}
+
+	if _, err = tx.Exec(`DELETE FROM oidc_state WHERE state_hash=?`, stateHash); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return state, nil
+}
+
+// GetUserByIdentity will return the user linked to the subject of the issuer.
+// Deleted and deactivated users are returned as well, so that their subject does not get linked to another user.
+func (repo *mySQLOidcRepo) GetUserByIdentity(ctx context.Context, issuer string, subject string) (*models.User, error) {
+	query := `SELECT u.id, u.name, u.email, u.is_active, u.totp_enabled, u.created_at, u.updated_at, u.deleted_at, u.deactivated_at
+		FROM user_identity i JOIN user u ON u.id=i.user_id WHERE i.issuer=? AND i.subject=?`
+
+	stmt, err := repo.DB.PrepareContext(ctx, query)
+
+	if err != nil {
+		return nil, err
+	}
+
+	user := &models.User{}
+
+	err = stmt.QueryRowContext(ctx, issuer, subject).Scan(
+		&user.ID,
+		&user.Name,
+		&user.Email,
+		&user.IsActive,
+		&user.TotpEnabled,
+		&user.CreatedAt,
+		&user.UpdatedAt,
+		&user.DeletedAt,
+		&user.DeactivatedAt,
+	)
+
+	switch err {
+	case nil:
+	case sql.ErrNoRows:
+		return nil, nil
+	default:
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// Link will link an existing user to the subject of the issuer
+func (repo *mySQLOidcRepo) Link(ctx context.Context, identity *models.UserIdentity) error {
+	query := `INSERT INTO user_identity (user_id, issuer, subject, created_at) VALUES (?, ?, ?, ?)`
+
+	_, err := repo.DB.ExecContext(ctx, query, identity.User.ID, identity.Issuer, identity.Subject, identity.CreatedAt)
+
+	return err
+}
+
+// Provision will create the user of the identity, and link the user to the subject of the issuer
+func (repo *mySQLOidcRepo) Provision(ctx context.Context, identity *models.UserIdentity) error {
+	user := identity.User
+
+	tx, err := repo.DB.BeginTx(ctx, nil)
+
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO user (name, email, passwd, is_active, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?)`
+
+	res, err := tx.Exec(query, user.Name, user.Email, user.Passwd, user.IsActive, user.CreatedAt, user.UpdatedAt)
+
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	lastID, err := res.LastInsertId()
+
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	query = `INSERT INTO user_identity (user_id, issuer, subject, created_at) VALUES (?, ?, ?, ?)`
+
+	if _, err = tx.Exec(query, lastID, identity.Issuer, identity.Subject, identity.CreatedAt); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+
+	user.ID = lastID
+
+	return nil
+}
@@ -0,0 +1,196 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/dheerajgopi/todo-api/models"
+	"github.com/dheerajgopi/todo-api/oidc/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSaveState(t *testing.T) {
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	now := time.Now()
+	state := &models.OidcState{
+		StateHash: "hash",
+		Verifier:  "verifier",
+		Nonce:     "nonce",
+		ExpiresAt: now.Add(10 * time.Minute),
+		CreatedAt: now,
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM oidc_state WHERE expires_at<\\?").
+		WithArgs(now).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec("INSERT INTO oidc_state \\(state_hash, verifier, nonce, expires_at, created_at\\) VALUES \\(\\?, \\?, \\?, \\?, \\?\\)").
+		WithArgs("hash", "verifier", "nonce", state.ExpiresAt, now).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	repo := repository.New(db)
+
+	assert.NoError(t, repo.SaveState(context.TODO(), state))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTakeState(t *testing.T) {
+	assert := assert.New(t)
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	now := time.Now()
+	rows := sqlmock.
+		NewRows([]string{"state_hash", "verifier", "nonce", "expires_at", "created_at"}).
+		AddRow("hash", "verifier", "nonce", now.Add(10*time.Minute), now)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT (.+) FROM oidc_state WHERE state_hash=\\? FOR UPDATE").
+		WithArgs("hash").
+		WillReturnRows(rows)
+	mock.ExpectExec("DELETE FROM oidc_state WHERE state_hash=\\?").
+		WithArgs("hash").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	repo := repository.New(db)
+
+	state, err := repo.TakeState(context.TODO(), "hash")
+	assert.NoError(err)
+	assert.Equal("verifier", state.Verifier)
+	assert.Equal("nonce", state.Nonce)
+	assert.NoError(mock.ExpectationsWereMet())
+}
+
+func TestTakeStateWithNoRows(t *testing.T) {
+	assert := assert.New(t)
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT (.+) FROM oidc_state WHERE state_hash=\\? FOR UPDATE").
+		WithArgs("hash").
+		WillReturnError(sql.ErrNoRows)
+	mock.ExpectRollback()
+
+	repo := repository.New(db)
+
+	state, err := repo.TakeState(context.TODO(), "hash")
+	assert.NoError(err)
+	assert.Nil(state)
+	assert.NoError(mock.ExpectationsWereMet())
+}
+
+func TestGetUserByIdentity(t *testing.T) {
+	assert := assert.New(t)
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	now := time.Now()
+	rows := sqlmock.
+		NewRows([]string{"id", "name", "email", "is_active", "totp_enabled", "created_at", "updated_at", "deleted_at", "deactivated_at"}).
+		AddRow(1, "user", "user@company.com", true, false, now, now, nil, nil)
+
+	prep := mock.ExpectPrepare("SELECT (.+) FROM user_identity i JOIN user u ON u.id=i.user_id WHERE i.issuer=\\? AND i.subject=\\?")
+	prep.ExpectQuery().WithArgs("https://provider", "subject-1").WillReturnRows(rows)
+
+	repo := repository.New(db)
+
+	user, err := repo.GetUserByIdentity(context.TODO(), "https://provider", "subject-1")
+	assert.NoError(err)
+	assert.Equal(int64(1), user.ID)
+	assert.Equal("user@company.com", user.Email)
+	assert.Nil(user.DeletedAt)
+}
+
+func TestLink(t *testing.T) {
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	now := time.Now()
+	identity := &models.UserIdentity{
+		User:      &models.User{ID: 1},
+		Issuer:    "https://provider",
+		Subject:   "subject-1",
+		CreatedAt: now,
+	}
+
+	mock.ExpectExec("INSERT INTO user_identity \\(user_id, issuer, subject, created_at\\) VALUES \\(\\?, \\?, \\?, \\?\\)").
+		WithArgs(int64(1), "https://provider", "subject-1", now).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	repo := repository.New(db)
+
+	assert.NoError(t, repo.Link(context.TODO(), identity))
+}
+
+func TestProvision(t *testing.T) {
+	assert := assert.New(t)
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	now := time.Now()
+	identity := &models.UserIdentity{
+		User: &models.User{
+			Name:      "user",
+			Email:     "user@company.com",
+			IsActive:  true,
+			CreatedAt: now,
+			UpdatedAt: now,
+		},
+		Issuer:    "https://provider",
+		Subject:   "subject-1",
+		CreatedAt: now,
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO user \\(name, email, passwd, is_active, created_at, updated_at\\) VALUES \\(\\?, \\?, \\?, \\?, \\?, \\?\\)").
+		WithArgs("user", "user@company.com", "", true, now, now).
+		WillReturnResult(sqlmock.NewResult(3, 1))
+	mock.ExpectExec("INSERT INTO user_identity \\(user_id, issuer, subject, created_at\\) VALUES \\(\\?, \\?, \\?, \\?\\)").
+		WithArgs(int64(3), "https://provider", "subject-1", now).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	repo := repository.New(db)
+
+	assert.NoError(repo.Provision(context.TODO(), identity))
+	assert.Equal(int64(3), identity.User.ID)
+	assert.NoError(mock.ExpectationsWereMet())
+}
@@ -0,0 +1,23 @@
+package oidc
+
+import (
+	"context"
+	"time"
+
+	"github.com/dheerajgopi/todo-api/models"
+)
+
+// Authorization is a started login, which continues at the provider's authorization URL.
+// Client should keep the state, and check that the provider redirects back with the same state.
+type Authorization struct {
+	URL       string
+	State     string
+	ExpiresAt time.Time
+}
+
+// Service represents OpenID Connect login's service contract.
+// Users are found by the provider subject they are linked to, or else by verified email.
+type Service interface {
+	Begin(ctx context.Context) (*Authorization, error)
+	Complete(ctx context.Context, state string, code string) (*models.User, error)
+}
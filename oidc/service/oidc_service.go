@@ -0,0 +1,212 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"time"
+
+	todoErr "github.com/dheerajgopi/todo-api/common/error"
+	"github.com/dheerajgopi/todo-api/config"
+	"github.com/dheerajgopi/todo-api/models"
+	"github.com/dheerajgopi/todo-api/oidc"
+	"github.com/dheerajgopi/todo-api/user"
+)
+
+// randomSize is the number of random bytes in a state, PKCE verifier and nonce
+const randomSize = 32
+
+type oidcService struct {
+	provider oidc.Provider
+	oidcRepo oidc.Repository
+	userRepo user.Repository
+	setting  *config.OidcSetting
+}
+
+// New returns a new object implementing oidc.Service interface.
+// Users of the provider are looked up in the user repository, and created if the settings allow auto provisioning.
+func New(provider oidc.Provider, oidcRepo oidc.Repository, userRepo user.Repository, setting *config.OidcSetting) oidc.Service {
+	return &oidcService{
+		provider: provider,
+		oidcRepo: oidcRepo,
+		userRepo: userRepo,
+		setting:  setting,
+	}
+}
+
+// Begin starts a login, and returns the authorization URL of the provider along with the state of the login.
+// PKCE verifier and nonce are kept on the server, so that they never appear in an URL.
+func (service *oidcService) Begin(ctx context.Context) (*oidc.Authorization, error) {
+	values, err := randomStrings(3)
+
+	if err != nil {
+		return nil, err
+	}
+
+	state, verifier, nonce := values[0], values[1], values[2]
+	challenge := sha256.Sum256([]byte(verifier))
+
+	authURL, err := service.provider.AuthCodeURL(ctx, state, nonce, base64.RawURLEncoding.EncodeToString(challenge[:]))
+
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	oidcState := &models.OidcState{
+		StateHash: hashState(state),
+		Verifier:  verifier,
+		Nonce:     nonce,
+		ExpiresAt: now.Add(time.Duration(service.setting.StateExpiryInSeconds) * time.Second),
+		CreatedAt: now,
+	}
+
+	if err = service.oidcRepo.SaveState(ctx, oidcState); err != nil {
+		return nil, err
+	}
+
+	return &oidc.Authorization{
+		URL:       authURL,
+		State:     state,
+		ExpiresAt: oidcState.ExpiresAt,
+	}, nil
+}
+
+// Complete finishes a login with the code which the provider redirected back with, and returns the user who logged in.
+// User linked to the provider subject is returned if there is one. Else the user with the verified email of the ID token
+// gets linked, or is created if auto provisioning is allowed. Accounts whose email is not verified are never linked,
+// so that an account registered with someone else's email can not be taken over.
+func (service *oidcService) Complete(ctx context.Context, state string, code string) (*models.User, error) {
+	oidcState, err := service.oidcRepo.TakeState(ctx, hashState(state))
+
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+
+	if oidcState == nil || !oidcState.ExpiresAt.After(now) {
+		return nil, &todoErr.InvalidValueError{
+			Resource: "oidc",
+			Field:    "state",
+			Reason:   "unknown or expired state",
+		}
+	}
+
+	idToken, err := service.provider.Exchange(ctx, code, oidcState.Verifier)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if idToken.Nonce != oidcState.Nonce {
+		return nil, &todoErr.UnauthorizedError{}
+	}
+
+	issuer := service.provider.Issuer()
+	linkedUser, err := service.oidcRepo.GetUserByIdentity(ctx, issuer, idToken.Subject)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if linkedUser != nil {
+		if linkedUser.DeletedAt != nil || linkedUser.DeactivatedAt != nil {
+			return nil, &todoErr.ResourceNotFoundError{
+				Resource: "user",
+			}
+		}
+
+		return linkedUser, nil
+	}
+
+	if idToken.Email == "" || !idToken.EmailVerified {
+		return nil, &todoErr.PermissionDeniedError{
+			Resource: "user",
+			Action:   "link",
+		}
+	}
+
+	identity := &models.UserIdentity{
+		Issuer:    issuer,
+		Subject:   idToken.Subject,
+		CreatedAt: now,
+	}
+
+	existingUser, err := service.userRepo.GetByEmail(ctx, idToken.Email)
+
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case existingUser != nil && (existingUser.DeletedAt != nil || existingUser.DeactivatedAt != nil):
+		return nil, &todoErr.ResourceNotFoundError{
+			Resource: "user",
+		}
+	case existingUser != nil && !existingUser.IsActive:
+		return nil, &todoErr.PermissionDeniedError{
+			Resource: "user",
+			Action:   "link",
+		}
+	case existingUser != nil:
+		identity.User = existingUser
+
+		if err = service.oidcRepo.Link(ctx, identity); err != nil {
+			return nil, err
+		}
+
+		return existingUser, nil
+	case !service.setting.AutoProvision:
+		return nil, &todoErr.ResourceNotFoundError{
+			Resource: "user",
+		}
+	}
+
+	name := idToken.Name
+
+	if name == "" {
+		name = idToken.Email
+	}
+
+	// provisioned users have no password, and can set one with a password reset
+	identity.User = &models.User{
+		Name:      name,
+		Email:     idToken.Email,
+		IsActive:  true,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if err = service.oidcRepo.Provision(ctx, identity); err != nil {
+		return nil, err
+	}
+
+	return identity.User, nil
+}
+
+// randomStrings returns the given number of random, base64url encoded strings
+func randomStrings(count int) ([]string, error) {
+	values := make([]string, 0, count)
+
+	for i := 0; i < count; i++ {
+		value := make([]byte, randomSize)
+
+		if _, err := rand.Read(value); err != nil {
+			return nil, err
+		}
+
+		values = append(values, base64.RawURLEncoding.EncodeToString(value))
+	}
+
+	return values, nil
+}
+
+// hashState returns the hex encoded SHA-256 hash of a state
+func hashState(state string) string {
+	hash := sha256.Sum256([]byte(state))
+
+	return hex.EncodeToString(hash[:])
+}
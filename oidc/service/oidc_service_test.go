@@ -0,0 +1,256 @@
+package service_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	todoErr "github.com/dheerajgopi/todo-api/common/error"
+	"github.com/dheerajgopi/todo-api/config"
+	"github.com/dheerajgopi/todo-api/models"
+	"github.com/dheerajgopi/todo-api/oidc"
+	oidcMock "github.com/dheerajgopi/todo-api/oidc/mock"
+	"github.com/dheerajgopi/todo-api/oidc/service"
+	userMock "github.com/dheerajgopi/todo-api/user/mock"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+const issuer = "https://provider"
+
+func hash(value string) string {
+	sum := sha256.Sum256([]byte(value))
+
+	return hex.EncodeToString(sum[:])
+}
+
+type mocks struct {
+	provider *oidcMock.Provider
+	oidcRepo *oidcMock.Repository
+	userRepo *userMock.Repository
+}
+
+func setupService(mockCtrl *gomock.Controller, autoProvision bool) (oidc.Service, *mocks) {
+	m := &mocks{
+		provider: oidcMock.NewProvider(mockCtrl),
+		oidcRepo: oidcMock.NewRepository(mockCtrl),
+		userRepo: userMock.NewRepository(mockCtrl),
+	}
+
+	m.provider.EXPECT().Issuer().Return(issuer).AnyTimes()
+
+	setting := &config.OidcSetting{
+		Issuer:               issuer,
+		AutoProvision:        autoProvision,
+		StateExpiryInSeconds: 600,
+	}
+
+	return service.New(m.provider, m.oidcRepo, m.userRepo, setting), m
+}
+
+// expectState expects the state to be taken, and the code to be exchanged for an ID token with the claims
+func expectState(ctx context.Context, m *mocks, idToken *oidc.IDToken) {
+	m.oidcRepo.EXPECT().TakeState(ctx, hash("state")).Return(&models.OidcState{
+		StateHash: hash("state"),
+		Verifier:  "verifier",
+		Nonce:     "nonce",
+		ExpiresAt: time.Now().Add(time.Minute),
+	}, nil)
+
+	m.provider.EXPECT().Exchange(ctx, "code", "verifier").Return(idToken, nil)
+}
+
+func TestBegin(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	oidcService, m := setupService(mockCtrl, false)
+
+	var state, nonce, challenge string
+	var savedState *models.OidcState
+
+	m.provider.EXPECT().AuthCodeURL(ctx, gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, s string, n string, c string) (string, error) {
+			state, nonce, challenge = s, n, c
+			return "https://provider/authorize?state=" + s, nil
+		})
+
+	m.oidcRepo.EXPECT().SaveState(ctx, gomock.Any()).
+		DoAndReturn(func(ctx context.Context, oidcState *models.OidcState) error {
+			savedState = oidcState
+			return nil
+		})
+
+	authorization, err := oidcService.Begin(ctx)
+
+	assert.NoError(err)
+	assert.Equal(state, authorization.State)
+	assert.Equal("https://provider/authorize?state="+state, authorization.URL)
+	assert.Equal(hash(state), savedState.StateHash)
+	assert.Equal(nonce, savedState.Nonce)
+	assert.NotEqual(state, savedState.Verifier)
+
+	verifierHash := sha256.Sum256([]byte(savedState.Verifier))
+	assert.Equal(base64.RawURLEncoding.EncodeToString(verifierHash[:]), challenge)
+	assert.Equal(savedState.ExpiresAt, authorization.ExpiresAt)
+	assert.Equal(10*time.Minute, savedState.ExpiresAt.Sub(savedState.CreatedAt))
+}
+
+func TestCompleteWithInvalidState(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	oidcService, m := setupService(mockCtrl, false)
+
+	m.oidcRepo.EXPECT().TakeState(ctx, hash("unknown")).Return(nil, nil)
+	m.oidcRepo.EXPECT().TakeState(ctx, hash("expired")).Return(&models.OidcState{
+		ExpiresAt: time.Now().Add(-time.Second),
+	}, nil)
+
+	for _, state := range []string{"unknown", "expired"} {
+		loggedInUser, err := oidcService.Complete(ctx, state, "code")
+
+		assert.Nil(loggedInUser)
+		assert.IsType(&todoErr.InvalidValueError{}, err)
+	}
+}
+
+func TestCompleteWithWrongNonce(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	oidcService, m := setupService(mockCtrl, false)
+
+	expectState(ctx, m, &oidc.IDToken{Subject: "subject-1", Nonce: "other nonce"})
+
+	loggedInUser, err := oidcService.Complete(ctx, "state", "code")
+
+	assert.Nil(loggedInUser)
+	assert.Equal(&todoErr.UnauthorizedError{}, err)
+}
+
+func TestCompleteWithLinkedUser(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	oidcService, m := setupService(mockCtrl, false)
+	linkedUser := &models.User{ID: 1, IsActive: true}
+
+	expectState(ctx, m, &oidc.IDToken{Subject: "subject-1", Nonce: "nonce"})
+	m.oidcRepo.EXPECT().GetUserByIdentity(ctx, issuer, "subject-1").Return(linkedUser, nil)
+
+	loggedInUser, err := oidcService.Complete(ctx, "state", "code")
+
+	assert.NoError(err)
+	assert.Equal(linkedUser, loggedInUser)
+}
+
+func TestCompleteWithUnverifiedEmail(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	oidcService, m := setupService(mockCtrl, true)
+	denied := &todoErr.PermissionDeniedError{Resource: "user", Action: "link"}
+
+	// email which the provider did not verify
+	expectState(ctx, m, &oidc.IDToken{Subject: "subject-1", Nonce: "nonce", Email: "user@company.com"})
+	m.oidcRepo.EXPECT().GetUserByIdentity(ctx, issuer, "subject-1").Return(nil, nil)
+
+	loggedInUser, err := oidcService.Complete(ctx, "state", "code")
+
+	assert.Nil(loggedInUser)
+	assert.Equal(denied, err)
+
+	// local account whose email is not verified
+	expectState(ctx, m, &oidc.IDToken{Subject: "subject-1", Nonce: "nonce", Email: "user@company.com", EmailVerified: true})
+	m.oidcRepo.EXPECT().GetUserByIdentity(ctx, issuer, "subject-1").Return(nil, nil)
+	m.userRepo.EXPECT().GetByEmail(ctx, "user@company.com").Return(&models.User{ID: 1, IsActive: false}, nil)
+
+	loggedInUser, err = oidcService.Complete(ctx, "state", "code")
+
+	assert.Nil(loggedInUser)
+	assert.Equal(denied, err)
+}
+
+func TestCompleteWithExistingUser(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	oidcService, m := setupService(mockCtrl, false)
+	existingUser := &models.User{ID: 1, Email: "user@company.com", IsActive: true}
+
+	expectState(ctx, m, &oidc.IDToken{Subject: "subject-1", Nonce: "nonce", Email: "user@company.com", EmailVerified: true})
+	m.oidcRepo.EXPECT().GetUserByIdentity(ctx, issuer, "subject-1").Return(nil, nil)
+	m.userRepo.EXPECT().GetByEmail(ctx, "user@company.com").Return(existingUser, nil)
+	m.oidcRepo.EXPECT().Link(ctx, gomock.Any()).
+		DoAndReturn(func(ctx context.Context, identity *models.UserIdentity) error {
+			assert.Equal(existingUser, identity.User)
+			assert.Equal(issuer, identity.Issuer)
+			assert.Equal("subject-1", identity.Subject)
+			return nil
+		})
+
+	loggedInUser, err := oidcService.Complete(ctx, "state", "code")
+
+	assert.NoError(err)
+	assert.Equal(existingUser, loggedInUser)
+}
+
+func TestCompleteWithUnknownEmail(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	oidcService, m := setupService(mockCtrl, false)
+
+	expectState(ctx, m, &oidc.IDToken{Subject: "subject-1", Nonce: "nonce", Email: "user@company.com", EmailVerified: true})
+	m.oidcRepo.EXPECT().GetUserByIdentity(ctx, issuer, "subject-1").Return(nil, nil)
+	m.userRepo.EXPECT().GetByEmail(ctx, "user@company.com").Return(nil, nil)
+
+	loggedInUser, err := oidcService.Complete(ctx, "state", "code")
+
+	assert.Nil(loggedInUser)
+	assert.Equal(&todoErr.ResourceNotFoundError{Resource: "user"}, err)
+}
+
+func TestCompleteWithAutoProvision(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	oidcService, m := setupService(mockCtrl, true)
+
+	expectState(ctx, m, &oidc.IDToken{Subject: "subject-1", Nonce: "nonce", Email: "user@company.com", EmailVerified: true})
+	m.oidcRepo.EXPECT().GetUserByIdentity(ctx, issuer, "subject-1").Return(nil, nil)
+	m.userRepo.EXPECT().GetByEmail(ctx, "user@company.com").Return(nil, nil)
+	m.oidcRepo.EXPECT().Provision(ctx, gomock.Any()).
+		DoAndReturn(func(ctx context.Context, identity *models.UserIdentity) error {
+			identity.User.ID = 3
+			return nil
+		})
+
+	loggedInUser, err := oidcService.Complete(ctx, "state", "code")
+
+	assert.NoError(err)
+	assert.Equal(int64(3), loggedInUser.ID)
+	assert.Equal("user@company.com", loggedInUser.Name)
+	assert.Equal("", loggedInUser.Passwd)
+	assert.True(loggedInUser.IsActive)
+}
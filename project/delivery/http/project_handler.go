@@ -0,0 +1,277 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/dheerajgopi/todo-api/common"
+	todoErr "github.com/dheerajgopi/todo-api/common/error"
+	"github.com/dheerajgopi/todo-api/common/middlewares"
+	"github.com/dheerajgopi/todo-api/history"
+	"github.com/dheerajgopi/todo-api/models"
+	"github.com/dheerajgopi/todo-api/project"
+	"github.com/gorilla/mux"
+)
+
+// ProjectHandler represents HTTP handler for projects
+type ProjectHandler struct {
+	ProjectService project.Service
+	App            *common.App
+}
+
+// New creates new HTTP handler for project
+func New(router *mux.Router, service project.Service, app *common.App) {
+	handler := &ProjectHandler{
+		ProjectService: service,
+		App:            app,
+	}
+
+	jwtMiddleware := middlewares.JwtValidator(app)
+
+	router.HandleFunc("/projects", app.CreateHandler(jwtMiddleware(handler.Create))).Methods("POST")
+	router.HandleFunc("/projects", app.CreateHandler(jwtMiddleware(handler.List))).Methods("GET")
+	router.HandleFunc("/projects/{id:[0-9]+}", app.CreateHandler(jwtMiddleware(handler.Get))).Methods("GET")
+	router.HandleFunc("/projects/{id:[0-9]+}", app.CreateHandler(jwtMiddleware(handler.Patch))).Methods("PATCH")
+	router.HandleFunc("/projects/{id:[0-9]+}", app.CreateHandler(jwtMiddleware(handler.Delete))).Methods("DELETE")
+}
+
+// Create will store new project
+func (handler *ProjectHandler) Create(res http.ResponseWriter, req *http.Request, reqCtx *common.RequestContext) (int, interface{}, *todoErr.APIError) {
+	defer req.Body.Close()
+
+	decoder := json.NewDecoder(req.Body)
+	var createProjectReqBody CreateProjectRequest
+	err := decoder.Decode(&createProjectReqBody)
+
+	if err != nil {
+		apiError := todoErr.NewAPIError("", &todoErr.APIErrorBody{
+			Message: "Invalid request body",
+		})
+
+		return http.StatusBadRequest, nil, apiError
+	}
+
+	validationErrors := createProjectReqBody.ValidateAndBuild()
+
+	if len(validationErrors) > 0 {
+		apiError := todoErr.NewAPIError("", validationErrors...)
+
+		return http.StatusBadRequest, nil, apiError
+	}
+
+	now := time.Now()
+
+	newProject := &models.Project{
+		Name: createProjectReqBody.Name,
+		CreatedBy: &models.User{
+			ID: reqCtx.UserID,
+		},
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if err = handler.ProjectService.Create(context.TODO(), newProject); err != nil {
+		return projectServiceError(err)
+	}
+
+	responseData := &CreateProjectResponse{
+		Project: newProjectData(newProject),
+	}
+
+	return http.StatusCreated, responseData, nil
+}
+
+// List will return the projects of the user. Archived projects are included only if archived=true.
+func (handler *ProjectHandler) List(res http.ResponseWriter, req *http.Request, reqCtx *common.RequestContext) (int, interface{}, *todoErr.APIError) {
+	includeArchived := false
+
+	if archived := req.URL.Query().Get("archived"); archived != "" {
+		value, err := strconv.ParseBool(archived)
+
+		if err != nil {
+			apiError := todoErr.NewAPIError(err.Error(), &todoErr.APIErrorBody{
+				Message: "Invalid value",
+				Target:  "archived",
+			})
+
+			return http.StatusBadRequest, nil, apiError
+		}
+
+		includeArchived = value
+	}
+
+	projects, err := handler.ProjectService.List(context.TODO(), reqCtx.UserID, includeArchived)
+
+	if err != nil {
+		return projectServiceError(err)
+	}
+
+	projectList := make([]*ProjectData, 0)
+
+	for _, project := range projects {
+		projectList = append(projectList, newProjectData(project))
+	}
+
+	responseData := &ListProjectResponse{
+		Projects: projectList,
+	}
+
+	return http.StatusOK, responseData, nil
+}
+
+// Get will return a single project
+func (handler *ProjectHandler) Get(res http.ResponseWriter, req *http.Request, reqCtx *common.RequestContext) (int, interface{}, *todoErr.APIError) {
+	projectID, apiError := parseProjectID(req)
+
+	if apiError != nil {
+		return http.StatusBadRequest, nil, apiError
+	}
+
+	existingProject, err := handler.ProjectService.GetByID(context.TODO(), projectID, reqCtx.UserID)
+
+	if err != nil {
+		return projectServiceError(err)
+	}
+
+	responseData := &GetProjectResponse{
+		Project: newProjectData(existingProject),
+	}
+
+	return http.StatusOK, responseData, nil
+}
+
+// Patch will rename, archive or unarchive a project
+func (handler *ProjectHandler) Patch(res http.ResponseWriter, req *http.Request, reqCtx *common.RequestContext) (int, interface{}, *todoErr.APIError) {
+	defer req.Body.Close()
+
+	projectID, apiError := parseProjectID(req)
+
+	if apiError != nil {
+		return http.StatusBadRequest, nil, apiError
+	}
+
+	decoder := json.NewDecoder(req.Body)
+	var patchProjectReqBody PatchProjectRequest
+	err := decoder.Decode(&patchProjectReqBody)
+
+	if err != nil {
+		apiError := todoErr.NewAPIError("", &todoErr.APIErrorBody{
+			Message: "Invalid request body",
+		})
+
+		return http.StatusBadRequest, nil, apiError
+	}
+
+	validationErrors := patchProjectReqBody.ValidateAndBuild()
+
+	if len(validationErrors) > 0 {
+		apiError := todoErr.NewAPIError("", validationErrors...)
+
+		return http.StatusBadRequest, nil, apiError
+	}
+
+	existingProject, err := handler.ProjectService.GetByID(context.TODO(), projectID, reqCtx.UserID)
+
+	if err != nil {
+		return projectServiceError(err)
+	}
+
+	if patchProjectReqBody.Name != nil {
+		existingProject.Name = *patchProjectReqBody.Name
+	}
+
+	if patchProjectReqBody.IsArchived != nil {
+		existingProject.IsArchived = *patchProjectReqBody.IsArchived
+	}
+
+	existingProject.UpdatedAt = time.Now()
+
+	if err = handler.ProjectService.Update(context.TODO(), existingProject, reqCtx.UserID); err != nil {
+		return projectServiceError(err)
+	}
+
+	responseData := &UpdateProjectResponse{
+		Project: newProjectData(existingProject),
+	}
+
+	return http.StatusOK, responseData, nil
+}
+
+// Delete will remove a project. Its tasks are moved to the inbox, or deleted if tasks=delete.
+func (handler *ProjectHandler) Delete(res http.ResponseWriter, req *http.Request, reqCtx *common.RequestContext) (int, interface{}, *todoErr.APIError) {
+	projectID, apiError := parseProjectID(req)
+
+	if apiError != nil {
+		return http.StatusBadRequest, nil, apiError
+	}
+
+	deleteProjectReq := &DeleteProjectRequest{
+		Tasks: req.URL.Query().Get("tasks"),
+	}
+
+	deleteTasks, validationErrors := deleteProjectReq.ValidateAndBuild()
+
+	if len(validationErrors) > 0 {
+		apiError := todoErr.NewAPIError("", validationErrors...)
+
+		return http.StatusBadRequest, nil, apiError
+	}
+
+	ctx := history.WithActor(context.TODO(), &history.Actor{
+		UserID:    reqCtx.UserID,
+		RequestID: reqCtx.RequestID,
+	})
+
+	if err := handler.ProjectService.Delete(ctx, projectID, reqCtx.UserID, deleteTasks); err != nil {
+		return projectServiceError(err)
+	}
+
+	return http.StatusOK, nil, nil
+}
+
+// parseProjectID reads the project id from the request path
+func parseProjectID(req *http.Request) (int64, *todoErr.APIError) {
+	projectID, err := strconv.ParseInt(mux.Vars(req)["id"], 10, 64)
+
+	if err != nil {
+		return 0, todoErr.NewAPIError(err.Error(), &todoErr.APIErrorBody{
+			Message: "Invalid value",
+			Target:  "id",
+		})
+	}
+
+	return projectID, nil
+}
+
+// projectServiceError maps errors returned by the project service to the API response
+func projectServiceError(err error) (int, interface{}, *todoErr.APIError) {
+	switch err.(type) {
+	case *todoErr.ResourceNotFoundError:
+		resourceNotFoundErr, _ := err.(*todoErr.ResourceNotFoundError)
+
+		apiError := todoErr.NewAPIError(resourceNotFoundErr.Error(), &todoErr.APIErrorBody{
+			Message: "Not found",
+			Target:  resourceNotFoundErr.Resource,
+		})
+
+		return http.StatusNotFound, nil, apiError
+	case *todoErr.PermissionDeniedError:
+		permissionDeniedErr, _ := err.(*todoErr.PermissionDeniedError)
+
+		apiError := todoErr.NewAPIError(permissionDeniedErr.Error(), &todoErr.APIErrorBody{
+			Message: "Permission denied",
+			Target:  permissionDeniedErr.Resource,
+		})
+
+		return http.StatusForbidden, nil, apiError
+	default:
+		apiError := todoErr.NewAPIError(err.Error(), &todoErr.APIErrorBody{
+			Message: "Internal server error",
+		})
+
+		return http.StatusInternalServerError, nil, apiError
+	}
+}
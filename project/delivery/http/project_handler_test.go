@@ -0,0 +1,237 @@
+package http_test
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dheerajgopi/todo-api/common"
+	_errors "github.com/dheerajgopi/todo-api/common/error"
+	"github.com/dheerajgopi/todo-api/config"
+	"github.com/dheerajgopi/todo-api/models"
+	"github.com/dheerajgopi/todo-api/project"
+	_projectHandler "github.com/dheerajgopi/todo-api/project/delivery/http"
+	mock "github.com/dheerajgopi/todo-api/project/mock"
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateWithBlankName(t *testing.T) {
+	reqBody := &_projectHandler.CreateProjectRequest{
+		Name: " ",
+	}
+
+	payload, _ := json.Marshal(reqBody)
+
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+	req := httptest.NewRequest("POST", "/projects", strings.NewReader(string(payload)))
+
+	status, data, err := handler.Create(httptest.NewRecorder(), req, reqCtx)
+
+	assert.Equal(400, status)
+	assert.Nil(data)
+	assert.Equal("name", err.Body[0].Target)
+}
+
+func TestCreate(t *testing.T) {
+	reqBody := &_projectHandler.CreateProjectRequest{
+		Name: " work ",
+	}
+
+	payload, _ := json.Marshal(reqBody)
+
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+	req := httptest.NewRequest("POST", "/projects", strings.NewReader(string(payload)))
+
+	mockService.
+		EXPECT().
+		Create(gomock.Any(), gomock.Any()).
+		Return(nil).
+		Times(1)
+
+	status, data, err := handler.Create(httptest.NewRecorder(), req, reqCtx)
+
+	responseData := data.(*_projectHandler.CreateProjectResponse)
+
+	assert.Equal(201, status)
+	assert.Nil(err)
+	assert.Equal("work", responseData.Project.Name)
+	assert.Equal(false, responseData.Project.IsArchived)
+}
+
+func TestListWithArchived(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+	req := httptest.NewRequest("GET", "/projects?archived=true", nil)
+
+	mockService.
+		EXPECT().
+		List(gomock.Any(), reqCtx.UserID, true).
+		Return([]*models.Project{{ID: 1, Name: "work", OpenTasks: 3, CompletedTasks: 2}}, nil).
+		Times(1)
+
+	status, data, err := handler.List(httptest.NewRecorder(), req, reqCtx)
+
+	responseData := data.(*_projectHandler.ListProjectResponse)
+
+	assert.Equal(200, status)
+	assert.Nil(err)
+	assert.Equal(1, len(responseData.Projects))
+	assert.Equal(int64(3), responseData.Projects[0].OpenTasks)
+	assert.Equal(int64(2), responseData.Projects[0].CompletedTasks)
+}
+
+func TestListWithInvalidArchived(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+	req := httptest.NewRequest("GET", "/projects?archived=maybe", nil)
+
+	status, data, err := handler.List(httptest.NewRecorder(), req, reqCtx)
+
+	assert.Equal(400, status)
+	assert.Nil(data)
+	assert.Equal("archived", err.Body[0].Target)
+}
+
+func TestPatch(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+
+	req := httptest.NewRequest("PATCH", "/projects/1", strings.NewReader(`{"isArchived": true}`))
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+
+	existingProject := &models.Project{
+		ID:        1,
+		Name:      "work",
+		CreatedBy: &models.User{ID: 1},
+	}
+
+	mockService.
+		EXPECT().
+		GetByID(gomock.Any(), int64(1), reqCtx.UserID).
+		Return(existingProject, nil).
+		Times(1)
+
+	mockService.
+		EXPECT().
+		Update(gomock.Any(), existingProject, reqCtx.UserID).
+		Return(nil).
+		Times(1)
+
+	status, data, err := handler.Patch(httptest.NewRecorder(), req, reqCtx)
+
+	responseData := data.(*_projectHandler.UpdateProjectResponse)
+
+	assert.Equal(200, status)
+	assert.Nil(err)
+	assert.Equal("work", responseData.Project.Name)
+	assert.Equal(true, responseData.Project.IsArchived)
+}
+
+func TestGetForMissingProject(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+
+	req := httptest.NewRequest("GET", "/projects/1", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+
+	mockService.
+		EXPECT().
+		GetByID(gomock.Any(), int64(1), reqCtx.UserID).
+		Return(nil, &_errors.ResourceNotFoundError{Resource: "project"}).
+		Times(1)
+
+	status, data, err := handler.Get(httptest.NewRecorder(), req, reqCtx)
+
+	assert.Equal(404, status)
+	assert.Nil(data)
+	assert.Equal("project", err.Body[0].Target)
+}
+
+func TestDeleteWithTasks(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+
+	req := httptest.NewRequest("DELETE", "/projects/1?tasks=delete", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+
+	mockService.
+		EXPECT().
+		Delete(gomock.Any(), int64(1), reqCtx.UserID, true).
+		Return(nil).
+		Times(1)
+
+	status, data, err := handler.Delete(httptest.NewRecorder(), req, reqCtx)
+
+	assert.Equal(200, status)
+	assert.Nil(data)
+	assert.Nil(err)
+}
+
+func TestDeleteWithInvalidTasksOption(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+
+	req := httptest.NewRequest("DELETE", "/projects/1?tasks=keep", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+
+	status, data, err := handler.Delete(httptest.NewRecorder(), req, reqCtx)
+
+	assert.Equal(400, status)
+	assert.Nil(data)
+	assert.Equal("tasks", err.Body[0].Target)
+}
+
+func setupHandler(mockService project.Service) *_projectHandler.ProjectHandler {
+	app := &common.App{
+		Logger: logrus.New(),
+		Config: &config.Config{},
+	}
+
+	handler := &_projectHandler.ProjectHandler{
+		ProjectService: mockService,
+		App:            app,
+	}
+
+	return handler
+}
+
+func setupRequestContext(app *common.App) *common.RequestContext {
+	reqCtx := &common.RequestContext{
+		RequestID: "dummyRequestID",
+		LogEntry: app.Logger.WithFields(
+			logrus.Fields{},
+		),
+	}
+
+	return reqCtx
+}
@@ -0,0 +1,123 @@
+package http
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	todoErr "github.com/dheerajgopi/todo-api/common/error"
+)
+
+// ProjectData represents json structure for project
+type ProjectData struct {
+	ID             int64     `json:"id"`
+	Name           string    `json:"name"`
+	IsArchived     bool      `json:"isArchived"`
+	OpenTasks      int64     `json:"openTasks"`
+	CompletedTasks int64     `json:"completedTasks"`
+	SharedBy       *UserData `json:"sharedBy,omitempty"`
+	CreatedAt      time.Time `json:"createdAt"`
+	UpdatedAt      time.Time `json:"updatedAt"`
+}
+
+// UserData represents json structure for the owner of a project shared with the user
+type UserData struct {
+	ID    int64  `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+// CreateProjectRequest represents request body for POST /projects API
+type CreateProjectRequest struct {
+	Name string `json:"name"`
+}
+
+// ValidateAndBuild validates the request body for POST /projects API
+func (body *CreateProjectRequest) ValidateAndBuild() []*todoErr.APIErrorBody {
+	name, err := validateName(body.Name)
+	validationErrors := make([]*todoErr.APIErrorBody, 0)
+
+	if err != nil {
+		validationErrors = append(validationErrors, err)
+	}
+
+	body.Name = name
+
+	return validationErrors
+}
+
+// PatchProjectRequest represents request body for PATCH /projects/{id} API.
+// It is used to rename, archive or unarchive a project.
+type PatchProjectRequest struct {
+	Name       *string `json:"name"`
+	IsArchived *bool   `json:"isArchived"`
+}
+
+// ValidateAndBuild validates the request body for PATCH /projects/{id} API
+func (body *PatchProjectRequest) ValidateAndBuild() []*todoErr.APIErrorBody {
+	validationErrors := make([]*todoErr.APIErrorBody, 0)
+
+	if body.Name != nil {
+		name, err := validateName(*body.Name)
+
+		if err != nil {
+			validationErrors = append(validationErrors, err)
+		}
+
+		body.Name = &name
+	}
+
+	return validationErrors
+}
+
+// Choices for the tasks of a deleted project
+const (
+	DeleteTasks      = "delete"
+	MoveTasksToInbox = "inbox"
+)
+
+// DeleteProjectRequest represents query parameters for DELETE /projects/{id} API
+type DeleteProjectRequest struct {
+	Tasks string
+}
+
+// ValidateAndBuild validates the query parameters for DELETE /projects/{id} API,
+// and reports whether the tasks of the project should be deleted. Tasks are moved to the inbox by default.
+func (params *DeleteProjectRequest) ValidateAndBuild() (bool, []*todoErr.APIErrorBody) {
+	validationErrors := make([]*todoErr.APIErrorBody, 0)
+
+	switch params.Tasks {
+	case "", MoveTasksToInbox:
+		return false, validationErrors
+	case DeleteTasks:
+		return true, validationErrors
+	}
+
+	validationErrors = append(validationErrors, &todoErr.APIErrorBody{
+		Message: "Value should be one of delete, inbox",
+		Target:  "tasks",
+	})
+
+	return false, validationErrors
+}
+
+// validateName checks the project name and returns its trimmed value
+func validateName(name string) (string, *todoErr.APIErrorBody) {
+	trimmedName := strings.TrimSpace(name)
+
+	if trimmedName == "" {
+		return trimmedName, &todoErr.APIErrorBody{
+			Message: "Non-empty value is required",
+			Target:  "name",
+		}
+	}
+
+	if utf8.RuneCountInString(trimmedName) > 255 {
+		return trimmedName, &todoErr.APIErrorBody{
+			Message: "Length should be 255 or less",
+			Target:  "name",
+		}
+	}
+
+	return trimmedName, nil
+}
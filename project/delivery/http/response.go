@@ -0,0 +1,46 @@
+package http
+
+import "github.com/dheerajgopi/todo-api/models"
+
+// CreateProjectResponse represents response for POST /projects API
+type CreateProjectResponse struct {
+	Project *ProjectData `json:"project"`
+}
+
+// ListProjectResponse represents response for GET /projects API
+type ListProjectResponse struct {
+	Projects []*ProjectData `json:"projects"`
+}
+
+// GetProjectResponse represents response for GET /projects/{id} API
+type GetProjectResponse struct {
+	Project *ProjectData `json:"project"`
+}
+
+// UpdateProjectResponse represents response for PATCH /projects/{id} API
+type UpdateProjectResponse struct {
+	Project *ProjectData `json:"project"`
+}
+
+// newProjectData builds the json structure of a project
+func newProjectData(project *models.Project) *ProjectData {
+	projectData := &ProjectData{
+		ID:             project.ID,
+		Name:           project.Name,
+		IsArchived:     project.IsArchived,
+		OpenTasks:      project.OpenTasks,
+		CompletedTasks: project.CompletedTasks,
+		CreatedAt:      project.CreatedAt,
+		UpdatedAt:      project.UpdatedAt,
+	}
+
+	if project.SharedBy != nil {
+		projectData.SharedBy = &UserData{
+			ID:    project.SharedBy.ID,
+			Name:  project.SharedBy.Name,
+			Email: project.SharedBy.Email,
+		}
+	}
+
+	return projectData
+}
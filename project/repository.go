@@ -0,0 +1,16 @@
+package project
+
+import (
+	"context"
+
+	"github.com/dheerajgopi/todo-api/models"
+)
+
+// Repository represents project's repository contract
+type Repository interface {
+	GetByID(ctx context.Context, id int64) (*models.Project, error)
+	GetAllByUserID(ctx context.Context, userID int64, includeArchived bool) ([]*models.Project, error)
+	Create(ctx context.Context, project *models.Project) error
+	Update(ctx context.Context, project *models.Project) error
+	Delete(ctx context.Context, id int64, deleteTasks bool) error
+}
@@ -0,0 +1,220 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/dheerajgopi/todo-api/models"
+	"github.com/dheerajgopi/todo-api/project"
+	taskRepository "github.com/dheerajgopi/todo-api/task/repository"
+)
+
+// selectProjectQuery reads the projects along with the number of open and completed tasks in them
+const selectProjectQuery = `SELECT p.id, p.name, p.is_archived, p.created_by, p.created_at, p.updated_at,
+	COUNT(CASE WHEN t.is_complete=0 THEN 1 END), COUNT(CASE WHEN t.is_complete=1 THEN 1 END)
+	FROM project p LEFT JOIN task t ON t.project_id=p.id AND t.deleted_at IS NULL `
+
+type mySQLProjectRepo struct {
+	DB *sql.DB
+}
+
+// New will return new object which implements project.Repository
+func New(db *sql.DB) project.Repository {
+	return &mySQLProjectRepo{
+		DB: db,
+	}
+}
+
+// rowScanner is implemented by both sql.Row and sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanProject reads a project from a row selected using selectProjectQuery
+func scanProject(row rowScanner) (*models.Project, error) {
+	project := &models.Project{}
+	userID := int64(0)
+
+	err := row.Scan(
+		&project.ID,
+		&project.Name,
+		&project.IsArchived,
+		&userID,
+		&project.CreatedAt,
+		&project.UpdatedAt,
+		&project.OpenTasks,
+		&project.CompletedTasks,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	project.CreatedBy = &models.User{
+		ID: userID,
+	}
+
+	return project, nil
+}
+
+// GetByID will return project with the given id
+func (repo *mySQLProjectRepo) GetByID(ctx context.Context, id int64) (*models.Project, error) {
+	query := selectProjectQuery + `WHERE p.id=? GROUP BY p.id`
+
+	stmt, err := repo.DB.PrepareContext(ctx, query)
+
+	if err != nil {
+		return nil, err
+	}
+
+	project, err := scanProject(stmt.QueryRowContext(ctx, id))
+
+	switch err {
+	case nil:
+	case sql.ErrNoRows:
+		return nil, nil
+	default:
+		return nil, err
+	}
+
+	return project, nil
+}
+
+// GetAllByUserID returns list of projects created by an user or shared with the user, ordered by name.
+// Archived projects are left out unless asked for.
+func (repo *mySQLProjectRepo) GetAllByUserID(ctx context.Context, userID int64, includeArchived bool) ([]*models.Project, error) {
+	query := selectProjectQuery + `WHERE (p.created_by=? OR p.id IN (SELECT project_id FROM project_collaborator WHERE user_id=?)) `
+
+	if !includeArchived {
+		query += `AND p.is_archived=0 `
+	}
+
+	query += `GROUP BY p.id ORDER BY p.name, p.id`
+
+	stmt, err := repo.DB.PrepareContext(ctx, query)
+
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := stmt.QueryContext(ctx, userID, userID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	projects := make([]*models.Project, 0)
+
+	for rows.Next() {
+		project, err := scanProject(rows)
+
+		if err != nil {
+			return nil, err
+		}
+
+		projects = append(projects, project)
+	}
+
+	err = rows.Err()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return projects, nil
+}
+
+// Create will store new project entry
+func (repo *mySQLProjectRepo) Create(ctx context.Context, project *models.Project) error {
+	query := `INSERT INTO project (name, is_archived, created_by, created_at, updated_at) VALUES (?, ?, ?, ?, ?)`
+
+	tx, err := repo.DB.BeginTx(ctx, nil)
+
+	if err != nil {
+		return err
+	}
+
+	res, err := tx.Exec(
+		query,
+		project.Name,
+		project.IsArchived,
+		project.CreatedBy.ID,
+		project.CreatedAt,
+		project.UpdatedAt,
+	)
+
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	lastID, err := res.LastInsertId()
+
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	err = tx.Commit()
+
+	if err != nil {
+		return err
+	}
+
+	project.ID = lastID
+
+	return nil
+}
+
+// Update will overwrite the name and archived flag of an existing project entry
+func (repo *mySQLProjectRepo) Update(ctx context.Context, project *models.Project) error {
+	query := `UPDATE project SET name=?, is_archived=?, updated_at=? WHERE id=?`
+
+	tx, err := repo.DB.BeginTx(ctx, nil)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(query, project.Name, project.IsArchived, project.UpdatedAt, project.ID)
+
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Delete will remove the project entry with the given id.
+// Tasks of the project are either moved to trash along with it (recording the deletion in their history), or moved to the inbox.
+func (repo *mySQLProjectRepo) Delete(ctx context.Context, id int64, deleteTasks bool) error {
+	tx, err := repo.DB.BeginTx(ctx, nil)
+
+	if err != nil {
+		return err
+	}
+
+	if deleteTasks {
+		err = taskRepository.DeleteAll(ctx, tx, time.Now(), `project_id=?`, id)
+	} else {
+		_, err = tx.Exec(`UPDATE task SET project_id=NULL WHERE project_id=?`, id)
+	}
+
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	_, err = tx.Exec(`DELETE FROM project WHERE id=?`, id)
+
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,258 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/dheerajgopi/todo-api/history"
+	"github.com/dheerajgopi/todo-api/models"
+	"github.com/dheerajgopi/todo-api/project/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+var projectColumns = []string{"id", "name", "is_archived", "created_by", "created_at", "updated_at", "open_tasks", "completed_tasks"}
+
+const selectProjectQuery = "SELECT p.id, p.name, p.is_archived, p.created_by, p.created_at, p.updated_at,\\s+" +
+	"COUNT\\(CASE WHEN t.is_complete=0 THEN 1 END\\), COUNT\\(CASE WHEN t.is_complete=1 THEN 1 END\\)\\s+" +
+	"FROM project p LEFT JOIN task t ON t.project_id=p.id AND t.deleted_at IS NULL "
+
+func TestGetByID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	rows := sqlmock.
+		NewRows(projectColumns).
+		AddRow(1, "work", false, 1, time.Now(), time.Now(), 3, 2)
+
+	projectID := int64(1)
+
+	prep := mock.ExpectPrepare(selectProjectQuery + "WHERE p.id=\\? GROUP BY p.id")
+	prep.ExpectQuery().WithArgs(projectID).WillReturnRows(rows)
+
+	repo := repository.New(db)
+
+	project, err := repo.GetByID(context.TODO(), projectID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "work", project.Name)
+	assert.Equal(t, int64(3), project.OpenTasks)
+	assert.Equal(t, int64(2), project.CompletedTasks)
+	assert.Equal(t, int64(1), project.CreatedBy.ID)
+}
+
+func TestGetByIDWithNoRows(t *testing.T) {
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	projectID := int64(1)
+
+	prep := mock.ExpectPrepare(selectProjectQuery + "WHERE p.id=\\? GROUP BY p.id")
+	prep.ExpectQuery().WithArgs(projectID).WillReturnRows(sqlmock.NewRows(projectColumns))
+
+	repo := repository.New(db)
+
+	project, err := repo.GetByID(context.TODO(), projectID)
+
+	assert.NoError(t, err)
+	assert.Nil(t, project)
+}
+
+func TestGetAllByUserID(t *testing.T) {
+	assert := assert.New(t)
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	rows := sqlmock.
+		NewRows(projectColumns).
+		AddRow(2, "home", false, 1, time.Now(), time.Now(), 0, 0).
+		AddRow(1, "work", false, 1, time.Now(), time.Now(), 3, 2)
+
+	userID := int64(1)
+
+	prep := mock.ExpectPrepare(selectProjectQuery + "WHERE \\(p.created_by=\\? OR p.id IN \\(SELECT project_id FROM project_collaborator WHERE user_id=\\?\\)\\) " +
+		"AND p.is_archived=0 GROUP BY p.id ORDER BY p.name, p.id")
+	prep.ExpectQuery().WithArgs(userID, userID).WillReturnRows(rows)
+
+	repo := repository.New(db)
+
+	projects, err := repo.GetAllByUserID(context.TODO(), userID, false)
+
+	assert.NoError(err)
+	assert.Equal(2, len(projects))
+	assert.Equal("home", projects[0].Name)
+	assert.NoError(mock.ExpectationsWereMet())
+}
+
+func TestGetAllByUserIDWithArchived(t *testing.T) {
+	assert := assert.New(t)
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	rows := sqlmock.
+		NewRows(projectColumns).
+		AddRow(1, "old", true, 1, time.Now(), time.Now(), 0, 4)
+
+	userID := int64(1)
+
+	prep := mock.ExpectPrepare(selectProjectQuery + "WHERE \\(p.created_by=\\? OR p.id IN \\(SELECT project_id FROM project_collaborator WHERE user_id=\\?\\)\\) " +
+		"GROUP BY p.id ORDER BY p.name, p.id")
+	prep.ExpectQuery().WithArgs(userID, userID).WillReturnRows(rows)
+
+	repo := repository.New(db)
+
+	projects, err := repo.GetAllByUserID(context.TODO(), userID, true)
+
+	assert.NoError(err)
+	assert.Equal(1, len(projects))
+	assert.Equal(true, projects[0].IsArchived)
+	assert.NoError(mock.ExpectationsWereMet())
+}
+
+func TestCreate(t *testing.T) {
+	now := time.Now()
+	project := &models.Project{
+		Name: "work",
+		CreatedBy: &models.User{
+			ID: int64(1),
+		},
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	query := "INSERT INTO project \\(name, is_archived, created_by, created_at, updated_at\\) VALUES \\(\\?, \\?, \\?, \\?, \\?\\)"
+
+	mock.ExpectBegin()
+	mock.ExpectExec(query).
+		WithArgs(project.Name, false, project.CreatedBy.ID, project.CreatedAt, project.UpdatedAt).
+		WillReturnResult(sqlmock.NewResult(4, 1))
+	mock.ExpectCommit()
+
+	repo := repository.New(db)
+
+	err = repo.Create(context.TODO(), project)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), project.ID)
+}
+
+func TestUpdate(t *testing.T) {
+	now := time.Now()
+	project := &models.Project{
+		ID:         1,
+		Name:       "work",
+		IsArchived: true,
+		UpdatedAt:  now,
+	}
+
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE project SET name=\\?, is_archived=\\?, updated_at=\\? WHERE id=\\?").
+		WithArgs(project.Name, true, project.UpdatedAt, project.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	repo := repository.New(db)
+
+	err = repo.Update(context.TODO(), project)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteMovingTasksToInbox(t *testing.T) {
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	projectID := int64(1)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE task SET project_id=NULL WHERE project_id=\\?").WithArgs(projectID).WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec("DELETE FROM project WHERE id=\\?").WithArgs(projectID).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	repo := repository.New(db)
+
+	err = repo.Delete(context.TODO(), projectID, false)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteWithTasks(t *testing.T) {
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	projectID := int64(1)
+
+	insertEventQuery := "INSERT INTO task_event \\(task_id, type, old_value, new_value, actor_id, request_id, created_at\\)"
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT id, title FROM task WHERE project_id=\\? AND deleted_at IS NULL FOR UPDATE").
+		WithArgs(projectID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title"}).AddRow(int64(2), "first").AddRow(int64(3), "second"))
+	mock.ExpectExec("UPDATE task SET deleted_at=\\?, revision=revision\\+1 WHERE project_id=\\? AND deleted_at IS NULL").
+		WithArgs(sqlmock.AnyArg(), projectID).WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(insertEventQuery).
+		WithArgs(int64(2), "deleted", "first", nil, int64(5), "request-1", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(insertEventQuery).
+		WithArgs(int64(3), "deleted", "second", nil, int64(5), "request-1", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(2, 1))
+	mock.ExpectExec("DELETE FROM project WHERE id=\\?").WithArgs(projectID).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	repo := repository.New(db)
+	ctx := history.WithActor(context.TODO(), &history.Actor{UserID: 5, RequestID: "request-1"})
+
+	err = repo.Delete(ctx, projectID, true)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,16 @@
+package project
+
+import (
+	"context"
+
+	"github.com/dheerajgopi/todo-api/models"
+)
+
+// Service represents project service contract
+type Service interface {
+	Create(ctx context.Context, newProject *models.Project) error
+	List(ctx context.Context, userID int64, includeArchived bool) ([]*models.Project, error)
+	GetByID(ctx context.Context, id int64, userID int64) (*models.Project, error)
+	Update(ctx context.Context, project *models.Project, userID int64) error
+	Delete(ctx context.Context, id int64, userID int64, deleteTasks bool) error
+}
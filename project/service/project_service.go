@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+
+	"github.com/dheerajgopi/todo-api/collaborator"
+	"github.com/dheerajgopi/todo-api/models"
+	"github.com/dheerajgopi/todo-api/project"
+	"github.com/dheerajgopi/todo-api/user"
+)
+
+type projectService struct {
+	projectRepo      project.Repository
+	collaboratorRepo collaborator.Repository
+	userRepo         user.Repository
+}
+
+// New returns a new object implementing project.Service interface
+func New(repo project.Repository, collaboratorRepo collaborator.Repository, userRepo user.Repository) project.Service {
+	return &projectService{
+		projectRepo:      repo,
+		collaboratorRepo: collaboratorRepo,
+		userRepo:         userRepo,
+	}
+}
+
+// Create creates a new project
+func (service *projectService) Create(ctx context.Context, newProject *models.Project) error {
+	return service.projectRepo.Create(ctx, newProject)
+}
+
+// List returns projects created by an user, or shared with the user
+func (service *projectService) List(ctx context.Context, userID int64, includeArchived bool) ([]*models.Project, error) {
+	projects, err := service.projectRepo.GetAllByUserID(ctx, userID, includeArchived)
+
+	if err != nil {
+		return nil, err
+	}
+
+	owners := make(map[int64]*models.User)
+
+	for _, sharedProject := range projects {
+		if err = service.markShared(ctx, sharedProject, userID, owners); err != nil {
+			return nil, err
+		}
+	}
+
+	return projects, nil
+}
+
+// GetByID returns the project with the given id, if it is owned by the user or shared with the user.
+// ResourceNotFoundError is returned if the project is missing or not accessible to the user.
+func (service *projectService) GetByID(ctx context.Context, id int64, userID int64) (*models.Project, error) {
+	existingProject, err := service.authorize(ctx, id, userID, collaborator.RoleViewer, "view")
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err = service.markShared(ctx, existingProject, userID, make(map[int64]*models.User)); err != nil {
+		return nil, err
+	}
+
+	return existingProject, nil
+}
+
+// Update renames, archives or unarchives an existing project owned by the user.
+// PermissionDeniedError is returned if the user is a collaborator of the project.
+func (service *projectService) Update(ctx context.Context, project *models.Project, userID int64) error {
+	existingProject, err := service.authorize(ctx, project.ID, userID, collaborator.RoleOwner, "update")
+
+	if err != nil {
+		return err
+	}
+
+	project.CreatedBy = existingProject.CreatedBy
+	project.OpenTasks = existingProject.OpenTasks
+	project.CompletedTasks = existingProject.CompletedTasks
+	project.CreatedAt = existingProject.CreatedAt
+
+	return service.projectRepo.Update(ctx, project)
+}
+
+// Delete removes an existing project owned by the user.
+// Its tasks are deleted too if deleteTasks is set, otherwise they are moved to the inbox.
+// PermissionDeniedError is returned if the user is a collaborator of the project.
+func (service *projectService) Delete(ctx context.Context, id int64, userID int64, deleteTasks bool) error {
+	if _, err := service.authorize(ctx, id, userID, collaborator.RoleOwner, "delete"); err != nil {
+		return err
+	}
+
+	return service.projectRepo.Delete(ctx, id, deleteTasks)
+}
+
+// authorize loads a project and verifies that the role of the user on it allows an action.
+// ResourceNotFoundError is returned if the project is missing or not accessible to the user,
+// and PermissionDeniedError is returned if the role of the user is lower than the required role.
+func (service *projectService) authorize(ctx context.Context, id int64, userID int64, required string, action string) (*models.Project, error) {
+	existingProject, err := service.projectRepo.GetByID(ctx, id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	role := ""
+
+	if existingProject != nil {
+		role = collaborator.RoleOwner
+
+		if existingProject.CreatedBy.ID != userID {
+			if role, err = service.collaboratorRepo.GetRole(ctx, collaborator.ResourceProject, id, userID); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err = collaborator.CheckRole(collaborator.ResourceProject, role, required, action); err != nil {
+		return nil, err
+	}
+
+	return existingProject, nil
+}
+
+// markShared sets the owner as SharedBy of a project which is not owned by the user.
+// Owners are read once per call, and cached in the given map.
+func (service *projectService) markShared(ctx context.Context, sharedProject *models.Project, userID int64, owners map[int64]*models.User) error {
+	ownerID := sharedProject.CreatedBy.ID
+
+	if ownerID == userID {
+		return nil
+	}
+
+	owner, ok := owners[ownerID]
+
+	if !ok {
+		existingUser, err := service.userRepo.GetByID(ctx, ownerID)
+
+		if err != nil {
+			return err
+		}
+
+		if existingUser != nil {
+			owner = &models.User{
+				ID:    existingUser.ID,
+				Name:  existingUser.Name,
+				Email: existingUser.Email,
+			}
+		}
+
+		owners[ownerID] = owner
+	}
+
+	sharedProject.SharedBy = owner
+
+	return nil
+}
@@ -0,0 +1,284 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dheerajgopi/todo-api/collaborator"
+	collaboratorMock "github.com/dheerajgopi/todo-api/collaborator/mock"
+	todoErr "github.com/dheerajgopi/todo-api/common/error"
+	"github.com/dheerajgopi/todo-api/models"
+	projectMock "github.com/dheerajgopi/todo-api/project/mock"
+	"github.com/dheerajgopi/todo-api/project/service"
+	userMock "github.com/dheerajgopi/todo-api/user/mock"
+)
+
+func TestCreate(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	mockRepo := projectMock.NewRepository(mockCtrl)
+	mockCollaboratorRepo := collaboratorMock.NewRepository(mockCtrl)
+	mockUserRepo := userMock.NewRepository(mockCtrl)
+	projectService := service.New(mockRepo, mockCollaboratorRepo, mockUserRepo)
+
+	newProject := &models.Project{
+		Name: "work",
+		CreatedBy: &models.User{
+			ID: 1,
+		},
+	}
+
+	mockRepo.
+		EXPECT().
+		Create(ctx, newProject).
+		Return(errors.New("error")).
+		Times(1)
+
+	err := projectService.Create(ctx, newProject)
+
+	assert.Error(err)
+}
+
+func TestList(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	mockRepo := projectMock.NewRepository(mockCtrl)
+	mockCollaboratorRepo := collaboratorMock.NewRepository(mockCtrl)
+	mockUserRepo := userMock.NewRepository(mockCtrl)
+	projectService := service.New(mockRepo, mockCollaboratorRepo, mockUserRepo)
+
+	mockRepo.
+		EXPECT().
+		GetAllByUserID(ctx, int64(1), true).
+		Return([]*models.Project{
+			{ID: 1, Name: "home", CreatedBy: &models.User{ID: 2}},
+			{ID: 2, Name: "work", CreatedBy: &models.User{ID: 1}},
+			{ID: 3, Name: "trip", CreatedBy: &models.User{ID: 2}},
+		}, nil).
+		Times(1)
+
+	mockUserRepo.
+		EXPECT().
+		GetByID(ctx, int64(2)).
+		Return(&models.User{ID: 2, Name: "jane", Email: "jane@example.com", Passwd: "secret"}, nil).
+		Times(1)
+
+	projects, err := projectService.List(ctx, 1, true)
+
+	assert.NoError(err)
+	assert.Equal(3, len(projects))
+	assert.Equal(&models.User{ID: 2, Name: "jane", Email: "jane@example.com"}, projects[0].SharedBy)
+	assert.Nil(projects[1].SharedBy)
+	assert.Equal("jane", projects[2].SharedBy.Name)
+}
+
+func TestGetByIDForProjectOwnedByOtherUser(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	mockRepo := projectMock.NewRepository(mockCtrl)
+	mockCollaboratorRepo := collaboratorMock.NewRepository(mockCtrl)
+	mockUserRepo := userMock.NewRepository(mockCtrl)
+	projectService := service.New(mockRepo, mockCollaboratorRepo, mockUserRepo)
+
+	mockRepo.
+		EXPECT().
+		GetByID(ctx, int64(1)).
+		Return(&models.Project{ID: 1, CreatedBy: &models.User{ID: 2}}, nil).
+		Times(1)
+
+	mockCollaboratorRepo.
+		EXPECT().
+		GetRole(ctx, collaborator.ResourceProject, int64(1), int64(1)).
+		Return("", nil).
+		Times(1)
+
+	result, err := projectService.GetByID(ctx, 1, 1)
+
+	assert.Nil(result)
+	assert.IsType(&todoErr.ResourceNotFoundError{}, err)
+}
+
+func TestGetByIDForSharedProject(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	mockRepo := projectMock.NewRepository(mockCtrl)
+	mockCollaboratorRepo := collaboratorMock.NewRepository(mockCtrl)
+	mockUserRepo := userMock.NewRepository(mockCtrl)
+	projectService := service.New(mockRepo, mockCollaboratorRepo, mockUserRepo)
+
+	mockRepo.
+		EXPECT().
+		GetByID(ctx, int64(1)).
+		Return(&models.Project{ID: 1, CreatedBy: &models.User{ID: 2}}, nil).
+		Times(1)
+
+	mockCollaboratorRepo.
+		EXPECT().
+		GetRole(ctx, collaborator.ResourceProject, int64(1), int64(1)).
+		Return(collaborator.RoleViewer, nil).
+		Times(1)
+
+	mockUserRepo.
+		EXPECT().
+		GetByID(ctx, int64(2)).
+		Return(&models.User{ID: 2, Name: "jane", Email: "jane@example.com"}, nil).
+		Times(1)
+
+	result, err := projectService.GetByID(ctx, 1, 1)
+
+	assert.NoError(err)
+	assert.Equal(int64(2), result.SharedBy.ID)
+}
+
+func TestUpdateByCollaborator(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	mockRepo := projectMock.NewRepository(mockCtrl)
+	mockCollaboratorRepo := collaboratorMock.NewRepository(mockCtrl)
+	mockUserRepo := userMock.NewRepository(mockCtrl)
+	projectService := service.New(mockRepo, mockCollaboratorRepo, mockUserRepo)
+
+	mockRepo.
+		EXPECT().
+		GetByID(ctx, int64(1)).
+		Return(&models.Project{ID: 1, CreatedBy: &models.User{ID: 2}}, nil).
+		Times(1)
+
+	mockCollaboratorRepo.
+		EXPECT().
+		GetRole(ctx, collaborator.ResourceProject, int64(1), int64(1)).
+		Return(collaborator.RoleEditor, nil).
+		Times(1)
+
+	err := projectService.Update(ctx, &models.Project{ID: 1, Name: "office"}, 1)
+
+	assert.IsType(&todoErr.PermissionDeniedError{}, err)
+}
+
+func TestUpdate(t *testing.T) {
+	createdAt := time.Now().Add(-time.Hour)
+	userID := int64(1)
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	mockRepo := projectMock.NewRepository(mockCtrl)
+	mockCollaboratorRepo := collaboratorMock.NewRepository(mockCtrl)
+	mockUserRepo := userMock.NewRepository(mockCtrl)
+	projectService := service.New(mockRepo, mockCollaboratorRepo, mockUserRepo)
+
+	existingProject := &models.Project{
+		ID:   1,
+		Name: "work",
+		CreatedBy: &models.User{
+			ID: userID,
+		},
+		OpenTasks: 3,
+		CreatedAt: createdAt,
+	}
+
+	updatedProject := &models.Project{
+		ID:         1,
+		Name:       "office",
+		IsArchived: true,
+	}
+
+	mockRepo.
+		EXPECT().
+		GetByID(ctx, int64(1)).
+		Return(existingProject, nil).
+		Times(1)
+
+	mockRepo.
+		EXPECT().
+		Update(ctx, updatedProject).
+		Return(nil).
+		Times(1)
+
+	err := projectService.Update(ctx, updatedProject, userID)
+
+	assert.NoError(err)
+	assert.Equal(userID, updatedProject.CreatedBy.ID)
+	assert.Equal(int64(3), updatedProject.OpenTasks)
+	assert.Equal(createdAt, updatedProject.CreatedAt)
+}
+
+func TestDelete(t *testing.T) {
+	userID := int64(1)
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	mockRepo := projectMock.NewRepository(mockCtrl)
+	mockCollaboratorRepo := collaboratorMock.NewRepository(mockCtrl)
+	mockUserRepo := userMock.NewRepository(mockCtrl)
+	projectService := service.New(mockRepo, mockCollaboratorRepo, mockUserRepo)
+
+	mockRepo.
+		EXPECT().
+		GetByID(ctx, int64(1)).
+		Return(&models.Project{ID: 1, CreatedBy: &models.User{ID: userID}}, nil).
+		Times(1)
+
+	mockRepo.
+		EXPECT().
+		Delete(ctx, int64(1), true).
+		Return(nil).
+		Times(1)
+
+	err := projectService.Delete(ctx, 1, userID, true)
+
+	assert.NoError(err)
+}
+
+func TestDeleteForMissingProject(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	mockRepo := projectMock.NewRepository(mockCtrl)
+	mockCollaboratorRepo := collaboratorMock.NewRepository(mockCtrl)
+	mockUserRepo := userMock.NewRepository(mockCtrl)
+	projectService := service.New(mockRepo, mockCollaboratorRepo, mockUserRepo)
+
+	mockRepo.
+		EXPECT().
+		GetByID(ctx, int64(1)).
+		Return(nil, nil).
+		Times(1)
+
+	err := projectService.Delete(ctx, 1, 1, false)
+
+	assert.IsType(&todoErr.ResourceNotFoundError{}, err)
+}
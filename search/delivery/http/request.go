@@ -0,0 +1,65 @@
+package http
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	todoErr "github.com/dheerajgopi/todo-api/common/error"
+	"github.com/dheerajgopi/todo-api/search"
+)
+
+// maxQueryLength is the maximum number of characters in a search text
+const maxQueryLength = 200
+
+// SearchRequest represents query parameters for GET /tasks/search API
+type SearchRequest struct {
+	Text  string
+	Limit string
+}
+
+// NewSearchRequest reads the query parameters for GET /tasks/search API
+func NewSearchRequest(query url.Values) *SearchRequest {
+	return &SearchRequest{
+		Text:  query.Get("q"),
+		Limit: query.Get("limit"),
+	}
+}
+
+// ValidateAndBuild validates the query parameters for GET /tasks/search API and builds the search query
+func (params *SearchRequest) ValidateAndBuild() (*search.Query, []*todoErr.APIErrorBody) {
+	query := &search.Query{
+		Text:  strings.TrimSpace(params.Text),
+		Limit: search.DefaultLimit,
+	}
+
+	validationErrors := make([]*todoErr.APIErrorBody, 0)
+
+	if query.Text == "" {
+		validationErrors = append(validationErrors, &todoErr.APIErrorBody{
+			Message: "Non-empty value is required",
+			Target:  "q",
+		})
+	} else if utf8.RuneCountInString(query.Text) > maxQueryLength {
+		validationErrors = append(validationErrors, &todoErr.APIErrorBody{
+			Message: "Length should be 200 or less",
+			Target:  "q",
+		})
+	}
+
+	if params.Limit != "" {
+		limit, err := strconv.Atoi(params.Limit)
+
+		if err != nil || limit < 1 || limit > search.MaxLimit {
+			validationErrors = append(validationErrors, &todoErr.APIErrorBody{
+				Message: "Value should be between 1 and " + strconv.Itoa(search.MaxLimit),
+				Target:  "limit",
+			})
+		} else {
+			query.Limit = limit
+		}
+	}
+
+	return query, validationErrors
+}
@@ -0,0 +1,59 @@
+package http
+
+import (
+	"time"
+
+	"github.com/dheerajgopi/todo-api/search"
+)
+
+// TaskData represents json structure for a task found by search
+type TaskData struct {
+	ID         int64      `json:"id"`
+	Title      string     `json:"title"`
+	IsComplete bool       `json:"isComplete"`
+	DueAt      *time.Time `json:"dueAt"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	UpdatedAt  time.Time  `json:"updatedAt"`
+}
+
+// SnippetData represents json structure for a snippet. Matching words are wrapped in mark tags,
+// and rest of the text is HTML escaped.
+type SnippetData struct {
+	Field string `json:"field"`
+	Text  string `json:"text"`
+}
+
+// ResultData represents json structure for a search result
+type ResultData struct {
+	Task     *TaskData      `json:"task"`
+	Snippets []*SnippetData `json:"snippets"`
+}
+
+// SearchResponse represents response for GET /tasks/search API
+type SearchResponse struct {
+	Results []*ResultData `json:"results"`
+}
+
+// newResultData builds the json structure of a search result
+func newResultData(result *search.Result) *ResultData {
+	snippets := make([]*SnippetData, 0)
+
+	for _, snippet := range result.Snippets {
+		snippets = append(snippets, &SnippetData{
+			Field: snippet.Field,
+			Text:  snippet.Text,
+		})
+	}
+
+	return &ResultData{
+		Task: &TaskData{
+			ID:         result.Task.ID,
+			Title:      result.Task.Title,
+			IsComplete: result.Task.IsComplete,
+			DueAt:      result.Task.DueAt,
+			CreatedAt:  result.Task.CreatedAt,
+			UpdatedAt:  result.Task.UpdatedAt,
+		},
+		Snippets: snippets,
+	}
+}
@@ -0,0 +1,67 @@
+package http
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/dheerajgopi/todo-api/auth"
+	"github.com/dheerajgopi/todo-api/common"
+	todoErr "github.com/dheerajgopi/todo-api/common/error"
+	"github.com/dheerajgopi/todo-api/common/middlewares"
+	"github.com/dheerajgopi/todo-api/search"
+	"github.com/gorilla/mux"
+)
+
+// SearchHandler represents HTTP handler for searching tasks
+type SearchHandler struct {
+	SearchService search.Service
+	App           *common.App
+}
+
+// New creates new HTTP handler for search
+func New(router *mux.Router, service search.Service, app *common.App) {
+	handler := &SearchHandler{
+		SearchService: service,
+		App:           app,
+	}
+
+	// API keys are accepted besides access tokens, as far as they grant the scope
+	readMiddleware := middlewares.ScopedValidator(app, auth.ScopeTasksRead)
+
+	router.HandleFunc("/tasks/search", app.CreateHandler(readMiddleware(handler.Search))).Methods("GET")
+}
+
+// Search will return the tasks matching the text in their title, description or comments
+func (handler *SearchHandler) Search(res http.ResponseWriter, req *http.Request, reqCtx *common.RequestContext) (int, interface{}, *todoErr.APIError) {
+	query, validationErrors := NewSearchRequest(req.URL.Query()).ValidateAndBuild()
+
+	if len(validationErrors) > 0 {
+		apiError := todoErr.NewAPIError("", validationErrors...)
+
+		return http.StatusBadRequest, nil, apiError
+	}
+
+	query.UserID = reqCtx.UserID
+
+	results, err := handler.SearchService.Search(context.TODO(), query)
+
+	if err != nil {
+		apiError := todoErr.NewAPIError(err.Error(), &todoErr.APIErrorBody{
+			Message: "Internal server error",
+		})
+
+		return http.StatusInternalServerError, nil, apiError
+	}
+
+	resultList := make([]*ResultData, 0)
+
+	for _, result := range results {
+		resultList = append(resultList, newResultData(result))
+	}
+
+	responseData := &SearchResponse{
+		Results: resultList,
+	}
+
+	return http.StatusOK, responseData, nil
+}
@@ -0,0 +1,90 @@
+package http_test
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dheerajgopi/todo-api/common"
+	"github.com/dheerajgopi/todo-api/config"
+	"github.com/dheerajgopi/todo-api/models"
+	"github.com/dheerajgopi/todo-api/search"
+	_searchHandler "github.com/dheerajgopi/todo-api/search/delivery/http"
+	mock "github.com/dheerajgopi/todo-api/search/mock"
+	"github.com/golang/mock/gomock"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearch(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+	reqCtx.UserID = 1
+	req := httptest.NewRequest("GET", "/tasks/search?q=%20quarterly+report%20&limit=5", nil)
+
+	mockService.
+		EXPECT().
+		Search(gomock.Any(), &search.Query{Text: "quarterly report", UserID: 1, Limit: 5}).
+		Return([]*search.Result{
+			{
+				Task:     &models.Task{ID: 5, Title: "Quarterly report"},
+				Snippets: []*search.Snippet{{Field: "title", Text: "<mark>Quarterly</mark> <mark>report</mark>"}},
+			},
+		}, nil).
+		Times(1)
+
+	status, data, err := handler.Search(httptest.NewRecorder(), req, reqCtx)
+
+	responseData := data.(*_searchHandler.SearchResponse)
+
+	assert.Equal(200, status)
+	assert.Nil(err)
+	assert.Equal(1, len(responseData.Results))
+	assert.Equal(int64(5), responseData.Results[0].Task.ID)
+	assert.Equal("title", responseData.Results[0].Snippets[0].Field)
+	assert.Equal("<mark>Quarterly</mark> <mark>report</mark>", responseData.Results[0].Snippets[0].Text)
+}
+
+func TestSearchWithInvalidParams(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+	req := httptest.NewRequest("GET", "/tasks/search?q=%20&limit=500", nil)
+
+	status, data, err := handler.Search(httptest.NewRecorder(), req, reqCtx)
+
+	assert.Equal(400, status)
+	assert.Nil(data)
+	assert.Equal(2, len(err.Body))
+	assert.Equal("q", err.Body[0].Target)
+	assert.Equal("limit", err.Body[1].Target)
+}
+
+func setupHandler(mockService search.Service) *_searchHandler.SearchHandler {
+	app := &common.App{
+		Logger: logrus.New(),
+		Config: &config.Config{},
+	}
+
+	handler := &_searchHandler.SearchHandler{
+		SearchService: mockService,
+		App:           app,
+	}
+
+	return handler
+}
+
+func setupRequestContext(app *common.App) *common.RequestContext {
+	reqCtx := &common.RequestContext{
+		RequestID: "dummyRequestID",
+		LogEntry: app.Logger.WithFields(
+			logrus.Fields{},
+		),
+	}
+
+	return reqCtx
+}
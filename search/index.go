@@ -0,0 +1,23 @@
+package search
+
+import (
+	"context"
+
+	"github.com/dheerajgopi/todo-api/models"
+)
+
+// Index represents search index contract.
+// Hits are ordered by relevance. Indexes may leave out tasks which are not visible to the user of the query,
+// but visibility is checked again for each hit before it is returned to the user.
+type Index interface {
+	Search(ctx context.Context, query *Query) ([]*Hit, error)
+}
+
+// Indexer is implemented by indexes which are kept up to date by the application, rather than by the database
+type Indexer interface {
+	Index
+	PutTask(task *models.Task)
+	RemoveTask(id int64)
+	PutComment(comment *models.Comment)
+	RemoveComment(id int64)
+}
@@ -0,0 +1,56 @@
+package indexing
+
+import (
+	"context"
+
+	"github.com/dheerajgopi/todo-api/comment"
+	"github.com/dheerajgopi/todo-api/models"
+	"github.com/dheerajgopi/todo-api/search"
+)
+
+// commentRepo passes the comments written to a comment repository on to an indexer
+type commentRepo struct {
+	comment.Repository
+	indexer search.Indexer
+}
+
+// NewCommentRepository wraps a comment repository, so that the indexer is updated whenever a comment is stored or removed
+func NewCommentRepository(repo comment.Repository, indexer search.Indexer) comment.Repository {
+	return &commentRepo{
+		Repository: repo,
+		indexer:    indexer,
+	}
+}
+
+// Create stores a comment and adds it to the index
+func (repo *commentRepo) Create(ctx context.Context, comment *models.Comment) error {
+	if err := repo.Repository.Create(ctx, comment); err != nil {
+		return err
+	}
+
+	repo.indexer.PutComment(comment)
+
+	return nil
+}
+
+// Update stores the new body of a comment and indexes it
+func (repo *commentRepo) Update(ctx context.Context, comment *models.Comment) error {
+	if err := repo.Repository.Update(ctx, comment); err != nil {
+		return err
+	}
+
+	repo.indexer.PutComment(comment)
+
+	return nil
+}
+
+// Delete removes a comment from the repository and from the index
+func (repo *commentRepo) Delete(ctx context.Context, id int64) error {
+	if err := repo.Repository.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	repo.indexer.RemoveComment(id)
+
+	return nil
+}
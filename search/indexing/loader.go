@@ -0,0 +1,73 @@
+package indexing
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/dheerajgopi/todo-api/models"
+	"github.com/dheerajgopi/todo-api/search"
+)
+
+// Load fills an indexer with all the stored tasks and comments. It is meant to be run once, at startup.
+func Load(ctx context.Context, db *sql.DB, indexer search.Indexer) error {
+	if err := loadTasks(ctx, db, indexer); err != nil {
+		return err
+	}
+
+	return loadComments(ctx, db, indexer)
+}
+
+// loadTasks indexes the title and description of all tasks
+func loadTasks(ctx context.Context, db *sql.DB, indexer search.Indexer) error {
+	rows, err := db.QueryContext(ctx, `SELECT id, parent_id, title, COALESCE(description, '') FROM task`)
+
+	if err != nil {
+		return err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		task := &models.Task{}
+		parentID := sql.NullInt64{}
+
+		if err = rows.Scan(&task.ID, &parentID, &task.Title, &task.Description); err != nil {
+			return err
+		}
+
+		if parentID.Valid {
+			task.Parent = &models.Task{
+				ID: parentID.Int64,
+			}
+		}
+
+		indexer.PutTask(task)
+	}
+
+	return rows.Err()
+}
+
+// loadComments indexes the body of all comments
+func loadComments(ctx context.Context, db *sql.DB, indexer search.Indexer) error {
+	rows, err := db.QueryContext(ctx, `SELECT id, task_id, body FROM comment`)
+
+	if err != nil {
+		return err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		comment := &models.Comment{
+			Task: &models.Task{},
+		}
+
+		if err = rows.Scan(&comment.ID, &comment.Task.ID, &comment.Body); err != nil {
+			return err
+		}
+
+		indexer.PutComment(comment)
+	}
+
+	return rows.Err()
+}
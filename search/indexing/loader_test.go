@@ -0,0 +1,47 @@
+package indexing_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dheerajgopi/todo-api/models"
+	"github.com/dheerajgopi/todo-api/search/indexing"
+	searchMock "github.com/dheerajgopi/todo-api/search/mock"
+)
+
+func TestLoad(t *testing.T) {
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	mockIndexer := searchMock.NewIndexer(mockCtrl)
+
+	mock.ExpectQuery("SELECT id, parent_id, title, COALESCE\\(description, ''\\) FROM task").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "parent_id", "title", "description"}).
+			AddRow(1, nil, "Move house", "").
+			AddRow(2, 1, "Pack boxes", "Kitchen first"))
+	mock.ExpectQuery("SELECT id, task_id, body FROM comment").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "task_id", "body"}).
+			AddRow(10, 1, "Rent a van"))
+
+	gomock.InOrder(
+		mockIndexer.EXPECT().PutTask(&models.Task{ID: 1, Title: "Move house"}),
+		mockIndexer.EXPECT().PutTask(&models.Task{ID: 2, Title: "Pack boxes", Description: "Kitchen first", Parent: &models.Task{ID: 1}}),
+		mockIndexer.EXPECT().PutComment(&models.Comment{ID: 10, Task: &models.Task{ID: 1}, Body: "Rent a van"}),
+	)
+
+	assert.NoError(t, indexing.Load(context.TODO(), db, mockIndexer))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
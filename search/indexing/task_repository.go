@@ -0,0 +1,59 @@
+package indexing
+
+import (
+	"context"
+
+	"github.com/dheerajgopi/todo-api/models"
+	"github.com/dheerajgopi/todo-api/search"
+	"github.com/dheerajgopi/todo-api/task"
+)
+
+// taskRepo passes the tasks written to a task repository on to an indexer
+type taskRepo struct {
+	task.Repository
+	indexer search.Indexer
+}
+
+// NewTaskRepository wraps a task repository, so that the indexer is updated whenever a task is stored or removed
+func NewTaskRepository(repo task.Repository, indexer search.Indexer) task.Repository {
+	return &taskRepo{
+		Repository: repo,
+		indexer:    indexer,
+	}
+}
+
+// Create stores a task and adds it to the index
+func (repo *taskRepo) Create(ctx context.Context, task *models.Task) error {
+	if err := repo.Repository.Create(ctx, task); err != nil {
+		return err
+	}
+
+	repo.indexer.PutTask(task)
+
+	return nil
+}
+
+// Update stores the changes of a task and indexes its new text
+func (repo *taskRepo) Update(ctx context.Context, task *models.Task) error {
+	if err := repo.Repository.Update(ctx, task); err != nil {
+		return err
+	}
+
+	repo.indexer.PutTask(task)
+
+	return nil
+}
+
+// Purge permanently removes tasks from the repository and from the index.
+// Tasks in trash are left in the index, since search results are checked against the repository.
+func (repo *taskRepo) Purge(ctx context.Context, ids []int64) error {
+	if err := repo.Repository.Purge(ctx, ids); err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		repo.indexer.RemoveTask(id)
+	}
+
+	return nil
+}
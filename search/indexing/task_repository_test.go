@@ -0,0 +1,99 @@
+package indexing_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dheerajgopi/todo-api/models"
+	"github.com/dheerajgopi/todo-api/search/indexing"
+	searchMock "github.com/dheerajgopi/todo-api/search/mock"
+	taskMock "github.com/dheerajgopi/todo-api/task/mock"
+)
+
+func TestCreateTask(t *testing.T) {
+	ctx := context.TODO()
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	mockRepo := taskMock.NewRepository(mockCtrl)
+	mockIndexer := searchMock.NewIndexer(mockCtrl)
+	repo := indexing.NewTaskRepository(mockRepo, mockIndexer)
+	newTask := &models.Task{Title: "Quarterly report"}
+
+	gomock.InOrder(
+		mockRepo.EXPECT().Create(ctx, newTask).Return(nil),
+		mockIndexer.EXPECT().PutTask(newTask),
+	)
+
+	assert.NoError(t, repo.Create(ctx, newTask))
+}
+
+func TestCreateTaskWithError(t *testing.T) {
+	ctx := context.TODO()
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	mockRepo := taskMock.NewRepository(mockCtrl)
+	repo := indexing.NewTaskRepository(mockRepo, searchMock.NewIndexer(mockCtrl))
+	newTask := &models.Task{Title: "Quarterly report"}
+
+	mockRepo.EXPECT().Create(ctx, newTask).Return(errors.New("db down"))
+
+	assert.EqualError(t, repo.Create(ctx, newTask), "db down")
+}
+
+func TestPurgeTasks(t *testing.T) {
+	ctx := context.TODO()
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	mockRepo := taskMock.NewRepository(mockCtrl)
+	mockIndexer := searchMock.NewIndexer(mockCtrl)
+	repo := indexing.NewTaskRepository(mockRepo, mockIndexer)
+
+	gomock.InOrder(
+		mockRepo.EXPECT().Purge(ctx, []int64{5, 7}).Return(nil),
+		mockIndexer.EXPECT().RemoveTask(int64(5)),
+		mockIndexer.EXPECT().RemoveTask(int64(7)),
+	)
+
+	assert.NoError(t, repo.Purge(ctx, []int64{5, 7}))
+}
+
+func TestDeleteTaskKeepsIndex(t *testing.T) {
+	ctx := context.TODO()
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	mockRepo := taskMock.NewRepository(mockCtrl)
+	repo := indexing.NewTaskRepository(mockRepo, searchMock.NewIndexer(mockCtrl))
+
+	mockRepo.EXPECT().Delete(ctx, int64(5)).Return(nil)
+
+	assert.NoError(t, repo.Delete(ctx, 5))
+}
+
+func TestGetByIDIsPassedThrough(t *testing.T) {
+	ctx := context.TODO()
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	mockRepo := taskMock.NewRepository(mockCtrl)
+	repo := indexing.NewTaskRepository(mockRepo, searchMock.NewIndexer(mockCtrl))
+
+	mockRepo.EXPECT().GetByID(ctx, int64(5)).Return(&models.Task{ID: 5}, nil)
+
+	existingTask, err := repo.GetByID(ctx, 5)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), existingTask.ID)
+}
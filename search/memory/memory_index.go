@@ -0,0 +1,291 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/dheerajgopi/todo-api/models"
+	"github.com/dheerajgopi/todo-api/search"
+)
+
+// maxCommentSnippets is the maximum number of matching comments highlighted for a task
+const maxCommentSnippets = 3
+
+// titleWeight boosts the words of the title over the words of the description and comments
+const titleWeight = 3
+
+// indexedComment is the searchable text of a comment
+type indexedComment struct {
+	id   int64
+	body string
+}
+
+// indexedTask is the searchable text of a task, along with its comments in the order they were written
+type indexedTask struct {
+	id          int64
+	parentID    int64
+	title       string
+	description string
+	comments    []*indexedComment
+	weights     map[string]float64
+}
+
+type memoryIndex struct {
+	mutex         sync.RWMutex
+	tasks         map[int64]*indexedTask
+	commentTaskID map[int64]int64
+	postings      map[string]map[int64]float64
+}
+
+// New returns an empty in-process inverted index, which implements search.Indexer.
+// Visibility is not known to this index, so the hits are not restricted to the tasks visible to the user.
+func New() search.Indexer {
+	return &memoryIndex{
+		tasks:         make(map[int64]*indexedTask),
+		commentTaskID: make(map[int64]int64),
+		postings:      make(map[string]map[int64]float64),
+	}
+}
+
+// Search returns the tasks matching the query, most relevant first.
+// Relevance of a task is the weighted count of its words which start with any of the search terms.
+func (index *memoryIndex) Search(ctx context.Context, query *search.Query) ([]*search.Hit, error) {
+	terms := search.Tokenize(query.Text)
+
+	index.mutex.RLock()
+	defer index.mutex.RUnlock()
+
+	scores := make(map[int64]float64)
+
+	for token, posting := range index.postings {
+		if !search.Matches(token, terms) {
+			continue
+		}
+
+		for taskID, weight := range posting {
+			scores[taskID] += weight
+		}
+	}
+
+	hits := make([]*search.Hit, 0, len(scores))
+
+	for taskID, score := range scores {
+		hits = append(hits, &search.Hit{
+			TaskID: taskID,
+			Score:  score,
+		})
+	}
+
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+
+		return hits[i].TaskID > hits[j].TaskID
+	})
+
+	if query.Offset >= len(hits) {
+		return []*search.Hit{}, nil
+	}
+
+	hits = hits[query.Offset:]
+
+	if len(hits) > query.Limit {
+		hits = hits[:query.Limit]
+	}
+
+	for _, hit := range hits {
+		hit.Snippets = index.tasks[hit.TaskID].snippets(terms)
+	}
+
+	return hits, nil
+}
+
+// PutTask adds a task to the index, or replaces the title and description of an indexed task
+func (index *memoryIndex) PutTask(task *models.Task) {
+	index.mutex.Lock()
+	defer index.mutex.Unlock()
+
+	entry := index.entry(task.ID)
+	entry.title = task.Title
+	entry.description = task.Description
+	entry.parentID = 0
+
+	if task.Parent != nil {
+		entry.parentID = task.Parent.ID
+	}
+
+	index.reindex(entry)
+}
+
+// RemoveTask removes a task from the index, along with its comments and subtasks
+func (index *memoryIndex) RemoveTask(id int64) {
+	index.mutex.Lock()
+	defer index.mutex.Unlock()
+
+	index.remove(id)
+}
+
+// PutComment adds a comment to the index, or replaces the body of an indexed comment
+func (index *memoryIndex) PutComment(comment *models.Comment) {
+	index.mutex.Lock()
+	defer index.mutex.Unlock()
+
+	entry := index.entry(comment.Task.ID)
+	index.commentTaskID[comment.ID] = entry.id
+
+	for _, existingComment := range entry.comments {
+		if existingComment.id == comment.ID {
+			existingComment.body = comment.Body
+			index.reindex(entry)
+			return
+		}
+	}
+
+	entry.comments = append(entry.comments, &indexedComment{
+		id:   comment.ID,
+		body: comment.Body,
+	})
+
+	sort.Slice(entry.comments, func(i, j int) bool {
+		return entry.comments[i].id < entry.comments[j].id
+	})
+
+	index.reindex(entry)
+}
+
+// RemoveComment removes a comment from the index
+func (index *memoryIndex) RemoveComment(id int64) {
+	index.mutex.Lock()
+	defer index.mutex.Unlock()
+
+	taskID, ok := index.commentTaskID[id]
+
+	if !ok {
+		return
+	}
+
+	delete(index.commentTaskID, id)
+
+	entry := index.tasks[taskID]
+
+	for i, comment := range entry.comments {
+		if comment.id == id {
+			entry.comments = append(entry.comments[:i], entry.comments[i+1:]...)
+			break
+		}
+	}
+
+	index.reindex(entry)
+}
+
+// entry returns the indexed task with an id, adding an empty one if it is missing
+func (index *memoryIndex) entry(id int64) *indexedTask {
+	entry, ok := index.tasks[id]
+
+	if !ok {
+		entry = &indexedTask{
+			id:       id,
+			comments: make([]*indexedComment, 0),
+			weights:  make(map[string]float64),
+		}
+
+		index.tasks[id] = entry
+	}
+
+	return entry
+}
+
+// remove drops a task and its subtasks from the index
+func (index *memoryIndex) remove(id int64) {
+	entry, ok := index.tasks[id]
+
+	if !ok {
+		return
+	}
+
+	index.unpost(entry)
+	delete(index.tasks, id)
+
+	for _, comment := range entry.comments {
+		delete(index.commentTaskID, comment.id)
+	}
+
+	for _, subtask := range index.tasks {
+		if subtask.parentID == id {
+			index.remove(subtask.id)
+		}
+	}
+}
+
+// reindex replaces the postings of a task with the weights of the words in its current text
+func (index *memoryIndex) reindex(entry *indexedTask) {
+	index.unpost(entry)
+
+	entry.weights = make(map[string]float64)
+	addWeights(entry.weights, entry.title, titleWeight)
+	addWeights(entry.weights, entry.description, 1)
+
+	for _, comment := range entry.comments {
+		addWeights(entry.weights, comment.body, 1)
+	}
+
+	for token, weight := range entry.weights {
+		posting, ok := index.postings[token]
+
+		if !ok {
+			posting = make(map[int64]float64)
+			index.postings[token] = posting
+		}
+
+		posting[entry.id] = weight
+	}
+}
+
+// unpost removes a task from the postings of its words
+func (index *memoryIndex) unpost(entry *indexedTask) {
+	for token := range entry.weights {
+		posting := index.postings[token]
+		delete(posting, entry.id)
+
+		if len(posting) == 0 {
+			delete(index.postings, token)
+		}
+	}
+}
+
+// addWeights counts the occurrences of the words in a text
+func addWeights(weights map[string]float64, text string, weight float64) {
+	for _, word := range search.Words(text) {
+		weights[word] += weight
+	}
+}
+
+// snippets highlights the matching fields of a task, along with its oldest matching comments
+func (entry *indexedTask) snippets(terms []string) []*search.Snippet {
+	snippets := make([]*search.Snippet, 0)
+
+	if snippet, ok := search.Highlight(entry.title, terms); ok {
+		snippets = append(snippets, &search.Snippet{Field: search.FieldTitle, Text: snippet})
+	}
+
+	if snippet, ok := search.Highlight(entry.description, terms); ok {
+		snippets = append(snippets, &search.Snippet{Field: search.FieldDescription, Text: snippet})
+	}
+
+	commentSnippets := 0
+
+	for _, comment := range entry.comments {
+		if commentSnippets == maxCommentSnippets {
+			break
+		}
+
+		if snippet, ok := search.Highlight(comment.body, terms); ok {
+			snippets = append(snippets, &search.Snippet{Field: search.FieldComment, Text: snippet})
+			commentSnippets++
+		}
+	}
+
+	return snippets
+}
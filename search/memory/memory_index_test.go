@@ -0,0 +1,85 @@
+package memory_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dheerajgopi/todo-api/models"
+	"github.com/dheerajgopi/todo-api/search"
+	"github.com/dheerajgopi/todo-api/search/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearch(t *testing.T) {
+	assert := assert.New(t)
+	index := memory.New()
+
+	index.PutTask(&models.Task{ID: 1, Title: "Groceries", Description: "Milk and paper"})
+	index.PutTask(&models.Task{ID: 2, Title: "Quarterly report", Description: "Collect numbers"})
+	index.PutTask(&models.Task{ID: 3, Title: "Call plumber"})
+	index.PutComment(&models.Comment{ID: 10, Task: &models.Task{ID: 1}, Body: "Also the weekly report"})
+
+	hits, err := index.Search(context.TODO(), &search.Query{Text: "rep", Limit: 10})
+
+	assert.NoError(err)
+	assert.Equal(2, len(hits))
+	assert.Equal(int64(2), hits[0].TaskID)
+	assert.Equal([]*search.Snippet{{Field: "title", Text: "Quarterly <mark>report</mark>"}}, hits[0].Snippets)
+	assert.Equal(int64(1), hits[1].TaskID)
+	assert.Equal([]*search.Snippet{{Field: "comment", Text: "Also the weekly <mark>report</mark>"}}, hits[1].Snippets)
+
+	hits, err = index.Search(context.TODO(), &search.Query{Text: "rep", Offset: 1, Limit: 10})
+
+	assert.NoError(err)
+	assert.Equal(1, len(hits))
+	assert.Equal(int64(1), hits[0].TaskID)
+}
+
+func TestPutTaskReplacesText(t *testing.T) {
+	assert := assert.New(t)
+	index := memory.New()
+
+	index.PutTask(&models.Task{ID: 1, Title: "Quarterly report"})
+	index.PutTask(&models.Task{ID: 1, Title: "Annual budget"})
+
+	hits, _ := index.Search(context.TODO(), &search.Query{Text: "report", Limit: 10})
+
+	assert.Equal(0, len(hits))
+
+	hits, _ = index.Search(context.TODO(), &search.Query{Text: "budget", Limit: 10})
+
+	assert.Equal(1, len(hits))
+}
+
+func TestRemoveTaskRemovesSubtasksAndComments(t *testing.T) {
+	assert := assert.New(t)
+	index := memory.New()
+
+	index.PutTask(&models.Task{ID: 1, Title: "Move house"})
+	index.PutTask(&models.Task{ID: 2, Title: "Pack boxes", Parent: &models.Task{ID: 1}})
+	index.PutComment(&models.Comment{ID: 10, Task: &models.Task{ID: 1}, Body: "Rent a van for the boxes"})
+	index.RemoveTask(1)
+
+	hits, _ := index.Search(context.TODO(), &search.Query{Text: "boxes", Limit: 10})
+
+	assert.Equal(0, len(hits))
+}
+
+func TestRemoveComment(t *testing.T) {
+	assert := assert.New(t)
+	index := memory.New()
+
+	index.PutTask(&models.Task{ID: 1, Title: "Move house"})
+	index.PutComment(&models.Comment{ID: 10, Task: &models.Task{ID: 1}, Body: "Rent a van"})
+	index.PutComment(&models.Comment{ID: 10, Task: &models.Task{ID: 1}, Body: "Rent a truck"})
+
+	hits, _ := index.Search(context.TODO(), &search.Query{Text: "van", Limit: 10})
+
+	assert.Equal(0, len(hits))
+
+	index.RemoveComment(10)
+
+	hits, _ = index.Search(context.TODO(), &search.Query{Text: "truck", Limit: 10})
+
+	assert.Equal(0, len(hits))
+}
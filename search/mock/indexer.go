@@ -0,0 +1,99 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/dheerajgopi/todo-api/search (interfaces: Indexer)
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	models "github.com/dheerajgopi/todo-api/models"
+	search "github.com/dheerajgopi/todo-api/search"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+)
+
+// Indexer is a mock of Indexer interface
+type Indexer struct {
+	ctrl     *gomock.Controller
+	recorder *IndexerMockRecorder
+}
+
+// IndexerMockRecorder is the mock recorder for Indexer
+type IndexerMockRecorder struct {
+	mock *Indexer
+}
+
+// NewIndexer creates a new mock instance
+func NewIndexer(ctrl *gomock.Controller) *Indexer {
+	mock := &Indexer{ctrl: ctrl}
+	mock.recorder = &IndexerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *Indexer) EXPECT() *IndexerMockRecorder {
+	return m.recorder
+}
+
+// PutComment mocks base method
+func (m *Indexer) PutComment(arg0 *models.Comment) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "PutComment", arg0)
+}
+
+// PutComment indicates an expected call of PutComment
+func (mr *IndexerMockRecorder) PutComment(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutComment", reflect.TypeOf((*Indexer)(nil).PutComment), arg0)
+}
+
+// PutTask mocks base method
+func (m *Indexer) PutTask(arg0 *models.Task) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "PutTask", arg0)
+}
+
+// PutTask indicates an expected call of PutTask
+func (mr *IndexerMockRecorder) PutTask(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutTask", reflect.TypeOf((*Indexer)(nil).PutTask), arg0)
+}
+
+// RemoveComment mocks base method
+func (m *Indexer) RemoveComment(arg0 int64) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RemoveComment", arg0)
+}
+
+// RemoveComment indicates an expected call of RemoveComment
+func (mr *IndexerMockRecorder) RemoveComment(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveComment", reflect.TypeOf((*Indexer)(nil).RemoveComment), arg0)
+}
+
+// RemoveTask mocks base method
+func (m *Indexer) RemoveTask(arg0 int64) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RemoveTask", arg0)
+}
+
+// RemoveTask indicates an expected call of RemoveTask
+func (mr *IndexerMockRecorder) RemoveTask(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveTask", reflect.TypeOf((*Indexer)(nil).RemoveTask), arg0)
+}
+
+// Search mocks base method
+func (m *Indexer) Search(arg0 context.Context, arg1 *search.Query) ([]*search.Hit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", arg0, arg1)
+	ret0, _ := ret[0].([]*search.Hit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Search indicates an expected call of Search
+func (mr *IndexerMockRecorder) Search(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*Indexer)(nil).Search), arg0, arg1)
+}
@@ -0,0 +1,176 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/dheerajgopi/todo-api/search"
+)
+
+// maxCommentSnippets is the maximum number of matching comments highlighted for a task
+const maxCommentSnippets = 3
+
+// visibleCondition restricts the tasks to those visible to an user, same as in the task repository
+const visibleCondition = "(t.created_by=? OR t.id IN (SELECT task_id FROM task_collaborator WHERE user_id=?) " +
+	"OR t.parent_id IN (SELECT task_id FROM task_collaborator WHERE user_id=?) " +
+	"OR t.project_id IN (SELECT project_id FROM project_collaborator WHERE user_id=?) " +
+	"OR t.project_id IN (SELECT id FROM project WHERE created_by=?))"
+
+// searchQuery ranks the visible tasks by the relevance of their title and description, along with their comments
+const searchQuery = `SELECT t.id, t.title, COALESCE(t.description, ''), SUM(m.score) AS score FROM (
+		SELECT id AS task_id, MATCH(title, description) AGAINST (? IN BOOLEAN MODE) AS score FROM task
+		WHERE MATCH(title, description) AGAINST (? IN BOOLEAN MODE)
+		UNION ALL
+		SELECT task_id, MATCH(body) AGAINST (? IN BOOLEAN MODE) FROM comment
+		WHERE MATCH(body) AGAINST (? IN BOOLEAN MODE)
+	) m JOIN task t ON t.id=m.task_id
+	WHERE t.deleted_at IS NULL AND ` + visibleCondition + `
+	GROUP BY t.id, t.title, t.description ORDER BY score DESC, t.id DESC LIMIT ? OFFSET ?`
+
+type mySQLIndex struct {
+	DB *sql.DB
+}
+
+// New will return new object which implements search.Index using the full-text indexes of MySQL
+func New(db *sql.DB) search.Index {
+	return &mySQLIndex{
+		DB: db,
+	}
+}
+
+// booleanQuery builds a full-text search in boolean mode, matching any of the terms as a word prefix.
+// Terms are made of letters and digits only, so they can not contain any boolean mode operators.
+func booleanQuery(terms []string) string {
+	words := make([]string, len(terms))
+
+	for i, term := range terms {
+		words[i] = term + "*"
+	}
+
+	return strings.Join(words, " ")
+}
+
+// Search returns the visible tasks matching the query, most relevant first
+func (index *mySQLIndex) Search(ctx context.Context, query *search.Query) ([]*search.Hit, error) {
+	terms := search.Tokenize(query.Text)
+
+	if len(terms) == 0 {
+		return []*search.Hit{}, nil
+	}
+
+	against := booleanQuery(terms)
+	userID := query.UserID
+
+	stmt, err := index.DB.PrepareContext(ctx, searchQuery)
+
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := stmt.QueryContext(ctx, against, against, against, against,
+		userID, userID, userID, userID, userID, query.Limit, query.Offset)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	hits := make([]*search.Hit, 0)
+
+	for rows.Next() {
+		hit := &search.Hit{
+			Snippets: make([]*search.Snippet, 0),
+		}
+
+		title := ""
+		description := ""
+
+		if err = rows.Scan(&hit.TaskID, &title, &description, &hit.Score); err != nil {
+			return nil, err
+		}
+
+		addSnippet(hit, search.FieldTitle, title, terms)
+		addSnippet(hit, search.FieldDescription, description, terms)
+
+		hits = append(hits, hit)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(hits) == 0 {
+		return hits, nil
+	}
+
+	if err = index.loadCommentSnippets(ctx, hits, against, terms); err != nil {
+		return nil, err
+	}
+
+	return hits, nil
+}
+
+// loadCommentSnippets highlights the oldest matching comments of the matched tasks
+func (index *mySQLIndex) loadCommentSnippets(ctx context.Context, hits []*search.Hit, against string, terms []string) error {
+	hitsByTaskID := make(map[int64]*search.Hit)
+	placeholders := make([]string, 0, len(hits))
+	args := make([]interface{}, 0, len(hits)+1)
+
+	for _, hit := range hits {
+		hitsByTaskID[hit.TaskID] = hit
+		placeholders = append(placeholders, "?")
+		args = append(args, hit.TaskID)
+	}
+
+	args = append(args, against)
+
+	query := `SELECT task_id, body FROM comment WHERE task_id IN (` + strings.Join(placeholders, ", ") + `)
+		AND MATCH(body) AGAINST (? IN BOOLEAN MODE) ORDER BY created_at, id`
+
+	stmt, err := index.DB.PrepareContext(ctx, query)
+
+	if err != nil {
+		return err
+	}
+
+	rows, err := stmt.QueryContext(ctx, args...)
+
+	if err != nil {
+		return err
+	}
+
+	defer rows.Close()
+
+	commentSnippets := make(map[int64]int)
+
+	for rows.Next() {
+		taskID := int64(0)
+		body := ""
+
+		if err = rows.Scan(&taskID, &body); err != nil {
+			return err
+		}
+
+		if commentSnippets[taskID] < maxCommentSnippets && addSnippet(hitsByTaskID[taskID], search.FieldComment, body, terms) {
+			commentSnippets[taskID]++
+		}
+	}
+
+	return rows.Err()
+}
+
+// addSnippet highlights a field of a hit if any of its words matches the search terms
+func addSnippet(hit *search.Hit, field string, text string, terms []string) bool {
+	snippet, ok := search.Highlight(text, terms)
+
+	if ok {
+		hit.Snippets = append(hit.Snippets, &search.Snippet{
+			Field: field,
+			Text:  snippet,
+		})
+	}
+
+	return ok
+}
@@ -0,0 +1,74 @@
+package mysql_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/dheerajgopi/todo-api/search"
+	"github.com/dheerajgopi/todo-api/search/mysql"
+	"github.com/stretchr/testify/assert"
+)
+
+const searchQuery = "SELECT t.id, t.title, COALESCE\\(t.description, ''\\), SUM\\(m.score\\) AS score FROM \\(.+\\) m JOIN task t ON t.id=m.task_id\\s+" +
+	"WHERE t.deleted_at IS NULL AND \\(t.created_by=\\? .+\\)\\s+GROUP BY t.id, t.title, t.description ORDER BY score DESC, t.id DESC LIMIT \\? OFFSET \\?"
+
+func TestSearch(t *testing.T) {
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	rows := sqlmock.
+		NewRows([]string{"id", "title", "description", "score"}).
+		AddRow(5, "Quarterly report", "Collect numbers", 2.5).
+		AddRow(3, "Groceries", "", 0.8)
+
+	prep := mock.ExpectPrepare(searchQuery)
+	prep.ExpectQuery().
+		WithArgs("report* draft*", "report* draft*", "report* draft*", "report* draft*", int64(1), int64(1), int64(1), int64(1), int64(1), 20, 0).
+		WillReturnRows(rows)
+
+	commentRows := sqlmock.
+		NewRows([]string{"task_id", "body"}).
+		AddRow(5, "Draft is ready").
+		AddRow(3, "Add the report paper")
+
+	commentPrep := mock.ExpectPrepare("SELECT task_id, body FROM comment WHERE task_id IN \\(\\?, \\?\\)\\s+AND MATCH\\(body\\) AGAINST \\(\\? IN BOOLEAN MODE\\) ORDER BY created_at, id")
+	commentPrep.ExpectQuery().WithArgs(int64(5), int64(3), "report* draft*").WillReturnRows(commentRows)
+
+	index := mysql.New(db)
+
+	hits, err := index.Search(context.TODO(), &search.Query{Text: "Report (draft)", UserID: 1, Limit: 20})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(hits))
+	assert.Equal(t, int64(5), hits[0].TaskID)
+	assert.Equal(t, 2.5, hits[0].Score)
+	assert.Equal(t, 2, len(hits[0].Snippets))
+	assert.Equal(t, &search.Snippet{Field: "title", Text: "Quarterly <mark>report</mark>"}, hits[0].Snippets[0])
+	assert.Equal(t, &search.Snippet{Field: "comment", Text: "<mark>Draft</mark> is ready"}, hits[0].Snippets[1])
+	assert.Equal(t, &search.Snippet{Field: "comment", Text: "Add the <mark>report</mark> paper"}, hits[1].Snippets[0])
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSearchWithoutWords(t *testing.T) {
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	index := mysql.New(db)
+
+	hits, err := index.Search(context.TODO(), &search.Query{Text: "+-*", UserID: 1, Limit: 20})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(hits))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
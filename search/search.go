@@ -0,0 +1,44 @@
+package search
+
+import "github.com/dheerajgopi/todo-api/models"
+
+// Size limits for search results
+const (
+	DefaultLimit = 20
+	MaxLimit     = 50
+)
+
+// Fields of a task which are searched. Comments on a task are searched along with the task.
+const (
+	FieldTitle       = "title"
+	FieldDescription = "description"
+	FieldComment     = "comment"
+)
+
+// Query represents a search for tasks visible to an user.
+// Words of the text are matched against the prefixes of the words in the searched fields.
+type Query struct {
+	Text   string
+	UserID int64
+	Offset int
+	Limit  int
+}
+
+// Snippet is a part of a searched field, with the matching words highlighted
+type Snippet struct {
+	Field string
+	Text  string
+}
+
+// Hit is a task matched by a search, along with its relevance and snippets
+type Hit struct {
+	TaskID   int64
+	Score    float64
+	Snippets []*Snippet
+}
+
+// Result is a task found by a search, along with its snippets
+type Result struct {
+	Task     *models.Task
+	Snippets []*Snippet
+}
@@ -0,0 +1,8 @@
+package search
+
+import "context"
+
+// Service represents search service contract
+type Service interface {
+	Search(ctx context.Context, query *Query) ([]*Result, error)
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+
+	todoErr "github.com/dheerajgopi/todo-api/common/error"
+	"github.com/dheerajgopi/todo-api/search"
+	"github.com/dheerajgopi/todo-api/task"
+)
+
+type searchService struct {
+	index       search.Index
+	taskService task.Service
+}
+
+// New returns a new object implementing search.Service interface.
+// Visibility of the hits returned by the index is checked through the task service.
+func New(index search.Index, taskService task.Service) search.Service {
+	return &searchService{
+		index:       index,
+		taskService: taskService,
+	}
+}
+
+// Search returns the tasks visible to the user which match the query, most relevant first.
+// Hits on tasks which are not visible to the user are skipped, and the index is read further until
+// the result is full or the hits run out.
+func (service *searchService) Search(ctx context.Context, query *search.Query) ([]*search.Result, error) {
+	results := make([]*search.Result, 0)
+
+	if len(search.Tokenize(query.Text)) == 0 {
+		return results, nil
+	}
+
+	indexQuery := *query
+	indexQuery.Offset = 0
+
+	for {
+		hits, err := service.index.Search(ctx, &indexQuery)
+
+		if err != nil {
+			return nil, err
+		}
+
+		for _, hit := range hits {
+			matchedTask, err := service.taskService.GetByID(ctx, hit.TaskID, query.UserID)
+
+			if _, ok := err.(*todoErr.ResourceNotFoundError); ok {
+				continue
+			}
+
+			if err != nil {
+				return nil, err
+			}
+
+			results = append(results, &search.Result{
+				Task:     matchedTask,
+				Snippets: hit.Snippets,
+			})
+
+			if len(results) == query.Limit {
+				return results, nil
+			}
+		}
+
+		if len(hits) < indexQuery.Limit {
+			return results, nil
+		}
+
+		indexQuery.Offset += len(hits)
+	}
+}
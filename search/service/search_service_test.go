@@ -0,0 +1,84 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	todoErr "github.com/dheerajgopi/todo-api/common/error"
+	"github.com/dheerajgopi/todo-api/models"
+	"github.com/dheerajgopi/todo-api/search"
+	searchMock "github.com/dheerajgopi/todo-api/search/mock"
+	"github.com/dheerajgopi/todo-api/search/service"
+	taskMock "github.com/dheerajgopi/todo-api/task/mock"
+)
+
+func TestSearch(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	mockIndex := searchMock.NewIndexer(mockCtrl)
+	mockTaskService := taskMock.NewService(mockCtrl)
+	searchService := service.New(mockIndex, mockTaskService)
+	snippets := []*search.Snippet{{Field: "title", Text: "<mark>report</mark>"}}
+
+	gomock.InOrder(
+		mockIndex.EXPECT().Search(ctx, &search.Query{Text: "report", UserID: 1, Limit: 2}).
+			Return([]*search.Hit{{TaskID: 5, Snippets: snippets}, {TaskID: 6}}, nil),
+		mockTaskService.EXPECT().GetByID(ctx, int64(5), int64(1)).Return(&models.Task{ID: 5}, nil),
+		mockTaskService.EXPECT().GetByID(ctx, int64(6), int64(1)).Return(nil, &todoErr.ResourceNotFoundError{Resource: "task"}),
+		mockIndex.EXPECT().Search(ctx, &search.Query{Text: "report", UserID: 1, Offset: 2, Limit: 2}).
+			Return([]*search.Hit{{TaskID: 7}}, nil),
+		mockTaskService.EXPECT().GetByID(ctx, int64(7), int64(1)).Return(&models.Task{ID: 7}, nil),
+	)
+
+	results, err := searchService.Search(ctx, &search.Query{Text: "report", UserID: 1, Limit: 2})
+
+	assert.NoError(err)
+	assert.Equal(2, len(results))
+	assert.Equal(int64(5), results[0].Task.ID)
+	assert.Equal(snippets, results[0].Snippets)
+	assert.Equal(int64(7), results[1].Task.ID)
+}
+
+func TestSearchStopsWhenHitsRunOut(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	mockIndex := searchMock.NewIndexer(mockCtrl)
+	mockTaskService := taskMock.NewService(mockCtrl)
+	searchService := service.New(mockIndex, mockTaskService)
+
+	gomock.InOrder(
+		mockIndex.EXPECT().Search(ctx, gomock.Any()).Return([]*search.Hit{{TaskID: 6}}, nil),
+		mockTaskService.EXPECT().GetByID(ctx, int64(6), int64(1)).Return(nil, &todoErr.ResourceNotFoundError{Resource: "task"}),
+	)
+
+	results, err := searchService.Search(ctx, &search.Query{Text: "report", UserID: 1, Limit: 2})
+
+	assert.NoError(err)
+	assert.Equal(0, len(results))
+}
+
+func TestSearchWithoutWords(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	searchService := service.New(searchMock.NewIndexer(mockCtrl), taskMock.NewService(mockCtrl))
+
+	results, err := searchService.Search(ctx, &search.Query{Text: "!!", UserID: 1, Limit: 2})
+
+	assert.NoError(err)
+	assert.Equal(0, len(results))
+}
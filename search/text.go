@@ -0,0 +1,171 @@
+package search
+
+import (
+	"html"
+	"strings"
+	"unicode"
+)
+
+// Snippets are cut to snippetLength characters, starting snippetLead characters before the first match
+const (
+	snippetLength = 160
+	snippetLead   = 40
+)
+
+// Tags wrapped around the highlighted words in snippets
+const (
+	HighlightStart = "<mark>"
+	HighlightEnd   = "</mark>"
+)
+
+// ellipsis marks the ends of a snippet, where the text is cut
+const ellipsis = "…"
+
+// span is the position of a word in a text, in characters
+type span struct {
+	start int
+	end   int
+}
+
+// isWordRune reports whether a character is part of a word
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// words finds the positions of the words in a text
+func words(runes []rune) []span {
+	spans := make([]span, 0)
+	start := -1
+
+	for i, r := range runes {
+		if isWordRune(r) {
+			if start < 0 {
+				start = i
+			}
+		} else if start >= 0 {
+			spans = append(spans, span{start, i})
+			start = -1
+		}
+	}
+
+	if start >= 0 {
+		spans = append(spans, span{start, len(runes)})
+	}
+
+	return spans
+}
+
+// Words splits a text into lower case words, leaving out punctuation
+func Words(text string) []string {
+	runes := []rune(text)
+	spans := words(runes)
+	tokens := make([]string, len(spans))
+
+	for i, word := range spans {
+		tokens[i] = strings.ToLower(string(runes[word.start:word.end]))
+	}
+
+	return tokens
+}
+
+// Tokenize splits a text into lower case words, leaving out punctuation and repeated words
+func Tokenize(text string) []string {
+	seen := make(map[string]bool)
+	tokens := make([]string, 0)
+
+	for _, token := range Words(text) {
+		if !seen[token] {
+			seen[token] = true
+			tokens = append(tokens, token)
+		}
+	}
+
+	return tokens
+}
+
+// Matches reports whether a word starts with any of the search terms
+func Matches(word string, terms []string) bool {
+	for _, term := range terms {
+		if strings.HasPrefix(word, term) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Highlight cuts a snippet around the first word of a text matching the search terms, and wraps the matching words
+// in HighlightStart and HighlightEnd. Rest of the snippet is HTML escaped. False is returned if no word matches.
+func Highlight(text string, terms []string) (string, bool) {
+	runes := []rune(text)
+	matched := make([]span, 0)
+
+	for _, word := range words(runes) {
+		if Matches(strings.ToLower(string(runes[word.start:word.end])), terms) {
+			matched = append(matched, word)
+		}
+	}
+
+	if len(matched) == 0 {
+		return "", false
+	}
+
+	start := 0
+
+	if matched[0].start > snippetLead {
+		start = matched[0].start - snippetLead
+
+		// do not start the snippet in the middle of a word
+		for start < matched[0].start && (isWordRune(runes[start-1]) || !isWordRune(runes[start])) {
+			start++
+		}
+	}
+
+	end := len(runes)
+
+	if end-start > snippetLength {
+		end = start + snippetLength
+
+		for end > matched[0].end && isWordRune(runes[end]) {
+			end--
+		}
+
+		for end > matched[0].end && unicode.IsSpace(runes[end-1]) {
+			end--
+		}
+	}
+
+	var snippet strings.Builder
+
+	if start > 0 {
+		snippet.WriteString(ellipsis)
+	}
+
+	position := start
+
+	for _, word := range matched {
+		if word.start >= end {
+			break
+		}
+
+		wordEnd := word.end
+
+		if wordEnd > end {
+			wordEnd = end
+		}
+
+		snippet.WriteString(html.EscapeString(string(runes[position:word.start])))
+		snippet.WriteString(HighlightStart)
+		snippet.WriteString(html.EscapeString(string(runes[word.start:wordEnd])))
+		snippet.WriteString(HighlightEnd)
+		position = wordEnd
+	}
+
+	snippet.WriteString(html.EscapeString(string(runes[position:end])))
+
+	if end < len(runes) {
+		snippet.WriteString(ellipsis)
+	}
+
+	return snippet.String(), true
+}
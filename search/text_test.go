@@ -0,0 +1,39 @@
+package search_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dheerajgopi/todo-api/search"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenize(t *testing.T) {
+	assert.Equal(t, []string{"fix", "login", "bug", "on", "ios", "12"}, search.Tokenize("Fix login-bug on iOS 12 (login)!"))
+	assert.Equal(t, []string{}, search.Tokenize(" +-* "))
+}
+
+func TestHighlight(t *testing.T) {
+	snippet, ok := search.Highlight("Review <b>report</b> & reports", []string{"report"})
+
+	assert.True(t, ok)
+	assert.Equal(t, "Review &lt;b&gt;<mark>report</mark>&lt;/b&gt; &amp; <mark>reports</mark>", snippet)
+}
+
+func TestHighlightWithoutMatch(t *testing.T) {
+	_, ok := search.Highlight("Review report", []string{"invoice"})
+
+	assert.False(t, ok)
+}
+
+func TestHighlightLongText(t *testing.T) {
+	text := strings.Repeat("lorem ipsum ", 10) + "invoice " + strings.Repeat("dolor sit ", 30)
+
+	snippet, ok := search.Highlight(text, []string{"inv"})
+
+	assert.True(t, ok)
+	assert.True(t, strings.HasPrefix(snippet, "…lorem ipsum lorem ipsum"))
+	assert.Contains(t, snippet, "<mark>invoice</mark> dolor")
+	assert.True(t, strings.HasSuffix(snippet, "sit…") || strings.HasSuffix(snippet, "dolor…"))
+	assert.True(t, len([]rune(snippet)) <= 160+len("<mark></mark>")+2)
+}
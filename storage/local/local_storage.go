@@ -0,0 +1,103 @@
+package local
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/dheerajgopi/todo-api/storage"
+)
+
+type localStorage struct {
+	directory string
+}
+
+// New will return new object which implements storage.Storage, keeping the blobs as files under the given directory
+func New(directory string) storage.Storage {
+	return &localStorage{
+		directory: directory,
+	}
+}
+
+// path returns the file path of a blob. Keys escaping the storage directory are rejected.
+func (store *localStorage) path(key string) (string, error) {
+	cleanKey := filepath.Clean("/" + key)
+
+	if cleanKey == "/" || strings.Contains(key, "..") {
+		return "", errors.New("invalid blob key")
+	}
+
+	return filepath.Join(store.directory, filepath.FromSlash(cleanKey)), nil
+}
+
+// Put will store a blob. Content is written to a temporary file first, so that a failed write never leaves a partial blob.
+func (store *localStorage) Put(ctx context.Context, key string, content io.Reader, size int64, contentType string) error {
+	path, err := store.path(key)
+
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	file, err := ioutil.TempFile(filepath.Dir(path), ".upload-")
+
+	if err != nil {
+		return err
+	}
+
+	if _, err = io.Copy(file, content); err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return err
+	}
+
+	if err = file.Close(); err != nil {
+		os.Remove(file.Name())
+		return err
+	}
+
+	return os.Rename(file.Name(), path)
+}
+
+// Get will open a stored blob for reading
+func (store *localStorage) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	path, err := store.path(key)
+
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+
+	if os.IsNotExist(err) {
+		return nil, storage.ErrNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return file, nil
+}
+
+// Delete will remove a stored blob. Missing blobs are ignored.
+func (store *localStorage) Delete(ctx context.Context, key string) error {
+	path, err := store.path(key)
+
+	if err != nil {
+		return err
+	}
+
+	if err = os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
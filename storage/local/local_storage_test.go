@@ -0,0 +1,52 @@
+package local_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/dheerajgopi/todo-api/storage"
+	"github.com/dheerajgopi/todo-api/storage/local"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPutGetDelete(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.TODO()
+	directory, err := ioutil.TempDir("", "attachments")
+
+	if err != nil {
+		t.Fatalf("Unexpected error while creating temporary directory: %s", err)
+	}
+
+	defer os.RemoveAll(directory)
+
+	store := local.New(directory)
+
+	assert.NoError(store.Put(ctx, "tasks/1/abc", strings.NewReader("hello"), 5, "text/plain"))
+
+	body, err := store.Get(ctx, "tasks/1/abc")
+
+	assert.NoError(err)
+
+	content, _ := ioutil.ReadAll(body)
+	body.Close()
+
+	assert.Equal("hello", string(content))
+	assert.NoError(store.Delete(ctx, "tasks/1/abc"))
+	assert.NoError(store.Delete(ctx, "tasks/1/abc"))
+
+	_, err = store.Get(ctx, "tasks/1/abc")
+
+	assert.Equal(storage.ErrNotFound, err)
+}
+
+func TestPutWithKeyOutsideDirectory(t *testing.T) {
+	store := local.New(os.TempDir())
+
+	err := store.Put(context.TODO(), "../escape", strings.NewReader("hello"), 5, "text/plain")
+
+	assert.Error(t, err)
+}
@@ -0,0 +1,143 @@
+package s3
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/dheerajgopi/todo-api/config"
+	"github.com/dheerajgopi/todo-api/storage"
+)
+
+type s3Storage struct {
+	setting *config.S3StorageSetting
+	signer  *Signer
+	client  *http.Client
+}
+
+// New will return new object which implements storage.Storage, keeping the blobs in a S3 compatible bucket
+func New(setting *config.S3StorageSetting) storage.Storage {
+	return &s3Storage{
+		setting: setting,
+		signer: &Signer{
+			AccessKey: setting.AccessKey,
+			SecretKey: setting.SecretKey,
+			Region:    setting.Region,
+		},
+		client: &http.Client{},
+	}
+}
+
+// objectURL returns the URL of a blob, with the bucket either in the path or in the host name
+func (store *s3Storage) objectURL(key string) (string, error) {
+	endpoint, err := url.Parse(strings.TrimSuffix(store.setting.Endpoint, "/"))
+
+	if err != nil {
+		return "", err
+	}
+
+	path := "/" + uriEncode(key, false)
+
+	if store.setting.PathStyle {
+		path = "/" + uriEncode(store.setting.Bucket, true) + path
+	} else {
+		endpoint.Host = store.setting.Bucket + "." + endpoint.Host
+	}
+
+	return endpoint.Scheme + "://" + endpoint.Host + endpoint.Path + path, nil
+}
+
+// do signs and sends a request for a blob
+func (store *s3Storage) do(ctx context.Context, method string, key string, body io.Reader, payloadHash string,
+	prepare func(req *http.Request)) (*http.Response, error) {
+	objectURL, err := store.objectURL(key)
+
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(method, objectURL, body)
+
+	if err != nil {
+		return nil, err
+	}
+
+	req = req.WithContext(ctx)
+
+	if prepare != nil {
+		prepare(req)
+	}
+
+	store.signer.Sign(req, payloadHash, time.Now())
+
+	return store.client.Do(req)
+}
+
+// Put will upload a blob. Payload is streamed without hashing, so that the content need not be read twice.
+func (store *s3Storage) Put(ctx context.Context, key string, content io.Reader, size int64, contentType string) error {
+	res, err := store.do(ctx, http.MethodPut, key, content, UnsignedPayload, func(req *http.Request) {
+		req.ContentLength = size
+		req.Header.Set("Content-Type", contentType)
+	})
+
+	if err != nil {
+		return err
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return responseError(res)
+	}
+
+	return nil
+}
+
+// Get will download a blob. Caller should close the returned body.
+func (store *s3Storage) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	res, err := store.do(ctx, http.MethodGet, key, nil, EmptyPayloadHash, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	switch res.StatusCode {
+	case http.StatusOK:
+		return res.Body, nil
+	case http.StatusNotFound:
+		res.Body.Close()
+		return nil, storage.ErrNotFound
+	default:
+		defer res.Body.Close()
+		return nil, responseError(res)
+	}
+}
+
+// Delete will remove a blob. S3 does not report missing blobs while deleting.
+func (store *s3Storage) Delete(ctx context.Context, key string) error {
+	res, err := store.do(ctx, http.MethodDelete, key, nil, EmptyPayloadHash, nil)
+
+	if err != nil {
+		return err
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusNoContent && res.StatusCode != http.StatusOK && res.StatusCode != http.StatusNotFound {
+		return responseError(res)
+	}
+
+	return nil
+}
+
+// responseError builds an error from an unexpected response, including the start of the error document
+func responseError(res *http.Response) error {
+	body, _ := ioutil.ReadAll(io.LimitReader(res.Body, 512))
+
+	return fmt.Errorf("s3 responded with status %d: %s", res.StatusCode, strings.TrimSpace(string(body)))
+}
@@ -0,0 +1,83 @@
+package http
+
+import (
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	todoErr "github.com/dheerajgopi/todo-api/common/error"
+)
+
+// colorPattern matches hex color codes like #1a2b3c
+var colorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// TagData represents json structure for tag
+type TagData struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name"`
+	Color     string    `json:"color,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// CreateTagRequest represents request body for POST /tags API
+type CreateTagRequest struct {
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+// ValidateAndBuild validates the request body for POST /tags API
+func (body *CreateTagRequest) ValidateAndBuild() []*todoErr.APIErrorBody {
+	name, color, validationErrors := validateTag(body.Name, body.Color)
+
+	body.Name = name
+	body.Color = color
+
+	return validationErrors
+}
+
+// UpdateTagRequest represents request body for PUT /tags/{id} API
+type UpdateTagRequest struct {
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+// ValidateAndBuild validates the request body for PUT /tags/{id} API
+func (body *UpdateTagRequest) ValidateAndBuild() []*todoErr.APIErrorBody {
+	name, color, validationErrors := validateTag(body.Name, body.Color)
+
+	body.Name = name
+	body.Color = color
+
+	return validationErrors
+}
+
+// validateTag checks the name and the optional hex color of a tag, and returns their trimmed values
+func validateTag(name string, color string) (string, string, []*todoErr.APIErrorBody) {
+	trimmedName := strings.TrimSpace(name)
+	trimmedColor := strings.TrimSpace(color)
+
+	validationErrors := make([]*todoErr.APIErrorBody, 0)
+
+	if trimmedName == "" {
+		validationErrors = append(validationErrors, &todoErr.APIErrorBody{
+			Message: "Non-empty value is required",
+			Target:  "name",
+		})
+	} else if utf8.RuneCountInString(trimmedName) > 64 {
+		validationErrors = append(validationErrors, &todoErr.APIErrorBody{
+			Message: "Length should be 64 or less",
+			Target:  "name",
+		})
+	}
+
+	if trimmedColor != "" && !colorPattern.MatchString(trimmedColor) {
+		validationErrors = append(validationErrors, &todoErr.APIErrorBody{
+			Message: "Invalid value",
+			Target:  "color",
+		})
+	}
+
+	return trimmedName, trimmedColor, validationErrors
+}
@@ -0,0 +1,34 @@
+package http
+
+import "github.com/dheerajgopi/todo-api/models"
+
+// CreateTagResponse represents response for POST /tags API
+type CreateTagResponse struct {
+	Tag *TagData `json:"tag"`
+}
+
+// ListTagResponse represents response for GET /tags API
+type ListTagResponse struct {
+	Tags []*TagData `json:"tags"`
+}
+
+// GetTagResponse represents response for GET /tags/{id} API
+type GetTagResponse struct {
+	Tag *TagData `json:"tag"`
+}
+
+// UpdateTagResponse represents response for PUT /tags/{id} API
+type UpdateTagResponse struct {
+	Tag *TagData `json:"tag"`
+}
+
+// newTagData builds the json structure of a tag
+func newTagData(tag *models.Tag) *TagData {
+	return &TagData{
+		ID:        tag.ID,
+		Name:      tag.Name,
+		Color:     tag.Color,
+		CreatedAt: tag.CreatedAt,
+		UpdatedAt: tag.UpdatedAt,
+	}
+}
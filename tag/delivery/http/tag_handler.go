@@ -0,0 +1,234 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/dheerajgopi/todo-api/common"
+	todoErr "github.com/dheerajgopi/todo-api/common/error"
+	"github.com/dheerajgopi/todo-api/common/middlewares"
+	"github.com/dheerajgopi/todo-api/models"
+	"github.com/dheerajgopi/todo-api/tag"
+	"github.com/gorilla/mux"
+)
+
+// TagHandler represents HTTP handler for tags
+type TagHandler struct {
+	TagService tag.Service
+	App        *common.App
+}
+
+// New creates new HTTP handler for tag
+func New(router *mux.Router, service tag.Service, app *common.App) {
+	handler := &TagHandler{
+		TagService: service,
+		App:        app,
+	}
+
+	jwtMiddleware := middlewares.JwtValidator(app)
+
+	router.HandleFunc("/tags", app.CreateHandler(jwtMiddleware(handler.Create))).Methods("POST")
+	router.HandleFunc("/tags", app.CreateHandler(jwtMiddleware(handler.List))).Methods("GET")
+	router.HandleFunc("/tags/{id:[0-9]+}", app.CreateHandler(jwtMiddleware(handler.Get))).Methods("GET")
+	router.HandleFunc("/tags/{id:[0-9]+}", app.CreateHandler(jwtMiddleware(handler.Update))).Methods("PUT")
+	router.HandleFunc("/tags/{id:[0-9]+}", app.CreateHandler(jwtMiddleware(handler.Delete))).Methods("DELETE")
+}
+
+// Create will store new tag
+func (handler *TagHandler) Create(res http.ResponseWriter, req *http.Request, reqCtx *common.RequestContext) (int, interface{}, *todoErr.APIError) {
+	defer req.Body.Close()
+
+	decoder := json.NewDecoder(req.Body)
+	var createTagReqBody CreateTagRequest
+	err := decoder.Decode(&createTagReqBody)
+
+	if err != nil {
+		apiError := todoErr.NewAPIError("", &todoErr.APIErrorBody{
+			Message: "Invalid request body",
+		})
+
+		return http.StatusBadRequest, nil, apiError
+	}
+
+	validationErrors := createTagReqBody.ValidateAndBuild()
+
+	if len(validationErrors) > 0 {
+		apiError := todoErr.NewAPIError("", validationErrors...)
+
+		return http.StatusBadRequest, nil, apiError
+	}
+
+	now := time.Now()
+
+	newTag := &models.Tag{
+		Name:  createTagReqBody.Name,
+		Color: createTagReqBody.Color,
+		CreatedBy: &models.User{
+			ID: reqCtx.UserID,
+		},
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if err = handler.TagService.Create(context.TODO(), newTag); err != nil {
+		return tagServiceError(err)
+	}
+
+	responseData := &CreateTagResponse{
+		Tag: newTagData(newTag),
+	}
+
+	return http.StatusCreated, responseData, nil
+}
+
+// List will return all tags of the user
+func (handler *TagHandler) List(res http.ResponseWriter, req *http.Request, reqCtx *common.RequestContext) (int, interface{}, *todoErr.APIError) {
+	tags, err := handler.TagService.List(context.TODO(), reqCtx.UserID)
+
+	if err != nil {
+		return tagServiceError(err)
+	}
+
+	tagList := make([]*TagData, 0)
+
+	for _, tag := range tags {
+		tagList = append(tagList, newTagData(tag))
+	}
+
+	responseData := &ListTagResponse{
+		Tags: tagList,
+	}
+
+	return http.StatusOK, responseData, nil
+}
+
+// Get will return a single tag
+func (handler *TagHandler) Get(res http.ResponseWriter, req *http.Request, reqCtx *common.RequestContext) (int, interface{}, *todoErr.APIError) {
+	tagID, apiError := parseTagID(req)
+
+	if apiError != nil {
+		return http.StatusBadRequest, nil, apiError
+	}
+
+	existingTag, err := handler.TagService.GetByID(context.TODO(), tagID, reqCtx.UserID)
+
+	if err != nil {
+		return tagServiceError(err)
+	}
+
+	responseData := &GetTagResponse{
+		Tag: newTagData(existingTag),
+	}
+
+	return http.StatusOK, responseData, nil
+}
+
+// Update will rename or recolor a tag
+func (handler *TagHandler) Update(res http.ResponseWriter, req *http.Request, reqCtx *common.RequestContext) (int, interface{}, *todoErr.APIError) {
+	defer req.Body.Close()
+
+	tagID, apiError := parseTagID(req)
+
+	if apiError != nil {
+		return http.StatusBadRequest, nil, apiError
+	}
+
+	decoder := json.NewDecoder(req.Body)
+	var updateTagReqBody UpdateTagRequest
+	err := decoder.Decode(&updateTagReqBody)
+
+	if err != nil {
+		apiError := todoErr.NewAPIError("", &todoErr.APIErrorBody{
+			Message: "Invalid request body",
+		})
+
+		return http.StatusBadRequest, nil, apiError
+	}
+
+	validationErrors := updateTagReqBody.ValidateAndBuild()
+
+	if len(validationErrors) > 0 {
+		apiError := todoErr.NewAPIError("", validationErrors...)
+
+		return http.StatusBadRequest, nil, apiError
+	}
+
+	updatedTag := &models.Tag{
+		ID:        tagID,
+		Name:      updateTagReqBody.Name,
+		Color:     updateTagReqBody.Color,
+		UpdatedAt: time.Now(),
+	}
+
+	if err = handler.TagService.Update(context.TODO(), updatedTag, reqCtx.UserID); err != nil {
+		return tagServiceError(err)
+	}
+
+	responseData := &UpdateTagResponse{
+		Tag: newTagData(updatedTag),
+	}
+
+	return http.StatusOK, responseData, nil
+}
+
+// Delete will remove a tag and detach it from all tasks
+func (handler *TagHandler) Delete(res http.ResponseWriter, req *http.Request, reqCtx *common.RequestContext) (int, interface{}, *todoErr.APIError) {
+	tagID, apiError := parseTagID(req)
+
+	if apiError != nil {
+		return http.StatusBadRequest, nil, apiError
+	}
+
+	if err := handler.TagService.Delete(context.TODO(), tagID, reqCtx.UserID); err != nil {
+		return tagServiceError(err)
+	}
+
+	return http.StatusOK, nil, nil
+}
+
+// parseTagID reads the tag id from the request path
+func parseTagID(req *http.Request) (int64, *todoErr.APIError) {
+	tagID, err := strconv.ParseInt(mux.Vars(req)["id"], 10, 64)
+
+	if err != nil {
+		return 0, todoErr.NewAPIError(err.Error(), &todoErr.APIErrorBody{
+			Message: "Invalid value",
+			Target:  "id",
+		})
+	}
+
+	return tagID, nil
+}
+
+// tagServiceError maps errors returned by the tag service to the API response
+func tagServiceError(err error) (int, interface{}, *todoErr.APIError) {
+	switch err.(type) {
+	case *todoErr.ResourceNotFoundError:
+		resourceNotFoundErr, _ := err.(*todoErr.ResourceNotFoundError)
+
+		apiError := todoErr.NewAPIError(resourceNotFoundErr.Error(), &todoErr.APIErrorBody{
+			Message: "Not found",
+			Target:  resourceNotFoundErr.Resource,
+		})
+
+		return http.StatusNotFound, nil, apiError
+	case *todoErr.DataConflictError:
+		dataConflictErr, _ := err.(*todoErr.DataConflictError)
+
+		apiError := todoErr.NewAPIError(dataConflictErr.Error(), &todoErr.APIErrorBody{
+			Message: "Conflicting data",
+			Target:  dataConflictErr.Field,
+		})
+
+		return http.StatusConflict, nil, apiError
+	default:
+		apiError := todoErr.NewAPIError(err.Error(), &todoErr.APIErrorBody{
+			Message: "Internal server error",
+		})
+
+		return http.StatusInternalServerError, nil, apiError
+	}
+}
@@ -0,0 +1,193 @@
+package http_test
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dheerajgopi/todo-api/common"
+	_errors "github.com/dheerajgopi/todo-api/common/error"
+	"github.com/dheerajgopi/todo-api/config"
+	"github.com/dheerajgopi/todo-api/models"
+	"github.com/dheerajgopi/todo-api/tag"
+	_tagHandler "github.com/dheerajgopi/todo-api/tag/delivery/http"
+	mock "github.com/dheerajgopi/todo-api/tag/mock"
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateWithInvalidData(t *testing.T) {
+	reqBody := &_tagHandler.CreateTagRequest{
+		Name:  " ",
+		Color: "red",
+	}
+
+	payload, _ := json.Marshal(reqBody)
+
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+	req := httptest.NewRequest("POST", "/tags", strings.NewReader(string(payload)))
+
+	status, data, err := handler.Create(httptest.NewRecorder(), req, reqCtx)
+
+	assert.Equal(400, status)
+	assert.Nil(data)
+	assert.Equal(2, len(err.Body))
+	assert.Equal("name", err.Body[0].Target)
+	assert.Equal("color", err.Body[1].Target)
+}
+
+func TestCreate(t *testing.T) {
+	reqBody := &_tagHandler.CreateTagRequest{
+		Name:  " work ",
+		Color: "#FF0000",
+	}
+
+	payload, _ := json.Marshal(reqBody)
+
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+	req := httptest.NewRequest("POST", "/tags", strings.NewReader(string(payload)))
+
+	mockService.
+		EXPECT().
+		Create(gomock.Any(), gomock.Any()).
+		Return(nil).
+		Times(1)
+
+	status, data, err := handler.Create(httptest.NewRecorder(), req, reqCtx)
+
+	responseData := data.(*_tagHandler.CreateTagResponse)
+
+	assert.Equal(201, status)
+	assert.Nil(err)
+	assert.Equal("work", responseData.Tag.Name)
+	assert.Equal("#FF0000", responseData.Tag.Color)
+}
+
+func TestCreateWithDuplicateName(t *testing.T) {
+	reqBody := &_tagHandler.CreateTagRequest{
+		Name: "work",
+	}
+
+	payload, _ := json.Marshal(reqBody)
+
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+	req := httptest.NewRequest("POST", "/tags", strings.NewReader(string(payload)))
+
+	mockService.
+		EXPECT().
+		Create(gomock.Any(), gomock.Any()).
+		Return(&_errors.DataConflictError{Resource: "tag", Field: "name"}).
+		Times(1)
+
+	status, data, err := handler.Create(httptest.NewRecorder(), req, reqCtx)
+
+	assert.Equal(409, status)
+	assert.Nil(data)
+	assert.Equal("Conflicting data", err.Body[0].Message)
+	assert.Equal("name", err.Body[0].Target)
+}
+
+func TestList(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+	req := httptest.NewRequest("GET", "/tags", nil)
+
+	mockService.
+		EXPECT().
+		List(gomock.Any(), reqCtx.UserID).
+		Return([]*models.Tag{{ID: 1, Name: "work"}}, nil).
+		Times(1)
+
+	status, data, err := handler.List(httptest.NewRecorder(), req, reqCtx)
+
+	responseData := data.(*_tagHandler.ListTagResponse)
+
+	assert.Equal(200, status)
+	assert.Nil(err)
+	assert.Equal(1, len(responseData.Tags))
+}
+
+func TestGetForMissingTag(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+	req := mux.SetURLVars(httptest.NewRequest("GET", "/tags/1", nil), map[string]string{"id": "1"})
+
+	mockService.
+		EXPECT().
+		GetByID(gomock.Any(), int64(1), reqCtx.UserID).
+		Return(nil, &_errors.ResourceNotFoundError{Resource: "tag"}).
+		Times(1)
+
+	status, data, err := handler.Get(httptest.NewRecorder(), req, reqCtx)
+
+	assert.Equal(404, status)
+	assert.Nil(data)
+	assert.Equal("tag", err.Body[0].Target)
+}
+
+func TestDelete(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+	req := mux.SetURLVars(httptest.NewRequest("DELETE", "/tags/1", nil), map[string]string{"id": "1"})
+
+	mockService.
+		EXPECT().
+		Delete(gomock.Any(), int64(1), reqCtx.UserID).
+		Return(nil).
+		Times(1)
+
+	status, data, err := handler.Delete(httptest.NewRecorder(), req, reqCtx)
+
+	assert.Equal(200, status)
+	assert.Nil(data)
+	assert.Nil(err)
+}
+
+func setupHandler(mockService tag.Service) *_tagHandler.TagHandler {
+	app := &common.App{
+		Logger: logrus.New(),
+		Config: &config.Config{},
+	}
+
+	handler := &_tagHandler.TagHandler{
+		TagService: mockService,
+		App:        app,
+	}
+
+	return handler
+}
+
+func setupRequestContext(app *common.App) *common.RequestContext {
+	reqCtx := &common.RequestContext{
+		RequestID: "dummyRequestID",
+		LogEntry: app.Logger.WithFields(
+			logrus.Fields{},
+		),
+	}
+
+	return reqCtx
+}
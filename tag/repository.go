@@ -0,0 +1,18 @@
+package tag
+
+import (
+	"context"
+
+	"github.com/dheerajgopi/todo-api/models"
+)
+
+// Repository represents tag's repository contract
+type Repository interface {
+	GetByID(ctx context.Context, id int64) (*models.Tag, error)
+	GetByIDs(ctx context.Context, ids []int64) ([]*models.Tag, error)
+	GetByName(ctx context.Context, userID int64, name string) (*models.Tag, error)
+	GetAllByUserID(ctx context.Context, userID int64) ([]*models.Tag, error)
+	Create(ctx context.Context, tag *models.Tag) error
+	Update(ctx context.Context, tag *models.Tag) error
+	Delete(ctx context.Context, id int64) error
+}
@@ -0,0 +1,236 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/dheerajgopi/todo-api/models"
+	"github.com/dheerajgopi/todo-api/tag"
+)
+
+type mySQLTagRepo struct {
+	DB *sql.DB
+}
+
+// New will return new object which implements tag.Repository
+func New(db *sql.DB) tag.Repository {
+	return &mySQLTagRepo{
+		DB: db,
+	}
+}
+
+// rowScanner is implemented by both sql.Row and sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanTag reads a tag from a row having id, name, color, created_by, created_at and updated_at columns
+func scanTag(row rowScanner) (*models.Tag, error) {
+	tag := &models.Tag{}
+	userID := int64(0)
+	color := sql.NullString{}
+
+	err := row.Scan(
+		&tag.ID,
+		&tag.Name,
+		&color,
+		&userID,
+		&tag.CreatedAt,
+		&tag.UpdatedAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	tag.Color = color.String
+	tag.CreatedBy = &models.User{
+		ID: userID,
+	}
+
+	return tag, nil
+}
+
+func (repo *mySQLTagRepo) getOne(ctx context.Context, query string, args ...interface{}) (*models.Tag, error) {
+	stmt, err := repo.DB.PrepareContext(ctx, query)
+
+	if err != nil {
+		return nil, err
+	}
+
+	tag, err := scanTag(stmt.QueryRowContext(ctx, args...))
+
+	switch err {
+	case nil:
+	case sql.ErrNoRows:
+		return nil, nil
+	default:
+		return nil, err
+	}
+
+	return tag, nil
+}
+
+func (repo *mySQLTagRepo) getAll(ctx context.Context, query string, args ...interface{}) ([]*models.Tag, error) {
+	stmt, err := repo.DB.PrepareContext(ctx, query)
+
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := stmt.QueryContext(ctx, args...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	tags := make([]*models.Tag, 0)
+
+	for rows.Next() {
+		tag, err := scanTag(rows)
+
+		if err != nil {
+			return nil, err
+		}
+
+		tags = append(tags, tag)
+	}
+
+	err = rows.Err()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return tags, nil
+}
+
+// GetByID will return tag with the given id
+func (repo *mySQLTagRepo) GetByID(ctx context.Context, id int64) (*models.Tag, error) {
+	query := `SELECT id, name, color, created_by, created_at, updated_at FROM tag WHERE id=?`
+	return repo.getOne(ctx, query, id)
+}
+
+// GetByIDs will return the existing tags among the given ids
+func (repo *mySQLTagRepo) GetByIDs(ctx context.Context, ids []int64) ([]*models.Tag, error) {
+	if len(ids) == 0 {
+		return make([]*models.Tag, 0), nil
+	}
+
+	args := make([]interface{}, 0, len(ids))
+
+	for _, id := range ids {
+		args = append(args, id)
+	}
+
+	query := `SELECT id, name, color, created_by, created_at, updated_at FROM tag WHERE id IN (` +
+		strings.TrimSuffix(strings.Repeat("?, ", len(ids)), ", ") + `) ORDER BY name`
+
+	return repo.getAll(ctx, query, args...)
+}
+
+// GetByName will return the tag of an user with the given name
+func (repo *mySQLTagRepo) GetByName(ctx context.Context, userID int64, name string) (*models.Tag, error) {
+	query := `SELECT id, name, color, created_by, created_at, updated_at FROM tag WHERE created_by=? AND name=?`
+	return repo.getOne(ctx, query, userID, name)
+}
+
+// GetAllByUserID returns list of tags created by an user, ordered by name
+func (repo *mySQLTagRepo) GetAllByUserID(ctx context.Context, userID int64) ([]*models.Tag, error) {
+	query := `SELECT id, name, color, created_by, created_at, updated_at FROM tag WHERE created_by=? ORDER BY name`
+	return repo.getAll(ctx, query, userID)
+}
+
+// Create will store new tag entry
+func (repo *mySQLTagRepo) Create(ctx context.Context, tag *models.Tag) error {
+	query := `INSERT INTO tag (name, color, created_by, created_at, updated_at) VALUES (?, ?, ?, ?, ?)`
+
+	tx, err := repo.DB.BeginTx(ctx, nil)
+
+	if err != nil {
+		return err
+	}
+
+	res, err := tx.Exec(
+		query,
+		tag.Name,
+		nullString(tag.Color),
+		tag.CreatedBy.ID,
+		tag.CreatedAt,
+		tag.UpdatedAt,
+	)
+
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	lastID, err := res.LastInsertId()
+
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	err = tx.Commit()
+
+	if err != nil {
+		return err
+	}
+
+	tag.ID = lastID
+
+	return nil
+}
+
+// Update will overwrite the name and color of an existing tag entry
+func (repo *mySQLTagRepo) Update(ctx context.Context, tag *models.Tag) error {
+	query := `UPDATE tag SET name=?, color=?, updated_at=? WHERE id=?`
+
+	tx, err := repo.DB.BeginTx(ctx, nil)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(query, tag.Name, nullString(tag.Color), tag.UpdatedAt, tag.ID)
+
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Delete will remove the tag entry with the given id.
+// The tag is detached from its tasks by the foreign key cascade of task_tag table.
+func (repo *mySQLTagRepo) Delete(ctx context.Context, id int64) error {
+	query := `DELETE FROM tag WHERE id=?`
+
+	tx, err := repo.DB.BeginTx(ctx, nil)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(query, id)
+
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// nullString stores empty strings as NULL
+func nullString(value string) sql.NullString {
+	return sql.NullString{
+		String: value,
+		Valid:  value != "",
+	}
+}
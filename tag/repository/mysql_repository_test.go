@@ -0,0 +1,210 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/dheerajgopi/todo-api/models"
+	"github.com/dheerajgopi/todo-api/tag/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+var tagColumns = []string{"id", "name", "color", "created_by", "created_at", "updated_at"}
+
+const selectTagQuery = "SELECT id, name, color, created_by, created_at, updated_at FROM tag "
+
+func TestGetByID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	rows := sqlmock.
+		NewRows(tagColumns).
+		AddRow(1, "work", "#ff0000", 1, time.Now(), time.Now())
+
+	tagID := int64(1)
+
+	prep := mock.ExpectPrepare(selectTagQuery + "WHERE id=\\?")
+	prep.ExpectQuery().WithArgs(tagID).WillReturnRows(rows)
+
+	repo := repository.New(db)
+
+	tag, err := repo.GetByID(context.TODO(), tagID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "work", tag.Name)
+	assert.Equal(t, "#ff0000", tag.Color)
+	assert.Equal(t, int64(1), tag.CreatedBy.ID)
+}
+
+func TestGetByIDWithNoRows(t *testing.T) {
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	tagID := int64(1)
+
+	prep := mock.ExpectPrepare(selectTagQuery + "WHERE id=\\?")
+	prep.ExpectQuery().WithArgs(tagID).WillReturnRows(sqlmock.NewRows(tagColumns))
+
+	repo := repository.New(db)
+
+	tag, err := repo.GetByID(context.TODO(), tagID)
+
+	assert.NoError(t, err)
+	assert.Nil(t, tag)
+}
+
+func TestGetByIDs(t *testing.T) {
+	assert := assert.New(t)
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	rows := sqlmock.
+		NewRows(tagColumns).
+		AddRow(2, "home", nil, 1, time.Now(), time.Now()).
+		AddRow(1, "work", "#ff0000", 1, time.Now(), time.Now())
+
+	prep := mock.ExpectPrepare(selectTagQuery + "WHERE id IN \\(\\?, \\?\\) ORDER BY name")
+	prep.ExpectQuery().WithArgs(int64(1), int64(2)).WillReturnRows(rows)
+
+	repo := repository.New(db)
+
+	tags, err := repo.GetByIDs(context.TODO(), []int64{1, 2})
+
+	assert.NoError(err)
+	assert.Equal(2, len(tags))
+	assert.Equal("", tags[0].Color)
+	assert.NoError(mock.ExpectationsWereMet())
+}
+
+func TestGetAllByUserID(t *testing.T) {
+	assert := assert.New(t)
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	rows := sqlmock.
+		NewRows(tagColumns).
+		AddRow(1, "work", "#ff0000", 1, time.Now(), time.Now())
+
+	userID := int64(1)
+
+	prep := mock.ExpectPrepare(selectTagQuery + "WHERE created_by=\\? ORDER BY name")
+	prep.ExpectQuery().WithArgs(userID).WillReturnRows(rows)
+
+	repo := repository.New(db)
+
+	tags, err := repo.GetAllByUserID(context.TODO(), userID)
+
+	assert.NoError(err)
+	assert.Equal(1, len(tags))
+}
+
+func TestCreate(t *testing.T) {
+	now := time.Now()
+	tag := &models.Tag{
+		Name: "work",
+		CreatedBy: &models.User{
+			ID: int64(1),
+		},
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	query := "INSERT INTO tag \\(name, color, created_by, created_at, updated_at\\) VALUES \\(\\?, \\?, \\?, \\?, \\?\\)"
+
+	mock.ExpectBegin()
+	mock.ExpectExec(query).
+		WithArgs(tag.Name, nil, tag.CreatedBy.ID, tag.CreatedAt, tag.UpdatedAt).
+		WillReturnResult(sqlmock.NewResult(3, 1))
+	mock.ExpectCommit()
+
+	repo := repository.New(db)
+
+	err = repo.Create(context.TODO(), tag)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), tag.ID)
+}
+
+func TestUpdate(t *testing.T) {
+	now := time.Now()
+	tag := &models.Tag{
+		ID:        1,
+		Name:      "work",
+		Color:     "#00ff00",
+		UpdatedAt: now,
+	}
+
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE tag SET name=\\?, color=\\?, updated_at=\\? WHERE id=\\?").
+		WithArgs(tag.Name, tag.Color, tag.UpdatedAt, tag.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	repo := repository.New(db)
+
+	err = repo.Update(context.TODO(), tag)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDelete(t *testing.T) {
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	tagID := int64(1)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM tag WHERE id=\\?").WithArgs(tagID).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	repo := repository.New(db)
+
+	err = repo.Delete(context.TODO(), tagID)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
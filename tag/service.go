@@ -0,0 +1,16 @@
+package tag
+
+import (
+	"context"
+
+	"github.com/dheerajgopi/todo-api/models"
+)
+
+// Service represents tag service contract
+type Service interface {
+	Create(ctx context.Context, newTag *models.Tag) error
+	List(ctx context.Context, userID int64) ([]*models.Tag, error)
+	GetByID(ctx context.Context, id int64, userID int64) (*models.Tag, error)
+	Update(ctx context.Context, tag *models.Tag, userID int64) error
+	Delete(ctx context.Context, id int64, userID int64) error
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+
+	todoErr "github.com/dheerajgopi/todo-api/common/error"
+	"github.com/dheerajgopi/todo-api/models"
+	"github.com/dheerajgopi/todo-api/tag"
+)
+
+type tagService struct {
+	tagRepo tag.Repository
+}
+
+// New returns a new object implementing tag.Service interface
+func New(repo tag.Repository) tag.Service {
+	return &tagService{
+		tagRepo: repo,
+	}
+}
+
+// Create creates a new tag. Tag names are unique per user.
+func (service *tagService) Create(ctx context.Context, newTag *models.Tag) error {
+	if err := service.checkNameConflict(ctx, newTag); err != nil {
+		return err
+	}
+
+	return service.tagRepo.Create(ctx, newTag)
+}
+
+// List returns tags created by an user
+func (service *tagService) List(ctx context.Context, userID int64) ([]*models.Tag, error) {
+	return service.tagRepo.GetAllByUserID(ctx, userID)
+}
+
+// GetByID returns the tag with the given id, if it is owned by the user.
+// ResourceNotFoundError is returned if the tag is missing or owned by someone else.
+func (service *tagService) GetByID(ctx context.Context, id int64, userID int64) (*models.Tag, error) {
+	existingTag, err := service.tagRepo.GetByID(ctx, id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if existingTag == nil || existingTag.CreatedBy.ID != userID {
+		return nil, &todoErr.ResourceNotFoundError{
+			Resource: "tag",
+		}
+	}
+
+	return existingTag, nil
+}
+
+// Update renames or recolors an existing tag owned by the user
+func (service *tagService) Update(ctx context.Context, tag *models.Tag, userID int64) error {
+	existingTag, err := service.GetByID(ctx, tag.ID, userID)
+
+	if err != nil {
+		return err
+	}
+
+	tag.CreatedBy = existingTag.CreatedBy
+	tag.CreatedAt = existingTag.CreatedAt
+
+	if err = service.checkNameConflict(ctx, tag); err != nil {
+		return err
+	}
+
+	return service.tagRepo.Update(ctx, tag)
+}
+
+// Delete removes an existing tag owned by the user
+func (service *tagService) Delete(ctx context.Context, id int64, userID int64) error {
+	if _, err := service.GetByID(ctx, id, userID); err != nil {
+		return err
+	}
+
+	return service.tagRepo.Delete(ctx, id)
+}
+
+// checkNameConflict returns DataConflictError if the owner of the tag has another tag with the same name
+func (service *tagService) checkNameConflict(ctx context.Context, tag *models.Tag) error {
+	existingTag, err := service.tagRepo.GetByName(ctx, tag.CreatedBy.ID, tag.Name)
+
+	if err != nil {
+		return err
+	}
+
+	if existingTag != nil && existingTag.ID != tag.ID {
+		return &todoErr.DataConflictError{
+			Resource: "tag",
+			Field:    "name",
+		}
+	}
+
+	return nil
+}
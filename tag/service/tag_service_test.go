@@ -0,0 +1,171 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	todoErr "github.com/dheerajgopi/todo-api/common/error"
+	"github.com/dheerajgopi/todo-api/models"
+	tagMock "github.com/dheerajgopi/todo-api/tag/mock"
+	"github.com/dheerajgopi/todo-api/tag/service"
+)
+
+func TestCreate(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	mockRepo := tagMock.NewRepository(mockCtrl)
+	tagService := service.New(mockRepo)
+
+	newTag := &models.Tag{
+		Name: "work",
+		CreatedBy: &models.User{
+			ID: 1,
+		},
+	}
+
+	mockRepo.
+		EXPECT().
+		GetByName(ctx, int64(1), "work").
+		Return(nil, nil).
+		Times(1)
+
+	mockRepo.
+		EXPECT().
+		Create(ctx, newTag).
+		Return(nil).
+		Times(1)
+
+	err := tagService.Create(ctx, newTag)
+
+	assert.NoError(err)
+}
+
+func TestCreateWithDuplicateName(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	mockRepo := tagMock.NewRepository(mockCtrl)
+	tagService := service.New(mockRepo)
+
+	newTag := &models.Tag{
+		Name: "work",
+		CreatedBy: &models.User{
+			ID: 1,
+		},
+	}
+
+	mockRepo.
+		EXPECT().
+		GetByName(ctx, int64(1), "work").
+		Return(&models.Tag{ID: 2, Name: "work"}, nil).
+		Times(1)
+
+	err := tagService.Create(ctx, newTag)
+
+	assert.IsType(&todoErr.DataConflictError{}, err)
+}
+
+func TestGetByIDForTagOwnedByOtherUser(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	mockRepo := tagMock.NewRepository(mockCtrl)
+	tagService := service.New(mockRepo)
+
+	mockRepo.
+		EXPECT().
+		GetByID(ctx, int64(1)).
+		Return(&models.Tag{ID: 1, CreatedBy: &models.User{ID: 2}}, nil).
+		Times(1)
+
+	result, err := tagService.GetByID(ctx, 1, 1)
+
+	assert.Nil(result)
+	assert.IsType(&todoErr.ResourceNotFoundError{}, err)
+}
+
+func TestUpdate(t *testing.T) {
+	createdAt := time.Now().Add(-time.Hour)
+	userID := int64(1)
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	mockRepo := tagMock.NewRepository(mockCtrl)
+	tagService := service.New(mockRepo)
+
+	existingTag := &models.Tag{
+		ID:   1,
+		Name: "work",
+		CreatedBy: &models.User{
+			ID: userID,
+		},
+		CreatedAt: createdAt,
+	}
+
+	updatedTag := &models.Tag{
+		ID:   1,
+		Name: "office",
+	}
+
+	mockRepo.
+		EXPECT().
+		GetByID(ctx, int64(1)).
+		Return(existingTag, nil).
+		Times(1)
+
+	mockRepo.
+		EXPECT().
+		GetByName(ctx, userID, "office").
+		Return(nil, nil).
+		Times(1)
+
+	mockRepo.
+		EXPECT().
+		Update(ctx, updatedTag).
+		Return(nil).
+		Times(1)
+
+	err := tagService.Update(ctx, updatedTag, userID)
+
+	assert.NoError(err)
+	assert.Equal(userID, updatedTag.CreatedBy.ID)
+	assert.Equal(createdAt, updatedTag.CreatedAt)
+}
+
+func TestDeleteForMissingTag(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	mockRepo := tagMock.NewRepository(mockCtrl)
+	tagService := service.New(mockRepo)
+
+	mockRepo.
+		EXPECT().
+		GetByID(ctx, int64(1)).
+		Return(nil, nil).
+		Times(1)
+
+	err := tagService.Delete(ctx, 1, 1)
+
+	assert.IsType(&todoErr.ResourceNotFoundError{}, err)
+}
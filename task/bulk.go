@@ -0,0 +1,46 @@
+package task
+
+import "github.com/dheerajgopi/todo-api/models"
+
+// Operations which can be applied to many tasks at once
+const (
+	BulkComplete      = "complete"
+	BulkReopen        = "reopen"
+	BulkDelete        = "delete"
+	BulkMoveToProject = "moveToProject"
+	BulkAddTag        = "addTag"
+)
+
+// Outcomes of a bulk change on a single task
+const (
+	BulkSucceeded  = "succeeded"
+	BulkFailed     = "failed"
+	BulkRolledBack = "rolledBack"
+	BulkSkipped    = "skipped"
+)
+
+// MaxBulkTasks is the maximum number of tasks which can be changed in a single bulk change
+const MaxBulkTasks = 100
+
+// BulkOperation is an operation applied to each task of a bulk change.
+// Project is the destination of moveToProject, where nil moves the tasks to the inbox, and tag is the tag added by addTag.
+type BulkOperation struct {
+	Type    string
+	Project *models.Project
+	Tag     *models.Tag
+}
+
+// BulkChange applies the operations, in order, to each of the tasks.
+// In all-or-nothing mode, failure of any task rolls back the changes of all the tasks.
+type BulkChange struct {
+	TaskIDs      []int64
+	Operations   []*BulkOperation
+	AllOrNothing bool
+}
+
+// BulkResult is the outcome of a bulk change on a single task, along with the error for failed tasks
+type BulkResult struct {
+	TaskID int64
+	Status string
+	Err    error
+}
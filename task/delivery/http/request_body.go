@@ -1,26 +1,158 @@
 package http
 
 import (
+	"encoding/json"
+	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
 	todoErr "github.com/dheerajgopi/todo-api/common/error"
+	"github.com/dheerajgopi/todo-api/models"
+	"github.com/dheerajgopi/todo-api/task"
 )
 
 // TaskData represents json structure for user
 type TaskData struct {
-	ID          int64     `json:"id"`
-	Title       string    `json:"title"`
-	Description string    `json:"description"`
-	IsComplete  bool      `json:"isComplete"`
-	CreatedAt   time.Time `json:"createdAt"`
-	UpdatedAt   time.Time `json:"updatedAt"`
+	ID           int64         `json:"id"`
+	Title        string        `json:"title"`
+	Description  string        `json:"description"`
+	ProjectID    *int64        `json:"projectId"`
+	ParentID     *int64        `json:"parentId"`
+	IsComplete   bool          `json:"isComplete"`
+	AutoComplete bool          `json:"autoComplete"`
+	Priority     string        `json:"priority"`
+	Position     int64         `json:"position"`
+	DueAt        *time.Time    `json:"dueAt"`
+	DueTimezone  string        `json:"dueTimezone,omitempty"`
+	RemindAt     *time.Time    `json:"remindAt"`
+	Recurrence   string        `json:"recurrence,omitempty"`
+	Tags         []*TagData    `json:"tags"`
+	Progress     *ProgressData `json:"progress,omitempty"`
+	CommentCount int64         `json:"commentCount"`
+	Subtasks     []*TaskData   `json:"subtasks,omitempty"`
+	SharedBy     *UserData     `json:"sharedBy,omitempty"`
+	CreatedAt    time.Time     `json:"createdAt"`
+	UpdatedAt    time.Time     `json:"updatedAt"`
+}
+
+// ProgressData represents json structure for the completion of the subtasks of a task
+type ProgressData struct {
+	Completed int64 `json:"completed"`
+	Total     int64 `json:"total"`
+}
+
+// TagData represents json structure for a tag attached to a task
+type TagData struct {
+	ID    int64  `json:"id"`
+	Name  string `json:"name"`
+	Color string `json:"color,omitempty"`
+}
+
+// UserData represents json structure for the owner of a task shared with the user
+type UserData struct {
+	ID    int64  `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+// PortableTaskData represents json structure for a task in the files of GET /tasks/export and POST /tasks/import APIs.
+// Project and tags are referred by name, so that the file can be imported by another user.
+// Subtasks refer to the id of their parent in the same file.
+type PortableTaskData struct {
+	Line         int        `json:"-"`
+	ID           int64      `json:"id"`
+	ParentID     *int64     `json:"parentId"`
+	Title        string     `json:"title"`
+	Description  string     `json:"description"`
+	Project      string     `json:"project"`
+	Tags         []string   `json:"tags"`
+	IsComplete   bool       `json:"isComplete"`
+	AutoComplete bool       `json:"autoComplete"`
+	Priority     string     `json:"priority"`
+	DueAt        *time.Time `json:"dueAt"`
+	DueTimezone  string     `json:"dueTimezone"`
+	RemindAt     *time.Time `json:"remindAt"`
+	Recurrence   string     `json:"recurrence"`
+	CreatedAt    *time.Time `json:"createdAt"`
+}
+
+// NullableTime is a JSON time field which remembers whether it was present in the request body,
+// so that an explicit null can be told apart from a missing field
+type NullableTime struct {
+	Set   bool
+	Value *time.Time
+}
+
+// UnmarshalJSON marks the field as set and parses the RFC 3339 time, if not null
+func (field *NullableTime) UnmarshalJSON(data []byte) error {
+	field.Set = true
+
+	if string(data) == "null" {
+		field.Value = nil
+		return nil
+	}
+
+	value := time.Time{}
+
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	field.Value = &value
+
+	return nil
+}
+
+// MarshalJSON writes the time value, or null if it is missing
+func (field NullableTime) MarshalJSON() ([]byte, error) {
+	return json.Marshal(field.Value)
+}
+
+// NullableInt64 is a JSON integer field which remembers whether it was present in the request body,
+// so that an explicit null can be told apart from a missing field
+type NullableInt64 struct {
+	Set   bool
+	Value *int64
+}
+
+// UnmarshalJSON marks the field as set and parses the integer, if not null
+func (field *NullableInt64) UnmarshalJSON(data []byte) error {
+	field.Set = true
+
+	if string(data) == "null" {
+		field.Value = nil
+		return nil
+	}
+
+	value := int64(0)
+
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	field.Value = &value
+
+	return nil
+}
+
+// MarshalJSON writes the integer value, or null if it is missing
+func (field NullableInt64) MarshalJSON() ([]byte, error) {
+	return json.Marshal(field.Value)
 }
 
 // CreateTaskRequest represents request body for POST /tasks API
 type CreateTaskRequest struct {
-	Title       string `json:"title"`
-	Description string `json:"description"`
+	Title        string     `json:"title"`
+	Description  string     `json:"description"`
+	ProjectID    *int64     `json:"projectId"`
+	AutoComplete bool       `json:"autoComplete"`
+	Priority     string     `json:"priority"`
+	DueAt        *time.Time `json:"dueAt"`
+	DueTimezone  string     `json:"dueTimezone"`
+	RemindAt     *time.Time `json:"remindAt"`
+	Recurrence   string     `json:"recurrence"`
+	Tags         []int64    `json:"tags"`
 }
 
 // ValidateAndBuild validates the request body for POST /tasks API
@@ -37,8 +169,770 @@ func (body *CreateTaskRequest) ValidateAndBuild() []*todoErr.APIErrorBody {
 		})
 	}
 
+	priority, err := validatePriority(body.Priority)
+
+	if err != nil {
+		validationErrors = append(validationErrors, err)
+	}
+
+	dueTimezone, err := validateDueTimezone(body.DueAt, body.DueTimezone)
+
+	if err != nil {
+		validationErrors = append(validationErrors, err)
+	}
+
+	recurrence, err := validateRecurrence(body.Recurrence)
+
+	if err != nil {
+		validationErrors = append(validationErrors, err)
+	}
+
 	body.Title = trimmedTitle
+	body.Priority = priority
 	body.Description = trimmedDescription
+	body.DueTimezone = dueTimezone
+	body.Recurrence = recurrence
 
 	return validationErrors
 }
+
+// UpdateTaskRequest represents request body for PUT /tasks/{id} API
+type UpdateTaskRequest struct {
+	Title        string     `json:"title"`
+	Description  string     `json:"description"`
+	ProjectID    *int64     `json:"projectId"`
+	IsComplete   bool       `json:"isComplete"`
+	AutoComplete bool       `json:"autoComplete"`
+	Priority     string     `json:"priority"`
+	DueAt        *time.Time `json:"dueAt"`
+	DueTimezone  string     `json:"dueTimezone"`
+	RemindAt     *time.Time `json:"remindAt"`
+	Recurrence   string     `json:"recurrence"`
+	Tags         []int64    `json:"tags"`
+}
+
+// ValidateAndBuild validates the request body for PUT /tasks/{id} API
+func (body *UpdateTaskRequest) ValidateAndBuild() []*todoErr.APIErrorBody {
+	trimmedTitle := strings.TrimSpace(body.Title)
+	trimmedDescription := strings.TrimSpace(body.Description)
+
+	validationErrors := make([]*todoErr.APIErrorBody, 0)
+
+	if trimmedTitle == "" {
+		validationErrors = append(validationErrors, &todoErr.APIErrorBody{
+			Message: "Non-empty value is required",
+			Target:  "title",
+		})
+	}
+
+	priority, err := validatePriority(body.Priority)
+
+	if err != nil {
+		validationErrors = append(validationErrors, err)
+	}
+
+	dueTimezone, err := validateDueTimezone(body.DueAt, body.DueTimezone)
+
+	if err != nil {
+		validationErrors = append(validationErrors, err)
+	}
+
+	recurrence, err := validateRecurrence(body.Recurrence)
+
+	if err != nil {
+		validationErrors = append(validationErrors, err)
+	}
+
+	body.Title = trimmedTitle
+	body.Priority = priority
+	body.Description = trimmedDescription
+	body.DueTimezone = dueTimezone
+	body.Recurrence = recurrence
+
+	return validationErrors
+}
+
+// PatchTaskRequest represents request body for PATCH /tasks/{id} API.
+// Only the fields present in the request are applied to the task.
+// Due date and reminder are removed if they are set to null, and the task is moved to the inbox if project is null.
+// Recurrence is removed if it is set to an empty string.
+type PatchTaskRequest struct {
+	Title        *string       `json:"title"`
+	Description  *string       `json:"description"`
+	ProjectID    NullableInt64 `json:"projectId"`
+	IsComplete   *bool         `json:"isComplete"`
+	AutoComplete *bool         `json:"autoComplete"`
+	Priority     *string       `json:"priority"`
+	DueAt        NullableTime  `json:"dueAt"`
+	DueTimezone  *string       `json:"dueTimezone"`
+	RemindAt     NullableTime  `json:"remindAt"`
+	Recurrence   *string       `json:"recurrence"`
+	Tags         *[]int64      `json:"tags"`
+}
+
+// ValidateAndBuild validates the request body for PATCH /tasks/{id} API
+func (body *PatchTaskRequest) ValidateAndBuild() []*todoErr.APIErrorBody {
+	validationErrors := make([]*todoErr.APIErrorBody, 0)
+
+	if body.Priority != nil {
+		priority, err := validatePriority(*body.Priority)
+
+		if err != nil {
+			validationErrors = append(validationErrors, err)
+		}
+
+		body.Priority = &priority
+	}
+
+	if body.DueTimezone != nil {
+		if _, err := time.LoadLocation(*body.DueTimezone); err != nil || *body.DueTimezone == "" {
+			validationErrors = append(validationErrors, &todoErr.APIErrorBody{
+				Message: "Invalid timezone",
+				Target:  "dueTimezone",
+			})
+		}
+	}
+
+	if body.Recurrence != nil {
+		recurrence, err := validateRecurrence(*body.Recurrence)
+
+		if err != nil {
+			validationErrors = append(validationErrors, err)
+		}
+
+		body.Recurrence = &recurrence
+	}
+
+	if body.Title != nil {
+		trimmedTitle := strings.TrimSpace(*body.Title)
+
+		if trimmedTitle == "" {
+			validationErrors = append(validationErrors, &todoErr.APIErrorBody{
+				Message: "Non-empty value is required",
+				Target:  "title",
+			})
+		}
+
+		body.Title = &trimmedTitle
+	}
+
+	if body.Description != nil {
+		trimmedDescription := strings.TrimSpace(*body.Description)
+		body.Description = &trimmedDescription
+	}
+
+	return validationErrors
+}
+
+// appendUnique appends the id to the list, unless it is already present
+func appendUnique(ids []int64, id int64) []int64 {
+	for _, existingID := range ids {
+		if existingID == id {
+			return ids
+		}
+	}
+
+	return append(ids, id)
+}
+
+// validatePriority checks the priority name, which defaults to none
+func validatePriority(priority string) (string, *todoErr.APIErrorBody) {
+	priority = strings.ToLower(strings.TrimSpace(priority))
+
+	if priority == "" {
+		return task.PriorityName(task.PriorityNone), nil
+	}
+
+	if _, ok := task.ParsePriority(priority); !ok {
+		return priority, &todoErr.APIErrorBody{
+			Message: "Value should be one of none, low, medium, high, urgent",
+			Target:  "priority",
+		}
+	}
+
+	return priority, nil
+}
+
+// validateDueTimezone checks the IANA timezone of a due date and returns the timezone to be stored.
+// Timezone defaults to UTC when a due date is given, and is dropped when there is no due date.
+func validateDueTimezone(dueAt *time.Time, dueTimezone string) (string, *todoErr.APIErrorBody) {
+	dueTimezone = strings.TrimSpace(dueTimezone)
+
+	if dueAt == nil {
+		return "", nil
+	}
+
+	if dueTimezone == "" {
+		return "UTC", nil
+	}
+
+	if _, err := time.LoadLocation(dueTimezone); err != nil {
+		return "", &todoErr.APIErrorBody{
+			Message: "Invalid timezone",
+			Target:  "dueTimezone",
+		}
+	}
+
+	return dueTimezone, nil
+}
+
+// validateRecurrence checks the recurrence rule of a task and returns it in canonical form.
+// Empty rule means that the task does not recur.
+func validateRecurrence(rule string) (string, *todoErr.APIErrorBody) {
+	rule = strings.TrimSpace(rule)
+
+	if rule == "" {
+		return "", nil
+	}
+
+	recurrence, err := task.ParseRecurrence(rule)
+
+	if err != nil {
+		return rule, &todoErr.APIErrorBody{
+			Message: err.Error(),
+			Target:  "recurrence",
+		}
+	}
+
+	return recurrence.String(), nil
+}
+
+// MoveTaskRequest represents request body for POST /tasks/{id}/move API.
+// Exactly one of the anchor tasks should be given.
+type MoveTaskRequest struct {
+	BeforeID *int64 `json:"beforeId"`
+	AfterID  *int64 `json:"afterId"`
+}
+
+// ValidateAndBuild validates the request body for POST /tasks/{id}/move API
+func (body *MoveTaskRequest) ValidateAndBuild() []*todoErr.APIErrorBody {
+	validationErrors := make([]*todoErr.APIErrorBody, 0)
+
+	if (body.BeforeID == nil) == (body.AfterID == nil) {
+		validationErrors = append(validationErrors, &todoErr.APIErrorBody{
+			Message: "Either beforeId or afterId is required",
+			Target:  "beforeId",
+		})
+	}
+
+	return validationErrors
+}
+
+// ImportTaskRequest represents the tasks read from the file of POST /tasks/import API
+type ImportTaskRequest struct {
+	Tasks []*PortableTaskData
+}
+
+// ValidateAndBuild validates each task of the file of POST /tasks/import API with the rules of POST /tasks API,
+// and builds the tasks to be created, with projects and tags referred by name.
+// A subtask should come after its parent, and its parent should not be a subtask.
+func (body *ImportTaskRequest) ValidateAndBuild() ([]*models.Task, []*ImportErrorData) {
+	tasks := make([]*models.Task, 0, len(body.Tasks))
+	tasksByID := make(map[int64]*models.Task)
+	validationErrors := make([]*ImportErrorData, 0)
+
+	for _, taskData := range body.Tasks {
+		createTaskReqBody := &CreateTaskRequest{
+			Title:        taskData.Title,
+			Description:  taskData.Description,
+			AutoComplete: taskData.AutoComplete,
+			Priority:     taskData.Priority,
+			DueAt:        taskData.DueAt,
+			DueTimezone:  taskData.DueTimezone,
+			RemindAt:     taskData.RemindAt,
+			Recurrence:   taskData.Recurrence,
+		}
+
+		lineErrors := createTaskReqBody.ValidateAndBuild()
+		parent, parentError := validateImportParent(taskData.ParentID, tasksByID)
+
+		if parentError != nil {
+			lineErrors = append(lineErrors, parentError)
+		}
+
+		if taskData.ID != 0 && tasksByID[taskData.ID] != nil {
+			lineErrors = append(lineErrors, &todoErr.APIErrorBody{
+				Message: "Duplicate id",
+				Target:  "id",
+			})
+		}
+
+		for _, lineError := range lineErrors {
+			validationErrors = append(validationErrors, &ImportErrorData{
+				Line:    taskData.Line,
+				Message: lineError.Message,
+				Target:  lineError.Target,
+			})
+		}
+
+		priority, _ := task.ParsePriority(createTaskReqBody.Priority)
+
+		newTask := &models.Task{
+			Title:        createTaskReqBody.Title,
+			Description:  createTaskReqBody.Description,
+			Parent:       parent,
+			IsComplete:   taskData.IsComplete,
+			AutoComplete: createTaskReqBody.AutoComplete,
+			Priority:     priority,
+			DueAt:        createTaskReqBody.DueAt,
+			DueTimezone:  createTaskReqBody.DueTimezone,
+			RemindAt:     createTaskReqBody.RemindAt,
+			Recurrence:   createTaskReqBody.Recurrence,
+			Tags:         make([]*models.Tag, 0, len(taskData.Tags)),
+		}
+
+		if projectName := strings.TrimSpace(taskData.Project); projectName != "" {
+			newTask.Project = &models.Project{
+				Name: projectName,
+			}
+		}
+
+		seenTags := make(map[string]bool)
+
+		for _, tagName := range taskData.Tags {
+			tagName = strings.TrimSpace(tagName)
+
+			if tagName != "" && !seenTags[tagName] {
+				seenTags[tagName] = true
+				newTask.Tags = append(newTask.Tags, &models.Tag{
+					Name: tagName,
+				})
+			}
+		}
+
+		if taskData.CreatedAt != nil {
+			newTask.CreatedAt = *taskData.CreatedAt
+		}
+
+		if taskData.ID != 0 && tasksByID[taskData.ID] == nil {
+			tasksByID[taskData.ID] = newTask
+		}
+
+		tasks = append(tasks, newTask)
+	}
+
+	return tasks, validationErrors
+}
+
+// validateImportParent finds the parent of an imported subtask among the tasks which came before it
+func validateImportParent(parentID *int64, tasksByID map[int64]*models.Task) (*models.Task, *todoErr.APIErrorBody) {
+	if parentID == nil {
+		return nil, nil
+	}
+
+	parent, ok := tasksByID[*parentID]
+
+	if !ok {
+		return nil, &todoErr.APIErrorBody{
+			Message: "Parent task should come before its subtasks",
+			Target:  "parentId",
+		}
+	}
+
+	if parent.Parent != nil {
+		return nil, &todoErr.APIErrorBody{
+			Message: "Subtasks can not have subtasks",
+			Target:  "parentId",
+		}
+	}
+
+	return parent, nil
+}
+
+// BulkOperationData represents an operation in the request body for POST /tasks/bulk API.
+// Project id is required for moveToProject, where null moves the tasks to the inbox, and tag id is required for addTag.
+type BulkOperationData struct {
+	Type      string        `json:"type"`
+	ProjectID NullableInt64 `json:"projectId"`
+	TagID     *int64        `json:"tagId"`
+}
+
+// BulkTaskRequest represents request body for POST /tasks/bulk API.
+// Changes are best-effort by default, where only the failed tasks are left unchanged.
+type BulkTaskRequest struct {
+	TaskIDs      []int64              `json:"taskIds"`
+	Operations   []*BulkOperationData `json:"operations"`
+	AllOrNothing bool                 `json:"allOrNothing"`
+}
+
+// ValidateAndBuild validates the request body for POST /tasks/bulk API, and builds the bulk change.
+// Duplicate task ids are removed. Delete is allowed only as the last operation.
+func (body *BulkTaskRequest) ValidateAndBuild() (*task.BulkChange, []*todoErr.APIErrorBody) {
+	validationErrors := make([]*todoErr.APIErrorBody, 0)
+	change := &task.BulkChange{
+		TaskIDs:      make([]int64, 0, len(body.TaskIDs)),
+		Operations:   make([]*task.BulkOperation, 0, len(body.Operations)),
+		AllOrNothing: body.AllOrNothing,
+	}
+
+	for _, id := range body.TaskIDs {
+		change.TaskIDs = appendUnique(change.TaskIDs, id)
+	}
+
+	if len(change.TaskIDs) == 0 || len(change.TaskIDs) > task.MaxBulkTasks {
+		validationErrors = append(validationErrors, &todoErr.APIErrorBody{
+			Message: "Between 1 and " + strconv.Itoa(task.MaxBulkTasks) + " tasks are required",
+			Target:  "taskIds",
+		})
+	}
+
+	if len(body.Operations) == 0 {
+		validationErrors = append(validationErrors, &todoErr.APIErrorBody{
+			Message: "At least one operation is required",
+			Target:  "operations",
+		})
+	}
+
+	for i, operationData := range body.Operations {
+		operation, err := operationData.build()
+
+		if err == nil && operation.Type == task.BulkDelete && i != len(body.Operations)-1 {
+			err = &todoErr.APIErrorBody{
+				Message: "Delete should be the last operation",
+				Target:  "operations",
+			}
+		}
+
+		if err != nil {
+			validationErrors = append(validationErrors, err)
+			continue
+		}
+
+		change.Operations = append(change.Operations, operation)
+	}
+
+	return change, validationErrors
+}
+
+// build validates a bulk operation and builds it
+func (operationData *BulkOperationData) build() (*task.BulkOperation, *todoErr.APIErrorBody) {
+	if operationData == nil {
+		return nil, &todoErr.APIErrorBody{
+			Message: "Invalid operation",
+			Target:  "operations",
+		}
+	}
+
+	operation := &task.BulkOperation{
+		Type: operationData.Type,
+	}
+
+	switch operationData.Type {
+	case task.BulkComplete, task.BulkReopen, task.BulkDelete:
+	case task.BulkMoveToProject:
+		if !operationData.ProjectID.Set {
+			return nil, &todoErr.APIErrorBody{
+				Message: "Project is required",
+				Target:  "projectId",
+			}
+		}
+
+		operation.Project = newProjectRef(operationData.ProjectID.Value)
+	case task.BulkAddTag:
+		if operationData.TagID == nil {
+			return nil, &todoErr.APIErrorBody{
+				Message: "Tag is required",
+				Target:  "tagId",
+			}
+		}
+
+		operation.Tag = &models.Tag{
+			ID: *operationData.TagID,
+		}
+	default:
+		return nil, &todoErr.APIErrorBody{
+			Message: "Operation should be one of complete, reopen, delete, moveToProject or addTag",
+			Target:  "type",
+		}
+	}
+
+	return operation, nil
+}
+
+// ListTaskRequest represents query parameters for GET /tasks API
+type ListTaskRequest struct {
+	IsComplete    string
+	Title         string
+	Project       string
+	CreatedAfter  string
+	CreatedBefore string
+	UpdatedAfter  string
+	UpdatedBefore string
+	DueAfter      string
+	DueBefore     string
+	Due           string
+	Timezone      string
+	Tags          []string
+	TagMatch      string
+	Subtasks      string
+	Sort          string
+	Cursor        string
+	Limit         string
+}
+
+// NewListTaskRequest reads the query parameters for GET /tasks API
+func NewListTaskRequest(query url.Values) *ListTaskRequest {
+	return &ListTaskRequest{
+		IsComplete:    query.Get("isComplete"),
+		Title:         query.Get("title"),
+		Project:       query.Get("project"),
+		CreatedAfter:  query.Get("createdAfter"),
+		CreatedBefore: query.Get("createdBefore"),
+		UpdatedAfter:  query.Get("updatedAfter"),
+		UpdatedBefore: query.Get("updatedBefore"),
+		DueAfter:      query.Get("dueAfter"),
+		DueBefore:     query.Get("dueBefore"),
+		Due:           query.Get("due"),
+		Timezone:      query.Get("tz"),
+		Tags:          query["tag"],
+		TagMatch:      query.Get("tagMatch"),
+		Subtasks:      query.Get("subtasks"),
+		Sort:          query.Get("sort"),
+		Cursor:        query.Get("cursor"),
+		Limit:         query.Get("limit"),
+	}
+}
+
+// ValidateAndBuild validates the query parameters for GET /tasks API and builds the list filter.
+// Sort field can be prefixed with '-' for descending order. Dates are expected in RFC 3339 format.
+// Due date windows (overdue, today, week) are computed in the timezone given by tz, defaulting to UTC.
+// Tag ids can be repeated or comma separated, and tagMatch decides whether tasks need any or all of them.
+// Project can be a project id, or inbox for tasks without project.
+// Subtasks are left out by default. They are nested in their parents with subtasks=tree,
+// or listed along with the top level tasks with subtasks=flat.
+func (params *ListTaskRequest) ValidateAndBuild() (*task.ListFilter, []*todoErr.APIErrorBody) {
+	filter := &task.ListFilter{
+		Title: strings.TrimSpace(params.Title),
+		Sort:  task.SortByCreatedAt,
+		Limit: task.DefaultListLimit,
+	}
+
+	validationErrors := make([]*todoErr.APIErrorBody, 0)
+
+	if params.IsComplete != "" {
+		isComplete, err := strconv.ParseBool(params.IsComplete)
+
+		if err != nil {
+			validationErrors = append(validationErrors, &todoErr.APIErrorBody{
+				Message: "Invalid value",
+				Target:  "isComplete",
+			})
+		} else {
+			filter.IsComplete = &isComplete
+		}
+	}
+
+	if params.Project == "inbox" {
+		filter.Inbox = true
+	} else if params.Project != "" {
+		projectID, err := strconv.ParseInt(params.Project, 10, 64)
+
+		if err != nil {
+			validationErrors = append(validationErrors, &todoErr.APIErrorBody{
+				Message: "Invalid value",
+				Target:  "project",
+			})
+		} else {
+			filter.ProjectID = &projectID
+		}
+	}
+
+	dateParams := []struct {
+		value  string
+		target string
+		field  **time.Time
+	}{
+		{params.CreatedAfter, "createdAfter", &filter.CreatedAfter},
+		{params.CreatedBefore, "createdBefore", &filter.CreatedBefore},
+		{params.UpdatedAfter, "updatedAfter", &filter.UpdatedAfter},
+		{params.UpdatedBefore, "updatedBefore", &filter.UpdatedBefore},
+		{params.DueAfter, "dueAfter", &filter.DueAfter},
+		{params.DueBefore, "dueBefore", &filter.DueBefore},
+	}
+
+	for _, param := range dateParams {
+		if param.value == "" {
+			continue
+		}
+
+		date, err := time.Parse(time.RFC3339, param.value)
+
+		if err != nil {
+			validationErrors = append(validationErrors, &todoErr.APIErrorBody{
+				Message: "Invalid date",
+				Target:  param.target,
+			})
+
+			continue
+		}
+
+		*param.field = &date
+	}
+
+	location := time.UTC
+
+	if params.Timezone != "" {
+		loc, err := time.LoadLocation(params.Timezone)
+
+		if err != nil {
+			validationErrors = append(validationErrors, &todoErr.APIErrorBody{
+				Message: "Invalid timezone",
+				Target:  "tz",
+			})
+		} else {
+			location = loc
+		}
+	}
+
+	if params.Due != "" {
+		if !task.IsDueWindow(params.Due) {
+			validationErrors = append(validationErrors, &todoErr.APIErrorBody{
+				Message: "Value should be one of overdue, today, week",
+				Target:  "due",
+			})
+		} else {
+			filter.DueAfter, filter.DueBefore = task.DueWindowRange(params.Due, time.Now().In(location))
+
+			if params.Due == task.DueOverdue {
+				isComplete := false
+				filter.IsComplete = &isComplete
+			}
+		}
+	}
+
+	for _, tagParam := range params.Tags {
+		for _, value := range strings.Split(tagParam, ",") {
+			tagID, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+
+			if err != nil {
+				validationErrors = append(validationErrors, &todoErr.APIErrorBody{
+					Message: "Invalid value",
+					Target:  "tag",
+				})
+
+				continue
+			}
+
+			filter.TagIDs = appendUnique(filter.TagIDs, tagID)
+		}
+	}
+
+	switch params.TagMatch {
+	case "", "any":
+	case "all":
+		filter.MatchAllTags = true
+	default:
+		validationErrors = append(validationErrors, &todoErr.APIErrorBody{
+			Message: "Value should be one of any, all",
+			Target:  "tagMatch",
+		})
+	}
+
+	switch params.Subtasks {
+	case "", "none":
+		filter.TopLevelOnly = true
+	case "tree":
+		filter.TopLevelOnly = true
+		filter.WithSubtasks = true
+	case "flat":
+	default:
+		validationErrors = append(validationErrors, &todoErr.APIErrorBody{
+			Message: "Value should be one of none, tree, flat",
+			Target:  "subtasks",
+		})
+	}
+
+	if params.Sort != "" {
+		sort := params.Sort
+
+		if strings.HasPrefix(sort, "-") {
+			filter.Descending = true
+			sort = sort[1:]
+		}
+
+		if !task.IsSortable(sort) {
+			validationErrors = append(validationErrors, &todoErr.APIErrorBody{
+				Message: "Invalid value",
+				Target:  "sort",
+			})
+		}
+
+		filter.Sort = sort
+	}
+
+	if params.Limit != "" {
+		limit, err := strconv.Atoi(params.Limit)
+
+		if err != nil || limit < 1 || limit > task.MaxListLimit {
+			validationErrors = append(validationErrors, &todoErr.APIErrorBody{
+				Message: "Value should be between 1 and " + strconv.Itoa(task.MaxListLimit),
+				Target:  "limit",
+			})
+		} else {
+			filter.Limit = limit
+		}
+	}
+
+	if params.Cursor != "" {
+		cursor, err := task.DecodeCursor(params.Cursor)
+
+		if err != nil || cursor.Sort != filter.Sort {
+			validationErrors = append(validationErrors, &todoErr.APIErrorBody{
+				Message: "Invalid value",
+				Target:  "cursor",
+			})
+		} else {
+			filter.Cursor = cursor
+		}
+	}
+
+	return filter, validationErrors
+}
+
+// TransferTaskRequest represents query parameters for GET /tasks/export and POST /tasks/import APIs
+type TransferTaskRequest struct {
+	Format string
+	DryRun string
+}
+
+// NewTransferTaskRequest reads the query parameters for GET /tasks/export and POST /tasks/import APIs
+func NewTransferTaskRequest(query url.Values) *TransferTaskRequest {
+	return &TransferTaskRequest{
+		Format: query.Get("format"),
+		DryRun: query.Get("dryRun"),
+	}
+}
+
+// ValidateAndBuild validates the query parameters for GET /tasks/export and POST /tasks/import APIs,
+// and returns the format of the file and whether the import is a dry run. Format defaults to json.
+func (params *TransferTaskRequest) ValidateAndBuild() (string, bool, []*todoErr.APIErrorBody) {
+	validationErrors := make([]*todoErr.APIErrorBody, 0)
+	format := strings.ToLower(strings.TrimSpace(params.Format))
+	dryRun := false
+
+	switch format {
+	case "":
+		format = formatJSON
+	case formatJSON, formatCSV, formatTodoTxt:
+	default:
+		validationErrors = append(validationErrors, &todoErr.APIErrorBody{
+			Message: "Value should be one of json, csv, todotxt",
+			Target:  "format",
+		})
+	}
+
+	if params.DryRun != "" {
+		value, err := strconv.ParseBool(params.DryRun)
+
+		if err != nil {
+			validationErrors = append(validationErrors, &todoErr.APIErrorBody{
+				Message: "Invalid value",
+				Target:  "dryRun",
+			})
+		}
+
+		dryRun = value
+	}
+
+	return format, dryRun, validationErrors
+}
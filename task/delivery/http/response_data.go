@@ -1,5 +1,13 @@
 package http
 
+import (
+	"time"
+
+	todoErr "github.com/dheerajgopi/todo-api/common/error"
+	"github.com/dheerajgopi/todo-api/models"
+	"github.com/dheerajgopi/todo-api/task"
+)
+
 // CreateTaskResponse represents response for POST /tasks API
 type CreateTaskResponse struct {
 	Task *TaskData `json:"task"`
@@ -7,5 +15,200 @@ type CreateTaskResponse struct {
 
 // ListTaskResponse represents response for GET /tasks API
 type ListTaskResponse struct {
-	Tasks []*TaskData `json:"tasks"`
+	Tasks      []*TaskData     `json:"tasks"`
+	Pagination *PaginationData `json:"pagination"`
+}
+
+// PaginationData represents json structure for the pagination details of a list
+type PaginationData struct {
+	NextCursor string `json:"nextCursor,omitempty"`
+	HasMore    bool   `json:"hasMore"`
+}
+
+// GetTaskResponse represents response for GET /tasks/{id} API
+type GetTaskResponse struct {
+	Task *TaskData `json:"task"`
+}
+
+// ListSubtaskResponse represents response for GET /tasks/{id}/subtasks API
+type ListSubtaskResponse struct {
+	Subtasks []*TaskData `json:"subtasks"`
+}
+
+// UpdateTaskResponse represents response for PUT and PATCH /tasks/{id} APIs
+type UpdateTaskResponse struct {
+	Task *TaskData `json:"task"`
+}
+
+// BulkResultData represents json structure for the outcome of a bulk change on a task.
+// Error is present only for failed tasks, along with the HTTP status which the failure would get on its own.
+type BulkResultData struct {
+	TaskID int64                 `json:"taskId"`
+	Status string                `json:"status"`
+	Code   int                   `json:"code,omitempty"`
+	Error  *todoErr.APIErrorBody `json:"error,omitempty"`
+}
+
+// BulkTaskResponse represents response for POST /tasks/bulk API
+type BulkTaskResponse struct {
+	Results   []*BulkResultData `json:"results"`
+	Succeeded int               `json:"succeeded"`
+	Failed    int               `json:"failed"`
+}
+
+// ImportErrorData represents json structure for a task of an imported file which could not be imported
+type ImportErrorData struct {
+	Line    int    `json:"line"`
+	Message string `json:"message"`
+	Target  string `json:"target,omitempty"`
+}
+
+// ImportTaskResponse represents response for POST /tasks/import API.
+// Tasks are imported only if there are no errors and it is not a dry run.
+type ImportTaskResponse struct {
+	DryRun   bool               `json:"dryRun"`
+	Total    int                `json:"total"`
+	Imported int                `json:"imported"`
+	Errors   []*ImportErrorData `json:"errors"`
+}
+
+// newTaskData builds the json structure of a task.
+// Due date is rendered in the timezone it was set in, and progress is shown only for tasks having subtasks.
+func newTaskData(taskModel *models.Task) *TaskData {
+	taskData := &TaskData{
+		ID:           taskModel.ID,
+		Title:        taskModel.Title,
+		Description:  taskModel.Description,
+		IsComplete:   taskModel.IsComplete,
+		AutoComplete: taskModel.AutoComplete,
+		Priority:     task.PriorityName(taskModel.Priority),
+		Position:     taskModel.Position,
+		RemindAt:     taskModel.RemindAt,
+		Recurrence:   taskModel.Recurrence,
+		Tags:         make([]*TagData, 0),
+		CommentCount: taskModel.CommentCount,
+		CreatedAt:    taskModel.CreatedAt,
+		UpdatedAt:    taskModel.UpdatedAt,
+	}
+
+	if taskModel.Parent != nil {
+		parentID := taskModel.Parent.ID
+		taskData.ParentID = &parentID
+	}
+
+	if taskModel.SharedBy != nil {
+		taskData.SharedBy = &UserData{
+			ID:    taskModel.SharedBy.ID,
+			Name:  taskModel.SharedBy.Name,
+			Email: taskModel.SharedBy.Email,
+		}
+	}
+
+	if taskModel.Progress != nil && taskModel.Progress.Total > 0 {
+		taskData.Progress = &ProgressData{
+			Completed: taskModel.Progress.Completed,
+			Total:     taskModel.Progress.Total,
+		}
+	}
+
+	if taskModel.Subtasks != nil {
+		taskData.Subtasks = make([]*TaskData, 0, len(taskModel.Subtasks))
+
+		for _, subtask := range taskModel.Subtasks {
+			taskData.Subtasks = append(taskData.Subtasks, newTaskData(subtask))
+		}
+	}
+
+	for _, tag := range taskModel.Tags {
+		taskData.Tags = append(taskData.Tags, &TagData{
+			ID:    tag.ID,
+			Name:  tag.Name,
+			Color: tag.Color,
+		})
+	}
+
+	if taskModel.Project != nil {
+		projectID := taskModel.Project.ID
+		taskData.ProjectID = &projectID
+	}
+
+	if taskModel.DueAt != nil {
+		dueAt := *taskModel.DueAt
+
+		if location, err := time.LoadLocation(taskModel.DueTimezone); err == nil {
+			dueAt = dueAt.In(location)
+		}
+
+		taskData.DueAt = &dueAt
+		taskData.DueTimezone = taskModel.DueTimezone
+	}
+
+	return taskData
+}
+
+// newPortableTaskData builds the json structure of an exported task
+func newPortableTaskData(taskModel *models.Task) *PortableTaskData {
+	createdAt := taskModel.CreatedAt
+	taskData := &PortableTaskData{
+		ID:           taskModel.ID,
+		Title:        taskModel.Title,
+		Description:  taskModel.Description,
+		Tags:         make([]string, 0, len(taskModel.Tags)),
+		IsComplete:   taskModel.IsComplete,
+		AutoComplete: taskModel.AutoComplete,
+		Priority:     task.PriorityName(taskModel.Priority),
+		RemindAt:     taskModel.RemindAt,
+		Recurrence:   taskModel.Recurrence,
+		CreatedAt:    &createdAt,
+	}
+
+	if taskModel.Parent != nil {
+		parentID := taskModel.Parent.ID
+		taskData.ParentID = &parentID
+	}
+
+	if taskModel.Project != nil {
+		taskData.Project = taskModel.Project.Name
+	}
+
+	for _, tag := range taskModel.Tags {
+		taskData.Tags = append(taskData.Tags, tag.Name)
+	}
+
+	if taskModel.DueAt != nil {
+		dueAt := *taskModel.DueAt
+
+		if location, err := time.LoadLocation(taskModel.DueTimezone); err == nil {
+			dueAt = dueAt.In(location)
+		}
+
+		taskData.DueAt = &dueAt
+		taskData.DueTimezone = taskModel.DueTimezone
+	}
+
+	return taskData
+}
+
+// newTagRefs builds the tags referred by id in a request
+func newTagRefs(ids []int64) []*models.Tag {
+	tags := make([]*models.Tag, 0, len(ids))
+
+	for _, id := range ids {
+		tags = append(tags, &models.Tag{
+			ID: id,
+		})
+	}
+
+	return tags
+}
+
+// newProjectRef builds the project referred by id in a request, or nil for the inbox
+func newProjectRef(id *int64) *models.Project {
+	if id == nil {
+		return nil
+	}
+
+	return &models.Project{
+		ID: *id,
+	}
 }
@@ -0,0 +1,581 @@
+package http
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dheerajgopi/todo-api/models"
+	"github.com/dheerajgopi/todo-api/task"
+)
+
+// Formats of the files of GET /tasks/export and POST /tasks/import APIs
+const (
+	formatJSON    = "json"
+	formatCSV     = "csv"
+	formatTodoTxt = "todotxt"
+)
+
+// formatContentTypes holds the content type of the exported file of each format
+var formatContentTypes = map[string]string{
+	formatJSON:    "application/json",
+	formatCSV:     "text/csv; charset=utf-8",
+	formatTodoTxt: "text/plain; charset=utf-8",
+}
+
+// formatFileNames holds the name of the exported file of each format
+var formatFileNames = map[string]string{
+	formatJSON:    "tasks.json",
+	formatCSV:     "tasks.csv",
+	formatTodoTxt: "todo.txt",
+}
+
+// csvColumns is the header of exported CSV files. Columns of imported CSV files are matched by name, in any order.
+var csvColumns = []string{"id", "parentId", "title", "description", "project", "tags", "isComplete", "autoComplete", "priority",
+	"dueAt", "dueTimezone", "remindAt", "recurrence", "createdAt"}
+
+// csvTagSeparator separates the tag names of a task in a CSV cell
+const csvTagSeparator = ";"
+
+// todoTxtDateLayout is the layout of dates in todo.txt files
+const todoTxtDateLayout = "2006-01-02"
+
+// todoTxtPriorities holds the todo.txt priority letter of each task priority
+var todoTxtPriorities = map[int]string{
+	task.PriorityUrgent: "A",
+	task.PriorityHigh:   "B",
+	task.PriorityMedium: "C",
+	task.PriorityLow:    "D",
+}
+
+// taskEncoder writes exported tasks to a file, one at a time
+type taskEncoder interface {
+	Encode(task *models.Task) error
+	Close() error
+}
+
+// newTaskEncoder returns the encoder of a format, which writes to the given writer
+func newTaskEncoder(format string, writer io.Writer) taskEncoder {
+	switch format {
+	case formatCSV:
+		return &csvEncoder{writer: csv.NewWriter(writer)}
+	case formatTodoTxt:
+		return &todoTxtEncoder{writer: writer}
+	default:
+		return &jsonEncoder{writer: writer}
+	}
+}
+
+// decodeTasks reads the tasks of an imported file of a format.
+// Tasks which can not be read are reported as errors on their lines, while an error is returned if the file can not be read at all.
+func decodeTasks(format string, reader io.Reader) ([]*PortableTaskData, []*ImportErrorData, error) {
+	switch format {
+	case formatCSV:
+		return decodeCSV(reader)
+	case formatTodoTxt:
+		return decodeTodoTxt(reader)
+	default:
+		return decodeJSON(reader)
+	}
+}
+
+// jsonEncoder writes tasks as the elements of a JSON array
+type jsonEncoder struct {
+	writer io.Writer
+	count  int
+}
+
+// Encode writes a task, opening the array before the first task
+func (encoder *jsonEncoder) Encode(taskModel *models.Task) error {
+	data, err := json.Marshal(newPortableTaskData(taskModel))
+
+	if err != nil {
+		return err
+	}
+
+	separator := ",\n"
+
+	if encoder.count == 0 {
+		separator = "[\n"
+	}
+
+	encoder.count++
+
+	_, err = fmt.Fprintf(encoder.writer, "%s%s", separator, data)
+
+	return err
+}
+
+// Close ends the array, which is empty if no task was written
+func (encoder *jsonEncoder) Close() error {
+	end := "\n]\n"
+
+	if encoder.count == 0 {
+		end = "[]\n"
+	}
+
+	_, err := io.WriteString(encoder.writer, end)
+
+	return err
+}
+
+// decodeJSON reads the tasks of a JSON array. Line of each task is its position in the array.
+func decodeJSON(reader io.Reader) ([]*PortableTaskData, []*ImportErrorData, error) {
+	tasks := make([]*PortableTaskData, 0)
+
+	if err := json.NewDecoder(reader).Decode(&tasks); err != nil {
+		return nil, nil, err
+	}
+
+	for i, taskData := range tasks {
+		if taskData == nil {
+			return nil, nil, fmt.Errorf("task %d is null", i+1)
+		}
+
+		taskData.Line = i + 1
+	}
+
+	return tasks, make([]*ImportErrorData, 0), nil
+}
+
+// csvEncoder writes tasks as the rows of a CSV file, after a header row
+type csvEncoder struct {
+	writer        *csv.Writer
+	headerWritten bool
+}
+
+// Encode writes a task, writing the header before the first task
+func (encoder *csvEncoder) Encode(taskModel *models.Task) error {
+	if err := encoder.writeHeader(); err != nil {
+		return err
+	}
+
+	taskData := newPortableTaskData(taskModel)
+
+	record := []string{
+		strconv.FormatInt(taskData.ID, 10),
+		formatOptionalID(taskData.ParentID),
+		taskData.Title,
+		taskData.Description,
+		taskData.Project,
+		strings.Join(taskData.Tags, csvTagSeparator),
+		strconv.FormatBool(taskData.IsComplete),
+		strconv.FormatBool(taskData.AutoComplete),
+		taskData.Priority,
+		formatOptionalTime(taskData.DueAt),
+		taskData.DueTimezone,
+		formatOptionalTime(taskData.RemindAt),
+		taskData.Recurrence,
+		formatOptionalTime(taskData.CreatedAt),
+	}
+
+	if err := encoder.writer.Write(record); err != nil {
+		return err
+	}
+
+	encoder.writer.Flush()
+
+	return encoder.writer.Error()
+}
+
+// Close writes the header if no task was written
+func (encoder *csvEncoder) Close() error {
+	if err := encoder.writeHeader(); err != nil {
+		return err
+	}
+
+	encoder.writer.Flush()
+
+	return encoder.writer.Error()
+}
+
+func (encoder *csvEncoder) writeHeader() error {
+	if encoder.headerWritten {
+		return nil
+	}
+
+	encoder.headerWritten = true
+
+	return encoder.writer.Write(csvColumns)
+}
+
+// decodeCSV reads the tasks of a CSV file with a header row. Line of each task counts the header as the first line.
+func decodeCSV(reader io.Reader) ([]*PortableTaskData, []*ImportErrorData, error) {
+	csvReader := csv.NewReader(reader)
+	csvReader.FieldsPerRecord = -1
+
+	header, err := csvReader.Read()
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	columns := make(map[string]int)
+
+	for i, name := range header {
+		columns[strings.TrimSpace(name)] = i
+	}
+
+	if _, ok := columns["title"]; !ok {
+		return nil, nil, fmt.Errorf("title column is missing")
+	}
+
+	tasks := make([]*PortableTaskData, 0)
+	decodeErrors := make([]*ImportErrorData, 0)
+	line := 1
+
+	for {
+		record, err := csvReader.Read()
+
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		line++
+
+		cell := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+
+			return ""
+		}
+
+		taskData, decodeError := decodeCSVRecord(cell)
+
+		if decodeError != nil {
+			decodeError.Line = line
+			decodeErrors = append(decodeErrors, decodeError)
+			continue
+		}
+
+		taskData.Line = line
+		tasks = append(tasks, taskData)
+	}
+
+	return tasks, decodeErrors, nil
+}
+
+// decodeCSVRecord reads a task from the cells of a CSV row, or returns the first cell with an invalid value
+func decodeCSVRecord(cell func(name string) string) (*PortableTaskData, *ImportErrorData) {
+	taskData := &PortableTaskData{
+		Title:       cell("title"),
+		Description: cell("description"),
+		Project:     cell("project"),
+		Priority:    cell("priority"),
+		DueTimezone: cell("dueTimezone"),
+		Recurrence:  cell("recurrence"),
+		Tags:        make([]string, 0),
+	}
+
+	if tags := cell("tags"); tags != "" {
+		taskData.Tags = strings.Split(tags, csvTagSeparator)
+	}
+
+	var err error
+
+	if taskData.ID, err = parseOptionalInt64(cell("id")); err != nil {
+		return nil, newInvalidValueError("id")
+	}
+
+	if parentID, err := parseOptionalInt64(cell("parentId")); err != nil {
+		return nil, newInvalidValueError("parentId")
+	} else if parentID != 0 {
+		taskData.ParentID = &parentID
+	}
+
+	if taskData.IsComplete, err = parseOptionalBool(cell("isComplete")); err != nil {
+		return nil, newInvalidValueError("isComplete")
+	}
+
+	if taskData.AutoComplete, err = parseOptionalBool(cell("autoComplete")); err != nil {
+		return nil, newInvalidValueError("autoComplete")
+	}
+
+	if taskData.DueAt, err = parseOptionalTime(cell("dueAt")); err != nil {
+		return nil, newInvalidValueError("dueAt")
+	}
+
+	if taskData.RemindAt, err = parseOptionalTime(cell("remindAt")); err != nil {
+		return nil, newInvalidValueError("remindAt")
+	}
+
+	if taskData.CreatedAt, err = parseOptionalTime(cell("createdAt")); err != nil {
+		return nil, newInvalidValueError("createdAt")
+	}
+
+	return taskData, nil
+}
+
+// todoTxtEncoder writes tasks as the lines of a todo.txt file.
+// Descriptions, reminders and the time of due dates are left out, since the format has no place for them.
+// Spaces in project and tag names are replaced with underscores.
+type todoTxtEncoder struct {
+	writer io.Writer
+}
+
+// Encode writes a task as a line like "x (A) 2019-10-20 Title +Project @tag due:2019-10-30 id:2 parent:1"
+func (encoder *todoTxtEncoder) Encode(taskModel *models.Task) error {
+	parts := make([]string, 0)
+	priority := todoTxtPriorities[taskModel.Priority]
+
+	if taskModel.IsComplete {
+		parts = append(parts, "x", taskModel.UpdatedAt.Format(todoTxtDateLayout))
+	} else if priority != "" {
+		parts = append(parts, "("+priority+")")
+	}
+
+	parts = append(parts, taskModel.CreatedAt.Format(todoTxtDateLayout), strings.Join(strings.Fields(taskModel.Title), " "))
+
+	if taskModel.Project != nil {
+		parts = append(parts, "+"+todoTxtName(taskModel.Project.Name))
+	}
+
+	for _, tag := range taskModel.Tags {
+		parts = append(parts, "@"+todoTxtName(tag.Name))
+	}
+
+	if taskModel.DueAt != nil {
+		dueAt := *taskModel.DueAt
+
+		if location, err := time.LoadLocation(taskModel.DueTimezone); err == nil {
+			dueAt = dueAt.In(location)
+		}
+
+		parts = append(parts, "due:"+dueAt.Format(todoTxtDateLayout))
+	}
+
+	if taskModel.Recurrence != "" {
+		parts = append(parts, "rec:"+taskModel.Recurrence)
+	}
+
+	if taskModel.IsComplete && priority != "" {
+		parts = append(parts, "pri:"+priority)
+	}
+
+	parts = append(parts, "id:"+strconv.FormatInt(taskModel.ID, 10))
+
+	if taskModel.Parent != nil {
+		parts = append(parts, "parent:"+strconv.FormatInt(taskModel.Parent.ID, 10))
+	}
+
+	_, err := io.WriteString(encoder.writer, strings.Join(parts, " ")+"\n")
+
+	return err
+}
+
+// Close does nothing, since todo.txt files have no header or footer
+func (encoder *todoTxtEncoder) Close() error {
+	return nil
+}
+
+// todoTxtName replaces the spaces in a project or tag name with underscores
+func todoTxtName(name string) string {
+	return strings.Join(strings.Fields(name), "_")
+}
+
+// decodeTodoTxt reads the tasks of a todo.txt file, skipping blank lines
+func decodeTodoTxt(reader io.Reader) ([]*PortableTaskData, []*ImportErrorData, error) {
+	scanner := bufio.NewScanner(reader)
+	tasks := make([]*PortableTaskData, 0)
+	decodeErrors := make([]*ImportErrorData, 0)
+	line := 0
+
+	for scanner.Scan() {
+		line++
+
+		fields := strings.Fields(scanner.Text())
+
+		if len(fields) == 0 {
+			continue
+		}
+
+		taskData, decodeError := decodeTodoTxtLine(fields)
+
+		if decodeError != nil {
+			decodeError.Line = line
+			decodeErrors = append(decodeErrors, decodeError)
+			continue
+		}
+
+		taskData.Line = line
+		tasks = append(tasks, taskData)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	return tasks, decodeErrors, nil
+}
+
+// decodeTodoTxtLine reads a task from the words of a todo.txt line.
+// Completion mark, priority and creation date are read from the start of the line, and the due date, recurrence,
+// priority of completed tasks and the subtask links are read from the due, rec, pri, id and parent keys.
+// Underscores in project and tag names are read as spaces.
+func decodeTodoTxtLine(fields []string) (*PortableTaskData, *ImportErrorData) {
+	taskData := &PortableTaskData{
+		Tags: make([]string, 0),
+	}
+
+	if fields[0] == "x" {
+		taskData.IsComplete = true
+		fields = fields[1:]
+
+		if len(fields) > 0 && isTodoTxtDate(fields[0]) {
+			fields = fields[1:]
+		}
+	} else if priority, ok := parseTodoTxtPriority(fields[0]); ok {
+		taskData.Priority = priority
+		fields = fields[1:]
+	}
+
+	if len(fields) > 0 && isTodoTxtDate(fields[0]) {
+		createdAt, _ := time.Parse(todoTxtDateLayout, fields[0])
+		taskData.CreatedAt = &createdAt
+		fields = fields[1:]
+	}
+
+	words := make([]string, 0, len(fields))
+
+	for _, field := range fields {
+		switch {
+		case len(field) > 1 && strings.HasPrefix(field, "+"):
+			if taskData.Project != "" {
+				return nil, &ImportErrorData{
+					Message: "Only one project is allowed",
+					Target:  "project",
+				}
+			}
+
+			taskData.Project = strings.Replace(field[1:], "_", " ", -1)
+		case len(field) > 1 && strings.HasPrefix(field, "@"):
+			taskData.Tags = append(taskData.Tags, strings.Replace(field[1:], "_", " ", -1))
+		case strings.HasPrefix(field, "due:"):
+			dueAt, err := time.Parse(todoTxtDateLayout, field[len("due:"):])
+
+			if err != nil {
+				return nil, newInvalidValueError("dueAt")
+			}
+
+			taskData.DueAt = &dueAt
+		case strings.HasPrefix(field, "rec:"):
+			taskData.Recurrence = field[len("rec:"):]
+		case strings.HasPrefix(field, "pri:"):
+			priority, ok := parseTodoTxtPriority("(" + field[len("pri:"):] + ")")
+
+			if !ok {
+				return nil, newInvalidValueError("priority")
+			}
+
+			taskData.Priority = priority
+		case strings.HasPrefix(field, "id:"):
+			id, err := strconv.ParseInt(field[len("id:"):], 10, 64)
+
+			if err != nil {
+				return nil, newInvalidValueError("id")
+			}
+
+			taskData.ID = id
+		case strings.HasPrefix(field, "parent:"):
+			parentID, err := strconv.ParseInt(field[len("parent:"):], 10, 64)
+
+			if err != nil {
+				return nil, newInvalidValueError("parentId")
+			}
+
+			taskData.ParentID = &parentID
+		default:
+			words = append(words, field)
+		}
+	}
+
+	taskData.Title = strings.Join(words, " ")
+
+	return taskData, nil
+}
+
+// parseTodoTxtPriority reads a priority like (A). Letters after D are read as the low priority.
+func parseTodoTxtPriority(field string) (string, bool) {
+	if len(field) != 3 || field[0] != '(' || field[2] != ')' || field[1] < 'A' || field[1] > 'Z' {
+		return "", false
+	}
+
+	for priority, letter := range todoTxtPriorities {
+		if letter == field[1:2] {
+			return task.PriorityName(priority), true
+		}
+	}
+
+	return task.PriorityName(task.PriorityLow), true
+}
+
+// isTodoTxtDate checks whether a word is a date like 2019-10-20
+func isTodoTxtDate(field string) bool {
+	_, err := time.Parse(todoTxtDateLayout, field)
+
+	return err == nil
+}
+
+// newInvalidValueError reports a value which could not be read from an imported file
+func newInvalidValueError(target string) *ImportErrorData {
+	return &ImportErrorData{
+		Message: "Invalid value",
+		Target:  target,
+	}
+}
+
+func formatOptionalID(id *int64) string {
+	if id == nil {
+		return ""
+	}
+
+	return strconv.FormatInt(*id, 10)
+}
+
+func formatOptionalTime(value *time.Time) string {
+	if value == nil {
+		return ""
+	}
+
+	return value.Format(time.RFC3339)
+}
+
+func parseOptionalInt64(value string) (int64, error) {
+	if value == "" {
+		return 0, nil
+	}
+
+	return strconv.ParseInt(value, 10, 64)
+}
+
+func parseOptionalBool(value string) (bool, error) {
+	if value == "" {
+		return false, nil
+	}
+
+	return strconv.ParseBool(value)
+}
+
+func parseOptionalTime(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	parsed, err := time.Parse(time.RFC3339, value)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &parsed, nil
+}
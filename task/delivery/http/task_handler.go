@@ -3,17 +3,26 @@ package http
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"io"
 	"net/http"
+	"sort"
+	"strconv"
 	"time"
 
+	"github.com/dheerajgopi/todo-api/auth"
 	"github.com/dheerajgopi/todo-api/common"
 	todoErr "github.com/dheerajgopi/todo-api/common/error"
 	"github.com/dheerajgopi/todo-api/common/middlewares"
+	"github.com/dheerajgopi/todo-api/history"
 	"github.com/dheerajgopi/todo-api/models"
 	"github.com/dheerajgopi/todo-api/task"
 	"github.com/gorilla/mux"
 )
 
+// maxImportSize is the maximum size of the file of POST /tasks/import API, in bytes
+const maxImportSize = 10 << 20
+
 // TaskHandler represents HTTP handler for tasks
 type TaskHandler struct {
 	TaskService task.Service
@@ -27,14 +36,43 @@ func New(router *mux.Router, service task.Service, app *common.App) {
 		App:         app,
 	}
 
-	jwtMiddleware := middlewares.JwtValidator(app.Config.Auth.Jwt.Secret)
+	// API keys are accepted besides access tokens, as far as they grant the scope
+	readMiddleware := middlewares.ScopedValidator(app, auth.ScopeTasksRead)
+	writeMiddleware := middlewares.ScopedValidator(app, auth.ScopeTasksWrite)
 
-	router.HandleFunc("/tasks", app.CreateHandler(jwtMiddleware(handler.Create))).Methods("POST")
-	router.HandleFunc("/tasks", app.CreateHandler(jwtMiddleware(handler.List))).Methods("GET")
+	router.HandleFunc("/tasks", app.CreateHandler(writeMiddleware(handler.Create))).Methods("POST")
+	router.HandleFunc("/tasks", app.CreateHandler(readMiddleware(handler.List))).Methods("GET")
+	router.HandleFunc("/tasks/export", app.CreateHandler(readMiddleware(handler.Export))).Methods("GET")
+	router.HandleFunc("/tasks/import", app.CreateHandler(writeMiddleware(handler.Import))).Methods("POST")
+	router.HandleFunc("/tasks/bulk", app.CreateHandler(writeMiddleware(handler.Bulk))).Methods("POST")
+	router.HandleFunc("/tasks/{id:[0-9]+}", app.CreateHandler(readMiddleware(handler.Get))).Methods("GET")
+	router.HandleFunc("/tasks/{id:[0-9]+}", app.CreateHandler(writeMiddleware(handler.Update))).Methods("PUT")
+	router.HandleFunc("/tasks/{id:[0-9]+}", app.CreateHandler(writeMiddleware(handler.Patch))).Methods("PATCH")
+	router.HandleFunc("/tasks/{id:[0-9]+}", app.CreateHandler(writeMiddleware(handler.Delete))).Methods("DELETE")
+	router.HandleFunc("/tasks/{id:[0-9]+}/move", app.CreateHandler(writeMiddleware(handler.Move))).Methods("POST")
+	router.HandleFunc("/tasks/{id:[0-9]+}/subtasks", app.CreateHandler(writeMiddleware(handler.CreateSubtask))).Methods("POST")
+	router.HandleFunc("/tasks/{id:[0-9]+}/subtasks", app.CreateHandler(readMiddleware(handler.ListSubtasks))).Methods("GET")
+	router.HandleFunc("/projects/{id:[0-9]+}/tasks", app.CreateHandler(readMiddleware(handler.ListByProject))).Methods("GET")
 }
 
 // Create will store new task
 func (handler *TaskHandler) Create(res http.ResponseWriter, req *http.Request, reqCtx *common.RequestContext) (int, interface{}, *todoErr.APIError) {
+	return handler.create(req, reqCtx, nil)
+}
+
+// CreateSubtask will store new subtask under a task
+func (handler *TaskHandler) CreateSubtask(res http.ResponseWriter, req *http.Request, reqCtx *common.RequestContext) (int, interface{}, *todoErr.APIError) {
+	parentID, apiError := parseTaskID(req)
+
+	if apiError != nil {
+		return http.StatusBadRequest, nil, apiError
+	}
+
+	return handler.create(req, reqCtx, &models.Task{ID: parentID})
+}
+
+// create stores new task from the request body, as a subtask if a parent is given
+func (handler *TaskHandler) create(req *http.Request, reqCtx *common.RequestContext, parent *models.Task) (int, interface{}, *todoErr.APIError) {
 	defer req.Body.Close()
 
 	decoder := json.NewDecoder(req.Body)
@@ -58,10 +96,20 @@ func (handler *TaskHandler) Create(res http.ResponseWriter, req *http.Request, r
 	}
 
 	now := time.Now()
+	priority, _ := task.ParsePriority(createTaskReqBody.Priority)
 
 	newTask := &models.Task{
-		Title:       createTaskReqBody.Title,
-		Description: createTaskReqBody.Description,
+		Title:        createTaskReqBody.Title,
+		Description:  createTaskReqBody.Description,
+		Project:      newProjectRef(createTaskReqBody.ProjectID),
+		Parent:       parent,
+		AutoComplete: createTaskReqBody.AutoComplete,
+		Priority:     priority,
+		DueAt:        createTaskReqBody.DueAt,
+		DueTimezone:  createTaskReqBody.DueTimezone,
+		RemindAt:     createTaskReqBody.RemindAt,
+		Recurrence:   createTaskReqBody.Recurrence,
+		Tags:         newTagRefs(createTaskReqBody.Tags),
 		CreatedBy: &models.User{
 			ID: reqCtx.UserID,
 		},
@@ -70,64 +118,604 @@ func (handler *TaskHandler) Create(res http.ResponseWriter, req *http.Request, r
 		UpdatedAt:  now,
 	}
 
-	switch err = handler.TaskService.Create(context.TODO(), newTask); err.(type) {
-	case nil:
-		break
-	default:
+	if err = handler.TaskService.Create(actorContext(reqCtx), newTask); err != nil {
+		return taskServiceError(err)
+	}
+
+	responseData := &CreateTaskResponse{
+		Task: newTaskData(newTask),
+	}
+
+	return http.StatusCreated, responseData, nil
+}
+
+// List will return a page of tasks matching the query parameters
+func (handler *TaskHandler) List(res http.ResponseWriter, req *http.Request, reqCtx *common.RequestContext) (int, interface{}, *todoErr.APIError) {
+	filter, validationErrors := NewListTaskRequest(req.URL.Query()).ValidateAndBuild()
+
+	if len(validationErrors) > 0 {
+		apiError := todoErr.NewAPIError("", validationErrors...)
+
+		return http.StatusBadRequest, nil, apiError
+	}
+
+	return handler.list(filter, reqCtx)
+}
+
+// ListByProject will return a page of tasks in a project, matching the query parameters
+func (handler *TaskHandler) ListByProject(res http.ResponseWriter, req *http.Request, reqCtx *common.RequestContext) (int, interface{}, *todoErr.APIError) {
+	projectID, err := strconv.ParseInt(mux.Vars(req)["id"], 10, 64)
+
+	if err != nil {
 		apiError := todoErr.NewAPIError(err.Error(), &todoErr.APIErrorBody{
-			Message: "Internal server error",
+			Message: "Invalid value",
+			Target:  "id",
 		})
 
-		return http.StatusInternalServerError, nil, apiError
+		return http.StatusBadRequest, nil, apiError
 	}
 
-	taskData := &TaskData{
-		ID:          newTask.ID,
-		Title:       newTask.Title,
-		Description: newTask.Description,
-		IsComplete:  newTask.IsComplete,
-		CreatedAt:   newTask.CreatedAt,
-		UpdatedAt:   newTask.UpdatedAt,
-	}
+	filter, validationErrors := NewListTaskRequest(req.URL.Query()).ValidateAndBuild()
 
-	responseData := &CreateTaskResponse{
-		Task: taskData,
+	if len(validationErrors) > 0 {
+		apiError := todoErr.NewAPIError("", validationErrors...)
+
+		return http.StatusBadRequest, nil, apiError
 	}
 
-	return http.StatusCreated, responseData, nil
+	filter.ProjectID = &projectID
+	filter.Inbox = false
+
+	return handler.list(filter, reqCtx)
 }
 
-// List will return all tasks
-func (handler *TaskHandler) List(res http.ResponseWriter, req *http.Request, reqCtx *common.RequestContext) (int, interface{}, *todoErr.APIError) {
+// list fetches a page of tasks of the user and builds the list response
+func (handler *TaskHandler) list(filter *task.ListFilter, reqCtx *common.RequestContext) (int, interface{}, *todoErr.APIError) {
 	taskList := make([]*TaskData, 0)
 
-	tasksByUserID, err := handler.TaskService.List(context.TODO(), reqCtx.UserID)
+	page, err := handler.TaskService.List(context.TODO(), reqCtx.UserID, filter)
 
-	switch err {
-	case nil:
-		break
-	default:
+	if err != nil {
+		return taskServiceError(err)
+	}
+
+	for _, task := range page.Tasks {
+		taskList = append(taskList, newTaskData(task))
+	}
+
+	responseData := &ListTaskResponse{
+		Tasks: taskList,
+		Pagination: &PaginationData{
+			NextCursor: page.NextCursor,
+			HasMore:    page.HasMore,
+		},
+	}
+
+	return http.StatusOK, responseData, nil
+}
+
+// Get will return a single task
+func (handler *TaskHandler) Get(res http.ResponseWriter, req *http.Request, reqCtx *common.RequestContext) (int, interface{}, *todoErr.APIError) {
+	taskID, apiError := parseTaskID(req)
+
+	if apiError != nil {
+		return http.StatusBadRequest, nil, apiError
+	}
+
+	existingTask, err := handler.TaskService.GetByID(context.TODO(), taskID, reqCtx.UserID)
+
+	if err != nil {
+		return taskServiceError(err)
+	}
+
+	responseData := &GetTaskResponse{
+		Task: newTaskData(existingTask),
+	}
+
+	return http.StatusOK, responseData, nil
+}
+
+// ListSubtasks will return the subtasks of a task
+func (handler *TaskHandler) ListSubtasks(res http.ResponseWriter, req *http.Request, reqCtx *common.RequestContext) (int, interface{}, *todoErr.APIError) {
+	taskID, apiError := parseTaskID(req)
+
+	if apiError != nil {
+		return http.StatusBadRequest, nil, apiError
+	}
+
+	subtasks, err := handler.TaskService.ListSubtasks(context.TODO(), taskID, reqCtx.UserID)
+
+	if err != nil {
+		return taskServiceError(err)
+	}
+
+	subtaskList := make([]*TaskData, 0)
+
+	for _, subtask := range subtasks {
+		subtaskList = append(subtaskList, newTaskData(subtask))
+	}
+
+	responseData := &ListSubtaskResponse{
+		Subtasks: subtaskList,
+	}
+
+	return http.StatusOK, responseData, nil
+}
+
+// Update will overwrite all editable fields of a task
+func (handler *TaskHandler) Update(res http.ResponseWriter, req *http.Request, reqCtx *common.RequestContext) (int, interface{}, *todoErr.APIError) {
+	defer req.Body.Close()
+
+	taskID, apiError := parseTaskID(req)
+
+	if apiError != nil {
+		return http.StatusBadRequest, nil, apiError
+	}
+
+	decoder := json.NewDecoder(req.Body)
+	var updateTaskReqBody UpdateTaskRequest
+	err := decoder.Decode(&updateTaskReqBody)
+
+	if err != nil {
+		apiError := todoErr.NewAPIError("", &todoErr.APIErrorBody{
+			Message: "Invalid request body",
+		})
+
+		return http.StatusBadRequest, nil, apiError
+	}
+
+	validationErrors := updateTaskReqBody.ValidateAndBuild()
+
+	if len(validationErrors) > 0 {
+		apiError := todoErr.NewAPIError("", validationErrors...)
+
+		return http.StatusBadRequest, nil, apiError
+	}
+
+	priority, _ := task.ParsePriority(updateTaskReqBody.Priority)
+
+	updatedTask := &models.Task{
+		ID:           taskID,
+		Title:        updateTaskReqBody.Title,
+		Description:  updateTaskReqBody.Description,
+		Project:      newProjectRef(updateTaskReqBody.ProjectID),
+		IsComplete:   updateTaskReqBody.IsComplete,
+		AutoComplete: updateTaskReqBody.AutoComplete,
+		Priority:     priority,
+		DueAt:        updateTaskReqBody.DueAt,
+		DueTimezone:  updateTaskReqBody.DueTimezone,
+		RemindAt:     updateTaskReqBody.RemindAt,
+		Recurrence:   updateTaskReqBody.Recurrence,
+		Tags:         newTagRefs(updateTaskReqBody.Tags),
+		UpdatedAt:    time.Now(),
+	}
+
+	if err = handler.TaskService.Update(actorContext(reqCtx), updatedTask, reqCtx.UserID); err != nil {
+		return taskServiceError(err)
+	}
+
+	responseData := &UpdateTaskResponse{
+		Task: newTaskData(updatedTask),
+	}
+
+	return http.StatusOK, responseData, nil
+}
+
+// Patch will update only the fields of a task which are present in the request
+func (handler *TaskHandler) Patch(res http.ResponseWriter, req *http.Request, reqCtx *common.RequestContext) (int, interface{}, *todoErr.APIError) {
+	defer req.Body.Close()
+
+	taskID, apiError := parseTaskID(req)
+
+	if apiError != nil {
+		return http.StatusBadRequest, nil, apiError
+	}
+
+	decoder := json.NewDecoder(req.Body)
+	var patchTaskReqBody PatchTaskRequest
+	err := decoder.Decode(&patchTaskReqBody)
+
+	if err != nil {
+		apiError := todoErr.NewAPIError("", &todoErr.APIErrorBody{
+			Message: "Invalid request body",
+		})
+
+		return http.StatusBadRequest, nil, apiError
+	}
+
+	validationErrors := patchTaskReqBody.ValidateAndBuild()
+
+	if len(validationErrors) > 0 {
+		apiError := todoErr.NewAPIError("", validationErrors...)
+
+		return http.StatusBadRequest, nil, apiError
+	}
+
+	existingTask, err := handler.TaskService.GetByID(context.TODO(), taskID, reqCtx.UserID)
+
+	if err != nil {
+		return taskServiceError(err)
+	}
+
+	if patchTaskReqBody.Title != nil {
+		existingTask.Title = *patchTaskReqBody.Title
+	}
+
+	if patchTaskReqBody.Description != nil {
+		existingTask.Description = *patchTaskReqBody.Description
+	}
+
+	if patchTaskReqBody.ProjectID.Set {
+		existingTask.Project = newProjectRef(patchTaskReqBody.ProjectID.Value)
+	}
+
+	if patchTaskReqBody.IsComplete != nil {
+		existingTask.IsComplete = *patchTaskReqBody.IsComplete
+	}
+
+	if patchTaskReqBody.AutoComplete != nil {
+		existingTask.AutoComplete = *patchTaskReqBody.AutoComplete
+	}
+
+	if patchTaskReqBody.Priority != nil {
+		existingTask.Priority, _ = task.ParsePriority(*patchTaskReqBody.Priority)
+	}
+
+	if patchTaskReqBody.DueAt.Set {
+		existingTask.DueAt = patchTaskReqBody.DueAt.Value
+	}
+
+	if patchTaskReqBody.DueTimezone != nil {
+		existingTask.DueTimezone = *patchTaskReqBody.DueTimezone
+	}
+
+	if existingTask.DueAt == nil {
+		existingTask.DueTimezone = ""
+	} else if existingTask.DueTimezone == "" {
+		existingTask.DueTimezone = "UTC"
+	}
+
+	if patchTaskReqBody.RemindAt.Set {
+		existingTask.RemindAt = patchTaskReqBody.RemindAt.Value
+	}
+
+	if patchTaskReqBody.Recurrence != nil {
+		existingTask.Recurrence = *patchTaskReqBody.Recurrence
+	}
+
+	if patchTaskReqBody.Tags != nil {
+		existingTask.Tags = newTagRefs(*patchTaskReqBody.Tags)
+	}
+
+	existingTask.UpdatedAt = time.Now()
+
+	if err = handler.TaskService.Update(actorContext(reqCtx), existingTask, reqCtx.UserID); err != nil {
+		return taskServiceError(err)
+	}
+
+	responseData := &UpdateTaskResponse{
+		Task: newTaskData(existingTask),
+	}
+
+	return http.StatusOK, responseData, nil
+}
+
+// Delete will move a task to trash
+func (handler *TaskHandler) Delete(res http.ResponseWriter, req *http.Request, reqCtx *common.RequestContext) (int, interface{}, *todoErr.APIError) {
+	taskID, apiError := parseTaskID(req)
+
+	if apiError != nil {
+		return http.StatusBadRequest, nil, apiError
+	}
+
+	if err := handler.TaskService.Delete(actorContext(reqCtx), taskID, reqCtx.UserID); err != nil {
+		return taskServiceError(err)
+	}
+
+	return http.StatusOK, nil, nil
+}
+
+// Bulk will apply a list of operations to many tasks at once, and return the outcome for each task
+func (handler *TaskHandler) Bulk(res http.ResponseWriter, req *http.Request, reqCtx *common.RequestContext) (int, interface{}, *todoErr.APIError) {
+	defer req.Body.Close()
+
+	decoder := json.NewDecoder(req.Body)
+	var bulkTaskReqBody BulkTaskRequest
+	err := decoder.Decode(&bulkTaskReqBody)
+
+	if err != nil {
+		apiError := todoErr.NewAPIError("", &todoErr.APIErrorBody{
+			Message: "Invalid request body",
+		})
+
+		return http.StatusBadRequest, nil, apiError
+	}
+
+	change, validationErrors := bulkTaskReqBody.ValidateAndBuild()
+
+	if len(validationErrors) > 0 {
+		apiError := todoErr.NewAPIError("", validationErrors...)
+
+		return http.StatusBadRequest, nil, apiError
+	}
+
+	results, err := handler.TaskService.Bulk(actorContext(reqCtx), change, reqCtx.UserID)
+
+	if err != nil {
+		return taskServiceError(err)
+	}
+
+	responseData := &BulkTaskResponse{
+		Results: make([]*BulkResultData, 0, len(results)),
+	}
+
+	for _, result := range results {
+		resultData := &BulkResultData{
+			TaskID: result.TaskID,
+			Status: result.Status,
+		}
+
+		switch result.Status {
+		case task.BulkSucceeded:
+			responseData.Succeeded++
+		case task.BulkFailed:
+			code, _, apiError := taskServiceError(result.Err)
+
+			if code == http.StatusInternalServerError {
+				reqCtx.LogEntry.WithField("taskId", result.TaskID).Error(apiError.Message)
+			}
+
+			resultData.Code = code
+			resultData.Error = apiError.Body[0]
+			responseData.Failed++
+		}
+
+		responseData.Results = append(responseData.Results, resultData)
+	}
+
+	return http.StatusOK, responseData, nil
+}
+
+// Export will stream all tasks of the user as a file of the requested format
+func (handler *TaskHandler) Export(res http.ResponseWriter, req *http.Request, reqCtx *common.RequestContext) (int, interface{}, *todoErr.APIError) {
+	format, _, validationErrors := NewTransferTaskRequest(req.URL.Query()).ValidateAndBuild()
+
+	if len(validationErrors) > 0 {
+		apiError := todoErr.NewAPIError("", validationErrors...)
+
+		return http.StatusBadRequest, nil, apiError
+	}
+
+	reader, writer := io.Pipe()
+	encoder := newTaskEncoder(format, writer)
+
+	go func() {
+		err := handler.TaskService.Export(req.Context(), reqCtx.UserID, encoder.Encode)
+
+		if err == nil {
+			err = encoder.Close()
+		}
+
+		if err != nil && err != io.ErrClosedPipe {
+			reqCtx.LogEntry.WithError(err).Error("Error exporting tasks")
+		}
+
+		writer.CloseWithError(err)
+	}()
+
+	responseData := &common.RawResponse{
+		ContentType: formatContentTypes[format],
+		Headers: map[string]string{
+			"Content-Disposition": fmt.Sprintf(`attachment; filename="%s"`, formatFileNames[format]),
+		},
+		Body: reader,
+	}
+
+	return http.StatusOK, responseData, nil
+}
+
+// Import will create tasks from a file of the requested format, and report the tasks which could not be imported.
+// Nothing is imported if any task fails, or if it is a dry run.
+func (handler *TaskHandler) Import(res http.ResponseWriter, req *http.Request, reqCtx *common.RequestContext) (int, interface{}, *todoErr.APIError) {
+	defer req.Body.Close()
+
+	format, dryRun, validationErrors := NewTransferTaskRequest(req.URL.Query()).ValidateAndBuild()
+
+	if len(validationErrors) > 0 {
+		apiError := todoErr.NewAPIError("", validationErrors...)
+
+		return http.StatusBadRequest, nil, apiError
+	}
+
+	taskData, importErrors, err := decodeTasks(format, http.MaxBytesReader(res, req.Body, maxImportSize))
+
+	if err != nil {
 		apiError := todoErr.NewAPIError(err.Error(), &todoErr.APIErrorBody{
-			Message: "Internal server error",
+			Message: "Invalid request body",
 		})
 
-		return http.StatusInternalServerError, nil, apiError
+		return http.StatusBadRequest, nil, apiError
 	}
 
-	for _, task := range tasksByUserID {
-		taskList = append(taskList, &TaskData{
-			ID:          task.ID,
-			Title:       task.Title,
-			Description: task.Description,
-			IsComplete:  task.IsComplete,
-			CreatedAt:   task.CreatedAt,
-			UpdatedAt:   task.UpdatedAt,
+	total := len(taskData) + len(importErrors)
+
+	if total == 0 || total > task.MaxImportTasks {
+		apiError := todoErr.NewAPIError("", &todoErr.APIErrorBody{
+			Message: "File should have between 1 and " + strconv.Itoa(task.MaxImportTasks) + " tasks",
 		})
+
+		return http.StatusBadRequest, nil, apiError
 	}
 
-	responseData := &ListTaskResponse{
-		Tasks: taskList,
+	importTaskReqBody := &ImportTaskRequest{
+		Tasks: taskData,
+	}
+
+	newTasks, lineErrors := importTaskReqBody.ValidateAndBuild()
+	importErrors = append(importErrors, lineErrors...)
+
+	responseData := &ImportTaskResponse{
+		DryRun: dryRun,
+		Total:  total,
+		Errors: importErrors,
+	}
+
+	if len(importErrors) > 0 {
+		sort.SliceStable(importErrors, func(i, j int) bool {
+			return importErrors[i].Line < importErrors[j].Line
+		})
+
+		return http.StatusOK, responseData, nil
+	}
+
+	now := time.Now()
+
+	for _, newTask := range newTasks {
+		newTask.CreatedBy = &models.User{
+			ID: reqCtx.UserID,
+		}
+
+		if newTask.CreatedAt.IsZero() {
+			newTask.CreatedAt = now
+		}
+
+		newTask.UpdatedAt = now
+	}
+
+	failures, err := handler.TaskService.Import(actorContext(reqCtx), newTasks, reqCtx.UserID, dryRun)
+
+	if err != nil {
+		return taskServiceError(err)
+	}
+
+	for _, failure := range failures {
+		code, _, apiError := taskServiceError(failure.Err)
+		line := taskData[failure.Index].Line
+
+		if code == http.StatusInternalServerError {
+			reqCtx.LogEntry.WithField("line", line).Error(apiError.Message)
+		}
+
+		responseData.Errors = append(responseData.Errors, &ImportErrorData{
+			Line:    line,
+			Message: apiError.Body[0].Message,
+			Target:  apiError.Body[0].Target,
+		})
+	}
+
+	if len(failures) == 0 && !dryRun {
+		responseData.Imported = len(newTasks)
+	}
+
+	return http.StatusOK, responseData, nil
+}
+
+// Move will place a task before or after another task in the manual ordering
+func (handler *TaskHandler) Move(res http.ResponseWriter, req *http.Request, reqCtx *common.RequestContext) (int, interface{}, *todoErr.APIError) {
+	defer req.Body.Close()
+
+	taskID, apiError := parseTaskID(req)
+
+	if apiError != nil {
+		return http.StatusBadRequest, nil, apiError
+	}
+
+	decoder := json.NewDecoder(req.Body)
+	var moveTaskReqBody MoveTaskRequest
+	err := decoder.Decode(&moveTaskReqBody)
+
+	if err != nil {
+		apiError := todoErr.NewAPIError("", &todoErr.APIErrorBody{
+			Message: "Invalid request body",
+		})
+
+		return http.StatusBadRequest, nil, apiError
+	}
+
+	validationErrors := moveTaskReqBody.ValidateAndBuild()
+
+	if len(validationErrors) > 0 {
+		apiError := todoErr.NewAPIError("", validationErrors...)
+
+		return http.StatusBadRequest, nil, apiError
+	}
+
+	anchorID, before := moveTaskReqBody.AfterID, false
+
+	if moveTaskReqBody.BeforeID != nil {
+		anchorID, before = moveTaskReqBody.BeforeID, true
+	}
+
+	movedTask, err := handler.TaskService.Move(actorContext(reqCtx), taskID, *anchorID, before, reqCtx.UserID)
+
+	if err != nil {
+		return taskServiceError(err)
+	}
+
+	responseData := &UpdateTaskResponse{
+		Task: newTaskData(movedTask),
 	}
 
 	return http.StatusOK, responseData, nil
 }
+
+// actorContext returns a context carrying the user and the request, for recording them in the task history
+func actorContext(reqCtx *common.RequestContext) context.Context {
+	return history.WithActor(context.TODO(), &history.Actor{
+		UserID:    reqCtx.UserID,
+		RequestID: reqCtx.RequestID,
+	})
+}
+
+// parseTaskID reads the task id from the request path
+func parseTaskID(req *http.Request) (int64, *todoErr.APIError) {
+	taskID, err := strconv.ParseInt(mux.Vars(req)["id"], 10, 64)
+
+	if err != nil {
+		return 0, todoErr.NewAPIError(err.Error(), &todoErr.APIErrorBody{
+			Message: "Invalid value",
+			Target:  "id",
+		})
+	}
+
+	return taskID, nil
+}
+
+// taskServiceError maps errors returned by the task service to the API response
+func taskServiceError(err error) (int, interface{}, *todoErr.APIError) {
+	switch err.(type) {
+	case *todoErr.ResourceNotFoundError:
+		resourceNotFoundErr, _ := err.(*todoErr.ResourceNotFoundError)
+
+		apiError := todoErr.NewAPIError(resourceNotFoundErr.Error(), &todoErr.APIErrorBody{
+			Message: "Not found",
+			Target:  resourceNotFoundErr.Resource,
+		})
+
+		return http.StatusNotFound, nil, apiError
+	case *todoErr.PermissionDeniedError:
+		permissionDeniedErr, _ := err.(*todoErr.PermissionDeniedError)
+
+		apiError := todoErr.NewAPIError(permissionDeniedErr.Error(), &todoErr.APIErrorBody{
+			Message: "Permission denied",
+			Target:  permissionDeniedErr.Resource,
+		})
+
+		return http.StatusForbidden, nil, apiError
+	case *todoErr.InvalidValueError:
+		invalidValueErr, _ := err.(*todoErr.InvalidValueError)
+
+		apiError := todoErr.NewAPIError(invalidValueErr.Error(), &todoErr.APIErrorBody{
+			Message: invalidValueErr.Reason,
+			Target:  invalidValueErr.Field,
+		})
+
+		return http.StatusBadRequest, nil, apiError
+	default:
+		apiError := todoErr.NewAPIError(err.Error(), &todoErr.APIErrorBody{
+			Message: "Internal server error",
+		})
+
+		return http.StatusInternalServerError, nil, apiError
+	}
+}
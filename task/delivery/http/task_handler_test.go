@@ -3,18 +3,21 @@ package http_test
 import (
 	"encoding/json"
 	"errors"
+	"io/ioutil"
 	"net/http/httptest"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/dheerajgopi/todo-api/common"
+	_errors "github.com/dheerajgopi/todo-api/common/error"
 	"github.com/dheerajgopi/todo-api/config"
 	"github.com/dheerajgopi/todo-api/models"
 	"github.com/dheerajgopi/todo-api/task"
 	_taskHandler "github.com/dheerajgopi/todo-api/task/delivery/http"
 	mock "github.com/dheerajgopi/todo-api/task/mock"
 	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 )
@@ -122,6 +125,56 @@ func TestCreate(t *testing.T) {
 	assert.Equal(false, responseData.Task.IsComplete)
 }
 
+func TestCreateWithInvalidDueTimezone(t *testing.T) {
+	dueAt := time.Now()
+	payload, _ := json.Marshal(&_taskHandler.CreateTaskRequest{
+		Title:       "test title",
+		DueAt:       &dueAt,
+		DueTimezone: "Mars/Olympus_Mons",
+	})
+
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+	req := httptest.NewRequest("POST", "/tasks", strings.NewReader(string(payload)))
+
+	status, data, err := handler.Create(httptest.NewRecorder(), req, reqCtx)
+
+	assert.Equal(400, status)
+	assert.Nil(data)
+	assert.Equal(1, len(err.Body))
+	assert.Equal("Invalid timezone", err.Body[0].Message)
+	assert.Equal("dueTimezone", err.Body[0].Target)
+}
+
+func TestCreateWithDueDate(t *testing.T) {
+	payload := `{"title": "test title", "dueAt": "2019-05-08T18:30:00Z", "dueTimezone": "Asia/Kolkata"}`
+
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+	req := httptest.NewRequest("POST", "/tasks", strings.NewReader(payload))
+
+	mockService.
+		EXPECT().
+		Create(gomock.Any(), gomock.Any()).
+		Return(nil).
+		Times(1)
+
+	status, data, err := handler.Create(httptest.NewRecorder(), req, reqCtx)
+
+	responseData := data.(*_taskHandler.CreateTaskResponse)
+
+	assert.Equal(201, status)
+	assert.Nil(err)
+	assert.Equal("Asia/Kolkata", responseData.Task.DueTimezone)
+	assert.Equal("2019-05-09T00:00:00+05:30", responseData.Task.DueAt.Format(time.RFC3339))
+}
+
 func TestListWithServerError(t *testing.T) {
 	assert := assert.New(t)
 	mockCtrl := gomock.NewController(t)
@@ -133,7 +186,7 @@ func TestListWithServerError(t *testing.T) {
 
 	mockService.
 		EXPECT().
-		List(gomock.Any(), gomock.Any()).
+		List(gomock.Any(), gomock.Any(), gomock.Any()).
 		Return(nil, errors.New("server error")).
 		Times(1)
 
@@ -171,8 +224,8 @@ func TestList(t *testing.T) {
 
 	mockService.
 		EXPECT().
-		List(gomock.Any(), gomock.Any()).
-		Return(expectedData, nil).
+		List(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(&task.Page{Tasks: expectedData, NextCursor: "next", HasMore: true}, nil).
 		Times(1)
 
 	status, data, err := handler.List(httptest.NewRecorder(), req, reqCtx)
@@ -188,6 +241,1206 @@ func TestList(t *testing.T) {
 	assert.Equal(false, actualData.Tasks[0].IsComplete)
 	assert.Equal(now, actualData.Tasks[0].CreatedAt)
 	assert.Equal(now, actualData.Tasks[0].UpdatedAt)
+	assert.Equal("next", actualData.Pagination.NextCursor)
+	assert.Equal(true, actualData.Pagination.HasMore)
+}
+
+func TestListWithFilters(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+
+	req := httptest.NewRequest("GET", "/tasks?isComplete=false&title=%20report%20&createdAfter=2019-05-01T00:00:00Z&sort=-updatedAt&limit=10", nil)
+
+	isComplete := false
+	createdAfter := time.Date(2019, 5, 1, 0, 0, 0, 0, time.UTC)
+	expectedFilter := &task.ListFilter{
+		IsComplete:   &isComplete,
+		Title:        "report",
+		CreatedAfter: &createdAfter,
+		Sort:         task.SortByUpdatedAt,
+		Descending:   true,
+		Limit:        10,
+		TopLevelOnly: true,
+	}
+
+	mockService.
+		EXPECT().
+		List(gomock.Any(), reqCtx.UserID, expectedFilter).
+		Return(&task.Page{Tasks: make([]*models.Task, 0)}, nil).
+		Times(1)
+
+	status, data, err := handler.List(httptest.NewRecorder(), req, reqCtx)
+
+	actualData := data.(*_taskHandler.ListTaskResponse)
+
+	assert.Equal(200, status)
+	assert.Nil(err)
+	assert.Equal(0, len(actualData.Tasks))
+	assert.Equal(false, actualData.Pagination.HasMore)
+}
+
+func TestListWithTags(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+
+	req := httptest.NewRequest("GET", "/tasks?tag=3,4&tag=5&tag=3&tagMatch=all", nil)
+
+	expectedFilter := &task.ListFilter{
+		TagIDs:       []int64{3, 4, 5},
+		MatchAllTags: true,
+		Sort:         task.SortByCreatedAt,
+		Limit:        task.DefaultListLimit,
+		TopLevelOnly: true,
+	}
+
+	mockService.
+		EXPECT().
+		List(gomock.Any(), reqCtx.UserID, expectedFilter).
+		Return(&task.Page{Tasks: []*models.Task{
+			{
+				ID:        1,
+				Title:     "title",
+				CreatedBy: &models.User{ID: 1},
+				Tags:      []*models.Tag{{ID: 3, Name: "home"}, {ID: 4, Name: "work", Color: "#00ff00"}},
+			},
+		}}, nil).
+		Times(1)
+
+	status, data, err := handler.List(httptest.NewRecorder(), req, reqCtx)
+
+	actualData := data.(*_taskHandler.ListTaskResponse)
+
+	assert.Equal(200, status)
+	assert.Nil(err)
+	assert.Equal(1, len(actualData.Tasks))
+	assert.Equal(2, len(actualData.Tasks[0].Tags))
+	assert.Equal("work", actualData.Tasks[0].Tags[1].Name)
+	assert.Equal("#00ff00", actualData.Tasks[0].Tags[1].Color)
+}
+
+func TestListWithInvalidTags(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+
+	req := httptest.NewRequest("GET", "/tasks?tag=home&tagMatch=some", nil)
+
+	status, data, err := handler.List(httptest.NewRecorder(), req, reqCtx)
+
+	assert.Equal(400, status)
+	assert.Nil(data)
+	assert.Equal(2, len(err.Body))
+	assert.Equal("tag", err.Body[0].Target)
+	assert.Equal("tagMatch", err.Body[1].Target)
+}
+
+func TestListWithInvalidQuery(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+
+	req := httptest.NewRequest("GET", "/tasks?isComplete=maybe&updatedBefore=yesterday&sort=owner&limit=500", nil)
+
+	status, data, err := handler.List(httptest.NewRecorder(), req, reqCtx)
+
+	assert.Equal(400, status)
+	assert.Nil(data)
+	assert.Equal(4, len(err.Body))
+	assert.Equal("isComplete", err.Body[0].Target)
+	assert.Equal("updatedBefore", err.Body[1].Target)
+	assert.Equal("sort", err.Body[2].Target)
+	assert.Equal("limit", err.Body[3].Target)
+}
+
+func TestListOverdue(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+
+	req := httptest.NewRequest("GET", "/tasks?due=overdue&tz=Asia/Kolkata", nil)
+
+	var actualFilter *task.ListFilter
+
+	mockService.
+		EXPECT().
+		List(gomock.Any(), reqCtx.UserID, gomock.Any()).
+		Do(func(_ interface{}, _ int64, filter *task.ListFilter) {
+			actualFilter = filter
+		}).
+		Return(&task.Page{Tasks: make([]*models.Task, 0)}, nil).
+		Times(1)
+
+	status, _, err := handler.List(httptest.NewRecorder(), req, reqCtx)
+
+	assert.Equal(200, status)
+	assert.Nil(err)
+	assert.Nil(actualFilter.DueAfter)
+	assert.NotNil(actualFilter.DueBefore)
+	assert.Equal(false, *actualFilter.IsComplete)
+}
+
+func TestListWithInvalidDueWindow(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+
+	req := httptest.NewRequest("GET", "/tasks?due=tomorrow&tz=Nowhere", nil)
+
+	status, data, err := handler.List(httptest.NewRecorder(), req, reqCtx)
+
+	assert.Equal(400, status)
+	assert.Nil(data)
+	assert.Equal(2, len(err.Body))
+	assert.Equal("tz", err.Body[0].Target)
+	assert.Equal("due", err.Body[1].Target)
+}
+
+func TestListWithCursorOfOtherSort(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+
+	cursor := task.NewCursor(&models.Task{ID: 1, Title: "title"}, task.SortByTitle).Encode()
+	req := httptest.NewRequest("GET", "/tasks?sort=createdAt&cursor="+cursor, nil)
+
+	status, data, err := handler.List(httptest.NewRecorder(), req, reqCtx)
+
+	assert.Equal(400, status)
+	assert.Nil(data)
+	assert.Equal("cursor", err.Body[0].Target)
+}
+
+func TestListByProject(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+
+	req := httptest.NewRequest("GET", "/projects/5/tasks?project=inbox", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "5"})
+
+	projectID := int64(5)
+	expectedFilter := &task.ListFilter{
+		ProjectID:    &projectID,
+		Sort:         task.SortByCreatedAt,
+		Limit:        task.DefaultListLimit,
+		TopLevelOnly: true,
+	}
+
+	mockService.
+		EXPECT().
+		List(gomock.Any(), reqCtx.UserID, expectedFilter).
+		Return(&task.Page{Tasks: []*models.Task{
+			{
+				ID:        1,
+				Title:     "title",
+				CreatedBy: &models.User{ID: 1},
+				Project:   &models.Project{ID: projectID},
+			},
+		}}, nil).
+		Times(1)
+
+	status, data, err := handler.ListByProject(httptest.NewRecorder(), req, reqCtx)
+
+	actualData := data.(*_taskHandler.ListTaskResponse)
+
+	assert.Equal(200, status)
+	assert.Nil(err)
+	assert.Equal(projectID, *actualData.Tasks[0].ProjectID)
+}
+
+func TestListByMissingProject(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+
+	req := httptest.NewRequest("GET", "/projects/5/tasks", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "5"})
+
+	mockService.
+		EXPECT().
+		List(gomock.Any(), reqCtx.UserID, gomock.Any()).
+		Return(nil, &_errors.ResourceNotFoundError{Resource: "project"}).
+		Times(1)
+
+	status, data, err := handler.ListByProject(httptest.NewRecorder(), req, reqCtx)
+
+	assert.Equal(404, status)
+	assert.Nil(data)
+	assert.Equal("project", err.Body[0].Target)
+}
+
+func TestPatchMovingToInbox(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+
+	req := httptest.NewRequest("PATCH", "/tasks/1", strings.NewReader(`{"projectId": null}`))
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+
+	existingTask := &models.Task{
+		ID:        1,
+		Title:     "title",
+		CreatedBy: &models.User{ID: 1},
+		Project:   &models.Project{ID: 5},
+	}
+
+	mockService.
+		EXPECT().
+		GetByID(gomock.Any(), int64(1), reqCtx.UserID).
+		Return(existingTask, nil).
+		Times(1)
+
+	mockService.
+		EXPECT().
+		Update(gomock.Any(), existingTask, reqCtx.UserID).
+		Return(nil).
+		Times(1)
+
+	status, data, err := handler.Patch(httptest.NewRecorder(), req, reqCtx)
+
+	actualData := data.(*_taskHandler.UpdateTaskResponse)
+
+	assert.Equal(200, status)
+	assert.Nil(err)
+	assert.Nil(existingTask.Project)
+	assert.Nil(actualData.Task.ProjectID)
+}
+
+func TestGetForMissingTask(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+
+	req := httptest.NewRequest("GET", "/tasks/1", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+
+	mockService.
+		EXPECT().
+		GetByID(gomock.Any(), int64(1), reqCtx.UserID).
+		Return(nil, &_errors.ResourceNotFoundError{Resource: "task"}).
+		Times(1)
+
+	status, data, err := handler.Get(httptest.NewRecorder(), req, reqCtx)
+
+	assert.Equal(404, status)
+	assert.Nil(data)
+	assert.NotNil(err)
+	assert.Equal(1, len(err.Body))
+	assert.Equal("Not found", err.Body[0].Message)
+	assert.Equal("task", err.Body[0].Target)
+}
+
+func TestGet(t *testing.T) {
+	now := time.Now()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+
+	req := httptest.NewRequest("GET", "/tasks/1", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+
+	mockService.
+		EXPECT().
+		GetByID(gomock.Any(), int64(1), reqCtx.UserID).
+		Return(&models.Task{
+			ID:          1,
+			Title:       "test title",
+			Description: "test description",
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		}, nil).
+		Times(1)
+
+	status, data, err := handler.Get(httptest.NewRecorder(), req, reqCtx)
+
+	actualData := data.(*_taskHandler.GetTaskResponse)
+
+	assert.Equal(200, status)
+	assert.Nil(err)
+	assert.Equal(int64(1), actualData.Task.ID)
+	assert.Equal("test title", actualData.Task.Title)
+	assert.Equal("test description", actualData.Task.Description)
+}
+
+func TestUpdateWithBlankTitle(t *testing.T) {
+	payload, _ := json.Marshal(&_taskHandler.UpdateTaskRequest{
+		Title:       " ",
+		Description: "test description",
+	})
+
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+
+	req := httptest.NewRequest("PUT", "/tasks/1", strings.NewReader(string(payload)))
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+
+	status, data, err := handler.Update(httptest.NewRecorder(), req, reqCtx)
+
+	assert.Equal(400, status)
+	assert.Nil(data)
+	assert.Error(err)
+	assert.Equal(1, len(err.Body))
+	assert.Equal("Non-empty value is required", err.Body[0].Message)
+	assert.Equal("title", err.Body[0].Target)
+}
+
+func TestUpdateForMissingTask(t *testing.T) {
+	payload, _ := json.Marshal(&_taskHandler.UpdateTaskRequest{
+		Title: "test title",
+	})
+
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+
+	req := httptest.NewRequest("PUT", "/tasks/1", strings.NewReader(string(payload)))
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+
+	mockService.
+		EXPECT().
+		Update(gomock.Any(), gomock.Any(), reqCtx.UserID).
+		Return(&_errors.ResourceNotFoundError{Resource: "task"}).
+		Times(1)
+
+	status, data, err := handler.Update(httptest.NewRecorder(), req, reqCtx)
+
+	assert.Equal(404, status)
+	assert.Nil(data)
+	assert.NotNil(err)
+	assert.Equal("Not found", err.Body[0].Message)
+}
+
+func TestUpdate(t *testing.T) {
+	reqBody := &_taskHandler.UpdateTaskRequest{
+		Title:       "new title",
+		Description: "new description",
+		IsComplete:  true,
+	}
+
+	payload, _ := json.Marshal(reqBody)
+
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+
+	req := httptest.NewRequest("PUT", "/tasks/1", strings.NewReader(string(payload)))
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+
+	mockService.
+		EXPECT().
+		Update(gomock.Any(), gomock.Any(), reqCtx.UserID).
+		Return(nil).
+		Times(1)
+
+	status, data, err := handler.Update(httptest.NewRecorder(), req, reqCtx)
+
+	responseData := data.(*_taskHandler.UpdateTaskResponse)
+
+	assert.Equal(200, status)
+	assert.Nil(err)
+	assert.Equal(int64(1), responseData.Task.ID)
+	assert.Equal(reqBody.Title, responseData.Task.Title)
+	assert.Equal(reqBody.Description, responseData.Task.Description)
+	assert.Equal(true, responseData.Task.IsComplete)
+}
+
+func TestPatch(t *testing.T) {
+	now := time.Now()
+	isComplete := true
+	payload, _ := json.Marshal(&_taskHandler.PatchTaskRequest{
+		IsComplete: &isComplete,
+	})
+
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+
+	req := httptest.NewRequest("PATCH", "/tasks/1", strings.NewReader(string(payload)))
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+
+	mockService.
+		EXPECT().
+		GetByID(gomock.Any(), int64(1), reqCtx.UserID).
+		Return(&models.Task{
+			ID:          1,
+			Title:       "test title",
+			Description: "test description",
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		}, nil).
+		Times(1)
+
+	mockService.
+		EXPECT().
+		Update(gomock.Any(), gomock.Any(), reqCtx.UserID).
+		Return(nil).
+		Times(1)
+
+	status, data, err := handler.Patch(httptest.NewRecorder(), req, reqCtx)
+
+	responseData := data.(*_taskHandler.UpdateTaskResponse)
+
+	assert.Equal(200, status)
+	assert.Nil(err)
+	assert.Equal("test title", responseData.Task.Title)
+	assert.Equal("test description", responseData.Task.Description)
+	assert.Equal(true, responseData.Task.IsComplete)
+}
+
+func TestPatchRemovingDueDate(t *testing.T) {
+	now := time.Now()
+	payload := `{"dueAt": null}`
+
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+
+	req := httptest.NewRequest("PATCH", "/tasks/1", strings.NewReader(payload))
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+
+	mockService.
+		EXPECT().
+		GetByID(gomock.Any(), int64(1), reqCtx.UserID).
+		Return(&models.Task{
+			ID:          1,
+			Title:       "test title",
+			DueAt:       &now,
+			DueTimezone: "Asia/Kolkata",
+			RemindAt:    &now,
+		}, nil).
+		Times(1)
+
+	mockService.
+		EXPECT().
+		Update(gomock.Any(), gomock.Any(), reqCtx.UserID).
+		Return(nil).
+		Times(1)
+
+	status, data, err := handler.Patch(httptest.NewRecorder(), req, reqCtx)
+
+	responseData := data.(*_taskHandler.UpdateTaskResponse)
+
+	assert.Equal(200, status)
+	assert.Nil(err)
+	assert.Nil(responseData.Task.DueAt)
+	assert.Equal("", responseData.Task.DueTimezone)
+	assert.NotNil(responseData.Task.RemindAt)
+}
+
+func TestPatchWithBlankTitle(t *testing.T) {
+	title := " "
+	payload, _ := json.Marshal(&_taskHandler.PatchTaskRequest{
+		Title: &title,
+	})
+
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+
+	req := httptest.NewRequest("PATCH", "/tasks/1", strings.NewReader(string(payload)))
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+
+	status, data, err := handler.Patch(httptest.NewRecorder(), req, reqCtx)
+
+	assert.Equal(400, status)
+	assert.Nil(data)
+	assert.Equal("title", err.Body[0].Target)
+}
+
+func TestDeleteForMissingTask(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+
+	req := httptest.NewRequest("DELETE", "/tasks/1", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+
+	mockService.
+		EXPECT().
+		Delete(gomock.Any(), int64(1), reqCtx.UserID).
+		Return(&_errors.ResourceNotFoundError{Resource: "task"}).
+		Times(1)
+
+	status, data, err := handler.Delete(httptest.NewRecorder(), req, reqCtx)
+
+	assert.Equal(404, status)
+	assert.Nil(data)
+	assert.Equal("Not found", err.Body[0].Message)
+}
+
+func TestDelete(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+
+	req := httptest.NewRequest("DELETE", "/tasks/1", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+
+	mockService.
+		EXPECT().
+		Delete(gomock.Any(), int64(1), reqCtx.UserID).
+		Return(nil).
+		Times(1)
+
+	status, data, err := handler.Delete(httptest.NewRecorder(), req, reqCtx)
+
+	assert.Equal(200, status)
+	assert.Nil(data)
+	assert.Nil(err)
+}
+
+func TestCreateWithInvalidPriority(t *testing.T) {
+	payload, _ := json.Marshal(&_taskHandler.CreateTaskRequest{
+		Title:    "test title",
+		Priority: "critical",
+	})
+
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+	req := httptest.NewRequest("POST", "/tasks", strings.NewReader(string(payload)))
+
+	status, data, err := handler.Create(httptest.NewRecorder(), req, reqCtx)
+
+	assert.Equal(400, status)
+	assert.Nil(data)
+	assert.Equal(1, len(err.Body))
+	assert.Equal("priority", err.Body[0].Target)
+}
+
+func TestBulkWithInvalidOperations(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+
+	body := `{"taskIds": [], "operations": [{"type": "delete"}, {"type": "archive"}, {"type": "addTag"}, {"type": "moveToProject"}]}`
+	req := httptest.NewRequest("POST", "/tasks/bulk", strings.NewReader(body))
+
+	status, data, err := handler.Bulk(httptest.NewRecorder(), req, reqCtx)
+
+	assert.Equal(400, status)
+	assert.Nil(data)
+	assert.Equal(5, len(err.Body))
+	assert.Equal("taskIds", err.Body[0].Target)
+	assert.Equal("Delete should be the last operation", err.Body[1].Message)
+	assert.Equal("type", err.Body[2].Target)
+	assert.Equal("tagId", err.Body[3].Target)
+	assert.Equal("projectId", err.Body[4].Target)
+}
+
+func TestBulk(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+	reqCtx.UserID = 1
+
+	body := `{"taskIds": [1, 2, 1, 3], "operations": [{"type": "moveToProject", "projectId": null}, {"type": "addTag", "tagId": 4}]}`
+	req := httptest.NewRequest("POST", "/tasks/bulk", strings.NewReader(body))
+
+	mockService.
+		EXPECT().
+		Bulk(gomock.Any(), gomock.Any(), int64(1)).
+		DoAndReturn(func(ctx interface{}, change *task.BulkChange, userID int64) ([]*task.BulkResult, error) {
+			assert.Equal([]int64{1, 2, 3}, change.TaskIDs)
+			assert.False(change.AllOrNothing)
+			assert.Nil(change.Operations[0].Project)
+			assert.Equal(int64(4), change.Operations[1].Tag.ID)
+
+			return []*task.BulkResult{
+				{TaskID: 1, Status: task.BulkSucceeded},
+				{TaskID: 2, Status: task.BulkFailed, Err: &_errors.PermissionDeniedError{Resource: "task", Action: "update"}},
+				{TaskID: 3, Status: task.BulkFailed, Err: errors.New("db down")},
+			}, nil
+		}).
+		Times(1)
+
+	status, data, err := handler.Bulk(httptest.NewRecorder(), req, reqCtx)
+
+	responseData := data.(*_taskHandler.BulkTaskResponse)
+
+	assert.Equal(200, status)
+	assert.Nil(err)
+	assert.Equal(1, responseData.Succeeded)
+	assert.Equal(2, responseData.Failed)
+	assert.Nil(responseData.Results[0].Error)
+	assert.Equal(403, responseData.Results[1].Code)
+	assert.Equal("Permission denied", responseData.Results[1].Error.Message)
+	assert.Equal(500, responseData.Results[2].Code)
+	assert.Equal("Internal server error", responseData.Results[2].Error.Message)
+}
+
+func exportedTasks() []*models.Task {
+	createdAt := time.Date(2019, 10, 20, 9, 0, 0, 0, time.UTC)
+	dueAt := time.Date(2019, 10, 30, 22, 0, 0, 0, time.UTC)
+
+	parent := &models.Task{
+		ID:          1,
+		Title:       "Plan trip",
+		Description: "Summer, with family",
+		Project:     &models.Project{ID: 3, Name: "Family time"},
+		Tags:        []*models.Tag{{ID: 4, Name: "travel"}},
+		Priority:    task.PriorityHigh,
+		DueAt:       &dueAt,
+		DueTimezone: "Asia/Kolkata",
+		CreatedAt:   createdAt,
+		UpdatedAt:   createdAt,
+	}
+
+	subtask := &models.Task{
+		ID:         2,
+		Title:      "Book hotel",
+		Parent:     &models.Task{ID: 1},
+		IsComplete: true,
+		Priority:   task.PriorityUrgent,
+		Tags:       []*models.Tag{},
+		CreatedAt:  createdAt,
+		UpdatedAt:  createdAt.Add(48 * time.Hour),
+	}
+
+	return []*models.Task{parent, subtask}
+}
+
+func runExport(t *testing.T, format string, tasks []*models.Task) (*common.RawResponse, string) {
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+	reqCtx.UserID = 1
+	req := httptest.NewRequest("GET", "/tasks/export?format="+format, nil)
+
+	mockService.
+		EXPECT().
+		Export(gomock.Any(), int64(1), gomock.Any()).
+		DoAndReturn(func(ctx interface{}, userID int64, fn func(task *models.Task) error) error {
+			for _, exportedTask := range tasks {
+				if err := fn(exportedTask); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		}).
+		Times(1)
+
+	status, data, err := handler.Export(httptest.NewRecorder(), req, reqCtx)
+
+	assert.Equal(t, 200, status)
+	assert.Nil(t, err)
+
+	responseData := data.(*common.RawResponse)
+	body, readErr := ioutil.ReadAll(responseData.Body)
+
+	assert.NoError(t, readErr)
+
+	return responseData, string(body)
+}
+
+func TestExportJSON(t *testing.T) {
+	assert := assert.New(t)
+	responseData, body := runExport(t, "", exportedTasks())
+
+	assert.Equal("application/json", responseData.ContentType)
+	assert.Equal(`attachment; filename="tasks.json"`, responseData.Headers["Content-Disposition"])
+
+	tasks := make([]*_taskHandler.PortableTaskData, 0)
+
+	assert.NoError(json.Unmarshal([]byte(body), &tasks))
+	assert.Equal(2, len(tasks))
+	assert.Equal("Family time", tasks[0].Project)
+	assert.Equal([]string{"travel"}, tasks[0].Tags)
+	assert.Equal("2019-10-31T03:30:00+05:30", tasks[0].DueAt.Format(time.RFC3339))
+	assert.Equal(int64(1), *tasks[1].ParentID)
+}
+
+func TestExportEmptyJSON(t *testing.T) {
+	_, body := runExport(t, "json", []*models.Task{})
+
+	assert.Equal(t, "[]\n", body)
+}
+
+func TestExportCSV(t *testing.T) {
+	assert := assert.New(t)
+	responseData, body := runExport(t, "csv", exportedTasks())
+
+	assert.Equal("text/csv; charset=utf-8", responseData.ContentType)
+	assert.Equal("id,parentId,title,description,project,tags,isComplete,autoComplete,priority,dueAt,dueTimezone,remindAt,recurrence,createdAt\n"+
+		"1,,Plan trip,\"Summer, with family\",Family time,travel,false,false,high,2019-10-31T03:30:00+05:30,Asia/Kolkata,,,2019-10-20T09:00:00Z\n"+
+		"2,1,Book hotel,,,,true,false,urgent,,,,,2019-10-20T09:00:00Z\n", body)
+}
+
+func TestExportTodoTxt(t *testing.T) {
+	assert := assert.New(t)
+	responseData, body := runExport(t, "todotxt", exportedTasks())
+
+	assert.Equal(`attachment; filename="todo.txt"`, responseData.Headers["Content-Disposition"])
+	assert.Equal("(B) 2019-10-20 Plan trip +Family_time @travel due:2019-10-31 id:1\n"+
+		"x 2019-10-22 2019-10-20 Book hotel pri:A id:2 parent:1\n", body)
+}
+
+func TestExportWithInvalidFormat(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+	req := httptest.NewRequest("GET", "/tasks/export?format=xml", nil)
+
+	status, data, err := handler.Export(httptest.NewRecorder(), req, reqCtx)
+
+	assert.Equal(400, status)
+	assert.Nil(data)
+	assert.Equal("format", err.Body[0].Target)
+}
+
+func TestImportTodoTxt(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+	reqCtx.UserID = 1
+
+	body := "(B) 2019-10-20 Plan trip +Family_time @travel due:2019-10-31 id:1\n" +
+		"\n" +
+		"x 2019-10-22 2019-10-20 Book hotel pri:A id:2 parent:1\n" +
+		"(F) Call mom @family_time\n"
+	req := httptest.NewRequest("POST", "/tasks/import?format=todotxt", strings.NewReader(body))
+
+	mockService.
+		EXPECT().
+		Import(gomock.Any(), gomock.Any(), int64(1), false).
+		DoAndReturn(func(ctx interface{}, tasks []*models.Task, userID int64, dryRun bool) ([]*task.ImportFailure, error) {
+			assert.Equal(3, len(tasks))
+			assert.Equal("Plan trip", tasks[0].Title)
+			assert.Equal(task.PriorityHigh, tasks[0].Priority)
+			assert.Equal("Family time", tasks[0].Project.Name)
+			assert.Equal("travel", tasks[0].Tags[0].Name)
+			assert.Equal("2019-10-31T00:00:00Z", tasks[0].DueAt.Format(time.RFC3339))
+			assert.Equal("UTC", tasks[0].DueTimezone)
+			assert.Equal("2019-10-20", tasks[0].CreatedAt.Format("2006-01-02"))
+			assert.Equal(int64(1), tasks[0].CreatedBy.ID)
+			assert.Equal(tasks[0], tasks[1].Parent)
+			assert.True(tasks[1].IsComplete)
+			assert.Equal(task.PriorityUrgent, tasks[1].Priority)
+			assert.Equal(task.PriorityLow, tasks[2].Priority)
+			assert.Equal("family time", tasks[2].Tags[0].Name)
+			assert.False(tasks[2].CreatedAt.IsZero())
+
+			return []*task.ImportFailure{}, nil
+		}).
+		Times(1)
+
+	status, data, err := handler.Import(httptest.NewRecorder(), req, reqCtx)
+
+	responseData := data.(*_taskHandler.ImportTaskResponse)
+
+	assert.Equal(200, status)
+	assert.Nil(err)
+	assert.Equal(3, responseData.Total)
+	assert.Equal(3, responseData.Imported)
+	assert.Equal(0, len(responseData.Errors))
+}
+
+func TestImportCSVWithInvalidLines(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+
+	body := "title,priority,isComplete,parentId,id\n" +
+		"Plan trip,high,false,,1\n" +
+		" ,,,,\n" +
+		"Book hotel,,maybe,,\n" +
+		"Pack bags,asap,,5,\n"
+	req := httptest.NewRequest("POST", "/tasks/import?format=csv", strings.NewReader(body))
+
+	status, data, err := handler.Import(httptest.NewRecorder(), req, reqCtx)
+
+	responseData := data.(*_taskHandler.ImportTaskResponse)
+
+	assert.Equal(200, status)
+	assert.Nil(err)
+	assert.Equal(4, responseData.Total)
+	assert.Equal(0, responseData.Imported)
+	assert.Equal(4, len(responseData.Errors))
+	assert.Equal(3, responseData.Errors[0].Line)
+	assert.Equal("title", responseData.Errors[0].Target)
+	assert.Equal(4, responseData.Errors[1].Line)
+	assert.Equal("isComplete", responseData.Errors[1].Target)
+	assert.Equal(5, responseData.Errors[2].Line)
+	assert.Equal("priority", responseData.Errors[2].Target)
+	assert.Equal(5, responseData.Errors[3].Line)
+	assert.Equal("Parent task should come before its subtasks", responseData.Errors[3].Message)
+}
+
+func TestImportDryRunWithFailures(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+	reqCtx.UserID = 1
+
+	body := `[{"title": "Plan trip", "project": "Travel"}, {"title": "Book hotel", "tags": ["work", " work "]}]`
+	req := httptest.NewRequest("POST", "/tasks/import?dryRun=true", strings.NewReader(body))
+
+	mockService.
+		EXPECT().
+		Import(gomock.Any(), gomock.Any(), int64(1), true).
+		DoAndReturn(func(ctx interface{}, tasks []*models.Task, userID int64, dryRun bool) ([]*task.ImportFailure, error) {
+			assert.Equal(1, len(tasks[1].Tags))
+
+			return []*task.ImportFailure{
+				{Index: 0, Err: &_errors.InvalidValueError{Resource: "task", Field: "project", Reason: "Project Travel does not exist"}},
+			}, nil
+		}).
+		Times(1)
+
+	status, data, err := handler.Import(httptest.NewRecorder(), req, reqCtx)
+
+	responseData := data.(*_taskHandler.ImportTaskResponse)
+
+	assert.Equal(200, status)
+	assert.Nil(err)
+	assert.True(responseData.DryRun)
+	assert.Equal(0, responseData.Imported)
+	assert.Equal(1, len(responseData.Errors))
+	assert.Equal(1, responseData.Errors[0].Line)
+	assert.Equal("Project Travel does not exist", responseData.Errors[0].Message)
+}
+
+func TestImportWithInvalidBody(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+
+	for _, body := range []string{`{"title": "Plan trip"}`, `[]`, `[null]`} {
+		req := httptest.NewRequest("POST", "/tasks/import?format=json", strings.NewReader(body))
+
+		status, data, err := handler.Import(httptest.NewRecorder(), req, reqCtx)
+
+		assert.Equal(400, status)
+		assert.Nil(data)
+		assert.Equal(1, len(err.Body))
+	}
+}
+
+func TestMoveWithoutAnchor(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+
+	req := httptest.NewRequest("POST", "/tasks/1/move", strings.NewReader(`{"beforeId": 2, "afterId": 3}`))
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+
+	status, data, err := handler.Move(httptest.NewRecorder(), req, reqCtx)
+
+	assert.Equal(400, status)
+	assert.Nil(data)
+	assert.Equal("Either beforeId or afterId is required", err.Body[0].Message)
+}
+
+func TestMove(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+
+	req := httptest.NewRequest("POST", "/tasks/1/move", strings.NewReader(`{"afterId": 2}`))
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+
+	mockService.
+		EXPECT().
+		Move(gomock.Any(), int64(1), int64(2), false, reqCtx.UserID).
+		Return(&models.Task{ID: 1, Title: "test title", Priority: task.PriorityUrgent, Position: 98304}, nil).
+		Times(1)
+
+	status, data, err := handler.Move(httptest.NewRecorder(), req, reqCtx)
+
+	responseData := data.(*_taskHandler.UpdateTaskResponse)
+
+	assert.Equal(200, status)
+	assert.Nil(err)
+	assert.Equal(int64(98304), responseData.Task.Position)
+	assert.Equal("urgent", responseData.Task.Priority)
+}
+
+func TestListWithSubtaskTree(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+
+	req := httptest.NewRequest("GET", "/tasks?subtasks=tree", nil)
+
+	expectedFilter := &task.ListFilter{
+		Sort:         task.SortByCreatedAt,
+		Limit:        task.DefaultListLimit,
+		TopLevelOnly: true,
+		WithSubtasks: true,
+	}
+
+	mockService.
+		EXPECT().
+		List(gomock.Any(), reqCtx.UserID, expectedFilter).
+		Return(&task.Page{Tasks: []*models.Task{
+			{
+				ID:        1,
+				CreatedBy: &models.User{ID: 1},
+				Progress:  &models.Progress{Completed: 1, Total: 1},
+				Subtasks: []*models.Task{
+					{ID: 2, CreatedBy: &models.User{ID: 1}, Parent: &models.Task{ID: 1}, IsComplete: true},
+				},
+			},
+		}}, nil).
+		Times(1)
+
+	status, data, err := handler.List(httptest.NewRecorder(), req, reqCtx)
+
+	actualData := data.(*_taskHandler.ListTaskResponse)
+
+	assert.Equal(200, status)
+	assert.Nil(err)
+	assert.Equal(&_taskHandler.ProgressData{Completed: 1, Total: 1}, actualData.Tasks[0].Progress)
+	assert.Equal(1, len(actualData.Tasks[0].Subtasks))
+	assert.Equal(int64(1), *actualData.Tasks[0].Subtasks[0].ParentID)
+}
+
+func TestListWithInvalidSubtasks(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+
+	req := httptest.NewRequest("GET", "/tasks?subtasks=all", nil)
+
+	status, data, err := handler.List(httptest.NewRecorder(), req, reqCtx)
+
+	assert.Equal(400, status)
+	assert.Nil(data)
+	assert.Equal(1, len(err.Body))
+	assert.Equal("subtasks", err.Body[0].Target)
+}
+
+func TestCreateSubtask(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+
+	req := httptest.NewRequest("POST", "/tasks/3/subtasks", strings.NewReader(`{"title": "test title"}`))
+	req = mux.SetURLVars(req, map[string]string{"id": "3"})
+
+	mockService.
+		EXPECT().
+		Create(gomock.Any(), gomock.Any()).
+		Do(func(_ interface{}, newTask *models.Task) {
+			newTask.Project = &models.Project{ID: 5}
+		}).
+		Return(nil).
+		Times(1)
+
+	status, data, err := handler.CreateSubtask(httptest.NewRecorder(), req, reqCtx)
+
+	responseData := data.(*_taskHandler.CreateTaskResponse)
+
+	assert.Equal(201, status)
+	assert.Nil(err)
+	assert.Equal(int64(3), *responseData.Task.ParentID)
+	assert.Equal(int64(5), *responseData.Task.ProjectID)
+	assert.Nil(responseData.Task.Progress)
+}
+
+func TestCreateNestedSubtask(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+
+	req := httptest.NewRequest("POST", "/tasks/3/subtasks", strings.NewReader(`{"title": "test title"}`))
+	req = mux.SetURLVars(req, map[string]string{"id": "3"})
+
+	mockService.
+		EXPECT().
+		Create(gomock.Any(), gomock.Any()).
+		Return(&_errors.InvalidValueError{Resource: "task", Field: "parentId", Reason: "Subtasks can not have subtasks"}).
+		Times(1)
+
+	status, data, err := handler.CreateSubtask(httptest.NewRecorder(), req, reqCtx)
+
+	assert.Equal(400, status)
+	assert.Nil(data)
+	assert.Equal(1, len(err.Body))
+	assert.Equal("Subtasks can not have subtasks", err.Body[0].Message)
+	assert.Equal("parentId", err.Body[0].Target)
+}
+
+func TestListSubtasks(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+
+	req := httptest.NewRequest("GET", "/tasks/3/subtasks", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "3"})
+
+	mockService.
+		EXPECT().
+		ListSubtasks(gomock.Any(), int64(3), reqCtx.UserID).
+		Return([]*models.Task{{ID: 4, Parent: &models.Task{ID: 3}}}, nil).
+		Times(1)
+
+	status, data, err := handler.ListSubtasks(httptest.NewRecorder(), req, reqCtx)
+
+	responseData := data.(*_taskHandler.ListSubtaskResponse)
+
+	assert.Equal(200, status)
+	assert.Nil(err)
+	assert.Equal(1, len(responseData.Subtasks))
+	assert.Equal(int64(4), responseData.Subtasks[0].ID)
+}
+
+func TestCreateRecurringTask(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+
+	payload := `{"title": "standup", "dueAt": "2019-05-08T09:00:00Z", "recurrence": "RRULE:FREQ=WEEKLY;BYDAY=MO,WE,FR"}`
+	req := httptest.NewRequest("POST", "/tasks", strings.NewReader(payload))
+
+	mockService.
+		EXPECT().
+		Create(gomock.Any(), gomock.Any()).
+		Do(func(_ interface{}, newTask *models.Task) {
+			assert.Equal("FREQ=WEEKLY;BYDAY=MO,WE,FR", newTask.Recurrence)
+		}).
+		Return(nil).
+		Times(1)
+
+	status, data, err := handler.Create(httptest.NewRecorder(), req, reqCtx)
+
+	responseData := data.(*_taskHandler.CreateTaskResponse)
+
+	assert.Equal(201, status)
+	assert.Nil(err)
+	assert.Equal("FREQ=WEEKLY;BYDAY=MO,WE,FR", responseData.Task.Recurrence)
+}
+
+func TestCreateWithInvalidRecurrence(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+
+	payload := `{"title": "standup", "dueAt": "2019-05-08T09:00:00Z", "recurrence": "FREQ=HOURLY"}`
+	req := httptest.NewRequest("POST", "/tasks", strings.NewReader(payload))
+
+	status, data, err := handler.Create(httptest.NewRecorder(), req, reqCtx)
+
+	assert.Equal(400, status)
+	assert.Nil(data)
+	assert.Equal(1, len(err.Body))
+	assert.Equal("FREQ should be one of DAILY, WEEKLY, MONTHLY, YEARLY", err.Body[0].Message)
+	assert.Equal("recurrence", err.Body[0].Target)
+}
+
+func TestPatchRemovingRecurrence(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+
+	req := httptest.NewRequest("PATCH", "/tasks/1", strings.NewReader(`{"recurrence": ""}`))
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+
+	dueAt := time.Date(2019, 5, 8, 9, 0, 0, 0, time.UTC)
+
+	mockService.
+		EXPECT().
+		GetByID(gomock.Any(), int64(1), reqCtx.UserID).
+		Return(&models.Task{ID: 1, Title: "standup", DueAt: &dueAt, Recurrence: "FREQ=DAILY"}, nil).
+		Times(1)
+
+	mockService.
+		EXPECT().
+		Update(gomock.Any(), gomock.Any(), reqCtx.UserID).
+		Do(func(_ interface{}, updatedTask *models.Task, _ int64) {
+			assert.Equal("", updatedTask.Recurrence)
+		}).
+		Return(nil).
+		Times(1)
+
+	status, data, err := handler.Patch(httptest.NewRecorder(), req, reqCtx)
+
+	responseData := data.(*_taskHandler.UpdateTaskResponse)
+
+	assert.Equal(200, status)
+	assert.Nil(err)
+	assert.Equal("", responseData.Task.Recurrence)
 }
 
 func setupHandler(mockService task.Service) *_taskHandler.TaskHandler {
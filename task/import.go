@@ -0,0 +1,11 @@
+package task
+
+// MaxImportTasks is the maximum number of tasks which can be imported at once
+const MaxImportTasks = 1000
+
+// ImportFailure tells why one of the imported tasks could not be created.
+// Index is the position of the task in the imported list.
+type ImportFailure struct {
+	Index int
+	Err   error
+}
@@ -0,0 +1,173 @@
+package task
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/dheerajgopi/todo-api/models"
+)
+
+// Fields on which a task list can be sorted
+const (
+	SortByID        = "id"
+	SortByCreatedAt = "createdAt"
+	SortByUpdatedAt = "updatedAt"
+	SortByTitle     = "title"
+	SortByDueAt     = "dueAt"
+	SortByPriority  = "priority"
+	SortByPosition  = "position"
+)
+
+// Due date windows for listing tasks
+const (
+	DueOverdue  = "overdue"
+	DueToday    = "today"
+	DueThisWeek = "week"
+)
+
+// Page size limits for task lists
+const (
+	DefaultListLimit = 50
+	MaxListLimit     = 100
+)
+
+// ErrInvalidCursor is returned if a pagination cursor cannot be decoded
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// ListFilter holds the filtering, sorting and pagination options for listing tasks.
+// Nil or empty fields are not applied. Inbox restricts the list to tasks without project.
+// TopLevelOnly leaves out subtasks, and WithSubtasks attaches the subtasks to each listed task.
+type ListFilter struct {
+	IsComplete    *bool
+	Title         string
+	ProjectID     *int64
+	Inbox         bool
+	TopLevelOnly  bool
+	WithSubtasks  bool
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	UpdatedAfter  *time.Time
+	UpdatedBefore *time.Time
+	DueAfter      *time.Time
+	DueBefore     *time.Time
+	TagIDs        []int64
+	MatchAllTags  bool
+	Sort          string
+	Descending    bool
+	Cursor        *Cursor
+	Limit         int
+}
+
+// Page represents a single page of tasks
+type Page struct {
+	Tasks      []*models.Task
+	NextCursor string
+	HasMore    bool
+}
+
+// Cursor marks the position of the last task in a page. Value holds the
+// sort field of that task, and ID breaks ties between equal values.
+type Cursor struct {
+	Sort  string `json:"s"`
+	Value string `json:"v"`
+	ID    int64  `json:"id"`
+}
+
+// IsSortable reports whether tasks can be sorted on the given field
+func IsSortable(field string) bool {
+	switch field {
+	case SortByID, SortByCreatedAt, SortByUpdatedAt, SortByTitle, SortByDueAt, SortByPriority, SortByPosition:
+		return true
+	}
+
+	return false
+}
+
+// IsDueWindow reports whether the given value is a known due date window
+func IsDueWindow(window string) bool {
+	switch window {
+	case DueOverdue, DueToday, DueThisWeek:
+		return true
+	}
+
+	return false
+}
+
+// DueWindowRange returns the due date range [from, to) of a due date window.
+// Calendar days are computed in the location of now, and weeks start on Monday.
+// Overdue tasks have no lower bound, so a nil from is returned for them.
+func DueWindowRange(window string, now time.Time) (*time.Time, *time.Time) {
+	year, month, day := now.Date()
+	startOfDay := time.Date(year, month, day, 0, 0, 0, 0, now.Location())
+
+	switch window {
+	case DueOverdue:
+		return nil, &now
+	case DueToday:
+		endOfDay := startOfDay.AddDate(0, 0, 1)
+		return &startOfDay, &endOfDay
+	case DueThisWeek:
+		daysSinceMonday := (int(now.Weekday()) + 6) % 7
+		startOfWeek := startOfDay.AddDate(0, 0, -daysSinceMonday)
+		endOfWeek := startOfWeek.AddDate(0, 0, 7)
+		return &startOfWeek, &endOfWeek
+	}
+
+	return nil, nil
+}
+
+// NewCursor returns the cursor pointing at the given task for a sort field
+func NewCursor(task *models.Task, sort string) *Cursor {
+	cursor := &Cursor{
+		Sort: sort,
+		ID:   task.ID,
+	}
+
+	switch sort {
+	case SortByCreatedAt:
+		cursor.Value = task.CreatedAt.UTC().Format(time.RFC3339Nano)
+	case SortByUpdatedAt:
+		cursor.Value = task.UpdatedAt.UTC().Format(time.RFC3339Nano)
+	case SortByTitle:
+		cursor.Value = task.Title
+	case SortByDueAt:
+		if task.DueAt != nil {
+			cursor.Value = task.DueAt.UTC().Format(time.RFC3339Nano)
+		}
+	case SortByPriority:
+		cursor.Value = strconv.Itoa(task.Priority)
+	case SortByPosition:
+		cursor.Value = strconv.FormatInt(task.Position, 10)
+	default:
+		cursor.Value = strconv.FormatInt(task.ID, 10)
+	}
+
+	return cursor
+}
+
+// Encode returns the opaque string form of the cursor
+func (cursor *Cursor) Encode() string {
+	data, _ := json.Marshal(cursor)
+
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor parses a cursor created by Cursor.Encode
+func DecodeCursor(encoded string) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	cursor := &Cursor{}
+
+	if err = json.Unmarshal(data, cursor); err != nil || !IsSortable(cursor.Sort) {
+		return nil, ErrInvalidCursor
+	}
+
+	return cursor, nil
+}
@@ -0,0 +1,66 @@
+package task_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dheerajgopi/todo-api/models"
+	"github.com/dheerajgopi/todo-api/task"
+)
+
+func TestDueWindowRangeForToday(t *testing.T) {
+	assert := assert.New(t)
+	location, _ := time.LoadLocation("Asia/Kolkata")
+	now := time.Date(2019, 5, 8, 1, 30, 0, 0, location)
+
+	from, to := task.DueWindowRange(task.DueToday, now)
+
+	assert.Equal(time.Date(2019, 5, 8, 0, 0, 0, 0, location), *from)
+	assert.Equal(time.Date(2019, 5, 9, 0, 0, 0, 0, location), *to)
+	assert.Equal(time.Date(2019, 5, 7, 18, 30, 0, 0, time.UTC), from.UTC())
+}
+
+func TestDueWindowRangeForThisWeek(t *testing.T) {
+	assert := assert.New(t)
+	location, _ := time.LoadLocation("America/New_York")
+
+	// Sunday, the last day of the week in which DST starts
+	now := time.Date(2019, 3, 10, 12, 0, 0, 0, location)
+
+	from, to := task.DueWindowRange(task.DueThisWeek, now)
+
+	assert.Equal(time.Date(2019, 3, 4, 0, 0, 0, 0, location), *from)
+	assert.Equal(time.Date(2019, 3, 11, 0, 0, 0, 0, location), *to)
+	assert.Equal(167*time.Hour, to.Sub(*from))
+}
+
+func TestDueWindowRangeForOverdue(t *testing.T) {
+	assert := assert.New(t)
+	now := time.Now()
+
+	from, to := task.DueWindowRange(task.DueOverdue, now)
+
+	assert.Nil(from)
+	assert.Equal(now, *to)
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	assert := assert.New(t)
+	dueAt := time.Date(2019, 5, 8, 10, 0, 0, 0, time.UTC)
+
+	encoded := task.NewCursor(&models.Task{ID: 7, DueAt: &dueAt}, task.SortByDueAt).Encode()
+	cursor, err := task.DecodeCursor(encoded)
+
+	assert.NoError(err)
+	assert.Equal(task.SortByDueAt, cursor.Sort)
+	assert.Equal("2019-05-08T10:00:00Z", cursor.Value)
+	assert.Equal(int64(7), cursor.ID)
+}
+
+func TestDecodeCursorWithInvalidValue(t *testing.T) {
+	_, err := task.DecodeCursor("not-a-cursor")
+
+	assert.Equal(t, task.ErrInvalidCursor, err)
+}
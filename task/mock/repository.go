@@ -7,8 +7,10 @@ package mock
 import (
 	context "context"
 	models "github.com/dheerajgopi/todo-api/models"
+	task "github.com/dheerajgopi/todo-api/task"
 	gomock "github.com/golang/mock/gomock"
 	reflect "reflect"
+	time "time"
 )
 
 // Repository is a mock of Repository interface
@@ -34,6 +36,20 @@ func (m *Repository) EXPECT() *RepositoryMockRecorder {
 	return m.recorder
 }
 
+// Atomic mocks base method
+func (m *Repository) Atomic(arg0 context.Context, arg1 func(context.Context) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Atomic", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Atomic indicates an expected call of Atomic
+func (mr *RepositoryMockRecorder) Atomic(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Atomic", reflect.TypeOf((*Repository)(nil).Atomic), arg0, arg1)
+}
+
 // Create mocks base method
 func (m *Repository) Create(arg0 context.Context, arg1 *models.Task) error {
 	m.ctrl.T.Helper()
@@ -48,19 +64,62 @@ func (mr *RepositoryMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*Repository)(nil).Create), arg0, arg1)
 }
 
+// Delete mocks base method
+func (m *Repository) Delete(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete
+func (mr *RepositoryMockRecorder) Delete(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*Repository)(nil).Delete), arg0, arg1)
+}
+
+// Export mocks base method
+func (m *Repository) Export(arg0 context.Context, arg1 int64, arg2 func(*models.Task) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Export", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Export indicates an expected call of Export
+func (mr *RepositoryMockRecorder) Export(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Export", reflect.TypeOf((*Repository)(nil).Export), arg0, arg1, arg2)
+}
+
+// GetAdjacent mocks base method
+func (m *Repository) GetAdjacent(arg0 context.Context, arg1, arg2, arg3 int64, arg4 bool) (*models.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAdjacent", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(*models.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAdjacent indicates an expected call of GetAdjacent
+func (mr *RepositoryMockRecorder) GetAdjacent(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAdjacent", reflect.TypeOf((*Repository)(nil).GetAdjacent), arg0, arg1, arg2, arg3, arg4)
+}
+
 // GetAllByUserID mocks base method
-func (m *Repository) GetAllByUserID(arg0 context.Context, arg1 int64) ([]*models.Task, error) {
+func (m *Repository) GetAllByUserID(arg0 context.Context, arg1 int64, arg2 *task.ListFilter) ([]*models.Task, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAllByUserID", arg0, arg1)
+	ret := m.ctrl.Call(m, "GetAllByUserID", arg0, arg1, arg2)
 	ret0, _ := ret[0].([]*models.Task)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetAllByUserID indicates an expected call of GetAllByUserID
-func (mr *RepositoryMockRecorder) GetAllByUserID(arg0, arg1 interface{}) *gomock.Call {
+func (mr *RepositoryMockRecorder) GetAllByUserID(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllByUserID", reflect.TypeOf((*Repository)(nil).GetAllByUserID), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllByUserID", reflect.TypeOf((*Repository)(nil).GetAllByUserID), arg0, arg1, arg2)
 }
 
 // GetByID mocks base method
@@ -77,3 +136,162 @@ func (mr *RepositoryMockRecorder) GetByID(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*Repository)(nil).GetByID), arg0, arg1)
 }
+
+// GetDeletedByID mocks base method
+func (m *Repository) GetDeletedByID(arg0 context.Context, arg1 int64) (*models.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeletedByID", arg0, arg1)
+	ret0, _ := ret[0].(*models.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeletedByID indicates an expected call of GetDeletedByID
+func (mr *RepositoryMockRecorder) GetDeletedByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeletedByID", reflect.TypeOf((*Repository)(nil).GetDeletedByID), arg0, arg1)
+}
+
+// GetMaxPosition mocks base method
+func (m *Repository) GetMaxPosition(arg0 context.Context, arg1 int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMaxPosition", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMaxPosition indicates an expected call of GetMaxPosition
+func (mr *RepositoryMockRecorder) GetMaxPosition(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMaxPosition", reflect.TypeOf((*Repository)(nil).GetMaxPosition), arg0, arg1)
+}
+
+// GetPurgeable mocks base method
+func (m *Repository) GetPurgeable(arg0 context.Context, arg1 time.Time, arg2 int) ([]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPurgeable", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPurgeable indicates an expected call of GetPurgeable
+func (mr *RepositoryMockRecorder) GetPurgeable(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPurgeable", reflect.TypeOf((*Repository)(nil).GetPurgeable), arg0, arg1, arg2)
+}
+
+// GetSubtasks mocks base method
+func (m *Repository) GetSubtasks(arg0 context.Context, arg1 int64) ([]*models.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSubtasks", arg0, arg1)
+	ret0, _ := ret[0].([]*models.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSubtasks indicates an expected call of GetSubtasks
+func (mr *RepositoryMockRecorder) GetSubtasks(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubtasks", reflect.TypeOf((*Repository)(nil).GetSubtasks), arg0, arg1)
+}
+
+// GetTrash mocks base method
+func (m *Repository) GetTrash(arg0 context.Context, arg1 int64) ([]*models.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTrash", arg0, arg1)
+	ret0, _ := ret[0].([]*models.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTrash indicates an expected call of GetTrash
+func (mr *RepositoryMockRecorder) GetTrash(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTrash", reflect.TypeOf((*Repository)(nil).GetTrash), arg0, arg1)
+}
+
+// Purge mocks base method
+func (m *Repository) Purge(arg0 context.Context, arg1 []int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Purge", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Purge indicates an expected call of Purge
+func (mr *RepositoryMockRecorder) Purge(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Purge", reflect.TypeOf((*Repository)(nil).Purge), arg0, arg1)
+}
+
+// RebalancePositions mocks base method
+func (m *Repository) RebalancePositions(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RebalancePositions", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RebalancePositions indicates an expected call of RebalancePositions
+func (mr *RepositoryMockRecorder) RebalancePositions(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RebalancePositions", reflect.TypeOf((*Repository)(nil).RebalancePositions), arg0, arg1)
+}
+
+// Restore mocks base method
+func (m *Repository) Restore(arg0 context.Context, arg1 *models.Task) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore
+func (mr *RepositoryMockRecorder) Restore(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*Repository)(nil).Restore), arg0, arg1)
+}
+
+// Update mocks base method
+func (m *Repository) Update(arg0 context.Context, arg1 *models.Task) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update
+func (mr *RepositoryMockRecorder) Update(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*Repository)(nil).Update), arg0, arg1)
+}
+
+// UpdateCompletion mocks base method
+func (m *Repository) UpdateCompletion(arg0 context.Context, arg1 int64, arg2 bool, arg3 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCompletion", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateCompletion indicates an expected call of UpdateCompletion
+func (mr *RepositoryMockRecorder) UpdateCompletion(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCompletion", reflect.TypeOf((*Repository)(nil).UpdateCompletion), arg0, arg1, arg2, arg3)
+}
+
+// UpdatePosition mocks base method
+func (m *Repository) UpdatePosition(arg0 context.Context, arg1, arg2 int64, arg3 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePosition", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePosition indicates an expected call of UpdatePosition
+func (mr *RepositoryMockRecorder) UpdatePosition(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePosition", reflect.TypeOf((*Repository)(nil).UpdatePosition), arg0, arg1, arg2, arg3)
+}
@@ -7,6 +7,7 @@ package mock
 import (
 	context "context"
 	models "github.com/dheerajgopi/todo-api/models"
+	task "github.com/dheerajgopi/todo-api/task"
 	gomock "github.com/golang/mock/gomock"
 	reflect "reflect"
 )
@@ -34,6 +35,36 @@ func (m *Service) EXPECT() *ServiceMockRecorder {
 	return m.recorder
 }
 
+// Authorize mocks base method
+func (m *Service) Authorize(arg0 context.Context, arg1, arg2 int64, arg3, arg4 string) (*models.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Authorize", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(*models.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Authorize indicates an expected call of Authorize
+func (mr *ServiceMockRecorder) Authorize(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authorize", reflect.TypeOf((*Service)(nil).Authorize), arg0, arg1, arg2, arg3, arg4)
+}
+
+// Bulk mocks base method
+func (m *Service) Bulk(arg0 context.Context, arg1 *task.BulkChange, arg2 int64) ([]*task.BulkResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Bulk", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*task.BulkResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Bulk indicates an expected call of Bulk
+func (mr *ServiceMockRecorder) Bulk(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Bulk", reflect.TypeOf((*Service)(nil).Bulk), arg0, arg1, arg2)
+}
+
 // Create mocks base method
 func (m *Service) Create(arg0 context.Context, arg1 *models.Task) error {
 	m.ctrl.T.Helper()
@@ -48,17 +79,119 @@ func (mr *ServiceMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*Service)(nil).Create), arg0, arg1)
 }
 
+// Delete mocks base method
+func (m *Service) Delete(arg0 context.Context, arg1, arg2 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete
+func (mr *ServiceMockRecorder) Delete(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*Service)(nil).Delete), arg0, arg1, arg2)
+}
+
+// Export mocks base method
+func (m *Service) Export(arg0 context.Context, arg1 int64, arg2 func(*models.Task) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Export", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Export indicates an expected call of Export
+func (mr *ServiceMockRecorder) Export(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Export", reflect.TypeOf((*Service)(nil).Export), arg0, arg1, arg2)
+}
+
+// GetByID mocks base method
+func (m *Service) GetByID(arg0 context.Context, arg1, arg2 int64) (*models.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*models.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID
+func (mr *ServiceMockRecorder) GetByID(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*Service)(nil).GetByID), arg0, arg1, arg2)
+}
+
+// Import mocks base method
+func (m *Service) Import(arg0 context.Context, arg1 []*models.Task, arg2 int64, arg3 bool) ([]*task.ImportFailure, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Import", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]*task.ImportFailure)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Import indicates an expected call of Import
+func (mr *ServiceMockRecorder) Import(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Import", reflect.TypeOf((*Service)(nil).Import), arg0, arg1, arg2, arg3)
+}
+
 // List mocks base method
-func (m *Service) List(arg0 context.Context, arg1 int64) ([]*models.Task, error) {
+func (m *Service) List(arg0 context.Context, arg1 int64, arg2 *task.ListFilter) (*task.Page, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "List", arg0, arg1)
-	ret0, _ := ret[0].([]*models.Task)
+	ret := m.ctrl.Call(m, "List", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*task.Page)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // List indicates an expected call of List
-func (mr *ServiceMockRecorder) List(arg0, arg1 interface{}) *gomock.Call {
+func (mr *ServiceMockRecorder) List(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*Service)(nil).List), arg0, arg1, arg2)
+}
+
+// ListSubtasks mocks base method
+func (m *Service) ListSubtasks(arg0 context.Context, arg1, arg2 int64) ([]*models.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSubtasks", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*models.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSubtasks indicates an expected call of ListSubtasks
+func (mr *ServiceMockRecorder) ListSubtasks(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSubtasks", reflect.TypeOf((*Service)(nil).ListSubtasks), arg0, arg1, arg2)
+}
+
+// Move mocks base method
+func (m *Service) Move(arg0 context.Context, arg1, arg2 int64, arg3 bool, arg4 int64) (*models.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Move", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(*models.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Move indicates an expected call of Move
+func (mr *ServiceMockRecorder) Move(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Move", reflect.TypeOf((*Service)(nil).Move), arg0, arg1, arg2, arg3, arg4)
+}
+
+// Update mocks base method
+func (m *Service) Update(arg0 context.Context, arg1 *models.Task, arg2 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update
+func (mr *ServiceMockRecorder) Update(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*Service)(nil).List), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*Service)(nil).Update), arg0, arg1, arg2)
 }
@@ -0,0 +1,16 @@
+package task
+
+// PositionGap is the distance kept between the positions of adjacent tasks.
+// A task moved between two others takes the middle position, so positions
+// have to be renumbered only after the gap between two tasks is used up.
+const PositionGap = int64(1 << 16)
+
+// PositionBetween returns the middle position between two adjacent positions.
+// False is returned if there is no free position left between them.
+func PositionBetween(lower int64, upper int64) (int64, bool) {
+	if upper-lower < 2 {
+		return 0, false
+	}
+
+	return lower + (upper-lower)/2, true
+}
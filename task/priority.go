@@ -0,0 +1,33 @@
+package task
+
+// Task priorities, from lowest to highest
+const (
+	PriorityNone = iota
+	PriorityLow
+	PriorityMedium
+	PriorityHigh
+	PriorityUrgent
+)
+
+// priorityNames holds the API names of task priorities
+var priorityNames = []string{"none", "low", "medium", "high", "urgent"}
+
+// PriorityName returns the API name of a task priority
+func PriorityName(priority int) string {
+	if priority < PriorityNone || priority > PriorityUrgent {
+		return priorityNames[PriorityNone]
+	}
+
+	return priorityNames[priority]
+}
+
+// ParsePriority returns the task priority with the given API name
+func ParsePriority(name string) (int, bool) {
+	for priority, priorityName := range priorityNames {
+		if priorityName == name {
+			return priority, true
+		}
+	}
+
+	return PriorityNone, false
+}
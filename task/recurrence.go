@@ -0,0 +1,517 @@
+package task
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Recurrence frequencies
+const (
+	FrequencyDaily   = "DAILY"
+	FrequencyWeekly  = "WEEKLY"
+	FrequencyMonthly = "MONTHLY"
+	FrequencyYearly  = "YEARLY"
+)
+
+// maxRecurrencePeriods limits the number of periods searched for the next occurrence,
+// so that rules which never match a valid date do not loop forever
+const maxRecurrencePeriods = 50000
+
+// weekdayNames holds the RFC 5545 names of weekdays, starting from sunday like time.Weekday
+var weekdayNames = []string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+// Recurrence represents a subset of RFC 5545 recurrence rules.
+// Supported parts are FREQ, INTERVAL, COUNT, UNTIL, BYDAY, BYMONTHDAY and BYMONTH, with weeks starting on monday.
+type Recurrence struct {
+	Frequency string
+	Interval  int
+	Count     int
+	// Until is either an UTC time, or a date at midnight UTC if UntilDate is set
+	Until     *time.Time
+	UntilDate bool
+	Weekdays  []RecurrenceWeekday
+	MonthDays []int
+	Months    []time.Month
+}
+
+// RecurrenceWeekday is a BYDAY entry. Ordinal selects the nth weekday of the month, counting from the end if negative.
+// Every matching weekday is selected if ordinal is zero.
+type RecurrenceWeekday struct {
+	Weekday time.Weekday
+	Ordinal int
+}
+
+// ParseRecurrence parses a recurrence rule like FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE. An optional RRULE: prefix is allowed.
+func ParseRecurrence(rule string) (*Recurrence, error) {
+	rule = strings.TrimSpace(rule)
+
+	if strings.HasPrefix(strings.ToUpper(rule), "RRULE:") {
+		rule = rule[len("RRULE:"):]
+	}
+
+	recurrence := &Recurrence{Interval: 1}
+	seen := make(map[string]bool)
+
+	for _, part := range strings.Split(rule, ";") {
+		pair := strings.SplitN(part, "=", 2)
+
+		if len(pair) != 2 || pair[1] == "" {
+			return nil, fmt.Errorf("Invalid rule part %s", part)
+		}
+
+		name := strings.ToUpper(strings.TrimSpace(pair[0]))
+		value := strings.ToUpper(strings.TrimSpace(pair[1]))
+
+		if seen[name] {
+			return nil, fmt.Errorf("Rule part %s is repeated", name)
+		}
+
+		seen[name] = true
+
+		var err error
+
+		switch name {
+		case "FREQ":
+			err = recurrence.parseFrequency(value)
+		case "INTERVAL":
+			recurrence.Interval, err = parsePositive(name, value)
+		case "COUNT":
+			recurrence.Count, err = parsePositive(name, value)
+		case "UNTIL":
+			err = recurrence.parseUntil(value)
+		case "BYDAY":
+			err = recurrence.parseWeekdays(value)
+		case "BYMONTHDAY":
+			err = recurrence.parseMonthDays(value)
+		case "BYMONTH":
+			err = recurrence.parseMonths(value)
+		default:
+			return nil, fmt.Errorf("Rule part %s is not supported", name)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := recurrence.validate(); err != nil {
+		return nil, err
+	}
+
+	return recurrence, nil
+}
+
+// String returns the rule in a canonical form, without the RRULE: prefix
+func (recurrence *Recurrence) String() string {
+	parts := []string{"FREQ=" + recurrence.Frequency}
+
+	if recurrence.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(recurrence.Interval))
+	}
+
+	if len(recurrence.Months) > 0 {
+		months := make([]string, 0, len(recurrence.Months))
+
+		for _, month := range recurrence.Months {
+			months = append(months, strconv.Itoa(int(month)))
+		}
+
+		parts = append(parts, "BYMONTH="+strings.Join(months, ","))
+	}
+
+	if len(recurrence.MonthDays) > 0 {
+		days := make([]string, 0, len(recurrence.MonthDays))
+
+		for _, day := range recurrence.MonthDays {
+			days = append(days, strconv.Itoa(day))
+		}
+
+		parts = append(parts, "BYMONTHDAY="+strings.Join(days, ","))
+	}
+
+	if len(recurrence.Weekdays) > 0 {
+		weekdays := make([]string, 0, len(recurrence.Weekdays))
+
+		for _, weekday := range recurrence.Weekdays {
+			name := weekdayNames[weekday.Weekday]
+
+			if weekday.Ordinal != 0 {
+				name = strconv.Itoa(weekday.Ordinal) + name
+			}
+
+			weekdays = append(weekdays, name)
+		}
+
+		parts = append(parts, "BYDAY="+strings.Join(weekdays, ","))
+	}
+
+	if recurrence.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(recurrence.Count))
+	}
+
+	if recurrence.Until != nil && recurrence.UntilDate {
+		parts = append(parts, "UNTIL="+recurrence.Until.Format("20060102"))
+	} else if recurrence.Until != nil {
+		parts = append(parts, "UNTIL="+recurrence.Until.Format("20060102T150405Z"))
+	}
+
+	return strings.Join(parts, ";")
+}
+
+// Next returns the first occurrence after the given time, in a series starting at start.
+// Start is always the first occurrence of the series, and every occurrence keeps the wall clock time of start in its location.
+// Occurrences falling in a DST gap are shifted forward by the length of the gap,
+// and the first of the two possible times is taken for occurrences in a DST overlap.
+// False is returned if the series ends before the given time.
+func (recurrence *Recurrence) Next(start time.Time, after time.Time) (time.Time, bool) {
+	if start.After(after) {
+		return start, true
+	}
+
+	count := 1
+
+	for period := 0; period < maxRecurrencePeriods; period++ {
+		for _, date := range recurrence.datesInPeriod(start, period) {
+			occurrence := localTime(date, start)
+
+			if !occurrence.After(start) {
+				continue
+			}
+
+			if recurrence.isAfterUntil(occurrence) {
+				return time.Time{}, false
+			}
+
+			count++
+
+			if recurrence.Count > 0 && count > recurrence.Count {
+				return time.Time{}, false
+			}
+
+			if occurrence.After(after) {
+				return occurrence, true
+			}
+		}
+	}
+
+	return time.Time{}, false
+}
+
+// datesInPeriod returns the sorted dates of the nth period of the series, as midnight UTC times
+func (recurrence *Recurrence) datesInPeriod(start time.Time, period int) []time.Time {
+	year, month, day := start.Date()
+	step := period * recurrence.Interval
+	dates := make([]time.Time, 0)
+
+	switch recurrence.Frequency {
+	case FrequencyDaily:
+		dates = append(dates, time.Date(year, month, day+step, 0, 0, 0, 0, time.UTC))
+	case FrequencyWeekly:
+		weekStart := time.Date(year, month, day-(int(start.Weekday())+6)%7+7*step, 0, 0, 0, 0, time.UTC)
+
+		if len(recurrence.Weekdays) == 0 {
+			dates = append(dates, weekStart.AddDate(0, 0, (int(start.Weekday())+6)%7))
+		}
+
+		for _, weekday := range recurrence.Weekdays {
+			dates = append(dates, weekStart.AddDate(0, 0, (int(weekday.Weekday)+6)%7))
+		}
+	case FrequencyMonthly:
+		dates = recurrence.datesInMonth(year, month+time.Month(step), day)
+	case FrequencyYearly:
+		months := recurrence.Months
+
+		if len(months) == 0 {
+			months = []time.Month{month}
+		}
+
+		for _, byMonth := range months {
+			dates = append(dates, recurrence.datesInMonth(year+step, byMonth, day)...)
+		}
+	}
+
+	sort.Slice(dates, func(i, j int) bool {
+		return dates[i].Before(dates[j])
+	})
+
+	filtered := make([]time.Time, 0, len(dates))
+
+	for i, date := range dates {
+		if i > 0 && date.Equal(dates[i-1]) {
+			continue
+		}
+
+		if recurrence.matchesMonth(date) && recurrence.matchesWeekday(date) {
+			filtered = append(filtered, date)
+		}
+	}
+
+	return filtered
+}
+
+// datesInMonth expands BYMONTHDAY and BYDAY within a month, using both as filters if both are given.
+// Day of the series start is used if neither is given. Days which do not exist in the month are skipped.
+func (recurrence *Recurrence) datesInMonth(year int, month time.Month, startDay int) []time.Time {
+	first := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+	year, month = first.Year(), first.Month()
+	daysInMonth := first.AddDate(0, 1, -1).Day()
+	dates := make([]time.Time, 0)
+
+	for day := 1; day <= daysInMonth; day++ {
+		date := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+		matches := true
+
+		if len(recurrence.MonthDays) > 0 {
+			matches = recurrence.matchesMonthDay(day, daysInMonth)
+		}
+
+		if len(recurrence.Weekdays) > 0 {
+			matches = matches && recurrence.matchesOrdinalWeekday(date, daysInMonth)
+		}
+
+		if len(recurrence.MonthDays) == 0 && len(recurrence.Weekdays) == 0 {
+			matches = day == startDay
+		}
+
+		if matches {
+			dates = append(dates, date)
+		}
+	}
+
+	return dates
+}
+
+// matchesMonthDay checks a day against BYMONTHDAY, where negative days count from the end of the month
+func (recurrence *Recurrence) matchesMonthDay(day int, daysInMonth int) bool {
+	for _, monthDay := range recurrence.MonthDays {
+		if monthDay == day || daysInMonth+1+monthDay == day {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchesOrdinalWeekday checks a date against BYDAY, within its month
+func (recurrence *Recurrence) matchesOrdinalWeekday(date time.Time, daysInMonth int) bool {
+	for _, weekday := range recurrence.Weekdays {
+		if weekday.Weekday != date.Weekday() {
+			continue
+		}
+
+		if weekday.Ordinal == 0 ||
+			weekday.Ordinal == (date.Day()+6)/7 ||
+			weekday.Ordinal == -((daysInMonth-date.Day())/7+1) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchesMonth checks a date against BYMONTH, which limits daily, weekly and monthly rules
+func (recurrence *Recurrence) matchesMonth(date time.Time) bool {
+	if len(recurrence.Months) == 0 || recurrence.Frequency == FrequencyYearly {
+		return true
+	}
+
+	for _, month := range recurrence.Months {
+		if month == date.Month() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchesWeekday checks a date against BYDAY, which limits daily rules. Other rules expand BYDAY instead.
+func (recurrence *Recurrence) matchesWeekday(date time.Time) bool {
+	if len(recurrence.Weekdays) == 0 || recurrence.Frequency != FrequencyDaily {
+		return true
+	}
+
+	for _, weekday := range recurrence.Weekdays {
+		if weekday.Weekday == date.Weekday() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isAfterUntil checks whether an occurrence is past the end of the series
+func (recurrence *Recurrence) isAfterUntil(occurrence time.Time) bool {
+	if recurrence.Until == nil {
+		return false
+	}
+
+	if recurrence.UntilDate {
+		year, month, day := occurrence.Date()
+
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC).After(*recurrence.Until)
+	}
+
+	return occurrence.After(*recurrence.Until)
+}
+
+// validate checks the combinations of rule parts
+func (recurrence *Recurrence) validate() error {
+	if recurrence.Frequency == "" {
+		return errors.New("FREQ is required")
+	}
+
+	if recurrence.Count > 0 && recurrence.Until != nil {
+		return errors.New("COUNT and UNTIL can not be used together")
+	}
+
+	if len(recurrence.MonthDays) > 0 && recurrence.Frequency != FrequencyMonthly && recurrence.Frequency != FrequencyYearly {
+		return errors.New("BYMONTHDAY is allowed only for MONTHLY and YEARLY rules")
+	}
+
+	if len(recurrence.Weekdays) > 0 && recurrence.Frequency == FrequencyYearly && len(recurrence.Months) == 0 {
+		return errors.New("BYDAY is allowed for YEARLY rules only along with BYMONTH")
+	}
+
+	for _, weekday := range recurrence.Weekdays {
+		if weekday.Ordinal != 0 && recurrence.Frequency != FrequencyMonthly && recurrence.Frequency != FrequencyYearly {
+			return errors.New("BYDAY ordinals are allowed only for MONTHLY and YEARLY rules")
+		}
+	}
+
+	return nil
+}
+
+// parseFrequency parses the FREQ part
+func (recurrence *Recurrence) parseFrequency(value string) error {
+	switch value {
+	case FrequencyDaily, FrequencyWeekly, FrequencyMonthly, FrequencyYearly:
+		recurrence.Frequency = value
+		return nil
+	default:
+		return errors.New("FREQ should be one of DAILY, WEEKLY, MONTHLY, YEARLY")
+	}
+}
+
+// parseUntil parses the UNTIL part, which is either a date or an UTC time
+func (recurrence *Recurrence) parseUntil(value string) error {
+	until, err := time.Parse("20060102T150405Z", value)
+
+	if err != nil {
+		until, err = time.Parse("20060102", value)
+		recurrence.UntilDate = true
+	}
+
+	if err != nil {
+		return errors.New("UNTIL should be a date like 20191231 or an UTC time like 20191231T235959Z")
+	}
+
+	recurrence.Until = &until
+
+	return nil
+}
+
+// parseWeekdays parses the BYDAY part, with optional ordinals like 1MO or -1FR
+func (recurrence *Recurrence) parseWeekdays(value string) error {
+	for _, entry := range strings.Split(value, ",") {
+		if len(entry) < 2 {
+			return fmt.Errorf("Invalid BYDAY value %s", entry)
+		}
+
+		weekday := indexOf(weekdayNames, entry[len(entry)-2:])
+		ordinal := 0
+
+		if prefix := entry[:len(entry)-2]; prefix != "" {
+			var err error
+
+			if ordinal, err = strconv.Atoi(prefix); err != nil || ordinal == 0 || ordinal < -5 || ordinal > 5 {
+				return fmt.Errorf("Invalid BYDAY value %s", entry)
+			}
+		}
+
+		if weekday < 0 {
+			return fmt.Errorf("Invalid BYDAY value %s", entry)
+		}
+
+		recurrence.Weekdays = append(recurrence.Weekdays, RecurrenceWeekday{
+			Weekday: time.Weekday(weekday),
+			Ordinal: ordinal,
+		})
+	}
+
+	return nil
+}
+
+// parseMonthDays parses the BYMONTHDAY part, where negative days count from the end of the month
+func (recurrence *Recurrence) parseMonthDays(value string) error {
+	for _, entry := range strings.Split(value, ",") {
+		day, err := strconv.Atoi(entry)
+
+		if err != nil || day == 0 || day < -31 || day > 31 {
+			return fmt.Errorf("Invalid BYMONTHDAY value %s", entry)
+		}
+
+		recurrence.MonthDays = append(recurrence.MonthDays, day)
+	}
+
+	return nil
+}
+
+// parseMonths parses the BYMONTH part
+func (recurrence *Recurrence) parseMonths(value string) error {
+	for _, entry := range strings.Split(value, ",") {
+		month, err := strconv.Atoi(entry)
+
+		if err != nil || month < 1 || month > 12 {
+			return fmt.Errorf("Invalid BYMONTH value %s", entry)
+		}
+
+		recurrence.Months = append(recurrence.Months, time.Month(month))
+	}
+
+	return nil
+}
+
+// parsePositive parses the value of a rule part which should be a positive integer
+func parsePositive(name string, value string) (int, error) {
+	number, err := strconv.Atoi(value)
+
+	if err != nil || number < 1 {
+		return 0, fmt.Errorf("%s should be a positive integer", name)
+	}
+
+	return number, nil
+}
+
+// localTime returns the given date at the wall clock time of start, in the location of start.
+// Times in a DST gap are read with the UTC offset in effect before the gap, which moves them forward by the gap.
+func localTime(date time.Time, start time.Time) time.Time {
+	year, month, day := date.Date()
+	hour, min, sec := start.Clock()
+	loc := start.Location()
+
+	occurrence := time.Date(year, month, day, hour, min, sec, start.Nanosecond(), loc)
+
+	if occurrence.Day() == day && occurrence.Hour() == hour && occurrence.Minute() == min {
+		return occurrence
+	}
+
+	_, offsetBeforeGap := occurrence.Add(-6 * time.Hour).Zone()
+	wallClock := time.Date(year, month, day, hour, min, sec, start.Nanosecond(), time.UTC)
+
+	return wallClock.Add(-time.Duration(offsetBeforeGap) * time.Second).In(loc)
+}
+
+// indexOf returns the index of the value in the list, or -1 if it is missing
+func indexOf(values []string, value string) int {
+	for i, existing := range values {
+		if existing == value {
+			return i
+		}
+	}
+
+	return -1
+}
@@ -0,0 +1,336 @@
+package task_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dheerajgopi/todo-api/task"
+)
+
+func TestParseRecurrence(t *testing.T) {
+	assert := assert.New(t)
+
+	recurrence, err := task.ParseRecurrence("RRULE:freq=weekly;interval=2;byday=MO,WE;count=10")
+
+	assert.NoError(err)
+	assert.Equal(task.FrequencyWeekly, recurrence.Frequency)
+	assert.Equal(2, recurrence.Interval)
+	assert.Equal(10, recurrence.Count)
+	assert.Equal([]task.RecurrenceWeekday{{Weekday: time.Monday}, {Weekday: time.Wednesday}}, recurrence.Weekdays)
+	assert.Equal("FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE;COUNT=10", recurrence.String())
+}
+
+func TestParseRecurrenceWithUntil(t *testing.T) {
+	assert := assert.New(t)
+
+	recurrence, err := task.ParseRecurrence("FREQ=MONTHLY;BYDAY=-1FR;UNTIL=20191231")
+
+	assert.NoError(err)
+	assert.True(recurrence.UntilDate)
+	assert.Equal(time.Date(2019, 12, 31, 0, 0, 0, 0, time.UTC), *recurrence.Until)
+	assert.Equal("FREQ=MONTHLY;BYDAY=-1FR;UNTIL=20191231", recurrence.String())
+
+	recurrence, err = task.ParseRecurrence("FREQ=YEARLY;BYMONTH=11;BYDAY=4TH;UNTIL=20251127T170000Z")
+
+	assert.NoError(err)
+	assert.False(recurrence.UntilDate)
+	assert.Equal("FREQ=YEARLY;BYMONTH=11;BYDAY=4TH;UNTIL=20251127T170000Z", recurrence.String())
+}
+
+func TestParseInvalidRecurrence(t *testing.T) {
+	assert := assert.New(t)
+
+	rules := map[string]string{
+		"":                                     "Invalid rule part ",
+		"INTERVAL=2":                           "FREQ is required",
+		"FREQ=HOURLY":                          "FREQ should be one of DAILY, WEEKLY, MONTHLY, YEARLY",
+		"FREQ=DAILY;INTERVAL=0":                "INTERVAL should be a positive integer",
+		"FREQ=DAILY;FREQ=WEEKLY":               "Rule part FREQ is repeated",
+		"FREQ=DAILY;BYHOUR=9":                  "Rule part BYHOUR is not supported",
+		"FREQ=DAILY;COUNT=2;UNTIL=20191231":    "COUNT and UNTIL can not be used together",
+		"FREQ=DAILY;UNTIL=2019-12-31":          "UNTIL should be a date like 20191231 or an UTC time like 20191231T235959Z",
+		"FREQ=WEEKLY;BYDAY=XX":                 "Invalid BYDAY value XX",
+		"FREQ=WEEKLY;BYDAY=1MO":                "BYDAY ordinals are allowed only for MONTHLY and YEARLY rules",
+		"FREQ=MONTHLY;BYDAY=6MO":               "Invalid BYDAY value 6MO",
+		"FREQ=WEEKLY;BYMONTHDAY=1":             "BYMONTHDAY is allowed only for MONTHLY and YEARLY rules",
+		"FREQ=MONTHLY;BYMONTHDAY=32":           "Invalid BYMONTHDAY value 32",
+		"FREQ=YEARLY;BYMONTH=13":               "Invalid BYMONTH value 13",
+		"FREQ=YEARLY;BYDAY=MO":                 "BYDAY is allowed for YEARLY rules only along with BYMONTH",
+		"FREQ=DAILY;INTERVAL":                  "Invalid rule part INTERVAL",
+		"FREQ=MONTHLY;BYMONTHDAY=1;BYMONTH=x1": "Invalid BYMONTH value X1",
+	}
+
+	for rule, message := range rules {
+		recurrence, err := task.ParseRecurrence(rule)
+
+		assert.Nil(recurrence, rule)
+		assert.EqualError(err, message, rule)
+	}
+}
+
+func TestNextDaily(t *testing.T) {
+	start := time.Date(2019, 5, 8, 9, 0, 0, 0, time.UTC)
+
+	assertOccurrences(t, "FREQ=DAILY;INTERVAL=3", start, start,
+		time.Date(2019, 5, 11, 9, 0, 0, 0, time.UTC),
+		time.Date(2019, 5, 14, 9, 0, 0, 0, time.UTC),
+	)
+}
+
+func TestNextDailyOnWeekdays(t *testing.T) {
+	// Friday
+	start := time.Date(2019, 5, 10, 9, 0, 0, 0, time.UTC)
+
+	assertOccurrences(t, "FREQ=DAILY;BYDAY=MO,TU,WE,TH,FR", start, start,
+		time.Date(2019, 5, 13, 9, 0, 0, 0, time.UTC),
+		time.Date(2019, 5, 14, 9, 0, 0, 0, time.UTC),
+	)
+}
+
+func TestNextWeeklyWithInterval(t *testing.T) {
+	// Wednesday
+	start := time.Date(2019, 5, 8, 9, 0, 0, 0, time.UTC)
+
+	assertOccurrences(t, "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE,FR", start, start,
+		time.Date(2019, 5, 10, 9, 0, 0, 0, time.UTC),
+		time.Date(2019, 5, 20, 9, 0, 0, 0, time.UTC),
+		time.Date(2019, 5, 22, 9, 0, 0, 0, time.UTC),
+		time.Date(2019, 5, 24, 9, 0, 0, 0, time.UTC),
+		time.Date(2019, 6, 3, 9, 0, 0, 0, time.UTC),
+	)
+}
+
+func TestNextWeeklyDefaultsToStartWeekday(t *testing.T) {
+	// Sunday, which is the last day of the week
+	start := time.Date(2019, 5, 12, 18, 0, 0, 0, time.UTC)
+
+	assertOccurrences(t, "FREQ=WEEKLY", start, start,
+		time.Date(2019, 5, 19, 18, 0, 0, 0, time.UTC),
+		time.Date(2019, 5, 26, 18, 0, 0, 0, time.UTC),
+	)
+}
+
+func TestNextMonthlySkipsShortMonths(t *testing.T) {
+	start := time.Date(2019, 1, 31, 9, 0, 0, 0, time.UTC)
+
+	assertOccurrences(t, "FREQ=MONTHLY", start, start,
+		time.Date(2019, 3, 31, 9, 0, 0, 0, time.UTC),
+		time.Date(2019, 5, 31, 9, 0, 0, 0, time.UTC),
+		time.Date(2019, 7, 31, 9, 0, 0, 0, time.UTC),
+	)
+}
+
+func TestNextMonthlyOnLastDay(t *testing.T) {
+	start := time.Date(2019, 1, 31, 9, 0, 0, 0, time.UTC)
+
+	assertOccurrences(t, "FREQ=MONTHLY;BYMONTHDAY=-1", start, start,
+		time.Date(2019, 2, 28, 9, 0, 0, 0, time.UTC),
+		time.Date(2019, 3, 31, 9, 0, 0, 0, time.UTC),
+		time.Date(2019, 4, 30, 9, 0, 0, 0, time.UTC),
+	)
+}
+
+func TestNextMonthlyOnOrdinalWeekday(t *testing.T) {
+	start := time.Date(2019, 5, 6, 10, 0, 0, 0, time.UTC)
+
+	assertOccurrences(t, "FREQ=MONTHLY;BYDAY=1MO,-1FR", start, start,
+		time.Date(2019, 5, 31, 10, 0, 0, 0, time.UTC),
+		time.Date(2019, 6, 3, 10, 0, 0, 0, time.UTC),
+		time.Date(2019, 6, 28, 10, 0, 0, 0, time.UTC),
+		time.Date(2019, 7, 1, 10, 0, 0, 0, time.UTC),
+	)
+}
+
+func TestNextMonthlyWithMonthDayAndWeekday(t *testing.T) {
+	// Friday the 13th
+	start := time.Date(2019, 9, 13, 0, 0, 0, 0, time.UTC)
+
+	assertOccurrences(t, "FREQ=MONTHLY;BYMONTHDAY=13;BYDAY=FR", start, start,
+		time.Date(2019, 12, 13, 0, 0, 0, 0, time.UTC),
+		time.Date(2020, 3, 13, 0, 0, 0, 0, time.UTC),
+		time.Date(2020, 11, 13, 0, 0, 0, 0, time.UTC),
+	)
+}
+
+func TestNextYearlyOnLeapDay(t *testing.T) {
+	start := time.Date(2016, 2, 29, 8, 0, 0, 0, time.UTC)
+
+	assertOccurrences(t, "FREQ=YEARLY", start, start,
+		time.Date(2020, 2, 29, 8, 0, 0, 0, time.UTC),
+		time.Date(2024, 2, 29, 8, 0, 0, 0, time.UTC),
+	)
+}
+
+func TestNextYearlyOnOrdinalWeekdayOfMonth(t *testing.T) {
+	start := time.Date(2019, 11, 28, 17, 0, 0, 0, time.UTC)
+
+	assertOccurrences(t, "FREQ=YEARLY;BYMONTH=11;BYDAY=4TH", start, start,
+		time.Date(2020, 11, 26, 17, 0, 0, 0, time.UTC),
+		time.Date(2021, 11, 25, 17, 0, 0, 0, time.UTC),
+	)
+}
+
+func TestNextWithCount(t *testing.T) {
+	assert := assert.New(t)
+	recurrence, _ := task.ParseRecurrence("FREQ=DAILY;COUNT=3")
+	start := time.Date(2019, 5, 8, 9, 0, 0, 0, time.UTC)
+
+	next, ok := recurrence.Next(start, start.AddDate(0, 0, 1))
+
+	assert.True(ok)
+	assert.Equal(time.Date(2019, 5, 10, 9, 0, 0, 0, time.UTC), next)
+
+	_, ok = recurrence.Next(start, next)
+
+	assert.False(ok)
+}
+
+func TestNextWithUntilDate(t *testing.T) {
+	assert := assert.New(t)
+	recurrence, _ := task.ParseRecurrence("FREQ=DAILY;UNTIL=20190510")
+	location, _ := time.LoadLocation("America/Los_Angeles")
+
+	// Last occurrence is on the until date in the location of the series, even though it is a day later in UTC
+	start := time.Date(2019, 5, 8, 20, 0, 0, 0, location)
+	last := time.Date(2019, 5, 10, 20, 0, 0, 0, location)
+
+	next, ok := recurrence.Next(start, start.AddDate(0, 0, 1))
+
+	assert.True(ok)
+	assert.Equal(last, next)
+
+	_, ok = recurrence.Next(start, last)
+
+	assert.False(ok)
+}
+
+func TestNextWithUntilTime(t *testing.T) {
+	assert := assert.New(t)
+	recurrence, _ := task.ParseRecurrence("FREQ=DAILY;UNTIL=20190510T085959Z")
+	start := time.Date(2019, 5, 8, 9, 0, 0, 0, time.UTC)
+
+	next, ok := recurrence.Next(start, start)
+
+	assert.True(ok)
+	assert.Equal(time.Date(2019, 5, 9, 9, 0, 0, 0, time.UTC), next)
+
+	_, ok = recurrence.Next(start, next)
+
+	assert.False(ok)
+}
+
+func TestNextBeforeStart(t *testing.T) {
+	assert := assert.New(t)
+	recurrence, _ := task.ParseRecurrence("FREQ=WEEKLY;BYDAY=MO")
+
+	// Start is the first occurrence, even though it is not a monday
+	start := time.Date(2019, 5, 8, 9, 0, 0, 0, time.UTC)
+
+	next, ok := recurrence.Next(start, start.Add(-time.Hour))
+
+	assert.True(ok)
+	assert.Equal(start, next)
+}
+
+func TestNextForRuleWithoutOccurrences(t *testing.T) {
+	assert := assert.New(t)
+	recurrence, _ := task.ParseRecurrence("FREQ=YEARLY;BYMONTH=2;BYMONTHDAY=30")
+	start := time.Date(2019, 5, 8, 9, 0, 0, 0, time.UTC)
+
+	_, ok := recurrence.Next(start, start)
+
+	assert.False(ok)
+}
+
+func TestNextKeepsWallClockAcrossDST(t *testing.T) {
+	location, _ := time.LoadLocation("America/New_York")
+	start := time.Date(2019, 3, 8, 9, 0, 0, 0, location)
+
+	occurrences := assertOccurrences(t, "FREQ=DAILY", start, start,
+		time.Date(2019, 3, 9, 9, 0, 0, 0, location),
+		time.Date(2019, 3, 10, 9, 0, 0, 0, location),
+		time.Date(2019, 3, 11, 9, 0, 0, 0, location),
+	)
+
+	// Only 23 hours pass on the day DST starts
+	assert.Equal(t, 23*time.Hour, occurrences[1].Sub(occurrences[0]))
+	assert.Equal(t, 14, occurrences[0].UTC().Hour())
+	assert.Equal(t, 13, occurrences[1].UTC().Hour())
+}
+
+func TestNextInDSTGap(t *testing.T) {
+	location, _ := time.LoadLocation("America/New_York")
+	start := time.Date(2019, 3, 9, 2, 30, 0, 0, location)
+
+	// 02:30 does not exist on the day DST starts, so it is moved forward by the one hour gap
+	assertOccurrences(t, "FREQ=DAILY", start, start,
+		time.Date(2019, 3, 10, 3, 30, 0, 0, location),
+		time.Date(2019, 3, 11, 2, 30, 0, 0, location),
+	)
+}
+
+func TestNextInHalfHourDSTGap(t *testing.T) {
+	location, _ := time.LoadLocation("Australia/Lord_Howe")
+	start := time.Date(2019, 10, 5, 2, 15, 0, 0, location)
+
+	assertOccurrences(t, "FREQ=DAILY", start, start,
+		time.Date(2019, 10, 6, 2, 45, 0, 0, location),
+		time.Date(2019, 10, 7, 2, 15, 0, 0, location),
+	)
+}
+
+func TestNextInDSTOverlap(t *testing.T) {
+	assert := assert.New(t)
+	location, _ := time.LoadLocation("America/New_York")
+	start := time.Date(2019, 11, 2, 1, 30, 0, 0, location)
+
+	occurrences := assertOccurrences(t, "FREQ=DAILY", start, start,
+		time.Date(2019, 11, 3, 1, 30, 0, 0, location),
+		time.Date(2019, 11, 4, 1, 30, 0, 0, location),
+	)
+
+	// 01:30 happens twice on the day DST ends, and the first one is taken
+	_, offset := occurrences[0].Zone()
+
+	assert.Equal(-4*60*60, offset)
+	assert.Equal(25*time.Hour, occurrences[1].Sub(occurrences[0]))
+}
+
+func TestNextWeeklyAcrossDSTInSouthernHemisphere(t *testing.T) {
+	location, _ := time.LoadLocation("Australia/Sydney")
+
+	// DST ends on 7 April 2019 in Sydney
+	start := time.Date(2019, 4, 1, 8, 0, 0, 0, location)
+
+	occurrences := assertOccurrences(t, "FREQ=WEEKLY;BYDAY=MO", start, start,
+		time.Date(2019, 4, 8, 8, 0, 0, 0, location),
+	)
+
+	assert.Equal(t, 7*24*time.Hour+time.Hour, occurrences[0].Sub(start))
+}
+
+// assertOccurrences checks the occurrences which follow the given time one after another, and returns them
+func assertOccurrences(t *testing.T, rule string, start time.Time, after time.Time, expected ...time.Time) []time.Time {
+	recurrence, err := task.ParseRecurrence(rule)
+
+	if err != nil {
+		t.Fatalf("Unexpected error while parsing recurrence rule: %s", err)
+	}
+
+	occurrences := make([]time.Time, 0, len(expected))
+
+	for _, expectedOccurrence := range expected {
+		next, ok := recurrence.Next(start, after)
+
+		assert.True(t, ok)
+		assert.True(t, expectedOccurrence.Equal(next), "expected %s, got %s", expectedOccurrence, next)
+		assert.Equal(t, expectedOccurrence.Location(), next.Location())
+
+		occurrences = append(occurrences, next)
+		after = next
+	}
+
+	return occurrences
+}
@@ -2,13 +2,29 @@ package task
 
 import (
 	"context"
+	"time"
 
 	"github.com/dheerajgopi/todo-api/models"
 )
 
 // Repository represents task's repository contract
 type Repository interface {
-	GetAllByUserID(ctx context.Context, userID int64) ([]*models.Task, error)
+	GetAllByUserID(ctx context.Context, userID int64, filter *ListFilter) ([]*models.Task, error)
 	GetByID(ctx context.Context, id int64) (*models.Task, error)
+	GetSubtasks(ctx context.Context, parentID int64) ([]*models.Task, error)
 	Create(ctx context.Context, task *models.Task) error
+	Update(ctx context.Context, task *models.Task) error
+	Delete(ctx context.Context, id int64) error
+	GetTrash(ctx context.Context, userID int64) ([]*models.Task, error)
+	GetDeletedByID(ctx context.Context, id int64) (*models.Task, error)
+	Restore(ctx context.Context, task *models.Task) error
+	GetPurgeable(ctx context.Context, before time.Time, limit int) ([]int64, error)
+	Purge(ctx context.Context, ids []int64) error
+	UpdateCompletion(ctx context.Context, id int64, isComplete bool, updatedAt time.Time) error
+	GetMaxPosition(ctx context.Context, userID int64) (int64, error)
+	GetAdjacent(ctx context.Context, userID int64, position int64, excludeID int64, before bool) (*models.Task, error)
+	UpdatePosition(ctx context.Context, id int64, position int64, updatedAt time.Time) error
+	RebalancePositions(ctx context.Context, userID int64) error
+	Export(ctx context.Context, userID int64, fn func(task *models.Task) error) error
+	Atomic(ctx context.Context, fn func(ctx context.Context) error) error
 }
@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"time"
+
+	"github.com/dheerajgopi/todo-api/history"
+	"github.com/dheerajgopi/todo-api/models"
+)
+
+// lockedState is the stored state of a task which is tracked in its history, read while locking the task row
+type lockedState struct {
+	title      string
+	isComplete bool
+}
+
+// lockState reads the tracked state of a task in a transaction, and locks the task until the transaction ends.
+// Nil is returned if the task is missing or in trash.
+func lockState(ctx context.Context, tx transaction, id int64) (*lockedState, error) {
+	state := &lockedState{}
+	err := tx.QueryRowContext(ctx, `SELECT title, is_complete FROM task WHERE id=? AND deleted_at IS NULL FOR UPDATE`, id).Scan(&state.title, &state.isComplete)
+
+	switch err {
+	case nil:
+	case sql.ErrNoRows:
+		return nil, nil
+	default:
+		return nil, err
+	}
+
+	return state, nil
+}
+
+// changeEvents builds the events for the changes in the tracked state of a task
+func changeEvents(state *lockedState, title string, isComplete bool) []*models.TaskEvent {
+	events := make([]*models.TaskEvent, 0)
+
+	if state == nil {
+		return events
+	}
+
+	if state.title != title {
+		events = append(events, newEvent(history.EventTitleChanged, &state.title, &title))
+	}
+
+	if state.isComplete != isComplete {
+		events = append(events, completionEvent(state.isComplete, isComplete))
+	}
+
+	return events
+}
+
+// completionEvent builds the event for completing or reopening a task
+func completionEvent(wasComplete bool, isComplete bool) *models.TaskEvent {
+	oldValue := strconv.FormatBool(wasComplete)
+	newValue := strconv.FormatBool(isComplete)
+
+	if isComplete {
+		return newEvent(history.EventCompleted, &oldValue, &newValue)
+	}
+
+	return newEvent(history.EventReopened, &oldValue, &newValue)
+}
+
+// newEvent builds a task event with its old and new values
+func newEvent(eventType string, oldValue *string, newValue *string) *models.TaskEvent {
+	return &models.TaskEvent{
+		Type:     eventType,
+		OldValue: oldValue,
+		NewValue: newValue,
+	}
+}
+
+// recordEvents appends the events of a task change, in the transaction of the change.
+// Actor of the events is read from the context, and left empty if the context does not carry one.
+func recordEvents(ctx context.Context, tx transaction, taskID int64, createdAt time.Time, events []*models.TaskEvent) error {
+	query := `INSERT INTO task_event (task_id, type, old_value, new_value, actor_id, request_id, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`
+
+	actorID := sql.NullInt64{}
+	requestID := sql.NullString{}
+
+	if actor := history.ActorFrom(ctx); actor != nil {
+		actorID = sql.NullInt64{Int64: actor.UserID, Valid: true}
+		requestID = nullString(actor.RequestID)
+	}
+
+	for _, event := range events {
+		_, err := tx.ExecContext(ctx, query, taskID, event.Type, event.OldValue, event.NewValue, actorID, requestID, createdAt)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DeleteAll moves the tasks matching the condition to trash in the transaction of the caller, increments their revision,
+// and records the deletion in their history. It is used by the repositories which move tasks to trash along with their own entries.
+// Tasks which are already in trash are left untouched.
+func DeleteAll(ctx context.Context, tx *sql.Tx, deletedAt time.Time, condition string, args ...interface{}) error {
+	rows, err := tx.QueryContext(ctx, `SELECT id, title FROM task WHERE `+condition+` AND deleted_at IS NULL FOR UPDATE`, args...)
+
+	if err != nil {
+		return err
+	}
+
+	tasks := make([]*models.Task, 0)
+
+	for rows.Next() {
+		task := &models.Task{}
+
+		if err = rows.Scan(&task.ID, &task.Title); err != nil {
+			rows.Close()
+			return err
+		}
+
+		tasks = append(tasks, task)
+	}
+
+	rows.Close()
+
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	query := `UPDATE task SET deleted_at=?, revision=revision+1 WHERE ` + condition + ` AND deleted_at IS NULL`
+
+	if _, err = tx.ExecContext(ctx, query, append([]interface{}{deletedAt}, args...)...); err != nil {
+		return err
+	}
+
+	for _, task := range tasks {
+		deletedEvent := newEvent(history.EventDeleted, &task.Title, nil)
+
+		if err = recordEvents(ctx, tx, task.ID, deletedAt, []*models.TaskEvent{deletedEvent}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,195 @@
+package repository
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dheerajgopi/todo-api/task"
+)
+
+// sortColumns maps the sortable task fields to their table columns
+var sortColumns = map[string]string{
+	task.SortByID:        "id",
+	task.SortByCreatedAt: "created_at",
+	task.SortByUpdatedAt: "updated_at",
+	task.SortByTitle:     "title",
+	task.SortByDueAt:     "COALESCE(due_at, '" + noDueDate + "')",
+	task.SortByPriority:  "priority",
+	task.SortByPosition:  "position",
+}
+
+// noDueDate is used in place of a missing due date while sorting, which places
+// tasks without due date after all others
+const noDueDate = "9999-12-31 23:59:59"
+
+// visibleCondition restricts the tasks to those owned by an user or shared with the user,
+// directly, through the parent task or through the project. Owner of a project sees all tasks in it.
+const visibleCondition = "(created_by=? OR id IN (SELECT task_id FROM task_collaborator WHERE user_id=?) " +
+	"OR parent_id IN (SELECT task_id FROM task_collaborator WHERE user_id=?) " +
+	"OR project_id IN (SELECT project_id FROM project_collaborator WHERE user_id=?) " +
+	"OR project_id IN (SELECT id FROM project WHERE created_by=?))"
+
+// buildListQuery creates the SELECT query and its arguments for listing the tasks of an user.
+// Pagination is keyset based: rows are ordered by the sort column with id as tie-breaker,
+// and the cursor restricts the result to rows placed after the cursor's row.
+func buildListQuery(userID int64, filter *task.ListFilter) (string, []interface{}, error) {
+	conditions := []string{"deleted_at IS NULL", visibleCondition}
+	args := []interface{}{userID, userID, userID, userID, userID}
+
+	if filter.IsComplete != nil {
+		conditions = append(conditions, "is_complete=?")
+		args = append(args, *filter.IsComplete)
+	}
+
+	if filter.Title != "" {
+		conditions = append(conditions, "title LIKE ?")
+		args = append(args, "%"+escapeLike(filter.Title)+"%")
+	}
+
+	if filter.ProjectID != nil {
+		conditions = append(conditions, "project_id=?")
+		args = append(args, *filter.ProjectID)
+	} else if filter.Inbox {
+		conditions = append(conditions, "project_id IS NULL")
+	}
+
+	if filter.TopLevelOnly {
+		conditions = append(conditions, "parent_id IS NULL")
+	}
+
+	if filter.CreatedAfter != nil {
+		conditions = append(conditions, "created_at>=?")
+		args = append(args, *filter.CreatedAfter)
+	}
+
+	if filter.CreatedBefore != nil {
+		conditions = append(conditions, "created_at<?")
+		args = append(args, *filter.CreatedBefore)
+	}
+
+	if filter.UpdatedAfter != nil {
+		conditions = append(conditions, "updated_at>=?")
+		args = append(args, *filter.UpdatedAfter)
+	}
+
+	if filter.UpdatedBefore != nil {
+		conditions = append(conditions, "updated_at<?")
+		args = append(args, *filter.UpdatedBefore)
+	}
+
+	if filter.DueAfter != nil {
+		conditions = append(conditions, "due_at>=?")
+		args = append(args, *filter.DueAfter)
+	}
+
+	if filter.DueBefore != nil {
+		conditions = append(conditions, "due_at<?")
+		args = append(args, *filter.DueBefore)
+	}
+
+	if len(filter.TagIDs) > 0 {
+		tagCondition := "id IN (SELECT task_id FROM task_tag WHERE tag_id IN (" + placeholders(len(filter.TagIDs)) + ")"
+
+		for _, tagID := range filter.TagIDs {
+			args = append(args, tagID)
+		}
+
+		if filter.MatchAllTags {
+			tagCondition += " GROUP BY task_id HAVING COUNT(*)=?"
+			args = append(args, len(filter.TagIDs))
+		}
+
+		conditions = append(conditions, tagCondition+")")
+	}
+
+	sortColumn, ok := sortColumns[filter.Sort]
+
+	if !ok {
+		sortColumn = sortColumns[task.SortByCreatedAt]
+	}
+
+	operator, direction := ">", "ASC"
+
+	if filter.Descending {
+		operator, direction = "<", "DESC"
+	}
+
+	if filter.Cursor != nil {
+		if sortColumn == "id" {
+			conditions = append(conditions, "id"+operator+"?")
+			args = append(args, filter.Cursor.ID)
+		} else {
+			value, err := cursorValue(filter.Cursor)
+
+			if err != nil {
+				return "", nil, err
+			}
+
+			conditions = append(conditions, "("+sortColumn+operator+"? OR ("+sortColumn+"=? AND id"+operator+"?))")
+			args = append(args, value, value, filter.Cursor.ID)
+		}
+	}
+
+	query := `SELECT ` + taskColumns + ` FROM task WHERE ` + strings.Join(conditions, " AND ")
+
+	if sortColumn == "id" {
+		query += " ORDER BY id " + direction
+	} else {
+		query += " ORDER BY " + sortColumn + " " + direction + ", id " + direction
+	}
+
+	if filter.Limit > 0 {
+		query += " LIMIT " + strconv.Itoa(filter.Limit)
+	}
+
+	return query, args, nil
+}
+
+// cursorValue converts the cursor value to the type of its sort column
+func cursorValue(cursor *task.Cursor) (interface{}, error) {
+	switch cursor.Sort {
+	case task.SortByCreatedAt, task.SortByUpdatedAt:
+		value, err := time.Parse(time.RFC3339Nano, cursor.Value)
+
+		if err != nil {
+			return nil, task.ErrInvalidCursor
+		}
+
+		return value, nil
+	case task.SortByDueAt:
+		if cursor.Value == "" {
+			return noDueDate, nil
+		}
+
+		value, err := time.Parse(time.RFC3339Nano, cursor.Value)
+
+		if err != nil {
+			return nil, task.ErrInvalidCursor
+		}
+
+		return value, nil
+	case task.SortByPriority, task.SortByPosition:
+		value, err := strconv.ParseInt(cursor.Value, 10, 64)
+
+		if err != nil {
+			return nil, task.ErrInvalidCursor
+		}
+
+		return value, nil
+	default:
+		return cursor.Value, nil
+	}
+}
+
+// escapeLike escapes the wildcard characters of a LIKE pattern
+func escapeLike(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+	return replacer.Replace(value)
+}
+
+// placeholders returns a comma separated list of n query placeholders
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
@@ -3,12 +3,22 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"strings"
+	"time"
 
+	"github.com/dheerajgopi/todo-api/history"
 	"github.com/dheerajgopi/todo-api/models"
 
 	"github.com/dheerajgopi/todo-api/task"
 )
 
+// taskColumns is the list of columns read by every task SELECT query, in the order of scanTask
+const taskColumns = `id, title, description, created_by, project_id, parent_id, is_complete, auto_complete, priority, position,
+	due_at, due_timezone, remind_at, recurrence, recurrence_start, created_at, updated_at`
+
+// exportBatchSize is the number of tasks read by each query of Export
+const exportBatchSize = 100
+
 type mySQLRepo struct {
 	DB *sql.DB
 }
@@ -20,26 +30,42 @@ func New(db *sql.DB) task.Repository {
 	}
 }
 
-func (repo *mySQLRepo) getOne(ctx context.Context, query string, args ...interface{}) (*models.Task, error) {
-	stmt, err := repo.DB.PrepareContext(ctx, query)
-
-	if err != nil {
-		return nil, err
-	}
+// rowScanner is implemented by both sql.Row and sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
 
-	row := stmt.QueryRowContext(ctx, args...)
+// scanTask reads a task from a row selected using taskColumns.
+// Extra destinations are scanned from the columns selected after taskColumns.
+func scanTask(row rowScanner, extra ...interface{}) (*models.Task, error) {
 	task := &models.Task{}
 	userID := int64(0)
+	projectID := sql.NullInt64{}
+	parentID := sql.NullInt64{}
+	dueTimezone := sql.NullString{}
+	recurrence := sql.NullString{}
 
-	err = row.Scan(
+	dest := []interface{}{
 		&task.ID,
 		&task.Title,
 		&task.Description,
 		&userID,
+		&projectID,
+		&parentID,
 		&task.IsComplete,
+		&task.AutoComplete,
+		&task.Priority,
+		&task.Position,
+		&task.DueAt,
+		&dueTimezone,
+		&task.RemindAt,
+		&recurrence,
+		&task.RecurrenceStart,
 		&task.CreatedAt,
 		&task.UpdatedAt,
-	)
+	}
+
+	err := row.Scan(append(dest, extra...)...)
 
 	if err != nil {
 		return nil, err
@@ -48,23 +74,131 @@ func (repo *mySQLRepo) getOne(ctx context.Context, query string, args ...interfa
 	task.CreatedBy = &models.User{
 		ID: userID,
 	}
+	task.DueTimezone = dueTimezone.String
+	task.Recurrence = recurrence.String
+
+	if projectID.Valid {
+		task.Project = &models.Project{
+			ID: projectID.Int64,
+		}
+	}
+
+	if parentID.Valid {
+		task.Parent = &models.Task{
+			ID: parentID.Int64,
+		}
+	}
+
+	return task, nil
+}
+
+func (repo *mySQLRepo) getOne(ctx context.Context, query string, args ...interface{}) (*models.Task, error) {
+	stmt, err := repo.conn(ctx).PrepareContext(ctx, query)
+
+	if err != nil {
+		return nil, err
+	}
+
+	task, err := scanTask(stmt.QueryRowContext(ctx, args...))
+
+	switch err {
+	case nil:
+	case sql.ErrNoRows:
+		return nil, nil
+	default:
+		return nil, err
+	}
 
 	return task, nil
 }
 
-// GetByID will return task with the given id
+func (repo *mySQLRepo) getAll(ctx context.Context, query string, args ...interface{}) ([]*models.Task, error) {
+	stmt, err := repo.conn(ctx).PrepareContext(ctx, query)
+
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := stmt.QueryContext(ctx, args...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	tasks := make([]*models.Task, 0)
+
+	for rows.Next() {
+		task, err := scanTask(rows)
+
+		if err != nil {
+			return nil, err
+		}
+
+		tasks = append(tasks, task)
+	}
+
+	err = rows.Err()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return tasks, nil
+}
+
+// GetByID will return task with the given id, along with its tags, subtask progress and comment count
 func (repo *mySQLRepo) GetByID(ctx context.Context, id int64) (*models.Task, error) {
-	query := `SELECT id, title, description, created_by, is_complete, created_at, updated_at
-		FROM task WHERE id=?`
-	return repo.getOne(ctx, query, id)
+	query := `SELECT ` + taskColumns + ` FROM task WHERE id=? AND deleted_at IS NULL`
+	task, err := repo.getOne(ctx, query, id)
+
+	if err != nil || task == nil {
+		return task, err
+	}
+
+	if err = repo.loadTags(ctx, []*models.Task{task}); err != nil {
+		return nil, err
+	}
+
+	if err = repo.loadProgress(ctx, []*models.Task{task}); err != nil {
+		return nil, err
+	}
+
+	if err = repo.loadCommentCounts(ctx, []*models.Task{task}); err != nil {
+		return nil, err
+	}
+
+	return task, nil
+}
+
+// GetSubtasks returns the subtasks of a task along with their tags and comment counts, in manual order
+func (repo *mySQLRepo) GetSubtasks(ctx context.Context, parentID int64) ([]*models.Task, error) {
+	query := `SELECT ` + taskColumns + ` FROM task WHERE parent_id=? AND deleted_at IS NULL ORDER BY position ASC, id ASC`
+	subtasks, err := repo.getAll(ctx, query, parentID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err = repo.loadTags(ctx, subtasks); err != nil {
+		return nil, err
+	}
+
+	if err = repo.loadCommentCounts(ctx, subtasks); err != nil {
+		return nil, err
+	}
+
+	return subtasks, nil
 }
 
 // Create will store new task entry
 func (repo *mySQLRepo) Create(ctx context.Context, task *models.Task) error {
-	query := `INSERT INTO task (title, description, created_by, is_complete, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?)`
+	query := `INSERT INTO task (title, description, created_by, project_id, parent_id, is_complete, auto_complete, priority, position,
+		due_at, due_timezone, remind_at, recurrence, recurrence_start, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
 
-	tx, err := repo.DB.BeginTx(ctx, nil)
+	tx, err := repo.begin(ctx)
 
 	if err != nil {
 		return err
@@ -75,7 +209,17 @@ func (repo *mySQLRepo) Create(ctx context.Context, task *models.Task) error {
 		task.Title,
 		task.Description,
 		task.CreatedBy.ID,
+		nullProjectID(task),
+		nullParentID(task),
 		task.IsComplete,
+		task.AutoComplete,
+		task.Priority,
+		task.Position,
+		task.DueAt,
+		nullString(task.DueTimezone),
+		task.RemindAt,
+		nullString(task.Recurrence),
+		task.RecurrenceStart,
 		task.CreatedAt,
 		task.UpdatedAt,
 	)
@@ -92,6 +236,18 @@ func (repo *mySQLRepo) Create(ctx context.Context, task *models.Task) error {
 		return err
 	}
 
+	if err = insertTags(ctx, tx, lastID, task.Tags); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	createdEvent := newEvent(history.EventCreated, nil, &task.Title)
+
+	if err = recordEvents(ctx, tx, lastID, task.CreatedAt, []*models.TaskEvent{createdEvent}); err != nil {
+		tx.Rollback()
+		return err
+	}
+
 	err = tx.Commit()
 
 	if err != nil {
@@ -103,56 +259,784 @@ func (repo *mySQLRepo) Create(ctx context.Context, task *models.Task) error {
 	return nil
 }
 
-// GetAllByUserID returns list of tasks created by an user
-func (repo *mySQLRepo) GetAllByUserID(ctx context.Context, userID int64) ([]*models.Task, error) {
-	query := `SELECT id, title, description, created_by, is_complete, created_at, updated_at
-		FROM task WHERE created_by=?`
+// Update will overwrite the editable fields and the tags of an existing task entry.
+// Subtasks are moved along with the task, when its project is changed.
+// Changes of the title and the completion are recorded in the history of the task. Revision of the task is incremented.
+func (repo *mySQLRepo) Update(ctx context.Context, task *models.Task) error {
+	query := `UPDATE task SET title=?, description=?, project_id=?, is_complete=?, auto_complete=?, priority=?, due_at=?,
+		due_timezone=?, remind_at=?, recurrence=?, recurrence_start=?, revision=revision+1, updated_at=? WHERE id=?`
+
+	tx, err := repo.begin(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	state, err := lockState(ctx, tx, task.ID)
+
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	_, err = tx.Exec(
+		query,
+		task.Title,
+		task.Description,
+		nullProjectID(task),
+		task.IsComplete,
+		task.AutoComplete,
+		task.Priority,
+		task.DueAt,
+		nullString(task.DueTimezone),
+		task.RemindAt,
+		nullString(task.Recurrence),
+		task.RecurrenceStart,
+		task.UpdatedAt,
+		task.ID,
+	)
+
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `UPDATE task SET project_id=? WHERE parent_id=?`, nullProjectID(task), task.ID)
+
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM task_tag WHERE task_id=?`, task.ID)
+
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err = insertTags(ctx, tx, task.ID, task.Tags); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err = recordEvents(ctx, tx, task.ID, task.UpdatedAt, changeEvents(state, task.Title, task.IsComplete)); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Delete will move the task and its subtasks to trash, and record the deletion in its history.
+// Deleting a task which is already in trash has no effect.
+func (repo *mySQLRepo) Delete(ctx context.Context, id int64) error {
+	query := `UPDATE task SET deleted_at=? WHERE (id=? OR parent_id=?) AND deleted_at IS NULL`
+
+	tx, err := repo.begin(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	state, err := lockState(ctx, tx, id)
+
+	if err != nil || state == nil {
+		tx.Rollback()
+		return err
+	}
+
+	deletedAt := time.Now()
+	_, err = tx.Exec(query, deletedAt, id, id)
+
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	deletedEvent := newEvent(history.EventDeleted, &state.title, nil)
+
+	if err = recordEvents(ctx, tx, id, deletedAt, []*models.TaskEvent{deletedEvent}); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetTrash returns the deleted tasks of an user, most recently deleted first.
+// Subtasks are left out when their parent is also in trash, since they are restored along with the parent.
+func (repo *mySQLRepo) GetTrash(ctx context.Context, userID int64) ([]*models.Task, error) {
+	query := `SELECT ` + taskColumns + `, deleted_at FROM task WHERE created_by=? AND deleted_at IS NOT NULL
+		AND (parent_id IS NULL OR parent_id NOT IN (SELECT id FROM task WHERE deleted_at IS NOT NULL))
+		ORDER BY deleted_at DESC, id DESC`
 
-	stmt, err := repo.DB.PrepareContext(ctx, query)
+	stmt, err := repo.conn(ctx).PrepareContext(ctx, query)
 
 	if err != nil {
 		return nil, err
 	}
 
 	rows, err := stmt.QueryContext(ctx, userID)
-	defer rows.Close()
 
 	if err != nil {
 		return nil, err
 	}
 
+	defer rows.Close()
+
 	tasks := make([]*models.Task, 0)
 
 	for rows.Next() {
-		task := &models.Task{}
-		userID := int64(0)
-
-		err = rows.Scan(
-			&task.ID,
-			&task.Title,
-			&task.Description,
-			&userID,
-			&task.IsComplete,
-			&task.CreatedAt,
-			&task.UpdatedAt,
-		)
+		var deletedAt *time.Time
+		task, err := scanTask(rows, &deletedAt)
 
 		if err != nil {
 			return nil, err
 		}
 
-		task.CreatedBy = &models.User{
-			ID: userID,
-		}
-
+		task.DeletedAt = deletedAt
 		tasks = append(tasks, task)
 	}
 
-	err = rows.Err()
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return tasks, nil
+}
+
+// GetDeletedByID returns the task with the given id if it is in trash, or nil otherwise
+func (repo *mySQLRepo) GetDeletedByID(ctx context.Context, id int64) (*models.Task, error) {
+	query := `SELECT ` + taskColumns + `, deleted_at FROM task WHERE id=? AND deleted_at IS NOT NULL`
+
+	stmt, err := repo.conn(ctx).PrepareContext(ctx, query)
 
 	if err != nil {
 		return nil, err
 	}
 
-	return tasks, nil
+	var deletedAt *time.Time
+	task, err := scanTask(stmt.QueryRowContext(ctx, id), &deletedAt)
+
+	switch err {
+	case nil:
+	case sql.ErrNoRows:
+		return nil, nil
+	default:
+		return nil, err
+	}
+
+	task.DeletedAt = deletedAt
+
+	return task, nil
+}
+
+// Restore will bring a task back from trash, along with the subtasks which were deleted with it,
+// and record the restoration in its history. Restoring a task which is not in trash has no effect.
+func (repo *mySQLRepo) Restore(ctx context.Context, task *models.Task) error {
+	query := `UPDATE task SET deleted_at=NULL WHERE (id=? OR parent_id=?) AND deleted_at=?`
+
+	tx, err := repo.begin(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	result, err := tx.Exec(query, task.ID, task.ID, task.DeletedAt)
+
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if restored, err := result.RowsAffected(); err != nil || restored == 0 {
+		tx.Rollback()
+		return err
+	}
+
+	restoredEvent := newEvent(history.EventRestored, nil, &task.Title)
+
+	if err = recordEvents(ctx, tx, task.ID, time.Now(), []*models.TaskEvent{restoredEvent}); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetPurgeable returns the ids of the tasks which were moved to trash before the given time, up to the limit
+func (repo *mySQLRepo) GetPurgeable(ctx context.Context, before time.Time, limit int) ([]int64, error) {
+	query := `SELECT id FROM task WHERE deleted_at<? ORDER BY id ASC LIMIT ?`
+
+	stmt, err := repo.conn(ctx).PrepareContext(ctx, query)
+
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := stmt.QueryContext(ctx, before, limit)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	ids := make([]int64, 0)
+
+	for rows.Next() {
+		id := int64(0)
+
+		if err = rows.Scan(&id); err != nil {
+			return nil, err
+		}
+
+		ids = append(ids, id)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// Purge will permanently remove the tasks with the given ids, along with their subtasks and history
+func (repo *mySQLRepo) Purge(ctx context.Context, ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	args := make([]interface{}, 0, len(ids))
+
+	for _, id := range ids {
+		args = append(args, id)
+	}
+
+	tx, err := repo.begin(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM task_event WHERE task_id IN (SELECT id FROM task
+		WHERE id IN (`+placeholders(len(ids))+`) OR parent_id IN (`+placeholders(len(ids))+`))`, append(args, args...)...)
+
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM task WHERE id IN (`+placeholders(len(ids))+`)`, args...)
+
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetMaxPosition returns the highest position among the tasks of an user, or zero if there are no tasks
+func (repo *mySQLRepo) GetMaxPosition(ctx context.Context, userID int64) (int64, error) {
+	query := `SELECT COALESCE(MAX(position), 0) FROM task WHERE created_by=?`
+
+	stmt, err := repo.conn(ctx).PrepareContext(ctx, query)
+
+	if err != nil {
+		return 0, err
+	}
+
+	position := int64(0)
+	err = stmt.QueryRowContext(ctx, userID).Scan(&position)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return position, nil
+}
+
+// GetAdjacent returns the task of an user placed right before (or after) the given position.
+// The task with excludeID is skipped, so that a task being moved is not picked as its own neighbour.
+func (repo *mySQLRepo) GetAdjacent(ctx context.Context, userID int64, position int64, excludeID int64, before bool) (*models.Task, error) {
+	query := `SELECT ` + taskColumns + ` FROM task WHERE created_by=? AND id<>? AND position>? AND deleted_at IS NULL ORDER BY position ASC, id ASC LIMIT 1`
+
+	if before {
+		query = `SELECT ` + taskColumns + ` FROM task WHERE created_by=? AND id<>? AND position<? AND deleted_at IS NULL ORDER BY position DESC, id DESC LIMIT 1`
+	}
+
+	return repo.getOne(ctx, query, userID, excludeID, position)
+}
+
+// UpdateCompletion will store whether a task is complete, increment its revision, and record the change in its history
+func (repo *mySQLRepo) UpdateCompletion(ctx context.Context, id int64, isComplete bool, updatedAt time.Time) error {
+	query := `UPDATE task SET is_complete=?, revision=revision+1, updated_at=? WHERE id=?`
+
+	tx, err := repo.begin(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	state, err := lockState(ctx, tx, id)
+
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	_, err = tx.Exec(query, isComplete, updatedAt, id)
+
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if state != nil {
+		if err = recordEvents(ctx, tx, id, updatedAt, changeEvents(state, state.title, isComplete)); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// UpdatePosition will store the new position of a task
+func (repo *mySQLRepo) UpdatePosition(ctx context.Context, id int64, position int64, updatedAt time.Time) error {
+	query := `UPDATE task SET position=?, updated_at=? WHERE id=?`
+
+	tx, err := repo.begin(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(query, position, updatedAt, id)
+
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// RebalancePositions spreads the positions of all tasks of an user evenly, keeping their order
+func (repo *mySQLRepo) RebalancePositions(ctx context.Context, userID int64) error {
+	tx, err := repo.begin(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	rows, err := tx.QueryContext(ctx, `SELECT id FROM task WHERE created_by=? ORDER BY position ASC, id ASC FOR UPDATE`, userID)
+
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	ids := make([]int64, 0)
+
+	for rows.Next() {
+		id := int64(0)
+
+		if err = rows.Scan(&id); err != nil {
+			rows.Close()
+			tx.Rollback()
+			return err
+		}
+
+		ids = append(ids, id)
+	}
+
+	rows.Close()
+
+	if err = rows.Err(); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	stmt, err := tx.PrepareContext(ctx, `UPDATE task SET position=? WHERE id=?`)
+
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	defer stmt.Close()
+
+	for i, id := range ids {
+		if _, err = stmt.ExecContext(ctx, int64(i+1)*task.PositionGap, id); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetAllByUserID returns list of tasks created by an user or shared with the user, along with their tags, subtask progress
+// and comment counts, after applying the filters, sort order and cursor of the given filter.
+// Subtasks are attached to the listed tasks if the filter asks for them.
+func (repo *mySQLRepo) GetAllByUserID(ctx context.Context, userID int64, filter *task.ListFilter) ([]*models.Task, error) {
+	query, args, err := buildListQuery(userID, filter)
+
+	if err != nil {
+		return nil, err
+	}
+
+	tasks, err := repo.getAll(ctx, query, args...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err = repo.loadTags(ctx, tasks); err != nil {
+		return nil, err
+	}
+
+	if err = repo.loadProgress(ctx, tasks); err != nil {
+		return nil, err
+	}
+
+	if err = repo.loadCommentCounts(ctx, tasks); err != nil {
+		return nil, err
+	}
+
+	if filter.WithSubtasks {
+		if err = repo.loadSubtasks(ctx, tasks); err != nil {
+			return nil, err
+		}
+	}
+
+	return tasks, nil
+}
+
+// Export passes every task created by an user to fn in the order of creation, along with its tags and the name of its project.
+// Tasks are read in batches, so that all tasks of the user are never held in memory at once.
+func (repo *mySQLRepo) Export(ctx context.Context, userID int64, fn func(task *models.Task) error) error {
+	query := `SELECT ` + taskColumns + ` FROM task WHERE created_by=? AND id>? AND deleted_at IS NULL ORDER BY id ASC LIMIT ?`
+	lastID := int64(0)
+
+	for {
+		tasks, err := repo.getAll(ctx, query, userID, lastID, exportBatchSize)
+
+		if err != nil {
+			return err
+		}
+
+		if err = repo.loadTags(ctx, tasks); err != nil {
+			return err
+		}
+
+		if err = repo.loadProjectNames(ctx, tasks); err != nil {
+			return err
+		}
+
+		for _, task := range tasks {
+			if err = fn(task); err != nil {
+				return err
+			}
+		}
+
+		if len(tasks) < exportBatchSize {
+			return nil
+		}
+
+		lastID = tasks[len(tasks)-1].ID
+	}
+}
+
+// loadProgress counts the completed and total subtasks of all given tasks in a single query
+func (repo *mySQLRepo) loadProgress(ctx context.Context, tasks []*models.Task) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	tasksByID := make(map[int64]*models.Task)
+	args := make([]interface{}, 0, len(tasks))
+
+	for _, task := range tasks {
+		task.Progress = &models.Progress{}
+		tasksByID[task.ID] = task
+		args = append(args, task.ID)
+	}
+
+	query := `SELECT parent_id, COUNT(CASE WHEN is_complete=1 THEN 1 END), COUNT(*) FROM task
+		WHERE parent_id IN (` + placeholders(len(tasks)) + `) AND deleted_at IS NULL GROUP BY parent_id`
+
+	stmt, err := repo.conn(ctx).PrepareContext(ctx, query)
+
+	if err != nil {
+		return err
+	}
+
+	rows, err := stmt.QueryContext(ctx, args...)
+
+	if err != nil {
+		return err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		parentID := int64(0)
+		progress := &models.Progress{}
+
+		if err = rows.Scan(&parentID, &progress.Completed, &progress.Total); err != nil {
+			return err
+		}
+
+		if task, ok := tasksByID[parentID]; ok {
+			task.Progress = progress
+		}
+	}
+
+	return rows.Err()
+}
+
+// loadCommentCounts counts the comments of all given tasks in a single query
+func (repo *mySQLRepo) loadCommentCounts(ctx context.Context, tasks []*models.Task) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	tasksByID := make(map[int64]*models.Task)
+	args := make([]interface{}, 0, len(tasks))
+
+	for _, task := range tasks {
+		tasksByID[task.ID] = task
+		args = append(args, task.ID)
+	}
+
+	query := `SELECT task_id, COUNT(*) FROM comment WHERE task_id IN (` + placeholders(len(tasks)) + `) GROUP BY task_id`
+
+	stmt, err := repo.conn(ctx).PrepareContext(ctx, query)
+
+	if err != nil {
+		return err
+	}
+
+	rows, err := stmt.QueryContext(ctx, args...)
+
+	if err != nil {
+		return err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		taskID := int64(0)
+		count := int64(0)
+
+		if err = rows.Scan(&taskID, &count); err != nil {
+			return err
+		}
+
+		if task, ok := tasksByID[taskID]; ok {
+			task.CommentCount = count
+		}
+	}
+
+	return rows.Err()
+}
+
+// loadSubtasks attaches the subtasks of all given tasks in manual order, using a single query
+func (repo *mySQLRepo) loadSubtasks(ctx context.Context, tasks []*models.Task) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	tasksByID := make(map[int64]*models.Task)
+	args := make([]interface{}, 0, len(tasks))
+
+	for _, task := range tasks {
+		task.Subtasks = make([]*models.Task, 0)
+		tasksByID[task.ID] = task
+		args = append(args, task.ID)
+	}
+
+	query := `SELECT ` + taskColumns + ` FROM task WHERE parent_id IN (` + placeholders(len(tasks)) + `)
+		AND deleted_at IS NULL ORDER BY position ASC, id ASC`
+
+	subtasks, err := repo.getAll(ctx, query, args...)
+
+	if err != nil {
+		return err
+	}
+
+	if err = repo.loadTags(ctx, subtasks); err != nil {
+		return err
+	}
+
+	if err = repo.loadCommentCounts(ctx, subtasks); err != nil {
+		return err
+	}
+
+	for _, subtask := range subtasks {
+		if parent, ok := tasksByID[subtask.Parent.ID]; ok {
+			parent.Subtasks = append(parent.Subtasks, subtask)
+		}
+	}
+
+	return nil
+}
+
+// loadProjectNames reads the names of the projects of all given tasks in a single query
+func (repo *mySQLRepo) loadProjectNames(ctx context.Context, tasks []*models.Task) error {
+	projectsByID := make(map[int64][]*models.Project)
+	args := make([]interface{}, 0, len(tasks))
+
+	for _, task := range tasks {
+		if task.Project == nil {
+			continue
+		}
+
+		if _, ok := projectsByID[task.Project.ID]; !ok {
+			args = append(args, task.Project.ID)
+		}
+
+		projectsByID[task.Project.ID] = append(projectsByID[task.Project.ID], task.Project)
+	}
+
+	if len(args) == 0 {
+		return nil
+	}
+
+	query := `SELECT id, name FROM project WHERE id IN (` + placeholders(len(args)) + `)`
+
+	stmt, err := repo.conn(ctx).PrepareContext(ctx, query)
+
+	if err != nil {
+		return err
+	}
+
+	rows, err := stmt.QueryContext(ctx, args...)
+
+	if err != nil {
+		return err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		projectID := int64(0)
+		name := ""
+
+		if err = rows.Scan(&projectID, &name); err != nil {
+			return err
+		}
+
+		for _, project := range projectsByID[projectID] {
+			project.Name = name
+		}
+	}
+
+	return rows.Err()
+}
+
+// loadTags reads the tags of all given tasks in a single query
+func (repo *mySQLRepo) loadTags(ctx context.Context, tasks []*models.Task) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	tasksByID := make(map[int64]*models.Task)
+	args := make([]interface{}, 0, len(tasks))
+
+	for _, task := range tasks {
+		task.Tags = make([]*models.Tag, 0)
+		tasksByID[task.ID] = task
+		args = append(args, task.ID)
+	}
+
+	query := `SELECT tt.task_id, t.id, t.name, t.color FROM task_tag tt JOIN tag t ON t.id=tt.tag_id
+		WHERE tt.task_id IN (` + placeholders(len(tasks)) + `) ORDER BY t.name`
+
+	stmt, err := repo.conn(ctx).PrepareContext(ctx, query)
+
+	if err != nil {
+		return err
+	}
+
+	rows, err := stmt.QueryContext(ctx, args...)
+
+	if err != nil {
+		return err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		taskID := int64(0)
+		tag := &models.Tag{}
+		color := sql.NullString{}
+
+		if err = rows.Scan(&taskID, &tag.ID, &tag.Name, &color); err != nil {
+			return err
+		}
+
+		tag.Color = color.String
+
+		if task, ok := tasksByID[taskID]; ok {
+			task.Tags = append(task.Tags, tag)
+		}
+	}
+
+	return rows.Err()
+}
+
+// insertTags attaches the given tags to a task
+func insertTags(ctx context.Context, tx transaction, taskID int64, tags []*models.Tag) error {
+	if len(tags) == 0 {
+		return nil
+	}
+
+	args := make([]interface{}, 0, 2*len(tags))
+	values := make([]string, 0, len(tags))
+
+	for _, tag := range tags {
+		args = append(args, taskID, tag.ID)
+		values = append(values, "(?, ?)")
+	}
+
+	_, err := tx.ExecContext(ctx, `INSERT INTO task_tag (task_id, tag_id) VALUES `+strings.Join(values, ", "), args...)
+
+	return err
+}
+
+// nullProjectID returns the project of a task, or NULL for tasks in the inbox
+func nullProjectID(task *models.Task) sql.NullInt64 {
+	if task.Project == nil {
+		return sql.NullInt64{}
+	}
+
+	return sql.NullInt64{
+		Int64: task.Project.ID,
+		Valid: true,
+	}
+}
+
+// nullParentID returns the parent of a subtask, or NULL for top level tasks
+func nullParentID(task *models.Task) sql.NullInt64 {
+	if task.Parent == nil {
+		return sql.NullInt64{}
+	}
+
+	return sql.NullInt64{
+		Int64: task.Parent.ID,
+		Valid: true,
+	}
+}
+
+// nullString stores empty strings as NULL
+func nullString(value string) sql.NullString {
+	return sql.NullString{
+		String: value,
+		Valid:  value != "",
+	}
 }
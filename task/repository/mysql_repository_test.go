@@ -2,17 +2,52 @@ package repository_test
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
+	"github.com/dheerajgopi/todo-api/history"
 	"github.com/dheerajgopi/todo-api/models"
 
 	"github.com/stretchr/testify/assert"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/dheerajgopi/todo-api/task"
 	"github.com/dheerajgopi/todo-api/task/repository"
 )
 
+const lockStateQuery = "SELECT title, is_complete FROM task WHERE id=\\? AND deleted_at IS NULL FOR UPDATE"
+
+const insertEventQuery = "INSERT INTO task_event \\(task_id, type, old_value, new_value, actor_id, request_id, created_at\\)\\s+" +
+	"VALUES \\(\\?, \\?, \\?, \\?, \\?, \\?, \\?\\)"
+
+var taskColumns = []string{
+	"id", "title", "description", "created_by", "project_id", "parent_id", "is_complete", "auto_complete", "priority", "position", "due_at",
+	"due_timezone", "remind_at", "recurrence", "recurrence_start", "created_at", "updated_at",
+}
+
+const selectTaskQuery = "SELECT id, title, description, created_by, project_id, parent_id, is_complete, auto_complete, priority, " +
+	"position, due_at, due_timezone, remind_at, recurrence, recurrence_start, created_at, updated_at FROM task "
+
+const visibleCondition = "\\(created_by=\\? OR id IN \\(SELECT task_id FROM task_collaborator WHERE user_id=\\?\\) " +
+	"OR parent_id IN \\(SELECT task_id FROM task_collaborator WHERE user_id=\\?\\) " +
+	"OR project_id IN \\(SELECT project_id FROM project_collaborator WHERE user_id=\\?\\) " +
+	"OR project_id IN \\(SELECT id FROM project WHERE created_by=\\?\\)\\) "
+
+var progressColumns = []string{"parent_id", "completed", "total"}
+
+const selectProgressQuery = "SELECT parent_id, COUNT\\(CASE WHEN is_complete=1 THEN 1 END\\), COUNT\\(\\*\\) FROM task\\s+" +
+	"WHERE parent_id IN "
+
+var commentCountColumns = []string{"task_id", "count"}
+
+const selectCommentCountQuery = "SELECT task_id, COUNT\\(\\*\\) FROM comment WHERE task_id IN "
+
+var taskTagColumns = []string{"task_id", "id", "name", "color"}
+
+const selectTaskTagQuery = "SELECT tt.task_id, t.id, t.name, t.color FROM task_tag tt JOIN tag t ON t.id=tt.tag_id\\s+" +
+	"WHERE tt.task_id IN "
+
 func TestGetByID(t *testing.T) {
 	db, mock, err := sqlmock.New()
 
@@ -23,14 +58,24 @@ func TestGetByID(t *testing.T) {
 	defer db.Close()
 
 	rows := sqlmock.
-		NewRows([]string{"id", "title", "description", "created_by", "is_complete", "created_at", "updated_at"}).
-		AddRow(1, "title", "description", 1, false, time.Now(), time.Now())
+		NewRows(taskColumns).
+		AddRow(1, "title", "description", 1, nil, nil, false, false, 0, 65536, nil, nil, nil, nil, nil, time.Now(), time.Now())
 
 	taskID := int64(1)
-	query := "SELECT id, title, description, created_by, is_complete, created_at, updated_at FROM task WHERE id=\\?"
+	query := selectTaskQuery + "WHERE id=\\? AND deleted_at IS NULL"
+
+	tagRows := sqlmock.
+		NewRows(taskTagColumns).
+		AddRow(1, 3, "work", "#ff0000")
 
 	prep := mock.ExpectPrepare(query)
 	prep.ExpectQuery().WithArgs(taskID).WillReturnRows(rows)
+	tagPrep := mock.ExpectPrepare(selectTaskTagQuery + "\\(\\?\\) ORDER BY t.name")
+	tagPrep.ExpectQuery().WithArgs(taskID).WillReturnRows(tagRows)
+	progressPrep := mock.ExpectPrepare(selectProgressQuery + "\\(\\?\\) AND deleted_at IS NULL GROUP BY parent_id")
+	progressPrep.ExpectQuery().WithArgs(taskID).WillReturnRows(sqlmock.NewRows(progressColumns).AddRow(taskID, 1, 3))
+	commentPrep := mock.ExpectPrepare(selectCommentCountQuery + "\\(\\?\\) GROUP BY task_id")
+	commentPrep.ExpectQuery().WithArgs(taskID).WillReturnRows(sqlmock.NewRows(commentCountColumns).AddRow(taskID, 4))
 
 	repo := repository.New(db)
 
@@ -38,19 +83,34 @@ func TestGetByID(t *testing.T) {
 
 	assert.NoError(t, err)
 	assert.NotNil(t, task)
+	assert.Equal(t, 1, len(task.Tags))
+	assert.Equal(t, "work", task.Tags[0].Name)
+	assert.Equal(t, &models.Progress{Completed: 1, Total: 3}, task.Progress)
+	assert.Equal(t, int64(4), task.CommentCount)
+	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
 func TestCreate(t *testing.T) {
 	now := time.Now()
+	dueAt := now.Add(24 * time.Hour)
 	task := &models.Task{
 		Title:       "title",
 		Description: "description",
 		CreatedBy: &models.User{
 			ID: int64(1),
 		},
-		IsComplete: false,
-		CreatedAt:  now,
-		UpdatedAt:  now,
+		Project: &models.Project{
+			ID: int64(5),
+		},
+		IsComplete:      false,
+		Priority:        task.PriorityHigh,
+		Position:        task.PositionGap,
+		DueAt:           &dueAt,
+		DueTimezone:     "Asia/Kolkata",
+		Recurrence:      "FREQ=DAILY",
+		RecurrenceStart: &dueAt,
+		CreatedAt:       now,
+		UpdatedAt:       now,
 	}
 
 	db, mock, err := sqlmock.New()
@@ -61,25 +121,42 @@ func TestCreate(t *testing.T) {
 
 	defer db.Close()
 
-	query := "INSERT INTO task \\(title, description, created_by, is_complete, created_at, updated_at\\) VALUES \\(\\?, \\?, \\?, \\?, \\?, \\?\\)"
+	query := "INSERT INTO task \\(title, description, created_by, project_id, parent_id, is_complete, auto_complete, priority, position, " +
+		"due_at, due_timezone, remind_at, recurrence, recurrence_start, created_at, updated_at\\)\\s+" +
+		"VALUES \\(\\?, \\?, \\?, \\?, \\?, \\?, \\?, \\?, \\?, \\?, \\?, \\?, \\?, \\?, \\?, \\?\\)"
 
 	mock.ExpectBegin()
 	mock.ExpectExec(query).WithArgs(
 		task.Title,
 		task.Description,
 		task.CreatedBy.ID,
+		task.Project.ID,
+		nil,
 		task.IsComplete,
+		task.AutoComplete,
+		task.Priority,
+		task.Position,
+		task.DueAt,
+		"Asia/Kolkata",
+		task.RemindAt,
+		"FREQ=DAILY",
+		task.RecurrenceStart,
 		task.CreatedAt,
 		task.UpdatedAt,
 	).WillReturnResult(sqlmock.NewResult(2, 1))
+	mock.ExpectExec(insertEventQuery).
+		WithArgs(int64(2), "created", nil, "title", int64(1), "request-1", task.CreatedAt).
+		WillReturnResult(sqlmock.NewResult(1, 1))
 	mock.ExpectCommit()
 
 	repo := repository.New(db)
+	ctx := history.WithActor(context.TODO(), &history.Actor{UserID: 1, RequestID: "request-1"})
 
-	err = repo.Create(context.TODO(), task)
+	err = repo.Create(ctx, task)
 
 	assert.NoError(t, err)
 	assert.Equal(t, int64(2), task.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
 func TestGetAllByUserID(t *testing.T) {
@@ -93,20 +170,897 @@ func TestGetAllByUserID(t *testing.T) {
 	defer db.Close()
 
 	rows := sqlmock.
-		NewRows([]string{"id", "title", "description", "created_by", "is_complete", "created_at", "updated_at"}).
-		AddRow(1, "title", "description", 1, false, time.Now(), time.Now())
+		NewRows(taskColumns).
+		AddRow(1, "title", "description", 1, nil, nil, false, false, 0, 65536, nil, nil, nil, nil, nil, time.Now(), time.Now())
 
 	userID := int64(1)
-	query := "SELECT id, title, description, created_by, is_complete, created_at, updated_at FROM task WHERE created_by=\\?"
+	query := selectTaskQuery + "WHERE deleted_at IS NULL AND " + visibleCondition +
+		"ORDER BY created_at ASC, id ASC LIMIT 51"
 
 	prep := mock.ExpectPrepare(query)
-	prep.ExpectQuery().WithArgs(userID).WillReturnRows(rows)
+	prep.ExpectQuery().WithArgs(userID, userID, userID, userID, userID).WillReturnRows(rows)
+	tagPrep := mock.ExpectPrepare(selectTaskTagQuery + "\\(\\?\\) ORDER BY t.name")
+	tagPrep.ExpectQuery().WithArgs(int64(1)).WillReturnRows(sqlmock.NewRows(taskTagColumns))
+	progressPrep := mock.ExpectPrepare(selectProgressQuery + "\\(\\?\\) AND deleted_at IS NULL GROUP BY parent_id")
+	progressPrep.ExpectQuery().WithArgs(int64(1)).WillReturnRows(sqlmock.NewRows(progressColumns))
+	commentPrep := mock.ExpectPrepare(selectCommentCountQuery + "\\(\\?\\) GROUP BY task_id")
+	commentPrep.ExpectQuery().WithArgs(int64(1)).WillReturnRows(sqlmock.NewRows(commentCountColumns))
 
 	repo := repository.New(db)
 
-	tasks, err := repo.GetAllByUserID(context.TODO(), userID)
+	tasks, err := repo.GetAllByUserID(context.TODO(), userID, &task.ListFilter{
+		Sort:  task.SortByCreatedAt,
+		Limit: 51,
+	})
 
 	assert.NoError(err)
 	assert.NotNil(tasks)
 	assert.Equal(1, len(tasks))
 }
+
+func TestExport(t *testing.T) {
+	assert := assert.New(t)
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	rows := sqlmock.
+		NewRows(taskColumns).
+		AddRow(1, "title", "description", 1, 3, nil, false, false, 0, 65536, nil, nil, nil, nil, nil, time.Now(), time.Now()).
+		AddRow(2, "subtask", "", 1, 3, 1, true, false, 0, 65536, nil, nil, nil, nil, nil, time.Now(), time.Now())
+
+	userID := int64(1)
+	query := selectTaskQuery + "WHERE created_by=\\? AND id>\\? AND deleted_at IS NULL ORDER BY id ASC LIMIT \\?"
+
+	prep := mock.ExpectPrepare(query)
+	prep.ExpectQuery().WithArgs(userID, int64(0), 100).WillReturnRows(rows)
+	tagPrep := mock.ExpectPrepare(selectTaskTagQuery + "\\(\\?, \\?\\) ORDER BY t.name")
+	tagPrep.ExpectQuery().WithArgs(int64(1), int64(2)).WillReturnRows(sqlmock.NewRows(taskTagColumns).AddRow(1, 4, "work", nil))
+	projectPrep := mock.ExpectPrepare("SELECT id, name FROM project WHERE id IN \\(\\?\\)")
+	projectPrep.ExpectQuery().WithArgs(int64(3)).WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(3, "home"))
+
+	repo := repository.New(db)
+	tasks := make([]*models.Task, 0)
+
+	err = repo.Export(context.TODO(), userID, func(task *models.Task) error {
+		tasks = append(tasks, task)
+		return nil
+	})
+
+	assert.NoError(err)
+	assert.Equal(2, len(tasks))
+	assert.Equal("home", tasks[0].Project.Name)
+	assert.Equal("home", tasks[1].Project.Name)
+	assert.Equal("work", tasks[0].Tags[0].Name)
+	assert.Equal(0, len(tasks[1].Tags))
+	assert.Nil(mock.ExpectationsWereMet())
+}
+
+func TestExportStopsOnError(t *testing.T) {
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	rows := sqlmock.
+		NewRows(taskColumns).
+		AddRow(1, "title", "description", 1, nil, nil, false, false, 0, 65536, nil, nil, nil, nil, nil, time.Now(), time.Now()).
+		AddRow(2, "title", "description", 1, nil, nil, false, false, 0, 65536, nil, nil, nil, nil, nil, time.Now(), time.Now())
+
+	prep := mock.ExpectPrepare(selectTaskQuery + "WHERE created_by=\\? AND id>\\?")
+	prep.ExpectQuery().WillReturnRows(rows)
+	tagPrep := mock.ExpectPrepare(selectTaskTagQuery)
+	tagPrep.ExpectQuery().WillReturnRows(sqlmock.NewRows(taskTagColumns))
+
+	repo := repository.New(db)
+	writeErr := errors.New("broken pipe")
+	calls := 0
+
+	err = repo.Export(context.TODO(), int64(1), func(task *models.Task) error {
+		calls++
+		return writeErr
+	})
+
+	assert.Equal(t, writeErr, err)
+	assert.Equal(t, 1, calls)
+}
+
+func TestGetByIDWithNoRows(t *testing.T) {
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	rows := sqlmock.
+		NewRows(taskColumns)
+
+	taskID := int64(1)
+	query := selectTaskQuery + "WHERE id=\\? AND deleted_at IS NULL"
+
+	prep := mock.ExpectPrepare(query)
+	prep.ExpectQuery().WithArgs(taskID).WillReturnRows(rows)
+
+	repo := repository.New(db)
+
+	task, err := repo.GetByID(context.TODO(), taskID)
+
+	assert.NoError(t, err)
+	assert.Nil(t, task)
+}
+
+func TestUpdate(t *testing.T) {
+	now := time.Now()
+	task := &models.Task{
+		ID:          1,
+		Title:       "title",
+		Description: "description",
+		IsComplete:  true,
+		Tags:        []*models.Tag{{ID: 3}, {ID: 4}},
+		UpdatedAt:   now,
+	}
+
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	query := "UPDATE task SET title=\\?, description=\\?, project_id=\\?, is_complete=\\?, auto_complete=\\?, priority=\\?, " +
+		"due_at=\\?, due_timezone=\\?, remind_at=\\?, recurrence=\\?, recurrence_start=\\?, revision=revision\\+1, updated_at=\\? WHERE id=\\?"
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(lockStateQuery).
+		WithArgs(task.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"title", "is_complete"}).AddRow("old title", false))
+	mock.ExpectExec(query).WithArgs(
+		task.Title,
+		task.Description,
+		nil,
+		task.IsComplete,
+		task.AutoComplete,
+		task.Priority,
+		task.DueAt,
+		nil,
+		task.RemindAt,
+		nil,
+		task.RecurrenceStart,
+		task.UpdatedAt,
+		task.ID,
+	).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE task SET project_id=\\? WHERE parent_id=\\?").WithArgs(nil, task.ID).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("DELETE FROM task_tag WHERE task_id=\\?").WithArgs(task.ID).WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec("INSERT INTO task_tag \\(task_id, tag_id\\) VALUES \\(\\?, \\?\\), \\(\\?, \\?\\)").
+		WithArgs(task.ID, int64(3), task.ID, int64(4)).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(insertEventQuery).
+		WithArgs(task.ID, "title_changed", "old title", "title", nil, nil, now).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(insertEventQuery).
+		WithArgs(task.ID, "completed", "false", "true", nil, nil, now).
+		WillReturnResult(sqlmock.NewResult(2, 1))
+	mock.ExpectCommit()
+
+	repo := repository.New(db)
+
+	err = repo.Update(context.TODO(), task)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDelete(t *testing.T) {
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	taskID := int64(1)
+	query := "UPDATE task SET deleted_at=\\? WHERE \\(id=\\? OR parent_id=\\?\\) AND deleted_at IS NULL"
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(lockStateQuery).
+		WithArgs(taskID).
+		WillReturnRows(sqlmock.NewRows([]string{"title", "is_complete"}).AddRow("title", false))
+	mock.ExpectExec(query).WithArgs(sqlmock.AnyArg(), taskID, taskID).WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec(insertEventQuery).
+		WithArgs(taskID, "deleted", "title", nil, int64(3), "request-1", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	repo := repository.New(db)
+
+	err = repo.Delete(history.WithActor(context.TODO(), &history.Actor{UserID: 3, RequestID: "request-1"}), taskID)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteInTrash(t *testing.T) {
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	taskID := int64(1)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(lockStateQuery).
+		WithArgs(taskID).
+		WillReturnRows(sqlmock.NewRows([]string{"title", "is_complete"}))
+	mock.ExpectRollback()
+
+	repo := repository.New(db)
+
+	err = repo.Delete(context.TODO(), taskID)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetTrash(t *testing.T) {
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	deletedAt := time.Now()
+	rows := sqlmock.
+		NewRows(append(taskColumns, "deleted_at")).
+		AddRow(4, "title", "description", 1, nil, nil, false, false, 0, 65536, nil, nil, nil, nil, nil, time.Now(), time.Now(), deletedAt)
+
+	query := "SELECT id, .+, updated_at, deleted_at FROM task WHERE created_by=\\? AND deleted_at IS NOT NULL\\s+" +
+		"AND \\(parent_id IS NULL OR parent_id NOT IN \\(SELECT id FROM task WHERE deleted_at IS NOT NULL\\)\\)\\s+" +
+		"ORDER BY deleted_at DESC, id DESC"
+
+	prep := mock.ExpectPrepare(query)
+	prep.ExpectQuery().WithArgs(int64(1)).WillReturnRows(rows)
+
+	repo := repository.New(db)
+
+	tasks, err := repo.GetTrash(context.TODO(), 1)
+
+	assert.NoError(t, err)
+	assert.Len(t, tasks, 1)
+	assert.Equal(t, int64(4), tasks[0].ID)
+	assert.Equal(t, deletedAt, *tasks[0].DeletedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetDeletedByIDWithNoRows(t *testing.T) {
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	prep := mock.ExpectPrepare("SELECT id, .+, deleted_at FROM task WHERE id=\\? AND deleted_at IS NOT NULL")
+	prep.ExpectQuery().WithArgs(int64(4)).WillReturnRows(sqlmock.NewRows(append(taskColumns, "deleted_at")))
+
+	repo := repository.New(db)
+
+	task, err := repo.GetDeletedByID(context.TODO(), 4)
+
+	assert.NoError(t, err)
+	assert.Nil(t, task)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRestore(t *testing.T) {
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	deletedAt := time.Now()
+	task := &models.Task{ID: 4, Title: "title", DeletedAt: &deletedAt}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE task SET deleted_at=NULL WHERE \\(id=\\? OR parent_id=\\?\\) AND deleted_at=\\?").
+		WithArgs(task.ID, task.ID, deletedAt).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(insertEventQuery).
+		WithArgs(task.ID, "restored", nil, "title", nil, nil, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	repo := repository.New(db)
+
+	err = repo.Restore(context.TODO(), task)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRestoreNotInTrash(t *testing.T) {
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	deletedAt := time.Now()
+	task := &models.Task{ID: 4, Title: "title", DeletedAt: &deletedAt}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE task SET deleted_at=NULL").
+		WithArgs(task.ID, task.ID, deletedAt).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	repo := repository.New(db)
+
+	err = repo.Restore(context.TODO(), task)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetPurgeable(t *testing.T) {
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	before := time.Now()
+
+	prep := mock.ExpectPrepare("SELECT id FROM task WHERE deleted_at<\\? ORDER BY id ASC LIMIT \\?")
+	prep.ExpectQuery().WithArgs(before, 100).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2).AddRow(5))
+
+	repo := repository.New(db)
+
+	ids, err := repo.GetPurgeable(context.TODO(), before, 100)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{2, 5}, ids)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPurge(t *testing.T) {
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM task_event WHERE task_id IN \\(SELECT id FROM task\\s+"+
+		"WHERE id IN \\(\\?, \\?\\) OR parent_id IN \\(\\?, \\?\\)\\)").
+		WithArgs(2, 5, 2, 5).
+		WillReturnResult(sqlmock.NewResult(0, 6))
+	mock.ExpectExec("DELETE FROM task WHERE id IN \\(\\?, \\?\\)").
+		WithArgs(2, 5).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	repo := repository.New(db)
+
+	err = repo.Purge(context.TODO(), []int64{2, 5})
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetAllByUserIDWithFiltersAndCursor(t *testing.T) {
+	assert := assert.New(t)
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	rows := sqlmock.
+		NewRows(taskColumns).
+		AddRow(3, "title", "description", 1, nil, nil, true, false, 2, 65536, time.Now(), "Asia/Kolkata", nil, nil, nil, time.Now(), time.Now())
+
+	userID := int64(1)
+	isComplete := true
+	updatedAfter := time.Date(2019, 5, 1, 0, 0, 0, 0, time.UTC)
+	cursorTime := time.Date(2019, 5, 10, 0, 0, 0, 0, time.UTC)
+
+	query := selectTaskQuery +
+		"WHERE deleted_at IS NULL AND " + visibleCondition + "AND is_complete=\\? AND title LIKE \\? AND updated_at>=\\? " +
+		"AND \\(updated_at<\\? OR \\(updated_at=\\? AND id<\\?\\)\\) " +
+		"ORDER BY updated_at DESC, id DESC LIMIT 11"
+
+	prep := mock.ExpectPrepare(query)
+	prep.ExpectQuery().
+		WithArgs(userID, userID, userID, userID, userID, isComplete, "%50\\%%", updatedAfter, cursorTime, cursorTime, int64(4)).
+		WillReturnRows(rows)
+	tagPrep := mock.ExpectPrepare(selectTaskTagQuery + "\\(\\?\\) ORDER BY t.name")
+	tagPrep.ExpectQuery().WithArgs(int64(3)).WillReturnRows(sqlmock.NewRows(taskTagColumns))
+	progressPrep := mock.ExpectPrepare(selectProgressQuery + "\\(\\?\\) AND deleted_at IS NULL GROUP BY parent_id")
+	progressPrep.ExpectQuery().WithArgs(int64(3)).WillReturnRows(sqlmock.NewRows(progressColumns))
+	commentPrep := mock.ExpectPrepare(selectCommentCountQuery + "\\(\\?\\) GROUP BY task_id")
+	commentPrep.ExpectQuery().WithArgs(int64(3)).WillReturnRows(sqlmock.NewRows(commentCountColumns))
+
+	repo := repository.New(db)
+
+	tasks, err := repo.GetAllByUserID(context.TODO(), userID, &task.ListFilter{
+		IsComplete:   &isComplete,
+		Title:        "50%",
+		UpdatedAfter: &updatedAfter,
+		Sort:         task.SortByUpdatedAt,
+		Descending:   true,
+		Cursor:       task.NewCursor(&models.Task{ID: 4, UpdatedAt: cursorTime}, task.SortByUpdatedAt),
+		Limit:        11,
+	})
+
+	assert.NoError(err)
+	assert.Equal(1, len(tasks))
+	assert.NoError(mock.ExpectationsWereMet())
+}
+
+func TestGetAllByUserIDSortedByDueDate(t *testing.T) {
+	assert := assert.New(t)
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	rows := sqlmock.NewRows(taskColumns)
+
+	userID := int64(1)
+	dueAfter := time.Date(2019, 5, 6, 0, 0, 0, 0, time.UTC)
+	dueBefore := time.Date(2019, 5, 13, 0, 0, 0, 0, time.UTC)
+
+	query := selectTaskQuery +
+		"WHERE deleted_at IS NULL AND " + visibleCondition + "AND due_at>=\\? AND due_at<\\? " +
+		"AND \\(COALESCE\\(due_at, '9999-12-31 23:59:59'\\)>\\? OR \\(COALESCE\\(due_at, '9999-12-31 23:59:59'\\)=\\? AND id>\\?\\)\\) " +
+		"ORDER BY COALESCE\\(due_at, '9999-12-31 23:59:59'\\) ASC, id ASC LIMIT 11"
+
+	prep := mock.ExpectPrepare(query)
+	prep.ExpectQuery().
+		WithArgs(userID, userID, userID, userID, userID, dueAfter, dueBefore, "9999-12-31 23:59:59", "9999-12-31 23:59:59", int64(4)).
+		WillReturnRows(rows)
+
+	repo := repository.New(db)
+
+	tasks, err := repo.GetAllByUserID(context.TODO(), userID, &task.ListFilter{
+		DueAfter:  &dueAfter,
+		DueBefore: &dueBefore,
+		Sort:      task.SortByDueAt,
+		Cursor:    task.NewCursor(&models.Task{ID: 4}, task.SortByDueAt),
+		Limit:     11,
+	})
+
+	assert.NoError(err)
+	assert.Equal(0, len(tasks))
+	assert.NoError(mock.ExpectationsWereMet())
+}
+
+func TestGetAllByUserIDWithAllTags(t *testing.T) {
+	assert := assert.New(t)
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	rows := sqlmock.
+		NewRows(taskColumns).
+		AddRow(1, "title", "description", 1, nil, nil, false, false, 0, 65536, nil, nil, nil, nil, nil, time.Now(), time.Now()).
+		AddRow(2, "title", "description", 1, nil, nil, false, false, 0, 131072, nil, nil, nil, nil, nil, time.Now(), time.Now())
+
+	tagRows := sqlmock.
+		NewRows(taskTagColumns).
+		AddRow(1, 3, "home", nil).
+		AddRow(2, 3, "home", nil).
+		AddRow(1, 4, "work", "#00ff00").
+		AddRow(2, 4, "work", "#00ff00")
+
+	userID := int64(1)
+	query := selectTaskQuery +
+		"WHERE deleted_at IS NULL AND " + visibleCondition + "AND id IN \\(SELECT task_id FROM task_tag WHERE tag_id IN \\(\\?, \\?\\) " +
+		"GROUP BY task_id HAVING COUNT\\(\\*\\)=\\?\\) ORDER BY position ASC, id ASC LIMIT 11"
+
+	prep := mock.ExpectPrepare(query)
+	prep.ExpectQuery().WithArgs(userID, userID, userID, userID, userID, int64(3), int64(4), 2).WillReturnRows(rows)
+	tagPrep := mock.ExpectPrepare(selectTaskTagQuery + "\\(\\?, \\?\\) ORDER BY t.name")
+	tagPrep.ExpectQuery().WithArgs(int64(1), int64(2)).WillReturnRows(tagRows)
+	progressPrep := mock.ExpectPrepare(selectProgressQuery + "\\(\\?, \\?\\) AND deleted_at IS NULL GROUP BY parent_id")
+	progressPrep.ExpectQuery().WithArgs(int64(1), int64(2)).WillReturnRows(sqlmock.NewRows(progressColumns))
+	commentPrep := mock.ExpectPrepare(selectCommentCountQuery + "\\(\\?, \\?\\) GROUP BY task_id")
+	commentPrep.ExpectQuery().WithArgs(int64(1), int64(2)).WillReturnRows(sqlmock.NewRows(commentCountColumns))
+
+	repo := repository.New(db)
+
+	tasks, err := repo.GetAllByUserID(context.TODO(), userID, &task.ListFilter{
+		TagIDs:       []int64{3, 4},
+		MatchAllTags: true,
+		Sort:         task.SortByPosition,
+		Limit:        11,
+	})
+
+	assert.NoError(err)
+	assert.Equal(2, len(tasks))
+	assert.Equal(2, len(tasks[1].Tags))
+	assert.Equal("#00ff00", tasks[1].Tags[1].Color)
+	assert.NoError(mock.ExpectationsWereMet())
+}
+
+func TestGetAllByUserIDInInbox(t *testing.T) {
+	assert := assert.New(t)
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	userID := int64(1)
+	query := selectTaskQuery + "WHERE deleted_at IS NULL AND " + visibleCondition + "AND project_id IS NULL ORDER BY id ASC LIMIT 11"
+
+	prep := mock.ExpectPrepare(query)
+	prep.ExpectQuery().WithArgs(userID, userID, userID, userID, userID).WillReturnRows(sqlmock.NewRows(taskColumns))
+
+	repo := repository.New(db)
+
+	tasks, err := repo.GetAllByUserID(context.TODO(), userID, &task.ListFilter{
+		Inbox: true,
+		Sort:  task.SortByID,
+		Limit: 11,
+	})
+
+	assert.NoError(err)
+	assert.Equal(0, len(tasks))
+	assert.NoError(mock.ExpectationsWereMet())
+}
+
+func TestGetMaxPosition(t *testing.T) {
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	userID := int64(1)
+	rows := sqlmock.NewRows([]string{"position"}).AddRow(3 * task.PositionGap)
+
+	prep := mock.ExpectPrepare("SELECT COALESCE\\(MAX\\(position\\), 0\\) FROM task WHERE created_by=\\?")
+	prep.ExpectQuery().WithArgs(userID).WillReturnRows(rows)
+
+	repo := repository.New(db)
+
+	position, err := repo.GetMaxPosition(context.TODO(), userID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3*task.PositionGap, position)
+}
+
+func TestGetAdjacentBefore(t *testing.T) {
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	rows := sqlmock.
+		NewRows(taskColumns).
+		AddRow(3, "title", "description", 1, nil, nil, false, false, 0, 65536, nil, nil, nil, nil, nil, time.Now(), time.Now())
+
+	query := selectTaskQuery + "WHERE created_by=\\? AND id<>\\? AND position<\\? AND deleted_at IS NULL ORDER BY position DESC, id DESC LIMIT 1"
+
+	prep := mock.ExpectPrepare(query)
+	prep.ExpectQuery().WithArgs(int64(1), int64(2), int64(131072)).WillReturnRows(rows)
+
+	repo := repository.New(db)
+
+	task, err := repo.GetAdjacent(context.TODO(), 1, 131072, 2, true)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), task.ID)
+	assert.Equal(t, int64(65536), task.Position)
+}
+
+func TestUpdatePosition(t *testing.T) {
+	now := time.Now()
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE task SET position=\\?, updated_at=\\? WHERE id=\\?").
+		WithArgs(int64(98304), now, int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	repo := repository.New(db)
+
+	err = repo.UpdatePosition(context.TODO(), 1, 98304, now)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRebalancePositions(t *testing.T) {
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	userID := int64(1)
+	rows := sqlmock.NewRows([]string{"id"}).AddRow(5).AddRow(2)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT id FROM task WHERE created_by=\\? ORDER BY position ASC, id ASC FOR UPDATE").
+		WithArgs(userID).
+		WillReturnRows(rows)
+	prep := mock.ExpectPrepare("UPDATE task SET position=\\? WHERE id=\\?")
+	prep.ExpectExec().WithArgs(task.PositionGap, int64(5)).WillReturnResult(sqlmock.NewResult(0, 1))
+	prep.ExpectExec().WithArgs(2*task.PositionGap, int64(2)).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	repo := repository.New(db)
+
+	err = repo.RebalancePositions(context.TODO(), userID)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetAllByUserIDWithSubtasks(t *testing.T) {
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	userID := int64(1)
+	rows := sqlmock.
+		NewRows(taskColumns).
+		AddRow(1, "title", "description", 1, nil, nil, false, true, 0, 65536, nil, nil, nil, nil, nil, time.Now(), time.Now())
+	subtaskRows := sqlmock.
+		NewRows(taskColumns).
+		AddRow(2, "title", "description", 1, nil, 1, true, false, 0, 98304, nil, nil, nil, nil, nil, time.Now(), time.Now()).
+		AddRow(3, "title", "description", 1, nil, 1, false, false, 0, 114688, nil, nil, nil, nil, nil, time.Now(), time.Now())
+
+	query := selectTaskQuery + "WHERE deleted_at IS NULL AND " + visibleCondition + "AND parent_id IS NULL ORDER BY created_at ASC, id ASC LIMIT 10"
+
+	prep := mock.ExpectPrepare(query)
+	prep.ExpectQuery().WithArgs(userID, userID, userID, userID, userID).WillReturnRows(rows)
+	tagPrep := mock.ExpectPrepare(selectTaskTagQuery + "\\(\\?\\) ORDER BY t.name")
+	tagPrep.ExpectQuery().WithArgs(int64(1)).WillReturnRows(sqlmock.NewRows(taskTagColumns))
+	progressPrep := mock.ExpectPrepare(selectProgressQuery + "\\(\\?\\) AND deleted_at IS NULL GROUP BY parent_id")
+	progressPrep.ExpectQuery().WithArgs(int64(1)).WillReturnRows(sqlmock.NewRows(progressColumns).AddRow(1, 1, 2))
+	commentPrep := mock.ExpectPrepare(selectCommentCountQuery + "\\(\\?\\) GROUP BY task_id")
+	commentPrep.ExpectQuery().WithArgs(int64(1)).WillReturnRows(sqlmock.NewRows(commentCountColumns))
+	subtaskPrep := mock.ExpectPrepare(selectTaskQuery + "WHERE parent_id IN \\(\\?\\)\\s+AND deleted_at IS NULL ORDER BY position ASC, id ASC")
+	subtaskPrep.ExpectQuery().WithArgs(int64(1)).WillReturnRows(subtaskRows)
+	subtaskTagPrep := mock.ExpectPrepare(selectTaskTagQuery + "\\(\\?, \\?\\) ORDER BY t.name")
+	subtaskTagPrep.ExpectQuery().WithArgs(int64(2), int64(3)).WillReturnRows(sqlmock.NewRows(taskTagColumns))
+	subtaskCommentPrep := mock.ExpectPrepare(selectCommentCountQuery + "\\(\\?, \\?\\) GROUP BY task_id")
+	subtaskCommentPrep.ExpectQuery().WithArgs(int64(2), int64(3)).WillReturnRows(sqlmock.NewRows(commentCountColumns).AddRow(3, 2))
+
+	repo := repository.New(db)
+
+	filter := &task.ListFilter{TopLevelOnly: true, WithSubtasks: true, Limit: 10}
+	tasks, err := repo.GetAllByUserID(context.TODO(), userID, filter)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(tasks))
+	assert.Equal(t, &models.Progress{Completed: 1, Total: 2}, tasks[0].Progress)
+	assert.Equal(t, 2, len(tasks[0].Subtasks))
+	assert.Equal(t, int64(2), tasks[0].Subtasks[0].ID)
+	assert.Equal(t, int64(1), tasks[0].Subtasks[0].Parent.ID)
+	assert.Equal(t, int64(2), tasks[0].Subtasks[1].CommentCount)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetSubtasks(t *testing.T) {
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	parentID := int64(1)
+	rows := sqlmock.
+		NewRows(taskColumns).
+		AddRow(2, "title", "description", 1, nil, 1, false, false, 0, 98304, nil, nil, nil, nil, nil, time.Now(), time.Now())
+
+	prep := mock.ExpectPrepare(selectTaskQuery + "WHERE parent_id=\\? AND deleted_at IS NULL ORDER BY position ASC, id ASC")
+	prep.ExpectQuery().WithArgs(parentID).WillReturnRows(rows)
+	tagPrep := mock.ExpectPrepare(selectTaskTagQuery + "\\(\\?\\) ORDER BY t.name")
+	tagPrep.ExpectQuery().WithArgs(int64(2)).WillReturnRows(sqlmock.NewRows(taskTagColumns))
+	commentPrep := mock.ExpectPrepare(selectCommentCountQuery + "\\(\\?\\) GROUP BY task_id")
+	commentPrep.ExpectQuery().WithArgs(int64(2)).WillReturnRows(sqlmock.NewRows(commentCountColumns))
+
+	repo := repository.New(db)
+
+	subtasks, err := repo.GetSubtasks(context.TODO(), parentID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(subtasks))
+	assert.Equal(t, parentID, subtasks[0].Parent.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateCompletion(t *testing.T) {
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	now := time.Now()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(lockStateQuery).
+		WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"title", "is_complete"}).AddRow("title", false))
+	mock.ExpectExec("UPDATE task SET is_complete=\\?, revision=revision\\+1, updated_at=\\? WHERE id=\\?").
+		WithArgs(true, now, int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(insertEventQuery).
+		WithArgs(int64(1), "completed", "false", "true", nil, nil, now).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	repo := repository.New(db)
+
+	err = repo.UpdateCompletion(context.TODO(), int64(1), true, now)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAtomic(t *testing.T) {
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE task SET position=\\?, updated_at=\\? WHERE id=\\?").
+		WithArgs(int64(131072), sqlmock.AnyArg(), int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE task SET position=\\?, updated_at=\\? WHERE id=\\?").
+		WithArgs(int64(196608), sqlmock.AnyArg(), int64(2)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	repo := repository.New(db)
+
+	err = repo.Atomic(context.TODO(), func(ctx context.Context) error {
+		if err := repo.UpdatePosition(ctx, 1, 131072, time.Now()); err != nil {
+			return err
+		}
+
+		return repo.UpdatePosition(ctx, 2, 196608, time.Now())
+	})
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAtomicRollsBackOnError(t *testing.T) {
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE task SET position=\\?, updated_at=\\? WHERE id=\\?").
+		WithArgs(int64(131072), sqlmock.AnyArg(), int64(1)).
+		WillReturnError(errors.New("lock wait timeout"))
+	mock.ExpectRollback()
+
+	repo := repository.New(db)
+
+	err = repo.Atomic(context.TODO(), func(ctx context.Context) error {
+		return repo.UpdatePosition(ctx, 1, 131072, time.Now())
+	})
+
+	assert.EqualError(t, err, "lock wait timeout")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAtomicNestedUsesSavepoints(t *testing.T) {
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("SAVEPOINT atomic_1").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("UPDATE task SET position=\\?, updated_at=\\? WHERE id=\\?").
+		WithArgs(int64(131072), sqlmock.AnyArg(), int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("RELEASE SAVEPOINT atomic_1").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("SAVEPOINT atomic_1").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("UPDATE task SET position=\\?, updated_at=\\? WHERE id=\\?").
+		WithArgs(int64(196608), sqlmock.AnyArg(), int64(2)).
+		WillReturnError(errors.New("lock wait timeout"))
+	mock.ExpectExec("ROLLBACK TO SAVEPOINT atomic_1").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	repo := repository.New(db)
+	failures := 0
+
+	err = repo.Atomic(context.TODO(), func(ctx context.Context) error {
+		positions := map[int64]int64{1: 131072, 2: 196608}
+
+		for _, id := range []int64{1, 2} {
+			err := repo.Atomic(ctx, func(ctx context.Context) error {
+				return repo.UpdatePosition(ctx, id, positions[id], time.Now())
+			})
+
+			if err != nil {
+				failures++
+			}
+		}
+
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, failures)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+)
+
+// conn is implemented by both sql.DB and sql.Tx
+type conn interface {
+	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// transaction is the transaction in which a single change is made
+type transaction interface {
+	conn
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	Commit() error
+	Rollback() error
+}
+
+// atomicTx is the transaction started by Atomic, along with the nesting depth of the Atomic calls
+type atomicTx struct {
+	tx    *sql.Tx
+	depth int
+}
+
+type atomicTxKey struct{}
+
+// joinedTx is used for the changes made inside the transaction of Atomic.
+// Such changes are committed or rolled back along with that transaction.
+type joinedTx struct {
+	*sql.Tx
+}
+
+// Commit is a no-op, since the transaction is committed by Atomic
+func (tx *joinedTx) Commit() error {
+	return nil
+}
+
+// Rollback is a no-op, since the failed change is rolled back by Atomic
+func (tx *joinedTx) Rollback() error {
+	return nil
+}
+
+// Atomic runs fn in a transaction. Changes made through the repository using the context passed to fn
+// are committed only if fn succeeds. Nested calls use savepoints, so that a failed nested call is rolled back
+// without ending the outer transaction.
+func (repo *mySQLRepo) Atomic(ctx context.Context, fn func(ctx context.Context) error) error {
+	if outer, ok := ctx.Value(atomicTxKey{}).(*atomicTx); ok {
+		return repo.savepoint(ctx, outer, fn)
+	}
+
+	tx, err := repo.DB.BeginTx(ctx, nil)
+
+	if err != nil {
+		return err
+	}
+
+	if err = fn(context.WithValue(ctx, atomicTxKey{}, &atomicTx{tx: tx})); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// savepoint runs fn inside the transaction of an outer Atomic call, and rolls back only the changes of fn if it fails
+func (repo *mySQLRepo) savepoint(ctx context.Context, outer *atomicTx, fn func(ctx context.Context) error) error {
+	nested := &atomicTx{tx: outer.tx, depth: outer.depth + 1}
+	name := "atomic_" + strconv.Itoa(nested.depth)
+
+	if _, err := nested.tx.ExecContext(ctx, "SAVEPOINT "+name); err != nil {
+		return err
+	}
+
+	if err := fn(context.WithValue(ctx, atomicTxKey{}, nested)); err != nil {
+		if _, rollbackErr := nested.tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+name); rollbackErr != nil {
+			return rollbackErr
+		}
+
+		return err
+	}
+
+	_, err := nested.tx.ExecContext(ctx, "RELEASE SAVEPOINT "+name)
+
+	return err
+}
+
+// conn returns the transaction of Atomic carried by the context, or the database if there is none
+func (repo *mySQLRepo) conn(ctx context.Context) conn {
+	if current, ok := ctx.Value(atomicTxKey{}).(*atomicTx); ok {
+		return current.tx
+	}
+
+	return repo.DB
+}
+
+// begin starts the transaction for a change, or joins the transaction of Atomic carried by the context
+func (repo *mySQLRepo) begin(ctx context.Context) (transaction, error) {
+	if current, ok := ctx.Value(atomicTxKey{}).(*atomicTx); ok {
+		return &joinedTx{Tx: current.tx}, nil
+	}
+
+	return repo.DB.BeginTx(ctx, nil)
+}
@@ -9,5 +9,14 @@ import (
 // Service represents task service contract
 type Service interface {
 	Create(ctx context.Context, newTask *models.Task) error
-	List(ctx context.Context, userID int64) ([]*models.Task, error)
+	List(ctx context.Context, userID int64, filter *ListFilter) (*Page, error)
+	GetByID(ctx context.Context, id int64, userID int64) (*models.Task, error)
+	ListSubtasks(ctx context.Context, id int64, userID int64) ([]*models.Task, error)
+	Update(ctx context.Context, task *models.Task, userID int64) error
+	Delete(ctx context.Context, id int64, userID int64) error
+	Move(ctx context.Context, id int64, anchorID int64, before bool, userID int64) (*models.Task, error)
+	Bulk(ctx context.Context, change *BulkChange, userID int64) ([]*BulkResult, error)
+	Export(ctx context.Context, userID int64, fn func(task *models.Task) error) error
+	Import(ctx context.Context, tasks []*models.Task, userID int64, dryRun bool) ([]*ImportFailure, error)
+	Authorize(ctx context.Context, id int64, userID int64, required string, action string) (*models.Task, error)
 }
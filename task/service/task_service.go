@@ -2,28 +2,985 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"time"
 
+	"github.com/dheerajgopi/todo-api/collaborator"
+	todoErr "github.com/dheerajgopi/todo-api/common/error"
 	"github.com/dheerajgopi/todo-api/models"
+	"github.com/dheerajgopi/todo-api/project"
+	"github.com/dheerajgopi/todo-api/tag"
 	"github.com/dheerajgopi/todo-api/task"
+	"github.com/dheerajgopi/todo-api/user"
 )
 
+// errBulkAborted ends the transaction of an all-or-nothing bulk change when any of the tasks fails
+var errBulkAborted = errors.New("bulk change aborted")
+
+// errImportRejected ends the transaction of an import when any of the tasks fails, or when it is a dry run
+var errImportRejected = errors.New("import rejected")
+
 type taskService struct {
-	taskRepo task.Repository
+	taskRepo         task.Repository
+	tagRepo          tag.Repository
+	projectRepo      project.Repository
+	collaboratorRepo collaborator.Repository
+	userRepo         user.Repository
 }
 
 // New returns a new object implementing task.Service interface
-func New(repo task.Repository) task.Service {
+func New(repo task.Repository, tagRepo tag.Repository, projectRepo project.Repository, collaboratorRepo collaborator.Repository,
+	userRepo user.Repository) task.Service {
 	return &taskService{
-		taskRepo: repo,
+		taskRepo:         repo,
+		tagRepo:          tagRepo,
+		projectRepo:      projectRepo,
+		collaboratorRepo: collaboratorRepo,
+		userRepo:         userRepo,
 	}
 }
 
-// Create creates a new task
+// Create creates a new task, placed after all existing tasks of the user.
+// Subtasks are created in the project of their parent, and can not have subtasks of their own.
+// Subtasks belong to the owner of their parent, even when they are added by an editor of the parent.
+// Series of a recurring task starts at its due date.
 func (service *taskService) Create(ctx context.Context, newTask *models.Task) error {
-	return service.taskRepo.Create(ctx, newTask)
+	if newTask.Parent != nil {
+		parent, err := service.Authorize(ctx, newTask.Parent.ID, newTask.CreatedBy.ID, collaborator.RoleEditor, "add subtasks to")
+
+		if err != nil {
+			return err
+		}
+
+		if parent.Parent != nil {
+			return &todoErr.InvalidValueError{
+				Resource: "task",
+				Field:    "parentId",
+				Reason:   "Subtasks can not have subtasks",
+			}
+		}
+
+		newTask.CreatedBy = parent.CreatedBy
+		newTask.Project = parent.Project
+	} else if err := service.checkProject(ctx, newTask.Project, newTask.CreatedBy.ID, collaborator.RoleEditor); err != nil {
+		return err
+	}
+
+	tags, err := service.resolveTags(ctx, newTask.Tags, newTask.CreatedBy.ID)
+
+	if err != nil {
+		return err
+	}
+
+	newTask.Tags = tags
+
+	if err = checkRecurrence(newTask, nil); err != nil {
+		return err
+	}
+
+	maxPosition, err := service.taskRepo.GetMaxPosition(ctx, newTask.CreatedBy.ID)
+
+	if err != nil {
+		return err
+	}
+
+	newTask.Position = maxPosition + task.PositionGap
+
+	if err = service.taskRepo.Create(ctx, newTask); err != nil {
+		return err
+	}
+
+	if newTask.Parent != nil {
+		return service.syncCompletion(ctx, newTask.Parent.ID)
+	}
+
+	return nil
+}
+
+// List returns a page of tasks created by an user, or shared with the user.
+// One extra task is fetched to find out whether more pages are available.
+// ResourceNotFoundError is returned if the tasks are filtered by a project which is not accessible to the user.
+func (service *taskService) List(ctx context.Context, userID int64, filter *task.ListFilter) (*task.Page, error) {
+	if filter.ProjectID != nil {
+		if err := service.checkProject(ctx, &models.Project{ID: *filter.ProjectID}, userID, collaborator.RoleViewer); err != nil {
+			return nil, err
+		}
+	}
+
+	repoFilter := *filter
+
+	if repoFilter.Sort == "" {
+		repoFilter.Sort = task.SortByCreatedAt
+	}
+
+	if repoFilter.Limit <= 0 || repoFilter.Limit > task.MaxListLimit {
+		repoFilter.Limit = task.DefaultListLimit
+	}
+
+	limit := repoFilter.Limit
+	repoFilter.Limit = limit + 1
+
+	tasks, err := service.taskRepo.GetAllByUserID(ctx, userID, &repoFilter)
+
+	if err != nil {
+		return nil, err
+	}
+
+	page := &task.Page{
+		Tasks: tasks,
+	}
+
+	if len(tasks) > limit {
+		page.Tasks = tasks[:limit]
+		page.HasMore = true
+		page.NextCursor = task.NewCursor(page.Tasks[limit-1], repoFilter.Sort).Encode()
+	}
+
+	if err = service.markShared(ctx, page.Tasks, userID, make(map[int64]*models.User)); err != nil {
+		return nil, err
+	}
+
+	return page, nil
+}
+
+// GetByID returns the task with the given id, if it is owned by the user or shared with the user.
+// ResourceNotFoundError is returned if the task is missing or not accessible to the user.
+func (service *taskService) GetByID(ctx context.Context, id int64, userID int64) (*models.Task, error) {
+	existingTask, err := service.Authorize(ctx, id, userID, collaborator.RoleViewer, "view")
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err = service.markShared(ctx, []*models.Task{existingTask}, userID, make(map[int64]*models.User)); err != nil {
+		return nil, err
+	}
+
+	return existingTask, nil
+}
+
+// ListSubtasks returns the subtasks of a task accessible to the user, in manual order
+func (service *taskService) ListSubtasks(ctx context.Context, id int64, userID int64) ([]*models.Task, error) {
+	if _, err := service.Authorize(ctx, id, userID, collaborator.RoleViewer, "view"); err != nil {
+		return nil, err
+	}
+
+	subtasks, err := service.taskRepo.GetSubtasks(ctx, id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err = service.markShared(ctx, subtasks, userID, make(map[int64]*models.User)); err != nil {
+		return nil, err
+	}
+
+	return subtasks, nil
+}
+
+// Update overwrites an existing task, if the user is its owner or an editor.
+// Owner, parent, position and creation time are always retained from the stored task,
+// and subtasks always stay in the project of their parent.
+// Task can be moved only to projects in which the user can add tasks.
+// Parent of a subtask is completed or reopened along with it, if the parent has auto completion.
+// Next occurrence of a recurring task is created when the task is completed.
+func (service *taskService) Update(ctx context.Context, task *models.Task, userID int64) error {
+	existingTask, err := service.Authorize(ctx, task.ID, userID, collaborator.RoleEditor, "update")
+
+	if err != nil {
+		return err
+	}
+
+	task.CreatedBy = existingTask.CreatedBy
+	task.Parent = existingTask.Parent
+	task.Position = existingTask.Position
+	task.Progress = existingTask.Progress
+	task.CreatedAt = existingTask.CreatedAt
+
+	if task.Parent != nil {
+		task.Project = existingTask.Project
+	}
+
+	tags, err := service.resolveTags(ctx, task.Tags, existingTask.CreatedBy.ID)
+
+	if err != nil {
+		return err
+	}
+
+	task.Tags = tags
+
+	if task.Parent == nil && projectID(task) != projectID(existingTask) {
+		if err = service.checkProject(ctx, task.Project, userID, collaborator.RoleEditor); err != nil {
+			return err
+		}
+	}
+
+	if err = checkRecurrence(task, existingTask); err != nil {
+		return err
+	}
+
+	var next *models.Task
+
+	if task.IsComplete && !existingTask.IsComplete && task.Recurrence != "" {
+		next = moveRecurrence(task)
+	}
+
+	// task is completed along with creating its next occurrence, so that a failure does not end the series
+	return service.taskRepo.Atomic(ctx, func(ctx context.Context) error {
+		if err := service.taskRepo.Update(ctx, task); err != nil {
+			return err
+		}
+
+		if next != nil {
+			if err := service.createOccurrence(ctx, next, task); err != nil {
+				return err
+			}
+		}
+
+		if task.Parent != nil && task.IsComplete != existingTask.IsComplete {
+			return service.syncCompletion(ctx, task.Parent.ID)
+		}
+
+		if task.AutoComplete && !existingTask.AutoComplete {
+			return service.syncCompletion(ctx, task.ID)
+		}
+
+		return nil
+	})
+}
+
+// Delete moves an existing task owned by the user to trash, along with its subtasks.
+// Subtasks can be removed by the editors of the task too.
+func (service *taskService) Delete(ctx context.Context, id int64, userID int64) error {
+	existingTask, err := service.Authorize(ctx, id, userID, collaborator.RoleEditor, "delete")
+
+	if err != nil {
+		return err
+	}
+
+	if existingTask.Parent == nil && existingTask.CreatedBy.ID != userID {
+		return &todoErr.PermissionDeniedError{
+			Resource: "task",
+			Action:   "delete",
+		}
+	}
+
+	if err = service.taskRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	if existingTask.Parent != nil {
+		return service.syncCompletion(ctx, existingTask.Parent.ID)
+	}
+
+	return nil
+}
+
+// Move places a task right before or after the anchor task, in the manual ordering of the user's tasks.
+// Subtasks can only be placed next to subtasks of the same parent, and top level tasks next to top level tasks.
+// Only the moved task is updated, unless there is no free position left next to the anchor.
+// In that case, the positions of all tasks of the user are spread out before moving the task.
+// Only the owner can move tasks, since the manual ordering belongs to the owner.
+func (service *taskService) Move(ctx context.Context, id int64, anchorID int64, before bool, userID int64) (*models.Task, error) {
+	movedTask, err := service.Authorize(ctx, id, userID, collaborator.RoleOwner, "move")
+
+	if err != nil {
+		return nil, err
+	}
+
+	anchorTask, err := service.Authorize(ctx, anchorID, userID, collaborator.RoleOwner, "move")
+
+	if err != nil {
+		return nil, err
+	}
+
+	if movedTask.ID == anchorTask.ID {
+		return movedTask, nil
+	}
+
+	if parentID(movedTask) != parentID(anchorTask) {
+		field := "afterId"
+
+		if before {
+			field = "beforeId"
+		}
+
+		return nil, &todoErr.InvalidValueError{
+			Resource: "task",
+			Field:    field,
+			Reason:   "Task can only be moved next to its siblings",
+		}
+	}
+
+	position, ok, err := service.positionNextTo(ctx, anchorTask, movedTask.ID, before)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !ok {
+		if err = service.taskRepo.RebalancePositions(ctx, userID); err != nil {
+			return nil, err
+		}
+
+		if anchorTask, err = service.Authorize(ctx, anchorID, userID, collaborator.RoleOwner, "move"); err != nil {
+			return nil, err
+		}
+
+		if position, _, err = service.positionNextTo(ctx, anchorTask, movedTask.ID, before); err != nil {
+			return nil, err
+		}
+	}
+
+	movedTask.Position = position
+	movedTask.UpdatedAt = time.Now()
+
+	if err = service.taskRepo.UpdatePosition(ctx, movedTask.ID, movedTask.Position, movedTask.UpdatedAt); err != nil {
+		return nil, err
+	}
+
+	return movedTask, nil
+}
+
+// Bulk applies the operations to each of the tasks in a single transaction, with the same checks as for a single task.
+// Changes of each task are made in a savepoint, so that a failed task is rolled back alone. In all-or-nothing mode,
+// first failure rolls back the whole transaction, and the remaining tasks are skipped.
+func (service *taskService) Bulk(ctx context.Context, change *task.BulkChange, userID int64) ([]*task.BulkResult, error) {
+	results := make([]*task.BulkResult, 0, len(change.TaskIDs))
+
+	err := service.taskRepo.Atomic(ctx, func(ctx context.Context) error {
+		aborted := false
+
+		for _, id := range change.TaskIDs {
+			result := &task.BulkResult{
+				TaskID: id,
+				Status: task.BulkSucceeded,
+			}
+
+			results = append(results, result)
+
+			if aborted {
+				result.Status = task.BulkSkipped
+				continue
+			}
+
+			err := service.taskRepo.Atomic(ctx, func(ctx context.Context) error {
+				return service.applyOperations(ctx, id, change.Operations, userID)
+			})
+
+			if err != nil {
+				result.Status = task.BulkFailed
+				result.Err = err
+				aborted = change.AllOrNothing
+			}
+		}
+
+		if aborted {
+			return errBulkAborted
+		}
+
+		return nil
+	})
+
+	if err == errBulkAborted {
+		for _, result := range results {
+			if result.Status == task.BulkSucceeded {
+				result.Status = task.BulkRolledBack
+			}
+		}
+
+		return results, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// applyOperations applies the operations of a bulk change to a task, through the same methods used for a single task
+func (service *taskService) applyOperations(ctx context.Context, id int64, operations []*task.BulkOperation, userID int64) error {
+	for _, operation := range operations {
+		if operation.Type == task.BulkDelete {
+			if err := service.Delete(ctx, id, userID); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		existingTask, err := service.GetByID(ctx, id, userID)
+
+		if err != nil {
+			return err
+		}
+
+		switch operation.Type {
+		case task.BulkComplete:
+			existingTask.IsComplete = true
+		case task.BulkReopen:
+			existingTask.IsComplete = false
+		case task.BulkMoveToProject:
+			existingTask.Project = operation.Project
+		case task.BulkAddTag:
+			existingTask.Tags = append(existingTask.Tags, operation.Tag)
+		}
+
+		existingTask.UpdatedAt = time.Now()
+
+		if err = service.Update(ctx, existingTask, userID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Export passes every task created by an user to fn in the order of creation, subtasks included
+func (service *taskService) Export(ctx context.Context, userID int64, fn func(task *models.Task) error) error {
+	return service.taskRepo.Export(ctx, userID, fn)
+}
+
+// Import creates a list of tasks for an user in a single transaction, in the given order.
+// Projects and tags of the tasks are looked up by name among those accessible to the user,
+// and a subtask is created under its parent, which should come before it in the list.
+// Every task is tried, but nothing is stored if any of them fails or if it is a dry run.
+func (service *taskService) Import(ctx context.Context, tasks []*models.Task, userID int64, dryRun bool) ([]*task.ImportFailure, error) {
+	projects, err := service.projectsByName(ctx, userID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	tags, err := service.tagsByName(ctx, userID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	failures := make([]*task.ImportFailure, 0)
+
+	err = service.taskRepo.Atomic(ctx, func(ctx context.Context) error {
+		for index, newTask := range tasks {
+			err := service.taskRepo.Atomic(ctx, func(ctx context.Context) error {
+				return service.importTask(ctx, newTask, projects, tags)
+			})
+
+			if err != nil {
+				failures = append(failures, &task.ImportFailure{
+					Index: index,
+					Err:   err,
+				})
+			}
+		}
+
+		if dryRun || len(failures) > 0 {
+			return errImportRejected
+		}
+
+		return nil
+	})
+
+	if err != nil && err != errImportRejected {
+		return nil, err
+	}
+
+	return failures, nil
+}
+
+// importTask replaces the project and the tags of an imported task with the existing ones of the same name, and creates the task
+func (service *taskService) importTask(ctx context.Context, newTask *models.Task, projects map[string]*models.Project,
+	tags map[string]*models.Tag) error {
+	if newTask.Parent != nil && newTask.Parent.ID == 0 {
+		return &todoErr.InvalidValueError{
+			Resource: "task",
+			Field:    "parentId",
+			Reason:   "Parent task could not be imported",
+		}
+	}
+
+	if newTask.Project != nil {
+		existingProject, ok := projects[newTask.Project.Name]
+
+		if !ok {
+			return &todoErr.InvalidValueError{
+				Resource: "task",
+				Field:    "project",
+				Reason:   fmt.Sprintf("Project %s does not exist", newTask.Project.Name),
+			}
+		}
+
+		newTask.Project = existingProject
+	}
+
+	for i, tag := range newTask.Tags {
+		existingTag, ok := tags[tag.Name]
+
+		if !ok {
+			return &todoErr.InvalidValueError{
+				Resource: "task",
+				Field:    "tags",
+				Reason:   fmt.Sprintf("Tag %s does not exist", tag.Name),
+			}
+		}
+
+		newTask.Tags[i] = existingTag
+	}
+
+	return service.Create(ctx, newTask)
 }
 
-// List returns tasks created by an user
-func (service *taskService) List(ctx context.Context, userID int64) ([]*models.Task, error) {
-	return service.taskRepo.GetAllByUserID(ctx, userID)
+// projectsByName returns the active projects accessible to an user by their names.
+// Projects of the user win over shared projects with the same name.
+func (service *taskService) projectsByName(ctx context.Context, userID int64) (map[string]*models.Project, error) {
+	projects, err := service.projectRepo.GetAllByUserID(ctx, userID, false)
+
+	if err != nil {
+		return nil, err
+	}
+
+	projectsByName := make(map[string]*models.Project)
+
+	for _, existingProject := range projects {
+		if _, ok := projectsByName[existingProject.Name]; !ok || existingProject.CreatedBy.ID == userID {
+			projectsByName[existingProject.Name] = existingProject
+		}
+	}
+
+	return projectsByName, nil
+}
+
+// tagsByName returns the tags of an user by their names
+func (service *taskService) tagsByName(ctx context.Context, userID int64) (map[string]*models.Tag, error) {
+	tags, err := service.tagRepo.GetAllByUserID(ctx, userID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	tagsByName := make(map[string]*models.Tag)
+
+	for _, existingTag := range tags {
+		tagsByName[existingTag.Name] = existingTag
+	}
+
+	return tagsByName, nil
+}
+
+// positionNextTo finds a free position between the anchor task and its neighbour on the requested side
+func (service *taskService) positionNextTo(ctx context.Context, anchorTask *models.Task, movedID int64, before bool) (int64, bool, error) {
+	neighbour, err := service.taskRepo.GetAdjacent(ctx, anchorTask.CreatedBy.ID, anchorTask.Position, movedID, before)
+
+	if err != nil {
+		return 0, false, err
+	}
+
+	lower, upper := anchorTask.Position, anchorTask.Position+2*task.PositionGap
+
+	if before {
+		lower, upper = anchorTask.Position-2*task.PositionGap, anchorTask.Position
+	}
+
+	if neighbour != nil && before {
+		lower = neighbour.Position
+	} else if neighbour != nil {
+		upper = neighbour.Position
+	}
+
+	position, ok := task.PositionBetween(lower, upper)
+
+	return position, ok, nil
+}
+
+// resolveTags loads the tags referred by id, which should all be owned by the task owner.
+// ResourceNotFoundError is returned if any of the tags is missing or owned by someone else.
+func (service *taskService) resolveTags(ctx context.Context, tags []*models.Tag, ownerID int64) ([]*models.Tag, error) {
+	if len(tags) == 0 {
+		return make([]*models.Tag, 0), nil
+	}
+
+	ids := make([]int64, 0, len(tags))
+	seen := make(map[int64]bool)
+
+	for _, tag := range tags {
+		if !seen[tag.ID] {
+			seen[tag.ID] = true
+			ids = append(ids, tag.ID)
+		}
+	}
+
+	existingTags, err := service.tagRepo.GetByIDs(ctx, ids)
+
+	if err != nil {
+		return nil, err
+	}
+
+	ownedTags := make([]*models.Tag, 0, len(existingTags))
+
+	for _, existingTag := range existingTags {
+		if existingTag.CreatedBy.ID == ownerID {
+			ownedTags = append(ownedTags, existingTag)
+		}
+	}
+
+	if len(ownedTags) != len(ids) {
+		return nil, &todoErr.ResourceNotFoundError{
+			Resource: "tag",
+		}
+	}
+
+	return ownedTags, nil
+}
+
+// checkProject verifies that the role of the user on the project of a task allows the required access.
+// Adding tasks to a project needs the editor role, and listing its tasks needs the viewer role.
+// ResourceNotFoundError is returned if the project is missing or not accessible to the user.
+func (service *taskService) checkProject(ctx context.Context, project *models.Project, userID int64, required string) error {
+	if project == nil {
+		return nil
+	}
+
+	role, err := service.projectRole(ctx, project.ID, userID)
+
+	if err != nil {
+		return err
+	}
+
+	action := "view"
+
+	if required != collaborator.RoleViewer {
+		action = "add tasks to"
+	}
+
+	return collaborator.CheckRole(collaborator.ResourceProject, role, required, action)
+}
+
+// projectRole returns the role of an user on a project, or an empty role if the project is missing or not shared with the user
+func (service *taskService) projectRole(ctx context.Context, projectID int64, userID int64) (string, error) {
+	existingProject, err := service.projectRepo.GetByID(ctx, projectID)
+
+	if err != nil || existingProject == nil {
+		return "", err
+	}
+
+	if existingProject.CreatedBy.ID == userID {
+		return collaborator.RoleOwner, nil
+	}
+
+	return service.collaboratorRepo.GetRole(ctx, collaborator.ResourceProject, projectID, userID)
+}
+
+// Authorize loads a task and verifies that the role of the user on it allows an action.
+// ResourceNotFoundError is returned if the task is missing or not accessible to the user,
+// and PermissionDeniedError is returned if the role of the user is lower than the required role.
+func (service *taskService) Authorize(ctx context.Context, id int64, userID int64, required string, action string) (*models.Task, error) {
+	existingTask, err := service.taskRepo.GetByID(ctx, id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	role := ""
+
+	if existingTask != nil {
+		if role, err = service.roleOf(ctx, existingTask, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = collaborator.CheckRole(collaborator.ResourceTask, role, required, action); err != nil {
+		return nil, err
+	}
+
+	return existingTask, nil
+}
+
+// roleOf returns the role of an user on a task. Other than the owner, users get the highest role given to them
+// on the task, on its parent or on its project. Owner of the project can edit all tasks in the project.
+func (service *taskService) roleOf(ctx context.Context, existingTask *models.Task, userID int64) (string, error) {
+	if existingTask.CreatedBy.ID == userID {
+		return collaborator.RoleOwner, nil
+	}
+
+	role, err := service.collaboratorRepo.GetRole(ctx, collaborator.ResourceTask, existingTask.ID, userID)
+
+	if err != nil {
+		return "", err
+	}
+
+	if existingTask.Parent != nil {
+		parentRole, err := service.collaboratorRepo.GetRole(ctx, collaborator.ResourceTask, existingTask.Parent.ID, userID)
+
+		if err != nil {
+			return "", err
+		}
+
+		role = collaborator.HigherRole(role, parentRole)
+	}
+
+	if existingTask.Project != nil {
+		projectRole, err := service.projectRole(ctx, existingTask.Project.ID, userID)
+
+		if err != nil {
+			return "", err
+		}
+
+		if projectRole == collaborator.RoleOwner {
+			projectRole = collaborator.RoleEditor
+		}
+
+		role = collaborator.HigherRole(role, projectRole)
+	}
+
+	return role, nil
+}
+
+// markShared sets the owner as SharedBy of the tasks and subtasks which are not owned by the user.
+// Owners are read once per call, and cached in the given map.
+func (service *taskService) markShared(ctx context.Context, tasks []*models.Task, userID int64, owners map[int64]*models.User) error {
+	for _, sharedTask := range tasks {
+		ownerID := sharedTask.CreatedBy.ID
+
+		if ownerID != userID {
+			owner, ok := owners[ownerID]
+
+			if !ok {
+				existingUser, err := service.userRepo.GetByID(ctx, ownerID)
+
+				if err != nil {
+					return err
+				}
+
+				if existingUser != nil {
+					owner = &models.User{
+						ID:    existingUser.ID,
+						Name:  existingUser.Name,
+						Email: existingUser.Email,
+					}
+				}
+
+				owners[ownerID] = owner
+			}
+
+			sharedTask.SharedBy = owner
+		}
+
+		if err := service.markShared(ctx, sharedTask.Subtasks, userID, owners); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// syncCompletion completes a task with auto completion once all its subtasks are complete,
+// and reopens it when any of its subtasks is reopened or added.
+func (service *taskService) syncCompletion(ctx context.Context, id int64) error {
+	parent, err := service.taskRepo.GetByID(ctx, id)
+
+	if err != nil || parent == nil || !parent.AutoComplete || parent.Progress == nil {
+		return err
+	}
+
+	if parent.Progress.Total == 0 {
+		return nil
+	}
+
+	isComplete := parent.Progress.Completed == parent.Progress.Total
+
+	if parent.IsComplete == isComplete {
+		return nil
+	}
+
+	if !isComplete || parent.Recurrence == "" {
+		return service.taskRepo.UpdateCompletion(ctx, parent.ID, isComplete, time.Now())
+	}
+
+	parent.IsComplete = true
+	parent.UpdatedAt = time.Now()
+	next := moveRecurrence(parent)
+
+	return service.taskRepo.Atomic(ctx, func(ctx context.Context) error {
+		if err := service.taskRepo.Update(ctx, parent); err != nil || next == nil {
+			return err
+		}
+
+		return service.createOccurrence(ctx, next, parent)
+	})
+}
+
+// createOccurrence stores the next occurrence of a completed recurring task after all existing tasks of the user,
+// along with reopened copies of the subtasks of the completed task
+func (service *taskService) createOccurrence(ctx context.Context, next *models.Task, completedTask *models.Task) error {
+	maxPosition, err := service.taskRepo.GetMaxPosition(ctx, next.CreatedBy.ID)
+
+	if err != nil {
+		return err
+	}
+
+	next.Position = maxPosition + task.PositionGap
+
+	if err = service.taskRepo.Create(ctx, next); err != nil {
+		return err
+	}
+
+	subtasks, err := service.taskRepo.GetSubtasks(ctx, completedTask.ID)
+
+	if err != nil {
+		return err
+	}
+
+	for i, subtask := range subtasks {
+		subtaskCopy := &models.Task{
+			Title:       subtask.Title,
+			Description: subtask.Description,
+			CreatedBy:   next.CreatedBy,
+			Project:     next.Project,
+			Parent:      &models.Task{ID: next.ID},
+			Priority:    subtask.Priority,
+			Position:    next.Position + int64(i+1)*task.PositionGap,
+			DueAt:       shiftDate(subtask.DueAt, *completedTask.DueAt, *next.DueAt),
+			DueTimezone: subtask.DueTimezone,
+			RemindAt:    shiftDate(subtask.RemindAt, *completedTask.DueAt, *next.DueAt),
+			Tags:        subtask.Tags,
+			CreatedAt:   next.CreatedAt,
+			UpdatedAt:   next.UpdatedAt,
+		}
+
+		if err = service.taskRepo.Create(ctx, subtaskCopy); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkRecurrence validates the recurrence rule of a task, and stores it in canonical form.
+// Series starts at the due date when the rule is set or changed, or when the due date is moved before the series start.
+// InvalidValueError is returned for invalid rules, recurring subtasks, and recurring tasks without due date.
+func checkRecurrence(recurringTask *models.Task, existingTask *models.Task) error {
+	if recurringTask.Recurrence == "" {
+		recurringTask.RecurrenceStart = nil
+		return nil
+	}
+
+	recurrence, err := task.ParseRecurrence(recurringTask.Recurrence)
+
+	if err != nil {
+		return invalidRecurrence(err.Error())
+	}
+
+	if recurringTask.Parent != nil {
+		return invalidRecurrence("Subtasks can not recur")
+	}
+
+	if recurringTask.DueAt == nil {
+		return invalidRecurrence("Due date is required for recurring tasks")
+	}
+
+	recurringTask.Recurrence = recurrence.String()
+	recurringTask.RecurrenceStart = recurringTask.DueAt
+
+	if existingTask != nil && existingTask.Recurrence == recurringTask.Recurrence && existingTask.RecurrenceStart != nil &&
+		!recurringTask.DueAt.Before(*existingTask.RecurrenceStart) {
+		recurringTask.RecurrenceStart = existingTask.RecurrenceStart
+	}
+
+	return nil
+}
+
+// moveRecurrence builds the occurrence following a recurring task which is being completed, or nil if the series has ended.
+// Recurrence is removed from the completed task, so that reopening and completing it again does not repeat the occurrence.
+// Occurrences are computed in the timezone of the due date, so that they keep their wall clock time across DST changes.
+func moveRecurrence(completedTask *models.Task) *models.Task {
+	recurrence, err := task.ParseRecurrence(completedTask.Recurrence)
+	start := completedTask.RecurrenceStart
+
+	completedTask.Recurrence = ""
+	completedTask.RecurrenceStart = nil
+
+	if err != nil || completedTask.DueAt == nil {
+		return nil
+	}
+
+	if start == nil {
+		start = completedTask.DueAt
+	}
+
+	location, err := time.LoadLocation(completedTask.DueTimezone)
+
+	if err != nil {
+		location = time.UTC
+	}
+
+	dueAt, ok := recurrence.Next(start.In(location), completedTask.DueAt.In(location))
+
+	if !ok {
+		return nil
+	}
+
+	now := time.Now()
+
+	return &models.Task{
+		Title:           completedTask.Title,
+		Description:     completedTask.Description,
+		CreatedBy:       completedTask.CreatedBy,
+		Project:         completedTask.Project,
+		AutoComplete:    completedTask.AutoComplete,
+		Priority:        completedTask.Priority,
+		DueAt:           &dueAt,
+		DueTimezone:     completedTask.DueTimezone,
+		RemindAt:        shiftDate(completedTask.RemindAt, *completedTask.DueAt, dueAt),
+		Recurrence:      recurrence.String(),
+		RecurrenceStart: start,
+		Tags:            completedTask.Tags,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+}
+
+// shiftDate moves a time by the number of calendar days between two occurrences,
+// keeping its wall clock time in the location of the next occurrence
+func shiftDate(date *time.Time, from time.Time, to time.Time) *time.Time {
+	if date == nil {
+		return nil
+	}
+
+	location := to.Location()
+	fromYear, fromMonth, fromDay := from.In(location).Date()
+	toYear, toMonth, toDay := to.Date()
+	days := time.Date(toYear, toMonth, toDay, 0, 0, 0, 0, time.UTC).Sub(time.Date(fromYear, fromMonth, fromDay, 0, 0, 0, 0, time.UTC))
+
+	shifted := date.In(location).AddDate(0, 0, int(days.Hours()/24))
+
+	return &shifted
+}
+
+// invalidRecurrence returns the error for an invalid recurrence rule of a task
+func invalidRecurrence(reason string) error {
+	return &todoErr.InvalidValueError{
+		Resource: "task",
+		Field:    "recurrence",
+		Reason:   reason,
+	}
+}
+
+// parentID returns the id of the parent of a subtask, or zero for top level tasks
+func parentID(task *models.Task) int64 {
+	if task.Parent == nil {
+		return 0
+	}
+
+	return task.Parent.ID
+}
+
+// projectID returns the id of the project of a task, or zero for tasks in the inbox
+func projectID(task *models.Task) int64 {
+	if task.Project == nil {
+		return 0
+	}
+
+	return task.Project.ID
 }
@@ -9,9 +9,16 @@ import (
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/dheerajgopi/todo-api/collaborator"
+	collaboratorMock "github.com/dheerajgopi/todo-api/collaborator/mock"
+	todoErr "github.com/dheerajgopi/todo-api/common/error"
 	"github.com/dheerajgopi/todo-api/models"
+	projectMock "github.com/dheerajgopi/todo-api/project/mock"
+	tagMock "github.com/dheerajgopi/todo-api/tag/mock"
+	"github.com/dheerajgopi/todo-api/task"
 	taskMock "github.com/dheerajgopi/todo-api/task/mock"
 	"github.com/dheerajgopi/todo-api/task/service"
+	userMock "github.com/dheerajgopi/todo-api/user/mock"
 )
 
 func TestCreate(t *testing.T) {
@@ -23,7 +30,9 @@ func TestCreate(t *testing.T) {
 	defer mockCtrl.Finish()
 
 	mockRepo := taskMock.NewRepository(mockCtrl)
-	taskService := service.New(mockRepo)
+	mockCollaboratorRepo := collaboratorMock.NewRepository(mockCtrl)
+	mockUserRepo := userMock.NewRepository(mockCtrl)
+	taskService := service.New(mockRepo, tagMock.NewRepository(mockCtrl), projectMock.NewRepository(mockCtrl), mockCollaboratorRepo, mockUserRepo)
 
 	newTask := &models.Task{
 		Title:       "testTitle",
@@ -36,6 +45,12 @@ func TestCreate(t *testing.T) {
 		UpdatedAt:  now,
 	}
 
+	mockRepo.
+		EXPECT().
+		GetMaxPosition(ctx, int64(1)).
+		Return(int64(3*task.PositionGap), nil).
+		Times(1)
+
 	mockRepo.
 		EXPECT().
 		Create(ctx, newTask).
@@ -45,6 +60,7 @@ func TestCreate(t *testing.T) {
 	err := taskService.Create(ctx, newTask)
 
 	assert.NoError(err)
+	assert.Equal(4*task.PositionGap, newTask.Position)
 	assert.Equal(newTask, newTask)
 	assert.Equal("testTitle", newTask.Title)
 	assert.Equal("test description", newTask.Description)
@@ -61,9 +77,21 @@ func TestCreateWithError(t *testing.T) {
 	defer mockCtrl.Finish()
 
 	mockRepo := taskMock.NewRepository(mockCtrl)
-	taskService := service.New(mockRepo)
+	mockCollaboratorRepo := collaboratorMock.NewRepository(mockCtrl)
+	mockUserRepo := userMock.NewRepository(mockCtrl)
+	taskService := service.New(mockRepo, tagMock.NewRepository(mockCtrl), projectMock.NewRepository(mockCtrl), mockCollaboratorRepo, mockUserRepo)
+
+	newTask := &models.Task{
+		CreatedBy: &models.User{
+			ID: 1,
+		},
+	}
 
-	newTask := &models.Task{}
+	mockRepo.
+		EXPECT().
+		GetMaxPosition(ctx, int64(1)).
+		Return(int64(0), nil).
+		Times(1)
 
 	mockRepo.
 		EXPECT().
@@ -76,6 +104,46 @@ func TestCreateWithError(t *testing.T) {
 	assert.Error(err)
 }
 
+func TestCreateWithProjectOwnedByOtherUser(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	mockRepo := taskMock.NewRepository(mockCtrl)
+	mockProjectRepo := projectMock.NewRepository(mockCtrl)
+	mockCollaboratorRepo := collaboratorMock.NewRepository(mockCtrl)
+	mockUserRepo := userMock.NewRepository(mockCtrl)
+	taskService := service.New(mockRepo, tagMock.NewRepository(mockCtrl), mockProjectRepo, mockCollaboratorRepo, mockUserRepo)
+
+	newTask := &models.Task{
+		CreatedBy: &models.User{
+			ID: 1,
+		},
+		Project: &models.Project{
+			ID: 5,
+		},
+	}
+
+	mockProjectRepo.
+		EXPECT().
+		GetByID(ctx, int64(5)).
+		Return(&models.Project{ID: 5, CreatedBy: &models.User{ID: 2}}, nil).
+		Times(1)
+
+	mockCollaboratorRepo.
+		EXPECT().
+		GetRole(ctx, collaborator.ResourceProject, int64(5), int64(1)).
+		Return("", nil).
+		Times(1)
+
+	err := taskService.Create(ctx, newTask)
+
+	assert.IsType(&todoErr.ResourceNotFoundError{}, err)
+	assert.Equal("project", err.(*todoErr.ResourceNotFoundError).Resource)
+}
+
 func TestList(t *testing.T) {
 	now := time.Now()
 	userID := int64(1)
@@ -86,7 +154,9 @@ func TestList(t *testing.T) {
 	defer mockCtrl.Finish()
 
 	mockRepo := taskMock.NewRepository(mockCtrl)
-	taskService := service.New(mockRepo)
+	mockCollaboratorRepo := collaboratorMock.NewRepository(mockCtrl)
+	mockUserRepo := userMock.NewRepository(mockCtrl)
+	taskService := service.New(mockRepo, tagMock.NewRepository(mockCtrl), projectMock.NewRepository(mockCtrl), mockCollaboratorRepo, mockUserRepo)
 
 	tasks := make([]*models.Task, 0)
 	tasks = append(tasks, &models.Task{
@@ -102,14 +172,16 @@ func TestList(t *testing.T) {
 
 	mockRepo.
 		EXPECT().
-		GetAllByUserID(ctx, userID).
+		GetAllByUserID(ctx, userID, &task.ListFilter{Sort: task.SortByCreatedAt, Limit: task.DefaultListLimit + 1}).
 		Return(tasks, nil).
 		Times(1)
 
-	expectedResult, err := taskService.List(ctx, userID)
+	expectedResult, err := taskService.List(ctx, userID, &task.ListFilter{})
 
 	assert.NoError(err)
 	assert.NotNil(expectedResult)
+	assert.Equal(false, expectedResult.HasMore)
+	assert.Equal("", expectedResult.NextCursor)
 	assert.Equal(1, len(tasks))
 	assert.Equal("testTitle", tasks[0].Title)
 	assert.Equal("test description", tasks[0].Description)
@@ -118,6 +190,109 @@ func TestList(t *testing.T) {
 	assert.Equal(now, tasks[0].UpdatedAt)
 }
 
+func TestListByProject(t *testing.T) {
+	userID := int64(1)
+	projectID := int64(5)
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	mockRepo := taskMock.NewRepository(mockCtrl)
+	mockProjectRepo := projectMock.NewRepository(mockCtrl)
+	mockCollaboratorRepo := collaboratorMock.NewRepository(mockCtrl)
+	mockUserRepo := userMock.NewRepository(mockCtrl)
+	taskService := service.New(mockRepo, tagMock.NewRepository(mockCtrl), mockProjectRepo, mockCollaboratorRepo, mockUserRepo)
+
+	mockProjectRepo.
+		EXPECT().
+		GetByID(ctx, projectID).
+		Return(&models.Project{ID: projectID, CreatedBy: &models.User{ID: userID}}, nil).
+		Times(1)
+
+	mockRepo.
+		EXPECT().
+		GetAllByUserID(ctx, userID, &task.ListFilter{
+			ProjectID: &projectID,
+			Sort:      task.SortByCreatedAt,
+			Limit:     task.DefaultListLimit + 1,
+		}).
+		Return(make([]*models.Task, 0), nil).
+		Times(1)
+
+	result, err := taskService.List(ctx, userID, &task.ListFilter{ProjectID: &projectID})
+
+	assert.NoError(err)
+	assert.Equal(0, len(result.Tasks))
+}
+
+func TestListByMissingProject(t *testing.T) {
+	userID := int64(1)
+	projectID := int64(5)
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	mockRepo := taskMock.NewRepository(mockCtrl)
+	mockProjectRepo := projectMock.NewRepository(mockCtrl)
+	mockCollaboratorRepo := collaboratorMock.NewRepository(mockCtrl)
+	mockUserRepo := userMock.NewRepository(mockCtrl)
+	taskService := service.New(mockRepo, tagMock.NewRepository(mockCtrl), mockProjectRepo, mockCollaboratorRepo, mockUserRepo)
+
+	mockProjectRepo.
+		EXPECT().
+		GetByID(ctx, projectID).
+		Return(nil, nil).
+		Times(1)
+
+	result, err := taskService.List(ctx, userID, &task.ListFilter{ProjectID: &projectID})
+
+	assert.Nil(result)
+	assert.IsType(&todoErr.ResourceNotFoundError{}, err)
+}
+
+func TestListWithMorePages(t *testing.T) {
+	userID := int64(1)
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	mockRepo := taskMock.NewRepository(mockCtrl)
+	mockCollaboratorRepo := collaboratorMock.NewRepository(mockCtrl)
+	mockUserRepo := userMock.NewRepository(mockCtrl)
+	taskService := service.New(mockRepo, tagMock.NewRepository(mockCtrl), projectMock.NewRepository(mockCtrl), mockCollaboratorRepo, mockUserRepo)
+
+	tasks := []*models.Task{
+		{ID: 1, Title: "a", CreatedBy: &models.User{ID: userID}},
+		{ID: 2, Title: "b", CreatedBy: &models.User{ID: userID}},
+		{ID: 3, Title: "c", CreatedBy: &models.User{ID: userID}},
+	}
+
+	mockRepo.
+		EXPECT().
+		GetAllByUserID(ctx, userID, &task.ListFilter{Sort: task.SortByTitle, Limit: 3}).
+		Return(tasks, nil).
+		Times(1)
+
+	page, err := taskService.List(ctx, userID, &task.ListFilter{Sort: task.SortByTitle, Limit: 2})
+
+	assert.NoError(err)
+	assert.Equal(2, len(page.Tasks))
+	assert.Equal(true, page.HasMore)
+
+	cursor, err := task.DecodeCursor(page.NextCursor)
+
+	assert.NoError(err)
+	assert.Equal(task.SortByTitle, cursor.Sort)
+	assert.Equal("b", cursor.Value)
+	assert.Equal(int64(2), cursor.ID)
+}
+
 func TestListWithError(t *testing.T) {
 	ctx := context.TODO()
 	userID := int64(1)
@@ -127,16 +302,1623 @@ func TestListWithError(t *testing.T) {
 	defer mockCtrl.Finish()
 
 	mockRepo := taskMock.NewRepository(mockCtrl)
-	taskService := service.New(mockRepo)
+	mockCollaboratorRepo := collaboratorMock.NewRepository(mockCtrl)
+	mockUserRepo := userMock.NewRepository(mockCtrl)
+	taskService := service.New(mockRepo, tagMock.NewRepository(mockCtrl), projectMock.NewRepository(mockCtrl), mockCollaboratorRepo, mockUserRepo)
 
 	mockRepo.
 		EXPECT().
-		GetAllByUserID(ctx, userID).
+		GetAllByUserID(ctx, userID, gomock.Any()).
 		Return(nil, errors.New("error")).
 		Times(1)
 
-	tasks, err := taskService.List(ctx, userID)
+	tasks, err := taskService.List(ctx, userID, &task.ListFilter{})
 
 	assert.Error(err)
 	assert.Nil(tasks)
 }
+
+func TestGetByID(t *testing.T) {
+	ctx := context.TODO()
+	userID := int64(1)
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	mockRepo := taskMock.NewRepository(mockCtrl)
+	mockCollaboratorRepo := collaboratorMock.NewRepository(mockCtrl)
+	mockUserRepo := userMock.NewRepository(mockCtrl)
+	taskService := service.New(mockRepo, tagMock.NewRepository(mockCtrl), projectMock.NewRepository(mockCtrl), mockCollaboratorRepo, mockUserRepo)
+
+	existingTask := &models.Task{
+		ID:    1,
+		Title: "testTitle",
+		CreatedBy: &models.User{
+			ID: userID,
+		},
+	}
+
+	mockRepo.
+		EXPECT().
+		GetByID(ctx, existingTask.ID).
+		Return(existingTask, nil).
+		Times(1)
+
+	result, err := taskService.GetByID(ctx, existingTask.ID, userID)
+
+	assert.NoError(err)
+	assert.Equal(existingTask, result)
+}
+
+func TestGetByIDForMissingTask(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	mockRepo := taskMock.NewRepository(mockCtrl)
+	mockCollaboratorRepo := collaboratorMock.NewRepository(mockCtrl)
+	mockUserRepo := userMock.NewRepository(mockCtrl)
+	taskService := service.New(mockRepo, tagMock.NewRepository(mockCtrl), projectMock.NewRepository(mockCtrl), mockCollaboratorRepo, mockUserRepo)
+
+	mockRepo.
+		EXPECT().
+		GetByID(ctx, int64(1)).
+		Return(nil, nil).
+		Times(1)
+
+	result, err := taskService.GetByID(ctx, 1, 1)
+
+	assert.Nil(result)
+	assert.IsType(&todoErr.ResourceNotFoundError{}, err)
+}
+
+func TestGetByIDForTaskOwnedByOtherUser(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	mockRepo := taskMock.NewRepository(mockCtrl)
+	mockCollaboratorRepo := collaboratorMock.NewRepository(mockCtrl)
+	mockUserRepo := userMock.NewRepository(mockCtrl)
+	taskService := service.New(mockRepo, tagMock.NewRepository(mockCtrl), projectMock.NewRepository(mockCtrl), mockCollaboratorRepo, mockUserRepo)
+
+	existingTask := &models.Task{
+		ID: 1,
+		CreatedBy: &models.User{
+			ID: 2,
+		},
+	}
+
+	mockRepo.
+		EXPECT().
+		GetByID(ctx, existingTask.ID).
+		Return(existingTask, nil).
+		Times(1)
+
+	mockCollaboratorRepo.
+		EXPECT().
+		GetRole(ctx, collaborator.ResourceTask, int64(1), int64(1)).
+		Return("", nil).
+		Times(1)
+
+	result, err := taskService.GetByID(ctx, existingTask.ID, 1)
+
+	assert.Nil(result)
+	assert.IsType(&todoErr.ResourceNotFoundError{}, err)
+}
+
+func TestUpdate(t *testing.T) {
+	createdAt := time.Now().Add(-time.Hour)
+	userID := int64(1)
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	mockRepo := taskMock.NewRepository(mockCtrl)
+	mockCollaboratorRepo := collaboratorMock.NewRepository(mockCtrl)
+	mockUserRepo := userMock.NewRepository(mockCtrl)
+	taskService := service.New(mockRepo, tagMock.NewRepository(mockCtrl), projectMock.NewRepository(mockCtrl), mockCollaboratorRepo, mockUserRepo)
+	runAtomic(mockRepo)
+
+	existingTask := &models.Task{
+		ID:    1,
+		Title: "old title",
+		CreatedBy: &models.User{
+			ID: userID,
+		},
+		CreatedAt: createdAt,
+	}
+
+	updatedTask := &models.Task{
+		ID:         1,
+		Title:      "new title",
+		IsComplete: true,
+		UpdatedAt:  time.Now(),
+	}
+
+	mockRepo.
+		EXPECT().
+		GetByID(ctx, existingTask.ID).
+		Return(existingTask, nil).
+		Times(1)
+
+	mockRepo.
+		EXPECT().
+		Update(ctx, updatedTask).
+		Return(nil).
+		Times(1)
+
+	err := taskService.Update(ctx, updatedTask, userID)
+
+	assert.NoError(err)
+	assert.Equal(userID, updatedTask.CreatedBy.ID)
+	assert.Equal(createdAt, updatedTask.CreatedAt)
+	assert.Equal("new title", updatedTask.Title)
+}
+
+func TestUpdateWithTags(t *testing.T) {
+	userID := int64(1)
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	mockRepo := taskMock.NewRepository(mockCtrl)
+	mockTagRepo := tagMock.NewRepository(mockCtrl)
+	mockCollaboratorRepo := collaboratorMock.NewRepository(mockCtrl)
+	mockUserRepo := userMock.NewRepository(mockCtrl)
+	taskService := service.New(mockRepo, mockTagRepo, projectMock.NewRepository(mockCtrl), mockCollaboratorRepo, mockUserRepo)
+	runAtomic(mockRepo)
+
+	existingTask := &models.Task{
+		ID: 1,
+		CreatedBy: &models.User{
+			ID: userID,
+		},
+	}
+
+	updatedTask := &models.Task{
+		ID:    1,
+		Title: "new title",
+		Tags:  []*models.Tag{{ID: 3}, {ID: 4}, {ID: 3}},
+	}
+
+	ownedTags := []*models.Tag{
+		{ID: 3, Name: "home", CreatedBy: &models.User{ID: userID}},
+		{ID: 4, Name: "work", CreatedBy: &models.User{ID: userID}},
+	}
+
+	mockRepo.
+		EXPECT().
+		GetByID(ctx, existingTask.ID).
+		Return(existingTask, nil).
+		Times(1)
+
+	mockTagRepo.
+		EXPECT().
+		GetByIDs(ctx, []int64{3, 4}).
+		Return(ownedTags, nil).
+		Times(1)
+
+	mockRepo.
+		EXPECT().
+		Update(ctx, updatedTask).
+		Return(nil).
+		Times(1)
+
+	err := taskService.Update(ctx, updatedTask, userID)
+
+	assert.NoError(err)
+	assert.Equal(ownedTags, updatedTask.Tags)
+}
+
+func TestUpdateWithTagOwnedByOtherUser(t *testing.T) {
+	userID := int64(1)
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	mockRepo := taskMock.NewRepository(mockCtrl)
+	mockTagRepo := tagMock.NewRepository(mockCtrl)
+	mockCollaboratorRepo := collaboratorMock.NewRepository(mockCtrl)
+	mockUserRepo := userMock.NewRepository(mockCtrl)
+	taskService := service.New(mockRepo, mockTagRepo, projectMock.NewRepository(mockCtrl), mockCollaboratorRepo, mockUserRepo)
+
+	existingTask := &models.Task{
+		ID: 1,
+		CreatedBy: &models.User{
+			ID: userID,
+		},
+	}
+
+	updatedTask := &models.Task{
+		ID:    1,
+		Title: "new title",
+		Tags:  []*models.Tag{{ID: 3}},
+	}
+
+	mockRepo.
+		EXPECT().
+		GetByID(ctx, existingTask.ID).
+		Return(existingTask, nil).
+		Times(1)
+
+	mockTagRepo.
+		EXPECT().
+		GetByIDs(ctx, []int64{3}).
+		Return([]*models.Tag{{ID: 3, CreatedBy: &models.User{ID: 2}}}, nil).
+		Times(1)
+
+	err := taskService.Update(ctx, updatedTask, userID)
+
+	assert.IsType(&todoErr.ResourceNotFoundError{}, err)
+	assert.Equal("tag", err.(*todoErr.ResourceNotFoundError).Resource)
+}
+
+func TestUpdateForTaskOwnedByOtherUser(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	mockRepo := taskMock.NewRepository(mockCtrl)
+	mockCollaboratorRepo := collaboratorMock.NewRepository(mockCtrl)
+	mockUserRepo := userMock.NewRepository(mockCtrl)
+	taskService := service.New(mockRepo, tagMock.NewRepository(mockCtrl), projectMock.NewRepository(mockCtrl), mockCollaboratorRepo, mockUserRepo)
+
+	existingTask := &models.Task{
+		ID: 1,
+		CreatedBy: &models.User{
+			ID: 2,
+		},
+	}
+
+	mockRepo.
+		EXPECT().
+		GetByID(ctx, existingTask.ID).
+		Return(existingTask, nil).
+		Times(1)
+
+	mockCollaboratorRepo.
+		EXPECT().
+		GetRole(ctx, collaborator.ResourceTask, int64(1), int64(1)).
+		Return("", nil).
+		Times(1)
+
+	err := taskService.Update(ctx, &models.Task{ID: 1}, 1)
+
+	assert.IsType(&todoErr.ResourceNotFoundError{}, err)
+}
+
+func TestDelete(t *testing.T) {
+	userID := int64(1)
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	mockRepo := taskMock.NewRepository(mockCtrl)
+	mockCollaboratorRepo := collaboratorMock.NewRepository(mockCtrl)
+	mockUserRepo := userMock.NewRepository(mockCtrl)
+	taskService := service.New(mockRepo, tagMock.NewRepository(mockCtrl), projectMock.NewRepository(mockCtrl), mockCollaboratorRepo, mockUserRepo)
+
+	existingTask := &models.Task{
+		ID: 1,
+		CreatedBy: &models.User{
+			ID: userID,
+		},
+	}
+
+	mockRepo.
+		EXPECT().
+		GetByID(ctx, existingTask.ID).
+		Return(existingTask, nil).
+		Times(1)
+
+	mockRepo.
+		EXPECT().
+		Delete(ctx, existingTask.ID).
+		Return(nil).
+		Times(1)
+
+	err := taskService.Delete(ctx, existingTask.ID, userID)
+
+	assert.NoError(err)
+}
+
+func TestDeleteForMissingTask(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	mockRepo := taskMock.NewRepository(mockCtrl)
+	mockCollaboratorRepo := collaboratorMock.NewRepository(mockCtrl)
+	mockUserRepo := userMock.NewRepository(mockCtrl)
+	taskService := service.New(mockRepo, tagMock.NewRepository(mockCtrl), projectMock.NewRepository(mockCtrl), mockCollaboratorRepo, mockUserRepo)
+
+	mockRepo.
+		EXPECT().
+		GetByID(ctx, int64(1)).
+		Return(nil, nil).
+		Times(1)
+
+	err := taskService.Delete(ctx, 1, 1)
+
+	assert.IsType(&todoErr.ResourceNotFoundError{}, err)
+}
+
+func TestMoveBeforeAnchor(t *testing.T) {
+	userID := int64(1)
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	mockRepo := taskMock.NewRepository(mockCtrl)
+	mockCollaboratorRepo := collaboratorMock.NewRepository(mockCtrl)
+	mockUserRepo := userMock.NewRepository(mockCtrl)
+	taskService := service.New(mockRepo, tagMock.NewRepository(mockCtrl), projectMock.NewRepository(mockCtrl), mockCollaboratorRepo, mockUserRepo)
+
+	owner := &models.User{ID: userID}
+	movedTask := &models.Task{ID: 1, Position: 5 * task.PositionGap, CreatedBy: owner}
+	anchorTask := &models.Task{ID: 2, Position: 2 * task.PositionGap, CreatedBy: owner}
+	neighbour := &models.Task{ID: 3, Position: task.PositionGap, CreatedBy: owner}
+
+	mockRepo.EXPECT().GetByID(ctx, movedTask.ID).Return(movedTask, nil).Times(1)
+	mockRepo.EXPECT().GetByID(ctx, anchorTask.ID).Return(anchorTask, nil).Times(1)
+
+	mockRepo.
+		EXPECT().
+		GetAdjacent(ctx, userID, anchorTask.Position, movedTask.ID, true).
+		Return(neighbour, nil).
+		Times(1)
+
+	expectedPosition := task.PositionGap + task.PositionGap/2
+
+	mockRepo.
+		EXPECT().
+		UpdatePosition(ctx, movedTask.ID, expectedPosition, gomock.Any()).
+		Return(nil).
+		Times(1)
+
+	result, err := taskService.Move(ctx, movedTask.ID, anchorTask.ID, true, userID)
+
+	assert.NoError(err)
+	assert.Equal(expectedPosition, result.Position)
+}
+
+func TestMoveAfterLastTask(t *testing.T) {
+	userID := int64(1)
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	mockRepo := taskMock.NewRepository(mockCtrl)
+	mockCollaboratorRepo := collaboratorMock.NewRepository(mockCtrl)
+	mockUserRepo := userMock.NewRepository(mockCtrl)
+	taskService := service.New(mockRepo, tagMock.NewRepository(mockCtrl), projectMock.NewRepository(mockCtrl), mockCollaboratorRepo, mockUserRepo)
+
+	owner := &models.User{ID: userID}
+	movedTask := &models.Task{ID: 1, Position: task.PositionGap, CreatedBy: owner}
+	anchorTask := &models.Task{ID: 2, Position: 2 * task.PositionGap, CreatedBy: owner}
+
+	mockRepo.EXPECT().GetByID(ctx, movedTask.ID).Return(movedTask, nil).Times(1)
+	mockRepo.EXPECT().GetByID(ctx, anchorTask.ID).Return(anchorTask, nil).Times(1)
+
+	mockRepo.
+		EXPECT().
+		GetAdjacent(ctx, userID, anchorTask.Position, movedTask.ID, false).
+		Return(nil, nil).
+		Times(1)
+
+	mockRepo.
+		EXPECT().
+		UpdatePosition(ctx, movedTask.ID, 3*task.PositionGap, gomock.Any()).
+		Return(nil).
+		Times(1)
+
+	result, err := taskService.Move(ctx, movedTask.ID, anchorTask.ID, false, userID)
+
+	assert.NoError(err)
+	assert.Equal(3*task.PositionGap, result.Position)
+}
+
+func TestMoveWithoutFreePosition(t *testing.T) {
+	userID := int64(1)
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	mockRepo := taskMock.NewRepository(mockCtrl)
+	mockCollaboratorRepo := collaboratorMock.NewRepository(mockCtrl)
+	mockUserRepo := userMock.NewRepository(mockCtrl)
+	taskService := service.New(mockRepo, tagMock.NewRepository(mockCtrl), projectMock.NewRepository(mockCtrl), mockCollaboratorRepo, mockUserRepo)
+
+	owner := &models.User{ID: userID}
+	movedTask := &models.Task{ID: 1, Position: 10, CreatedBy: owner}
+	anchorTask := &models.Task{ID: 2, Position: 5, CreatedBy: owner}
+	neighbour := &models.Task{ID: 3, Position: 4, CreatedBy: owner}
+	rebalancedAnchor := &models.Task{ID: 2, Position: 2 * task.PositionGap, CreatedBy: owner}
+	rebalancedNeighbour := &models.Task{ID: 3, Position: task.PositionGap, CreatedBy: owner}
+
+	gomock.InOrder(
+		mockRepo.EXPECT().GetByID(ctx, movedTask.ID).Return(movedTask, nil),
+		mockRepo.EXPECT().GetByID(ctx, anchorTask.ID).Return(anchorTask, nil),
+		mockRepo.EXPECT().GetAdjacent(ctx, userID, anchorTask.Position, movedTask.ID, true).Return(neighbour, nil),
+		mockRepo.EXPECT().RebalancePositions(ctx, userID).Return(nil),
+		mockRepo.EXPECT().GetByID(ctx, anchorTask.ID).Return(rebalancedAnchor, nil),
+		mockRepo.EXPECT().GetAdjacent(ctx, userID, rebalancedAnchor.Position, movedTask.ID, true).Return(rebalancedNeighbour, nil),
+		mockRepo.EXPECT().UpdatePosition(ctx, movedTask.ID, task.PositionGap+task.PositionGap/2, gomock.Any()).Return(nil),
+	)
+
+	result, err := taskService.Move(ctx, movedTask.ID, anchorTask.ID, true, userID)
+
+	assert.NoError(err)
+	assert.Equal(task.PositionGap+task.PositionGap/2, result.Position)
+}
+
+func TestMoveWithAnchorOwnedByOtherUser(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	mockRepo := taskMock.NewRepository(mockCtrl)
+	mockCollaboratorRepo := collaboratorMock.NewRepository(mockCtrl)
+	mockUserRepo := userMock.NewRepository(mockCtrl)
+	taskService := service.New(mockRepo, tagMock.NewRepository(mockCtrl), projectMock.NewRepository(mockCtrl), mockCollaboratorRepo, mockUserRepo)
+
+	movedTask := &models.Task{ID: 1, CreatedBy: &models.User{ID: 1}}
+	anchorTask := &models.Task{ID: 2, CreatedBy: &models.User{ID: 2}}
+
+	mockRepo.EXPECT().GetByID(ctx, movedTask.ID).Return(movedTask, nil).Times(1)
+	mockRepo.EXPECT().GetByID(ctx, anchorTask.ID).Return(anchorTask, nil).Times(1)
+
+	mockCollaboratorRepo.EXPECT().GetRole(ctx, collaborator.ResourceTask, anchorTask.ID, int64(1)).Return("", nil).Times(1)
+
+	result, err := taskService.Move(ctx, movedTask.ID, anchorTask.ID, true, 1)
+
+	assert.Nil(result)
+	assert.IsType(&todoErr.ResourceNotFoundError{}, err)
+}
+
+func TestCreateSubtask(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	mockRepo := taskMock.NewRepository(mockCtrl)
+	mockCollaboratorRepo := collaboratorMock.NewRepository(mockCtrl)
+	mockUserRepo := userMock.NewRepository(mockCtrl)
+	taskService := service.New(mockRepo, tagMock.NewRepository(mockCtrl), projectMock.NewRepository(mockCtrl), mockCollaboratorRepo, mockUserRepo)
+
+	newTask := &models.Task{
+		Title:     "testTitle",
+		CreatedBy: &models.User{ID: 1},
+		Parent:    &models.Task{ID: 3},
+	}
+
+	parent := &models.Task{
+		ID:           3,
+		CreatedBy:    &models.User{ID: 1},
+		Project:      &models.Project{ID: 5},
+		IsComplete:   true,
+		AutoComplete: true,
+		Progress:     &models.Progress{Completed: 1, Total: 1},
+	}
+
+	reloadedParent := &models.Task{
+		ID:           3,
+		CreatedBy:    &models.User{ID: 1},
+		IsComplete:   true,
+		AutoComplete: true,
+		Progress:     &models.Progress{Completed: 1, Total: 2},
+	}
+
+	gomock.InOrder(
+		mockRepo.EXPECT().GetByID(ctx, int64(3)).Return(parent, nil),
+		mockRepo.EXPECT().GetMaxPosition(ctx, int64(1)).Return(int64(task.PositionGap), nil),
+		mockRepo.EXPECT().Create(ctx, newTask).Return(nil),
+		mockRepo.EXPECT().GetByID(ctx, int64(3)).Return(reloadedParent, nil),
+		mockRepo.EXPECT().UpdateCompletion(ctx, int64(3), false, gomock.Any()).Return(nil),
+	)
+
+	err := taskService.Create(ctx, newTask)
+
+	assert.NoError(err)
+	assert.Equal(int64(5), newTask.Project.ID)
+}
+
+func TestCreateNestedSubtask(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	mockRepo := taskMock.NewRepository(mockCtrl)
+	mockCollaboratorRepo := collaboratorMock.NewRepository(mockCtrl)
+	mockUserRepo := userMock.NewRepository(mockCtrl)
+	taskService := service.New(mockRepo, tagMock.NewRepository(mockCtrl), projectMock.NewRepository(mockCtrl), mockCollaboratorRepo, mockUserRepo)
+
+	newTask := &models.Task{
+		CreatedBy: &models.User{ID: 1},
+		Parent:    &models.Task{ID: 3},
+	}
+
+	mockRepo.
+		EXPECT().
+		GetByID(ctx, int64(3)).
+		Return(&models.Task{ID: 3, CreatedBy: &models.User{ID: 1}, Parent: &models.Task{ID: 2}}, nil).
+		Times(1)
+
+	err := taskService.Create(ctx, newTask)
+
+	assert.Equal(&todoErr.InvalidValueError{
+		Resource: "task",
+		Field:    "parentId",
+		Reason:   "Subtasks can not have subtasks",
+	}, err)
+}
+
+func TestListSubtasksForTaskOwnedByOtherUser(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	mockRepo := taskMock.NewRepository(mockCtrl)
+	mockCollaboratorRepo := collaboratorMock.NewRepository(mockCtrl)
+	mockUserRepo := userMock.NewRepository(mockCtrl)
+	taskService := service.New(mockRepo, tagMock.NewRepository(mockCtrl), projectMock.NewRepository(mockCtrl), mockCollaboratorRepo, mockUserRepo)
+
+	mockRepo.
+		EXPECT().
+		GetByID(ctx, int64(3)).
+		Return(&models.Task{ID: 3, CreatedBy: &models.User{ID: 2}}, nil).
+		Times(1)
+
+	mockCollaboratorRepo.
+		EXPECT().
+		GetRole(ctx, collaborator.ResourceTask, int64(3), int64(1)).
+		Return("", nil).
+		Times(1)
+
+	subtasks, err := taskService.ListSubtasks(ctx, int64(3), int64(1))
+
+	assert.Nil(subtasks)
+	assert.Equal(&todoErr.ResourceNotFoundError{Resource: "task"}, err)
+}
+
+func TestUpdateCompletesParent(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	mockRepo := taskMock.NewRepository(mockCtrl)
+	mockCollaboratorRepo := collaboratorMock.NewRepository(mockCtrl)
+	mockUserRepo := userMock.NewRepository(mockCtrl)
+	taskService := service.New(mockRepo, tagMock.NewRepository(mockCtrl), projectMock.NewRepository(mockCtrl), mockCollaboratorRepo, mockUserRepo)
+	runAtomic(mockRepo)
+
+	existingTask := &models.Task{
+		ID:        4,
+		CreatedBy: &models.User{ID: 1},
+		Parent:    &models.Task{ID: 3},
+		Project:   &models.Project{ID: 5},
+	}
+
+	updatedTask := &models.Task{
+		ID:         4,
+		Title:      "title",
+		IsComplete: true,
+	}
+
+	parent := &models.Task{
+		ID:           3,
+		CreatedBy:    &models.User{ID: 1},
+		AutoComplete: true,
+		Progress:     &models.Progress{Completed: 2, Total: 2},
+	}
+
+	gomock.InOrder(
+		mockRepo.EXPECT().GetByID(ctx, int64(4)).Return(existingTask, nil),
+		mockRepo.EXPECT().Update(ctx, updatedTask).Return(nil),
+		mockRepo.EXPECT().GetByID(ctx, int64(3)).Return(parent, nil),
+		mockRepo.EXPECT().UpdateCompletion(ctx, int64(3), true, gomock.Any()).Return(nil),
+	)
+
+	err := taskService.Update(ctx, updatedTask, int64(1))
+
+	assert.NoError(err)
+	assert.Equal(int64(5), updatedTask.Project.ID)
+	assert.Equal(int64(3), updatedTask.Parent.ID)
+}
+
+func TestUpdateKeepsParentWithoutAutoComplete(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	mockRepo := taskMock.NewRepository(mockCtrl)
+	mockCollaboratorRepo := collaboratorMock.NewRepository(mockCtrl)
+	mockUserRepo := userMock.NewRepository(mockCtrl)
+	taskService := service.New(mockRepo, tagMock.NewRepository(mockCtrl), projectMock.NewRepository(mockCtrl), mockCollaboratorRepo, mockUserRepo)
+	runAtomic(mockRepo)
+
+	existingTask := &models.Task{
+		ID:        4,
+		CreatedBy: &models.User{ID: 1},
+		Parent:    &models.Task{ID: 3},
+	}
+
+	updatedTask := &models.Task{
+		ID:         4,
+		IsComplete: true,
+	}
+
+	parent := &models.Task{
+		ID:        3,
+		CreatedBy: &models.User{ID: 1},
+		Progress:  &models.Progress{Completed: 2, Total: 2},
+	}
+
+	gomock.InOrder(
+		mockRepo.EXPECT().GetByID(ctx, int64(4)).Return(existingTask, nil),
+		mockRepo.EXPECT().Update(ctx, updatedTask).Return(nil),
+		mockRepo.EXPECT().GetByID(ctx, int64(3)).Return(parent, nil),
+	)
+
+	err := taskService.Update(ctx, updatedTask, int64(1))
+
+	assert.NoError(err)
+}
+
+func TestDeleteSubtaskCompletesParent(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	mockRepo := taskMock.NewRepository(mockCtrl)
+	mockCollaboratorRepo := collaboratorMock.NewRepository(mockCtrl)
+	mockUserRepo := userMock.NewRepository(mockCtrl)
+	taskService := service.New(mockRepo, tagMock.NewRepository(mockCtrl), projectMock.NewRepository(mockCtrl), mockCollaboratorRepo, mockUserRepo)
+
+	existingTask := &models.Task{
+		ID:        4,
+		CreatedBy: &models.User{ID: 1},
+		Parent:    &models.Task{ID: 3},
+	}
+
+	parent := &models.Task{
+		ID:           3,
+		CreatedBy:    &models.User{ID: 1},
+		AutoComplete: true,
+		Progress:     &models.Progress{Completed: 1, Total: 1},
+	}
+
+	gomock.InOrder(
+		mockRepo.EXPECT().GetByID(ctx, int64(4)).Return(existingTask, nil),
+		mockRepo.EXPECT().Delete(ctx, int64(4)).Return(nil),
+		mockRepo.EXPECT().GetByID(ctx, int64(3)).Return(parent, nil),
+		mockRepo.EXPECT().UpdateCompletion(ctx, int64(3), true, gomock.Any()).Return(nil),
+	)
+
+	err := taskService.Delete(ctx, int64(4), int64(1))
+
+	assert.NoError(err)
+}
+
+func TestMoveSubtaskNextToOtherParent(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	mockRepo := taskMock.NewRepository(mockCtrl)
+	mockCollaboratorRepo := collaboratorMock.NewRepository(mockCtrl)
+	mockUserRepo := userMock.NewRepository(mockCtrl)
+	taskService := service.New(mockRepo, tagMock.NewRepository(mockCtrl), projectMock.NewRepository(mockCtrl), mockCollaboratorRepo, mockUserRepo)
+
+	mockRepo.
+		EXPECT().
+		GetByID(ctx, int64(4)).
+		Return(&models.Task{ID: 4, CreatedBy: &models.User{ID: 1}, Parent: &models.Task{ID: 3}}, nil).
+		Times(1)
+
+	mockRepo.
+		EXPECT().
+		GetByID(ctx, int64(6)).
+		Return(&models.Task{ID: 6, CreatedBy: &models.User{ID: 1}, Parent: &models.Task{ID: 5}}, nil).
+		Times(1)
+
+	movedTask, err := taskService.Move(ctx, int64(4), int64(6), true, int64(1))
+
+	assert.Nil(movedTask)
+	assert.Equal(&todoErr.InvalidValueError{
+		Resource: "task",
+		Field:    "beforeId",
+		Reason:   "Task can only be moved next to its siblings",
+	}, err)
+}
+
+func TestCreateRecurringTask(t *testing.T) {
+	dueAt := time.Date(2019, 5, 8, 9, 0, 0, 0, time.UTC)
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	mockRepo := taskMock.NewRepository(mockCtrl)
+	mockCollaboratorRepo := collaboratorMock.NewRepository(mockCtrl)
+	mockUserRepo := userMock.NewRepository(mockCtrl)
+	taskService := service.New(mockRepo, tagMock.NewRepository(mockCtrl), projectMock.NewRepository(mockCtrl), mockCollaboratorRepo, mockUserRepo)
+
+	newTask := &models.Task{
+		Title:      "standup",
+		CreatedBy:  &models.User{ID: 1},
+		DueAt:      &dueAt,
+		Recurrence: "RRULE:freq=weekly;byday=MO,WE",
+	}
+
+	mockRepo.EXPECT().GetMaxPosition(ctx, int64(1)).Return(int64(0), nil).Times(1)
+	mockRepo.EXPECT().Create(ctx, newTask).Return(nil).Times(1)
+
+	err := taskService.Create(ctx, newTask)
+
+	assert.NoError(err)
+	assert.Equal("FREQ=WEEKLY;BYDAY=MO,WE", newTask.Recurrence)
+	assert.Equal(dueAt, *newTask.RecurrenceStart)
+}
+
+func TestCreateRecurringTaskWithoutDueDate(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	mockRepo := taskMock.NewRepository(mockCtrl)
+	mockCollaboratorRepo := collaboratorMock.NewRepository(mockCtrl)
+	mockUserRepo := userMock.NewRepository(mockCtrl)
+	taskService := service.New(mockRepo, tagMock.NewRepository(mockCtrl), projectMock.NewRepository(mockCtrl), mockCollaboratorRepo, mockUserRepo)
+
+	newTask := &models.Task{
+		CreatedBy:  &models.User{ID: 1},
+		Recurrence: "FREQ=DAILY",
+	}
+
+	err := taskService.Create(ctx, newTask)
+
+	assert.Equal(&todoErr.InvalidValueError{
+		Resource: "task",
+		Field:    "recurrence",
+		Reason:   "Due date is required for recurring tasks",
+	}, err)
+}
+
+func TestUpdateKeepsRecurrenceStart(t *testing.T) {
+	start := time.Date(2019, 5, 6, 9, 0, 0, 0, time.UTC)
+	dueAt := time.Date(2019, 5, 10, 9, 0, 0, 0, time.UTC)
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	mockRepo := taskMock.NewRepository(mockCtrl)
+	mockCollaboratorRepo := collaboratorMock.NewRepository(mockCtrl)
+	mockUserRepo := userMock.NewRepository(mockCtrl)
+	taskService := service.New(mockRepo, tagMock.NewRepository(mockCtrl), projectMock.NewRepository(mockCtrl), mockCollaboratorRepo, mockUserRepo)
+	runAtomic(mockRepo)
+
+	existingTask := &models.Task{
+		ID:              1,
+		CreatedBy:       &models.User{ID: 1},
+		DueAt:           &dueAt,
+		Recurrence:      "FREQ=WEEKLY;INTERVAL=2",
+		RecurrenceStart: &start,
+	}
+
+	postponedDueAt := dueAt.AddDate(0, 0, 1)
+	postponedTask := &models.Task{
+		ID:         1,
+		DueAt:      &postponedDueAt,
+		Recurrence: "FREQ=WEEKLY;INTERVAL=2",
+	}
+
+	mockRepo.EXPECT().GetByID(ctx, int64(1)).Return(existingTask, nil).Times(2)
+	mockRepo.EXPECT().Update(ctx, gomock.Any()).Return(nil).Times(2)
+
+	err := taskService.Update(ctx, postponedTask, int64(1))
+
+	assert.NoError(err)
+	assert.Equal(start, *postponedTask.RecurrenceStart)
+
+	changedTask := &models.Task{
+		ID:         1,
+		DueAt:      &postponedDueAt,
+		Recurrence: "FREQ=WEEKLY",
+	}
+
+	err = taskService.Update(ctx, changedTask, int64(1))
+
+	assert.NoError(err)
+	assert.Equal(postponedDueAt, *changedTask.RecurrenceStart)
+}
+
+func TestUpdateCompletingRecurringTask(t *testing.T) {
+	location, _ := time.LoadLocation("America/New_York")
+
+	// Daily standup at 9 AM, which is due on the day before DST starts
+	dueAt := time.Date(2019, 3, 9, 9, 0, 0, 0, location).UTC()
+	remindAt := dueAt.Add(-15 * time.Minute)
+	subtaskDueAt := dueAt.Add(-time.Hour)
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	mockRepo := taskMock.NewRepository(mockCtrl)
+	mockProjectRepo := projectMock.NewRepository(mockCtrl)
+	mockCollaboratorRepo := collaboratorMock.NewRepository(mockCtrl)
+	mockUserRepo := userMock.NewRepository(mockCtrl)
+	taskService := service.New(mockRepo, tagMock.NewRepository(mockCtrl), mockProjectRepo, mockCollaboratorRepo, mockUserRepo)
+	runAtomic(mockRepo)
+
+	existingTask := &models.Task{
+		ID:              1,
+		CreatedBy:       &models.User{ID: 1},
+		Project:         &models.Project{ID: 5},
+		DueAt:           &dueAt,
+		DueTimezone:     "America/New_York",
+		RemindAt:        &remindAt,
+		Recurrence:      "FREQ=DAILY",
+		RecurrenceStart: &dueAt,
+	}
+
+	completedTask := &models.Task{
+		ID:          1,
+		Title:       "standup",
+		Project:     &models.Project{ID: 5},
+		IsComplete:  true,
+		DueAt:       &dueAt,
+		DueTimezone: "America/New_York",
+		RemindAt:    &remindAt,
+		Recurrence:  "FREQ=DAILY",
+	}
+
+	subtask := &models.Task{
+		ID:          2,
+		Title:       "prepare notes",
+		Parent:      &models.Task{ID: 1},
+		IsComplete:  true,
+		DueAt:       &subtaskDueAt,
+		DueTimezone: "America/New_York",
+	}
+
+	var next, subtaskCopy *models.Task
+
+	gomock.InOrder(
+		mockRepo.EXPECT().GetByID(ctx, int64(1)).Return(existingTask, nil),
+		mockRepo.EXPECT().Update(ctx, completedTask).Return(nil),
+		mockRepo.EXPECT().GetMaxPosition(ctx, int64(1)).Return(2*task.PositionGap, nil),
+		mockRepo.EXPECT().Create(ctx, gomock.Any()).Do(func(_ context.Context, created *models.Task) {
+			created.ID = 3
+			next = created
+		}).Return(nil),
+		mockRepo.EXPECT().GetSubtasks(ctx, int64(1)).Return([]*models.Task{subtask}, nil),
+		mockRepo.EXPECT().Create(ctx, gomock.Any()).Do(func(_ context.Context, created *models.Task) {
+			subtaskCopy = created
+		}).Return(nil),
+	)
+
+	err := taskService.Update(ctx, completedTask, int64(1))
+
+	assert.NoError(err)
+	assert.Equal("", completedTask.Recurrence)
+	assert.Nil(completedTask.RecurrenceStart)
+
+	assert.Equal("standup", next.Title)
+	assert.Equal(int64(5), next.Project.ID)
+	assert.False(next.IsComplete)
+	assert.Equal(3*task.PositionGap, next.Position)
+	assert.Equal("FREQ=DAILY", next.Recurrence)
+	assert.True(dueAt.Equal(*next.RecurrenceStart))
+	assert.True(time.Date(2019, 3, 10, 9, 0, 0, 0, location).Equal(*next.DueAt))
+	assert.Equal(23*time.Hour, next.DueAt.Sub(dueAt))
+	assert.True(time.Date(2019, 3, 10, 8, 45, 0, 0, location).Equal(*next.RemindAt))
+
+	assert.Equal("prepare notes", subtaskCopy.Title)
+	assert.Equal(int64(3), subtaskCopy.Parent.ID)
+	assert.Equal(int64(5), subtaskCopy.Project.ID)
+	assert.False(subtaskCopy.IsComplete)
+	assert.Equal(4*task.PositionGap, subtaskCopy.Position)
+	assert.True(time.Date(2019, 3, 10, 8, 0, 0, 0, location).Equal(*subtaskCopy.DueAt))
+}
+
+func TestUpdateCompletingRecurringTaskWithOccurrenceError(t *testing.T) {
+	dueAt := time.Date(2019, 5, 10, 9, 0, 0, 0, time.UTC)
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	mockRepo := taskMock.NewRepository(mockCtrl)
+	taskService := service.New(mockRepo, tagMock.NewRepository(mockCtrl), projectMock.NewRepository(mockCtrl), collaboratorMock.NewRepository(mockCtrl), userMock.NewRepository(mockCtrl))
+
+	existingTask := &models.Task{
+		ID:              1,
+		CreatedBy:       &models.User{ID: 1},
+		DueAt:           &dueAt,
+		Recurrence:      "FREQ=DAILY",
+		RecurrenceStart: &dueAt,
+	}
+
+	completedTask := &models.Task{
+		ID:         1,
+		IsComplete: true,
+		DueAt:      &dueAt,
+		Recurrence: "FREQ=DAILY",
+	}
+
+	var atomicErr error
+
+	gomock.InOrder(
+		mockRepo.EXPECT().GetByID(ctx, int64(1)).Return(existingTask, nil),
+		mockRepo.
+			EXPECT().
+			Atomic(ctx, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+				atomicErr = fn(ctx)
+				return atomicErr
+			}),
+		mockRepo.EXPECT().Update(ctx, completedTask).Return(nil),
+		mockRepo.EXPECT().GetMaxPosition(ctx, int64(1)).Return(int64(0), errors.New("db down")),
+	)
+
+	err := taskService.Update(ctx, completedTask, int64(1))
+
+	// completion is rolled back along with the failed occurrence, so that the update can be retried
+	assert.EqualError(err, "db down")
+	assert.EqualError(atomicErr, "db down")
+}
+
+func TestUpdateCompletingLastOccurrence(t *testing.T) {
+	dueAt := time.Date(2019, 5, 10, 9, 0, 0, 0, time.UTC)
+	start := dueAt.AddDate(0, 0, -1)
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	mockRepo := taskMock.NewRepository(mockCtrl)
+	mockCollaboratorRepo := collaboratorMock.NewRepository(mockCtrl)
+	mockUserRepo := userMock.NewRepository(mockCtrl)
+	taskService := service.New(mockRepo, tagMock.NewRepository(mockCtrl), projectMock.NewRepository(mockCtrl), mockCollaboratorRepo, mockUserRepo)
+	runAtomic(mockRepo)
+
+	existingTask := &models.Task{
+		ID:              1,
+		CreatedBy:       &models.User{ID: 1},
+		DueAt:           &dueAt,
+		Recurrence:      "FREQ=DAILY;COUNT=2",
+		RecurrenceStart: &start,
+	}
+
+	completedTask := &models.Task{
+		ID:         1,
+		IsComplete: true,
+		DueAt:      &dueAt,
+		Recurrence: "FREQ=DAILY;COUNT=2",
+	}
+
+	mockRepo.EXPECT().GetByID(ctx, int64(1)).Return(existingTask, nil).Times(1)
+	mockRepo.EXPECT().Update(ctx, completedTask).Return(nil).Times(1)
+
+	err := taskService.Update(ctx, completedTask, int64(1))
+
+	assert.NoError(err)
+	assert.Equal("", completedTask.Recurrence)
+}
+
+func TestUpdateCompletesRecurringParent(t *testing.T) {
+	dueAt := time.Date(2019, 5, 10, 9, 0, 0, 0, time.UTC)
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	mockRepo := taskMock.NewRepository(mockCtrl)
+	mockCollaboratorRepo := collaboratorMock.NewRepository(mockCtrl)
+	mockUserRepo := userMock.NewRepository(mockCtrl)
+	taskService := service.New(mockRepo, tagMock.NewRepository(mockCtrl), projectMock.NewRepository(mockCtrl), mockCollaboratorRepo, mockUserRepo)
+	runAtomic(mockRepo)
+
+	existingTask := &models.Task{
+		ID:        4,
+		CreatedBy: &models.User{ID: 1},
+		Parent:    &models.Task{ID: 3},
+	}
+
+	updatedTask := &models.Task{
+		ID:         4,
+		IsComplete: true,
+	}
+
+	parent := &models.Task{
+		ID:              3,
+		CreatedBy:       &models.User{ID: 1},
+		AutoComplete:    true,
+		Progress:        &models.Progress{Completed: 1, Total: 1},
+		DueAt:           &dueAt,
+		Recurrence:      "FREQ=WEEKLY",
+		RecurrenceStart: &dueAt,
+	}
+
+	var next *models.Task
+
+	gomock.InOrder(
+		mockRepo.EXPECT().GetByID(ctx, int64(4)).Return(existingTask, nil),
+		mockRepo.EXPECT().Update(ctx, updatedTask).Return(nil),
+		mockRepo.EXPECT().GetByID(ctx, int64(3)).Return(parent, nil),
+		mockRepo.EXPECT().Update(ctx, parent).Return(nil),
+		mockRepo.EXPECT().GetMaxPosition(ctx, int64(1)).Return(task.PositionGap, nil),
+		mockRepo.EXPECT().Create(ctx, gomock.Any()).Do(func(_ context.Context, created *models.Task) {
+			next = created
+		}).Return(nil),
+		mockRepo.EXPECT().GetSubtasks(ctx, int64(3)).Return([]*models.Task{}, nil),
+	)
+
+	err := taskService.Update(ctx, updatedTask, int64(1))
+
+	assert.NoError(err)
+	assert.True(parent.IsComplete)
+	assert.Equal("", parent.Recurrence)
+	assert.True(dueAt.AddDate(0, 0, 7).Equal(*next.DueAt))
+}
+
+func TestListMarksSharedTasks(t *testing.T) {
+	userID := int64(1)
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	mockRepo := taskMock.NewRepository(mockCtrl)
+	mockCollaboratorRepo := collaboratorMock.NewRepository(mockCtrl)
+	mockUserRepo := userMock.NewRepository(mockCtrl)
+	taskService := service.New(mockRepo, tagMock.NewRepository(mockCtrl), projectMock.NewRepository(mockCtrl), mockCollaboratorRepo, mockUserRepo)
+
+	tasks := []*models.Task{
+		{ID: 1, CreatedBy: &models.User{ID: userID}},
+		{ID: 2, CreatedBy: &models.User{ID: 2}, Subtasks: []*models.Task{{ID: 4, CreatedBy: &models.User{ID: 2}}}},
+		{ID: 3, CreatedBy: &models.User{ID: 2}},
+	}
+
+	mockRepo.
+		EXPECT().
+		GetAllByUserID(ctx, userID, gomock.Any()).
+		Return(tasks, nil).
+		Times(1)
+
+	mockUserRepo.
+		EXPECT().
+		GetByID(ctx, int64(2)).
+		Return(&models.User{ID: 2, Name: "jane", Email: "jane@example.com", Passwd: "secret"}, nil).
+		Times(1)
+
+	page, err := taskService.List(ctx, userID, &task.ListFilter{})
+
+	assert.NoError(err)
+	assert.Nil(page.Tasks[0].SharedBy)
+	assert.Equal(&models.User{ID: 2, Name: "jane", Email: "jane@example.com"}, page.Tasks[1].SharedBy)
+	assert.Equal("jane", page.Tasks[1].Subtasks[0].SharedBy.Name)
+	assert.Equal("jane", page.Tasks[2].SharedBy.Name)
+}
+
+func TestGetByIDForTaskInSharedProject(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	mockRepo := taskMock.NewRepository(mockCtrl)
+	mockProjectRepo := projectMock.NewRepository(mockCtrl)
+	mockCollaboratorRepo := collaboratorMock.NewRepository(mockCtrl)
+	mockUserRepo := userMock.NewRepository(mockCtrl)
+	taskService := service.New(mockRepo, tagMock.NewRepository(mockCtrl), mockProjectRepo, mockCollaboratorRepo, mockUserRepo)
+
+	existingTask := &models.Task{
+		ID:        1,
+		CreatedBy: &models.User{ID: 2},
+		Project:   &models.Project{ID: 5},
+	}
+
+	mockRepo.EXPECT().GetByID(ctx, int64(1)).Return(existingTask, nil).Times(1)
+	mockCollaboratorRepo.EXPECT().GetRole(ctx, collaborator.ResourceTask, int64(1), int64(1)).Return("", nil).Times(1)
+	mockProjectRepo.EXPECT().GetByID(ctx, int64(5)).Return(&models.Project{ID: 5, CreatedBy: &models.User{ID: 2}}, nil).Times(1)
+	mockCollaboratorRepo.EXPECT().GetRole(ctx, collaborator.ResourceProject, int64(5), int64(1)).Return(collaborator.RoleViewer, nil).Times(1)
+	mockUserRepo.EXPECT().GetByID(ctx, int64(2)).Return(&models.User{ID: 2, Name: "jane"}, nil).Times(1)
+
+	result, err := taskService.GetByID(ctx, 1, 1)
+
+	assert.NoError(err)
+	assert.Equal("jane", result.SharedBy.Name)
+}
+
+func TestUpdateByViewer(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	mockRepo := taskMock.NewRepository(mockCtrl)
+	mockCollaboratorRepo := collaboratorMock.NewRepository(mockCtrl)
+	mockUserRepo := userMock.NewRepository(mockCtrl)
+	taskService := service.New(mockRepo, tagMock.NewRepository(mockCtrl), projectMock.NewRepository(mockCtrl), mockCollaboratorRepo, mockUserRepo)
+
+	mockRepo.EXPECT().GetByID(ctx, int64(1)).Return(&models.Task{ID: 1, CreatedBy: &models.User{ID: 2}}, nil).Times(1)
+	mockCollaboratorRepo.EXPECT().GetRole(ctx, collaborator.ResourceTask, int64(1), int64(1)).Return(collaborator.RoleViewer, nil).Times(1)
+
+	err := taskService.Update(ctx, &models.Task{ID: 1, Title: "new title"}, 1)
+
+	assert.Equal(&todoErr.PermissionDeniedError{Resource: "task", Action: "update"}, err)
+}
+
+func TestUpdateByEditorOfParent(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	mockRepo := taskMock.NewRepository(mockCtrl)
+	mockCollaboratorRepo := collaboratorMock.NewRepository(mockCtrl)
+	mockUserRepo := userMock.NewRepository(mockCtrl)
+	taskService := service.New(mockRepo, tagMock.NewRepository(mockCtrl), projectMock.NewRepository(mockCtrl), mockCollaboratorRepo, mockUserRepo)
+	runAtomic(mockRepo)
+
+	existingTask := &models.Task{
+		ID:        4,
+		CreatedBy: &models.User{ID: 2},
+		Parent:    &models.Task{ID: 3},
+	}
+
+	updatedTask := &models.Task{
+		ID:    4,
+		Title: "new title",
+	}
+
+	gomock.InOrder(
+		mockRepo.EXPECT().GetByID(ctx, int64(4)).Return(existingTask, nil),
+		mockCollaboratorRepo.EXPECT().GetRole(ctx, collaborator.ResourceTask, int64(4), int64(1)).Return("", nil),
+		mockCollaboratorRepo.EXPECT().GetRole(ctx, collaborator.ResourceTask, int64(3), int64(1)).Return(collaborator.RoleEditor, nil),
+		mockRepo.EXPECT().Update(ctx, updatedTask).Return(nil),
+	)
+
+	err := taskService.Update(ctx, updatedTask, 1)
+
+	assert.NoError(err)
+	assert.Equal(int64(2), updatedTask.CreatedBy.ID)
+}
+
+func TestDeleteByEditor(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	mockRepo := taskMock.NewRepository(mockCtrl)
+	mockCollaboratorRepo := collaboratorMock.NewRepository(mockCtrl)
+	mockUserRepo := userMock.NewRepository(mockCtrl)
+	taskService := service.New(mockRepo, tagMock.NewRepository(mockCtrl), projectMock.NewRepository(mockCtrl), mockCollaboratorRepo, mockUserRepo)
+
+	mockRepo.EXPECT().GetByID(ctx, int64(1)).Return(&models.Task{ID: 1, CreatedBy: &models.User{ID: 2}}, nil).Times(1)
+	mockCollaboratorRepo.EXPECT().GetRole(ctx, collaborator.ResourceTask, int64(1), int64(1)).Return(collaborator.RoleEditor, nil).Times(1)
+
+	err := taskService.Delete(ctx, 1, 1)
+
+	assert.Equal(&todoErr.PermissionDeniedError{Resource: "task", Action: "delete"}, err)
+}
+
+func TestCreateSubtaskByEditor(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	mockRepo := taskMock.NewRepository(mockCtrl)
+	mockCollaboratorRepo := collaboratorMock.NewRepository(mockCtrl)
+	mockUserRepo := userMock.NewRepository(mockCtrl)
+	taskService := service.New(mockRepo, tagMock.NewRepository(mockCtrl), projectMock.NewRepository(mockCtrl), mockCollaboratorRepo, mockUserRepo)
+
+	newTask := &models.Task{
+		Title:     "testTitle",
+		CreatedBy: &models.User{ID: 1},
+		Parent:    &models.Task{ID: 3},
+	}
+
+	parent := &models.Task{
+		ID:        3,
+		CreatedBy: &models.User{ID: 2},
+		Progress:  &models.Progress{},
+	}
+
+	gomock.InOrder(
+		mockRepo.EXPECT().GetByID(ctx, int64(3)).Return(parent, nil),
+		mockCollaboratorRepo.EXPECT().GetRole(ctx, collaborator.ResourceTask, int64(3), int64(1)).Return(collaborator.RoleEditor, nil),
+		mockRepo.EXPECT().GetMaxPosition(ctx, int64(2)).Return(int64(0), nil),
+		mockRepo.EXPECT().Create(ctx, newTask).Return(nil),
+		mockRepo.EXPECT().GetByID(ctx, int64(3)).Return(parent, nil),
+	)
+
+	err := taskService.Create(ctx, newTask)
+
+	assert.NoError(err)
+	assert.Equal(int64(2), newTask.CreatedBy.ID)
+}
+
+// runAtomic makes the Atomic calls of a mock repository run their function directly
+func runAtomic(mockRepo *taskMock.Repository) {
+	mockRepo.
+		EXPECT().
+		Atomic(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+			return fn(ctx)
+		}).
+		AnyTimes()
+}
+
+func TestBulkBestEffort(t *testing.T) {
+	userID := int64(1)
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	mockRepo := taskMock.NewRepository(mockCtrl)
+	taskService := service.New(mockRepo, tagMock.NewRepository(mockCtrl), projectMock.NewRepository(mockCtrl),
+		collaboratorMock.NewRepository(mockCtrl), userMock.NewRepository(mockCtrl))
+
+	runAtomic(mockRepo)
+
+	mockRepo.
+		EXPECT().
+		GetByID(ctx, int64(1)).
+		DoAndReturn(func(ctx context.Context, id int64) (*models.Task, error) {
+			return &models.Task{ID: 1, CreatedBy: &models.User{ID: userID}}, nil
+		}).
+		Times(2)
+
+	mockRepo.
+		EXPECT().
+		Update(ctx, gomock.Any()).
+		DoAndReturn(func(ctx context.Context, updatedTask *models.Task) error {
+			assert.Equal(int64(1), updatedTask.ID)
+			assert.True(updatedTask.IsComplete)
+
+			return nil
+		}).
+		Times(1)
+
+	mockRepo.EXPECT().GetByID(ctx, int64(2)).Return(nil, nil)
+
+	change := &task.BulkChange{
+		TaskIDs:    []int64{1, 2},
+		Operations: []*task.BulkOperation{{Type: task.BulkComplete}},
+	}
+
+	results, err := taskService.Bulk(ctx, change, userID)
+
+	assert.NoError(err)
+	assert.Equal(2, len(results))
+	assert.Equal(task.BulkSucceeded, results[0].Status)
+	assert.Nil(results[0].Err)
+	assert.Equal(task.BulkFailed, results[1].Status)
+	assert.IsType(&todoErr.ResourceNotFoundError{}, results[1].Err)
+}
+
+func TestBulkAllOrNothing(t *testing.T) {
+	userID := int64(1)
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	mockRepo := taskMock.NewRepository(mockCtrl)
+	mockCollaboratorRepo := collaboratorMock.NewRepository(mockCtrl)
+	taskService := service.New(mockRepo, tagMock.NewRepository(mockCtrl), projectMock.NewRepository(mockCtrl),
+		mockCollaboratorRepo, userMock.NewRepository(mockCtrl))
+
+	runAtomic(mockRepo)
+
+	gomock.InOrder(
+		mockRepo.EXPECT().GetByID(ctx, int64(1)).Return(&models.Task{ID: 1, CreatedBy: &models.User{ID: userID}}, nil),
+		mockRepo.EXPECT().Delete(ctx, int64(1)).Return(nil),
+		mockRepo.EXPECT().GetByID(ctx, int64(2)).Return(&models.Task{ID: 2, CreatedBy: &models.User{ID: 5}}, nil),
+		mockCollaboratorRepo.EXPECT().GetRole(ctx, collaborator.ResourceTask, int64(2), userID).Return(collaborator.RoleViewer, nil),
+	)
+
+	change := &task.BulkChange{
+		TaskIDs:      []int64{1, 2, 3},
+		Operations:   []*task.BulkOperation{{Type: task.BulkDelete}},
+		AllOrNothing: true,
+	}
+
+	results, err := taskService.Bulk(ctx, change, userID)
+
+	assert.NoError(err)
+	assert.Equal(task.BulkRolledBack, results[0].Status)
+	assert.Equal(task.BulkFailed, results[1].Status)
+	assert.IsType(&todoErr.PermissionDeniedError{}, results[1].Err)
+	assert.Equal(task.BulkSkipped, results[2].Status)
+}
+
+func TestBulkMovesAndTagsTasks(t *testing.T) {
+	userID := int64(1)
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	mockRepo := taskMock.NewRepository(mockCtrl)
+	mockTagRepo := tagMock.NewRepository(mockCtrl)
+	mockProjectRepo := projectMock.NewRepository(mockCtrl)
+	taskService := service.New(mockRepo, mockTagRepo, mockProjectRepo,
+		collaboratorMock.NewRepository(mockCtrl), userMock.NewRepository(mockCtrl))
+
+	runAtomic(mockRepo)
+
+	mockRepo.
+		EXPECT().
+		GetByID(ctx, int64(1)).
+		DoAndReturn(func(ctx context.Context, id int64) (*models.Task, error) {
+			return &models.Task{ID: 1, CreatedBy: &models.User{ID: userID}, Tags: []*models.Tag{{ID: 3}}}, nil
+		}).
+		Times(4)
+
+	mockProjectRepo.EXPECT().GetByID(ctx, int64(7)).Return(&models.Project{ID: 7, CreatedBy: &models.User{ID: userID}}, nil)
+
+	gomock.InOrder(
+		mockRepo.EXPECT().Update(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, updatedTask *models.Task) error {
+			assert.Equal(int64(7), updatedTask.Project.ID)
+
+			return nil
+		}),
+		mockTagRepo.EXPECT().GetByIDs(ctx, []int64{3, 4}).Return([]*models.Tag{
+			{ID: 3, CreatedBy: &models.User{ID: userID}},
+			{ID: 4, CreatedBy: &models.User{ID: userID}},
+		}, nil),
+		mockRepo.EXPECT().Update(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, updatedTask *models.Task) error {
+			assert.Equal(2, len(updatedTask.Tags))
+
+			return nil
+		}),
+	)
+
+	mockTagRepo.EXPECT().GetByIDs(ctx, []int64{3}).Return([]*models.Tag{{ID: 3, CreatedBy: &models.User{ID: userID}}}, nil)
+
+	change := &task.BulkChange{
+		TaskIDs: []int64{1},
+		Operations: []*task.BulkOperation{
+			{Type: task.BulkMoveToProject, Project: &models.Project{ID: 7}},
+			{Type: task.BulkAddTag, Tag: &models.Tag{ID: 4}},
+		},
+	}
+
+	results, err := taskService.Bulk(ctx, change, userID)
+
+	assert.NoError(err)
+	assert.Equal(task.BulkSucceeded, results[0].Status)
+}
+
+func TestBulkWithTransactionError(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	mockRepo := taskMock.NewRepository(mockCtrl)
+	taskService := service.New(mockRepo, tagMock.NewRepository(mockCtrl), projectMock.NewRepository(mockCtrl),
+		collaboratorMock.NewRepository(mockCtrl), userMock.NewRepository(mockCtrl))
+
+	mockRepo.EXPECT().Atomic(ctx, gomock.Any()).Return(errors.New("db down"))
+
+	change := &task.BulkChange{
+		TaskIDs:    []int64{1},
+		Operations: []*task.BulkOperation{{Type: task.BulkReopen}},
+	}
+
+	results, err := taskService.Bulk(ctx, change, 1)
+
+	assert.EqualError(err, "db down")
+	assert.Nil(results)
+}
+
+func TestImport(t *testing.T) {
+	userID := int64(1)
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	mockRepo := taskMock.NewRepository(mockCtrl)
+	mockTagRepo := tagMock.NewRepository(mockCtrl)
+	mockProjectRepo := projectMock.NewRepository(mockCtrl)
+	taskService := service.New(mockRepo, mockTagRepo, mockProjectRepo,
+		collaboratorMock.NewRepository(mockCtrl), userMock.NewRepository(mockCtrl))
+
+	runAtomic(mockRepo)
+
+	owner := &models.User{ID: userID}
+	other := &models.User{ID: 2}
+
+	mockProjectRepo.EXPECT().GetAllByUserID(ctx, userID, false).Return([]*models.Project{
+		{ID: 7, Name: "home", CreatedBy: other},
+		{ID: 8, Name: "home", CreatedBy: owner},
+	}, nil)
+	mockProjectRepo.EXPECT().GetByID(ctx, int64(8)).Return(&models.Project{ID: 8, CreatedBy: owner}, nil)
+	mockTagRepo.EXPECT().GetAllByUserID(ctx, userID).Return([]*models.Tag{{ID: 4, Name: "work", CreatedBy: owner}}, nil)
+	mockTagRepo.EXPECT().GetByIDs(ctx, []int64{4}).Return([]*models.Tag{{ID: 4, Name: "work", CreatedBy: owner}}, nil)
+	mockRepo.EXPECT().GetMaxPosition(ctx, userID).Return(int64(0), nil).Times(2)
+	mockRepo.EXPECT().GetByID(ctx, int64(10)).Return(&models.Task{ID: 10, CreatedBy: owner}, nil).Times(2)
+
+	gomock.InOrder(
+		mockRepo.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, newTask *models.Task) error {
+			assert.Equal(int64(8), newTask.Project.ID)
+			assert.Equal(int64(4), newTask.Tags[0].ID)
+			newTask.ID = 10
+
+			return nil
+		}),
+		mockRepo.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, newTask *models.Task) error {
+			assert.Equal(int64(10), newTask.Parent.ID)
+			newTask.ID = 11
+
+			return nil
+		}),
+	)
+
+	parent := &models.Task{
+		Title:     "parent",
+		CreatedBy: owner,
+		Project:   &models.Project{Name: "home"},
+		Tags:      []*models.Tag{{Name: "work"}},
+	}
+	subtask := &models.Task{
+		Title:     "subtask",
+		CreatedBy: owner,
+		Parent:    parent,
+	}
+
+	failures, err := taskService.Import(ctx, []*models.Task{parent, subtask}, userID, false)
+
+	assert.NoError(err)
+	assert.Equal(0, len(failures))
+}
+
+func TestImportRejectsUnknownNames(t *testing.T) {
+	userID := int64(1)
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	mockRepo := taskMock.NewRepository(mockCtrl)
+	mockTagRepo := tagMock.NewRepository(mockCtrl)
+	mockProjectRepo := projectMock.NewRepository(mockCtrl)
+	taskService := service.New(mockRepo, mockTagRepo, mockProjectRepo,
+		collaboratorMock.NewRepository(mockCtrl), userMock.NewRepository(mockCtrl))
+
+	owner := &models.User{ID: userID}
+
+	runAtomic(mockRepo)
+
+	mockProjectRepo.EXPECT().GetAllByUserID(ctx, userID, false).Return([]*models.Project{}, nil)
+	mockTagRepo.EXPECT().GetAllByUserID(ctx, userID).Return([]*models.Tag{}, nil)
+
+	parent := &models.Task{Title: "parent", CreatedBy: owner, Project: &models.Project{Name: "home"}}
+	subtask := &models.Task{Title: "subtask", CreatedBy: owner, Parent: parent}
+	tagged := &models.Task{Title: "tagged", CreatedBy: owner, Tags: []*models.Tag{{Name: "work"}}}
+
+	failures, err := taskService.Import(ctx, []*models.Task{parent, subtask, tagged}, userID, false)
+
+	assert.NoError(err)
+	assert.Equal(3, len(failures))
+	assert.Equal("Project home does not exist", failures[0].Err.(*todoErr.InvalidValueError).Reason)
+	assert.Equal("parentId", failures[1].Err.(*todoErr.InvalidValueError).Field)
+	assert.Equal(2, failures[2].Index)
+	assert.Equal("Tag work does not exist", failures[2].Err.(*todoErr.InvalidValueError).Reason)
+}
+
+func TestImportDryRun(t *testing.T) {
+	userID := int64(1)
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	mockRepo := taskMock.NewRepository(mockCtrl)
+	mockTagRepo := tagMock.NewRepository(mockCtrl)
+	mockProjectRepo := projectMock.NewRepository(mockCtrl)
+	taskService := service.New(mockRepo, mockTagRepo, mockProjectRepo,
+		collaboratorMock.NewRepository(mockCtrl), userMock.NewRepository(mockCtrl))
+
+	mockProjectRepo.EXPECT().GetAllByUserID(ctx, userID, false).Return([]*models.Project{}, nil)
+	mockTagRepo.EXPECT().GetAllByUserID(ctx, userID).Return([]*models.Tag{}, nil)
+	mockRepo.EXPECT().GetMaxPosition(ctx, userID).Return(int64(0), nil)
+	mockRepo.EXPECT().Create(ctx, gomock.Any()).Return(nil)
+
+	gomock.InOrder(
+		mockRepo.
+			EXPECT().
+			Atomic(ctx, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+				err := fn(ctx)
+				assert.NotNil(err, "dry run should roll back the transaction")
+
+				return err
+			}),
+		mockRepo.
+			EXPECT().
+			Atomic(ctx, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+				return fn(ctx)
+			}),
+	)
+
+	newTask := &models.Task{Title: "title", CreatedBy: &models.User{ID: userID}}
+
+	failures, err := taskService.Import(ctx, []*models.Task{newTask}, userID, true)
+
+	assert.NoError(err)
+	assert.Equal(0, len(failures))
+}
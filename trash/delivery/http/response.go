@@ -0,0 +1,56 @@
+package http
+
+import (
+	"time"
+
+	"github.com/dheerajgopi/todo-api/models"
+)
+
+// TaskData represents json structure for a task in trash or restored from it.
+// Deleted time is null for restored tasks.
+type TaskData struct {
+	ID          int64      `json:"id"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	ProjectID   *int64     `json:"projectId"`
+	ParentID    *int64     `json:"parentId"`
+	IsComplete  bool       `json:"isComplete"`
+	DueAt       *time.Time `json:"dueAt"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	UpdatedAt   time.Time  `json:"updatedAt"`
+	DeletedAt   *time.Time `json:"deletedAt"`
+}
+
+// ListTrashResponse represents response for GET /trash API
+type ListTrashResponse struct {
+	Tasks []*TaskData `json:"tasks"`
+}
+
+// RestoreTaskResponse represents response for POST /tasks/{id}/restore API
+type RestoreTaskResponse struct {
+	Task *TaskData `json:"task"`
+}
+
+// newTaskData builds the json structure of a task
+func newTaskData(task *models.Task) *TaskData {
+	taskData := &TaskData{
+		ID:          task.ID,
+		Title:       task.Title,
+		Description: task.Description,
+		IsComplete:  task.IsComplete,
+		DueAt:       task.DueAt,
+		CreatedAt:   task.CreatedAt,
+		UpdatedAt:   task.UpdatedAt,
+		DeletedAt:   task.DeletedAt,
+	}
+
+	if task.Project != nil {
+		taskData.ProjectID = &task.Project.ID
+	}
+
+	if task.Parent != nil {
+		taskData.ParentID = &task.Parent.ID
+	}
+
+	return taskData
+}
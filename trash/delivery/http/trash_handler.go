@@ -0,0 +1,154 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/dheerajgopi/todo-api/auth"
+	"github.com/dheerajgopi/todo-api/common"
+	todoErr "github.com/dheerajgopi/todo-api/common/error"
+	"github.com/dheerajgopi/todo-api/common/middlewares"
+	"github.com/dheerajgopi/todo-api/history"
+	"github.com/dheerajgopi/todo-api/trash"
+	"github.com/gorilla/mux"
+)
+
+// TrashHandler represents HTTP handler for deleted tasks
+type TrashHandler struct {
+	TrashService trash.Service
+	App          *common.App
+}
+
+// New creates new HTTP handler for trash
+func New(router *mux.Router, service trash.Service, app *common.App) {
+	handler := &TrashHandler{
+		TrashService: service,
+		App:          app,
+	}
+
+	jwtMiddleware := middlewares.JwtValidator(app)
+
+	// trash itself is managed with access tokens only, while restoring a task is a task write which API keys can do
+	writeMiddleware := middlewares.ScopedValidator(app, auth.ScopeTasksWrite)
+
+	router.HandleFunc("/trash", app.CreateHandler(jwtMiddleware(handler.List))).Methods("GET")
+	router.HandleFunc("/trash", app.CreateHandler(jwtMiddleware(handler.Empty))).Methods("DELETE")
+	router.HandleFunc("/trash/{id:[0-9]+}", app.CreateHandler(jwtMiddleware(handler.Delete))).Methods("DELETE")
+	router.HandleFunc("/tasks/{id:[0-9]+}/restore", app.CreateHandler(writeMiddleware(handler.Restore))).Methods("POST")
+}
+
+// List will return the tasks of the user in trash, most recently deleted first
+func (handler *TrashHandler) List(res http.ResponseWriter, req *http.Request, reqCtx *common.RequestContext) (int, interface{}, *todoErr.APIError) {
+	tasks, err := handler.TrashService.List(context.TODO(), reqCtx.UserID)
+
+	if err != nil {
+		return trashServiceError(err)
+	}
+
+	taskList := make([]*TaskData, 0)
+
+	for _, task := range tasks {
+		taskList = append(taskList, newTaskData(task))
+	}
+
+	responseData := &ListTrashResponse{
+		Tasks: taskList,
+	}
+
+	return http.StatusOK, responseData, nil
+}
+
+// Restore will bring a task back from trash
+func (handler *TrashHandler) Restore(res http.ResponseWriter, req *http.Request, reqCtx *common.RequestContext) (int, interface{}, *todoErr.APIError) {
+	taskID, apiError := parseTaskID(req)
+
+	if apiError != nil {
+		return http.StatusBadRequest, nil, apiError
+	}
+
+	ctx := history.WithActor(context.TODO(), &history.Actor{
+		UserID:    reqCtx.UserID,
+		RequestID: reqCtx.RequestID,
+	})
+
+	task, err := handler.TrashService.Restore(ctx, taskID, reqCtx.UserID)
+
+	if err != nil {
+		return trashServiceError(err)
+	}
+
+	responseData := &RestoreTaskResponse{
+		Task: newTaskData(task),
+	}
+
+	return http.StatusOK, responseData, nil
+}
+
+// Delete will permanently remove a task from trash
+func (handler *TrashHandler) Delete(res http.ResponseWriter, req *http.Request, reqCtx *common.RequestContext) (int, interface{}, *todoErr.APIError) {
+	taskID, apiError := parseTaskID(req)
+
+	if apiError != nil {
+		return http.StatusBadRequest, nil, apiError
+	}
+
+	if err := handler.TrashService.Delete(context.TODO(), taskID, reqCtx.UserID); err != nil {
+		return trashServiceError(err)
+	}
+
+	return http.StatusOK, nil, nil
+}
+
+// Empty will permanently remove all the tasks of the user from trash
+func (handler *TrashHandler) Empty(res http.ResponseWriter, req *http.Request, reqCtx *common.RequestContext) (int, interface{}, *todoErr.APIError) {
+	if err := handler.TrashService.Empty(context.TODO(), reqCtx.UserID); err != nil {
+		return trashServiceError(err)
+	}
+
+	return http.StatusOK, nil, nil
+}
+
+// parseTaskID reads the task id from the request path
+func parseTaskID(req *http.Request) (int64, *todoErr.APIError) {
+	taskID, err := strconv.ParseInt(mux.Vars(req)["id"], 10, 64)
+
+	if err != nil {
+		return 0, todoErr.NewAPIError(err.Error(), &todoErr.APIErrorBody{
+			Message: "Invalid value",
+			Target:  "id",
+		})
+	}
+
+	return taskID, nil
+}
+
+// trashServiceError maps errors returned by the trash service to the API response
+func trashServiceError(err error) (int, interface{}, *todoErr.APIError) {
+	switch err.(type) {
+	case *todoErr.ResourceNotFoundError:
+		resourceNotFoundErr, _ := err.(*todoErr.ResourceNotFoundError)
+
+		apiError := todoErr.NewAPIError(resourceNotFoundErr.Error(), &todoErr.APIErrorBody{
+			Message: "Not found",
+			Target:  resourceNotFoundErr.Resource,
+		})
+
+		return http.StatusNotFound, nil, apiError
+	case *todoErr.InvalidValueError:
+		invalidValueErr, _ := err.(*todoErr.InvalidValueError)
+
+		apiError := todoErr.NewAPIError(invalidValueErr.Error(), &todoErr.APIErrorBody{
+			Message: invalidValueErr.Reason,
+			Target:  invalidValueErr.Field,
+		})
+
+		return http.StatusBadRequest, nil, apiError
+	default:
+		apiError := todoErr.NewAPIError(err.Error(), &todoErr.APIErrorBody{
+			Message: "Internal server error",
+		})
+
+		return http.StatusInternalServerError, nil, apiError
+	}
+}
@@ -0,0 +1,217 @@
+package http_test
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dheerajgopi/todo-api/common"
+	_errors "github.com/dheerajgopi/todo-api/common/error"
+	"github.com/dheerajgopi/todo-api/config"
+	"github.com/dheerajgopi/todo-api/history"
+	"github.com/dheerajgopi/todo-api/models"
+	"github.com/dheerajgopi/todo-api/trash"
+	_trashHandler "github.com/dheerajgopi/todo-api/trash/delivery/http"
+	mock "github.com/dheerajgopi/todo-api/trash/mock"
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestList(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+	reqCtx.UserID = 1
+	req := httptest.NewRequest("GET", "/trash", nil)
+	deletedAt := time.Now()
+
+	mockService.
+		EXPECT().
+		List(gomock.Any(), int64(1)).
+		Return([]*models.Task{
+			{ID: 5, Title: "report", Project: &models.Project{ID: 2}, DeletedAt: &deletedAt},
+		}, nil).
+		Times(1)
+
+	status, data, err := handler.List(httptest.NewRecorder(), req, reqCtx)
+
+	responseData := data.(*_trashHandler.ListTrashResponse)
+
+	assert.Equal(200, status)
+	assert.Nil(err)
+	assert.Equal(1, len(responseData.Tasks))
+	assert.Equal(int64(2), *responseData.Tasks[0].ProjectID)
+	assert.Nil(responseData.Tasks[0].ParentID)
+	assert.Equal(&deletedAt, responseData.Tasks[0].DeletedAt)
+}
+
+func TestListWithServerError(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+	req := httptest.NewRequest("GET", "/trash", nil)
+
+	mockService.EXPECT().List(gomock.Any(), gomock.Any()).Return(nil, errors.New("db down"))
+
+	status, _, err := handler.List(httptest.NewRecorder(), req, reqCtx)
+
+	assert.Equal(500, status)
+	assert.Equal("Internal server error", err.Body[0].Message)
+}
+
+func TestRestore(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+	reqCtx.UserID = 1
+	req := httptest.NewRequest("POST", "/tasks/5/restore", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "5"})
+
+	mockService.
+		EXPECT().
+		Restore(gomock.Any(), int64(5), int64(1)).
+		DoAndReturn(func(ctx context.Context, id int64, userID int64) (*models.Task, error) {
+			actor := history.ActorFrom(ctx)
+
+			assert.Equal(int64(1), actor.UserID)
+			assert.Equal("dummyRequestID", actor.RequestID)
+
+			return &models.Task{ID: 5, Title: "report", Parent: &models.Task{ID: 3}}, nil
+		}).
+		Times(1)
+
+	status, data, err := handler.Restore(httptest.NewRecorder(), req, reqCtx)
+
+	responseData := data.(*_trashHandler.RestoreTaskResponse)
+
+	assert.Equal(200, status)
+	assert.Nil(err)
+	assert.Equal(int64(5), responseData.Task.ID)
+	assert.Equal(int64(3), *responseData.Task.ParentID)
+	assert.Nil(responseData.Task.DeletedAt)
+}
+
+func TestRestoreWithInvalidID(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	handler := setupHandler(mock.NewService(mockCtrl))
+	reqCtx := setupRequestContext(handler.App)
+	req := httptest.NewRequest("POST", "/tasks/abc/restore", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "abc"})
+
+	status, _, err := handler.Restore(httptest.NewRecorder(), req, reqCtx)
+
+	assert.Equal(400, status)
+	assert.Equal("id", err.Body[0].Target)
+}
+
+func TestRestoreWithDeletedParent(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+	reqCtx.UserID = 1
+	req := httptest.NewRequest("POST", "/tasks/6/restore", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "6"})
+
+	mockService.
+		EXPECT().
+		Restore(gomock.Any(), int64(6), int64(1)).
+		Return(nil, &_errors.InvalidValueError{Resource: "task", Field: "parent", Reason: "Parent task should be restored first"})
+
+	status, _, err := handler.Restore(httptest.NewRecorder(), req, reqCtx)
+
+	assert.Equal(400, status)
+	assert.Equal("parent", err.Body[0].Target)
+	assert.Equal("Parent task should be restored first", err.Body[0].Message)
+}
+
+func TestDelete(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+	reqCtx.UserID = 1
+	req := httptest.NewRequest("DELETE", "/trash/5", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "5"})
+
+	mockService.EXPECT().Delete(gomock.Any(), int64(5), int64(1)).Return(nil)
+
+	status, data, err := handler.Delete(httptest.NewRecorder(), req, reqCtx)
+
+	assert.Equal(200, status)
+	assert.Nil(err)
+	assert.Nil(data)
+}
+
+func TestDeleteOfMissingTask(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+	reqCtx.UserID = 1
+	req := httptest.NewRequest("DELETE", "/trash/5", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "5"})
+
+	mockService.EXPECT().Delete(gomock.Any(), int64(5), int64(1)).Return(&_errors.ResourceNotFoundError{Resource: "task"})
+
+	status, _, err := handler.Delete(httptest.NewRecorder(), req, reqCtx)
+
+	assert.Equal(404, status)
+	assert.Equal("task", err.Body[0].Target)
+}
+
+func TestEmpty(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+	reqCtx.UserID = 1
+	req := httptest.NewRequest("DELETE", "/trash", nil)
+
+	mockService.EXPECT().Empty(gomock.Any(), int64(1)).Return(nil)
+
+	status, _, err := handler.Empty(httptest.NewRecorder(), req, reqCtx)
+
+	assert.Equal(200, status)
+	assert.Nil(err)
+}
+
+func setupHandler(mockService trash.Service) *_trashHandler.TrashHandler {
+	app := &common.App{
+		Logger: logrus.New(),
+		Config: &config.Config{},
+	}
+
+	handler := &_trashHandler.TrashHandler{
+		TrashService: mockService,
+		App:          app,
+	}
+
+	return handler
+}
+
+func setupRequestContext(app *common.App) *common.RequestContext {
+	reqCtx := &common.RequestContext{
+		RequestID: "dummyRequestID",
+		LogEntry: app.Logger.WithFields(
+			logrus.Fields{},
+		),
+	}
+
+	return reqCtx
+}
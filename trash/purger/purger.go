@@ -0,0 +1,71 @@
+package purger
+
+import (
+	"context"
+	"time"
+
+	"github.com/dheerajgopi/todo-api/trash"
+	"github.com/sirupsen/logrus"
+)
+
+// Purger periodically removes the tasks and users which stayed deleted longer than the retention period
+type Purger struct {
+	service   trash.Service
+	retention time.Duration
+	interval  time.Duration
+	logger    *logrus.Logger
+	stop      chan struct{}
+	done      chan struct{}
+}
+
+// New creates a purger which runs every interval
+func New(service trash.Service, retention time.Duration, interval time.Duration, logger *logrus.Logger) *Purger {
+	return &Purger{
+		service:   service,
+		retention: retention,
+		interval:  interval,
+		logger:    logger,
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+}
+
+// Start runs the purger in background. First run is made right away.
+func (purger *Purger) Start() {
+	go func() {
+		defer close(purger.done)
+
+		ticker := time.NewTicker(purger.interval)
+		defer ticker.Stop()
+
+		for {
+			purger.Run(context.Background())
+
+			select {
+			case <-ticker.C:
+			case <-purger.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop ends the background runs, and waits for the current run to finish
+func (purger *Purger) Stop() {
+	close(purger.stop)
+	<-purger.done
+}
+
+// Run purges the tasks and users deleted before the retention period
+func (purger *Purger) Run(ctx context.Context) {
+	before := time.Now().Add(-purger.retention)
+	purged, err := purger.service.Purge(ctx, before)
+
+	if err != nil {
+		purger.logger.Errorf("Error purging trash: %v", err)
+	}
+
+	if purged > 0 {
+		purger.logger.Infof("Purged %d items deleted before %s", purged, before.Format(time.RFC3339))
+	}
+}
@@ -0,0 +1,74 @@
+package purger_test
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dheerajgopi/todo-api/trash/mock"
+	"github.com/dheerajgopi/todo-api/trash/purger"
+)
+
+func newLogger() *logrus.Logger {
+	logger := logrus.New()
+	logger.SetOutput(ioutil.Discard)
+
+	return logger
+}
+
+func TestRun(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	mockService := mock.NewService(mockCtrl)
+	retention := 30 * 24 * time.Hour
+	p := purger.New(mockService, retention, time.Hour, newLogger())
+
+	mockService.
+		EXPECT().
+		Purge(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, before time.Time) (int, error) {
+			assert.WithinDuration(t, time.Now().Add(-retention), before, time.Minute)
+
+			return 2, nil
+		})
+
+	p.Run(context.TODO())
+}
+
+func TestStartRunsUntilStopped(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	mockService := mock.NewService(mockCtrl)
+	p := purger.New(mockService, time.Hour, time.Millisecond, newLogger())
+	runs := make(chan struct{}, 10)
+
+	mockService.
+		EXPECT().
+		Purge(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, before time.Time) (int, error) {
+			select {
+			case runs <- struct{}{}:
+			default:
+			}
+
+			return 0, errors.New("db down")
+		}).
+		MinTimes(2)
+
+	p.Start()
+
+	<-runs
+	<-runs
+
+	p.Stop()
+}
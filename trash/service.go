@@ -0,0 +1,18 @@
+package trash
+
+import (
+	"context"
+	"time"
+
+	"github.com/dheerajgopi/todo-api/models"
+)
+
+// Service represents trash service contract.
+// Deleted tasks stay in trash until they are restored, deleted permanently or purged after the retention period.
+type Service interface {
+	List(ctx context.Context, userID int64) ([]*models.Task, error)
+	Restore(ctx context.Context, id int64, userID int64) (*models.Task, error)
+	Delete(ctx context.Context, id int64, userID int64) error
+	Empty(ctx context.Context, userID int64) error
+	Purge(ctx context.Context, before time.Time) (int, error)
+}
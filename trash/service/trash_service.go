@@ -0,0 +1,227 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/dheerajgopi/todo-api/attachment"
+	todoErr "github.com/dheerajgopi/todo-api/common/error"
+	"github.com/dheerajgopi/todo-api/models"
+	"github.com/dheerajgopi/todo-api/storage"
+	"github.com/dheerajgopi/todo-api/task"
+	"github.com/dheerajgopi/todo-api/trash"
+	"github.com/dheerajgopi/todo-api/user"
+)
+
+// purgeBatchSize is the maximum number of tasks or users read at once while purging
+const purgeBatchSize = 100
+
+type trashService struct {
+	taskRepo       task.Repository
+	userRepo       user.Repository
+	attachmentRepo attachment.Repository
+	store          storage.Storage
+}
+
+// New returns a new object implementing trash.Service interface.
+// Attachments of the purged tasks and users are removed from the blob storage.
+func New(taskRepo task.Repository, userRepo user.Repository, attachmentRepo attachment.Repository, store storage.Storage) trash.Service {
+	return &trashService{
+		taskRepo:       taskRepo,
+		userRepo:       userRepo,
+		attachmentRepo: attachmentRepo,
+		store:          store,
+	}
+}
+
+// List returns the tasks of the user in trash, most recently deleted first
+func (service *trashService) List(ctx context.Context, userID int64) ([]*models.Task, error) {
+	return service.taskRepo.GetTrash(ctx, userID)
+}
+
+// Restore brings a task of the user back from trash, along with the subtasks deleted with it.
+// A subtask can be restored only after its parent.
+func (service *trashService) Restore(ctx context.Context, id int64, userID int64) (*models.Task, error) {
+	deletedTask, err := service.getDeleted(ctx, id, userID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if deletedTask.Parent != nil {
+		deletedParent, err := service.taskRepo.GetDeletedByID(ctx, deletedTask.Parent.ID)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if deletedParent != nil {
+			return nil, &todoErr.InvalidValueError{
+				Resource: "task",
+				Field:    "parent",
+				Reason:   "Parent task should be restored first",
+			}
+		}
+	}
+
+	if err = service.taskRepo.Restore(ctx, deletedTask); err != nil {
+		return nil, err
+	}
+
+	return service.taskRepo.GetByID(ctx, id)
+}
+
+// Delete permanently removes a task of the user from trash
+func (service *trashService) Delete(ctx context.Context, id int64, userID int64) error {
+	if _, err := service.getDeleted(ctx, id, userID); err != nil {
+		return err
+	}
+
+	return service.purgeTasks(ctx, []int64{id})
+}
+
+// Empty permanently removes all the tasks of the user from trash
+func (service *trashService) Empty(ctx context.Context, userID int64) error {
+	deletedTasks, err := service.taskRepo.GetTrash(ctx, userID)
+
+	if err != nil {
+		return err
+	}
+
+	ids := make([]int64, 0, len(deletedTasks))
+
+	for _, deletedTask := range deletedTasks {
+		ids = append(ids, deletedTask.ID)
+	}
+
+	return service.purgeTasks(ctx, ids)
+}
+
+// Purge permanently removes the tasks and users deleted before the given time.
+// Number of purged tasks and users is returned, along with the first error.
+func (service *trashService) Purge(ctx context.Context, before time.Time) (int, error) {
+	purgedTasks, err := service.purgeDeletedTasks(ctx, before)
+
+	if err != nil {
+		return purgedTasks, err
+	}
+
+	purgedUsers, err := service.purgeDeletedUsers(ctx, before)
+
+	return purgedTasks + purgedUsers, err
+}
+
+// purgeDeletedTasks permanently removes the tasks deleted before the given time, in batches
+func (service *trashService) purgeDeletedTasks(ctx context.Context, before time.Time) (int, error) {
+	purged := 0
+
+	for {
+		ids, err := service.taskRepo.GetPurgeable(ctx, before, purgeBatchSize)
+
+		if err != nil {
+			return purged, err
+		}
+
+		if err = service.purgeTasks(ctx, ids); err != nil {
+			return purged, err
+		}
+
+		purged += len(ids)
+
+		if len(ids) < purgeBatchSize {
+			return purged, nil
+		}
+	}
+}
+
+// purgeDeletedUsers permanently removes the users deleted before the given time, in batches
+func (service *trashService) purgeDeletedUsers(ctx context.Context, before time.Time) (int, error) {
+	purged := 0
+
+	for {
+		ids, err := service.userRepo.GetPurgeable(ctx, before, purgeBatchSize)
+
+		if err != nil {
+			return purged, err
+		}
+
+		for _, id := range ids {
+			if err = service.purgeUser(ctx, id); err != nil {
+				return purged, err
+			}
+
+			purged++
+		}
+
+		if len(ids) < purgeBatchSize {
+			return purged, nil
+		}
+	}
+}
+
+// getDeleted returns the task in trash, if it is owned by the user
+func (service *trashService) getDeleted(ctx context.Context, id int64, userID int64) (*models.Task, error) {
+	deletedTask, err := service.taskRepo.GetDeletedByID(ctx, id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if deletedTask == nil || deletedTask.CreatedBy.ID != userID {
+		return nil, &todoErr.ResourceNotFoundError{
+			Resource: "task",
+		}
+	}
+
+	return deletedTask, nil
+}
+
+// purgeTasks permanently removes the tasks along with their subtasks, and then their attachments from the blob storage
+func (service *trashService) purgeTasks(ctx context.Context, ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	attachments, err := service.attachmentRepo.GetAllByTaskIDs(ctx, ids)
+
+	if err != nil {
+		return err
+	}
+
+	if err = service.taskRepo.Purge(ctx, ids); err != nil {
+		return err
+	}
+
+	return service.deleteBlobs(ctx, attachments)
+}
+
+// purgeUser permanently removes the user along with the user's data, and then the attachments from the blob storage
+func (service *trashService) purgeUser(ctx context.Context, id int64) error {
+	attachments, err := service.attachmentRepo.GetAllByUserID(ctx, id)
+
+	if err != nil {
+		return err
+	}
+
+	if err = service.userRepo.Purge(ctx, id); err != nil {
+		return err
+	}
+
+	return service.deleteBlobs(ctx, attachments)
+}
+
+// deleteBlobs removes the blobs of the attachments, and returns the first error.
+// Blobs which are already missing are skipped.
+func (service *trashService) deleteBlobs(ctx context.Context, attachments []*models.Attachment) error {
+	var firstErr error
+
+	for _, attachment := range attachments {
+		err := service.store.Delete(ctx, attachment.StorageKey)
+
+		if err != nil && err != storage.ErrNotFound && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	return firstErr
+}
@@ -0,0 +1,247 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	attachmentMock "github.com/dheerajgopi/todo-api/attachment/mock"
+	todoErr "github.com/dheerajgopi/todo-api/common/error"
+	"github.com/dheerajgopi/todo-api/models"
+	"github.com/dheerajgopi/todo-api/storage"
+	storageMock "github.com/dheerajgopi/todo-api/storage/mock"
+	taskMock "github.com/dheerajgopi/todo-api/task/mock"
+	"github.com/dheerajgopi/todo-api/trash"
+	"github.com/dheerajgopi/todo-api/trash/service"
+	userMock "github.com/dheerajgopi/todo-api/user/mock"
+)
+
+type mocks struct {
+	taskRepo       *taskMock.Repository
+	userRepo       *userMock.Repository
+	attachmentRepo *attachmentMock.Repository
+	store          *storageMock.Storage
+}
+
+func setupService(mockCtrl *gomock.Controller) (trash.Service, *mocks) {
+	m := &mocks{
+		taskRepo:       taskMock.NewRepository(mockCtrl),
+		userRepo:       userMock.NewRepository(mockCtrl),
+		attachmentRepo: attachmentMock.NewRepository(mockCtrl),
+		store:          storageMock.NewStorage(mockCtrl),
+	}
+
+	return service.New(m.taskRepo, m.userRepo, m.attachmentRepo, m.store), m
+}
+
+func deletedTask(id int64, userID int64) *models.Task {
+	deletedAt := time.Now()
+
+	return &models.Task{
+		ID:        id,
+		Title:     "report",
+		CreatedBy: &models.User{ID: userID},
+		DeletedAt: &deletedAt,
+	}
+}
+
+func TestRestore(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	trashService, m := setupService(mockCtrl)
+	task := deletedTask(5, 1)
+
+	gomock.InOrder(
+		m.taskRepo.EXPECT().GetDeletedByID(ctx, int64(5)).Return(task, nil),
+		m.taskRepo.EXPECT().Restore(ctx, task).Return(nil),
+		m.taskRepo.EXPECT().GetByID(ctx, int64(5)).Return(&models.Task{ID: 5}, nil),
+	)
+
+	restoredTask, err := trashService.Restore(ctx, 5, 1)
+
+	assert.NoError(err)
+	assert.Nil(restoredTask.DeletedAt)
+}
+
+func TestRestoreOfOtherUsersTask(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	trashService, m := setupService(mockCtrl)
+
+	m.taskRepo.EXPECT().GetDeletedByID(ctx, int64(5)).Return(deletedTask(5, 2), nil)
+
+	_, err := trashService.Restore(ctx, 5, 1)
+
+	assert.Equal(&todoErr.ResourceNotFoundError{Resource: "task"}, err)
+}
+
+func TestRestoreOfTaskNotInTrash(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	trashService, m := setupService(mockCtrl)
+
+	m.taskRepo.EXPECT().GetDeletedByID(ctx, int64(5)).Return(nil, nil)
+
+	_, err := trashService.Restore(ctx, 5, 1)
+
+	assert.IsType(&todoErr.ResourceNotFoundError{}, err)
+}
+
+func TestRestoreOfSubtaskWithDeletedParent(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	trashService, m := setupService(mockCtrl)
+	subtask := deletedTask(6, 1)
+	subtask.Parent = &models.Task{ID: 5}
+
+	gomock.InOrder(
+		m.taskRepo.EXPECT().GetDeletedByID(ctx, int64(6)).Return(subtask, nil),
+		m.taskRepo.EXPECT().GetDeletedByID(ctx, int64(5)).Return(deletedTask(5, 1), nil),
+	)
+
+	_, err := trashService.Restore(ctx, 6, 1)
+
+	assert.Equal(&todoErr.InvalidValueError{
+		Resource: "task",
+		Field:    "parent",
+		Reason:   "Parent task should be restored first",
+	}, err)
+}
+
+func TestDelete(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	trashService, m := setupService(mockCtrl)
+	attachments := []*models.Attachment{{ID: 1, StorageKey: "tasks/5/a"}, {ID: 2, StorageKey: "tasks/6/b"}}
+
+	gomock.InOrder(
+		m.taskRepo.EXPECT().GetDeletedByID(ctx, int64(5)).Return(deletedTask(5, 1), nil),
+		m.attachmentRepo.EXPECT().GetAllByTaskIDs(ctx, []int64{5}).Return(attachments, nil),
+		m.taskRepo.EXPECT().Purge(ctx, []int64{5}).Return(nil),
+		m.store.EXPECT().Delete(ctx, "tasks/5/a").Return(storage.ErrNotFound),
+		m.store.EXPECT().Delete(ctx, "tasks/6/b").Return(nil),
+	)
+
+	assert.NoError(trashService.Delete(ctx, 5, 1))
+}
+
+func TestDeleteKeepsBlobsOnFailure(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	trashService, m := setupService(mockCtrl)
+
+	gomock.InOrder(
+		m.taskRepo.EXPECT().GetDeletedByID(ctx, int64(5)).Return(deletedTask(5, 1), nil),
+		m.attachmentRepo.EXPECT().GetAllByTaskIDs(ctx, []int64{5}).Return([]*models.Attachment{{StorageKey: "tasks/5/a"}}, nil),
+		m.taskRepo.EXPECT().Purge(ctx, []int64{5}).Return(errors.New("db down")),
+	)
+
+	assert.EqualError(trashService.Delete(ctx, 5, 1), "db down")
+}
+
+func TestEmpty(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	trashService, m := setupService(mockCtrl)
+
+	gomock.InOrder(
+		m.taskRepo.EXPECT().GetTrash(ctx, int64(1)).Return([]*models.Task{deletedTask(5, 1), deletedTask(7, 1)}, nil),
+		m.attachmentRepo.EXPECT().GetAllByTaskIDs(ctx, []int64{5, 7}).Return([]*models.Attachment{}, nil),
+		m.taskRepo.EXPECT().Purge(ctx, []int64{5, 7}).Return(nil),
+	)
+
+	assert.NoError(trashService.Empty(ctx, 1))
+}
+
+func TestEmptyWithNoTasks(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	trashService, m := setupService(mockCtrl)
+
+	m.taskRepo.EXPECT().GetTrash(ctx, int64(1)).Return([]*models.Task{}, nil)
+
+	assert.NoError(trashService.Empty(ctx, 1))
+}
+
+func TestPurge(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	trashService, m := setupService(mockCtrl)
+	before := time.Now()
+
+	gomock.InOrder(
+		m.taskRepo.EXPECT().GetPurgeable(ctx, before, 100).Return([]int64{2, 3}, nil),
+		m.attachmentRepo.EXPECT().GetAllByTaskIDs(ctx, []int64{2, 3}).Return([]*models.Attachment{}, nil),
+		m.taskRepo.EXPECT().Purge(ctx, []int64{2, 3}).Return(nil),
+		m.userRepo.EXPECT().GetPurgeable(ctx, before, 100).Return([]int64{9}, nil),
+		m.attachmentRepo.EXPECT().GetAllByUserID(ctx, int64(9)).Return([]*models.Attachment{{StorageKey: "tasks/4/c"}}, nil),
+		m.userRepo.EXPECT().Purge(ctx, int64(9)).Return(nil),
+		m.store.EXPECT().Delete(ctx, "tasks/4/c").Return(nil),
+	)
+
+	purged, err := trashService.Purge(ctx, before)
+
+	assert.NoError(err)
+	assert.Equal(3, purged)
+}
+
+func TestPurgeWithNothingToPurge(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+
+	defer mockCtrl.Finish()
+
+	trashService, m := setupService(mockCtrl)
+	before := time.Now()
+
+	gomock.InOrder(
+		m.taskRepo.EXPECT().GetPurgeable(ctx, before, 100).Return([]int64{}, nil),
+		m.userRepo.EXPECT().GetPurgeable(ctx, before, 100).Return([]int64{}, nil),
+	)
+
+	purged, err := trashService.Purge(ctx, before)
+
+	assert.NoError(err)
+	assert.Equal(0, purged)
+}
@@ -119,3 +119,207 @@ func (body *LoginRequest) ValidateAndBuild() []*todoErr.APIErrorBody {
 
 	return validationErrors
 }
+
+// EmailRequest represents request body for POST /users/verification and POST /users/password-reset APIs
+type EmailRequest struct {
+	Email string `json:"email"`
+}
+
+// Validate validates the request body for POST /users/verification and POST /users/password-reset APIs
+func (body *EmailRequest) Validate() []*todoErr.APIErrorBody {
+	validationErrors := make([]*todoErr.APIErrorBody, 0)
+
+	body.Email = strings.TrimSpace(body.Email)
+
+	if body.Email == "" {
+		validationErrors = append(validationErrors, &todoErr.APIErrorBody{
+			Message: "Non-empty value is required",
+			Target:  "email",
+		})
+	} else if validator.New().Var(body.Email, "email") != nil {
+		validationErrors = append(validationErrors, &todoErr.APIErrorBody{
+			Message: "Invalid value",
+			Target:  "email",
+		})
+	}
+
+	return validationErrors
+}
+
+// VerifyRequest represents request body for POST /users/verification/confirm and POST /users/email-change/confirm APIs
+type VerifyRequest struct {
+	Token string `json:"token"`
+}
+
+// Validate validates the request body for POST /users/verification/confirm and POST /users/email-change/confirm APIs
+func (body *VerifyRequest) Validate() []*todoErr.APIErrorBody {
+	validationErrors := make([]*todoErr.APIErrorBody, 0)
+
+	body.Token = strings.TrimSpace(body.Token)
+
+	if body.Token == "" {
+		validationErrors = append(validationErrors, &todoErr.APIErrorBody{
+			Message: "Non-empty value is required",
+			Target:  "token",
+		})
+	}
+
+	return validationErrors
+}
+
+// ResetPasswordRequest represents request body for POST /users/password-reset/confirm API
+type ResetPasswordRequest struct {
+	Token    string `json:"token"`
+	Password string `json:"password"`
+}
+
+// Validate validates the request body for POST /users/password-reset/confirm API.
+// New password follows the same rules as the password of a new user.
+func (body *ResetPasswordRequest) Validate() []*todoErr.APIErrorBody {
+	validationErrors := make([]*todoErr.APIErrorBody, 0)
+
+	body.Token = strings.TrimSpace(body.Token)
+
+	if body.Token == "" {
+		validationErrors = append(validationErrors, &todoErr.APIErrorBody{
+			Message: "Non-empty value is required",
+			Target:  "token",
+		})
+	}
+
+	trimmedPswd := strings.TrimSpace(body.Password)
+
+	if trimmedPswd == "" {
+		validationErrors = append(validationErrors, &todoErr.APIErrorBody{
+			Message: "Non-empty value is required",
+			Target:  "password",
+		})
+	} else if len(trimmedPswd) < 6 {
+		validationErrors = append(validationErrors, &todoErr.APIErrorBody{
+			Message: "Length should be 6 or more",
+			Target:  "password",
+		})
+	}
+
+	return validationErrors
+}
+
+// MfaLoginRequest represents request body for POST /login/mfa API
+type MfaLoginRequest struct {
+	ChallengeToken string `json:"challengeToken"`
+	Code           string `json:"code"`
+}
+
+// Validate validates the request body for POST /login/mfa API
+func (body *MfaLoginRequest) Validate() []*todoErr.APIErrorBody {
+	validationErrors := make([]*todoErr.APIErrorBody, 0)
+
+	body.ChallengeToken = strings.TrimSpace(body.ChallengeToken)
+	body.Code = strings.TrimSpace(body.Code)
+
+	if body.ChallengeToken == "" {
+		validationErrors = append(validationErrors, &todoErr.APIErrorBody{
+			Message: "Non-empty value is required",
+			Target:  "challengeToken",
+		})
+	}
+
+	if body.Code == "" {
+		validationErrors = append(validationErrors, &todoErr.APIErrorBody{
+			Message: "Non-empty value is required",
+			Target:  "code",
+		})
+	}
+
+	return validationErrors
+}
+
+// UpdateUserRequest represents request body for PATCH /users/me API
+type UpdateUserRequest struct {
+	Name string `json:"name"`
+}
+
+// Validate validates the request body for PATCH /users/me API
+func (body *UpdateUserRequest) Validate() []*todoErr.APIErrorBody {
+	validationErrors := make([]*todoErr.APIErrorBody, 0)
+
+	body.Name = strings.TrimSpace(body.Name)
+
+	if body.Name == "" {
+		validationErrors = append(validationErrors, &todoErr.APIErrorBody{
+			Message: "Non-empty value is required",
+			Target:  "name",
+		})
+	}
+
+	return validationErrors
+}
+
+// ChangePasswordRequest represents request body for POST /users/me/password API
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"currentPassword"`
+	NewPassword     string `json:"newPassword"`
+}
+
+// Validate validates the request body for POST /users/me/password API.
+// New password follows the same rules as the password of a new user.
+func (body *ChangePasswordRequest) Validate() []*todoErr.APIErrorBody {
+	validationErrors := make([]*todoErr.APIErrorBody, 0)
+
+	if strings.TrimSpace(body.CurrentPassword) == "" {
+		validationErrors = append(validationErrors, &todoErr.APIErrorBody{
+			Message: "Non-empty value is required",
+			Target:  "currentPassword",
+		})
+	}
+
+	trimmedPswd := strings.TrimSpace(body.NewPassword)
+
+	if trimmedPswd == "" {
+		validationErrors = append(validationErrors, &todoErr.APIErrorBody{
+			Message: "Non-empty value is required",
+			Target:  "newPassword",
+		})
+	} else if len(trimmedPswd) < 6 {
+		validationErrors = append(validationErrors, &todoErr.APIErrorBody{
+			Message: "Length should be 6 or more",
+			Target:  "newPassword",
+		})
+	}
+
+	return validationErrors
+}
+
+// ChangeEmailRequest represents request body for POST /users/me/email API
+type ChangeEmailRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+// Validate validates the request body for POST /users/me/email API
+func (body *ChangeEmailRequest) Validate() []*todoErr.APIErrorBody {
+	validationErrors := make([]*todoErr.APIErrorBody, 0)
+
+	body.Email = strings.TrimSpace(body.Email)
+
+	if body.Email == "" {
+		validationErrors = append(validationErrors, &todoErr.APIErrorBody{
+			Message: "Non-empty value is required",
+			Target:  "email",
+		})
+	} else if validator.New().Var(body.Email, "email") != nil {
+		validationErrors = append(validationErrors, &todoErr.APIErrorBody{
+			Message: "Invalid value",
+			Target:  "email",
+		})
+	}
+
+	if strings.TrimSpace(body.Password) == "" {
+		validationErrors = append(validationErrors, &todoErr.APIErrorBody{
+			Message: "Non-empty value is required",
+			Target:  "password",
+		})
+	}
+
+	return validationErrors
+}
@@ -1,11 +1,44 @@
 package http
 
+import (
+	"time"
+
+	"github.com/dheerajgopi/todo-api/models"
+)
+
 // CreateUserResponse represents response for POST /users API
 type CreateUserResponse struct {
 	User *UserData `json:"user"`
 }
 
-// LoginResponse represents response for POST /login API
+// UserResponse represents response for GET /users/me and PATCH /users/me APIs
+type UserResponse struct {
+	User *UserData `json:"user"`
+}
+
+// LoginResponse represents response for POST /login and POST /login/mfa APIs
 type LoginResponse struct {
-	Token string `json:"token"`
+	Token            string    `json:"token"`
+	ExpiresAt        time.Time `json:"expiresAt"`
+	RefreshToken     string    `json:"refreshToken"`
+	RefreshExpiresAt time.Time `json:"refreshExpiresAt"`
+}
+
+// MfaChallengeResponse represents response for POST /login API, when the user has enabled two-factor authentication
+type MfaChallengeResponse struct {
+	MfaRequired        bool      `json:"mfaRequired"`
+	ChallengeToken     string    `json:"challengeToken"`
+	ChallengeExpiresAt time.Time `json:"challengeExpiresAt"`
+}
+
+// newUserData returns the json structure of an user
+func newUserData(user *models.User) *UserData {
+	return &UserData{
+		ID:        user.ID,
+		Name:      user.Name,
+		Email:     user.Email,
+		IsActive:  user.IsActive,
+		CreatedAt: user.CreatedAt,
+		UpdatedAt: user.UpdatedAt,
+	}
 }
@@ -3,35 +3,68 @@ package http
 import (
 	"context"
 	"encoding/json"
+	"math"
+	"net"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/dheerajgopi/todo-api/auth"
 	"github.com/dheerajgopi/todo-api/common"
 	todoErr "github.com/dheerajgopi/todo-api/common/error"
+	"github.com/dheerajgopi/todo-api/common/middlewares"
+	"github.com/dheerajgopi/todo-api/history"
+	"github.com/dheerajgopi/todo-api/limiter"
+	"github.com/dheerajgopi/todo-api/mfa"
 	"github.com/dheerajgopi/todo-api/models"
 
 	"github.com/dheerajgopi/todo-api/user"
 	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
 )
 
-// UserHandler represents HTTP handler for users
+// UserHandler represents HTTP handler for users.
+// Failed logins are tracked per account and per IP address by the login limiter.
 type UserHandler struct {
-	UserService user.Service
-	App         *common.App
+	UserService  user.Service
+	AuthService  auth.Service
+	MfaService   mfa.Service
+	LoginLimiter *limiter.Limiter
+	App          *common.App
 }
 
 // New creates new HTTP handler for user
-func New(router *mux.Router, service user.Service, app *common.App) {
+func New(router *mux.Router, service user.Service, authService auth.Service, mfaService mfa.Service, loginLimiter *limiter.Limiter, app *common.App) {
 	handler := &UserHandler{
-		UserService: service,
-		App:         app,
+		UserService:  service,
+		AuthService:  authService,
+		MfaService:   mfaService,
+		LoginLimiter: loginLimiter,
+		App:          app,
 	}
 
 	router.HandleFunc("/users", app.CreateHandler(handler.Create)).Methods("POST")
 	router.HandleFunc("/login", app.CreateHandler(handler.Login)).Methods("POST")
+	router.HandleFunc("/login/mfa", app.CreateHandler(handler.LoginMfa)).Methods("POST")
+	router.HandleFunc("/users/verification", app.CreateHandler(handler.RequestVerification)).Methods("POST")
+	router.HandleFunc("/users/verification/confirm", app.CreateHandler(handler.Verify)).Methods("POST")
+	router.HandleFunc("/users/password-reset", app.CreateHandler(handler.RequestPasswordReset)).Methods("POST")
+	router.HandleFunc("/users/password-reset/confirm", app.CreateHandler(handler.ResetPassword)).Methods("POST")
+	router.HandleFunc("/users/email-change/confirm", app.CreateHandler(handler.ChangeEmail)).Methods("POST")
+
+	jwtMiddleware := middlewares.UnverifiedJwtValidator(app)
+
+	router.HandleFunc("/users/me", app.CreateHandler(jwtMiddleware(handler.Get))).Methods("GET")
+	router.HandleFunc("/users/me", app.CreateHandler(jwtMiddleware(handler.Update))).Methods("PATCH")
+	router.HandleFunc("/users/me", app.CreateHandler(jwtMiddleware(handler.Delete))).Methods("DELETE")
+	router.HandleFunc("/users/me/password", app.CreateHandler(jwtMiddleware(handler.ChangePassword))).Methods("POST")
+	router.HandleFunc("/users/me/email", app.CreateHandler(jwtMiddleware(handler.RequestEmailChange))).Methods("POST")
+	router.HandleFunc("/users/me/deactivate", app.CreateHandler(jwtMiddleware(handler.Deactivate))).Methods("POST")
 }
 
-// Create will store new user
+// Create will store new user, and mail a verification token to the user.
+// Failing to send the mail does not fail the sign up, since the user can ask for another mail.
 func (handler *UserHandler) Create(res http.ResponseWriter, req *http.Request, reqCtx *common.RequestContext) (int, interface{}, *todoErr.APIError) {
 	timeoutInSec := time.Duration(handler.App.Config.Application.RequestTimeout) * time.Second
 	timeoutContext, cancel := context.WithTimeout(context.TODO(), timeoutInSec)
@@ -66,7 +99,7 @@ func (handler *UserHandler) Create(res http.ResponseWriter, req *http.Request, r
 		Name:      createUserReqBody.Name,
 		Email:     createUserReqBody.Email,
 		Passwd:    createUserReqBody.Password,
-		IsActive:  true,
+		IsActive:  false,
 		CreatedAt: now,
 		UpdatedAt: now,
 	}
@@ -91,23 +124,23 @@ func (handler *UserHandler) Create(res http.ResponseWriter, req *http.Request, r
 		return http.StatusInternalServerError, nil, apiError
 	}
 
-	userData := UserData{
-		ID:        newUser.ID,
-		Name:      newUser.Name,
-		Email:     newUser.Email,
-		IsActive:  newUser.IsActive,
-		CreatedAt: newUser.CreatedAt,
-		UpdatedAt: newUser.UpdatedAt,
+	if err = handler.UserService.RequestVerification(timeoutContext, newUser.Email); err != nil {
+		reqCtx.AddLogFields(logrus.Fields{
+			"verificationError": err.Error(),
+		})
 	}
 
 	responseData := &CreateUserResponse{
-		User: &userData,
+		User: newUserData(&newUser),
 	}
 
 	return http.StatusCreated, responseData, nil
 }
 
-// Login will validate user credentials and return a token
+// Login will validate user credentials and return an access token, along with a refresh token.
+// If the user has enabled two-factor authentication, a MFA challenge is returned instead, which POST /login/mfa exchanges.
+// Unknown email and wrong password fail alike, so that emails can not be probed.
+// Repeated failures of an account or an IP address delay further attempts, and eventually lock them out for a while.
 func (handler *UserHandler) Login(res http.ResponseWriter, req *http.Request, reqCtx *common.RequestContext) (int, interface{}, *todoErr.APIError) {
 	timeoutInSec := time.Duration(handler.App.Config.Application.RequestTimeout) * time.Second
 	timeoutContext, cancel := context.WithTimeout(context.TODO(), timeoutInSec)
@@ -136,14 +169,231 @@ func (handler *UserHandler) Login(res http.ResponseWriter, req *http.Request, re
 		return http.StatusBadRequest, nil, apiError
 	}
 
-	token, err := handler.UserService.GenerateAuthToken(
-		timeoutContext,
-		loginReqBody.Email,
-		loginReqBody.Passwd,
-		handler.App.Config.Auth.Jwt.Secret,
-	)
+	loginSetting := handler.App.Config.Auth.Login
+	accountKey := "account:" + strings.ToLower(loginReqBody.Email)
+	ipKey := "ip:" + clientIP(req, loginSetting.ClientIPHeader)
+
+	retryAt, err := handler.LoginLimiter.Check(timeoutContext, accountKey, ipKey)
+
+	if err != nil {
+		apiError := todoErr.NewAPIError(err.Error(), &todoErr.APIErrorBody{
+			Message: "Internal server error",
+		})
+
+		return http.StatusInternalServerError, nil, apiError
+	}
+
+	if !retryAt.IsZero() {
+		return throttled(res, reqCtx, retryAt)
+	}
+
+	authenticatedUser, err := handler.UserService.Authenticate(timeoutContext, loginReqBody.Email, loginReqBody.Passwd)
 
 	switch err.(type) {
+	case nil:
+		break
+	case *todoErr.ResourceNotFoundError, *todoErr.PasswordMismatchError:
+		accountRule := limiter.Rule{
+			FreeAttempts:    loginSetting.AccountFreeAttempts,
+			LockoutAttempts: loginSetting.AccountLockoutAttempts,
+		}
+
+		ipRule := limiter.Rule{
+			FreeAttempts:    loginSetting.IPFreeAttempts,
+			LockoutAttempts: loginSetting.IPLockoutAttempts,
+		}
+
+		if apiError := handler.failLogin(timeoutContext, reqCtx, accountKey, accountRule); apiError != nil {
+			return http.StatusInternalServerError, nil, apiError
+		}
+
+		if apiError := handler.failLogin(timeoutContext, reqCtx, ipKey, ipRule); apiError != nil {
+			return http.StatusInternalServerError, nil, apiError
+		}
+
+		apiError := todoErr.NewAPIError(err.Error(), &todoErr.APIErrorBody{
+			Message: "Invalid email/password",
+		})
+
+		return http.StatusForbidden, nil, apiError
+	case *todoErr.PermissionDeniedError:
+		permissionDeniedErr, _ := err.(*todoErr.PermissionDeniedError)
+
+		apiError := todoErr.NewAPIError(permissionDeniedErr.Error(), &todoErr.APIErrorBody{
+			Message: "Email not verified",
+			Target:  "email",
+		})
+
+		return http.StatusForbidden, nil, apiError
+	default:
+		apiError := todoErr.NewAPIError(err.Error(), &todoErr.APIErrorBody{
+			Message: "Internal server error",
+		})
+
+		return http.StatusInternalServerError, nil, apiError
+	}
+
+	// failures of the IP address are kept, since a successful login to an own account should not allow more guesses
+	if err = handler.LoginLimiter.Reset(timeoutContext, accountKey); err != nil {
+		apiError := todoErr.NewAPIError(err.Error(), &todoErr.APIErrorBody{
+			Message: "Internal server error",
+		})
+
+		return http.StatusInternalServerError, nil, apiError
+	}
+
+	if authenticatedUser.TotpEnabled {
+		challenge, err := handler.AuthService.IssueChallenge(timeoutContext, authenticatedUser)
+
+		if err != nil {
+			apiError := todoErr.NewAPIError(err.Error(), &todoErr.APIErrorBody{
+				Message: "Internal server error",
+			})
+
+			return http.StatusInternalServerError, nil, apiError
+		}
+
+		challengeResponse := MfaChallengeResponse{
+			MfaRequired:        true,
+			ChallengeToken:     challenge.Token,
+			ChallengeExpiresAt: challenge.ExpiresAt,
+		}
+
+		return http.StatusOK, challengeResponse, nil
+	}
+
+	return handler.issueTokens(timeoutContext, authenticatedUser)
+}
+
+// LoginMfa will exchange a MFA challenge, along with a TOTP or recovery code of its user, for an access token and a refresh token.
+// Wrong codes are tracked per user by the login limiter, like wrong passwords.
+func (handler *UserHandler) LoginMfa(res http.ResponseWriter, req *http.Request, reqCtx *common.RequestContext) (int, interface{}, *todoErr.APIError) {
+	timeoutInSec := time.Duration(handler.App.Config.Application.RequestTimeout) * time.Second
+	timeoutContext, cancel := context.WithTimeout(context.TODO(), timeoutInSec)
+	defer cancel()
+	defer req.Body.Close()
+
+	var mfaReqBody MfaLoginRequest
+
+	if status, apiError := decodeAndValidate(req, reqCtx, &mfaReqBody); apiError != nil {
+		return status, nil, apiError
+	}
+
+	challenge, err := handler.AuthService.ParseChallenge(timeoutContext, mfaReqBody.ChallengeToken)
+
+	switch err.(type) {
+	case nil:
+		break
+	case *todoErr.UnauthorizedError:
+		apiError := todoErr.NewAPIError(err.Error(), &todoErr.APIErrorBody{
+			Message: "Access denied",
+			Target:  "challengeToken",
+		})
+
+		return http.StatusForbidden, nil, apiError
+	default:
+		apiError := todoErr.NewAPIError(err.Error(), &todoErr.APIErrorBody{
+			Message: "Internal server error",
+		})
+
+		return http.StatusInternalServerError, nil, apiError
+	}
+
+	loginSetting := handler.App.Config.Auth.Login
+	mfaKey := "mfa:" + strconv.FormatInt(challenge.UserID, 10)
+
+	retryAt, err := handler.LoginLimiter.Check(timeoutContext, mfaKey)
+
+	if err != nil {
+		apiError := todoErr.NewAPIError(err.Error(), &todoErr.APIErrorBody{
+			Message: "Internal server error",
+		})
+
+		return http.StatusInternalServerError, nil, apiError
+	}
+
+	if !retryAt.IsZero() {
+		return throttled(res, reqCtx, retryAt)
+	}
+
+	authenticatedUser, err := handler.MfaService.Verify(timeoutContext, challenge.UserID, mfaReqBody.Code)
+
+	switch err.(type) {
+	case nil:
+		break
+	case *todoErr.InvalidValueError:
+		mfaRule := limiter.Rule{
+			FreeAttempts:    loginSetting.AccountFreeAttempts,
+			LockoutAttempts: loginSetting.AccountLockoutAttempts,
+		}
+
+		if apiError := handler.failLogin(timeoutContext, reqCtx, mfaKey, mfaRule); apiError != nil {
+			return http.StatusInternalServerError, nil, apiError
+		}
+
+		apiError := todoErr.NewAPIError(err.Error(), &todoErr.APIErrorBody{
+			Message: "Invalid code",
+			Target:  "code",
+		})
+
+		return http.StatusForbidden, nil, apiError
+	default:
+		apiError := todoErr.NewAPIError(err.Error(), &todoErr.APIErrorBody{
+			Message: "Internal server error",
+		})
+
+		return http.StatusInternalServerError, nil, apiError
+	}
+
+	if err = handler.LoginLimiter.Reset(timeoutContext, mfaKey); err == nil {
+		err = handler.AuthService.RedeemChallenge(timeoutContext, challenge)
+	}
+
+	if err != nil {
+		apiError := todoErr.NewAPIError(err.Error(), &todoErr.APIErrorBody{
+			Message: "Internal server error",
+		})
+
+		return http.StatusInternalServerError, nil, apiError
+	}
+
+	return handler.issueTokens(timeoutContext, authenticatedUser)
+}
+
+// issueTokens responds with a new access token and refresh token of the user
+func (handler *UserHandler) issueTokens(ctx context.Context, authenticatedUser *models.User) (int, interface{}, *todoErr.APIError) {
+	tokens, err := handler.AuthService.Issue(ctx, authenticatedUser)
+
+	if err != nil {
+		apiError := todoErr.NewAPIError(err.Error(), &todoErr.APIErrorBody{
+			Message: "Internal server error",
+		})
+
+		return http.StatusInternalServerError, nil, apiError
+	}
+
+	loginResponse := LoginResponse{
+		Token:            tokens.AccessToken,
+		ExpiresAt:        tokens.AccessExpiresAt,
+		RefreshToken:     tokens.RefreshToken,
+		RefreshExpiresAt: tokens.RefreshExpiresAt,
+	}
+
+	return http.StatusOK, loginResponse, nil
+}
+
+// Delete will delete the account of the logged in user. Access tokens of the user are revoked right away.
+func (handler *UserHandler) Delete(res http.ResponseWriter, req *http.Request, reqCtx *common.RequestContext) (int, interface{}, *todoErr.APIError) {
+	timeoutInSec := time.Duration(handler.App.Config.Application.RequestTimeout) * time.Second
+	timeoutContext, cancel := context.WithTimeout(context.TODO(), timeoutInSec)
+	defer cancel()
+
+	ctx := history.WithActor(timeoutContext, &history.Actor{
+		UserID:    reqCtx.UserID,
+		RequestID: reqCtx.RequestID,
+	})
+
+	switch err := handler.UserService.Delete(ctx, reqCtx.UserID); err.(type) {
 	case nil:
 		break
 	case *todoErr.ResourceNotFoundError:
@@ -155,15 +405,275 @@ func (handler *UserHandler) Login(res http.ResponseWriter, req *http.Request, re
 		})
 
 		return http.StatusNotFound, nil, apiError
+	default:
+		apiError := todoErr.NewAPIError(err.Error(), &todoErr.APIErrorBody{
+			Message: "Internal server error",
+		})
+
+		return http.StatusInternalServerError, nil, apiError
+	}
+
+	if err := handler.AuthService.RevokeUser(timeoutContext, reqCtx.UserID); err != nil {
+		apiError := todoErr.NewAPIError(err.Error(), &todoErr.APIErrorBody{
+			Message: "Internal server error",
+		})
+
+		return http.StatusInternalServerError, nil, apiError
+	}
+
+	return http.StatusOK, nil, nil
+}
+
+// RequestVerification will mail a new verification token, if the email belongs to an user who has not verified it yet.
+// Response is the same for any email, so that emails can not be probed.
+func (handler *UserHandler) RequestVerification(res http.ResponseWriter, req *http.Request, reqCtx *common.RequestContext) (int, interface{}, *todoErr.APIError) {
+	timeoutInSec := time.Duration(handler.App.Config.Application.RequestTimeout) * time.Second
+	timeoutContext, cancel := context.WithTimeout(context.TODO(), timeoutInSec)
+	defer cancel()
+	defer req.Body.Close()
+
+	var emailReqBody EmailRequest
+
+	if status, apiError := decodeAndValidate(req, reqCtx, &emailReqBody); apiError != nil {
+		return status, nil, apiError
+	}
+
+	if err := handler.UserService.RequestVerification(timeoutContext, emailReqBody.Email); err != nil {
+		return userServiceError(err)
+	}
+
+	return http.StatusOK, nil, nil
+}
+
+// Verify will activate the user of a verification token
+func (handler *UserHandler) Verify(res http.ResponseWriter, req *http.Request, reqCtx *common.RequestContext) (int, interface{}, *todoErr.APIError) {
+	timeoutInSec := time.Duration(handler.App.Config.Application.RequestTimeout) * time.Second
+	timeoutContext, cancel := context.WithTimeout(context.TODO(), timeoutInSec)
+	defer cancel()
+	defer req.Body.Close()
+
+	var verifyReqBody VerifyRequest
+
+	if status, apiError := decodeAndValidate(req, reqCtx, &verifyReqBody); apiError != nil {
+		return status, nil, apiError
+	}
+
+	if err := handler.UserService.Verify(timeoutContext, verifyReqBody.Token); err != nil {
+		return userServiceError(err)
+	}
+
+	return http.StatusOK, nil, nil
+}
+
+// RequestPasswordReset will mail a password reset token, if the email belongs to an user.
+// Response is the same for any email, so that emails can not be probed.
+func (handler *UserHandler) RequestPasswordReset(res http.ResponseWriter, req *http.Request, reqCtx *common.RequestContext) (int, interface{}, *todoErr.APIError) {
+	timeoutInSec := time.Duration(handler.App.Config.Application.RequestTimeout) * time.Second
+	timeoutContext, cancel := context.WithTimeout(context.TODO(), timeoutInSec)
+	defer cancel()
+	defer req.Body.Close()
+
+	var emailReqBody EmailRequest
+
+	if status, apiError := decodeAndValidate(req, reqCtx, &emailReqBody); apiError != nil {
+		return status, nil, apiError
+	}
+
+	if err := handler.UserService.RequestPasswordReset(timeoutContext, emailReqBody.Email); err != nil {
+		return userServiceError(err)
+	}
+
+	return http.StatusOK, nil, nil
+}
+
+// ResetPassword will replace the password of the user of a password reset token
+func (handler *UserHandler) ResetPassword(res http.ResponseWriter, req *http.Request, reqCtx *common.RequestContext) (int, interface{}, *todoErr.APIError) {
+	timeoutInSec := time.Duration(handler.App.Config.Application.RequestTimeout) * time.Second
+	timeoutContext, cancel := context.WithTimeout(context.TODO(), timeoutInSec)
+	defer cancel()
+	defer req.Body.Close()
+
+	var resetReqBody ResetPasswordRequest
+
+	if status, apiError := decodeAndValidate(req, reqCtx, &resetReqBody); apiError != nil {
+		return status, nil, apiError
+	}
+
+	if err := handler.UserService.ResetPassword(timeoutContext, resetReqBody.Token, resetReqBody.Password); err != nil {
+		return userServiceError(err)
+	}
+
+	return http.StatusOK, nil, nil
+}
+
+// Get will return the profile of the logged in user
+func (handler *UserHandler) Get(res http.ResponseWriter, req *http.Request, reqCtx *common.RequestContext) (int, interface{}, *todoErr.APIError) {
+	timeoutInSec := time.Duration(handler.App.Config.Application.RequestTimeout) * time.Second
+	timeoutContext, cancel := context.WithTimeout(context.TODO(), timeoutInSec)
+	defer cancel()
+
+	existingUser, err := handler.UserService.Get(timeoutContext, reqCtx.UserID)
+
+	if err != nil {
+		return userServiceError(err)
+	}
+
+	return http.StatusOK, &UserResponse{User: newUserData(existingUser)}, nil
+}
+
+// Update will change the name of the logged in user
+func (handler *UserHandler) Update(res http.ResponseWriter, req *http.Request, reqCtx *common.RequestContext) (int, interface{}, *todoErr.APIError) {
+	timeoutInSec := time.Duration(handler.App.Config.Application.RequestTimeout) * time.Second
+	timeoutContext, cancel := context.WithTimeout(context.TODO(), timeoutInSec)
+	defer cancel()
+	defer req.Body.Close()
+
+	var updateReqBody UpdateUserRequest
+
+	if status, apiError := decodeAndValidate(req, reqCtx, &updateReqBody); apiError != nil {
+		return status, nil, apiError
+	}
+
+	existingUser, err := handler.UserService.Get(timeoutContext, reqCtx.UserID)
+
+	if err != nil {
+		return userServiceError(err)
+	}
+
+	existingUser.Name = updateReqBody.Name
+
+	if err = handler.UserService.Update(timeoutContext, existingUser); err != nil {
+		return userServiceError(err)
+	}
+
+	return http.StatusOK, &UserResponse{User: newUserData(existingUser)}, nil
+}
+
+// ChangePassword will replace the password of the logged in user, after validating the current password.
+// Other sessions of the user are logged out.
+func (handler *UserHandler) ChangePassword(res http.ResponseWriter, req *http.Request, reqCtx *common.RequestContext) (int, interface{}, *todoErr.APIError) {
+	timeoutInSec := time.Duration(handler.App.Config.Application.RequestTimeout) * time.Second
+	timeoutContext, cancel := context.WithTimeout(context.TODO(), timeoutInSec)
+	defer cancel()
+	defer req.Body.Close()
+
+	var changeReqBody ChangePasswordRequest
+
+	if status, apiError := decodeAndValidate(req, reqCtx, &changeReqBody); apiError != nil {
+		return status, nil, apiError
+	}
+
+	return handler.passwordAttempt(timeoutContext, res, reqCtx, "currentPassword", func() error {
+		return handler.UserService.ChangePassword(
+			timeoutContext, reqCtx.UserID, reqCtx.SessionID, changeReqBody.CurrentPassword, changeReqBody.NewPassword,
+		)
+	})
+}
+
+// RequestEmailChange will mail an email change token to the new email, after validating the password of the logged in user.
+// Email is changed once the token is confirmed with POST /users/email-change/confirm.
+func (handler *UserHandler) RequestEmailChange(res http.ResponseWriter, req *http.Request, reqCtx *common.RequestContext) (int, interface{}, *todoErr.APIError) {
+	timeoutInSec := time.Duration(handler.App.Config.Application.RequestTimeout) * time.Second
+	timeoutContext, cancel := context.WithTimeout(context.TODO(), timeoutInSec)
+	defer cancel()
+	defer req.Body.Close()
+
+	var emailReqBody ChangeEmailRequest
+
+	if status, apiError := decodeAndValidate(req, reqCtx, &emailReqBody); apiError != nil {
+		return status, nil, apiError
+	}
+
+	return handler.passwordAttempt(timeoutContext, res, reqCtx, "password", func() error {
+		return handler.UserService.RequestEmailChange(timeoutContext, reqCtx.UserID, emailReqBody.Password, emailReqBody.Email)
+	})
+}
+
+// ChangeEmail will replace the email of the user of an email change token
+func (handler *UserHandler) ChangeEmail(res http.ResponseWriter, req *http.Request, reqCtx *common.RequestContext) (int, interface{}, *todoErr.APIError) {
+	timeoutInSec := time.Duration(handler.App.Config.Application.RequestTimeout) * time.Second
+	timeoutContext, cancel := context.WithTimeout(context.TODO(), timeoutInSec)
+	defer cancel()
+	defer req.Body.Close()
+
+	var confirmReqBody VerifyRequest
+
+	if status, apiError := decodeAndValidate(req, reqCtx, &confirmReqBody); apiError != nil {
+		return status, nil, apiError
+	}
+
+	if err := handler.UserService.ChangeEmail(timeoutContext, confirmReqBody.Token); err != nil {
+		return userServiceError(err)
+	}
+
+	return http.StatusOK, nil, nil
+}
+
+// Deactivate will deactivate the account of the logged in user. Access tokens of the user are revoked right away.
+func (handler *UserHandler) Deactivate(res http.ResponseWriter, req *http.Request, reqCtx *common.RequestContext) (int, interface{}, *todoErr.APIError) {
+	timeoutInSec := time.Duration(handler.App.Config.Application.RequestTimeout) * time.Second
+	timeoutContext, cancel := context.WithTimeout(context.TODO(), timeoutInSec)
+	defer cancel()
+
+	if err := handler.UserService.Deactivate(timeoutContext, reqCtx.UserID); err != nil {
+		return userServiceError(err)
+	}
+
+	if err := handler.AuthService.RevokeUser(timeoutContext, reqCtx.UserID); err != nil {
+		apiError := todoErr.NewAPIError(err.Error(), &todoErr.APIErrorBody{
+			Message: "Internal server error",
+		})
+
+		return http.StatusInternalServerError, nil, apiError
+	}
+
+	return http.StatusOK, nil, nil
+}
+
+// passwordAttempt runs an action which validates the password of the logged in user.
+// Wrong passwords are tracked per user by the login limiter, so that a stolen access token can not be used to guess the password.
+func (handler *UserHandler) passwordAttempt(ctx context.Context, res http.ResponseWriter, reqCtx *common.RequestContext, target string, attempt func() error) (int, interface{}, *todoErr.APIError) {
+	loginSetting := handler.App.Config.Auth.Login
+	passwordKey := "password:" + strconv.FormatInt(reqCtx.UserID, 10)
+
+	retryAt, err := handler.LoginLimiter.Check(ctx, passwordKey)
+
+	if err != nil {
+		apiError := todoErr.NewAPIError(err.Error(), &todoErr.APIErrorBody{
+			Message: "Internal server error",
+		})
+
+		return http.StatusInternalServerError, nil, apiError
+	}
+
+	if !retryAt.IsZero() {
+		return throttled(res, reqCtx, retryAt)
+	}
+
+	switch err = attempt(); err.(type) {
+	case nil:
+		break
 	case *todoErr.PasswordMismatchError:
-		passwordMismatchErr, _ := err.(*todoErr.PasswordMismatchError)
+		passwordRule := limiter.Rule{
+			FreeAttempts:    loginSetting.AccountFreeAttempts,
+			LockoutAttempts: loginSetting.AccountLockoutAttempts,
+		}
 
-		apiError := todoErr.NewAPIError(passwordMismatchErr.Error(), &todoErr.APIErrorBody{
-			Message: "Invalid email/password",
+		if apiError := handler.failLogin(ctx, reqCtx, passwordKey, passwordRule); apiError != nil {
+			return http.StatusInternalServerError, nil, apiError
+		}
+
+		apiError := todoErr.NewAPIError(err.Error(), &todoErr.APIErrorBody{
+			Message: "Invalid password",
+			Target:  target,
 		})
 
 		return http.StatusForbidden, nil, apiError
 	default:
+		return userServiceError(err)
+	}
+
+	if err = handler.LoginLimiter.Reset(ctx, passwordKey); err != nil {
 		apiError := todoErr.NewAPIError(err.Error(), &todoErr.APIErrorBody{
 			Message: "Internal server error",
 		})
@@ -171,9 +681,118 @@ func (handler *UserHandler) Login(res http.ResponseWriter, req *http.Request, re
 		return http.StatusInternalServerError, nil, apiError
 	}
 
-	loginResponse := LoginResponse{
-		Token: token,
+	return http.StatusOK, nil, nil
+}
+
+// validatedRequest is a request body which validates itself
+type validatedRequest interface {
+	Validate() []*todoErr.APIErrorBody
+}
+
+// decodeAndValidate decodes the JSON request body and validates it
+func decodeAndValidate(req *http.Request, reqCtx *common.RequestContext, body validatedRequest) (int, *todoErr.APIError) {
+	if err := json.NewDecoder(req.Body).Decode(body); err != nil {
+		reqCtx.AddLogMessage("Invalid request body")
+		apiError := todoErr.NewAPIError("", &todoErr.APIErrorBody{
+			Message: "Invalid request body",
+		})
+
+		return http.StatusBadRequest, apiError
 	}
 
-	return http.StatusOK, loginResponse, nil
+	if validationErrors := body.Validate(); len(validationErrors) > 0 {
+		reqCtx.AddLogMessage("validation error")
+
+		return http.StatusBadRequest, todoErr.NewAPIError("", validationErrors...)
+	}
+
+	return http.StatusOK, nil
+}
+
+// userServiceError maps errors returned by the token flows and profile actions of the user service to the API response
+func userServiceError(err error) (int, interface{}, *todoErr.APIError) {
+	switch err.(type) {
+	case *todoErr.DataConflictError:
+		dataConflictErr, _ := err.(*todoErr.DataConflictError)
+
+		apiError := todoErr.NewAPIError(dataConflictErr.Error(), &todoErr.APIErrorBody{
+			Message: "Conflicting data",
+			Target:  dataConflictErr.Field,
+		})
+
+		return http.StatusConflict, nil, apiError
+	case *todoErr.ResourceNotFoundError:
+		apiError := todoErr.NewAPIError(err.Error(), &todoErr.APIErrorBody{
+			Message: "Not found",
+			Target:  "user",
+		})
+
+		return http.StatusNotFound, nil, apiError
+	case *todoErr.InvalidValueError:
+		invalidValueErr, _ := err.(*todoErr.InvalidValueError)
+
+		apiError := todoErr.NewAPIError(invalidValueErr.Error(), &todoErr.APIErrorBody{
+			Message: invalidValueErr.Reason,
+			Target:  invalidValueErr.Field,
+		})
+
+		return http.StatusBadRequest, nil, apiError
+	default:
+		apiError := todoErr.NewAPIError(err.Error(), &todoErr.APIErrorBody{
+			Message: "Internal server error",
+		})
+
+		return http.StatusInternalServerError, nil, apiError
+	}
+}
+
+// failLogin records a failed login of the key, and logs the lockout if the key got locked out
+func (handler *UserHandler) failLogin(ctx context.Context, reqCtx *common.RequestContext, key string, rule limiter.Rule) *todoErr.APIError {
+	block, err := handler.LoginLimiter.Fail(ctx, key, rule)
+
+	if err != nil {
+		return todoErr.NewAPIError(err.Error(), &todoErr.APIErrorBody{
+			Message: "Internal server error",
+		})
+	}
+
+	if block != nil && block.Lockout {
+		reqCtx.AddLogFields(logrus.Fields{
+			"lockoutKey":      block.Key,
+			"lockoutFailures": block.Failures,
+			"lockoutUntil":    block.Until,
+		})
+		reqCtx.AddLogMessage("login locked out")
+	}
+
+	return nil
+}
+
+// clientIP returns the IP address of the client, from the header if it is set by a trusted proxy
+func clientIP(req *http.Request, header string) string {
+	if header != "" {
+		if value := strings.TrimSpace(strings.Split(req.Header.Get(header), ",")[0]); value != "" {
+			return value
+		}
+	}
+
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+
+	if err != nil {
+		return req.RemoteAddr
+	}
+
+	return host
+}
+
+// throttled responds with 429 error, telling the client when to retry
+func throttled(res http.ResponseWriter, reqCtx *common.RequestContext, retryAt time.Time) (int, interface{}, *todoErr.APIError) {
+	retryAfter := int(math.Ceil(time.Until(retryAt).Seconds()))
+	res.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+	reqCtx.AddLogMessage("login attempt throttled")
+	apiError := todoErr.NewAPIError("", &todoErr.APIErrorBody{
+		Message: "Too many failed attempts, retry after " + strconv.Itoa(retryAfter) + " seconds",
+	})
+
+	return http.StatusTooManyRequests, nil, apiError
 }
@@ -1,15 +1,23 @@
 package http_test
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/dheerajgopi/todo-api/auth"
+	authMock "github.com/dheerajgopi/todo-api/auth/mock"
 	"github.com/dheerajgopi/todo-api/common"
 	_errors "github.com/dheerajgopi/todo-api/common/error"
 	"github.com/dheerajgopi/todo-api/config"
+	"github.com/dheerajgopi/todo-api/limiter"
+	"github.com/dheerajgopi/todo-api/limiter/memory"
+	mfaMock "github.com/dheerajgopi/todo-api/mfa/mock"
+	"github.com/dheerajgopi/todo-api/models"
 	"github.com/dheerajgopi/todo-api/user"
 	_userHandler "github.com/dheerajgopi/todo-api/user/delivery/http"
 	mock "github.com/dheerajgopi/todo-api/user/mock"
@@ -239,11 +247,18 @@ func TestCreate(t *testing.T) {
 	reqCtx := setupRequestContext(handler.App)
 	req := httptest.NewRequest("POST", "/users", strings.NewReader(string(payload)))
 
-	mockService.
-		EXPECT().
-		Create(gomock.Any(), gomock.Any()).
-		Return(nil).
-		Times(1)
+	gomock.InOrder(
+		mockService.
+			EXPECT().
+			Create(gomock.Any(), gomock.Any()).
+			Return(nil).
+			Times(1),
+		mockService.
+			EXPECT().
+			RequestVerification(gomock.Any(), reqBody.Email).
+			Return(nil).
+			Times(1),
+	)
 
 	status, data, err := handler.Create(httptest.NewRecorder(), req, reqCtx)
 
@@ -254,7 +269,33 @@ func TestCreate(t *testing.T) {
 	assert.Nil(err)
 	assert.Equal(reqBody.Name, responseData.User.Name)
 	assert.Equal(reqBody.Email, responseData.User.Email)
-	assert.Equal(true, responseData.User.IsActive)
+	assert.Equal(false, responseData.User.IsActive)
+}
+
+func TestCreateWithMailError(t *testing.T) {
+	reqBody := &_userHandler.CreateUserRequest{
+		Name:     "testuser",
+		Email:    "testuser@mail.com",
+		Password: "secret",
+	}
+
+	payload, _ := json.Marshal(reqBody)
+
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+	req := httptest.NewRequest("POST", "/users", strings.NewReader(string(payload)))
+
+	mockService.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+	mockService.EXPECT().RequestVerification(gomock.Any(), reqBody.Email).Return(errors.New("mail error"))
+
+	status, data, err := handler.Create(httptest.NewRecorder(), req, reqCtx)
+
+	assert.Equal(201, status)
+	assert.NotNil(data)
+	assert.Nil(err)
 }
 
 func TestLoginWithNoRequestBody(t *testing.T) {
@@ -343,7 +384,7 @@ func TestLoginWithInvalidEmail(t *testing.T) {
 	assert.Equal("email", err.Body[0].Target)
 }
 
-func TestLoginWithDataConflict(t *testing.T) {
+func TestLoginWithUnknownEmail(t *testing.T) {
 	reqBody := &_userHandler.LoginRequest{
 		Email:  "testuser@mail.com",
 		Passwd: "secret",
@@ -364,18 +405,18 @@ func TestLoginWithDataConflict(t *testing.T) {
 
 	mockService.
 		EXPECT().
-		GenerateAuthToken(gomock.Any(), reqBody.Email, reqBody.Passwd, handler.App.Config.Auth.Jwt.Secret).
-		Return("", notFoundErr).
+		Authenticate(gomock.Any(), reqBody.Email, reqBody.Passwd).
+		Return(nil, notFoundErr).
 		Times(1)
 
 	status, data, err := handler.Login(httptest.NewRecorder(), req, reqCtx)
 
-	assert.Equal(404, status)
+	assert.Equal(403, status)
 	assert.Nil(data)
 	assert.NotNil(err)
 	assert.Equal(1, len(err.Body))
-	assert.Equal("Not found", err.Body[0].Message)
-	assert.Equal("user", err.Body[0].Target)
+	assert.Equal("Invalid email/password", err.Body[0].Message)
+	assert.Equal("", err.Body[0].Target)
 }
 
 func TestLoginWithMismatchingPassword(t *testing.T) {
@@ -397,8 +438,8 @@ func TestLoginWithMismatchingPassword(t *testing.T) {
 
 	mockService.
 		EXPECT().
-		GenerateAuthToken(gomock.Any(), reqBody.Email, reqBody.Passwd, handler.App.Config.Auth.Jwt.Secret).
-		Return("", passwordMismatchErr).
+		Authenticate(gomock.Any(), reqBody.Email, reqBody.Passwd).
+		Return(nil, passwordMismatchErr).
 		Times(1)
 
 	status, data, err := handler.Login(httptest.NewRecorder(), req, reqCtx)
@@ -410,6 +451,37 @@ func TestLoginWithMismatchingPassword(t *testing.T) {
 	assert.Equal("Invalid email/password", err.Body[0].Message)
 }
 
+func TestLoginWithUnverifiedEmail(t *testing.T) {
+	reqBody := &_userHandler.LoginRequest{
+		Email:  "testuser@mail.com",
+		Passwd: "secret",
+	}
+
+	payload, _ := json.Marshal(reqBody)
+
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+	req := httptest.NewRequest("POST", "/login", strings.NewReader(string(payload)))
+
+	mockService.
+		EXPECT().
+		Authenticate(gomock.Any(), reqBody.Email, reqBody.Passwd).
+		Return(nil, &_errors.PermissionDeniedError{Resource: "user", Action: "login"}).
+		Times(1)
+
+	status, data, err := handler.Login(httptest.NewRecorder(), req, reqCtx)
+
+	assert.Equal(403, status)
+	assert.Nil(data)
+	assert.NotNil(err)
+	assert.Equal(1, len(err.Body))
+	assert.Equal("Email not verified", err.Body[0].Message)
+	assert.Equal("email", err.Body[0].Target)
+}
+
 func TestLoginWithServerError(t *testing.T) {
 	reqBody := &_userHandler.LoginRequest{
 		Email:  "testuser@mail.com",
@@ -429,8 +501,8 @@ func TestLoginWithServerError(t *testing.T) {
 
 	mockService.
 		EXPECT().
-		GenerateAuthToken(gomock.Any(), reqBody.Email, reqBody.Passwd, handler.App.Config.Auth.Jwt.Secret).
-		Return("", serverErr).
+		Authenticate(gomock.Any(), reqBody.Email, reqBody.Passwd).
+		Return(nil, serverErr).
 		Times(1)
 
 	status, data, err := handler.Login(httptest.NewRecorder(), req, reqCtx)
@@ -457,20 +529,737 @@ func TestLogin(t *testing.T) {
 	reqCtx := setupRequestContext(handler.App)
 	req := httptest.NewRequest("POST", "/login", strings.NewReader(string(payload)))
 
+	mockAuthService := authMock.NewService(mockCtrl)
+	handler.AuthService = mockAuthService
+
+	existingUser := &models.User{
+		ID:    1,
+		Email: reqBody.Email,
+	}
+
+	tokens := &auth.TokenPair{
+		AccessToken:      "token",
+		AccessExpiresAt:  time.Now().Add(time.Hour),
+		RefreshToken:     "refreshToken",
+		RefreshExpiresAt: time.Now().Add(24 * time.Hour),
+	}
+
+	gomock.InOrder(
+		mockService.
+			EXPECT().
+			Authenticate(gomock.Any(), reqBody.Email, reqBody.Passwd).
+			Return(existingUser, nil).
+			Times(1),
+		mockAuthService.
+			EXPECT().
+			Issue(gomock.Any(), existingUser).
+			Return(tokens, nil).
+			Times(1),
+	)
+
+	status, data, err := handler.Login(httptest.NewRecorder(), req, reqCtx)
+
+	loginResponse := data.(_userHandler.LoginResponse)
+
+	assert.Equal(200, status)
+	assert.Nil(err)
+	assert.NotNil(data)
+	assert.Equal("token", loginResponse.Token)
+	assert.Equal(tokens.AccessExpiresAt, loginResponse.ExpiresAt)
+	assert.Equal("refreshToken", loginResponse.RefreshToken)
+	assert.Equal(tokens.RefreshExpiresAt, loginResponse.RefreshExpiresAt)
+}
+
+func TestLoginWithRepeatedFailures(t *testing.T) {
+	reqBody := &_userHandler.LoginRequest{
+		Email:  "testuser@mail.com",
+		Passwd: "secret",
+	}
+
+	payload, _ := json.Marshal(reqBody)
+
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+
 	mockService.
 		EXPECT().
-		GenerateAuthToken(gomock.Any(), reqBody.Email, reqBody.Passwd, handler.App.Config.Auth.Jwt.Secret).
-		Return("token", nil).
-		Times(1)
+		Authenticate(gomock.Any(), reqBody.Email, reqBody.Passwd).
+		Return(nil, &_errors.PasswordMismatchError{}).
+		Times(3)
+
+	for i := 0; i < 3; i++ {
+		req := httptest.NewRequest("POST", "/login", strings.NewReader(string(payload)))
+		status, _, _ := handler.Login(httptest.NewRecorder(), req, setupRequestContext(handler.App))
+
+		assert.Equal(403, status)
+	}
+
+	// third failure was past the free attempts of the account, so the next attempt is delayed
+	res := httptest.NewRecorder()
+	req := httptest.NewRequest("POST", "/login", strings.NewReader(string(payload)))
+	status, data, err := handler.Login(res, req, setupRequestContext(handler.App))
+
+	assert.Equal(429, status)
+	assert.Nil(data)
+	assert.Equal(1, len(err.Body))
+	assert.Equal("Too many failed attempts, retry after 60 seconds", err.Body[0].Message)
+	assert.Equal("60", res.Header().Get("Retry-After"))
+
+	// other accounts are not delayed from the same IP address
+	otherPayload := `{"email":"other@mail.com","password":"secret"}`
+
+	mockService.
+		EXPECT().
+		Authenticate(gomock.Any(), "other@mail.com", "secret").
+		Return(nil, &_errors.ResourceNotFoundError{Resource: "user"})
+
+	req = httptest.NewRequest("POST", "/login", strings.NewReader(otherPayload))
+	status, _, err = handler.Login(httptest.NewRecorder(), req, setupRequestContext(handler.App))
+
+	assert.Equal(403, status)
+	assert.Equal("Invalid email/password", err.Body[0].Message)
+}
+
+func TestLoginWithLockout(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	handler.LoginLimiter = limiter.New(&unblockedStore{memory.New()}, time.Minute, time.Hour, time.Hour)
+
+	mockService.
+		EXPECT().
+		Authenticate(gomock.Any(), gomock.Any(), "guess").
+		Return(nil, &_errors.ResourceNotFoundError{Resource: "user"}).
+		Times(10)
+
+	var reqCtx *common.RequestContext
+
+	// each guess is for another account, and all come from the same IP address
+	for i := 0; i < 10; i++ {
+		payload := `{"email":"user` + string('a'+rune(i)) + `@mail.com","password":"guess"}`
+		req := httptest.NewRequest("POST", "/login", strings.NewReader(payload))
+		req.Header.Set("X-Real-IP", "203.0.113.7, 10.0.0.1")
+		reqCtx = setupRequestContext(handler.App)
+
+		status, _, _ := handler.Login(httptest.NewRecorder(), req, reqCtx)
+
+		assert.Equal(403, status)
+
+		if i < 9 {
+			assert.Nil(reqCtx.LogEntry.Data["lockoutKey"])
+		}
+	}
+
+	assert.Equal("login locked out", reqCtx.LogEntry.Message)
+	assert.Equal("ip:203.0.113.7", reqCtx.LogEntry.Data["lockoutKey"])
+	assert.Equal(10, reqCtx.LogEntry.Data["lockoutFailures"])
+}
+
+func TestLoginResetsAccountFailures(t *testing.T) {
+	reqBody := &_userHandler.LoginRequest{
+		Email:  "testuser@mail.com",
+		Passwd: "secret",
+	}
+
+	payload, _ := json.Marshal(reqBody)
+
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+
+	mockAuthService := authMock.NewService(mockCtrl)
+	handler.AuthService = mockAuthService
+
+	existingUser := &models.User{
+		ID:    1,
+		Email: reqBody.Email,
+	}
+
+	gomock.InOrder(
+		mockService.EXPECT().Authenticate(gomock.Any(), reqBody.Email, "wrong").Return(nil, &_errors.PasswordMismatchError{}),
+		mockService.EXPECT().Authenticate(gomock.Any(), reqBody.Email, "wrong").Return(nil, &_errors.PasswordMismatchError{}),
+		mockService.EXPECT().Authenticate(gomock.Any(), reqBody.Email, reqBody.Passwd).Return(existingUser, nil),
+		mockService.EXPECT().Authenticate(gomock.Any(), reqBody.Email, "wrong").Return(nil, &_errors.PasswordMismatchError{}),
+		mockService.EXPECT().Authenticate(gomock.Any(), reqBody.Email, "wrong").Return(nil, &_errors.PasswordMismatchError{}),
+	)
+
+	mockAuthService.EXPECT().Issue(gomock.Any(), existingUser).Return(&auth.TokenPair{}, nil)
+
+	wrongPayload := `{"email":"testuser@mail.com","password":"wrong"}`
+
+	for _, body := range []string{wrongPayload, wrongPayload, string(payload), wrongPayload, wrongPayload} {
+		req := httptest.NewRequest("POST", "/login", strings.NewReader(body))
+		status, _, _ := handler.Login(httptest.NewRecorder(), req, setupRequestContext(handler.App))
+
+		if body == wrongPayload {
+			assert.Equal(403, status)
+		} else {
+			assert.Equal(200, status)
+		}
+	}
+}
+
+// unblockedStore records the failures in the embedded store, but lets every attempt through
+type unblockedStore struct {
+	limiter.Store
+}
+
+func (store *unblockedStore) BlockedUntil(ctx context.Context, keys ...string) (time.Time, error) {
+	return time.Time{}, nil
+}
+
+func TestLoginWithTotpEnabled(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+	req := httptest.NewRequest("POST", "/login", strings.NewReader(`{"email":"testuser@mail.com","password":"secret"}`))
+
+	mockAuthService := authMock.NewService(mockCtrl)
+	handler.AuthService = mockAuthService
+
+	existingUser := &models.User{
+		ID:          1,
+		Email:       "testuser@mail.com",
+		TotpEnabled: true,
+	}
+
+	challenge := &auth.Challenge{
+		ID:        "challengeID",
+		UserID:    1,
+		Token:     "challengeToken",
+		ExpiresAt: time.Now().Add(5 * time.Minute),
+	}
+
+	gomock.InOrder(
+		mockService.EXPECT().Authenticate(gomock.Any(), "testuser@mail.com", "secret").Return(existingUser, nil),
+		mockAuthService.EXPECT().IssueChallenge(gomock.Any(), existingUser).Return(challenge, nil),
+	)
 
 	status, data, err := handler.Login(httptest.NewRecorder(), req, reqCtx)
 
+	challengeResponse := data.(_userHandler.MfaChallengeResponse)
+
+	assert.Equal(200, status)
+	assert.Nil(err)
+	assert.True(challengeResponse.MfaRequired)
+	assert.Equal("challengeToken", challengeResponse.ChallengeToken)
+	assert.Equal(challenge.ExpiresAt, challengeResponse.ChallengeExpiresAt)
+}
+
+func TestLoginMfaWithInvalidChallenge(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+	req := httptest.NewRequest("POST", "/login/mfa", strings.NewReader(`{"challengeToken":"accessToken","code":"123456"}`))
+
+	mockAuthService := authMock.NewService(mockCtrl)
+	handler.AuthService = mockAuthService
+
+	mockAuthService.
+		EXPECT().
+		ParseChallenge(gomock.Any(), "accessToken").
+		Return(nil, &_errors.UnauthorizedError{})
+
+	status, data, err := handler.LoginMfa(httptest.NewRecorder(), req, reqCtx)
+
+	assert.Equal(403, status)
+	assert.Nil(data)
+	assert.Equal("Access denied", err.Body[0].Message)
+	assert.Equal("challengeToken", err.Body[0].Target)
+}
+
+func TestLoginMfaWithRepeatedInvalidCodes(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+
+	mockAuthService := authMock.NewService(mockCtrl)
+	mockMfaService := mfaMock.NewService(mockCtrl)
+	handler.AuthService = mockAuthService
+	handler.MfaService = mockMfaService
+
+	challenge := &auth.Challenge{ID: "challengeID", UserID: 1, ExpiresAt: time.Now().Add(5 * time.Minute)}
+
+	mockAuthService.EXPECT().ParseChallenge(gomock.Any(), "challengeToken").Return(challenge, nil).Times(4)
+	mockMfaService.
+		EXPECT().
+		Verify(gomock.Any(), int64(1), "000000").
+		Return(nil, &_errors.InvalidValueError{Resource: "totp", Field: "code", Reason: "Invalid code"}).
+		Times(3)
+
+	for i := 0; i < 3; i++ {
+		req := httptest.NewRequest("POST", "/login/mfa", strings.NewReader(`{"challengeToken":"challengeToken","code":"000000"}`))
+
+		status, _, err := handler.LoginMfa(httptest.NewRecorder(), req, reqCtx)
+
+		assert.Equal(403, status)
+		assert.Equal("Invalid code", err.Body[0].Message)
+		assert.Equal("code", err.Body[0].Target)
+	}
+
+	res := httptest.NewRecorder()
+	req := httptest.NewRequest("POST", "/login/mfa", strings.NewReader(`{"challengeToken":"challengeToken","code":"000000"}`))
+
+	status, _, _ := handler.LoginMfa(res, req, reqCtx)
+
+	assert.Equal(429, status)
+	assert.NotEmpty(res.Header().Get("Retry-After"))
+}
+
+func TestLoginMfa(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+	req := httptest.NewRequest("POST", "/login/mfa", strings.NewReader(`{"challengeToken":"challengeToken","code":"123456"}`))
+
+	mockAuthService := authMock.NewService(mockCtrl)
+	mockMfaService := mfaMock.NewService(mockCtrl)
+	handler.AuthService = mockAuthService
+	handler.MfaService = mockMfaService
+
+	existingUser := &models.User{ID: 1, TotpEnabled: true}
+	challenge := &auth.Challenge{ID: "challengeID", UserID: 1, ExpiresAt: time.Now().Add(5 * time.Minute)}
+	tokens := &auth.TokenPair{
+		AccessToken:  "token",
+		RefreshToken: "refreshToken",
+	}
+
+	gomock.InOrder(
+		mockAuthService.EXPECT().ParseChallenge(gomock.Any(), "challengeToken").Return(challenge, nil),
+		mockMfaService.EXPECT().Verify(gomock.Any(), int64(1), "123456").Return(existingUser, nil),
+		mockAuthService.EXPECT().RedeemChallenge(gomock.Any(), challenge).Return(nil),
+		mockAuthService.EXPECT().Issue(gomock.Any(), existingUser).Return(tokens, nil),
+	)
+
+	status, data, err := handler.LoginMfa(httptest.NewRecorder(), req, reqCtx)
+
 	loginResponse := data.(_userHandler.LoginResponse)
 
 	assert.Equal(200, status)
 	assert.Nil(err)
-	assert.NotNil(data)
 	assert.Equal("token", loginResponse.Token)
+	assert.Equal("refreshToken", loginResponse.RefreshToken)
+}
+
+func TestDelete(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+	reqCtx.UserID = 1
+	req := httptest.NewRequest("DELETE", "/users/me", nil)
+
+	mockAuthService := authMock.NewService(mockCtrl)
+	handler.AuthService = mockAuthService
+
+	gomock.InOrder(
+		mockService.EXPECT().Delete(gomock.Any(), int64(1)).Return(nil),
+		mockAuthService.EXPECT().RevokeUser(gomock.Any(), int64(1)).Return(nil),
+	)
+
+	status, data, err := handler.Delete(httptest.NewRecorder(), req, reqCtx)
+
+	assert.Equal(200, status)
+	assert.Nil(err)
+	assert.Nil(data)
+}
+
+func TestDeleteWithMissingUser(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+	reqCtx.UserID = 1
+	req := httptest.NewRequest("DELETE", "/users/me", nil)
+
+	mockService.EXPECT().Delete(gomock.Any(), int64(1)).Return(&_errors.ResourceNotFoundError{Resource: "user"})
+
+	status, _, err := handler.Delete(httptest.NewRecorder(), req, reqCtx)
+
+	assert.Equal(404, status)
+	assert.Equal("user", err.Body[0].Target)
+}
+
+func TestRequestVerificationWithInvalidEmail(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+	req := httptest.NewRequest("POST", "/users/verification", strings.NewReader(`{"email":"testuser@"}`))
+
+	status, data, err := handler.RequestVerification(httptest.NewRecorder(), req, reqCtx)
+
+	assert.Equal(400, status)
+	assert.Nil(data)
+	assert.Equal(1, len(err.Body))
+	assert.Equal("Invalid value", err.Body[0].Message)
+	assert.Equal("email", err.Body[0].Target)
+}
+
+func TestRequestVerification(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+	req := httptest.NewRequest("POST", "/users/verification", strings.NewReader(`{"email":"testuser@mail.com"}`))
+
+	mockService.EXPECT().RequestVerification(gomock.Any(), "testuser@mail.com").Return(nil)
+
+	status, data, err := handler.RequestVerification(httptest.NewRecorder(), req, reqCtx)
+
+	assert.Equal(200, status)
+	assert.Nil(data)
+	assert.Nil(err)
+}
+
+func TestVerifyWithBlankToken(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+	req := httptest.NewRequest("POST", "/users/verification/confirm", strings.NewReader(`{"token":" "}`))
+
+	status, _, err := handler.Verify(httptest.NewRecorder(), req, reqCtx)
+
+	assert.Equal(400, status)
+	assert.Equal(1, len(err.Body))
+	assert.Equal("Non-empty value is required", err.Body[0].Message)
+	assert.Equal("token", err.Body[0].Target)
+}
+
+func TestVerifyWithInvalidToken(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+	req := httptest.NewRequest("POST", "/users/verification/confirm", strings.NewReader(`{"token":"token"}`))
+
+	mockService.
+		EXPECT().
+		Verify(gomock.Any(), "token").
+		Return(&_errors.InvalidValueError{Resource: "user", Field: "token", Reason: "Invalid or expired token"})
+
+	status, _, err := handler.Verify(httptest.NewRecorder(), req, reqCtx)
+
+	assert.Equal(400, status)
+	assert.Equal(1, len(err.Body))
+	assert.Equal("Invalid or expired token", err.Body[0].Message)
+	assert.Equal("token", err.Body[0].Target)
+}
+
+func TestVerify(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+	req := httptest.NewRequest("POST", "/users/verification/confirm", strings.NewReader(`{"token":"token"}`))
+
+	mockService.EXPECT().Verify(gomock.Any(), "token").Return(nil)
+
+	status, data, err := handler.Verify(httptest.NewRecorder(), req, reqCtx)
+
+	assert.Equal(200, status)
+	assert.Nil(data)
+	assert.Nil(err)
+}
+
+func TestRequestPasswordReset(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+	req := httptest.NewRequest("POST", "/users/password-reset", strings.NewReader(`{"email":"testuser@mail.com"}`))
+
+	mockService.EXPECT().RequestPasswordReset(gomock.Any(), "testuser@mail.com").Return(nil)
+
+	status, data, err := handler.RequestPasswordReset(httptest.NewRecorder(), req, reqCtx)
+
+	assert.Equal(200, status)
+	assert.Nil(data)
+	assert.Nil(err)
+}
+
+func TestResetPasswordWithShortPassword(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+	req := httptest.NewRequest("POST", "/users/password-reset/confirm", strings.NewReader(`{"token":"token","password":"abc"}`))
+
+	status, _, err := handler.ResetPassword(httptest.NewRecorder(), req, reqCtx)
+
+	assert.Equal(400, status)
+	assert.Equal(1, len(err.Body))
+	assert.Equal("Length should be 6 or more", err.Body[0].Message)
+	assert.Equal("password", err.Body[0].Target)
+}
+
+func TestResetPassword(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+	req := httptest.NewRequest("POST", "/users/password-reset/confirm", strings.NewReader(`{"token":"token","password":"newSecret"}`))
+
+	mockService.EXPECT().ResetPassword(gomock.Any(), "token", "newSecret").Return(nil)
+
+	status, data, err := handler.ResetPassword(httptest.NewRecorder(), req, reqCtx)
+
+	assert.Equal(200, status)
+	assert.Nil(data)
+	assert.Nil(err)
+}
+
+func TestGet(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+	reqCtx.UserID = 1
+	req := httptest.NewRequest("GET", "/users/me", nil)
+
+	existingUser := &models.User{ID: 1, Name: "testuser", Email: "testuser@mail.com", IsActive: true}
+
+	mockService.EXPECT().Get(gomock.Any(), int64(1)).Return(existingUser, nil)
+
+	status, data, err := handler.Get(httptest.NewRecorder(), req, reqCtx)
+
+	responseData := data.(*_userHandler.UserResponse)
+
+	assert.Equal(200, status)
+	assert.Nil(err)
+	assert.Equal(int64(1), responseData.User.ID)
+	assert.Equal("testuser", responseData.User.Name)
+	assert.Equal("testuser@mail.com", responseData.User.Email)
+}
+
+func TestGetWithMissingUser(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+	reqCtx.UserID = 1
+	req := httptest.NewRequest("GET", "/users/me", nil)
+
+	mockService.EXPECT().Get(gomock.Any(), int64(1)).Return(nil, &_errors.ResourceNotFoundError{Resource: "user"})
+
+	status, data, err := handler.Get(httptest.NewRecorder(), req, reqCtx)
+
+	assert.Equal(404, status)
+	assert.Nil(data)
+	assert.Equal("Not found", err.Body[0].Message)
+}
+
+func TestUpdateWithBlankName(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+	req := httptest.NewRequest("PATCH", "/users/me", strings.NewReader(`{"name":" "}`))
+
+	status, data, err := handler.Update(httptest.NewRecorder(), req, reqCtx)
+
+	assert.Equal(400, status)
+	assert.Nil(data)
+	assert.Equal("name", err.Body[0].Target)
+}
+
+func TestUpdate(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+	reqCtx.UserID = 1
+	req := httptest.NewRequest("PATCH", "/users/me", strings.NewReader(`{"name":" newName "}`))
+
+	existingUser := &models.User{ID: 1, Name: "testuser", Email: "testuser@mail.com"}
+
+	gomock.InOrder(
+		mockService.EXPECT().Get(gomock.Any(), int64(1)).Return(existingUser, nil),
+		mockService.EXPECT().Update(gomock.Any(), existingUser).Return(nil),
+	)
+
+	status, data, err := handler.Update(httptest.NewRecorder(), req, reqCtx)
+
+	responseData := data.(*_userHandler.UserResponse)
+
+	assert.Equal(200, status)
+	assert.Nil(err)
+	assert.Equal("newName", responseData.User.Name)
+}
+
+func TestChangePasswordWithShortPassword(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+	req := httptest.NewRequest("POST", "/users/me/password", strings.NewReader(`{"currentPassword":"secret","newPassword":"abc"}`))
+
+	status, data, err := handler.ChangePassword(httptest.NewRecorder(), req, reqCtx)
+
+	assert.Equal(400, status)
+	assert.Nil(data)
+	assert.Equal("newPassword", err.Body[0].Target)
+}
+
+func TestChangePasswordWithRepeatedMismatches(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	payload := `{"currentPassword":"wrong","newPassword":"newSecret"}`
+
+	mockService.
+		EXPECT().
+		ChangePassword(gomock.Any(), int64(1), "session", "wrong", "newSecret").
+		Return(&_errors.PasswordMismatchError{}).
+		Times(3)
+
+	for i := 0; i < 3; i++ {
+		reqCtx := setupRequestContext(handler.App)
+		reqCtx.UserID = 1
+		reqCtx.SessionID = "session"
+		req := httptest.NewRequest("POST", "/users/me/password", strings.NewReader(payload))
+		status, _, err := handler.ChangePassword(httptest.NewRecorder(), req, reqCtx)
+
+		assert.Equal(403, status)
+		assert.Equal("Invalid password", err.Body[0].Message)
+		assert.Equal("currentPassword", err.Body[0].Target)
+	}
+
+	// third failure was past the free attempts, so the next attempt is delayed
+	reqCtx := setupRequestContext(handler.App)
+	reqCtx.UserID = 1
+	req := httptest.NewRequest("POST", "/users/me/password", strings.NewReader(payload))
+	status, _, _ := handler.ChangePassword(httptest.NewRecorder(), req, reqCtx)
+
+	assert.Equal(429, status)
+}
+
+func TestChangePassword(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+	reqCtx.UserID = 1
+	reqCtx.SessionID = "session"
+	req := httptest.NewRequest("POST", "/users/me/password", strings.NewReader(`{"currentPassword":"secret","newPassword":"newSecret"}`))
+
+	mockService.EXPECT().ChangePassword(gomock.Any(), int64(1), "session", "secret", "newSecret").Return(nil)
+
+	status, data, err := handler.ChangePassword(httptest.NewRecorder(), req, reqCtx)
+
+	assert.Equal(200, status)
+	assert.Nil(err)
+	assert.Nil(data)
+}
+
+func TestRequestEmailChangeWithDataConflict(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+	reqCtx.UserID = 1
+	req := httptest.NewRequest("POST", "/users/me/email", strings.NewReader(`{"email":"taken@mail.com","password":"secret"}`))
+
+	mockService.
+		EXPECT().
+		RequestEmailChange(gomock.Any(), int64(1), "secret", "taken@mail.com").
+		Return(&_errors.DataConflictError{Resource: "user", Field: "email"})
+
+	status, data, err := handler.RequestEmailChange(httptest.NewRecorder(), req, reqCtx)
+
+	assert.Equal(409, status)
+	assert.Nil(data)
+	assert.Equal("email", err.Body[0].Target)
+}
+
+func TestRequestEmailChange(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+	reqCtx.UserID = 1
+	req := httptest.NewRequest("POST", "/users/me/email", strings.NewReader(`{"email":"new@mail.com","password":"secret"}`))
+
+	mockService.EXPECT().RequestEmailChange(gomock.Any(), int64(1), "secret", "new@mail.com").Return(nil)
+
+	status, data, err := handler.RequestEmailChange(httptest.NewRecorder(), req, reqCtx)
+
+	assert.Equal(200, status)
+	assert.Nil(err)
+	assert.Nil(data)
+}
+
+func TestChangeEmail(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+	req := httptest.NewRequest("POST", "/users/email-change/confirm", strings.NewReader(`{"token":"token"}`))
+
+	mockService.EXPECT().ChangeEmail(gomock.Any(), "token").Return(nil)
+
+	status, data, err := handler.ChangeEmail(httptest.NewRecorder(), req, reqCtx)
+
+	assert.Equal(200, status)
+	assert.Nil(err)
+	assert.Nil(data)
+}
+
+func TestDeactivate(t *testing.T) {
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockService := mock.NewService(mockCtrl)
+	handler := setupHandler(mockService)
+	reqCtx := setupRequestContext(handler.App)
+	reqCtx.UserID = 1
+	req := httptest.NewRequest("POST", "/users/me/deactivate", nil)
+
+	mockAuthService := authMock.NewService(mockCtrl)
+	handler.AuthService = mockAuthService
+
+	gomock.InOrder(
+		mockService.EXPECT().Deactivate(gomock.Any(), int64(1)).Return(nil),
+		mockAuthService.EXPECT().RevokeUser(gomock.Any(), int64(1)).Return(nil),
+	)
+
+	status, data, err := handler.Deactivate(httptest.NewRecorder(), req, reqCtx)
+
+	assert.Equal(200, status)
+	assert.Nil(err)
+	assert.Nil(data)
 }
 
 func setupHandler(mockService user.Service) *_userHandler.UserHandler {
@@ -487,14 +1276,22 @@ func setupHandler(mockService user.Service) *_userHandler.UserHandler {
 		Jwt: &config.JwtSetting{
 			Secret: "secret",
 		},
+		Login: &config.LoginSetting{
+			AccountFreeAttempts:    2,
+			AccountLockoutAttempts: 4,
+			IPFreeAttempts:         5,
+			IPLockoutAttempts:      10,
+			ClientIPHeader:         "X-Real-IP",
+		},
 	}
 
 	app.Config.Application = appSettings
 	app.Config.Auth = authSettings
 
 	handler := &_userHandler.UserHandler{
-		UserService: mockService,
-		App:         app,
+		UserService:  mockService,
+		LoginLimiter: limiter.New(memory.New(), time.Minute, time.Hour, time.Hour),
+		App:          app,
 	}
 
 	return handler
@@ -2,6 +2,7 @@ package user
 
 import (
 	"context"
+	"time"
 
 	"github.com/dheerajgopi/todo-api/models"
 )
@@ -11,4 +12,15 @@ type Repository interface {
 	GetByID(ctx context.Context, id int64) (*models.User, error)
 	Create(ctx context.Context, user *models.User) error
 	GetByEmail(ctx context.Context, email string) (*models.User, error)
+	Delete(ctx context.Context, id int64, deletedAt time.Time) error
+	GetPurgeable(ctx context.Context, before time.Time, limit int) ([]int64, error)
+	Purge(ctx context.Context, id int64) error
+	CreateToken(ctx context.Context, token *models.UserToken) error
+	GetToken(ctx context.Context, purpose string, tokenHash string) (*models.UserToken, error)
+	Verify(ctx context.Context, token *models.UserToken, verifiedAt time.Time) (bool, error)
+	ResetPassword(ctx context.Context, token *models.UserToken, passwd string, resetAt time.Time) (bool, error)
+	Update(ctx context.Context, user *models.User) error
+	UpdatePassword(ctx context.Context, id int64, passwd string, sessionID string, updatedAt time.Time) error
+	UpdateEmail(ctx context.Context, token *models.UserToken, updatedAt time.Time) (bool, error)
+	Deactivate(ctx context.Context, id int64, deactivatedAt time.Time) (bool, error)
 }
@@ -3,11 +3,18 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"time"
 
+	todoErr "github.com/dheerajgopi/todo-api/common/error"
 	"github.com/dheerajgopi/todo-api/models"
+	taskRepository "github.com/dheerajgopi/todo-api/task/repository"
 	"github.com/dheerajgopi/todo-api/user"
+	"github.com/go-sql-driver/mysql"
 )
 
+// duplicateEntryErrorNumber is the MySQL error number for a violated unique key
+const duplicateEntryErrorNumber = 1062
+
 type mySQLUserRepo struct {
 	DB *sql.DB
 }
@@ -35,8 +42,11 @@ func (repo *mySQLUserRepo) getOne(ctx context.Context, query string, args ...int
 		&user.Email,
 		&user.Passwd,
 		&user.IsActive,
+		&user.TotpEnabled,
 		&user.CreatedAt,
 		&user.UpdatedAt,
+		&user.DeletedAt,
+		&user.DeactivatedAt,
 	)
 
 	switch err {
@@ -50,9 +60,9 @@ func (repo *mySQLUserRepo) getOne(ctx context.Context, query string, args ...int
 	return user, nil
 }
 
-// GetByID will return user with the given id
+// GetByID will return user with the given id, unless the user is deleted
 func (repo *mySQLUserRepo) GetByID(ctx context.Context, id int64) (*models.User, error) {
-	query := `SELECT id, name, email, passwd, is_active, created_at, updated_at FROM user WHERE id=?`
+	query := `SELECT id, name, email, passwd, is_active, totp_enabled, created_at, updated_at, deleted_at, deactivated_at FROM user WHERE id=? AND deleted_at IS NULL`
 	return repo.getOne(ctx, query, id)
 }
 
@@ -100,8 +110,392 @@ func (repo *mySQLUserRepo) Create(ctx context.Context, user *models.User) error
 	return nil
 }
 
-// GetByEmail will return user with the given email
+// GetByEmail will return user with the given email.
+// Deleted users are returned as well, since their email is taken until they are purged.
 func (repo *mySQLUserRepo) GetByEmail(ctx context.Context, email string) (*models.User, error) {
-	query := `SELECT id, name, email, passwd, is_active, created_at, updated_at FROM user WHERE email=?`
+	query := `SELECT id, name, email, passwd, is_active, totp_enabled, created_at, updated_at, deleted_at, deactivated_at FROM user WHERE email=?`
 	return repo.getOne(ctx, query, email)
 }
+
+// Delete will mark the user as deleted, move the user's tasks to trash (recording the deletion in their history), and revoke the refresh tokens of the user
+func (repo *mySQLUserRepo) Delete(ctx context.Context, id int64, deletedAt time.Time) error {
+	tx, err := repo.DB.BeginTx(ctx, nil)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`UPDATE user SET deleted_at=? WHERE id=? AND deleted_at IS NULL`, deletedAt, id)
+
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err = taskRepository.DeleteAll(ctx, tx, deletedAt, `created_by=?`, id); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	_, err = tx.Exec(`UPDATE refresh_token SET revoked_at=? WHERE user_id=? AND revoked_at IS NULL`, deletedAt, id)
+
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetPurgeable returns the ids of the users which were deleted before the given time, up to the limit
+func (repo *mySQLUserRepo) GetPurgeable(ctx context.Context, before time.Time, limit int) ([]int64, error) {
+	stmt, err := repo.DB.PrepareContext(ctx, `SELECT id FROM user WHERE deleted_at<? ORDER BY id ASC LIMIT ?`)
+
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := stmt.QueryContext(ctx, before, limit)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	ids := make([]int64, 0)
+
+	for rows.Next() {
+		id := int64(0)
+
+		if err = rows.Scan(&id); err != nil {
+			return nil, err
+		}
+
+		ids = append(ids, id)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// Purge will permanently remove the user, along with the tasks, tags and projects created by the user.
+// Comments, attachments and collaborations of the user are removed by the database.
+func (repo *mySQLUserRepo) Purge(ctx context.Context, id int64) error {
+	tx, err := repo.DB.BeginTx(ctx, nil)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`DELETE FROM task_event WHERE task_id IN (SELECT id FROM task
+		WHERE created_by=? OR parent_id IN (SELECT id FROM task WHERE created_by=?))`, id, id)
+
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	queries := []string{
+		`DELETE FROM task WHERE created_by=?`,
+		`DELETE FROM tag WHERE created_by=?`,
+		`DELETE FROM project WHERE created_by=?`,
+		`DELETE FROM user WHERE id=?`,
+	}
+
+	for _, query := range queries {
+		if _, err = tx.Exec(query, id); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// CreateToken will store new verification, password reset or email change token, replacing the earlier tokens of the user for the same purpose
+func (repo *mySQLUserRepo) CreateToken(ctx context.Context, token *models.UserToken) error {
+	tx, err := repo.DB.BeginTx(ctx, nil)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`DELETE FROM user_token WHERE user_id=? AND purpose=?`, token.User.ID, token.Purpose)
+
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	query := `INSERT INTO user_token (user_id, purpose, token_hash, email, expires_at, created_at) VALUES (?, ?, ?, ?, ?, ?)`
+
+	email := sql.NullString{
+		String: token.Email,
+		Valid:  token.Email != "",
+	}
+
+	res, err := tx.Exec(query, token.User.ID, token.Purpose, token.TokenHash, email, token.ExpiresAt, token.CreatedAt)
+
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	lastID, err := res.LastInsertId()
+
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+
+	token.ID = lastID
+
+	return nil
+}
+
+// GetToken will return the token with the given purpose and hash, along with its user, unless the user is deleted or deactivated
+func (repo *mySQLUserRepo) GetToken(ctx context.Context, purpose string, tokenHash string) (*models.UserToken, error) {
+	query := `SELECT t.id, t.user_id, u.email, u.is_active, t.purpose, t.token_hash, t.email, t.expires_at, t.used_at, t.created_at
+		FROM user_token t JOIN user u ON u.id=t.user_id WHERE t.purpose=? AND t.token_hash=?
+		AND u.deleted_at IS NULL AND u.deactivated_at IS NULL`
+
+	stmt, err := repo.DB.PrepareContext(ctx, query)
+
+	if err != nil {
+		return nil, err
+	}
+
+	token := &models.UserToken{
+		User: &models.User{},
+	}
+
+	email := sql.NullString{}
+
+	err = stmt.QueryRowContext(ctx, purpose, tokenHash).Scan(
+		&token.ID,
+		&token.User.ID,
+		&token.User.Email,
+		&token.User.IsActive,
+		&token.Purpose,
+		&token.TokenHash,
+		&email,
+		&token.ExpiresAt,
+		&token.UsedAt,
+		&token.CreatedAt,
+	)
+
+	switch err {
+	case nil:
+	case sql.ErrNoRows:
+		return nil, nil
+	default:
+		return nil, err
+	}
+
+	token.Email = email.String
+
+	return token, nil
+}
+
+// Verify will mark the verification token as used and activate its user.
+// False is returned, without activating the user, if the token was already used.
+func (repo *mySQLUserRepo) Verify(ctx context.Context, token *models.UserToken, verifiedAt time.Time) (bool, error) {
+	tx, err := repo.DB.BeginTx(ctx, nil)
+
+	if err != nil {
+		return false, err
+	}
+
+	if used, err := useToken(tx, token.ID, verifiedAt); err != nil || !used {
+		tx.Rollback()
+		return false, err
+	}
+
+	_, err = tx.Exec(`UPDATE user SET is_active=1, updated_at=? WHERE id=?`, verifiedAt, token.User.ID)
+
+	if err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// ResetPassword will mark the password reset token as used and replace the password hash of its user.
+// User is activated as well, since the mail was received, and the sessions of the user are revoked.
+// False is returned, without changing the password, if the token was already used.
+func (repo *mySQLUserRepo) ResetPassword(ctx context.Context, token *models.UserToken, passwd string, resetAt time.Time) (bool, error) {
+	tx, err := repo.DB.BeginTx(ctx, nil)
+
+	if err != nil {
+		return false, err
+	}
+
+	if used, err := useToken(tx, token.ID, resetAt); err != nil || !used {
+		tx.Rollback()
+		return false, err
+	}
+
+	_, err = tx.Exec(`UPDATE user SET passwd=?, is_active=1, updated_at=? WHERE id=?`, passwd, resetAt, token.User.ID)
+
+	if err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	_, err = tx.Exec(`UPDATE refresh_token SET revoked_at=? WHERE user_id=? AND revoked_at IS NULL`, resetAt, token.User.ID)
+
+	if err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// Update will store the name of the user
+func (repo *mySQLUserRepo) Update(ctx context.Context, user *models.User) error {
+	query := `UPDATE user SET name=?, updated_at=? WHERE id=? AND deleted_at IS NULL`
+
+	_, err := repo.DB.ExecContext(ctx, query, user.Name, user.UpdatedAt, user.ID)
+
+	return err
+}
+
+// UpdatePassword will replace the password hash of the user, and revoke the sessions of the user but the given one
+func (repo *mySQLUserRepo) UpdatePassword(ctx context.Context, id int64, passwd string, sessionID string, updatedAt time.Time) error {
+	tx, err := repo.DB.BeginTx(ctx, nil)
+
+	if err != nil {
+		return err
+	}
+
+	if _, err = tx.Exec(`UPDATE user SET passwd=?, updated_at=? WHERE id=?`, passwd, updatedAt, id); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	query := `UPDATE refresh_token SET revoked_at=? WHERE user_id=? AND family_id<>? AND revoked_at IS NULL`
+
+	if _, err = tx.Exec(query, updatedAt, id, sessionID); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// UpdateEmail will mark the email change token as used and replace the email of its user with the new one.
+// User is verified as well, since the mail was received at the new email.
+// False is returned, without changing the email, if the token was already used.
+// A conflict error is returned if the new email was taken by another user in the meantime.
+func (repo *mySQLUserRepo) UpdateEmail(ctx context.Context, token *models.UserToken, updatedAt time.Time) (bool, error) {
+	tx, err := repo.DB.BeginTx(ctx, nil)
+
+	if err != nil {
+		return false, err
+	}
+
+	if used, err := useToken(tx, token.ID, updatedAt); err != nil || !used {
+		tx.Rollback()
+		return false, err
+	}
+
+	_, err = tx.Exec(`UPDATE user SET email=?, is_active=1, updated_at=? WHERE id=?`, token.Email, updatedAt, token.User.ID)
+
+	if err != nil {
+		tx.Rollback()
+
+		if isDuplicateEntry(err) {
+			return false, &todoErr.DataConflictError{
+				Resource: "user",
+				Field:    "email",
+			}
+		}
+
+		return false, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// Deactivate will mark the user as deactivated and revoke the refresh tokens of the user.
+// False is returned if the user is already deactivated or deleted.
+func (repo *mySQLUserRepo) Deactivate(ctx context.Context, id int64, deactivatedAt time.Time) (bool, error) {
+	tx, err := repo.DB.BeginTx(ctx, nil)
+
+	if err != nil {
+		return false, err
+	}
+
+	query := `UPDATE user SET deactivated_at=?, updated_at=? WHERE id=? AND deactivated_at IS NULL AND deleted_at IS NULL`
+
+	result, err := tx.Exec(query, deactivatedAt, deactivatedAt, id)
+
+	if err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	if deactivated, err := result.RowsAffected(); err != nil || deactivated == 0 {
+		tx.Rollback()
+		return false, err
+	}
+
+	_, err = tx.Exec(`UPDATE refresh_token SET revoked_at=? WHERE user_id=? AND revoked_at IS NULL`, deactivatedAt, id)
+
+	if err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// isDuplicateEntry reports whether the error was caused by a violated unique key
+func isDuplicateEntry(err error) bool {
+	mysqlErr, ok := err.(*mysql.MySQLError)
+
+	return ok && mysqlErr.Number == duplicateEntryErrorNumber
+}
+
+// useToken marks an unused token as used, and tells whether it was unused
+func useToken(tx *sql.Tx, id int64, usedAt time.Time) (bool, error) {
+	result, err := tx.Exec(`UPDATE user_token SET used_at=? WHERE id=? AND used_at IS NULL`, usedAt, id)
+
+	if err != nil {
+		return false, err
+	}
+
+	used, err := result.RowsAffected()
+
+	if err != nil {
+		return false, err
+	}
+
+	return used > 0, nil
+}
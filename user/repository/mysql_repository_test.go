@@ -6,12 +6,14 @@ import (
 	"testing"
 	"time"
 
+	todoErr "github.com/dheerajgopi/todo-api/common/error"
 	"github.com/dheerajgopi/todo-api/models"
 
 	"github.com/stretchr/testify/assert"
 
 	sqlmock "github.com/DATA-DOG/go-sqlmock"
 	"github.com/dheerajgopi/todo-api/user/repository"
+	"github.com/go-sql-driver/mysql"
 )
 
 func TestGetByID(t *testing.T) {
@@ -25,11 +27,11 @@ func TestGetByID(t *testing.T) {
 	defer db.Close()
 
 	rows := sqlmock.
-		NewRows([]string{"id", "name", "email", "passwd", "is_active", "created_at", "updated_at"}).
-		AddRow(1, "test user", "test@email.com", "passwd", true, time.Now(), time.Now())
+		NewRows([]string{"id", "name", "email", "passwd", "is_active", "totp_enabled", "created_at", "updated_at", "deleted_at", "deactivated_at"}).
+		AddRow(1, "test user", "test@email.com", "passwd", true, false, time.Now(), time.Now(), nil, nil)
 
 	userID := int64(1)
-	query := "SELECT id, name, email, passwd, is_active, created_at, updated_at FROM user WHERE id=\\?"
+	query := "SELECT id, name, email, passwd, is_active, totp_enabled, created_at, updated_at, deleted_at, deactivated_at FROM user WHERE id=\\? AND deleted_at IS NULL"
 
 	prep := mock.ExpectPrepare(query)
 	prep.ExpectQuery().WithArgs(userID).WillReturnRows(rows)
@@ -52,7 +54,7 @@ func TestGetByIDWithNoRows(t *testing.T) {
 	defer db.Close()
 
 	userID := int64(1)
-	query := "SELECT id, name, email, passwd, is_active, created_at, updated_at FROM user WHERE id=\\?"
+	query := "SELECT id, name, email, passwd, is_active, totp_enabled, created_at, updated_at, deleted_at, deactivated_at FROM user WHERE id=\\? AND deleted_at IS NULL"
 
 	prep := mock.ExpectPrepare(query)
 	prep.ExpectQuery().WithArgs(userID).WillReturnError(sql.ErrNoRows)
@@ -117,11 +119,11 @@ func TestGetByEmail(t *testing.T) {
 	defer db.Close()
 
 	rows := sqlmock.
-		NewRows([]string{"id", "name", "email", "passwd", "is_active", "created_at", "updated_at"}).
-		AddRow(1, "test user", "test@email.com", "passwd", true, time.Now(), time.Now())
+		NewRows([]string{"id", "name", "email", "passwd", "is_active", "totp_enabled", "created_at", "updated_at", "deleted_at", "deactivated_at"}).
+		AddRow(1, "test user", "test@email.com", "passwd", true, false, time.Now(), time.Now(), nil, nil)
 
 	userEmail := "test@email.com"
-	query := "SELECT id, name, email, passwd, is_active, created_at, updated_at FROM user WHERE email=\\?"
+	query := "SELECT id, name, email, passwd, is_active, totp_enabled, created_at, updated_at, deleted_at, deactivated_at FROM user WHERE email=\\?"
 
 	prep := mock.ExpectPrepare(query)
 	prep.ExpectQuery().WithArgs(userEmail).WillReturnRows(rows)
@@ -144,7 +146,7 @@ func TestGetByEmailWithNoRows(t *testing.T) {
 	defer db.Close()
 
 	userEmail := "test@email.com"
-	query := "SELECT id, name, email, passwd, is_active, created_at, updated_at FROM user WHERE email=\\?"
+	query := "SELECT id, name, email, passwd, is_active, totp_enabled, created_at, updated_at, deleted_at, deactivated_at FROM user WHERE email=\\?"
 
 	prep := mock.ExpectPrepare(query)
 	prep.ExpectQuery().WithArgs(userEmail).WillReturnError(sql.ErrNoRows)
@@ -155,3 +157,432 @@ func TestGetByEmailWithNoRows(t *testing.T) {
 	assert.NoError(err)
 	assert.Nil(user)
 }
+
+func TestDelete(t *testing.T) {
+	assert := assert.New(t)
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	userID := int64(1)
+	deletedAt := time.Now()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE user SET deleted_at=\\? WHERE id=\\? AND deleted_at IS NULL").
+		WithArgs(deletedAt, userID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("SELECT id, title FROM task WHERE created_by=\\? AND deleted_at IS NULL FOR UPDATE").
+		WithArgs(userID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title"}).AddRow(int64(2), "title"))
+	mock.ExpectExec("UPDATE task SET deleted_at=\\?, revision=revision\\+1 WHERE created_by=\\? AND deleted_at IS NULL").
+		WithArgs(deletedAt, userID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO task_event \\(task_id, type, old_value, new_value, actor_id, request_id, created_at\\)").
+		WithArgs(int64(2), "deleted", "title", nil, nil, nil, deletedAt).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("UPDATE refresh_token SET revoked_at=\\? WHERE user_id=\\? AND revoked_at IS NULL").
+		WithArgs(deletedAt, userID).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	repo := repository.New(db)
+
+	err = repo.Delete(context.TODO(), userID, deletedAt)
+	assert.NoError(err)
+	assert.NoError(mock.ExpectationsWereMet())
+}
+
+func TestGetPurgeable(t *testing.T) {
+	assert := assert.New(t)
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	before := time.Now()
+
+	prep := mock.ExpectPrepare("SELECT id FROM user WHERE deleted_at<\\? ORDER BY id ASC LIMIT \\?")
+	prep.ExpectQuery().WithArgs(before, 10).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+
+	repo := repository.New(db)
+
+	ids, err := repo.GetPurgeable(context.TODO(), before, 10)
+	assert.NoError(err)
+	assert.Equal([]int64{3}, ids)
+}
+
+func TestPurge(t *testing.T) {
+	assert := assert.New(t)
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	userID := int64(3)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM task_event WHERE task_id IN \\(SELECT id FROM task\\s+"+
+		"WHERE created_by=\\? OR parent_id IN \\(SELECT id FROM task WHERE created_by=\\?\\)\\)").
+		WithArgs(userID, userID).
+		WillReturnResult(sqlmock.NewResult(0, 8))
+	mock.ExpectExec("DELETE FROM task WHERE created_by=\\?").WithArgs(userID).WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec("DELETE FROM tag WHERE created_by=\\?").WithArgs(userID).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM project WHERE created_by=\\?").WithArgs(userID).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM user WHERE id=\\?").WithArgs(userID).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	repo := repository.New(db)
+
+	err = repo.Purge(context.TODO(), userID)
+	assert.NoError(err)
+	assert.NoError(mock.ExpectationsWereMet())
+}
+
+func TestCreateToken(t *testing.T) {
+	assert := assert.New(t)
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	now := time.Now()
+	token := &models.UserToken{
+		User:      &models.User{ID: 1},
+		Purpose:   "verification",
+		TokenHash: "hash",
+		ExpiresAt: now.Add(time.Hour),
+		CreatedAt: now,
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM user_token WHERE user_id=\\? AND purpose=\\?").
+		WithArgs(int64(1), "verification").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO user_token \\(user_id, purpose, token_hash, email, expires_at, created_at\\) VALUES").
+		WithArgs(int64(1), "verification", "hash", nil, token.ExpiresAt, now).
+		WillReturnResult(sqlmock.NewResult(4, 1))
+	mock.ExpectCommit()
+
+	repo := repository.New(db)
+
+	err = repo.CreateToken(context.TODO(), token)
+	assert.NoError(err)
+	assert.Equal(int64(4), token.ID)
+	assert.NoError(mock.ExpectationsWereMet())
+}
+
+func TestGetToken(t *testing.T) {
+	assert := assert.New(t)
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	rows := sqlmock.
+		NewRows([]string{"id", "user_id", "email", "is_active", "purpose", "token_hash", "email", "expires_at", "used_at", "created_at"}).
+		AddRow(4, 1, "test@email.com", false, "verification", "hash", nil, time.Now(), nil, time.Now())
+
+	query := "SELECT t.id, t.user_id, u.email, u.is_active, t.purpose, t.token_hash, t.email, t.expires_at, t.used_at, t.created_at\\s+" +
+		"FROM user_token t JOIN user u ON u.id=t.user_id WHERE t.purpose=\\? AND t.token_hash=\\?\\s+" +
+		"AND u.deleted_at IS NULL AND u.deactivated_at IS NULL"
+
+	prep := mock.ExpectPrepare(query)
+	prep.ExpectQuery().WithArgs("verification", "hash").WillReturnRows(rows)
+
+	repo := repository.New(db)
+
+	token, err := repo.GetToken(context.TODO(), "verification", "hash")
+	assert.NoError(err)
+	assert.Equal(int64(4), token.ID)
+	assert.Equal(int64(1), token.User.ID)
+	assert.Equal("test@email.com", token.User.Email)
+	assert.Equal("", token.Email)
+	assert.Nil(token.UsedAt)
+}
+
+func TestGetTokenWithNoRows(t *testing.T) {
+	assert := assert.New(t)
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	prep := mock.ExpectPrepare("SELECT t.id, t.user_id")
+	prep.ExpectQuery().WithArgs("password_reset", "hash").WillReturnError(sql.ErrNoRows)
+
+	repo := repository.New(db)
+
+	token, err := repo.GetToken(context.TODO(), "password_reset", "hash")
+	assert.NoError(err)
+	assert.Nil(token)
+}
+
+func TestVerify(t *testing.T) {
+	assert := assert.New(t)
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	verifiedAt := time.Now()
+	token := &models.UserToken{ID: 4, User: &models.User{ID: 1}}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE user_token SET used_at=\\? WHERE id=\\? AND used_at IS NULL").
+		WithArgs(verifiedAt, int64(4)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE user SET is_active=1, updated_at=\\? WHERE id=\\?").
+		WithArgs(verifiedAt, int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	repo := repository.New(db)
+
+	verified, err := repo.Verify(context.TODO(), token, verifiedAt)
+	assert.NoError(err)
+	assert.True(verified)
+	assert.NoError(mock.ExpectationsWereMet())
+}
+
+func TestVerifyWithUsedToken(t *testing.T) {
+	assert := assert.New(t)
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	verifiedAt := time.Now()
+	token := &models.UserToken{ID: 4, User: &models.User{ID: 1}}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE user_token SET used_at=\\? WHERE id=\\? AND used_at IS NULL").
+		WithArgs(verifiedAt, int64(4)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	repo := repository.New(db)
+
+	verified, err := repo.Verify(context.TODO(), token, verifiedAt)
+	assert.NoError(err)
+	assert.False(verified)
+	assert.NoError(mock.ExpectationsWereMet())
+}
+
+func TestResetPassword(t *testing.T) {
+	assert := assert.New(t)
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	resetAt := time.Now()
+	token := &models.UserToken{ID: 4, User: &models.User{ID: 1}}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE user_token SET used_at=\\? WHERE id=\\? AND used_at IS NULL").
+		WithArgs(resetAt, int64(4)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE user SET passwd=\\?, is_active=1, updated_at=\\? WHERE id=\\?").
+		WithArgs("newHash", resetAt, int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE refresh_token SET revoked_at=\\? WHERE user_id=\\? AND revoked_at IS NULL").
+		WithArgs(resetAt, int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	repo := repository.New(db)
+
+	reset, err := repo.ResetPassword(context.TODO(), token, "newHash", resetAt)
+	assert.NoError(err)
+	assert.True(reset)
+	assert.NoError(mock.ExpectationsWereMet())
+}
+
+func TestUpdate(t *testing.T) {
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	now := time.Now()
+
+	mock.ExpectExec("UPDATE user SET name=\\?, updated_at=\\? WHERE id=\\? AND deleted_at IS NULL").
+		WithArgs("new name", now, int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	repo := repository.New(db)
+
+	assert.NoError(t, repo.Update(context.TODO(), &models.User{ID: 1, Name: "new name", UpdatedAt: now}))
+}
+
+func TestUpdatePassword(t *testing.T) {
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	now := time.Now()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE user SET passwd=\\?, updated_at=\\? WHERE id=\\?").
+		WithArgs("newHash", now, int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE refresh_token SET revoked_at=\\? WHERE user_id=\\? AND family_id<>\\? AND revoked_at IS NULL").
+		WithArgs(now, int64(1), "session").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	repo := repository.New(db)
+
+	assert.NoError(t, repo.UpdatePassword(context.TODO(), 1, "newHash", "session", now))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateEmail(t *testing.T) {
+	assert := assert.New(t)
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	now := time.Now()
+	token := &models.UserToken{ID: 4, User: &models.User{ID: 1}, Email: "new@email.com"}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE user_token SET used_at=\\? WHERE id=\\? AND used_at IS NULL").
+		WithArgs(now, int64(4)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE user SET email=\\?, is_active=1, updated_at=\\? WHERE id=\\?").
+		WithArgs("new@email.com", now, int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	repo := repository.New(db)
+
+	updated, err := repo.UpdateEmail(context.TODO(), token, now)
+	assert.NoError(err)
+	assert.True(updated)
+	assert.NoError(mock.ExpectationsWereMet())
+}
+
+func TestUpdateEmailWithTakenEmail(t *testing.T) {
+	assert := assert.New(t)
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	now := time.Now()
+	token := &models.UserToken{ID: 4, User: &models.User{ID: 1}, Email: "new@email.com"}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE user_token SET used_at=\\? WHERE id=\\? AND used_at IS NULL").
+		WithArgs(now, int64(4)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE user SET email=\\?, is_active=1, updated_at=\\? WHERE id=\\?").
+		WithArgs("new@email.com", now, int64(1)).
+		WillReturnError(&mysql.MySQLError{Number: 1062, Message: "Duplicate entry 'new@email.com' for key 'email'"})
+	mock.ExpectRollback()
+
+	repo := repository.New(db)
+
+	updated, err := repo.UpdateEmail(context.TODO(), token, now)
+	assert.Equal(&todoErr.DataConflictError{Resource: "user", Field: "email"}, err)
+	assert.False(updated)
+	assert.NoError(mock.ExpectationsWereMet())
+}
+
+func TestDeactivate(t *testing.T) {
+	assert := assert.New(t)
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	now := time.Now()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE user SET deactivated_at=\\?, updated_at=\\? WHERE id=\\? AND deactivated_at IS NULL AND deleted_at IS NULL").
+		WithArgs(now, now, int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE refresh_token SET revoked_at=\\? WHERE user_id=\\? AND revoked_at IS NULL").
+		WithArgs(now, int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	repo := repository.New(db)
+
+	deactivated, err := repo.Deactivate(context.TODO(), 1, now)
+	assert.NoError(err)
+	assert.True(deactivated)
+	assert.NoError(mock.ExpectationsWereMet())
+}
+
+func TestDeactivateWithDeactivatedUser(t *testing.T) {
+	assert := assert.New(t)
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error while opening stub DB connection: %s", err)
+	}
+
+	defer db.Close()
+
+	now := time.Now()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE user SET deactivated_at=\\?").
+		WithArgs(now, now, int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	repo := repository.New(db)
+
+	deactivated, err := repo.Deactivate(context.TODO(), 1, now)
+	assert.NoError(err)
+	assert.False(deactivated)
+	assert.NoError(mock.ExpectationsWereMet())
+}
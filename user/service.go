@@ -6,8 +6,20 @@ import (
 	"github.com/dheerajgopi/todo-api/models"
 )
 
-// Service represents user's service contract
+// Service represents user's service contract.
+// Verification, password reset and email change tokens are mailed to the users, and each of them works once.
 type Service interface {
 	Create(ctx context.Context, newUser *models.User) error
-	GenerateAuthToken(ctx context.Context, email string, pswd string, secret string) (string, error)
+	Authenticate(ctx context.Context, email string, pswd string) (*models.User, error)
+	Delete(ctx context.Context, userID int64) error
+	RequestVerification(ctx context.Context, email string) error
+	Verify(ctx context.Context, token string) error
+	RequestPasswordReset(ctx context.Context, email string) error
+	ResetPassword(ctx context.Context, token string, pswd string) error
+	Get(ctx context.Context, userID int64) (*models.User, error)
+	Update(ctx context.Context, user *models.User) error
+	ChangePassword(ctx context.Context, userID int64, sessionID string, currentPswd string, newPswd string) error
+	RequestEmailChange(ctx context.Context, userID int64, pswd string, newEmail string) error
+	ChangeEmail(ctx context.Context, token string) error
+	Deactivate(ctx context.Context, userID int64) error
 }
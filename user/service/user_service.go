@@ -2,23 +2,42 @@ package service
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
 	"time"
 
-	"github.com/dgrijalva/jwt-go"
 	todoErr "github.com/dheerajgopi/todo-api/common/error"
+	"github.com/dheerajgopi/todo-api/config"
+	"github.com/dheerajgopi/todo-api/mail"
 	"github.com/dheerajgopi/todo-api/models"
 	"github.com/dheerajgopi/todo-api/user"
 	"golang.org/x/crypto/bcrypt"
 )
 
+// tokenSize is the number of random bytes in a verification, password reset or email change token
+const tokenSize = 32
+
+// dummyPasswdHash is compared with the password when the email is unknown,
+// so that unknown emails take as long as wrong passwords and can not be probed by timing
+const dummyPasswdHash = "$2a$10$XT./dP9A7UgDQFo4EP.x0ux4.qHcskSnyL0AN42alJz55b.mq9CIi"
+
 type userService struct {
 	userRepo user.Repository
+	mailer   mail.Mailer
+	setting  *config.AccountSetting
 }
 
-// New returns a new object implementing user.Service interface
-func New(repo user.Repository) user.Service {
+// New returns a new object implementing user.Service interface.
+// Verification and password reset mails are sent with the mailer, as the account settings describe.
+func New(repo user.Repository, mailer mail.Mailer, setting *config.AccountSetting) user.Service {
 	return &userService{
 		userRepo: repo,
+		mailer:   mailer,
+		setting:  setting,
 	}
 }
 
@@ -58,45 +77,368 @@ func (service *userService) Create(ctx context.Context, newUser *models.User) er
 	return nil
 }
 
-// GenerateAuthToken validates the password and generates JWT
-func (service *userService) GenerateAuthToken(ctx context.Context, email string, pswd string, secret string) (string, error) {
+// Authenticate returns the user with the given email, after validating the password. Deactivated users are not found.
+// Users who have not verified their email can not log in, if the account settings deny them any access.
+func (service *userService) Authenticate(ctx context.Context, email string, pswd string) (*models.User, error) {
 	user, err := service.userRepo.GetByEmail(ctx, email)
 
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	if user == nil {
+	if user == nil || user.DeletedAt != nil || user.DeactivatedAt != nil {
+		bcrypt.CompareHashAndPassword([]byte(dummyPasswdHash), []byte(pswd))
+
 		resourceNotFoundError := todoErr.ResourceNotFoundError{
 			Resource: "user",
 		}
 
-		return "", &resourceNotFoundError
+		return nil, &resourceNotFoundError
 	}
 
 	err = bcrypt.CompareHashAndPassword([]byte(user.Passwd), []byte(pswd))
 
 	if err != nil {
-		return "", &todoErr.PasswordMismatchError{}
+		return nil, &todoErr.PasswordMismatchError{}
 	}
 
-	now := time.Now()
+	if !user.IsActive && service.setting.UnverifiedAccess == config.UnverifiedNoAccess {
+		return nil, &todoErr.PermissionDeniedError{
+			Resource: "user",
+			Action:   "login",
+		}
+	}
+
+	return user, nil
+}
+
+// Delete marks the user as deleted, along with the user's tasks. Deleted user is purged after the trash retention period.
+func (service *userService) Delete(ctx context.Context, userID int64) error {
+	existingUser, err := service.userRepo.GetByID(ctx, userID)
+
+	if err != nil {
+		return err
+	}
+
+	if existingUser == nil {
+		return &todoErr.ResourceNotFoundError{
+			Resource: "user",
+		}
+	}
+
+	return service.userRepo.Delete(ctx, userID, time.Now())
+}
+
+// RequestVerification mails a new verification token to an user who has not verified the email yet.
+// Nothing is sent for unknown or verified emails, and no error is returned either, so that emails can not be probed.
+func (service *userService) RequestVerification(ctx context.Context, email string) error {
+	existingUser, err := service.userRepo.GetByEmail(ctx, email)
+
+	if err != nil {
+		return err
+	}
+
+	if existingUser == nil || existingUser.DeletedAt != nil || existingUser.DeactivatedAt != nil || existingUser.IsActive {
+		return nil
+	}
+
+	expiry := time.Duration(service.setting.VerificationExpiryInHours) * time.Hour
+	token, err := service.createToken(ctx, existingUser, user.TokenVerification, "", expiry)
+
+	if err != nil {
+		return err
+	}
+
+	return service.mailer.Send(ctx, &mail.Message{
+		To:      existingUser.Email,
+		Subject: "Verify your email",
+		Body: fmt.Sprintf("Hi %s,\n\nPlease verify your email with the link below. It expires in %d hours.\n\n%s\n\n"+
+			"If you did not sign up, you can ignore this mail.\n",
+			existingUser.Name, service.setting.VerificationExpiryInHours, link(service.setting.VerificationURL, token)),
+	})
+}
+
+// Verify activates the user of a verification token
+func (service *userService) Verify(ctx context.Context, token string) error {
+	existingToken, err := service.getToken(ctx, user.TokenVerification, token)
+
+	if err != nil {
+		return err
+	}
+
+	verified, err := service.userRepo.Verify(ctx, existingToken, time.Now())
+
+	if err != nil {
+		return err
+	}
+
+	if !verified {
+		return invalidTokenError()
+	}
+
+	return nil
+}
+
+// RequestPasswordReset mails a new password reset token to an user.
+// Nothing is sent for unknown emails, and no error is returned either, so that emails can not be probed.
+func (service *userService) RequestPasswordReset(ctx context.Context, email string) error {
+	existingUser, err := service.userRepo.GetByEmail(ctx, email)
+
+	if err != nil {
+		return err
+	}
+
+	if existingUser == nil || existingUser.DeletedAt != nil || existingUser.DeactivatedAt != nil {
+		return nil
+	}
+
+	expiry := time.Duration(service.setting.PasswordResetExpiryInMinutes) * time.Minute
+	token, err := service.createToken(ctx, existingUser, user.TokenPasswordReset, "", expiry)
+
+	if err != nil {
+		return err
+	}
+
+	return service.mailer.Send(ctx, &mail.Message{
+		To:      existingUser.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf("Hi %s,\n\nYou can choose a new password with the link below. It expires in %d minutes.\n\n%s\n\n"+
+			"If you did not ask for a password reset, you can ignore this mail.\n",
+			existingUser.Name, service.setting.PasswordResetExpiryInMinutes, link(service.setting.PasswordResetURL, token)),
+	})
+}
+
+// ResetPassword replaces the password of the user of a password reset token, and logs the user out everywhere
+func (service *userService) ResetPassword(ctx context.Context, token string, pswd string) error {
+	existingToken, err := service.getToken(ctx, user.TokenPasswordReset, token)
+
+	if err != nil {
+		return err
+	}
+
+	pswdHash, err := bcrypt.GenerateFromPassword([]byte(pswd), bcrypt.DefaultCost)
+
+	if err != nil {
+		return err
+	}
+
+	reset, err := service.userRepo.ResetPassword(ctx, existingToken, string(pswdHash), time.Now())
+
+	if err != nil {
+		return err
+	}
+
+	if !reset {
+		return invalidTokenError()
+	}
+
+	return nil
+}
+
+// Get returns the user with the given id, unless the user is deleted or deactivated
+func (service *userService) Get(ctx context.Context, userID int64) (*models.User, error) {
+	existingUser, err := service.userRepo.GetByID(ctx, userID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if existingUser == nil || existingUser.DeactivatedAt != nil {
+		return nil, &todoErr.ResourceNotFoundError{
+			Resource: "user",
+		}
+	}
+
+	return existingUser, nil
+}
+
+// Update changes the name of an user. Email and password have their own flows, since they need to be confirmed.
+func (service *userService) Update(ctx context.Context, existingUser *models.User) error {
+	existingUser.UpdatedAt = time.Now()
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"iss":    "todo-api",
-		"sub":    "user",
-		"userId": user.ID,
-		"name":   user.Name,
-		"email":  user.Email,
-		"iat":    now,
-		"exp":    now.Add(1 * time.Hour),
+	return service.userRepo.Update(ctx, existingUser)
+}
+
+// ChangePassword replaces the password of an user after validating the current one.
+// Other sessions of the user are logged out, while the session which changed the password is kept.
+func (service *userService) ChangePassword(ctx context.Context, userID int64, sessionID string, currentPswd string, newPswd string) error {
+	existingUser, err := service.Get(ctx, userID)
+
+	if err != nil {
+		return err
+	}
+
+	if err = bcrypt.CompareHashAndPassword([]byte(existingUser.Passwd), []byte(currentPswd)); err != nil {
+		return &todoErr.PasswordMismatchError{}
+	}
+
+	pswdHash, err := bcrypt.GenerateFromPassword([]byte(newPswd), bcrypt.DefaultCost)
+
+	if err != nil {
+		return err
+	}
+
+	return service.userRepo.UpdatePassword(ctx, userID, string(pswdHash), sessionID, time.Now())
+}
+
+// RequestEmailChange mails an email change token to the new email, after validating the password of the user.
+// Email is changed only once the token is confirmed, so that nobody can take an email they do not own.
+func (service *userService) RequestEmailChange(ctx context.Context, userID int64, pswd string, newEmail string) error {
+	existingUser, err := service.Get(ctx, userID)
+
+	if err != nil {
+		return err
+	}
+
+	if err = bcrypt.CompareHashAndPassword([]byte(existingUser.Passwd), []byte(pswd)); err != nil {
+		return &todoErr.PasswordMismatchError{}
+	}
+
+	if err = service.checkEmailAvailable(ctx, newEmail); err != nil {
+		return err
+	}
+
+	expiry := time.Duration(service.setting.EmailChangeExpiryInHours) * time.Hour
+	token, err := service.createToken(ctx, existingUser, user.TokenEmailChange, newEmail, expiry)
+
+	if err != nil {
+		return err
+	}
+
+	return service.mailer.Send(ctx, &mail.Message{
+		To:      newEmail,
+		Subject: "Confirm your new email",
+		Body: fmt.Sprintf("Hi %s,\n\nPlease confirm your new email with the link below. It expires in %d hours.\n\n%s\n\n"+
+			"If you did not ask for an email change, you can ignore this mail.\n",
+			existingUser.Name, service.setting.EmailChangeExpiryInHours, link(service.setting.EmailChangeURL, token)),
 	})
+}
+
+// ChangeEmail replaces the email of the user of an email change token with the new email of the token.
+// New email counts as verified, since the token was mailed to it.
+func (service *userService) ChangeEmail(ctx context.Context, token string) error {
+	existingToken, err := service.getToken(ctx, user.TokenEmailChange, token)
+
+	if err != nil {
+		return err
+	}
+
+	// the email may have been taken since the token was mailed
+	if err = service.checkEmailAvailable(ctx, existingToken.Email); err != nil {
+		return err
+	}
+
+	changed, err := service.userRepo.UpdateEmail(ctx, existingToken, time.Now())
+
+	if err != nil {
+		return err
+	}
+
+	if !changed {
+		return invalidTokenError()
+	}
+
+	return nil
+}
 
-	signedToken, err := token.SignedString([]byte(secret))
+// Deactivate marks the user as deactivated, and logs the user out everywhere.
+// Unlike deleted users, deactivated users keep their data.
+func (service *userService) Deactivate(ctx context.Context, userID int64) error {
+	deactivated, err := service.userRepo.Deactivate(ctx, userID, time.Now())
 
 	if err != nil {
+		return err
+	}
+
+	if !deactivated {
+		return &todoErr.ResourceNotFoundError{
+			Resource: "user",
+		}
+	}
+
+	return nil
+}
+
+// checkEmailAvailable returns a conflict error if the email belongs to an user, including deleted and deactivated ones
+func (service *userService) checkEmailAvailable(ctx context.Context, email string) error {
+	existingUser, err := service.userRepo.GetByEmail(ctx, email)
+
+	if err != nil {
+		return err
+	}
+
+	if existingUser != nil {
+		return &todoErr.DataConflictError{
+			Resource: "user",
+			Field:    "email",
+		}
+	}
+
+	return nil
+}
+
+// createToken generates a new token for an user, and stores its hash. Email is the new email of an email change token.
+func (service *userService) createToken(ctx context.Context, existingUser *models.User, purpose string, email string, expiry time.Duration) (string, error) {
+	tokenBytes := make([]byte, tokenSize)
+
+	if _, err := rand.Read(tokenBytes); err != nil {
 		return "", err
 	}
 
-	return signedToken, nil
+	token := base64.RawURLEncoding.EncodeToString(tokenBytes)
+	now := time.Now()
+
+	newToken := &models.UserToken{
+		User:      existingUser,
+		Purpose:   purpose,
+		Email:     email,
+		TokenHash: hashToken(token),
+		ExpiresAt: now.Add(expiry),
+		CreatedAt: now,
+	}
+
+	if err := service.userRepo.CreateToken(ctx, newToken); err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+// getToken returns the stored token for the given purpose, unless it is unknown, expired or used
+func (service *userService) getToken(ctx context.Context, purpose string, token string) (*models.UserToken, error) {
+	existingToken, err := service.userRepo.GetToken(ctx, purpose, hashToken(token))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if existingToken == nil || existingToken.UsedAt != nil || !existingToken.ExpiresAt.After(time.Now()) {
+		return nil, invalidTokenError()
+	}
+
+	return existingToken, nil
+}
+
+// invalidTokenError is returned for unknown, expired or used tokens alike
+func invalidTokenError() error {
+	return &todoErr.InvalidValueError{
+		Resource: "user",
+		Field:    "token",
+		Reason:   "Invalid or expired token",
+	}
+}
+
+// link builds the link to a token by replacing {token} in the url. Token is given as it is if there is no url.
+func link(url string, token string) string {
+	if url == "" {
+		return token
+	}
+
+	return strings.Replace(url, "{token}", token, -1)
+}
+
+// hashToken returns the hex encoded SHA-256 hash of a token
+func hashToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
 }
@@ -2,22 +2,44 @@ package service_test
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
 	"testing"
 	"time"
 
-	"github.com/dgrijalva/jwt-go"
-
 	"github.com/stretchr/testify/assert"
 	"golang.org/x/crypto/bcrypt"
 
 	"github.com/golang/mock/gomock"
 
 	todoErr "github.com/dheerajgopi/todo-api/common/error"
+	"github.com/dheerajgopi/todo-api/config"
+	"github.com/dheerajgopi/todo-api/mail/memory"
 	"github.com/dheerajgopi/todo-api/models"
+	"github.com/dheerajgopi/todo-api/user"
 	repoMock "github.com/dheerajgopi/todo-api/user/mock"
 	"github.com/dheerajgopi/todo-api/user/service"
 )
 
+func newAccountSetting() *config.AccountSetting {
+	return &config.AccountSetting{
+		UnverifiedAccess:             config.UnverifiedReadOnly,
+		VerificationExpiryInHours:    48,
+		PasswordResetExpiryInMinutes: 60,
+		VerificationURL:              "https://todo.app/verify?token={token}",
+		PasswordResetURL:             "https://todo.app/reset?token={token}",
+		EmailChangeExpiryInHours:     24,
+		EmailChangeURL:               "https://todo.app/confirm-email?token={token}",
+	}
+}
+
+func hash(value string) string {
+	sum := sha256.Sum256([]byte(value))
+
+	return hex.EncodeToString(sum[:])
+}
+
 func TestCreate(t *testing.T) {
 	now := time.Now()
 	ctx := context.TODO()
@@ -26,7 +48,7 @@ func TestCreate(t *testing.T) {
 	defer mockCtrl.Finish()
 
 	userRepoMock := repoMock.NewRepository(mockCtrl)
-	userService := service.New(userRepoMock)
+	userService := service.New(userRepoMock, memory.New(), newAccountSetting())
 
 	newUser := &models.User{
 		Name:      "testName",
@@ -69,7 +91,7 @@ func TestCreateForAlreadyExistingUser(t *testing.T) {
 	defer mockCtrl.Finish()
 
 	userRepoMock := repoMock.NewRepository(mockCtrl)
-	userService := service.New(userRepoMock)
+	userService := service.New(userRepoMock, memory.New(), newAccountSetting())
 
 	newUser := &models.User{
 		Name:      "testName",
@@ -97,7 +119,7 @@ func TestCreateForAlreadyExistingUser(t *testing.T) {
 	assert.Equal(dataConflictErr, err)
 }
 
-func TestGenerateAuthToken(t *testing.T) {
+func TestAuthenticate(t *testing.T) {
 	now := time.Now()
 	ctx := context.TODO()
 	assert := assert.New(t)
@@ -105,12 +127,11 @@ func TestGenerateAuthToken(t *testing.T) {
 	defer mockCtrl.Finish()
 
 	userRepoMock := repoMock.NewRepository(mockCtrl)
-	userService := service.New(userRepoMock)
+	userService := service.New(userRepoMock, memory.New(), newAccountSetting())
 
 	email := "testName@email.com"
 	passwd := "test"
 	pswdHash, _ := bcrypt.GenerateFromPassword([]byte(passwd), bcrypt.DefaultCost)
-	jwtSecret := "secret"
 
 	newUser := &models.User{
 		Name:      "testName",
@@ -127,29 +148,23 @@ func TestGenerateAuthToken(t *testing.T) {
 		Return(newUser, nil).
 		Times(1)
 
-	token, _ := userService.GenerateAuthToken(ctx, newUser.Email, passwd, jwtSecret)
-
-	parsedToken, _ := jwt.Parse(token, func(token *jwt.Token) (interface{}, error) {
-		return []byte(jwtSecret), nil
-	})
-
-	claims, _ := parsedToken.Claims.(jwt.MapClaims)
+	authenticatedUser, err := userService.Authenticate(ctx, newUser.Email, passwd)
 
-	assert.NoError(claims.Valid())
+	assert.NoError(err)
+	assert.Equal(newUser, authenticatedUser)
 }
 
-func TestGenerateAuthTokenForMissingUser(t *testing.T) {
+func TestAuthenticateForMissingUser(t *testing.T) {
 	ctx := context.TODO()
 	assert := assert.New(t)
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
 
 	userRepoMock := repoMock.NewRepository(mockCtrl)
-	userService := service.New(userRepoMock)
+	userService := service.New(userRepoMock, memory.New(), newAccountSetting())
 
 	email := "testName@email.com"
 	passwd := "test"
-	jwtSecret := "secret"
 
 	userRepoMock.
 		EXPECT().
@@ -157,17 +172,17 @@ func TestGenerateAuthTokenForMissingUser(t *testing.T) {
 		Return(nil, nil).
 		Times(1)
 
-	token, err := userService.GenerateAuthToken(ctx, email, passwd, jwtSecret)
+	authenticatedUser, err := userService.Authenticate(ctx, email, passwd)
 
 	resourceNotFoundError := &todoErr.ResourceNotFoundError{
 		Resource: "user",
 	}
 
-	assert.Empty(token)
+	assert.Nil(authenticatedUser)
 	assert.Equal(resourceNotFoundError, err)
 }
 
-func TestGenerateAuthTokenForPasswordMismatch(t *testing.T) {
+func TestAuthenticateForPasswordMismatch(t *testing.T) {
 	now := time.Now()
 	ctx := context.TODO()
 	assert := assert.New(t)
@@ -175,12 +190,11 @@ func TestGenerateAuthTokenForPasswordMismatch(t *testing.T) {
 	defer mockCtrl.Finish()
 
 	userRepoMock := repoMock.NewRepository(mockCtrl)
-	userService := service.New(userRepoMock)
+	userService := service.New(userRepoMock, memory.New(), newAccountSetting())
 
 	email := "testName@email.com"
 	passwd := "test"
 	pswdHash, _ := bcrypt.GenerateFromPassword([]byte(passwd), bcrypt.DefaultCost)
-	jwtSecret := "secret"
 
 	newUser := &models.User{
 		Name:      "testName",
@@ -197,10 +211,536 @@ func TestGenerateAuthTokenForPasswordMismatch(t *testing.T) {
 		Return(newUser, nil).
 		Times(1)
 
-	token, err := userService.GenerateAuthToken(ctx, newUser.Email, "invalid"+passwd, jwtSecret)
+	authenticatedUser, err := userService.Authenticate(ctx, newUser.Email, "invalid"+passwd)
 
 	expectedErr := &todoErr.PasswordMismatchError{}
 
-	assert.Empty(token)
+	assert.Nil(authenticatedUser)
 	assert.Equal(expectedErr, err)
 }
+
+func TestAuthenticateForDeletedUser(t *testing.T) {
+	now := time.Now()
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	userRepoMock := repoMock.NewRepository(mockCtrl)
+	userService := service.New(userRepoMock, memory.New(), newAccountSetting())
+
+	email := "testName@email.com"
+
+	deletedUser := &models.User{
+		ID:        1,
+		Email:     email,
+		DeletedAt: &now,
+	}
+
+	userRepoMock.
+		EXPECT().
+		GetByEmail(ctx, email).
+		Return(deletedUser, nil).
+		Times(1)
+
+	authenticatedUser, err := userService.Authenticate(ctx, email, "test")
+
+	assert.Nil(authenticatedUser)
+	assert.Equal(&todoErr.ResourceNotFoundError{Resource: "user"}, err)
+}
+
+func TestAuthenticateForDeactivatedUser(t *testing.T) {
+	now := time.Now()
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	userRepoMock := repoMock.NewRepository(mockCtrl)
+	userService := service.New(userRepoMock, memory.New(), newAccountSetting())
+
+	email := "testName@email.com"
+	pswdHash, _ := bcrypt.GenerateFromPassword([]byte("test"), bcrypt.MinCost)
+
+	deactivatedUser := &models.User{
+		ID:            1,
+		Email:         email,
+		Passwd:        string(pswdHash),
+		IsActive:      true,
+		DeactivatedAt: &now,
+	}
+
+	userRepoMock.
+		EXPECT().
+		GetByEmail(ctx, email).
+		Return(deactivatedUser, nil).
+		Times(1)
+
+	authenticatedUser, err := userService.Authenticate(ctx, email, "test")
+
+	assert.Nil(authenticatedUser)
+	assert.Equal(&todoErr.ResourceNotFoundError{Resource: "user"}, err)
+}
+
+func TestDelete(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	userRepoMock := repoMock.NewRepository(mockCtrl)
+	userService := service.New(userRepoMock, memory.New(), newAccountSetting())
+
+	gomock.InOrder(
+		userRepoMock.EXPECT().GetByID(ctx, int64(1)).Return(&models.User{ID: 1}, nil),
+		userRepoMock.EXPECT().Delete(ctx, int64(1), gomock.Any()).Return(nil),
+	)
+
+	assert.NoError(userService.Delete(ctx, 1))
+}
+
+func TestDeleteForMissingUser(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	userRepoMock := repoMock.NewRepository(mockCtrl)
+	userService := service.New(userRepoMock, memory.New(), newAccountSetting())
+
+	userRepoMock.EXPECT().GetByID(ctx, int64(1)).Return(nil, nil)
+
+	err := userService.Delete(ctx, 1)
+
+	assert.Equal(&todoErr.ResourceNotFoundError{Resource: "user"}, err)
+}
+
+func TestAuthenticateForUnverifiedUser(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	userRepoMock := repoMock.NewRepository(mockCtrl)
+	setting := newAccountSetting()
+	userService := service.New(userRepoMock, memory.New(), setting)
+
+	pswdHash, _ := bcrypt.GenerateFromPassword([]byte("test"), bcrypt.MinCost)
+
+	unverifiedUser := &models.User{
+		ID:     1,
+		Email:  "testName@email.com",
+		Passwd: string(pswdHash),
+	}
+
+	userRepoMock.
+		EXPECT().
+		GetByEmail(ctx, unverifiedUser.Email).
+		Return(unverifiedUser, nil).
+		Times(2)
+
+	authenticatedUser, err := userService.Authenticate(ctx, unverifiedUser.Email, "test")
+
+	assert.NoError(err)
+	assert.Equal(unverifiedUser, authenticatedUser)
+
+	setting.UnverifiedAccess = config.UnverifiedNoAccess
+
+	authenticatedUser, err = userService.Authenticate(ctx, unverifiedUser.Email, "test")
+
+	assert.Nil(authenticatedUser)
+	assert.Equal(&todoErr.PermissionDeniedError{Resource: "user", Action: "login"}, err)
+}
+
+func TestRequestVerification(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	userRepoMock := repoMock.NewRepository(mockCtrl)
+	outbox := memory.New()
+	userService := service.New(userRepoMock, outbox, newAccountSetting())
+
+	unverifiedUser := &models.User{
+		ID:    1,
+		Name:  "testName",
+		Email: "testName@email.com",
+	}
+
+	var storedToken *models.UserToken
+
+	gomock.InOrder(
+		userRepoMock.EXPECT().GetByEmail(ctx, unverifiedUser.Email).Return(unverifiedUser, nil),
+		userRepoMock.
+			EXPECT().
+			CreateToken(ctx, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, token *models.UserToken) error {
+				storedToken = token
+				return nil
+			}),
+	)
+
+	err := userService.RequestVerification(ctx, unverifiedUser.Email)
+
+	assert.NoError(err)
+	assert.Equal(user.TokenVerification, storedToken.Purpose)
+	assert.Equal(int64(1), storedToken.User.ID)
+	assert.WithinDuration(time.Now().Add(48*time.Hour), storedToken.ExpiresAt, time.Minute)
+
+	messages := outbox.Messages()
+
+	assert.Equal(1, len(messages))
+	assert.Equal(unverifiedUser.Email, messages[0].To)
+	assert.Equal("Verify your email", messages[0].Subject)
+
+	linkStart := strings.Index(messages[0].Body, "https://todo.app/verify?token=")
+	token := strings.Fields(messages[0].Body[linkStart+len("https://todo.app/verify?token="):])[0]
+
+	assert.Equal(hash(token), storedToken.TokenHash)
+}
+
+func TestRequestVerificationForVerifiedOrUnknownUser(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	userRepoMock := repoMock.NewRepository(mockCtrl)
+	outbox := memory.New()
+	userService := service.New(userRepoMock, outbox, newAccountSetting())
+
+	verifiedUser := &models.User{
+		ID:       1,
+		Email:    "testName@email.com",
+		IsActive: true,
+	}
+
+	userRepoMock.EXPECT().GetByEmail(ctx, verifiedUser.Email).Return(verifiedUser, nil)
+	userRepoMock.EXPECT().GetByEmail(ctx, "unknown@email.com").Return(nil, nil)
+
+	assert.NoError(userService.RequestVerification(ctx, verifiedUser.Email))
+	assert.NoError(userService.RequestVerification(ctx, "unknown@email.com"))
+	assert.Equal(0, len(outbox.Messages()))
+}
+
+func TestVerify(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	userRepoMock := repoMock.NewRepository(mockCtrl)
+	userService := service.New(userRepoMock, memory.New(), newAccountSetting())
+
+	existingToken := &models.UserToken{
+		ID:        5,
+		User:      &models.User{ID: 1},
+		Purpose:   user.TokenVerification,
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+
+	gomock.InOrder(
+		userRepoMock.EXPECT().GetToken(ctx, user.TokenVerification, hash("token")).Return(existingToken, nil),
+		userRepoMock.EXPECT().Verify(ctx, existingToken, gomock.Any()).Return(true, nil),
+	)
+
+	assert.NoError(userService.Verify(ctx, "token"))
+}
+
+func TestVerifyWithInvalidToken(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	userRepoMock := repoMock.NewRepository(mockCtrl)
+	userService := service.New(userRepoMock, memory.New(), newAccountSetting())
+
+	usedAt := time.Now().Add(-time.Minute)
+	expiredToken := &models.UserToken{
+		ID:        5,
+		User:      &models.User{ID: 1},
+		ExpiresAt: time.Now().Add(-time.Second),
+	}
+	usedToken := &models.UserToken{
+		ID:        6,
+		User:      &models.User{ID: 1},
+		ExpiresAt: time.Now().Add(time.Hour),
+		UsedAt:    &usedAt,
+	}
+	concurrentlyUsedToken := &models.UserToken{
+		ID:        7,
+		User:      &models.User{ID: 1},
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+
+	userRepoMock.EXPECT().GetToken(ctx, user.TokenVerification, hash("unknown")).Return(nil, nil)
+	userRepoMock.EXPECT().GetToken(ctx, user.TokenVerification, hash("expired")).Return(expiredToken, nil)
+	userRepoMock.EXPECT().GetToken(ctx, user.TokenVerification, hash("used")).Return(usedToken, nil)
+	userRepoMock.EXPECT().GetToken(ctx, user.TokenVerification, hash("concurrent")).Return(concurrentlyUsedToken, nil)
+	userRepoMock.EXPECT().Verify(ctx, concurrentlyUsedToken, gomock.Any()).Return(false, nil)
+
+	invalidTokenErr := &todoErr.InvalidValueError{
+		Resource: "user",
+		Field:    "token",
+		Reason:   "Invalid or expired token",
+	}
+
+	for _, token := range []string{"unknown", "expired", "used", "concurrent"} {
+		assert.Equal(invalidTokenErr, userService.Verify(ctx, token), token)
+	}
+}
+
+func TestRequestPasswordReset(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	userRepoMock := repoMock.NewRepository(mockCtrl)
+	outbox := memory.New()
+	userService := service.New(userRepoMock, outbox, newAccountSetting())
+
+	existingUser := &models.User{
+		ID:       1,
+		Name:     "testName",
+		Email:    "testName@email.com",
+		IsActive: true,
+	}
+
+	var storedToken *models.UserToken
+
+	userRepoMock.EXPECT().GetByEmail(ctx, "unknown@email.com").Return(nil, nil)
+	userRepoMock.EXPECT().GetByEmail(ctx, existingUser.Email).Return(existingUser, nil)
+	userRepoMock.
+		EXPECT().
+		CreateToken(ctx, gomock.Any()).
+		DoAndReturn(func(ctx context.Context, token *models.UserToken) error {
+			storedToken = token
+			return nil
+		})
+
+	assert.NoError(userService.RequestPasswordReset(ctx, "unknown@email.com"))
+	assert.Equal(0, len(outbox.Messages()))
+
+	assert.NoError(userService.RequestPasswordReset(ctx, existingUser.Email))
+	assert.Equal(user.TokenPasswordReset, storedToken.Purpose)
+	assert.WithinDuration(time.Now().Add(time.Hour), storedToken.ExpiresAt, time.Minute)
+
+	messages := outbox.Messages()
+
+	assert.Equal(1, len(messages))
+	assert.Equal("Reset your password", messages[0].Subject)
+	assert.Contains(messages[0].Body, "It expires in 60 minutes.")
+	assert.Contains(messages[0].Body, "https://todo.app/reset?token=")
+}
+
+func TestResetPassword(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	userRepoMock := repoMock.NewRepository(mockCtrl)
+	userService := service.New(userRepoMock, memory.New(), newAccountSetting())
+
+	existingToken := &models.UserToken{
+		ID:        5,
+		User:      &models.User{ID: 1},
+		Purpose:   user.TokenPasswordReset,
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+
+	gomock.InOrder(
+		userRepoMock.EXPECT().GetToken(ctx, user.TokenPasswordReset, hash("token")).Return(existingToken, nil),
+		userRepoMock.
+			EXPECT().
+			ResetPassword(ctx, existingToken, gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, token *models.UserToken, passwd string, resetAt time.Time) (bool, error) {
+				assert.NoError(bcrypt.CompareHashAndPassword([]byte(passwd), []byte("newSecret")))
+				return true, nil
+			}),
+	)
+
+	assert.NoError(userService.ResetPassword(ctx, "token", "newSecret"))
+}
+
+func TestGet(t *testing.T) {
+	now := time.Now()
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	userRepoMock := repoMock.NewRepository(mockCtrl)
+	userService := service.New(userRepoMock, memory.New(), newAccountSetting())
+
+	existingUser := &models.User{ID: 1, Name: "testName"}
+
+	userRepoMock.EXPECT().GetByID(ctx, int64(1)).Return(existingUser, nil)
+	userRepoMock.EXPECT().GetByID(ctx, int64(2)).Return(nil, nil)
+	userRepoMock.EXPECT().GetByID(ctx, int64(3)).Return(&models.User{ID: 3, DeactivatedAt: &now}, nil)
+
+	foundUser, err := userService.Get(ctx, 1)
+	assert.NoError(err)
+	assert.Equal(existingUser, foundUser)
+
+	for _, id := range []int64{2, 3} {
+		foundUser, err = userService.Get(ctx, id)
+		assert.Nil(foundUser)
+		assert.Equal(&todoErr.ResourceNotFoundError{Resource: "user"}, err)
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	ctx := context.TODO()
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	userRepoMock := repoMock.NewRepository(mockCtrl)
+	userService := service.New(userRepoMock, memory.New(), newAccountSetting())
+
+	existingUser := &models.User{ID: 1, Name: "newName"}
+
+	userRepoMock.EXPECT().Update(ctx, existingUser).Return(nil)
+
+	assert.NoError(t, userService.Update(ctx, existingUser))
+	assert.WithinDuration(t, time.Now(), existingUser.UpdatedAt, time.Minute)
+}
+
+func TestChangePassword(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	userRepoMock := repoMock.NewRepository(mockCtrl)
+	userService := service.New(userRepoMock, memory.New(), newAccountSetting())
+
+	pswdHash, _ := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	existingUser := &models.User{ID: 1, Passwd: string(pswdHash)}
+
+	userRepoMock.EXPECT().GetByID(ctx, int64(1)).Return(existingUser, nil).Times(2)
+	userRepoMock.
+		EXPECT().
+		UpdatePassword(ctx, int64(1), gomock.Any(), "session", gomock.Any()).
+		DoAndReturn(func(ctx context.Context, id int64, passwd string, sessionID string, updatedAt time.Time) error {
+			assert.NoError(bcrypt.CompareHashAndPassword([]byte(passwd), []byte("newSecret")))
+			return nil
+		})
+
+	assert.Equal(&todoErr.PasswordMismatchError{}, userService.ChangePassword(ctx, 1, "session", "wrong", "newSecret"))
+	assert.NoError(userService.ChangePassword(ctx, 1, "session", "secret", "newSecret"))
+}
+
+func TestRequestEmailChange(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	userRepoMock := repoMock.NewRepository(mockCtrl)
+	outbox := memory.New()
+	userService := service.New(userRepoMock, outbox, newAccountSetting())
+
+	pswdHash, _ := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	existingUser := &models.User{ID: 1, Name: "testName", Email: "old@email.com", Passwd: string(pswdHash)}
+
+	var storedToken *models.UserToken
+
+	userRepoMock.EXPECT().GetByID(ctx, int64(1)).Return(existingUser, nil).Times(3)
+	userRepoMock.EXPECT().GetByEmail(ctx, "taken@email.com").Return(&models.User{ID: 2}, nil)
+	userRepoMock.EXPECT().GetByEmail(ctx, "new@email.com").Return(nil, nil)
+	userRepoMock.
+		EXPECT().
+		CreateToken(ctx, gomock.Any()).
+		DoAndReturn(func(ctx context.Context, token *models.UserToken) error {
+			storedToken = token
+			return nil
+		})
+
+	assert.Equal(&todoErr.PasswordMismatchError{}, userService.RequestEmailChange(ctx, 1, "wrong", "new@email.com"))
+	assert.Equal(
+		&todoErr.DataConflictError{Resource: "user", Field: "email"},
+		userService.RequestEmailChange(ctx, 1, "secret", "taken@email.com"),
+	)
+	assert.Equal(0, len(outbox.Messages()))
+
+	assert.NoError(userService.RequestEmailChange(ctx, 1, "secret", "new@email.com"))
+	assert.Equal(user.TokenEmailChange, storedToken.Purpose)
+	assert.Equal("new@email.com", storedToken.Email)
+	assert.WithinDuration(time.Now().Add(24*time.Hour), storedToken.ExpiresAt, time.Minute)
+
+	messages := outbox.Messages()
+
+	assert.Equal(1, len(messages))
+	assert.Equal("new@email.com", messages[0].To)
+	assert.Equal("Confirm your new email", messages[0].Subject)
+	assert.Contains(messages[0].Body, "https://todo.app/confirm-email?token=")
+}
+
+func TestChangeEmail(t *testing.T) {
+	ctx := context.TODO()
+	assert := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	userRepoMock := repoMock.NewRepository(mockCtrl)
+	userService := service.New(userRepoMock, memory.New(), newAccountSetting())
+
+	existingToken := &models.UserToken{
+		ID:        5,
+		User:      &models.User{ID: 1},
+		Purpose:   user.TokenEmailChange,
+		Email:     "new@email.com",
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+
+	gomock.InOrder(
+		userRepoMock.EXPECT().GetToken(ctx, user.TokenEmailChange, hash("token")).Return(existingToken, nil),
+		userRepoMock.EXPECT().GetByEmail(ctx, "new@email.com").Return(nil, nil),
+		userRepoMock.EXPECT().UpdateEmail(ctx, existingToken, gomock.Any()).Return(true, nil),
+	)
+
+	assert.NoError(userService.ChangeEmail(ctx, "token"))
+}
+
+func TestChangeEmailWithTakenEmail(t *testing.T) {
+	ctx := context.TODO()
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	userRepoMock := repoMock.NewRepository(mockCtrl)
+	userService := service.New(userRepoMock, memory.New(), newAccountSetting())
+
+	existingToken := &models.UserToken{
+		ID:        5,
+		User:      &models.User{ID: 1},
+		Purpose:   user.TokenEmailChange,
+		Email:     "new@email.com",
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+
+	userRepoMock.EXPECT().GetToken(ctx, user.TokenEmailChange, hash("token")).Return(existingToken, nil)
+	userRepoMock.EXPECT().GetByEmail(ctx, "new@email.com").Return(&models.User{ID: 2}, nil)
+
+	err := userService.ChangeEmail(ctx, "token")
+
+	assert.Equal(t, &todoErr.DataConflictError{Resource: "user", Field: "email"}, err)
+}
+
+func TestDeactivate(t *testing.T) {
+	ctx := context.TODO()
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	userRepoMock := repoMock.NewRepository(mockCtrl)
+	userService := service.New(userRepoMock, memory.New(), newAccountSetting())
+
+	userRepoMock.EXPECT().Deactivate(ctx, int64(1), gomock.Any()).Return(true, nil)
+	userRepoMock.EXPECT().Deactivate(ctx, int64(2), gomock.Any()).Return(false, nil)
+
+	assert.NoError(t, userService.Deactivate(ctx, 1))
+	assert.Equal(t, &todoErr.ResourceNotFoundError{Resource: "user"}, userService.Deactivate(ctx, 2))
+}
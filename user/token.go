@@ -0,0 +1,8 @@
+package user
+
+// Purposes of the single use tokens which are mailed to users
+const (
+	TokenVerification  = "verification"
+	TokenPasswordReset = "password_reset"
+	TokenEmailChange   = "email_change"
+)